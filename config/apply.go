@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultApplyTimeout is how long a component has to confirm a config apply
+const DefaultApplyTimeout = 10 * time.Second
+
+// Component states in an ApplyStatus
+const (
+	ComponentApplied        = "applied"         // Confirmed the new config
+	ComponentRolledBack     = "rolled_back"     // Failed the new config, the previous one was re-applied
+	ComponentFailed         = "failed"          // Failed the new config, no previous config to re-apply
+	ComponentRollbackFailed = "rollback_failed" // Failed both the new and the previous config
+)
+
+// Component is a subsystem that applies each reloaded configuration and confirms it
+// Apply returns once the config is in effect (e.g., listeners rebound, pools resized)
+// or with the reason it isn't; not returning within the apply timeout is a failure
+type Component struct {
+	// Name identifies the component in the apply status
+	Name string
+
+	// Apply applies config, its ctx expires with the apply timeout
+	Apply func(ctx context.Context, config map[string]interface{}) error
+}
+
+// ComponentStatus is the outcome of one component's apply
+type ComponentStatus struct {
+	Name     string        `json:"name"`
+	State    string        `json:"state"`
+	Error    string        `json:"error,omitempty"`    // Why the new config failed
+	Rollback string        `json:"rollback,omitempty"` // Why re-applying the previous config failed
+	Duration time.Duration `json:"duration"`
+}
+
+// ApplyStatus is the consolidated outcome of applying a reload to the components
+type ApplyStatus struct {
+	StartedAt  time.Time         `json:"started_at"`
+	Duration   time.Duration     `json:"duration"`
+	Components []ComponentStatus `json:"components"` // In registration order
+}
+
+// OK reports whether every component applied the new config
+func (s ApplyStatus) OK() bool {
+	return len(s.Failed()) == 0
+}
+
+// Failed returns the names of the components that didn't apply the new config
+func (s ApplyStatus) Failed() []string {
+	var failed []string
+	for _, c := range s.Components {
+		if c.State != ComponentApplied {
+			failed = append(failed, c.Name)
+		}
+	}
+	return failed
+}
+
+// ApplyError reports the components that failed to apply a reload
+type ApplyError struct {
+	Status ApplyStatus
+}
+
+func (e *ApplyError) Error() string {
+	var msgs []string
+	for _, c := range e.Status.Components {
+		if c.State == ComponentApplied {
+			continue
+		}
+		msg := fmt.Sprintf("%s: %s (%s)", c.Name, c.Error, c.State)
+		if c.Rollback != "" {
+			msg += ": " + c.Rollback
+		}
+		msgs = append(msgs, msg)
+	}
+	return "config apply failed: " + strings.Join(msgs, "; ")
+}
+
+// AddComponent registers a component to apply each reload
+func (m *Manager) AddComponent(component Component) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, component)
+}
+
+// LastApply returns the status of the last reload applied to the components,
+// false if none was
+func (m *Manager) LastApply() (ApplyStatus, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.lastApply == nil {
+		return ApplyStatus{}, false
+	}
+	return *m.lastApply, true
+}
+
+// applyComponents applies new to all components at once and waits for each to
+// confirm; components that fail get old re-applied
+// Failures are returned as *ApplyError, the status is also reported to OnApply
+func (m *Manager) applyComponents(ctx context.Context, old, new map[string]interface{}) error {
+	m.mu.RLock()
+	components := append([]Component(nil), m.components...)
+	m.mu.RUnlock()
+	if len(components) == 0 {
+		return nil
+	}
+
+	status := ApplyStatus{StartedAt: time.Now(), Components: make([]ComponentStatus, len(components))}
+	var wg sync.WaitGroup
+	for i, component := range components {
+		wg.Add(1)
+		go func(i int, component Component) {
+			defer wg.Done()
+			start := time.Now()
+			result := ComponentStatus{Name: component.Name, State: ComponentApplied}
+			if err := m.applyComponent(ctx, component, new); err != nil {
+				result.Error = err.Error()
+				result.State = ComponentFailed
+				if old != nil {
+					result.State = ComponentRolledBack
+					if err := m.applyComponent(ctx, component, old); err != nil {
+						result.State = ComponentRollbackFailed
+						result.Rollback = err.Error()
+					}
+				}
+			}
+			result.Duration = time.Since(start)
+			status.Components[i] = result
+		}(i, component)
+	}
+	wg.Wait()
+	status.Duration = time.Since(status.StartedAt)
+
+	m.mu.Lock()
+	m.lastApply = &status
+	m.mu.Unlock()
+	if m.onApply != nil {
+		m.onApply(status)
+	}
+
+	if !status.OK() {
+		return &ApplyError{Status: status}
+	}
+	return nil
+}
+
+// applyComponent runs component.Apply, failing if it doesn't confirm in time
+func (m *Manager) applyComponent(ctx context.Context, component Component, config map[string]interface{}) error {
+	timeout := m.applyTimeout
+	if timeout <= 0 {
+		timeout = DefaultApplyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- component.Apply(ctx, config)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("no confirmation within %s: %w", timeout, ctx.Err())
+	}
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManager_ApplyComponents(t *testing.T) {
+	provider := NewMockProvider("test", map[string]interface{}{"version": "v1"})
+
+	var mu sync.Mutex
+	applied := make(map[string][]string)
+	record := func(name string, config map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		applied[name] = append(applied[name], config["version"].(string))
+	}
+
+	var statuses []ApplyStatus
+	manager := NewManager(ManagerConfig{
+		Providers:    []Provider{provider},
+		ApplyTimeout: 50 * time.Millisecond,
+		OnApply:      func(s ApplyStatus) { statuses = append(statuses, s) },
+		Components: []Component{
+			{Name: "diameter", Apply: func(ctx context.Context, config map[string]interface{}) error {
+				record("diameter", config)
+				return nil
+			}},
+			{Name: "db_pool", Apply: func(ctx context.Context, config map[string]interface{}) error {
+				record("db_pool", config)
+				if config["version"] == "v2" {
+					return errors.New("pool size rejected")
+				}
+				return nil
+			}},
+		},
+	})
+	manager.AddComponent(Component{Name: "cache", Apply: func(ctx context.Context, config map[string]interface{}) error {
+		record("cache", config)
+		if config["version"] == "v2" {
+			<-ctx.Done() // Never confirms
+		}
+		return nil
+	}})
+
+	ctx := context.Background()
+	if _, err := manager.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := manager.LastApply(); ok {
+		t.Error("Expected no apply status before a reload")
+	}
+
+	provider.data = map[string]interface{}{"version": "v2"}
+	err := manager.Reload(ctx, nil)
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("Expected *ApplyError, got %v", err)
+	}
+
+	status, ok := manager.LastApply()
+	if !ok || len(statuses) != 1 {
+		t.Fatalf("Expected one apply status, got %v (OnApply calls: %d)", ok, len(statuses))
+	}
+	want := map[string]string{
+		"diameter": ComponentApplied,
+		"db_pool":  ComponentRolledBack,
+		"cache":    ComponentRolledBack,
+	}
+	for i, c := range status.Components {
+		if c.Name != []string{"diameter", "db_pool", "cache"}[i] {
+			t.Errorf("Expected components in registration order, got %s at %d", c.Name, i)
+		}
+		if c.State != want[c.Name] {
+			t.Errorf("Expected %s %s, got %s (%s)", c.Name, want[c.Name], c.State, c.Error)
+		}
+	}
+	if failed := status.Failed(); len(failed) != 2 || status.OK() {
+		t.Errorf("Expected db_pool and cache failed, got %v", failed)
+	}
+
+	// Failed components got the previous config back, the others keep the new one
+	wantApplied := map[string][]string{
+		"diameter": {"v2"},
+		"db_pool":  {"v2", "v1"},
+		"cache":    {"v2", "v1"},
+	}
+	for name, versions := range wantApplied {
+		if got := applied[name]; len(got) != len(versions) || got[len(got)-1] != versions[len(versions)-1] {
+			t.Errorf("Expected %s to apply %v, got %v", name, versions, got)
+		}
+	}
+
+	// Reloading a config every component accepts succeeds
+	provider.data = map[string]interface{}{"version": "v3"}
+	if err := manager.Reload(ctx, nil); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if status, _ := manager.LastApply(); !status.OK() {
+		t.Errorf("Expected all components applied, got %+v", status)
+	}
+}
+
+func TestManager_ApplyComponents_RollbackFailed(t *testing.T) {
+	provider := NewMockProvider("test", map[string]interface{}{"version": "v1"})
+	manager := NewManager(ManagerConfig{Providers: []Provider{provider}})
+	manager.AddComponent(Component{Name: "broken", Apply: func(ctx context.Context, config map[string]interface{}) error {
+		return errors.New("unavailable")
+	}})
+
+	ctx := context.Background()
+	if _, err := manager.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	provider.data = map[string]interface{}{"version": "v2"}
+	if err := manager.Reload(ctx, nil); err == nil {
+		t.Fatal("Expected an apply error")
+	}
+
+	status, _ := manager.LastApply()
+	if c := status.Components[0]; c.State != ComponentRollbackFailed || c.Rollback == "" {
+		t.Errorf("Expected a failed rollback, got %+v", c)
+	}
+}
+
+func TestManager_Reload_RejectedKeepsPrevious(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name      string
+		callback  func(map[string]interface{}) error
+		component error
+		watch     bool // Reload through a watch event rather than Reload
+	}{
+		{name: "callback", callback: func(map[string]interface{}) error { return errRejected }},
+		{name: "component", component: errRejected},
+		{name: "watch callback", callback: func(map[string]interface{}) error { return errRejected }, watch: true},
+		{name: "watch component", component: errRejected, watch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewMockProvider("test", map[string]interface{}{"version": "v1"})
+			watcher := &mockWatcher{}
+			manager := NewManager(ManagerConfig{Providers: []Provider{provider}, Watcher: watcher})
+			manager.AddComponent(Component{Name: "diameter", Apply: func(ctx context.Context, config map[string]interface{}) error {
+				if config["version"] == "v2" {
+					return tt.component
+				}
+				return nil
+			}})
+
+			ctx := context.Background()
+			if _, err := manager.Load(ctx); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if tt.watch {
+				if err := manager.Watch(ctx, tt.callback); err != nil {
+					t.Fatalf("Watch() error = %v", err)
+				}
+				watcher.emit(map[string]interface{}{"version": "v2"})
+			} else {
+				provider.data = map[string]interface{}{"version": "v2"}
+				if err := manager.Reload(ctx, tt.callback); err == nil {
+					t.Error("Expected the reload rejected")
+				}
+			}
+
+			if v, _ := manager.GetString("version"); v != "v1" {
+				t.Errorf("Expected version = v1 after a rejected reload, got %q", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Binding keeps a typed struct unmarshalled from the manager's current config
+type Binding[T any] struct {
+	mu       sync.RWMutex
+	current  T
+	target   *T
+	onChange func(old, new T) error
+}
+
+// Bind unmarshals the current config into a T (using its json tags) and keeps it up to
+// date: after each reload triggered by Watch, the config is unmarshalled again and, if it
+// differs, onChange receives copies of the old and new values
+// If onChange returns an error the new value is rejected and the binding keeps the old one
+// target, if not nil, is also overwritten on each accepted change; concurrent readers
+// should use Get instead. Call after Load
+func Bind[T any](m *Manager, target *T, onChange func(old, new T) error) (*Binding[T], error) {
+	m.mu.RLock()
+	current := m.current
+	m.mu.RUnlock()
+	if current == nil {
+		return nil, fmt.Errorf("config not loaded: call Load before Bind")
+	}
+
+	var initial T
+	if err := decodeInto(current, &initial); err != nil {
+		return nil, err
+	}
+
+	b := &Binding[T]{current: initial, target: target, onChange: onChange}
+	if target != nil {
+		*target = initial
+	}
+
+	m.AddReloadHook(ReloadHook{
+		Name:  fmt.Sprintf("bind(%s)", reflect.TypeOf((*T)(nil)).Elem()),
+		Apply: b.apply,
+	})
+	return b, nil
+}
+
+// Get returns the current value
+func (b *Binding[T]) Get() T {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.current
+}
+
+// apply unmarshals config and publishes it if it changed
+func (b *Binding[T]) apply(ctx context.Context, config map[string]interface{}) error {
+	var next T
+	if err := decodeInto(config, &next); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if reflect.DeepEqual(b.current, next) {
+		return nil
+	}
+	if b.onChange != nil {
+		// Decode a second copy so the callback can't alias the published value
+		var old T
+		if err := roundTrip(b.current, &old); err != nil {
+			return err
+		}
+		if err := b.onChange(old, next); err != nil {
+			return fmt.Errorf("change rejected: %w", err)
+		}
+	}
+
+	b.current = next
+	if b.target != nil {
+		*b.target = next
+	}
+	return nil
+}
+
+// decodeInto unmarshals a config map into target using json tags
+func decodeInto(config map[string]interface{}, target interface{}) error {
+	if err := roundTrip(config, target); err != nil {
+		return fmt.Errorf("failed to unmarshal config into %T: %w", target, err)
+	}
+	return nil
+}
+
+// roundTrip copies src into dst through JSON
+func roundTrip(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// staticProvider returns a fixed config map
+type staticProvider struct {
+	data map[string]interface{}
+}
+
+func (p *staticProvider) Load(ctx context.Context) (map[string]interface{}, error) {
+	return p.data, nil
+}
+func (p *staticProvider) Name() string { return "static" }
+func (p *staticProvider) Close() error { return nil }
+
+type boundConfig struct {
+	Server struct {
+		Port int    `json:"port"`
+		Host string `json:"host"`
+	} `json:"server"`
+	Peers []string `json:"peers"`
+}
+
+func TestBind(t *testing.T) {
+	provider := &staticProvider{data: map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080, "host": "eir-1"},
+		"peers":  []interface{}{"mme-1"},
+	}}
+	manager := NewManager(ManagerConfig{Providers: []Provider{provider}})
+
+	if _, err := Bind(manager, (*boundConfig)(nil), nil); err == nil {
+		t.Error("Expected error when binding before Load")
+	}
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var target boundConfig
+	var changes []string
+	reject := false
+	binding, err := Bind(manager, &target, func(old, new boundConfig) error {
+		if reject {
+			return errors.New("port change needs restart")
+		}
+		old.Peers[0] = "mutated" // Must not leak into the binding
+		changes = append(changes, old.Server.Host+"->"+new.Server.Host)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if target.Server.Port != 8080 || binding.Get().Peers[0] != "mme-1" {
+		t.Fatalf("Unexpected initial value: %+v", target)
+	}
+
+	// Unchanged config does not call back
+	if err := manager.RunReloadHooks(context.Background(), provider.data); err != nil {
+		t.Fatalf("RunReloadHooks failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no change callback, got %v", changes)
+	}
+
+	changed := map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080, "host": "eir-2"},
+		"peers":  []interface{}{"mme-1"},
+	}
+	if err := manager.RunReloadHooks(context.Background(), changed); err != nil {
+		t.Fatalf("RunReloadHooks failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0] != "eir-1->eir-2" {
+		t.Errorf("Expected one change eir-1->eir-2, got %v", changes)
+	}
+	if target.Server.Host != "eir-2" || binding.Get().Peers[0] != "mme-1" {
+		t.Errorf("Unexpected bound value: %+v", binding.Get())
+	}
+
+	// A rejected change keeps the old value
+	reject = true
+	changed["server"] = map[string]interface{}{"port": 9090, "host": "eir-2"}
+	var hookErr *ReloadHookError
+	if err := manager.RunReloadHooks(context.Background(), changed); !errors.As(err, &hookErr) {
+		t.Fatalf("Expected ReloadHookError, got %v", err)
+	}
+	if binding.Get().Server.Port != 8080 || target.Server.Port != 8080 {
+		t.Errorf("Expected rejected change to keep port 8080, got %d", binding.Get().Server.Port)
+	}
+}
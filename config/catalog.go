@@ -0,0 +1,306 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Key types reported in a KeyCatalog
+const (
+	KeyTypeString   = "string"
+	KeyTypeInt      = "int"
+	KeyTypeUint     = "uint"
+	KeyTypeFloat    = "float"
+	KeyTypeBool     = "bool"
+	KeyTypeDuration = "duration"
+	KeyTypeList     = "list"
+	KeyTypeMap      = "map"
+	KeyTypeObject   = "object"
+)
+
+// KeyDoc documents one configuration key
+type KeyDoc struct {
+	Key         string      `json:"key"`                   // Dotted key; "*" stands for any list index or map key
+	Type        string      `json:"type"`                  // KeyTypeString etc.
+	ItemType    string      `json:"item_type,omitempty"`   // Element type of lists and maps
+	Default     interface{} `json:"default,omitempty"`     // As NewDefaultsProvider would supply it
+	Env         string      `json:"env,omitempty"`         // Variable read by BindEnv (scalars only)
+	Required    bool        `json:"required,omitempty"`    // validate:"required"
+	Min         *float64    `json:"min,omitempty"`         // Minimum value, or length for strings and lists
+	Max         *float64    `json:"max,omitempty"`         // Maximum value, or length for strings and lists
+	Enum        []string    `json:"enum,omitempty"`        // validate:"oneof=..."
+	Format      string      `json:"format,omitempty"`      // url, email, ip, ipv4, ipv6, hostname or hostname_port
+	Validate    string      `json:"validate,omitempty"`    // The raw validate tag
+	Secret      bool        `json:"secret,omitempty"`      // Holds a credential (see Manager.IsSecretKey)
+	Description string      `json:"description,omitempty"` // The desc tag
+}
+
+// KeyCatalog is a machine-readable catalog of configuration keys, generated from
+// tagged config structs (such as the schemas package) for tooling that renders
+// configuration forms or documentation
+type KeyCatalog struct {
+	Keys []KeyDoc `json:"keys"`
+}
+
+// NewKeyCatalog creates an empty key catalog
+func NewKeyCatalog() *KeyCatalog {
+	return &KeyCatalog{Keys: []KeyDoc{}}
+}
+
+// GenerateKeyCatalog returns the catalog of a single config struct's keys
+func GenerateKeyCatalog(schema interface{}) (*KeyCatalog, error) {
+	catalog := NewKeyCatalog()
+	if err := catalog.Add(schema, "", ""); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// Add documents the keys of a struct (or pointer to struct) mounted at prefix
+// ("diameter.peer"), with environment variables named as BindEnv(target, envPrefix)
+// binds them
+//
+// Keys, types and defaults follow the same tags as Bind and NewDefaultsProvider:
+// json (then yaml) names, default or envDefault values, otherwise a non-zero
+// current value. Constraints come from the validate tag and descriptions from a
+// desc tag. Nested structs contribute their keys; lists and maps of structs
+// contribute item keys under a "*" segment. Keys are kept sorted.
+func (c *KeyCatalog) Add(schema interface{}, prefix, envPrefix string) error {
+	v := reflect.ValueOf(schema)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("schema must be a struct or pointer to struct, got %T", schema)
+	}
+
+	if err := c.addStruct(v, prefix, envPrefix, true); err != nil {
+		return err
+	}
+	sort.SliceStable(c.Keys, func(i, j int) bool { return c.Keys[i].Key < c.Keys[j].Key })
+	return nil
+}
+
+// Lookup returns the documentation of key
+func (c *KeyCatalog) Lookup(key string) (KeyDoc, bool) {
+	for _, doc := range c.Keys {
+		if doc.Key == key {
+			return doc, true
+		}
+	}
+	return KeyDoc{}, false
+}
+
+// WriteJSON writes the catalog as indented JSON
+func (c *KeyCatalog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// addStruct documents the fields of struct v; bound is false where BindEnv doesn't reach
+func (c *KeyCatalog) addStruct(v reflect.Value, path, envPrefix string, bound bool) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		key, tagged := defaultsKey(fieldType)
+		if key == "-" {
+			continue
+		}
+
+		field := v.Field(i)
+
+		// Embedded structs without a tag contribute their fields, as with encoding/json
+		if fieldType.Anonymous && !tagged && field.Kind() == reflect.Struct {
+			if err := c.addStruct(field, path, envPrefix, bound); err != nil {
+				return err
+			}
+			continue
+		}
+
+		keyPath := key
+		if path != "" {
+			keyPath = path + KeySeparator + key
+		}
+
+		envName := ""
+		if bound {
+			envName = catalogEnvName(fieldType, envPrefix)
+		}
+		if err := c.addField(fieldType, field, keyPath, envName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addField documents one field, recursing into structs and struct elements
+// envName is the field's variable, "" if BindEnv doesn't bind it
+func (c *KeyCatalog) addField(fieldType reflect.StructField, field reflect.Value, keyPath, envName string) error {
+	t := fieldType.Type
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		// BindEnv only descends into struct values; nil pointers document the zero struct
+		t, envName = t.Elem(), ""
+		if field.IsNil() {
+			field = reflect.New(t).Elem()
+		} else {
+			field = field.Elem()
+		}
+	}
+
+	doc := KeyDoc{
+		Key:         keyPath,
+		Type:        catalogType(t),
+		Secret:      isCredentialKey(keyPath),
+		Description: fieldType.Tag.Get("desc"),
+	}
+	applyConstraints(&doc, fieldType.Tag.Get("validate"))
+
+	switch doc.Type {
+	case KeyTypeObject:
+		// Structs are documented through their fields
+		if doc.Description != "" || doc.Required {
+			c.Keys = append(c.Keys, doc)
+		}
+		return c.addStruct(field, keyPath, envName, envName != "")
+
+	case KeyTypeList, KeyTypeMap:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		doc.ItemType = catalogType(elem)
+		if err := c.setDefault(&doc, fieldType, field); err != nil {
+			return err
+		}
+		c.Keys = append(c.Keys, doc)
+		if elem.Kind() == reflect.Struct {
+			return c.addStruct(reflect.New(elem).Elem(), keyPath+KeySeparator+"*", "", false)
+		}
+		return nil
+	}
+
+	doc.Env = envName
+	if err := c.setDefault(&doc, fieldType, field); err != nil {
+		return err
+	}
+	c.Keys = append(c.Keys, doc)
+	return nil
+}
+
+// setDefault sets the default from the field's tag, or its non-zero current value
+func (c *KeyCatalog) setDefault(doc *KeyDoc, fieldType reflect.StructField, field reflect.Value) error {
+	tag, ok := fieldType.Tag.Lookup("default")
+	if !ok {
+		tag = fieldType.Tag.Get("envDefault")
+		ok = tag != ""
+	}
+	if ok {
+		value, err := parseDefault(fieldType.Type, tag)
+		if err != nil {
+			return fmt.Errorf("invalid default for %s: %w", doc.Key, err)
+		}
+		doc.Default = value
+		return nil
+	}
+
+	if field.IsZero() {
+		return nil
+	}
+	value, ok, err := defaultValue(field, doc.Key)
+	if err != nil {
+		return err
+	}
+	if ok {
+		doc.Default = value
+	}
+	return nil
+}
+
+// catalogEnvName returns the variable BindEnv reads for a field, "" if it reads none
+func catalogEnvName(field reflect.StructField, envPrefix string) string {
+	envTag := field.Tag.Get("env")
+	if envTag == "-" {
+		return ""
+	}
+	if envTag == "" {
+		envTag = strings.ToUpper(field.Name)
+	}
+	if envPrefix == "" {
+		return envTag
+	}
+	return envPrefix + "_" + envTag
+}
+
+// catalogType returns the KeyDoc type of t
+func catalogType(t reflect.Type) string {
+	if t == durationType {
+		return KeyTypeDuration
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return KeyTypeString
+	case reflect.Bool:
+		return KeyTypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return KeyTypeInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return KeyTypeUint
+	case reflect.Float32, reflect.Float64:
+		return KeyTypeFloat
+	case reflect.Slice, reflect.Array:
+		return KeyTypeList
+	case reflect.Map:
+		return KeyTypeMap
+	case reflect.Struct:
+		return KeyTypeObject
+	case reflect.Ptr:
+		return catalogType(t.Elem())
+	default:
+		return t.Kind().String()
+	}
+}
+
+// applyConstraints fills the constraint fields of doc from a validate tag
+// Rules after "dive" constrain elements and are only kept in Validate
+func applyConstraints(doc *KeyDoc, tag string) {
+	if tag == "" || tag == "-" {
+		return
+	}
+	doc.Validate = tag
+
+	for _, rule := range splitRules(tag) {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return
+		case "required":
+			doc.Required = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if name == "min" {
+				doc.Min = &n
+			} else {
+				doc.Max = &n
+			}
+		case "oneof":
+			doc.Enum = strings.Fields(value)
+		case "url", "email", "ip", "ipv4", "ipv6", "hostname", "hostname_port":
+			doc.Format = name
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type catalogTestConfig struct {
+	Name     string        `json:"name" env:"NAME" validate:"required" desc:"Service name"`
+	Mode     string        `json:"mode" env:"MODE" envDefault:"active" validate:"oneof=active standby"`
+	Timeout  time.Duration `json:"timeout" default:"5s"`
+	Workers  int           `json:"workers" validate:"min=1,max=64"`
+	Endpoint string        `json:"endpoint" validate:"omitempty,url"`
+	Database struct {
+		Host     string `json:"host" env:"HOST"`
+		Password string `json:"password" env:"PASSWORD"`
+	} `json:"database" env:"DB"`
+	Peers  []catalogTestPeer `json:"peers" validate:"dive"`
+	Labels map[string]string `json:"labels"`
+	Cache  *struct {
+		Size int `json:"size" default:"128"`
+	} `json:"cache"`
+}
+
+type catalogTestPeer struct {
+	Host string `json:"host" validate:"required,hostname"`
+}
+
+// TestKeyCatalog tests keys, types, defaults, env names and constraints are documented
+func TestKeyCatalog(t *testing.T) {
+	catalog := NewKeyCatalog()
+	if err := catalog.Add(catalogTestConfig{Workers: 4}, "app", "APP"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	lookup := func(key string) KeyDoc {
+		t.Helper()
+		doc, ok := catalog.Lookup(key)
+		if !ok {
+			t.Fatalf("Expected key %s in %+v", key, catalog.Keys)
+		}
+		return doc
+	}
+
+	if doc := lookup("app.name"); doc.Type != KeyTypeString || !doc.Required || doc.Env != "APP_NAME" || doc.Description != "Service name" {
+		t.Errorf("app.name = %+v", doc)
+	}
+	if doc := lookup("app.mode"); doc.Default != "active" || len(doc.Enum) != 2 {
+		t.Errorf("app.mode = %+v", doc)
+	}
+	if doc := lookup("app.timeout"); doc.Type != KeyTypeDuration || doc.Default != "5s" {
+		t.Errorf("app.timeout = %+v", doc)
+	}
+	if doc := lookup("app.workers"); doc.Default != 4 || *doc.Min != 1 || *doc.Max != 64 {
+		t.Errorf("app.workers = %+v", doc)
+	}
+	if doc := lookup("app.endpoint"); doc.Format != "url" || doc.Required {
+		t.Errorf("app.endpoint = %+v", doc)
+	}
+	if doc := lookup("app.database.password"); doc.Env != "APP_DB_PASSWORD" || !doc.Secret {
+		t.Errorf("app.database.password = %+v", doc)
+	}
+	if doc := lookup("app.peers"); doc.Type != KeyTypeList || doc.ItemType != KeyTypeObject {
+		t.Errorf("app.peers = %+v", doc)
+	}
+	if doc := lookup("app.peers.*.host"); !doc.Required || doc.Format != "hostname" || doc.Env != "" {
+		t.Errorf("app.peers.*.host = %+v", doc)
+	}
+	if doc := lookup("app.labels"); doc.Type != KeyTypeMap || doc.ItemType != KeyTypeString {
+		t.Errorf("app.labels = %+v", doc)
+	}
+	if doc := lookup("app.cache.size"); doc.Default != 128 || doc.Env != "" {
+		t.Errorf("app.cache.size = %+v", doc)
+	}
+	if _, ok := catalog.Lookup("app.database"); ok {
+		t.Error("Expected undescribed objects to be documented through their fields only")
+	}
+
+	for i := 1; i < len(catalog.Keys); i++ {
+		if catalog.Keys[i-1].Key > catalog.Keys[i].Key {
+			t.Errorf("Expected sorted keys, got %s before %s", catalog.Keys[i-1].Key, catalog.Keys[i].Key)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded KeyCatalog
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Keys) != len(catalog.Keys) {
+		t.Errorf("Expected the JSON catalog to round-trip, got %v", err)
+	}
+
+	if _, err := GenerateKeyCatalog("not a struct"); err == nil {
+		t.Error("Expected an error for a non-struct schema")
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Standard config channel names
+const (
+	ChannelStable = "stable"
+	ChannelCanary = "canary"
+)
+
+// ChannelProvider serves one of several named config channels, e.g. a stable and
+// a canary document in the remote store, and delegates Load to the active one
+// Switch channels through Manager.SwitchChannel to get automatic rollback
+type ChannelProvider struct {
+	name     string
+	channels map[string]Provider
+
+	mu     sync.RWMutex
+	active string
+}
+
+// NewChannelProvider creates a channel provider serving active out of channels
+func NewChannelProvider(name string, channels map[string]Provider, active string) (*ChannelProvider, error) {
+	if _, ok := channels[active]; !ok {
+		return nil, fmt.Errorf("unknown config channel %q", active)
+	}
+	if name == "" {
+		name = "channels"
+	}
+	return &ChannelProvider{name: name, channels: channels, active: active}, nil
+}
+
+// NewConsulChannelProvider loads the stable and canary channels from the Consul keys
+// cfg.Key + "/stable" and cfg.Key + "/canary", starting on the stable channel
+func NewConsulChannelProvider(cfg RemoteProviderConfig) (*ChannelProvider, error) {
+	channels := make(map[string]Provider, 2)
+	for _, channel := range []string{ChannelStable, ChannelCanary} {
+		channelCfg := cfg
+		channelCfg.Key = strings.TrimSuffix(cfg.Key, "/") + "/" + channel
+		if cfg.Prefix {
+			channelCfg.Key += "/"
+		}
+		provider, err := NewConsulProvider(channelCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s channel provider: %w", channel, err)
+		}
+		channels[channel] = provider
+	}
+	return NewChannelProvider("consul", channels, ChannelStable)
+}
+
+// Load retrieves the configuration of the active channel
+func (p *ChannelProvider) Load(ctx context.Context) (map[string]interface{}, error) {
+	p.mu.RLock()
+	active := p.active
+	p.mu.RUnlock()
+
+	data, err := p.channels[active].Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("channel %s: %w", active, err)
+	}
+	return data, nil
+}
+
+// Active returns the active channel
+func (p *ChannelProvider) Active() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.active
+}
+
+// Channels returns the channel names, sorted
+func (p *ChannelProvider) Channels() []string {
+	names := make([]string, 0, len(p.channels))
+	for name := range p.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setActive makes channel active without reloading
+func (p *ChannelProvider) setActive(channel string) error {
+	if _, ok := p.channels[channel]; !ok {
+		return fmt.Errorf("unknown config channel %q", channel)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = channel
+	return nil
+}
+
+// Name returns the provider name
+func (p *ChannelProvider) Name() string {
+	return p.name
+}
+
+// Close closes every channel's provider
+func (p *ChannelProvider) Close() error {
+	for _, name := range p.Channels() {
+		if err := p.channels[name].Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChannelSwitch describes the last switch made with Manager.SwitchChannel
+type ChannelSwitch struct {
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	StartedAt  time.Time `json:"started_at"`
+	BakeUntil  time.Time `json:"bake_until"`
+	Baked      bool      `json:"baked"`       // The bake period passed without errors
+	RolledBack bool      `json:"rolled_back"` // From was restored
+	Error      string    `json:"error,omitempty"`
+}
+
+// channelSwitch is the switch being baked, with what it needs to roll back
+type channelSwitch struct {
+	ChannelSwitch
+	provider *ChannelProvider
+	callback func(map[string]interface{}) error
+	timer    *time.Timer
+}
+
+// SwitchChannel makes channel active on provider (one of the manager's providers)
+// and reloads, running callback and the reload hooks as for Watch
+//
+// If callback fails, the previous channel is restored and reloaded at once and the
+// error returned. Otherwise the switch bakes for bake: any reload during that
+// period whose callback fails also rolls back, and OnChannelRollback is notified.
+// A switch made while another is baking replaces it, rolling back to the channel
+// active at the time of the new switch
+func (m *Manager) SwitchChannel(ctx context.Context, provider *ChannelProvider, channel string, bake time.Duration, callback func(map[string]interface{}) error) error {
+	previous := provider.Active()
+	if err := provider.setActive(channel); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	old := m.current
+	if m.channelSwitch != nil && m.channelSwitch.timer != nil {
+		m.channelSwitch.timer.Stop()
+	}
+	now := time.Now()
+	sw := &channelSwitch{
+		ChannelSwitch: ChannelSwitch{From: previous, To: channel, StartedAt: now, BakeUntil: now.Add(bake)},
+		provider:      provider,
+		callback:      callback,
+	}
+	m.channelSwitch = sw
+	m.mu.Unlock()
+
+	data, err := m.Load(ctx)
+	if err != nil {
+		provider.setActive(previous)
+		m.finishSwitch(sw, true, err)
+		return fmt.Errorf("failed to load config channel %s: %w", channel, err)
+	}
+
+	if err := m.applyReload(ctx, old, data, callback); err != nil {
+		m.rollbackChannel(ctx, sw, err)
+		return fmt.Errorf("config channel %s rolled back to %s: %w", channel, previous, err)
+	}
+
+	if bake <= 0 {
+		m.finishSwitch(sw, false, nil)
+		return nil
+	}
+	m.mu.Lock()
+	if m.channelSwitch == sw {
+		sw.timer = time.AfterFunc(bake, func() { m.finishSwitch(sw, false, nil) })
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// ChannelSwitch returns the last channel switch, false if there was none
+func (m *Manager) ChannelSwitch() (ChannelSwitch, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.channelSwitch == nil {
+		return ChannelSwitch{}, false
+	}
+	return m.channelSwitch.ChannelSwitch, true
+}
+
+// observeReload rolls back a baking channel switch when a reload callback fails
+func (m *Manager) observeReload(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	m.mu.RLock()
+	sw := m.channelSwitch
+	baking := sw != nil && !sw.Baked && !sw.RolledBack && time.Now().Before(sw.BakeUntil)
+	m.mu.RUnlock()
+	if baking {
+		m.rollbackChannel(ctx, sw, err)
+	}
+}
+
+// rollbackChannel restores the channel active before sw and reloads it
+func (m *Manager) rollbackChannel(ctx context.Context, sw *channelSwitch, cause error) {
+	if !m.finishSwitch(sw, true, cause) {
+		return
+	}
+	sw.provider.setActive(sw.From)
+
+	m.mu.RLock()
+	old := m.current
+	onRollback := m.onChannelRollback
+	m.mu.RUnlock()
+	if data, err := m.Load(ctx); err == nil {
+		m.applyReload(ctx, old, data, sw.callback)
+	}
+	if onRollback != nil {
+		onRollback(sw.ChannelSwitch)
+	}
+}
+
+// finishSwitch ends the bake period of sw, returning false if it had already ended
+func (m *Manager) finishSwitch(sw *channelSwitch, rolledBack bool, err error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if sw.Baked || sw.RolledBack {
+		return false
+	}
+	if sw.timer != nil {
+		sw.timer.Stop()
+	}
+	sw.RolledBack = rolledBack
+	sw.Baked = !rolledBack
+	if err != nil {
+		sw.Error = err.Error()
+	}
+	return true
+}
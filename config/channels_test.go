@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newChannelTestManager(t *testing.T, watcher Watcher, onRollback func(ChannelSwitch)) (*Manager, *ChannelProvider) {
+	t.Helper()
+	channels, err := NewChannelProvider("remote", map[string]Provider{
+		ChannelStable: NewMockProvider("stable", map[string]interface{}{"mode": "stable"}),
+		ChannelCanary: NewMockProvider("canary", map[string]interface{}{"mode": "canary"}),
+	}, ChannelStable)
+	if err != nil {
+		t.Fatalf("NewChannelProvider() error = %v", err)
+	}
+
+	manager := NewManager(ManagerConfig{
+		Providers:         []Provider{channels},
+		Watcher:           watcher,
+		OnChannelRollback: onRollback,
+	})
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return manager, channels
+}
+
+func TestManager_SwitchChannel(t *testing.T) {
+	manager, channels := newChannelTestManager(t, nil, nil)
+	ctx := context.Background()
+
+	var applied string
+	callback := func(config map[string]interface{}) error {
+		applied = config["mode"].(string)
+		return nil
+	}
+	if err := manager.SwitchChannel(ctx, channels, ChannelCanary, 20*time.Millisecond, callback); err != nil {
+		t.Fatalf("SwitchChannel() error = %v", err)
+	}
+	if mode, _ := manager.GetString("mode"); mode != "canary" || applied != "canary" || channels.Active() != ChannelCanary {
+		t.Errorf("Expected the canary channel to be applied, got %q", mode)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if sw, ok := manager.ChannelSwitch(); !ok || !sw.Baked || sw.RolledBack || sw.From != ChannelStable {
+		t.Errorf("Expected a baked switch, got %+v", sw)
+	}
+
+	if err := manager.SwitchChannel(ctx, channels, "beta", 0, callback); err == nil {
+		t.Error("Expected an error for an unknown channel")
+	}
+}
+
+func TestManager_SwitchChannel_RejectedByCallback(t *testing.T) {
+	manager, channels := newChannelTestManager(t, nil, nil)
+
+	reject := errors.New("canary config rejected")
+	var calls []string
+	err := manager.SwitchChannel(context.Background(), channels, ChannelCanary, time.Minute, func(config map[string]interface{}) error {
+		calls = append(calls, config["mode"].(string))
+		if config["mode"] == "canary" {
+			return reject
+		}
+		return nil
+	})
+	if !errors.Is(err, reject) {
+		t.Fatalf("SwitchChannel() error = %v, want rollback error", err)
+	}
+	if mode, _ := manager.GetString("mode"); mode != "stable" || channels.Active() != ChannelStable {
+		t.Errorf("Expected the stable channel to be restored, got %q", mode)
+	}
+	if len(calls) != 2 || calls[1] != "stable" {
+		t.Errorf("Expected the callback to re-apply the stable config, got %v", calls)
+	}
+	if sw, _ := manager.ChannelSwitch(); !sw.RolledBack || sw.Error != reject.Error() {
+		t.Errorf("Expected a rolled back switch, got %+v", sw)
+	}
+}
+
+func TestManager_SwitchChannel_RollbackDuringBake(t *testing.T) {
+	watcher := &mockWatcher{}
+	rolledBack := make(chan ChannelSwitch, 1)
+	manager, channels := newChannelTestManager(t, watcher, func(sw ChannelSwitch) { rolledBack <- sw })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The service starts failing on the canary config after the switch was applied
+	var failing bool
+	callback := func(config map[string]interface{}) error {
+		if failing && config["mode"] == "canary" {
+			return errors.New("health check failed")
+		}
+		return nil
+	}
+	if err := manager.Watch(ctx, callback); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if err := manager.SwitchChannel(ctx, channels, ChannelCanary, time.Minute, callback); err != nil {
+		t.Fatalf("SwitchChannel() error = %v", err)
+	}
+
+	failing = true
+	watcher.emit(map[string]interface{}{"mode": "canary"})
+
+	select {
+	case sw := <-rolledBack:
+		if sw.To != ChannelCanary || sw.Error != "health check failed" {
+			t.Errorf("Unexpected rollback %+v", sw)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a rollback during the bake period")
+	}
+	if mode, _ := manager.GetString("mode"); mode != "stable" || channels.Active() != ChannelStable {
+		t.Errorf("Expected the stable channel after rollback, got %q", mode)
+	}
+}
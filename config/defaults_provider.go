@@ -0,0 +1,258 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultsProvider supplies built-in defaults extracted from a struct
+// Add it last in ManagerConfig.Providers so every other provider overrides it and
+// the merged config always starts from a complete, typed baseline
+type DefaultsProvider struct {
+	data map[string]interface{}
+}
+
+// NewDefaultsProvider extracts defaults from a struct (or pointer to struct)
+//
+// Keys follow the json tags (then yaml tags, then field names), as used by Bind.
+// A field's value is its `default:` tag (or `envDefault:` tag, as in the schemas
+// package) parsed into the field's type, otherwise its current value. Nested
+// structs (including nil struct pointers) become nested maps; other nil pointers,
+// maps and slices are omitted.
+// time.Duration values are kept as duration strings ("5s"), as in config files.
+func NewDefaultsProvider(defaults interface{}) (*DefaultsProvider, error) {
+	v := reflect.ValueOf(defaults)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("defaults must be a struct or pointer to struct, got %T", defaults)
+	}
+
+	data := make(map[string]interface{})
+	if err := extractDefaults(v, data, ""); err != nil {
+		return nil, err
+	}
+	return &DefaultsProvider{data: data}, nil
+}
+
+// Load returns a copy of the defaults, since the manager merges into the maps of
+// the lowest priority provider
+func (d *DefaultsProvider) Load(ctx context.Context) (map[string]interface{}, error) {
+	return copyTree(d.data), nil
+}
+
+// Name returns the provider name
+func (d *DefaultsProvider) Name() string {
+	return "defaults"
+}
+
+// Close cleans up resources
+func (d *DefaultsProvider) Close() error {
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// extractDefaults copies the defaults of struct v into data
+func extractDefaults(v reflect.Value, data map[string]interface{}, path string) error {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		fieldType := t.Field(i)
+
+		// Skip unexported fields
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		key, tagged := defaultsKey(fieldType)
+		if key == "-" {
+			continue
+		}
+
+		// Embedded structs without a tag contribute their fields, as with encoding/json
+		if fieldType.Anonymous && !tagged && field.Kind() == reflect.Struct {
+			if err := extractDefaults(field, data, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + KeySeparator + key
+		}
+
+		tag, ok := fieldType.Tag.Lookup("default")
+		if !ok {
+			tag = fieldType.Tag.Get("envDefault")
+			ok = tag != ""
+		}
+		if ok {
+			value, err := parseDefault(fieldType.Type, tag)
+			if err != nil {
+				return fmt.Errorf("invalid default for %s: %w", fieldPath, err)
+			}
+			data[key] = value
+			continue
+		}
+
+		value, ok, err := defaultValue(field, fieldPath)
+		if err != nil {
+			return err
+		}
+		if ok {
+			data[key] = value
+		}
+	}
+
+	return nil
+}
+
+// defaultsKey returns the config key of a field and whether it came from a tag
+func defaultsKey(field reflect.StructField) (string, bool) {
+	for _, tagName := range []string{"json", "yaml"} {
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			if name := strings.Split(tag, ",")[0]; name != "" {
+				return name, true
+			}
+		}
+	}
+	return field.Name, false
+}
+
+// parseDefault parses a default tag into the config value of a field of type t
+func parseDefault(t reflect.Type, tag string) (interface{}, error) {
+	if t == durationType {
+		d, err := time.ParseDuration(tag)
+		if err != nil {
+			return nil, err
+		}
+		return d.String(), nil
+	}
+
+	value := reflect.New(t).Elem()
+	if err := setFieldValue(value, tag); err != nil {
+		return nil, err
+	}
+	return configValue(value), nil
+}
+
+// defaultValue returns the config value of a field's current value, false if it has none
+func defaultValue(v reflect.Value, path string) (interface{}, bool, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			// A nil struct pointer still has the struct's tagged defaults
+			if v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct {
+				return defaultValue(reflect.New(v.Type().Elem()).Elem(), path)
+			}
+			return nil, false, nil
+		}
+		return defaultValue(v.Elem(), path)
+
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return nil, false, nil
+		}
+		nested := make(map[string]interface{})
+		if err := extractDefaults(v, nested, path); err != nil {
+			return nil, false, err
+		}
+		return nested, true, nil
+
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return nil, false, nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if value, ok, err := defaultValue(iter.Value(), path+KeySeparator+key); err != nil {
+				return nil, false, err
+			} else if ok {
+				m[key] = value
+			}
+		}
+		return m, true, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, false, nil
+		}
+		list := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			value, ok, err := defaultValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				list = append(list, value)
+			}
+		}
+		return list, true, nil
+
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return configValue(v), true, nil
+
+	default:
+		return nil, false, fmt.Errorf("unsupported default type for %s: %s", path, v.Type())
+	}
+}
+
+// configValue converts a scalar to the types providers produce: string, bool,
+// int, float64 and duration strings
+func configValue(v reflect.Value) interface{} {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return v.Interface()
+	}
+}
+
+// copyTree deep copies the maps and slices of a config tree
+func copyTree(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = copyValue(v)
+	}
+	return out
+}
+
+// copyValue deep copies a config value
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return copyTree(val)
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = copyValue(item)
+		}
+		return list
+	default:
+		return v
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hsdfat/telco/config/schemas"
+)
+
+type testServiceDefaults struct {
+	Name    string        `json:"name" default:"eir"`
+	Port    int           `json:"port" default:"3868"`
+	Debug   bool          `json:"debug"`
+	Ratio   float64       `json:"ratio" default:"0.5"`
+	Timeout time.Duration `json:"timeout" default:"5s"`
+	Retry   time.Duration `json:"retry"`
+	Peers   []string      `json:"peers"`
+	Ignored string        `json:"-" default:"x"`
+
+	Database *schemas.DatabasePool `json:"database"`
+	Limits   struct {
+		MaxConns int `yaml:"max_conns" default:"100"`
+	} `json:"limits"`
+}
+
+func TestDefaultsProvider(t *testing.T) {
+	defaults := testServiceDefaults{Retry: time.Second, Peers: []string{"hss1"}}
+	provider, err := NewDefaultsProvider(&defaults)
+	if err != nil {
+		t.Fatalf("NewDefaultsProvider() error = %v", err)
+	}
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"name":    "eir",
+		"port":    3868,
+		"debug":   false,
+		"ratio":   0.5,
+		"timeout": "5s",
+		"retry":   "1s",
+		"peers":   []interface{}{"hss1"},
+		"limits":  map[string]interface{}{"max_conns": 100},
+	}
+	for key, value := range want {
+		if !reflect.DeepEqual(data[key], value) {
+			t.Errorf("%s = %#v, want %#v", key, data[key], value)
+		}
+	}
+	if _, ok := data["Ignored"]; ok {
+		t.Error("Expected json:\"-\" field to be skipped")
+	}
+
+	// envDefault tags of a nil schema pointer are used too
+	db, _ := data["database"].(map[string]interface{})
+	if db["driver"] != "postgres" || db["port"] != 5432 || db["host"] != "" {
+		t.Errorf("database = %v", db)
+	}
+
+	// Load returns a copy the manager may merge into
+	db["port"] = 1
+	again, _ := provider.Load(context.Background())
+	if again["database"].(map[string]interface{})["port"] != 5432 {
+		t.Error("Load() should return a fresh copy of the defaults")
+	}
+}
+
+func TestDefaultsProvider_Manager(t *testing.T) {
+	defaults, err := NewDefaultsProvider(testServiceDefaults{})
+	if err != nil {
+		t.Fatalf("NewDefaultsProvider() error = %v", err)
+	}
+	file := NewMockProvider("file", map[string]interface{}{
+		"port":     3869,
+		"database": map[string]interface{}{"host": "db1"},
+	})
+
+	manager := NewManager(ManagerConfig{Providers: []Provider{file, defaults}, StrictMerge: true})
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if port, _ := manager.GetInt("port"); port != 3869 {
+		t.Errorf("port = %d, want override 3869", port)
+	}
+	if name, _ := manager.GetString("name"); name != "eir" {
+		t.Errorf("name = %q, want default eir", name)
+	}
+	if host, _ := manager.GetString("database.host"); host != "db1" {
+		t.Errorf("database.host = %q, want db1", host)
+	}
+	if conns, _ := manager.GetInt("database.max_open_conns"); conns != 25 {
+		t.Errorf("database.max_open_conns = %d, want default 25", conns)
+	}
+	if conflicts := manager.MergeConflicts(); len(conflicts) != 0 {
+		t.Errorf("Expected no merge conflicts, got %v", conflicts)
+	}
+}
+
+func TestDefaultsProvider_Errors(t *testing.T) {
+	if _, err := NewDefaultsProvider("not a struct"); err == nil {
+		t.Error("Expected error for a non-struct")
+	}
+
+	type badDefault struct {
+		Port int `json:"port" default:"http"`
+	}
+	if _, err := NewDefaultsProvider(badDefault{}); err == nil {
+		t.Error("Expected error for an unparsable default")
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -19,8 +20,31 @@ type EnvProviderConfig struct {
 
 	// AutomaticEnv enables automatic environment variable binding
 	AutomaticEnv bool
+
+	// ListSeparator splits values into lists (e.g., "," for EIR_ALLOWED_IPS=a,b,c)
+	// Empty disables list splitting
+	ListSeparator string
+
+	// TypeHints fixes the type of values by dot-separated key pattern ("*" matches one
+	// segment; a pattern also covers the keys under it), e.g. {"sccp.gt": ValueString}
+	// A value that doesn't parse as its hinted type fails Load
+	TypeHints map[string]ValueType
+
+	// RawStrings keeps every value without a type hint as a string
+	RawStrings bool
 }
 
+// ValueType is a type hint for environment values
+type ValueType string
+
+// Value types for EnvProviderConfig.TypeHints
+const (
+	ValueString ValueType = "string"
+	ValueInt    ValueType = "int"
+	ValueFloat  ValueType = "float"
+	ValueBool   ValueType = "bool"
+)
+
 // EnvProvider implements Provider for environment variables
 // It overlays environment variables on top of base configuration
 type EnvProvider struct {
@@ -44,6 +68,7 @@ func NewEnvProvider(cfg EnvProviderConfig) *EnvProvider {
 
 // Load reads environment variables and converts them to nested map
 // Example: EIR_SERVER_PORT=8080 -> {"server": {"port": 8080}}
+// Indexed keys become lists: EIR_PEERS_0_HOST=a -> {"peers": [{"host": "a"}]}
 func (e *EnvProvider) Load(ctx context.Context) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
@@ -68,47 +93,148 @@ func (e *EnvProvider) Load(ctx context.Context) (map[string]interface{}, error)
 
 		// Convert KEY_NAME to nested structure
 		path := strings.Split(strings.ToLower(key), strings.ToLower(e.separator))
-		e.setNestedValue(result, path, value)
+		if err := e.setNestedValue(result, path, value); err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", parts[0], err)
+		}
 	}
 
-	return result, nil
+	return indexedMapsToSlices(result).(map[string]interface{}), nil
 }
 
-// setNestedValue sets a value in a nested map structure
-func (e *EnvProvider) setNestedValue(m map[string]interface{}, path []string, value string) {
-	if len(path) == 0 {
-		return
+// indexedMapsToSlices converts maps keyed by contiguous indexes ("0", "1", ...) into slices
+func indexedMapsToSlices(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
 	}
 
-	key := path[0]
+	for k, v := range m {
+		m[k] = indexedMapsToSlices(v)
+	}
 
-	if len(path) == 1 {
-		// Leaf value - try to parse as appropriate type
-		m[key] = e.parseValue(value)
-		return
+	if len(m) == 0 {
+		return m
 	}
+	list := make([]interface{}, len(m))
+	for k, v := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m // Not a contiguous index set, keep as map
+		}
+		list[i] = v
+	}
+	return list
+}
 
-	// Create nested map if it doesn't exist
-	if _, ok := m[key]; !ok {
-		m[key] = make(map[string]interface{})
+// setNestedValue sets a value in a nested map structure
+// Nesting follows the same rules as Expand: nested values win over a conflicting leaf
+func (e *EnvProvider) setNestedValue(m map[string]interface{}, path []string, value string) error {
+	hint := e.typeHint(strings.Join(path, KeySeparator))
+	if hint == "" {
+		setPath(m, path, e.parseValue(value))
+		return nil
 	}
 
-	// Ensure it's a map
-	if nested, ok := m[key].(map[string]interface{}); ok {
-		e.setNestedValue(nested, path[1:], value)
+	parsed, err := e.parseHinted(value, hint)
+	if err != nil {
+		return err
 	}
+	setPath(m, path, parsed)
+	return nil
+}
+
+// typeHint returns the hint for key, preferring the most specific matching pattern
+func (e *EnvProvider) typeHint(key string) ValueType {
+	var hint ValueType
+	best := -1
+	for pattern, t := range e.config.TypeHints {
+		if n := strings.Count(pattern, KeySeparator); n > best && matchKeyPattern(pattern, key) {
+			hint, best = t, n
+		}
+	}
+	return hint
 }
 
 // parseValue attempts to parse string value to appropriate type
+// With a list separator configured, separated values become lists of parsed elements
 func (e *EnvProvider) parseValue(value string) interface{} {
+	parse := parseScalar
+	if e.config.RawStrings {
+		parse = func(s string) interface{} { return s }
+	}
+
+	if e.config.ListSeparator != "" && strings.Contains(value, e.config.ListSeparator) {
+		parts := strings.Split(value, e.config.ListSeparator)
+		list := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			list = append(list, parse(strings.TrimSpace(part)))
+		}
+		return list
+	}
+
+	return parse(value)
+}
+
+// parseHinted parses value (or each separated element) as hint
+func (e *EnvProvider) parseHinted(value string, hint ValueType) (interface{}, error) {
+	if e.config.ListSeparator != "" && strings.Contains(value, e.config.ListSeparator) {
+		parts := strings.Split(value, e.config.ListSeparator)
+		list := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			v, err := parseAs(strings.TrimSpace(part), hint)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	}
+
+	return parseAs(value, hint)
+}
+
+// parseAs parses a single string value as the hinted type
+func parseAs(value string, hint ValueType) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+	switch hint {
+	case ValueString:
+		return value, nil
+	case ValueInt:
+		v, err = strconv.ParseInt(value, 10, 64)
+	case ValueFloat:
+		v, err = strconv.ParseFloat(value, 64)
+	case ValueBool:
+		v, err = strconv.ParseBool(value)
+	default:
+		return nil, fmt.Errorf("unknown type hint %q", hint)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q as %s", value, hint)
+	}
+	return v, nil
+}
+
+// parseScalar attempts to parse a single string value to bool, int, float or string
+// Values a number would not reproduce stay strings: leading zeros ("0123"), a leading
+// "+" (E.164 numbers) and integers beyond int64
+func parseScalar(value string) interface{} {
 	// Try boolean
 	if b, err := strconv.ParseBool(value); err == nil {
 		return b
 	}
 
+	if !isPlainNumber(value) {
+		return value
+	}
+
 	// Try integer
 	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
 		return i
+	} else if errors.Is(err, strconv.ErrRange) {
+		return value
 	}
 
 	// Try float
@@ -120,6 +246,20 @@ func (e *EnvProvider) parseValue(value string) interface{} {
 	return value
 }
 
+// isPlainNumber reports whether value is an optionally negative decimal number without
+// a "+" sign or redundant leading zeros, so parsing it loses nothing but formatting
+func isPlainNumber(value string) bool {
+	digits := strings.TrimPrefix(value, "-")
+	if digits == "" || digits[0] < '0' || digits[0] > '9' {
+		return false
+	}
+	intPart := digits
+	if i := strings.IndexAny(digits, ".eE"); i >= 0 {
+		intPart = digits[:i]
+	}
+	return len(intPart) == 1 || intPart[0] != '0'
+}
+
 // Name returns the provider name
 func (e *EnvProvider) Name() string {
 	return fmt.Sprintf("env(%s*)", e.prefix)
@@ -277,6 +417,16 @@ func unmarshalMap(data map[string]interface{}, v reflect.Value) error {
 			continue
 		}
 
+		// Handle lists (e.g., peers from EIR_PEERS_0_HOST or CSV values)
+		if field.Kind() == reflect.Slice {
+			if list, ok := value.([]interface{}); ok {
+				if err := setSliceValue(field, list); err != nil {
+					return fmt.Errorf("failed to set field %s: %w", fieldType.Name, err)
+				}
+				continue
+			}
+		}
+
 		// Set field value
 		if err := setFieldValueFromInterface(field, value); err != nil {
 			return fmt.Errorf("failed to set field %s: %w", fieldType.Name, err)
@@ -286,6 +436,40 @@ func unmarshalMap(data map[string]interface{}, v reflect.Value) error {
 	return nil
 }
 
+// setSliceValue fills a slice field from a list of maps (structs), lists (nested slices) or scalars
+func setSliceValue(field reflect.Value, list []interface{}) error {
+	slice := reflect.MakeSlice(field.Type(), len(list), len(list))
+
+	for i, item := range list {
+		elem := slice.Index(i)
+
+		if elem.Kind() == reflect.Struct {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("element %d: cannot convert %T to %s", i, item, elem.Type())
+			}
+			if err := unmarshalMap(itemMap, elem); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			continue
+		}
+
+		if nested, ok := item.([]interface{}); ok && elem.Kind() == reflect.Slice {
+			if err := setSliceValue(elem, nested); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			continue
+		}
+
+		if err := setFieldValueFromInterface(elem, item); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	field.Set(slice)
+	return nil
+}
+
 // setFieldValueFromInterface sets field from interface{} value
 func setFieldValueFromInterface(field reflect.Value, value interface{}) error {
 	v := reflect.ValueOf(value)
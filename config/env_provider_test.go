@@ -296,3 +296,175 @@ func TestEnvProvider_Name(t *testing.T) {
 		t.Errorf("Name() = %v, want env(TEST_*)", name)
 	}
 }
+
+func TestEnvProvider_Load_IndexedLists(t *testing.T) {
+	os.Setenv("LISTTEST_PEERS_0_HOST", "hss1.example")
+	os.Setenv("LISTTEST_PEERS_0_PORT", "3868")
+	os.Setenv("LISTTEST_PEERS_1_HOST", "hss2.example")
+	os.Setenv("LISTTEST_SPARSE_0", "a")
+	os.Setenv("LISTTEST_SPARSE_2", "c")
+	defer func() {
+		os.Unsetenv("LISTTEST_PEERS_0_HOST")
+		os.Unsetenv("LISTTEST_PEERS_0_PORT")
+		os.Unsetenv("LISTTEST_PEERS_1_HOST")
+		os.Unsetenv("LISTTEST_SPARSE_0")
+		os.Unsetenv("LISTTEST_SPARSE_2")
+	}()
+
+	provider := NewEnvProvider(EnvProviderConfig{Prefix: "LISTTEST_"})
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	peers, ok := data["peers"].([]interface{})
+	if !ok || len(peers) != 2 {
+		t.Fatalf("peers = %#v, want list of 2", data["peers"])
+	}
+	first := peers[0].(map[string]interface{})
+	if first["host"] != "hss1.example" || first["port"] != int64(3868) {
+		t.Errorf("peers[0] = %v, want host hss1.example port 3868", first)
+	}
+
+	if _, ok := data["sparse"].(map[string]interface{}); !ok {
+		t.Errorf("non-contiguous indexes should stay a map, got %T", data["sparse"])
+	}
+
+	type peer struct {
+		Host string
+		Port int
+	}
+	var cfg struct {
+		Peers []peer
+	}
+	if err := UnmarshalEnv(data, &cfg); err != nil {
+		t.Fatalf("UnmarshalEnv() error = %v", err)
+	}
+	if len(cfg.Peers) != 2 || cfg.Peers[0].Port != 3868 || cfg.Peers[1].Host != "hss2.example" {
+		t.Errorf("UnmarshalEnv() peers = %+v", cfg.Peers)
+	}
+}
+
+func TestEnvProvider_Load_CSVLists(t *testing.T) {
+	os.Setenv("CSVTEST_ALLOWED_PORTS", "3868, 3869,3870")
+	os.Setenv("CSVTEST_NAME", "eir")
+	defer func() {
+		os.Unsetenv("CSVTEST_ALLOWED_PORTS")
+		os.Unsetenv("CSVTEST_NAME")
+	}()
+
+	provider := NewEnvProvider(EnvProviderConfig{Prefix: "CSVTEST_", ListSeparator: ","})
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	allowed := data["allowed"].(map[string]interface{})
+	ports, ok := allowed["ports"].([]interface{})
+	if !ok || len(ports) != 3 {
+		t.Fatalf("allowed.ports = %#v, want list of 3", allowed["ports"])
+	}
+	if ports[1] != int64(3869) {
+		t.Errorf("allowed.ports[1] = %v (%T), want int64 3869", ports[1], ports[1])
+	}
+	if data["name"] != "eir" {
+		t.Errorf("name = %v, want eir", data["name"])
+	}
+}
+
+func TestEnvProvider_ParseValue_KeepsDigitStrings(t *testing.T) {
+	provider := NewEnvProvider(EnvProviderConfig{})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"0123", "0123"},
+		{"+8491234567", "+8491234567"},
+		{"99999999999999999999", "99999999999999999999"},
+		{"nan", "nan"},
+		{"10", int64(10)},
+		{"0.5", 0.5},
+		{"0e3", 0.0},
+		{"-7", int64(-7)},
+	}
+
+	for _, tt := range tests {
+		if result := provider.parseValue(tt.input); result != tt.expected {
+			t.Errorf("parseValue(%q) = %v (%T), want %v (%T)", tt.input, result, result, tt.expected, tt.expected)
+		}
+	}
+}
+
+func TestEnvProvider_Load_TypeHints(t *testing.T) {
+	os.Setenv("HINTTEST_SCCP_GT", "841234567")
+	os.Setenv("HINTTEST_SCCP_SSN", "8")
+	os.Setenv("HINTTEST_PEER_A_ID", "12")
+	defer func() {
+		os.Unsetenv("HINTTEST_SCCP_GT")
+		os.Unsetenv("HINTTEST_SCCP_SSN")
+		os.Unsetenv("HINTTEST_PEER_A_ID")
+	}()
+
+	provider := NewEnvProvider(EnvProviderConfig{
+		Prefix: "HINTTEST_",
+		TypeHints: map[string]ValueType{
+			"sccp.gt":   ValueString,
+			"peer.*.id": ValueFloat,
+		},
+	})
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	sccp := data["sccp"].(map[string]interface{})
+	if sccp["gt"] != "841234567" {
+		t.Errorf("sccp.gt = %v (%T), want string", sccp["gt"], sccp["gt"])
+	}
+	if sccp["ssn"] != int64(8) {
+		t.Errorf("sccp.ssn = %v (%T), want int64 8", sccp["ssn"], sccp["ssn"])
+	}
+	peer := data["peer"].(map[string]interface{})["a"].(map[string]interface{})
+	if peer["id"] != float64(12) {
+		t.Errorf("peer.a.id = %v (%T), want float64 12", peer["id"], peer["id"])
+	}
+
+	os.Setenv("HINTTEST_SCCP_SSN", "eight")
+	provider = NewEnvProvider(EnvProviderConfig{
+		Prefix:    "HINTTEST_",
+		TypeHints: map[string]ValueType{"sccp.ssn": ValueInt},
+	})
+	if _, err := provider.Load(context.Background()); err == nil {
+		t.Error("Expected an error for a value that doesn't match its type hint")
+	}
+}
+
+func TestEnvProvider_Load_RawStrings(t *testing.T) {
+	os.Setenv("RAWTEST_PORT", "3868")
+	os.Setenv("RAWTEST_DEBUG", "true")
+	defer func() {
+		os.Unsetenv("RAWTEST_PORT")
+		os.Unsetenv("RAWTEST_DEBUG")
+	}()
+
+	provider := NewEnvProvider(EnvProviderConfig{
+		Prefix:     "RAWTEST_",
+		RawStrings: true,
+		TypeHints:  map[string]ValueType{"debug": ValueBool},
+	})
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if data["port"] != "3868" {
+		t.Errorf("port = %v (%T), want string", data["port"], data["port"])
+	}
+	if data["debug"] != true {
+		t.Errorf("debug = %v (%T), want bool", data["debug"], data["debug"])
+	}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// Resolver looks up the reference of a fallback expression alternative, e.g. the
+// Consul key in "${CONSUL:eir/db_host}"; ok is false when the reference is absent
+type Resolver interface {
+	Resolve(ctx context.Context, ref string) (value string, ok bool, err error)
+}
+
+// ResolverFunc adapts a function to Resolver
+type ResolverFunc func(ctx context.Context, ref string) (string, bool, error)
+
+// Resolve calls f
+func (f ResolverFunc) Resolve(ctx context.Context, ref string) (string, bool, error) {
+	return f(ctx, ref)
+}
+
+// EnvResolver resolves "${ENV:NAME}" from environment variables; empty variables are absent
+// It is always available as the ENV scheme
+var EnvResolver = ResolverFunc(func(ctx context.Context, name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	return value, ok && value != "", nil
+})
+
+// ConsulResolver resolves references as raw Consul KV keys ("${CONSUL:eir/db_host}")
+type ConsulResolver struct {
+	client *api.Client
+	config RemoteProviderConfig
+}
+
+// NewConsulResolver creates a resolver reading keys from the Consul agent of cfg
+// (Endpoints, TLSConfig and RetryConfig are used; Key and Prefix are ignored)
+func NewConsulResolver(cfg RemoteProviderConfig) (*ConsulResolver, error) {
+	provider, err := NewConsulProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsulResolver{client: provider.client, config: cfg}, nil
+}
+
+// Resolve reads key from Consul
+func (r *ConsulResolver) Resolve(ctx context.Context, key string) (string, bool, error) {
+	var pair *api.KVPair
+	err := retryWithBackoff(r.config.RetryConfig, func() error {
+		var err error
+		pair, _, err = r.client.KV().Get(key, (&api.QueryOptions{}).WithContext(ctx))
+		return err
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if pair == nil {
+		return "", false, nil
+	}
+	return string(pair.Value), true, nil
+}
+
+// FallbackError reports a config value whose fallback expression couldn't be evaluated
+type FallbackError struct {
+	Key        string // Dot-separated key path
+	Expression string
+	Err        error
+}
+
+func (e *FallbackError) Error() string {
+	return fmt.Sprintf("config key '%s': fallback %q: %v", e.Key, e.Expression, e.Err)
+}
+
+func (e *FallbackError) Unwrap() error {
+	return e.Err
+}
+
+// IsFallbackExpression reports whether a config value is a fallback expression
+func IsFallbackExpression(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}")
+}
+
+// resolveFallbacks replaces every string value of data holding a fallback expression
+// with its first resolvable alternative, e.g.
+//
+//	"${CONSUL:eir/db_host | ${ENV:DB_HOST} | localhost}"
+//
+// Alternatives are tried left to right: "SCHEME:ref" asks the scheme's Resolver,
+// a nested "${...}" is evaluated recursively and anything else is a literal.
+// Resolved values are typed like environment variables (see parseScalar)
+func (m *Manager) resolveFallbacks(ctx context.Context, data map[string]interface{}) error {
+	return m.resolveFallbacksIn(ctx, data, "")
+}
+
+// resolveFallbacksIn resolves the values of data, whose keys are under prefix
+func (m *Manager) resolveFallbacksIn(ctx context.Context, data map[string]interface{}, prefix string) error {
+	for k, v := range data {
+		key := k
+		if prefix != "" {
+			key = prefix + KeySeparator + k
+		}
+
+		switch value := v.(type) {
+		case map[string]interface{}:
+			if err := m.resolveFallbacksIn(ctx, value, key); err != nil {
+				return err
+			}
+		case string:
+			if !IsFallbackExpression(value) {
+				continue
+			}
+			resolved, err := m.evalFallback(ctx, strings.TrimSpace(value))
+			if err != nil {
+				return &FallbackError{Key: key, Expression: value, Err: err}
+			}
+			data[k] = parseScalar(resolved)
+		}
+	}
+	return nil
+}
+
+// evalFallback evaluates a "${...}" expression
+func (m *Manager) evalFallback(ctx context.Context, expr string) (string, error) {
+	alternatives, err := splitAlternatives(expr[2 : len(expr)-1])
+	if err != nil {
+		return "", err
+	}
+
+	for _, alt := range alternatives {
+		if IsFallbackExpression(alt) {
+			value, err := m.evalFallback(ctx, alt)
+			if err == errNoAlternative {
+				continue
+			}
+			return value, err
+		}
+
+		scheme, ref, ok := strings.Cut(alt, ":")
+		if !ok || !isSchemeName(scheme) {
+			return alt, nil // Literal
+		}
+		resolver := m.resolver(scheme)
+		if resolver == nil {
+			return "", fmt.Errorf("no resolver for scheme %s", scheme)
+		}
+		value, found, err := resolver.Resolve(ctx, strings.TrimSpace(ref))
+		if err != nil {
+			return "", fmt.Errorf("%s:%s: %w", scheme, ref, err)
+		}
+		if found {
+			return value, nil
+		}
+	}
+	return "", errNoAlternative
+}
+
+// errNoAlternative is returned when no alternative of an expression resolved
+var errNoAlternative = errors.New("no alternative resolved")
+
+// resolver returns the resolver of scheme, nil if unknown
+func (m *Manager) resolver(scheme string) Resolver {
+	if r, ok := m.resolvers[scheme]; ok {
+		return r
+	}
+	if scheme == "ENV" {
+		return EnvResolver
+	}
+	return nil
+}
+
+// splitAlternatives splits the body of an expression on "|" outside nested "${...}",
+// trimming each alternative
+func splitAlternatives(body string) ([]string, error) {
+	var alternatives []string
+	depth, start := 0, 0
+	for i := 0; i < len(body); i++ {
+		switch {
+		case strings.HasPrefix(body[i:], "${"):
+			depth++
+			i++
+		case body[i] == '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unbalanced '}' at offset %d", i)
+			}
+			depth--
+		case body[i] == '|' && depth == 0:
+			alternatives = append(alternatives, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unterminated '${'")
+	}
+	return append(alternatives, strings.TrimSpace(body[start:])), nil
+}
+
+// isSchemeName reports whether s is an upper case resolver scheme such as ENV or CONSUL
+func isSchemeName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestManager_FallbackExpressions(t *testing.T) {
+	consul := map[string]string{"eir/db_port": "5433"}
+	consulResolver := ResolverFunc(func(ctx context.Context, key string) (string, bool, error) {
+		value, ok := consul[key]
+		return value, ok, nil
+	})
+	t.Setenv("FALLBACK_TEST_DB_HOST", "db.site-b")
+
+	manager := NewManager(ManagerConfig{
+		Providers: []Provider{NewMockProvider("file", map[string]interface{}{
+			"database": map[string]interface{}{
+				"host":     "${CONSUL:eir/db_host | ${ENV:FALLBACK_TEST_DB_HOST} | localhost}",
+				"port":     "${CONSUL:eir/db_port | 5432}",
+				"user":     "${CONSUL:eir/db_user | ${ENV:FALLBACK_TEST_DB_USER} | eir}",
+				"endpoint": "localhost:5432",
+			},
+		})},
+		Resolvers: map[string]Resolver{"CONSUL": consulResolver},
+	})
+
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if host, _ := manager.GetString("database.host"); host != "db.site-b" {
+		t.Errorf("Expected the ENV alternative, got %q", host)
+	}
+	if port, _ := manager.GetInt("database.port"); port != 5433 {
+		t.Errorf("Expected the Consul alternative typed as an int, got %v", port)
+	}
+	if user, _ := manager.GetString("database.user"); user != "eir" {
+		t.Errorf("Expected the literal alternative, got %q", user)
+	}
+	if endpoint, _ := manager.GetString("database.endpoint"); endpoint != "localhost:5432" {
+		t.Errorf("Expected plain values untouched, got %q", endpoint)
+	}
+}
+
+func TestManager_FallbackExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"unresolved", "${ENV:FALLBACK_TEST_UNSET}"},
+		{"unknown scheme", "${VAULT:secret/db | x}"},
+		{"unterminated", "${ENV:A | ${ENV:B}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(ManagerConfig{
+				Providers: []Provider{NewMockProvider("file", map[string]interface{}{"db": map[string]interface{}{"host": tt.value}})},
+			})
+			_, err := manager.Load(context.Background())
+			var fallbackErr *FallbackError
+			if !errors.As(err, &fallbackErr) || fallbackErr.Key != "db.host" {
+				t.Errorf("Expected a FallbackError for db.host, got %v", err)
+			}
+		})
+	}
+}
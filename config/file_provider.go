@@ -1,11 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -71,32 +73,133 @@ func NewFileProvider(cfg FileProviderConfig) (*FileProvider, error) {
 	}, nil
 }
 
-// Load reads and parses the configuration file
+// IncludeKey is the config key listing files (or glob patterns) to compose into a file
+// Included paths are relative to the including file; included files are merged
+// in listed order (glob matches sorted) and the including file overrides them
+const IncludeKey = "include"
+
+// Load reads and parses the configuration file, resolving include directives
 func (f *FileProvider) Load(ctx context.Context) (map[string]interface{}, error) {
-	data, err := os.ReadFile(f.path)
-	if err != nil {
-		if !f.config.Required && os.IsNotExist(err) {
+	if !f.config.Required {
+		if _, err := os.Stat(f.path); os.IsNotExist(err) {
 			return make(map[string]interface{}), nil // Return empty config
 		}
-		return nil, fmt.Errorf("failed to read file %s: %w", f.path, err)
+	}
+
+	return f.loadFile(f.path, f.format, nil)
+}
+
+// loadFile parses a single file and merges its includes beneath it
+// stack holds the absolute paths of the files currently being loaded for cycle detection
+func (f *FileProvider) loadFile(path string, format FileFormat, stack []string) (map[string]interface{}, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	for _, p := range stack {
+		if p == absPath {
+			return nil, fmt.Errorf("include cycle detected: %s -> %s", strings.Join(stack, " -> "), absPath)
+		}
+	}
+	stack = append(stack, absPath)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
 	}
 
 	var result map[string]interface{}
 
-	switch f.format {
+	switch format {
 	case FormatYAML:
 		if err := yaml.Unmarshal(data, &result); err != nil {
 			return nil, fmt.Errorf("failed to parse YAML: %w", err)
 		}
 	case FormatJSON:
-		if err := json.Unmarshal(data, &result); err != nil {
+		if err := decodeJSON(data, &result); err != nil {
 			return nil, fmt.Errorf("failed to parse JSON: %w", err)
 		}
 	default:
-		return nil, fmt.Errorf("unsupported format: %s", f.format)
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if result == nil {
+		result = make(map[string]interface{})
 	}
 
-	return result, nil
+	includes, err := includePaths(result[IncludeKey], filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in %s: %w", IncludeKey, path, err)
+	}
+	delete(result, IncludeKey)
+
+	if len(includes) == 0 {
+		return result, nil
+	}
+
+	// Merge includes in order, then let the including file override them
+	composed := make(map[string]interface{})
+	for _, include := range includes {
+		includeFormat := format
+		switch strings.ToLower(filepath.Ext(include)) {
+		case ".yaml", ".yml":
+			includeFormat = FormatYAML
+		case ".json":
+			includeFormat = FormatJSON
+		}
+
+		included, err := f.loadFile(include, includeFormat, stack)
+		if err != nil {
+			return nil, err
+		}
+		merge(composed, included)
+	}
+	merge(composed, result)
+
+	return composed, nil
+}
+
+// includePaths expands an include value (string or list of strings) into file paths
+// Relative entries are resolved against dir; glob patterns expand to their sorted matches
+func includePaths(value interface{}, dir string) ([]string, error) {
+	var entries []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		entries = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("entries must be strings, got %T", item)
+			}
+			entries = append(entries, s)
+		}
+	default:
+		return nil, fmt.Errorf("must be a string or list of strings, got %T", value)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !filepath.IsAbs(entry) {
+			entry = filepath.Join(dir, entry)
+		}
+
+		if !strings.ContainsAny(entry, "*?[") {
+			paths = append(paths, entry)
+			continue
+		}
+
+		matches, err := filepath.Glob(entry)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern %s: %w", entry, err)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+
+	return paths, nil
 }
 
 // Name returns the provider name
@@ -208,7 +311,7 @@ func (fw *FileWatcher) Watch(ctx context.Context, callback func(map[string]inter
 						if ext == ".yaml" || ext == ".yml" {
 							yaml.Unmarshal(data, &config)
 						} else if ext == ".json" {
-							json.Unmarshal(data, &config)
+							decodeJSON(data, &config)
 						}
 
 						if config != nil {
@@ -234,3 +337,54 @@ func (fw *FileWatcher) Stop() error {
 	close(fw.stopCh)
 	return fw.watcher.Close()
 }
+
+// maxExactFloat is the largest magnitude below which every integer is exact in a float64
+const maxExactFloat = 1 << 53
+
+// decodeJSON decodes a JSON object without losing precision on large integers:
+// numbers become float64 as with encoding/json, except integers a float64 can't hold
+// exactly, which become int64, or stay json.Number beyond the int64 range
+func decodeJSON(data []byte, result *map[string]interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(result); err != nil {
+		return err
+	}
+	if *result != nil {
+		normalizeNumbers(*result)
+	}
+	return nil
+}
+
+// normalizeNumbers converts json.Number values in v in place
+func normalizeNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		return normalizeNumber(val)
+	}
+	return v
+}
+
+// normalizeNumber returns n as float64, or int64/json.Number for integers too large for one
+func normalizeNumber(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		if i > maxExactFloat || i < -maxExactFloat {
+			return i
+		}
+		return float64(i)
+	} else if !strings.ContainsAny(n.String(), ".eE") {
+		return n
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -107,6 +109,40 @@ func TestFileProvider_Load_JSON(t *testing.T) {
 	}
 }
 
+func TestFileProvider_Load_JSONLargeIntegers(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "config.json")
+
+	jsonContent := `{"ids": {"small": 42, "session": 9007199254740993, "huge": 123456789012345678901234}, "ratio": 0.25}`
+	if err := os.WriteFile(jsonFile, []byte(jsonContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := NewFileProvider(FileProviderConfig{Path: jsonFile, Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("NewFileProvider() error = %v", err)
+	}
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	ids := data["ids"].(map[string]interface{})
+	if ids["small"] != float64(42) {
+		t.Errorf("ids.small = %v (%T), want float64 42", ids["small"], ids["small"])
+	}
+	if ids["session"] != int64(9007199254740993) {
+		t.Errorf("ids.session = %v (%T), want int64 9007199254740993", ids["session"], ids["session"])
+	}
+	if ids["huge"] != json.Number("123456789012345678901234") {
+		t.Errorf("ids.huge = %v (%T), want json.Number", ids["huge"], ids["huge"])
+	}
+	if data["ratio"] != 0.25 {
+		t.Errorf("ratio = %v, want 0.25", data["ratio"])
+	}
+}
+
 func TestFileProvider_AutoDetectFormat(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -338,3 +374,80 @@ func TestResolveFilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestFileProvider_Load_Include(t *testing.T) {
+	tmpDir := t.TempDir()
+	ifaceDir := filepath.Join(tmpDir, "interfaces")
+	if err := os.MkdirAll(ifaceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"main.yaml": `
+include:
+  - base.json
+  - interfaces/*.yaml
+server:
+  port: 9090
+`,
+		"base.json":           `{"server": {"host": "0.0.0.0", "port": 8080}, "log_level": "info"}`,
+		"interfaces/s13.yaml": "diameter:\n  s13:\n    enabled: true\n",
+		"interfaces/n5g.yaml": "http:\n  n5g:\n    enabled: false\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider, err := NewFileProvider(FileProviderConfig{Path: filepath.Join(tmpDir, "main.yaml")})
+	if err != nil {
+		t.Fatalf("NewFileProvider() error = %v", err)
+	}
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if _, ok := data[IncludeKey]; ok {
+		t.Error("include key should be removed from the result")
+	}
+
+	server := data["server"].(map[string]interface{})
+	if server["port"] != 9090 {
+		t.Errorf("server.port = %v, want 9090 (including file overrides)", server["port"])
+	}
+	if server["host"] != "0.0.0.0" {
+		t.Errorf("server.host = %v, want 0.0.0.0 (from include)", server["host"])
+	}
+	if data["log_level"] != "info" {
+		t.Errorf("log_level = %v, want info", data["log_level"])
+	}
+	if _, ok := data["diameter"]; !ok {
+		t.Error("diameter section from glob include not found")
+	}
+	if _, ok := data["http"]; !ok {
+		t.Error("http section from glob include not found")
+	}
+}
+
+func TestFileProvider_Load_IncludeCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.yaml"), []byte("include: b.yaml\na: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.yaml"), []byte("include: [a.yaml]\nb: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := NewFileProvider(FileProviderConfig{Path: filepath.Join(tmpDir, "a.yaml")})
+	if err != nil {
+		t.Fatalf("NewFileProvider() error = %v", err)
+	}
+
+	if _, err := provider.Load(context.Background()); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("Load() error = %v, want include cycle error", err)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ImmutableKeyError describes a reload that tried to change an immutable key
+// Old is nil for a key the reload added and New is nil for a key it removed
+type ImmutableKeyError struct {
+	Key     string
+	Pattern string // ImmutableKeys pattern the key matched
+	Old     interface{}
+	New     interface{}
+}
+
+func (e ImmutableKeyError) Error() string {
+	return fmt.Sprintf("immutable key '%s' cannot change after startup: %v -> %v ignored", e.Key, e.Old, e.New)
+}
+
+// ImmutableKeyErrors is a collection of rejected immutable key changes
+type ImmutableKeyErrors []ImmutableKeyError
+
+func (e ImmutableKeyErrors) Error() string {
+	if len(e) == 0 {
+		return "no immutable key changes"
+	}
+
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ImmutableKeyViolations returns the immutable key changes rejected by the last reload
+func (m *Manager) ImmutableKeyViolations() ImmutableKeyErrors {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.immutableViolations
+}
+
+// freezeImmutable reverts changes to immutable keys in new, so a reload applies
+// everything else, and records the rejected changes (nothing is frozen before
+// the first successful load)
+func (m *Manager) freezeImmutable(old, new map[string]interface{}) {
+	if old == nil || len(m.immutableKeys) == 0 {
+		return
+	}
+
+	before, after := Flatten(old), Flatten(new)
+	var violations ImmutableKeyErrors
+	check := func(key string) {
+		pattern, ok := m.immutablePattern(key)
+		if !ok {
+			return
+		}
+		prev, hadPrev := before[key]
+		value, hasValue := after[key]
+		if hadPrev == hasValue && reflect.DeepEqual(prev, value) {
+			return
+		}
+
+		violation := ImmutableKeyError{Key: key, Pattern: pattern, Old: prev, New: value}
+		if m.IsSecretKey(key) {
+			violation.Old, violation.New = redact(prev), redact(value)
+		}
+		violations = append(violations, violation)
+
+		path := strings.Split(key, KeySeparator)
+		copyPath(new, path)
+		if hadPrev {
+			setPath(new, path, prev)
+		} else {
+			deletePath(new, path)
+		}
+	}
+
+	for key := range after {
+		check(key)
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			check(key)
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Key < violations[j].Key })
+
+	m.mu.Lock()
+	m.immutableViolations = violations
+	m.mu.Unlock()
+	if len(violations) > 0 && m.onImmutableViolation != nil {
+		m.onImmutableViolation(violations)
+	}
+}
+
+// immutablePattern returns the ImmutableKeys pattern matching key
+func (m *Manager) immutablePattern(key string) (string, bool) {
+	for _, pattern := range m.immutableKeys {
+		if matchKeyPattern(pattern, key) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// copyPath replaces the maps along path with shallow copies, so reverting a key
+// doesn't modify maps shared with a provider's data
+func copyPath(m map[string]interface{}, path []string) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := m[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		copied := make(map[string]interface{}, len(nested))
+		for k, v := range nested {
+			copied[k] = v
+		}
+		m[key] = copied
+		m = copied
+	}
+}
+
+// deletePath removes the value at path from m, along with maps it leaves empty
+func deletePath(m map[string]interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+
+	nested, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deletePath(nested, path[1:])
+	if len(nested) == 0 {
+		delete(m, path[0])
+	}
+}
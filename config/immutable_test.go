@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestManager_ImmutableKeys(t *testing.T) {
+	provider := NewMockProvider("file", map[string]interface{}{
+		"node":   map[string]interface{}{"id": "eir-1"},
+		"server": map[string]interface{}{"port": 3868, "timeout": "5s"},
+	})
+
+	var reported ImmutableKeyErrors
+	manager := NewManager(ManagerConfig{
+		Providers:            []Provider{provider},
+		ImmutableKeys:        []string{"node", "server.port"},
+		OnImmutableKeyChange: func(errs ImmutableKeyErrors) { reported = errs },
+	})
+
+	ctx := context.Background()
+	if _, err := manager.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	provider.data = map[string]interface{}{
+		"node":   map[string]interface{}{"id": "eir-2", "zone": "b"},
+		"server": map[string]interface{}{"port": 3869, "timeout": "10s"},
+	}
+	config, err := manager.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if port, _ := manager.GetInt("server.port"); port != 3868 {
+		t.Errorf("server.port = %d, want startup value 3868", port)
+	}
+	if id, _ := manager.GetString("node.id"); id != "eir-1" {
+		t.Errorf("node.id = %q, want startup value eir-1", id)
+	}
+	if _, ok := manager.Get("node.zone"); ok {
+		t.Error("Expected added immutable key node.zone to be rejected")
+	}
+	if timeout, _ := manager.GetString("server.timeout"); timeout != "10s" {
+		t.Errorf("server.timeout = %q, want reloaded value 10s", timeout)
+	}
+	if config["server"].(map[string]interface{})["port"] != 3868 {
+		t.Error("Load() should return the config with immutable keys reverted")
+	}
+	if provider.data["server"].(map[string]interface{})["port"] != 3869 {
+		t.Error("Reverting immutable keys modified the provider's data")
+	}
+
+	violations := manager.ImmutableKeyViolations()
+	if len(violations) != 3 || len(reported) != 3 {
+		t.Fatalf("Expected 3 violations, got %v (reported %v)", violations, reported)
+	}
+	if v := violations[2]; v.Key != "server.port" || v.Old != 3868 || v.New != 3869 {
+		t.Errorf("violation = %+v", v)
+	}
+	if msg := violations.Error(); !strings.Contains(msg, "immutable key 'node.id'") {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	// A reload without immutable changes clears the violations
+	provider.data = map[string]interface{}{
+		"node":   map[string]interface{}{"id": "eir-1"},
+		"server": map[string]interface{}{"port": 3868, "timeout": "1s"},
+	}
+	if _, err := manager.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if violations := manager.ImmutableKeyViolations(); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+}
+
+func TestManager_ImmutableKeys_Watch(t *testing.T) {
+	watcher := &mockWatcher{}
+	manager := NewManager(ManagerConfig{
+		Providers:     []Provider{NewMockProvider("file", map[string]interface{}{"db_password": "a", "port": 1})},
+		Watcher:       watcher,
+		ImmutableKeys: []string{"db_password"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := manager.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := manager.Watch(ctx, func(data map[string]interface{}) error {
+		got = data
+		return nil
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	watcher.emit(map[string]interface{}{"db_password": "b", "port": 2})
+
+	if got["db_password"] != "a" || got["port"] != 2 {
+		t.Errorf("reloaded config = %v, want db_password kept and port applied", got)
+	}
+	violations := manager.ImmutableKeyViolations()
+	if len(violations) != 1 || violations[0].Old != RedactedValue {
+		t.Errorf("Expected one redacted violation, got %v", violations)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeySeparator separates nesting levels in flattened config keys (e.g., "server.port")
+const KeySeparator = "."
+
+// Flatten converts a nested config map into a single-level map with dot-separated keys
+// Example: {"server": {"port": 8080}} -> {"server.port": 8080}
+// Slices and empty maps are kept as leaf values
+func Flatten(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	flattenInto(result, "", m)
+	return result
+}
+
+// flattenInto recursively copies leaves of m into result under prefix
+func flattenInto(result map[string]interface{}, prefix string, m map[string]interface{}) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + KeySeparator + k
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(result, key, nested)
+			continue
+		}
+		result[key] = v
+	}
+}
+
+// Expand converts a flattened map with dot-separated keys back into a nested map
+// It is the inverse of Flatten; when a key is both a leaf and a parent
+// (e.g., "server" and "server.port"), the nested value wins
+func Expand(flat map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // Parents sort before children, so children win deterministically
+
+	result := make(map[string]interface{})
+	for _, k := range keys {
+		setPath(result, strings.Split(k, KeySeparator), flat[k])
+	}
+	return result
+}
+
+// setPath sets value at path in m, creating (or replacing non-map values with) intermediate maps
+func setPath(m map[string]interface{}, path []string, value interface{}) {
+	if len(path) == 0 {
+		return
+	}
+
+	for _, key := range path[:len(path)-1] {
+		nested, ok := m[key].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			m[key] = nested
+		}
+		m = nested
+	}
+
+	last := path[len(path)-1]
+	if existing, ok := m[last].(map[string]interface{}); ok {
+		if _, isMap := value.(map[string]interface{}); !isMap && len(existing) > 0 {
+			return // Keep nested values over a conflicting leaf
+		}
+	}
+	m[last] = value
+}
+
+// lookupPath returns the value at a dot-separated key in a nested map
+func lookupPath(m map[string]interface{}, key string) (interface{}, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	var current interface{} = m
+	for _, part := range strings.Split(key, KeySeparator) {
+		nested, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = nested[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+)
+
+func TestFlatten(t *testing.T) {
+	nested := map[string]interface{}{
+		"server": map[string]interface{}{
+			"host": "localhost",
+			"port": 8080,
+			"tls":  map[string]interface{}{},
+		},
+		"peers":   []interface{}{"a", "b"},
+		"enabled": true,
+	}
+
+	got := Flatten(nested)
+
+	want := map[string]interface{}{
+		"server.host": "localhost",
+		"server.port": 8080,
+		"enabled":     true,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Flatten()[%q] = %v, want %v", k, got[k], v)
+		}
+	}
+	if _, ok := got["peers"].([]interface{}); !ok {
+		t.Errorf("Flatten() should keep slices as leaves, got %T", got["peers"])
+	}
+	if _, ok := got["server.tls"].(map[string]interface{}); !ok {
+		t.Errorf("Flatten() should keep empty maps as leaves, got %T", got["server.tls"])
+	}
+	if len(got) != 5 {
+		t.Errorf("Flatten() returned %d keys, want 5", len(got))
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name string
+		flat map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "nested keys",
+			flat: map[string]interface{}{
+				"server.host": "localhost",
+				"server.port": 8080,
+				"debug":       true,
+			},
+			want: map[string]interface{}{
+				"server": map[string]interface{}{
+					"host": "localhost",
+					"port": 8080,
+				},
+				"debug": true,
+			},
+		},
+		{
+			name: "nested value wins over conflicting leaf",
+			flat: map[string]interface{}{
+				"server":      "ignored",
+				"server.port": 8080,
+			},
+			want: map[string]interface{}{
+				"server": map[string]interface{}{
+					"port": 8080,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertMapEqual(t, Expand(tt.flat), tt.want)
+		})
+	}
+}
+
+func TestFlattenExpand_RoundTrip(t *testing.T) {
+	nested := map[string]interface{}{
+		"diameter": map[string]interface{}{
+			"s13": map[string]interface{}{
+				"enabled": true,
+				"port":    3868,
+			},
+		},
+		"log_level": "info",
+	}
+
+	assertMapEqual(t, Expand(Flatten(nested)), nested)
+}
+
+func TestLookupPath(t *testing.T) {
+	m := map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080},
+		"name":   "eir",
+	}
+
+	if v, ok := lookupPath(m, "server.port"); !ok || v != 8080 {
+		t.Errorf("lookupPath(server.port) = %v, %v; want 8080, true", v, ok)
+	}
+	if _, ok := lookupPath(m, "name.first"); ok {
+		t.Error("lookupPath through a leaf should fail")
+	}
+	if _, ok := lookupPath(m, "missing"); ok {
+		t.Error("lookupPath of a missing key should fail")
+	}
+}
+
+func TestConsulPairsToMap(t *testing.T) {
+	pairs := api.KVPairs{
+		{Key: "eir/", Value: nil},
+		{Key: "eir/server/", Value: nil},
+		{Key: "eir/server/port", Value: []byte("8080")},
+		{Key: "eir/server/host", Value: []byte("0.0.0.0")},
+		{Key: "eir/debug", Value: []byte("true")},
+	}
+
+	got := consulPairsToMap("eir/", pairs)
+
+	want := map[string]interface{}{
+		"server": map[string]interface{}{
+			"port": float64(8080),
+			"host": "0.0.0.0",
+		},
+		"debug": true,
+	}
+	assertMapEqual(t, got, want)
+}
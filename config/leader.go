@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// LeaderProvider reports whether this instance currently holds cluster leadership
+// Implementations typically wrap a Consul session lock or a Kubernetes Lease
+type LeaderProvider interface {
+	IsLeader(ctx context.Context) (bool, error)
+}
+
+// LeaderFunc adapts a function to the LeaderProvider interface
+// Use it to plug in an existing election (e.g., a client-go leaderelection callback)
+type LeaderFunc func(ctx context.Context) (bool, error)
+
+// IsLeader calls f(ctx)
+func (f LeaderFunc) IsLeader(ctx context.Context) (bool, error) {
+	return f(ctx)
+}
+
+// ReloadHook is invoked with the new configuration after a successful reload
+type ReloadHook struct {
+	// Name identifies the hook in errors
+	Name string
+
+	// LeaderOnly restricts the hook to the cluster leader (e.g., DB migrations)
+	// Leader-only hooks are skipped when no LeaderProvider is configured or leadership
+	// cannot be determined
+	LeaderOnly bool
+
+	// Apply applies the configuration
+	Apply func(ctx context.Context, config map[string]interface{}) error
+}
+
+// ReloadHookError reports the hooks that failed during a reload
+type ReloadHookError struct {
+	Failed map[string]error
+}
+
+func (e *ReloadHookError) Error() string {
+	var msgs []string
+	for name, err := range e.Failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", name, err))
+	}
+	return "reload hooks failed: " + strings.Join(msgs, "; ")
+}
+
+// AddReloadHook registers a hook to run after each successful reload
+func (m *Manager) AddReloadHook(hook ReloadHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// RunReloadHooks runs the registered hooks against config, consulting the
+// LeaderProvider once for all leader-only hooks
+// Every hook runs even if an earlier one fails; failures are returned as *ReloadHookError
+func (m *Manager) RunReloadHooks(ctx context.Context, config map[string]interface{}) error {
+	m.mu.RLock()
+	hooks := append([]ReloadHook(nil), m.hooks...)
+	m.mu.RUnlock()
+
+	leader, leaderErr := m.isLeader(ctx, hooks)
+
+	failed := make(map[string]error)
+	for _, hook := range hooks {
+		if hook.LeaderOnly && !leader {
+			if leaderErr != nil {
+				failed[hook.Name] = fmt.Errorf("skipped, leadership unknown: %w", leaderErr)
+			}
+			continue
+		}
+		if err := hook.Apply(ctx, config); err != nil {
+			failed[hook.Name] = err
+		}
+	}
+
+	if len(failed) > 0 {
+		return &ReloadHookError{Failed: failed}
+	}
+	return nil
+}
+
+// isLeader checks leadership only when a leader-only hook needs it
+func (m *Manager) isLeader(ctx context.Context, hooks []ReloadHook) (bool, error) {
+	if m.leader == nil {
+		return false, nil
+	}
+
+	for _, hook := range hooks {
+		if hook.LeaderOnly {
+			return m.leader.IsLeader(ctx)
+		}
+	}
+	return false, nil
+}
+
+// ConsulSessionLeader reports leadership by checking that a Consul KV lock key
+// is held by the given session (as acquired with api.Lock or KV.Acquire)
+type ConsulSessionLeader struct {
+	client  *api.Client
+	key     string
+	session string
+}
+
+// NewConsulSessionLeader creates a leader provider for a Consul session lock
+func NewConsulSessionLeader(client *api.Client, key, session string) *ConsulSessionLeader {
+	return &ConsulSessionLeader{
+		client:  client,
+		key:     key,
+		session: session,
+	}
+}
+
+// IsLeader reports whether the lock key is currently held by this session
+func (c *ConsulSessionLeader) IsLeader(ctx context.Context) (bool, error) {
+	opts := (&api.QueryOptions{RequireConsistent: true}).WithContext(ctx)
+	pair, _, err := c.client.KV().Get(c.key, opts)
+	if err != nil {
+		return false, fmt.Errorf("failed to read lock key %s: %w", c.key, err)
+	}
+
+	return pair != nil && pair.Session != "" && pair.Session == c.session, nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestManager_RunReloadHooks(t *testing.T) {
+	var isLeader bool
+	var leaderErr error
+	var ran []string
+
+	hook := func(name string) func(context.Context, map[string]interface{}) error {
+		return func(context.Context, map[string]interface{}) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+
+	manager := NewManager(ManagerConfig{
+		LeaderProvider: LeaderFunc(func(context.Context) (bool, error) {
+			return isLeader, leaderErr
+		}),
+		ReloadHooks: []ReloadHook{
+			{Name: "cache", Apply: hook("cache")},
+			{Name: "migrate", LeaderOnly: true, Apply: hook("migrate")},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		isLeader  bool
+		leaderErr error
+		wantRan   []string
+		wantErr   bool
+	}{
+		{"follower", false, nil, []string{"cache"}, false},
+		{"leader", true, nil, []string{"cache", "migrate"}, false},
+		{"leadership unknown", false, errors.New("session expired"), []string{"cache"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isLeader, leaderErr, ran = tt.isLeader, tt.leaderErr, nil
+
+			err := manager.RunReloadHooks(context.Background(), map[string]interface{}{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RunReloadHooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(ran) != len(tt.wantRan) {
+				t.Fatalf("ran = %v, want %v", ran, tt.wantRan)
+			}
+			for i := range ran {
+				if ran[i] != tt.wantRan[i] {
+					t.Errorf("ran = %v, want %v", ran, tt.wantRan)
+				}
+			}
+		})
+	}
+}
+
+func TestManager_Watch_RunsReloadHooks(t *testing.T) {
+	watcher := &mockWatcher{}
+	manager := NewManager(ManagerConfig{Watcher: watcher})
+
+	var got map[string]interface{}
+	manager.AddReloadHook(ReloadHook{
+		Name: "record",
+		Apply: func(_ context.Context, config map[string]interface{}) error {
+			got = config
+			return nil
+		},
+	})
+
+	if err := manager.Watch(context.Background(), nil); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	watcher.emit(map[string]interface{}{"key": "value"})
+
+	if got["key"] != "value" {
+		t.Errorf("hook received %v, want reloaded config", got)
+	}
+}
+
+func TestManager_RunReloadHooks_NoLeaderProvider(t *testing.T) {
+	manager := NewManager(ManagerConfig{
+		ReloadHooks: []ReloadHook{{
+			Name:       "migrate",
+			LeaderOnly: true,
+			Apply: func(context.Context, map[string]interface{}) error {
+				t.Error("leader-only hook should not run without a LeaderProvider")
+				return nil
+			},
+		}},
+	})
+
+	if err := manager.RunReloadHooks(context.Background(), nil); err != nil {
+		t.Errorf("RunReloadHooks() error = %v", err)
+	}
+}
+
+func TestManager_Reload_ReloadHooks(t *testing.T) {
+	errRejected := errors.New("rejected")
+	errMigration := errors.New("migration failed")
+
+	tests := []struct {
+		name        string
+		callbackErr error
+		hookErr     error
+		wantRan     bool
+		wantHookErr bool
+	}{
+		{name: "accepted", wantRan: true},
+		{name: "rejected", callbackErr: errRejected},
+		{name: "hook fails", hookErr: errMigration, wantRan: true, wantHookErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran bool
+			var hookErrs []*ReloadHookError
+			manager := NewManager(ManagerConfig{
+				Providers:         []Provider{NewMockProvider("test", map[string]interface{}{"key": "value"})},
+				OnReloadHookError: func(err *ReloadHookError) { hookErrs = append(hookErrs, err) },
+				ReloadHooks: []ReloadHook{{Name: "migrate", Apply: func(context.Context, map[string]interface{}) error {
+					ran = true
+					return tt.hookErr
+				}}},
+			})
+
+			err := manager.Reload(context.Background(), func(map[string]interface{}) error { return tt.callbackErr })
+			if !errors.Is(err, tt.callbackErr) || (tt.callbackErr == nil && err != nil) {
+				t.Errorf("Reload() error = %v, want %v", err, tt.callbackErr)
+			}
+			if ran != tt.wantRan {
+				t.Errorf("Hook ran = %v, want %v", ran, tt.wantRan)
+			}
+			if tt.wantHookErr != (len(hookErrs) == 1) {
+				t.Fatalf("OnReloadHookError got %v, want an error %v", hookErrs, tt.wantHookErr)
+			}
+			if tt.wantHookErr && !errors.Is(hookErrs[0].Failed["migrate"], errMigration) {
+				t.Errorf("Expected the migrate failure reported, got %v", hookErrs[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Lint finding severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Lint finding codes
+const (
+	FindingProviderError   = "provider_error"
+	FindingMergeConflict   = "merge_conflict"
+	FindingValidationError = "validation_error"
+)
+
+// LintFinding is a single machine-readable issue found by Lint
+type LintFinding struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Key      string `json:"key,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintReport is the result of linting a configuration
+type LintReport struct {
+	Valid     bool                   `json:"valid"`
+	Providers []string               `json:"providers"` // Priority order (first = highest)
+	Findings  []LintFinding          `json:"findings"`
+	Config    map[string]interface{} `json:"config,omitempty"` // Merged and expanded configuration
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *LintReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Lint loads, merges, expands and validates configuration without starting watchers
+// or invoking reload callbacks, so CI pipelines can verify rendered configs before rollout
+// Unlike Manager.Load it does not stop at the first problem: every provider is loaded and
+// all provider errors, merge conflicts and validation errors are reported as findings
+// Merge conflicts are warnings unless cfg.FailOnMergeConflict is set
+func Lint(ctx context.Context, cfg ManagerConfig) *LintReport {
+	report := &LintReport{
+		Providers: make([]string, 0, len(cfg.Providers)),
+		Findings:  []LintFinding{},
+	}
+
+	for _, provider := range cfg.Providers {
+		report.Providers = append(report.Providers, provider.Name())
+	}
+
+	result := make(map[string]interface{})
+	provenance := make(map[string]*Provenance)
+
+	// Load from providers in reverse order (lower priority first), same as Manager.Load
+	for i := len(cfg.Providers) - 1; i >= 0; i-- {
+		provider := cfg.Providers[i]
+
+		data, err := provider.Load(ctx)
+		if err != nil {
+			report.add(SeverityError, FindingProviderError, "", provider.Name(), err.Error())
+			continue
+		}
+
+		for _, conflict := range mergeChecked(result, data, "", provider.Name(), provenance) {
+			severity := SeverityWarning
+			if cfg.FailOnMergeConflict {
+				severity = SeverityError
+			}
+			report.add(severity, FindingMergeConflict, conflict.Key, conflict.Provider, conflict.Error())
+		}
+	}
+
+	// Expand dotted keys supplied literally by providers into nested maps
+	report.Config = Expand(Flatten(result))
+
+	if cfg.Validator != nil {
+		if err := cfg.Validator.Validate(report.Config); err != nil {
+			var verrs ValidationErrors
+			if errors.As(err, &verrs) {
+				for _, verr := range verrs {
+					report.add(SeverityError, FindingValidationError, verr.Field, "", verr.Message)
+				}
+			} else {
+				report.add(SeverityError, FindingValidationError, "", "", err.Error())
+			}
+		}
+	}
+
+	report.Valid = true
+	for _, finding := range report.Findings {
+		if finding.Severity == SeverityError {
+			report.Valid = false
+			break
+		}
+	}
+
+	return report
+}
+
+func (r *LintReport) add(severity, code, key, provider, message string) {
+	r.Findings = append(r.Findings, LintFinding{
+		Severity: severity,
+		Code:     code,
+		Key:      key,
+		Provider: provider,
+		Message:  message,
+	})
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	type serverConfig struct {
+		Port int `validate:"min=1,max=65535"`
+	}
+
+	failing := NewMockProvider("consul", nil)
+	failing.err = errors.New("connection refused")
+
+	report := Lint(context.Background(), ManagerConfig{
+		Providers: []Provider{
+			NewMockProvider("env", map[string]interface{}{
+				"server": "0.0.0.0",
+				"port":   int64(70000),
+			}),
+			failing,
+			NewMockProvider("file", map[string]interface{}{
+				"server":       map[string]interface{}{"host": "localhost"},
+				"diameter.dra": "dra1",
+			}),
+		},
+		Validator: NewStructValidator(&serverConfig{}),
+	})
+
+	if report.Valid {
+		t.Error("report should be invalid")
+	}
+	if want := []string{"env", "consul", "file"}; len(report.Providers) != 3 || report.Providers[0] != want[0] || report.Providers[2] != want[2] {
+		t.Errorf("Providers = %v, want %v", report.Providers, want)
+	}
+
+	codes := make(map[string]LintFinding)
+	for _, finding := range report.Findings {
+		codes[finding.Code] = finding
+	}
+	if f, ok := codes[FindingProviderError]; !ok || f.Provider != "consul" {
+		t.Errorf("missing provider error finding: %+v", report.Findings)
+	}
+	if f, ok := codes[FindingMergeConflict]; !ok || f.Key != "server" || f.Severity != SeverityWarning {
+		t.Errorf("missing merge conflict warning: %+v", report.Findings)
+	}
+	if f, ok := codes[FindingValidationError]; !ok || f.Key != "Port" {
+		t.Errorf("missing validation error finding: %+v", report.Findings)
+	}
+
+	if dra, ok := lookupPath(report.Config, "diameter.dra"); !ok || dra != "dra1" {
+		t.Errorf("dotted keys should be expanded, got %v", report.Config)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded LintReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("report JSON should decode: %v", err)
+	}
+	if len(decoded.Findings) != len(report.Findings) {
+		t.Errorf("decoded %d findings, want %d", len(decoded.Findings), len(report.Findings))
+	}
+}
+
+func TestLint_Valid(t *testing.T) {
+	report := Lint(context.Background(), ManagerConfig{
+		Providers: []Provider{NewMockProvider("file", map[string]interface{}{"port": 8080})},
+	})
+
+	if !report.Valid || len(report.Findings) != 0 {
+		t.Errorf("report = %+v, want valid with no findings", report)
+	}
+}
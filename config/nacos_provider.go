@@ -0,0 +1,404 @@
+package config
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderNacos identifies the Nacos config service
+const ProviderNacos RemoteProviderType = "nacos"
+
+// Nacos long-polling protocol separators
+const (
+	nacosWordSeparator = "\x02"
+	nacosLineSeparator = "\x01"
+)
+
+// NacosConfig configures a Nacos provider and watcher
+type NacosConfig struct {
+	// Endpoints are Nacos servers ("http://nacos-1:8848"); they are tried in order
+	Endpoints []string
+
+	// ContextPath of the Nacos server (default: "/nacos")
+	ContextPath string
+
+	// Namespace ID (tenant); empty is the public namespace
+	Namespace string
+
+	// Group of the config (default: "DEFAULT_GROUP")
+	Group string
+
+	// DataID of the config
+	DataID string
+
+	// Format of the content; inferred from the DataID extension when empty (default: yaml)
+	Format FileFormat
+
+	// Username and Password enable Nacos authentication (optional)
+	Username string
+	Password string
+
+	// Timeout for each request (default: 10s)
+	Timeout time.Duration
+
+	// LongPollTimeout is how long the server holds a watch request (default: 30s)
+	LongPollTimeout time.Duration
+
+	// RetryConfig for resilient loads
+	RetryConfig RetryConfig
+
+	// HTTPClient overrides the default client (optional)
+	HTTPClient *http.Client
+}
+
+// nacosClient talks to the Nacos Open API
+type nacosClient struct {
+	config NacosConfig
+	http   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+func newNacosClient(cfg NacosConfig) (*nacosClient, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("nacos provider requires at least one endpoint")
+	}
+	if cfg.DataID == "" {
+		return nil, fmt.Errorf("nacos provider requires a data ID")
+	}
+	if cfg.ContextPath == "" {
+		cfg.ContextPath = "/nacos"
+	}
+	if cfg.Group == "" {
+		cfg.Group = "DEFAULT_GROUP"
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatYAML
+		if strings.HasSuffix(cfg.DataID, ".json") {
+			cfg.Format = FormatJSON
+		}
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.LongPollTimeout == 0 {
+		cfg.LongPollTimeout = 30 * time.Second
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	return &nacosClient{config: cfg, http: client}, nil
+}
+
+// endpointURL joins an endpoint, the context path and an API path
+func (c *nacosClient) endpointURL(endpoint, api string) string {
+	return strings.TrimSuffix(endpoint, "/") + path.Join(c.config.ContextPath, api)
+}
+
+// do sends a request to each endpoint in turn until one answers
+func (c *nacosClient) do(ctx context.Context, method, api string, query url.Values, body url.Values, timeout time.Duration, header http.Header) ([]byte, error) {
+	var lastErr error
+	for _, endpoint := range c.config.Endpoints {
+		data, err := c.doEndpoint(ctx, endpoint, method, api, query, body, timeout, header)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (c *nacosClient) doEndpoint(ctx context.Context, endpoint, method, api string, query url.Values, body url.Values, timeout time.Duration, header http.Header) ([]byte, error) {
+	token, err := c.token(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if token != "" {
+		q.Set("accessToken", token)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = strings.NewReader(body.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpointURL(endpoint, api)+"?"+q.Encode(), reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("nacos request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nacos response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("config not found: %s", c.describe())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nacos returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// token returns a valid access token, logging in when credentials are configured
+func (c *nacosClient) token(ctx context.Context, endpoint string) (string, error) {
+	if c.config.Username == "" {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken != "" && time.Now().Before(c.tokenExpiry) {
+		return c.accessToken, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	form := url.Values{"username": {c.config.Username}, "password": {c.config.Password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpointURL(endpoint, "/v1/auth/login"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("nacos login failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("nacos login failed: HTTP %d", resp.StatusCode)
+	}
+
+	var login struct {
+		AccessToken string `json:"accessToken"`
+		TokenTTL    int64  `json:"tokenTtl"` // Seconds
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&login); err != nil {
+		return "", fmt.Errorf("invalid nacos login response: %w", err)
+	}
+
+	// Refresh at 90% of the TTL
+	c.accessToken = login.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(login.TokenTTL) * time.Second * 9 / 10)
+	return c.accessToken, nil
+}
+
+// configQuery identifies the config in API requests
+func (c *nacosClient) configQuery() url.Values {
+	q := url.Values{"dataId": {c.config.DataID}, "group": {c.config.Group}}
+	if c.config.Namespace != "" {
+		q.Set("tenant", c.config.Namespace)
+	}
+	return q
+}
+
+// fetch returns the raw config content
+func (c *nacosClient) fetch(ctx context.Context) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, "/v1/cs/configs", c.configQuery(), nil, c.config.Timeout, nil)
+}
+
+// listen long-polls until the content no longer matches md5 or the poll times out
+// It returns true when the server reports a change
+func (c *nacosClient) listen(ctx context.Context, md5 string) (bool, error) {
+	line := c.config.DataID + nacosWordSeparator + c.config.Group + nacosWordSeparator + md5
+	if c.config.Namespace != "" {
+		line += nacosWordSeparator + c.config.Namespace
+	}
+	body := url.Values{"Listening-Configs": {line + nacosLineSeparator}}
+	header := http.Header{"Long-Pulling-Timeout": {strconv.FormatInt(c.config.LongPollTimeout.Milliseconds(), 10)}}
+
+	// Allow the server to hold the request for the full poll
+	data, err := c.do(ctx, http.MethodPost, "/v1/cs/configs/listener", nil, body, c.config.LongPollTimeout+c.config.Timeout, header)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(data)) != "", nil
+}
+
+// parse decodes config content per the configured format
+func (c *nacosClient) parse(data []byte) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	switch c.config.Format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", c.config.Format)
+	}
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	return result, nil
+}
+
+// describe identifies the config for messages
+func (c *nacosClient) describe() string {
+	namespace := c.config.Namespace
+	if namespace == "" {
+		namespace = "public"
+	}
+	return fmt.Sprintf("%s/%s/%s", namespace, c.config.Group, c.config.DataID)
+}
+
+// contentMD5 returns the hex MD5 Nacos uses to detect changes
+func contentMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// NacosProvider implements Provider for the Nacos config service
+type NacosProvider struct {
+	client *nacosClient
+}
+
+// NewNacosProvider creates a Nacos-based configuration provider
+func NewNacosProvider(cfg NacosConfig) (*NacosProvider, error) {
+	client, err := newNacosClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NacosProvider{client: client}, nil
+}
+
+// Load retrieves configuration from Nacos
+func (n *NacosProvider) Load(ctx context.Context) (map[string]interface{}, error) {
+	var data []byte
+	err := retryWithBackoff(n.client.config.RetryConfig, func() error {
+		var err error
+		data, err = n.client.fetch(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return n.client.parse(data)
+}
+
+// Name returns the provider name
+func (n *NacosProvider) Name() string {
+	return fmt.Sprintf("nacos(%s)", n.client.describe())
+}
+
+// Close closes the Nacos provider
+func (n *NacosProvider) Close() error {
+	n.client.http.CloseIdleConnections()
+	return nil
+}
+
+// NacosWatcher watches a Nacos config using the long-polling listener API
+type NacosWatcher struct {
+	client *nacosClient
+	stopCh chan struct{}
+	once   sync.Once
+}
+
+// NewNacosWatcher creates a watcher for Nacos configuration changes
+func NewNacosWatcher(cfg NacosConfig) (*NacosWatcher, error) {
+	client, err := newNacosClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NacosWatcher{client: client, stopCh: make(chan struct{})}, nil
+}
+
+// Watch long-polls Nacos and invokes callback with the new config after each change
+func (w *NacosWatcher) Watch(ctx context.Context, callback func(map[string]interface{})) error {
+	data, err := w.client.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get initial config: %w", err)
+	}
+	md5 := contentMD5(data)
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-w.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer cancel()
+		for ctx.Err() == nil {
+			changed, err := w.client.listen(ctx, md5)
+			if err != nil {
+				// Back off before polling again
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(w.client.config.Timeout):
+				}
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			data, err := w.client.fetch(ctx)
+			if err != nil {
+				continue
+			}
+			md5 = contentMD5(data)
+
+			config, err := w.client.parse(data)
+			if err != nil {
+				// Keep the previous config until a valid one is published
+				continue
+			}
+			callback(config)
+		}
+	}()
+
+	return nil
+}
+
+// Stop halts the watcher
+func (w *NacosWatcher) Stop() error {
+	w.once.Do(func() { close(w.stopCh) })
+	return nil
+}
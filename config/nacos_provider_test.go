@@ -0,0 +1,141 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNacos serves one config through the Nacos Open API
+type fakeNacos struct {
+	mu      sync.Mutex
+	content string
+	changed chan struct{}
+}
+
+func newFakeNacos(content string) *fakeNacos {
+	return &fakeNacos{content: content, changed: make(chan struct{})}
+}
+
+func (f *fakeNacos) publish(content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.content = content
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeNacos) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/nacos/v1/auth/login" {
+		if r.FormValue("username") != "nacos" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"accessToken":"token-1","tokenTtl":18000}`))
+		return
+	}
+	if r.URL.Query().Get("accessToken") != "token-1" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	content, changed := f.content, f.changed
+	f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/nacos/v1/cs/configs":
+		q := r.URL.Query()
+		if q.Get("dataId") != "eir.yaml" || q.Get("group") != "EIR" || q.Get("tenant") != "prod" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(content))
+	case "/nacos/v1/cs/configs/listener":
+		fields := strings.Split(strings.TrimSuffix(r.FormValue("Listening-Configs"), nacosLineSeparator), nacosWordSeparator)
+		if len(fields) != 4 || fields[2] != contentMD5([]byte(content)) {
+			w.Write([]byte("eir.yaml%02EIR%02prod%01\n"))
+			return
+		}
+		select {
+		case <-changed:
+			w.Write([]byte("eir.yaml%02EIR%02prod%01\n"))
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestNacosProvider_LoadAndWatch(t *testing.T) {
+	nacos := newFakeNacos("server:\n  port: 8080\n")
+	server := httptest.NewServer(nacos)
+	defer server.Close()
+
+	cfg := NacosConfig{
+		Endpoints: []string{"http://127.0.0.1:1", server.URL}, // First endpoint is down
+		Namespace: "prod",
+		Group:     "EIR",
+		DataID:    "eir.yaml",
+		Username:  "nacos",
+		Password:  "secret",
+		Timeout:   time.Second,
+	}
+
+	provider, err := NewNacosProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewNacosProvider failed: %v", err)
+	}
+	defer provider.Close()
+
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if data["server"].(map[string]interface{})["port"] != 8080 {
+		t.Errorf("Expected port 8080, got %v", data)
+	}
+	if provider.Name() != "nacos(prod/EIR/eir.yaml)" {
+		t.Errorf("Unexpected name %q", provider.Name())
+	}
+
+	watcher, err := NewNacosWatcher(cfg)
+	if err != nil {
+		t.Fatalf("NewNacosWatcher failed: %v", err)
+	}
+	defer watcher.Stop()
+
+	updates := make(chan map[string]interface{}, 1)
+	if err := watcher.Watch(context.Background(), func(c map[string]interface{}) { updates <- c }); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	nacos.publish("server:\n  port: 9090\n")
+	select {
+	case update := <-updates:
+		if update["server"].(map[string]interface{})["port"] != 9090 {
+			t.Errorf("Expected port 9090, got %v", update)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for config change")
+	}
+}
+
+func TestNacosProvider_NotFound(t *testing.T) {
+	server := httptest.NewServer(newFakeNacos("{}"))
+	defer server.Close()
+
+	provider, _ := NewNacosProvider(NacosConfig{Endpoints: []string{server.URL}, DataID: "missing.json", Username: "nacos"})
+	if _, err := provider.Load(context.Background()); err == nil || !strings.Contains(err.Error(), "config not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	if _, err := NewNacosProvider(NacosConfig{DataID: "eir.yaml"}); err == nil {
+		t.Error("Expected error without endpoints")
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Provenance describes where the effective value of a config key came from
+type Provenance struct {
+	// Key is the dot-separated key path
+	Key string
+
+	// Provider supplied the effective value
+	Provider string
+
+	// Overridden lists lower priority providers whose values were replaced, lowest priority first
+	Overridden []string
+}
+
+// Provenance returns where the effective value of a leaf key came from after Load
+func (m *Manager) Provenance(key string) (Provenance, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.provenance[key]
+	if !ok {
+		return Provenance{}, false
+	}
+
+	result := *p
+	result.Overridden = append([]string(nil), p.Overridden...)
+	return result, true
+}
+
+// DumpEffectiveConfig writes the effective configuration as sorted "key = value" lines
+// With withProvenance, each line is annotated with its provider and overridden providers
+func (m *Manager) DumpEffectiveConfig(w io.Writer, withProvenance bool) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	flat := Flatten(m.current)
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		line := fmt.Sprintf("%s = %v", k, flat[k])
+
+		if withProvenance {
+			if p, ok := m.provenance[k]; ok {
+				line += "  # from " + p.Provider
+				if len(p.Overridden) > 0 {
+					line += ", overrides " + strings.Join(p.Overridden, ", ")
+				}
+			}
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordProvenance records source as the provider of value at key and returns the providers it overrides
+// Entries for the key and anything nested under it are replaced
+func recordProvenance(provenance map[string]*Provenance, key string, value interface{}, source string) []string {
+	var overridden []string
+	seen := make(map[string]bool)
+	addOverridden := func(provider string) {
+		if provider != "" && !seen[provider] {
+			seen[provider] = true
+			overridden = append(overridden, provider)
+		}
+	}
+
+	// Collect and remove the replaced value and its descendants
+	nestedPrefix := key + KeySeparator
+	for k, p := range provenance {
+		if k == key || strings.HasPrefix(k, nestedPrefix) {
+			for _, o := range p.Overridden {
+				addOverridden(o)
+			}
+			addOverridden(p.Provider)
+			delete(provenance, k)
+		}
+	}
+
+	// Record the new value's leaves
+	if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+		for leaf := range Flatten(nested) {
+			provenance[nestedPrefix+leaf] = &Provenance{
+				Key:        nestedPrefix + leaf,
+				Provider:   source,
+				Overridden: overridden,
+			}
+		}
+		return overridden
+	}
+
+	provenance[key] = &Provenance{Key: key, Provider: source, Overridden: overridden}
+	return overridden
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestManager_Provenance(t *testing.T) {
+	manager := NewManager(ManagerConfig{
+		Providers: []Provider{
+			NewMockProvider("env", map[string]interface{}{
+				"server": map[string]interface{}{"port": 9090},
+			}),
+			NewMockProvider("consul", map[string]interface{}{
+				"server": map[string]interface{}{"port": 8081},
+			}),
+			NewMockProvider("file", map[string]interface{}{
+				"server": map[string]interface{}{"host": "localhost", "port": 8080},
+			}),
+		},
+	})
+
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	p, ok := manager.Provenance("server.port")
+	if !ok {
+		t.Fatal("Provenance(server.port) not found")
+	}
+	if p.Provider != "env" {
+		t.Errorf("Provider = %q, want env", p.Provider)
+	}
+	if strings.Join(p.Overridden, ",") != "file,consul" {
+		t.Errorf("Overridden = %v, want [file consul]", p.Overridden)
+	}
+
+	p, ok = manager.Provenance("server.host")
+	if !ok || p.Provider != "file" || len(p.Overridden) != 0 {
+		t.Errorf("Provenance(server.host) = %+v, %v; want file with no overrides", p, ok)
+	}
+
+	if _, ok := manager.Provenance("server"); ok {
+		t.Error("Provenance should only be tracked for leaf keys")
+	}
+}
+
+func TestManager_Provenance_ReplacedMap(t *testing.T) {
+	manager := NewManager(ManagerConfig{
+		Providers: []Provider{
+			NewMockProvider("env", map[string]interface{}{"database": "postgres://db"}),
+			NewMockProvider("file", map[string]interface{}{
+				"database": map[string]interface{}{"host": "db", "port": 5432},
+			}),
+		},
+	})
+
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if _, ok := manager.Provenance("database.host"); ok {
+		t.Error("replaced nested keys should be removed")
+	}
+	p, ok := manager.Provenance("database")
+	if !ok || p.Provider != "env" || len(p.Overridden) != 1 || p.Overridden[0] != "file" {
+		t.Errorf("Provenance(database) = %+v, %v; want env overriding file", p, ok)
+	}
+}
+
+func TestManager_DumpEffectiveConfig(t *testing.T) {
+	manager := NewManager(ManagerConfig{
+		Providers: []Provider{
+			NewMockProvider("env", map[string]interface{}{"port": 9090}),
+			NewMockProvider("file", map[string]interface{}{"port": 8080, "host": "localhost"}),
+		},
+	})
+
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var plain bytes.Buffer
+	if err := manager.DumpEffectiveConfig(&plain, false); err != nil {
+		t.Fatalf("DumpEffectiveConfig() error = %v", err)
+	}
+	if got, want := plain.String(), "host = localhost\nport = 9090\n"; got != want {
+		t.Errorf("DumpEffectiveConfig(false) = %q, want %q", got, want)
+	}
+
+	var annotated bytes.Buffer
+	if err := manager.DumpEffectiveConfig(&annotated, true); err != nil {
+		t.Fatalf("DumpEffectiveConfig() error = %v", err)
+	}
+	if !strings.Contains(annotated.String(), "port = 9090  # from env, overrides file") {
+		t.Errorf("DumpEffectiveConfig(true) = %q, missing provenance annotation", annotated.String())
+	}
+}
@@ -2,6 +2,11 @@ package config
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -40,7 +45,45 @@ type Manager struct {
 	providers []Provider
 	validator Validator
 	watcher   Watcher
-	current   map[string]interface{}
+
+	mu         sync.RWMutex
+	current    map[string]interface{}
+	conflicts  MergeConflicts
+	provenance map[string]*Provenance
+
+	strictMerge    bool
+	failOnConflict bool
+
+	reloadQuietPeriod time.Duration
+	minReloadInterval time.Duration
+
+	leader      LeaderProvider
+	hooks       []ReloadHook
+	onHookError func(*ReloadHookError)
+
+	secretKeys     []string
+	secretHandlers []secretHandler
+	changeLog      func([]ConfigChange)
+
+	staleAfter time.Duration
+	status     []providerState
+
+	immutableKeys        []string
+	immutableViolations  ImmutableKeyErrors
+	onImmutableViolation func(ImmutableKeyErrors)
+
+	channelSwitch     *channelSwitch // Last SwitchChannel, baking until its deadline
+	onChannelRollback func(ChannelSwitch)
+
+	resolvers map[string]Resolver // Fallback expression schemes
+
+	reloadSignals  []os.Signal // Signals Watch reloads on
+	onSignalReload func(os.Signal, error)
+
+	components   []Component
+	applyTimeout time.Duration
+	onApply      func(ApplyStatus)
+	lastApply    *ApplyStatus
 }
 
 // ManagerConfig configures the config manager
@@ -59,6 +102,106 @@ type ManagerConfig struct {
 
 	// ReloadCallback is called after successful config reload
 	ReloadCallback func(map[string]interface{}) error
+
+	// StrictMerge detects overrides that change a value's type (e.g., a map replaced by a scalar)
+	// Detected conflicts are available from MergeConflicts after Load
+	StrictMerge bool
+
+	// FailOnMergeConflict makes Load fail when StrictMerge detects conflicts
+	FailOnMergeConflict bool
+
+	// ReloadQuietPeriod coalesces watch events: the reload fires once no change
+	// has been seen for this long, with the final merged state (0 = no coalescing)
+	ReloadQuietPeriod time.Duration
+
+	// MinReloadInterval limits reloads triggered by Watch to at most one per interval (0 = unlimited)
+	MinReloadInterval time.Duration
+
+	// LeaderProvider is consulted before running leader-only reload hooks
+	LeaderProvider LeaderProvider
+
+	// ReloadHooks run after each successful reload triggered by Watch
+	ReloadHooks []ReloadHook
+
+	// OnReloadHookError receives the failures of reload hooks run by a reload
+	// A failing hook does not reject the reload
+	OnReloadHookError func(*ReloadHookError)
+
+	// SecretKeys are additional key patterns holding credentials (see OnSecretRotated)
+	// Keys ending in "password", "secret", "token", "api_key" etc. are always secrets
+	SecretKeys []string
+
+	// ChangeLog receives the keys changed by each reload triggered by Watch,
+	// with secret values redacted
+	ChangeLog func([]ConfigChange)
+
+	// StaleAfter marks a provider stale in ProviderStatus when it hasn't loaded
+	// successfully for this long (0 = only never-loaded providers are stale)
+	StaleAfter time.Duration
+
+	// ImmutableKeys are key patterns (as for OnSecretRotated) frozen after the first
+	// successful Load, e.g. node identity and listen ports. Reloads keep their
+	// startup values and apply the rest of the new config
+	ImmutableKeys []string
+
+	// OnImmutableKeyChange receives the immutable key changes a reload rejected
+	OnImmutableKeyChange func(ImmutableKeyErrors)
+
+	// OnChannelRollback is called when a config channel switch is rolled back
+	// because the reload callback failed during its bake period (see SwitchChannel)
+	OnChannelRollback func(ChannelSwitch)
+
+	// Resolvers evaluate the schemes of fallback expressions such as
+	// "${CONSUL:eir/db_host | ${ENV:DB_HOST} | localhost}" by upper case scheme name
+	// (ENV is built in), see ConsulResolver
+	Resolvers map[string]Resolver
+
+	// ReloadOnSIGHUP makes Watch also reload from all providers on SIGHUP, through
+	// the same validation, callback and hook pipeline as watch events (see WatchSignals)
+	ReloadOnSIGHUP bool
+
+	// OnSignalReload receives the signal and result of each signal-triggered reload
+	OnSignalReload func(os.Signal, error)
+
+	// Components apply each reload after the callback and confirm it; a component
+	// that fails or doesn't confirm within ApplyTimeout gets the previous config
+	// re-applied (see AddComponent)
+	Components []Component
+
+	// ApplyTimeout bounds each component's apply (default: DefaultApplyTimeout)
+	ApplyTimeout time.Duration
+
+	// OnApply receives the consolidated status of each reload applied to the components
+	OnApply func(ApplyStatus)
+}
+
+// MergeConflict describes a higher priority provider overriding a value with a different type
+type MergeConflict struct {
+	Key                string // Dot-separated key path
+	Provider           string // Provider supplying the overriding value
+	Type               string
+	OverriddenProvider string // Provider whose value was overridden
+	OverriddenType     string
+}
+
+func (c MergeConflict) Error() string {
+	return fmt.Sprintf("merge conflict on key '%s': %s sets %s, overriding %s from %s",
+		c.Key, c.Provider, c.Type, c.OverriddenType, c.OverriddenProvider)
+}
+
+// MergeConflicts is a collection of merge conflicts
+type MergeConflicts []MergeConflict
+
+func (c MergeConflicts) Error() string {
+	if len(c) == 0 {
+		return "no merge conflicts"
+	}
+
+	var msgs []string
+	for _, conflict := range c {
+		msgs = append(msgs, conflict.Error())
+	}
+	return strings.Join(msgs, "; ")
 }
 
 // NewManager creates a new configuration manager
@@ -67,6 +210,35 @@ func NewManager(cfg ManagerConfig) *Manager {
 		providers: cfg.Providers,
 		validator: cfg.Validator,
 		watcher:   cfg.Watcher,
+
+		strictMerge:    cfg.StrictMerge,
+		failOnConflict: cfg.FailOnMergeConflict,
+
+		reloadQuietPeriod: cfg.ReloadQuietPeriod,
+		minReloadInterval: cfg.MinReloadInterval,
+
+		leader:      cfg.LeaderProvider,
+		hooks:       append([]ReloadHook(nil), cfg.ReloadHooks...),
+		onHookError: cfg.OnReloadHookError,
+
+		secretKeys: cfg.SecretKeys,
+		changeLog:  cfg.ChangeLog,
+
+		staleAfter: cfg.StaleAfter,
+
+		immutableKeys:        cfg.ImmutableKeys,
+		onImmutableViolation: cfg.OnImmutableKeyChange,
+
+		onChannelRollback: cfg.OnChannelRollback,
+
+		resolvers: cfg.Resolvers,
+
+		reloadSignals:  reloadSignals(cfg.ReloadOnSIGHUP),
+		onSignalReload: cfg.OnSignalReload,
+
+		components:   append([]Component(nil), cfg.Components...),
+		applyTimeout: cfg.ApplyTimeout,
+		onApply:      cfg.OnApply,
 	}
 }
 
@@ -74,18 +246,41 @@ func NewManager(cfg ManagerConfig) *Manager {
 // Higher priority providers (earlier in slice) override lower priority
 func (m *Manager) Load(ctx context.Context) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
+	provenance := make(map[string]*Provenance)
+	var conflicts MergeConflicts
 
 	// Load from providers in reverse order (lower priority first)
 	for i := len(m.providers) - 1; i >= 0; i-- {
-		data, err := m.providers[i].Load(ctx)
+		data, err := m.loadProvider(ctx, i, "")
 		if err != nil {
 			return nil, err
 		}
 
-		// Merge with deep merge strategy
-		merge(result, data)
+		// Merge with deep merge strategy, tracking where each key came from
+		conflicts = append(conflicts, mergeChecked(result, data, "", m.providers[i].Name(), provenance)...)
+	}
+
+	if !m.strictMerge {
+		conflicts = nil
+	}
+	m.mu.Lock()
+	m.conflicts = conflicts
+	m.mu.Unlock()
+	if len(conflicts) > 0 && m.failOnConflict {
+		return nil, conflicts
+	}
+
+	// Evaluate fallback expressions on the merged values
+	if err := m.resolveFallbacks(ctx, result); err != nil {
+		return nil, err
 	}
 
+	// Keep immutable keys at their startup values
+	m.mu.RLock()
+	old := m.current
+	m.mu.RUnlock()
+	m.freezeImmutable(old, result)
+
 	// Validate if validator is configured
 	if m.validator != nil {
 		if err := m.validator.Validate(result); err != nil {
@@ -93,17 +288,53 @@ func (m *Manager) Load(ctx context.Context) (map[string]interface{}, error) {
 		}
 	}
 
+	m.mu.Lock()
 	m.current = result
+	m.provenance = provenance
+	m.mu.Unlock()
 	return result, nil
 }
 
 // Watch starts watching for configuration changes
+// With ReloadQuietPeriod or MinReloadInterval set, change events are coalesced and
+// the callback receives the configuration reloaded from all providers
+// Reload hooks run after the callback; leader-only hooks run only on the leader
+// Reloads that only rotate handled secrets skip both (see OnSecretRotated)
 func (m *Manager) Watch(ctx context.Context, callback func(map[string]interface{}) error) error {
+	if len(m.reloadSignals) > 0 {
+		m.WatchSignals(ctx, callback, m.reloadSignals...)
+	}
+
 	if m.watcher == nil {
 		return nil // No watcher configured
 	}
 
+	if m.reloadQuietPeriod > 0 || m.minReloadInterval > 0 {
+		coalescer := newReloadCoalescer(m.reloadQuietPeriod, m.minReloadInterval, func() {
+			m.Reload(ctx, callback)
+		})
+
+		go func() {
+			<-ctx.Done()
+			coalescer.stop()
+		}()
+
+		return m.watcher.Watch(ctx, func(map[string]interface{}) {
+			coalescer.trigger()
+		})
+	}
+
 	return m.watcher.Watch(ctx, func(data map[string]interface{}) {
+		if err := m.resolveFallbacks(ctx, data); err != nil {
+			// Keep the previous config when an expression can't be evaluated
+			return
+		}
+
+		m.mu.RLock()
+		previous := m.current
+		m.mu.RUnlock()
+		m.freezeImmutable(previous, data)
+
 		// Validate before callback
 		if m.validator != nil {
 			if err := m.validator.Validate(data); err != nil {
@@ -112,13 +343,78 @@ func (m *Manager) Watch(ctx context.Context, callback func(map[string]interface{
 			}
 		}
 
+		m.mu.Lock()
+		old, provenance := m.current, m.provenance
 		m.current = data
-		if callback != nil {
-			callback(data)
+		m.mu.Unlock()
+
+		// Keep the previous config if the callback or a component rejects this one
+		err := m.applyReload(ctx, old, data, callback)
+		if err != nil {
+			m.restoreConfig(old, provenance)
 		}
+		m.observeReload(ctx, err)
 	})
 }
 
+// MergeConflicts returns the type conflicts detected by the last Load (StrictMerge only)
+func (m *Manager) MergeConflicts() MergeConflicts {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.conflicts
+}
+
+// Get returns the current value at a dot-separated key (e.g., "server.port")
+func (m *Manager) Get(key string) (interface{}, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return lookupPath(m.current, key)
+}
+
+// GetString returns the current value at key as a string
+func (m *Manager) GetString(key string) (string, bool) {
+	v, ok := m.Get(key)
+	if !ok {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
+
+// GetInt returns the current value at key as an int
+// Integer and whole float values (as decoded from JSON) are accepted
+func (m *Manager) GetInt(key string) (int, bool) {
+	v, ok := m.Get(key)
+	if !ok {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), true
+		}
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return int(i), true
+		}
+	}
+	return 0, false
+}
+
+// GetBool returns the current value at key as a bool
+func (m *Manager) GetBool(key string) (bool, bool) {
+	v, ok := m.Get(key)
+	if !ok {
+		return false, false
+	}
+	b, ok := v.(bool)
+	return b, ok
+}
+
 // Close closes all providers and watcher
 func (m *Manager) Close() error {
 	for _, p := range m.providers {
@@ -147,6 +443,63 @@ func merge(dst, src map[string]interface{}) {
 	}
 }
 
+// mergeChecked deep merges src into dst like merge, reporting overrides that change a value's type
+// provenance records which provider supplied each leaf key so conflicts can name both providers
+func mergeChecked(dst, src map[string]interface{}, prefix, source string, provenance map[string]*Provenance) []MergeConflict {
+	var conflicts []MergeConflict
+
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + KeySeparator + k
+		}
+
+		existing, exists := dst[k]
+		if srcMap, ok := v.(map[string]interface{}); ok {
+			if dstMap, ok := existing.(map[string]interface{}); ok {
+				conflicts = append(conflicts, mergeChecked(dstMap, srcMap, key, source, provenance)...)
+				continue
+			}
+		}
+
+		overridden := recordProvenance(provenance, key, v, source)
+
+		if exists && existing != nil && v != nil {
+			if oldType, newType := valueKind(existing), valueKind(v); oldType != newType {
+				conflicts = append(conflicts, MergeConflict{
+					Key:                key,
+					Provider:           source,
+					Type:               newType,
+					OverriddenProvider: strings.Join(overridden, ", "),
+					OverriddenType:     oldType,
+				})
+			}
+		}
+
+		dst[k] = v
+	}
+
+	return conflicts
+}
+
+// valueKind classifies a config value for conflict detection (numbers of any width are equivalent)
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
 // RetryConfig configures retry behavior for providers
 type RetryConfig struct {
 	MaxRetries  int
@@ -155,6 +508,35 @@ type RetryConfig struct {
 	Multiplier  float64
 }
 
+// retryWithBackoff runs fn, retrying failures with exponential backoff per cfg
+func retryWithBackoff(cfg RetryConfig, fn func() error) error {
+	var lastErr error
+	retries := 0
+	wait := cfg.InitialWait
+
+	for retries <= cfg.MaxRetries {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+		retries++
+
+		if retries > cfg.MaxRetries {
+			break
+		}
+
+		time.Sleep(wait)
+		wait = time.Duration(float64(wait) * cfg.Multiplier)
+		if wait > cfg.MaxWait {
+			wait = cfg.MaxWait
+		}
+	}
+
+	return fmt.Errorf("failed to load config after %d retries: %w", retries, lastErr)
+}
+
 // DefaultRetryConfig returns sensible retry defaults
 func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
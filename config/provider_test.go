@@ -247,3 +247,81 @@ func TestDefaultRetryConfig(t *testing.T) {
 		t.Errorf("Multiplier = %f, want 2.0", cfg.Multiplier)
 	}
 }
+
+func TestManager_Get(t *testing.T) {
+	manager := NewManager(ManagerConfig{
+		Providers: []Provider{
+			NewMockProvider("test", map[string]interface{}{
+				"server": map[string]interface{}{
+					"host":    "localhost",
+					"port":    float64(8080),
+					"enabled": true,
+				},
+			}),
+		},
+	})
+
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if host, ok := manager.GetString("server.host"); !ok || host != "localhost" {
+		t.Errorf("GetString(server.host) = %q, %v; want localhost, true", host, ok)
+	}
+	if port, ok := manager.GetInt("server.port"); !ok || port != 8080 {
+		t.Errorf("GetInt(server.port) = %d, %v; want 8080, true", port, ok)
+	}
+	if enabled, ok := manager.GetBool("server.enabled"); !ok || !enabled {
+		t.Errorf("GetBool(server.enabled) = %v, %v; want true, true", enabled, ok)
+	}
+	if _, ok := manager.GetInt("server.host"); ok {
+		t.Error("GetInt on a string value should fail")
+	}
+	if _, ok := manager.Get("server.missing"); ok {
+		t.Error("Get on a missing key should fail")
+	}
+}
+
+func TestManager_Load_StrictMerge(t *testing.T) {
+	newProviders := func() []Provider {
+		return []Provider{
+			// Higher priority
+			NewMockProvider("env", map[string]interface{}{
+				"server": "0.0.0.0:8080",
+				"port":   int64(9090),
+			}),
+			// Lower priority
+			NewMockProvider("file", map[string]interface{}{
+				"server": map[string]interface{}{"host": "localhost"},
+				"port":   float64(8080),
+			}),
+		}
+	}
+
+	manager := NewManager(ManagerConfig{Providers: newProviders(), StrictMerge: true})
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	conflicts := manager.MergeConflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("MergeConflicts() = %v, want 1 conflict (numbers of different widths are compatible)", conflicts)
+	}
+	want := MergeConflict{Key: "server", Provider: "env", Type: "string", OverriddenProvider: "file", OverriddenType: "map"}
+	if conflicts[0] != want {
+		t.Errorf("conflict = %+v, want %+v", conflicts[0], want)
+	}
+
+	manager = NewManager(ManagerConfig{Providers: newProviders(), StrictMerge: true, FailOnMergeConflict: true})
+	if _, err := manager.Load(context.Background()); err == nil {
+		t.Error("Load() should fail on merge conflict when FailOnMergeConflict is set")
+	}
+
+	manager = NewManager(ManagerConfig{Providers: newProviders()})
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(manager.MergeConflicts()) != 0 {
+		t.Error("conflicts should not be tracked without StrictMerge")
+	}
+}
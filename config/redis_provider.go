@@ -0,0 +1,391 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderRedis identifies Redis as a config store
+const ProviderRedis RemoteProviderType = "redis"
+
+// RedisConfig configures a Redis provider and watcher
+type RedisConfig struct {
+	// Address of the Redis server ("localhost:6379")
+	Address string
+
+	// Username and Password for AUTH (optional; Username requires Redis 6+)
+	Username string
+	Password string
+
+	// DB selects the logical database
+	DB int
+
+	// Key holding the config
+	Key string
+
+	// Hash loads Key as a hash whose fields are dotted config keys ("server.port")
+	// Field values are decoded as JSON when possible; otherwise Key holds one document
+	Hash bool
+
+	// Format of a document key (default: json)
+	Format FileFormat
+
+	// Timeout for connecting and each command (default: 5s)
+	Timeout time.Duration
+
+	// KeyspaceNotifications makes the watcher subscribe to keyspace events for Key
+	// The server must have notify-keyspace-events enabled (e.g. "K$h"); otherwise the watcher polls
+	KeyspaceNotifications bool
+
+	// PollInterval for the watcher when not using notifications (default: 10s)
+	PollInterval time.Duration
+}
+
+// withDefaults fills in defaults and validates the config
+func (c RedisConfig) withDefaults() (RedisConfig, error) {
+	if c.Address == "" {
+		return c, fmt.Errorf("redis provider requires an address")
+	}
+	if c.Key == "" {
+		return c, fmt.Errorf("redis provider requires a key")
+	}
+	if c.Format == "" {
+		c.Format = FormatJSON
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = 10 * time.Second
+	}
+	return c, nil
+}
+
+// RedisProvider implements Provider for a config stored in Redis
+type RedisProvider struct {
+	config RedisConfig
+}
+
+// NewRedisProvider creates a Redis-based configuration provider
+func NewRedisProvider(cfg RedisConfig) (*RedisProvider, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &RedisProvider{config: cfg}, nil
+}
+
+// Load retrieves configuration from Redis
+func (r *RedisProvider) Load(ctx context.Context) (map[string]interface{}, error) {
+	_, result, err := loadRedis(ctx, r.config)
+	return result, err
+}
+
+// Name returns the provider name
+func (r *RedisProvider) Name() string {
+	return fmt.Sprintf("redis(%s/%d/%s)", r.config.Address, r.config.DB, r.config.Key)
+}
+
+// Close closes the Redis provider; connections are per load, so there is nothing to release
+func (r *RedisProvider) Close() error {
+	return nil
+}
+
+// loadRedis reads the config, returning the raw content for change detection
+func loadRedis(ctx context.Context, cfg RedisConfig) ([]byte, map[string]interface{}, error) {
+	conn, err := dialRedis(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	if cfg.Hash {
+		reply, err := conn.do("HGETALL", cfg.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields, ok := reply.([]interface{})
+		if !ok || len(fields) == 0 {
+			return nil, nil, fmt.Errorf("key not found: %s", cfg.Key)
+		}
+
+		var raw bytes.Buffer
+		flat := make(map[string]interface{}, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			field, _ := fields[i].([]byte)
+			value, _ := fields[i+1].([]byte)
+			raw.Write(field)
+			raw.WriteByte(0)
+			raw.Write(value)
+			raw.WriteByte(0)
+
+			var decoded interface{}
+			if err := json.Unmarshal(value, &decoded); err != nil {
+				decoded = string(value)
+			}
+			flat[string(field)] = decoded
+		}
+		return raw.Bytes(), Expand(flat), nil
+	}
+
+	reply, err := conn.do("GET", cfg.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("key not found: %s", cfg.Key)
+	}
+
+	var result map[string]interface{}
+	switch cfg.Format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported format: %s", cfg.Format)
+	}
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	return data, result, nil
+}
+
+// RedisWatcher watches a Redis config key using keyspace notifications or polling
+type RedisWatcher struct {
+	config RedisConfig
+	stopCh chan struct{}
+	once   sync.Once
+}
+
+// NewRedisWatcher creates a watcher for Redis configuration changes
+func NewRedisWatcher(cfg RedisConfig) (*RedisWatcher, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &RedisWatcher{config: cfg, stopCh: make(chan struct{})}, nil
+}
+
+// Watch invokes callback with the new config whenever the key's content changes
+func (w *RedisWatcher) Watch(ctx context.Context, callback func(map[string]interface{})) error {
+	last, _, err := loadRedis(ctx, w.config)
+	if err != nil {
+		return fmt.Errorf("failed to get initial config: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-w.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// reload calls back if the content differs from the last seen version
+	reload := func() {
+		raw, config, err := loadRedis(ctx, w.config)
+		if err != nil || bytes.Equal(raw, last) {
+			return
+		}
+		last = raw
+		callback(config)
+	}
+
+	go func() {
+		defer cancel()
+		for ctx.Err() == nil {
+			if w.config.KeyspaceNotifications {
+				// Returns on connection loss; reload in case events were missed
+				w.subscribe(ctx, reload)
+				reload()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.config.PollInterval):
+				reload()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// subscribe delivers keyspace events for the key until the connection fails or ctx ends
+func (w *RedisWatcher) subscribe(ctx context.Context, onEvent func()) {
+	conn, err := dialRedis(ctx, w.config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	channel := fmt.Sprintf("__keyspace@%d__:%s", w.config.DB, w.config.Key)
+	if _, err := conn.do("SUBSCRIBE", channel); err != nil {
+		return
+	}
+
+	conn.blocking = true
+	for {
+		reply, err := conn.read()
+		if err != nil {
+			return
+		}
+		if msg, ok := reply.([]interface{}); ok && len(msg) == 3 {
+			if kind, _ := msg[0].([]byte); string(kind) == "message" {
+				onEvent()
+			}
+		}
+	}
+}
+
+// Stop halts the watcher
+func (w *RedisWatcher) Stop() error {
+	w.once.Do(func() { close(w.stopCh) })
+	return nil
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// respConn is a minimal RESP2 client connection
+type respConn struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	timeout  time.Duration
+	blocking bool // Subscribed connections wait for messages without a deadline
+}
+
+// dialRedis connects, authenticates and selects the database
+func dialRedis(ctx context.Context, cfg RedisConfig) (*respConn, error) {
+	dialer := net.Dialer{Timeout: cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	c := &respConn{conn: conn, r: bufio.NewReader(conn), timeout: cfg.Timeout}
+
+	if cfg.Password != "" {
+		args := []string{"AUTH", cfg.Password}
+		if cfg.Username != "" {
+			args = []string{"AUTH", cfg.Username, cfg.Password}
+		}
+		if _, err := c.do(args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis auth failed: %w", err)
+		}
+	}
+	if cfg.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(cfg.DB)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis select failed: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// do sends a command and reads its reply
+func (c *respConn) do(args ...string) (interface{}, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// read reads one reply: []byte or nil for bulk strings, string for simple strings,
+// int64 for integers and []interface{} for arrays
+func (c *respConn) read() (interface{}, error) {
+	if c.blocking {
+		c.conn.SetReadDeadline(time.Time{})
+	} else {
+		c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+	}
+	return readRESP(c.r)
+}
+
+func readRESP(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply")
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRESP(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type %q", kind)
+	}
+}
+
+// Close closes the connection
+func (c *respConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a tiny RESP server holding strings and hashes in DB 0
+type fakeRedis struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	strings     map[string]string
+	hashes      map[string][][2]string
+	subscribers map[string][]net.Conn
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Loopback listener unavailable: %v", err)
+	}
+	f := &fakeRedis{
+		listener:    listener,
+		strings:     make(map[string]string),
+		hashes:      make(map[string][][2]string),
+		subscribers: make(map[string][]net.Conn),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		reply, err := readRESP(r)
+		if err != nil {
+			return
+		}
+		items := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i] = string(item.([]byte))
+		}
+
+		f.mu.Lock()
+		switch args[0] {
+		case "AUTH":
+			if args[len(args)-1] == "secret" {
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "GET":
+			if v, ok := f.strings[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "HGETALL":
+			fields := f.hashes[args[1]]
+			fmt.Fprintf(conn, "*%d\r\n", len(fields)*2)
+			for _, kv := range fields {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n$%d\r\n%s\r\n", len(kv[0]), kv[0], len(kv[1]), kv[1])
+			}
+		case "SUBSCRIBE":
+			f.subscribers[args[1]] = append(f.subscribers[args[1]], conn)
+			fmt.Fprintf(conn, "*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(args[1]), args[1])
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		f.mu.Unlock()
+	}
+}
+
+// set stores a string and emits a keyspace event like notify-keyspace-events "K$"
+func (f *fakeRedis) set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.strings[key] = value
+
+	channel := "__keyspace@0__:" + key
+	for _, conn := range f.subscribers[channel] {
+		fmt.Fprintf(conn, "*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$3\r\nset\r\n", len(channel), channel)
+	}
+}
+
+func TestRedisProvider_Load(t *testing.T) {
+	redis := newFakeRedis(t)
+	redis.set("config/eir", `{"server": {"port": 8080}}`)
+	redis.hashes["config/eir-hash"] = [][2]string{{"server.port", "8080"}, {"server.host", "eir-1"}}
+
+	provider, err := NewRedisProvider(RedisConfig{Address: redis.listener.Addr().String(), Password: "secret", Key: "config/eir"})
+	if err != nil {
+		t.Fatalf("NewRedisProvider failed: %v", err)
+	}
+	data, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if data["server"].(map[string]interface{})["port"] != float64(8080) {
+		t.Errorf("Expected port 8080, got %v", data)
+	}
+
+	hashProvider, _ := NewRedisProvider(RedisConfig{Address: redis.listener.Addr().String(), Key: "config/eir-hash", Hash: true})
+	data, err = hashProvider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Hash load failed: %v", err)
+	}
+	server := data["server"].(map[string]interface{})
+	if server["port"] != float64(8080) || server["host"] != "eir-1" {
+		t.Errorf("Expected nested hash config, got %v", data)
+	}
+
+	missing, _ := NewRedisProvider(RedisConfig{Address: redis.listener.Addr().String(), Key: "nope"})
+	if _, err := missing.Load(context.Background()); err == nil {
+		t.Error("Expected error for a missing key")
+	}
+
+	badAuth, _ := NewRedisProvider(RedisConfig{Address: redis.listener.Addr().String(), Password: "wrong", Key: "config/eir"})
+	if _, err := badAuth.Load(context.Background()); err == nil {
+		t.Error("Expected auth error")
+	}
+}
+
+func TestRedisWatcher(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RedisConfig
+	}{
+		{"keyspace notifications", RedisConfig{KeyspaceNotifications: true, PollInterval: time.Hour}},
+		{"polling", RedisConfig{PollInterval: 20 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redis := newFakeRedis(t)
+			redis.set("config/eir", `{"log_level": "info"}`)
+
+			cfg := tt.cfg
+			cfg.Address = redis.listener.Addr().String()
+			cfg.Key = "config/eir"
+			watcher, err := NewRedisWatcher(cfg)
+			if err != nil {
+				t.Fatalf("NewRedisWatcher failed: %v", err)
+			}
+			defer watcher.Stop()
+
+			updates := make(chan map[string]interface{}, 4)
+			if err := watcher.Watch(context.Background(), func(c map[string]interface{}) { updates <- c }); err != nil {
+				t.Fatalf("Watch failed: %v", err)
+			}
+
+			// Wait for the subscription before publishing
+			for i := 0; cfg.KeyspaceNotifications && i < 100; i++ {
+				redis.mu.Lock()
+				n := len(redis.subscribers["__keyspace@0__:config/eir"])
+				redis.mu.Unlock()
+				if n > 0 {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			redis.set("config/eir", `{"log_level": "debug"}`)
+			select {
+			case update := <-updates:
+				if update["log_level"] != "debug" {
+					t.Errorf("Expected debug, got %v", update)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for config change")
+			}
+		})
+	}
+}
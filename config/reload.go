@@ -0,0 +1,77 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// reloadCoalescer collapses bursts of change events into a single reload
+// The reload fires after quiet has passed without a new event, and never
+// more often than once per minInterval
+type reloadCoalescer struct {
+	quiet       time.Duration
+	minInterval time.Duration
+	reload      func()
+
+	mu         sync.Mutex
+	timer      *time.Timer
+	lastReload time.Time
+	stopped    bool
+}
+
+// newReloadCoalescer creates a coalescer that calls reload after events settle
+func newReloadCoalescer(quiet, minInterval time.Duration, reload func()) *reloadCoalescer {
+	return &reloadCoalescer{
+		quiet:       quiet,
+		minInterval: minInterval,
+		reload:      reload,
+	}
+}
+
+// trigger records a change event and (re)schedules the reload
+func (c *reloadCoalescer) trigger() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return
+	}
+
+	delay := c.quiet
+	if c.minInterval > 0 && !c.lastReload.IsZero() {
+		if wait := time.Until(c.lastReload.Add(c.minInterval)); wait > delay {
+			delay = wait
+		}
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.AfterFunc(delay, c.fire)
+}
+
+// fire runs the reload unless the coalescer was stopped
+func (c *reloadCoalescer) fire() {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		return
+	}
+	c.timer = nil
+	c.lastReload = time.Now()
+	c.mu.Unlock()
+
+	c.reload()
+}
+
+// stop cancels any pending reload
+func (c *reloadCoalescer) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopped = true
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
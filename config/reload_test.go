@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockWatcher lets tests emit change events
+type mockWatcher struct {
+	mu       sync.Mutex
+	callback func(map[string]interface{})
+}
+
+func (w *mockWatcher) Watch(ctx context.Context, callback func(map[string]interface{})) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+func (w *mockWatcher) Stop() error {
+	return nil
+}
+
+func (w *mockWatcher) emit(data map[string]interface{}) {
+	w.mu.Lock()
+	callback := w.callback
+	w.mu.Unlock()
+	callback(data)
+}
+
+func TestManager_Watch_Coalesces(t *testing.T) {
+	watcher := &mockWatcher{}
+	manager := NewManager(ManagerConfig{
+		Providers:         []Provider{NewMockProvider("test", map[string]interface{}{"key": "value"})},
+		Watcher:           watcher,
+		ReloadQuietPeriod: 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	if err := manager.Watch(ctx, func(map[string]interface{}) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		watcher.emit(map[string]interface{}{"key": i})
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("callback called %d times, want 1", got)
+	}
+	if v, _ := manager.GetString("key"); v != "value" {
+		t.Errorf("current key = %q, want merged state from providers", v)
+	}
+}
+
+func TestManager_Watch_RateLimit(t *testing.T) {
+	watcher := &mockWatcher{}
+	manager := NewManager(ManagerConfig{
+		Providers:         []Provider{NewMockProvider("test", map[string]interface{}{})},
+		Watcher:           watcher,
+		MinReloadInterval: 200 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	if err := manager.Watch(ctx, func(map[string]interface{}) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	watcher.emit(nil)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("first reload: callback called %d times, want 1", got)
+	}
+
+	watcher.emit(nil)
+	watcher.emit(nil)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("within interval: callback called %d times, want 1", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("after interval: callback called %d times, want 2", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/consul/api"
@@ -29,6 +30,10 @@ type RemoteProviderConfig struct {
 	// Key path in the remote store
 	Key string
 
+	// Prefix loads every key under Key as a config tree instead of a single JSON document
+	// Example: eir/server/port=8080 with Key "eir/" -> {"server": {"port": 8080}}
+	Prefix bool
+
 	// Timeout for operations
 	Timeout time.Duration
 
@@ -86,41 +91,88 @@ func NewConsulProvider(cfg RemoteProviderConfig) (*ConsulProvider, error) {
 func (c *ConsulProvider) Load(ctx context.Context) (map[string]interface{}, error) {
 	kv := c.client.KV()
 
-	var lastErr error
-	retries := 0
-	wait := c.config.RetryConfig.InitialWait
-
-	for retries <= c.config.RetryConfig.MaxRetries {
-		pair, _, err := kv.Get(c.key, &api.QueryOptions{})
+	if c.config.Prefix {
+		var pairs api.KVPairs
+		err := c.withRetry(func() error {
+			var err error
+			pairs, _, err = kv.List(c.key, &api.QueryOptions{})
+			return err
+		})
 		if err != nil {
-			lastErr = err
-			retries++
+			return nil, err
+		}
 
-			if retries > c.config.RetryConfig.MaxRetries {
-				break
-			}
+		return consulPairsToMap(c.key, pairs), nil
+	}
 
-			time.Sleep(wait)
-			wait = time.Duration(float64(wait) * c.config.RetryConfig.Multiplier)
-			if wait > c.config.RetryConfig.MaxWait {
-				wait = c.config.RetryConfig.MaxWait
-			}
-			continue
-		}
+	var pair *api.KVPair
+	err := c.withRetry(func() error {
+		var err error
+		pair, _, err = kv.Get(c.key, &api.QueryOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if pair == nil {
-			return nil, fmt.Errorf("key not found: %s", c.key)
-		}
+	if pair == nil {
+		return nil, fmt.Errorf("key not found: %s", c.key)
+	}
 
-		var result map[string]interface{}
-		if err := json.Unmarshal(pair.Value, &result); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-		}
+	var result map[string]interface{}
+	if err := json.Unmarshal(pair.Value, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
 
-		return result, nil
+	return result, nil
+}
+
+// LoadSubtree lists only the keys under prefix (e.g. "stats_export" -> "eir/stats_export/")
+// in Prefix mode; a single JSON document is loaded whole
+func (c *ConsulProvider) LoadSubtree(ctx context.Context, prefix string) (map[string]interface{}, error) {
+	if !c.config.Prefix {
+		return c.Load(ctx)
+	}
+
+	key := strings.ReplaceAll(prefix, KeySeparator, "/") + "/"
+	if base := strings.TrimSuffix(c.key, "/"); base != "" {
+		key = base + "/" + key
 	}
+	var pairs api.KVPairs
+	err := c.withRetry(func() error {
+		var err error
+		pairs, _, err = c.client.KV().List(key, (&api.QueryOptions{}).WithContext(ctx))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consulPairsToMap(c.key, pairs), nil
+}
 
-	return nil, fmt.Errorf("failed to load config after %d retries: %w", retries, lastErr)
+// withRetry runs fn, retrying failures with exponential backoff per the retry config
+func (c *ConsulProvider) withRetry(fn func() error) error {
+	return retryWithBackoff(c.config.RetryConfig, fn)
+}
+
+// consulPairsToMap converts the KV pairs under prefix into a nested config map
+// Key segments separated by "/" become nesting levels; values are decoded as JSON when possible
+func consulPairsToMap(prefix string, pairs api.KVPairs) map[string]interface{} {
+	flat := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		key := strings.Trim(strings.TrimPrefix(pair.Key, prefix), "/")
+		if key == "" || strings.HasSuffix(pair.Key, "/") {
+			continue // Skip the prefix itself and folder entries
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(pair.Value, &value); err != nil {
+			value = string(pair.Value)
+		}
+		flat[strings.ReplaceAll(key, "/", KeySeparator)] = value
+	}
+	return Expand(flat)
 }
 
 // Name returns the provider name
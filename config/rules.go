@@ -0,0 +1,302 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rule is a constraint between configuration keys or sections, checked by a
+// RuleValidator against the merged configuration
+// Keys are dot-separated paths (e.g. "tls.cert_file"); a key is set when present
+// and not empty, zero or false ("false" and "0" strings count as false, as
+// provided by environment variables)
+type Rule func(config map[string]interface{}) []ValidationError
+
+// RuleValidator checks section-level rules, such as one enabled provider or files
+// required by a flag, reporting every violation at once
+// Combine it with a StructValidator through NewChainValidator
+type RuleValidator struct {
+	rules []Rule
+}
+
+// NewRuleValidator creates a validator checking rules
+func NewRuleValidator(rules ...Rule) *RuleValidator {
+	return &RuleValidator{rules: rules}
+}
+
+// Validate checks every rule, returning the violations as ValidationErrors
+func (rv *RuleValidator) Validate(config interface{}) error {
+	m, ok := config.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("rule validation requires a config map, got %T", config)
+	}
+
+	var errors ValidationErrors
+	for _, rule := range rv.rules {
+		errors = append(errors, rule(m)...)
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// ExactlyOneOf requires exactly one of keys to be set, e.g.
+// ExactlyOneOf("consul.enabled", "etcd.enabled", "file.enabled")
+func ExactlyOneOf(keys ...string) Rule {
+	return groupRule("exactly_one_of", keys)
+}
+
+// AtMostOneOf allows at most one of keys to be set
+func AtMostOneOf(keys ...string) Rule {
+	return groupRule("at_most_one_of", keys)
+}
+
+// AtLeastOneOf requires at least one of keys to be set
+func AtLeastOneOf(keys ...string) Rule {
+	return groupRule("at_least_one_of", keys)
+}
+
+// groupRule checks how many of keys are set
+func groupRule(name string, keys []string) Rule {
+	return func(config map[string]interface{}) []ValidationError {
+		var set []string
+		for _, key := range keys {
+			if isSetKey(config, key) {
+				set = append(set, key)
+			}
+		}
+		if msg := groupViolation(name, keys, set); msg != "" {
+			return []ValidationError{{Field: strings.Join(keys, "|"), Message: msg}}
+		}
+		return nil
+	}
+}
+
+// RequiredWhen requires keys to be set when condition is set, e.g.
+// RequiredWhen("tls.enabled", "tls.cert_file", "tls.key_file")
+func RequiredWhen(condition string, keys ...string) Rule {
+	return func(config map[string]interface{}) []ValidationError {
+		if !isSetKey(config, condition) {
+			return nil
+		}
+		var errors []ValidationError
+		for _, key := range keys {
+			if !isSetKey(config, key) {
+				errors = append(errors, ValidationError{Field: key, Message: fmt.Sprintf("required when %s is set", condition)})
+			}
+		}
+		return errors
+	}
+}
+
+// RequiredIf requires keys to be set when key has value, compared as text, e.g.
+// RequiredIf("mode", "cluster", "cluster.peers")
+func RequiredIf(key string, value interface{}, keys ...string) Rule {
+	want := fmt.Sprint(value)
+	return func(config map[string]interface{}) []ValidationError {
+		if v, ok := lookupPath(config, key); !ok || fmt.Sprint(v) != want {
+			return nil
+		}
+		var errors []ValidationError
+		for _, required := range keys {
+			if !isSetKey(config, required) {
+				errors = append(errors, ValidationError{Field: required, Message: fmt.Sprintf("required when %s is %s", key, want)})
+			}
+		}
+		return errors
+	}
+}
+
+// ExcludedWhen requires keys to be empty when condition is set, e.g.
+// ExcludedWhen("tls.insecure_skip_verify", "tls.ca_file")
+func ExcludedWhen(condition string, keys ...string) Rule {
+	return func(config map[string]interface{}) []ValidationError {
+		if !isSetKey(config, condition) {
+			return nil
+		}
+		var errors []ValidationError
+		for _, key := range keys {
+			if isSetKey(config, key) {
+				errors = append(errors, ValidationError{Field: key, Message: fmt.Sprintf("must not be set when %s is set", condition)})
+			}
+		}
+		return errors
+	}
+}
+
+// isSetKey reports whether the value at key is present and not empty, zero or false
+func isSetKey(config map[string]interface{}, key string) bool {
+	v, ok := lookupPath(config, key)
+	if !ok || v == nil {
+		return false
+	}
+
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		value = strings.TrimSpace(value)
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		return value != ""
+	case json.Number:
+		f, err := value.Float64()
+		return err != nil || f != 0
+	}
+	return !isZeroValue(reflect.ValueOf(v))
+}
+
+// groupViolation describes how set, the members of group that are set, breaks
+// the group rule, "" if it doesn't
+func groupViolation(rule string, group, set []string) string {
+	var ok bool
+	var quantity string
+	switch rule {
+	case "exactly_one_of":
+		ok, quantity = len(set) == 1, "exactly one"
+	case "at_most_one_of":
+		ok, quantity = len(set) <= 1, "at most one"
+	case "at_least_one_of":
+		ok, quantity = len(set) >= 1, "at least one"
+	}
+	if ok {
+		return ""
+	}
+
+	msg := fmt.Sprintf("%s of %s must be set", quantity, strings.Join(group, ", "))
+	if len(set) == 0 {
+		return msg + ", got none"
+	}
+	return fmt.Sprintf("%s, got %d: %s", msg, len(set), strings.Join(set, ", "))
+}
+
+// conditionalRules are the validate tag rules that depend on sibling fields
+var conditionalRules = map[string]bool{"required_if": true, "required_with": true, "excluded_with": true}
+
+// splitConditionalRules separates conditional rules from the others
+func splitConditionalRules(rules []string) (rest, conditional []string) {
+	for _, rule := range rules {
+		name, _, _ := strings.Cut(rule, "=")
+		if conditionalRules[name] {
+			conditional = append(conditional, rule)
+		} else {
+			rest = append(rest, rule)
+		}
+	}
+	return rest, conditional
+}
+
+// validateConditional applies a conditional rule to field, whose siblings are the
+// fields of parent
+func (sv *StructValidator) validateConditional(parent, field reflect.Value, fieldName, rule string) ValidationError {
+	name, value, _ := strings.Cut(rule, "=")
+	args := strings.Fields(value)
+
+	switch name {
+	case "required_if":
+		if len(args) == 0 || len(args)%2 != 0 {
+			return ValidationError{Field: fieldName, Message: fmt.Sprintf("invalid rule %q: expects field value pairs", rule)}
+		}
+		var conditions []string
+		for i := 0; i < len(args); i += 2 {
+			sibling, ok := fieldByPath(parent, args[i])
+			if !ok {
+				return ValidationError{Field: fieldName, Message: fmt.Sprintf("invalid rule %q: unknown field %s", rule, args[i])}
+			}
+			if fmt.Sprintf("%v", sibling.Interface()) != args[i+1] {
+				return ValidationError{}
+			}
+			conditions = append(conditions, args[i]+" is "+args[i+1])
+		}
+		if !isSetField(field) {
+			return ValidationError{Field: fieldName, Message: "required when " + strings.Join(conditions, " and ")}
+		}
+
+	case "required_with", "excluded_with":
+		for _, path := range args {
+			sibling, ok := fieldByPath(parent, path)
+			if !ok {
+				return ValidationError{Field: fieldName, Message: fmt.Sprintf("invalid rule %q: unknown field %s", rule, path)}
+			}
+			if !isSetField(sibling) {
+				continue
+			}
+			if name == "required_with" && !isSetField(field) {
+				return ValidationError{Field: fieldName, Message: fmt.Sprintf("required when %s is set", path)}
+			}
+			if name == "excluded_with" && isSetField(field) {
+				return ValidationError{Field: fieldName, Message: fmt.Sprintf("must not be set when %s is set", path)}
+			}
+		}
+	}
+
+	return ValidationError{}
+}
+
+// validateGroup checks how many of the named fields of a struct field are set
+func (sv *StructValidator) validateGroup(field reflect.Value, fieldName, rule, members string) ValidationError {
+	for field.Kind() == reflect.Ptr && !field.IsNil() {
+		field = field.Elem()
+	}
+
+	group := strings.Fields(members)
+	var set []string
+	for _, path := range group {
+		if field.Kind() == reflect.Ptr {
+			break // nil: no member is set
+		}
+		member, ok := fieldByPath(field, path)
+		if !ok {
+			return ValidationError{Field: fieldName, Message: fmt.Sprintf("invalid rule %s: unknown field %s", rule, path)}
+		}
+		if isSetField(member) {
+			set = append(set, path)
+		}
+	}
+
+	if msg := groupViolation(rule, group, set); msg != "" {
+		return ValidationError{Field: fieldName, Message: msg}
+	}
+	return ValidationError{}
+}
+
+// fieldByPath returns the field of struct v at a "."-separated path of Go field
+// names; a nil pointer on the way yields the zero value of the field
+func fieldByPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v = reflect.Zero(v.Type().Elem())
+			} else {
+				v = v.Elem()
+			}
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		field, ok := v.Type().FieldByName(name)
+		if !ok || !field.IsExported() {
+			return reflect.Value{}, false
+		}
+		next, err := v.FieldByIndexErr(field.Index)
+		if err != nil {
+			next = reflect.Zero(field.Type) // Through a nil embedded pointer
+		}
+		v = next
+	}
+	return v, true
+}
+
+// isSetField reports whether a field is set: not its zero value, and for structs
+// any field set
+func isSetField(v reflect.Value) bool {
+	if v.Kind() == reflect.Struct {
+		return !v.IsZero()
+	}
+	return !isZeroValue(v)
+}
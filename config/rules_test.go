@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStructValidator_SectionRules(t *testing.T) {
+	type Provider struct {
+		Enabled bool
+		Address string `validate:"required_if=Enabled true"`
+	}
+	type TLS struct {
+		Enabled            bool
+		CertFile           string `validate:"required_if=Enabled true"`
+		KeyFile            string `validate:"required_with=CertFile"`
+		CAFile             string `validate:"excluded_with=InsecureSkipVerify"`
+		InsecureSkipVerify bool
+	}
+	type Providers struct {
+		Consul Provider
+		Etcd   Provider
+		File   Provider
+	}
+	type Config struct {
+		Providers Providers `validate:"exactly_one_of=Consul.Enabled Etcd.Enabled File.Enabled"`
+		TLS       TLS
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   []string // Expected fields in error order, none if valid
+	}{
+		{
+			name: "valid",
+			config: map[string]interface{}{
+				"providers": map[string]interface{}{"consul": map[string]interface{}{"enabled": true, "address": "consul:8500"}},
+				"tls":       map[string]interface{}{"enabled": true, "certfile": "c.pem", "keyfile": "k.pem"},
+			},
+		},
+		{
+			name: "no provider",
+			config: map[string]interface{}{
+				"tls": map[string]interface{}{"enabled": false},
+			},
+			want: []string{"Providers"},
+		},
+		{
+			name: "every violation reported",
+			config: map[string]interface{}{
+				"providers": map[string]interface{}{
+					"consul": map[string]interface{}{"enabled": true},
+					"file":   map[string]interface{}{"enabled": true, "address": "/etc/eir.yaml"},
+				},
+				"tls": map[string]interface{}{"enabled": true, "cafile": "ca.pem", "insecureskipverify": true},
+			},
+			want: []string{"Providers", "Providers.Consul.Address", "TLS.CertFile", "TLS.CAFile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewStructValidator(&Config{}).Validate(tt.config)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+			var got []string
+			for _, e := range verrs {
+				got = append(got, e.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected errors on %v, got %v", tt.want, verrs)
+			}
+		})
+	}
+
+	err := NewStructValidator(&Config{}).Validate(map[string]interface{}{
+		"providers": map[string]interface{}{
+			"consul": map[string]interface{}{"enabled": true, "address": "a"},
+			"etcd":   map[string]interface{}{"enabled": true, "address": "b"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "exactly one of Consul.Enabled, Etcd.Enabled, File.Enabled must be set, got 2: Consul.Enabled, Etcd.Enabled") {
+		t.Errorf("Expected the set members in the error, got %v", err)
+	}
+}
+
+func TestStructValidator_SectionRules_UnknownField(t *testing.T) {
+	type Config struct {
+		CertFile string `validate:"required_if=Enabeld true"`
+	}
+	err := NewStructValidator(&Config{}).Validate(map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "unknown field Enabeld") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+}
+
+func TestRuleValidator(t *testing.T) {
+	validator := NewRuleValidator(
+		ExactlyOneOf("consul.enabled", "etcd.enabled", "file.enabled"),
+		RequiredWhen("tls.enabled", "tls.cert_file", "tls.key_file"),
+		RequiredIf("mode", "cluster", "cluster.peers"),
+		ExcludedWhen("tls.insecure_skip_verify", "tls.ca_file"),
+	)
+
+	valid := map[string]interface{}{
+		"consul": map[string]interface{}{"enabled": true},
+		"etcd":   map[string]interface{}{"enabled": "false"}, // From the environment
+		"tls":    map[string]interface{}{"enabled": "true", "cert_file": "c.pem", "key_file": "k.pem"},
+		"mode":   "standalone",
+	}
+	if err := validator.Validate(valid); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := map[string]interface{}{
+		"consul":  map[string]interface{}{"enabled": true},
+		"file":    map[string]interface{}{"enabled": "1"},
+		"tls":     map[string]interface{}{"enabled": true, "key_file": "k.pem", "insecure_skip_verify": true, "ca_file": "ca.pem"},
+		"mode":    "cluster",
+		"cluster": map[string]interface{}{"peers": []interface{}{}},
+	}
+	err := validator.Validate(invalid)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	want := []string{"consul.enabled|etcd.enabled|file.enabled", "tls.cert_file", "cluster.peers", "tls.ca_file"}
+	if len(verrs) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), verrs)
+	}
+	for i, field := range want {
+		if verrs[i].Field != field {
+			t.Errorf("Error %d: expected field %s, got %s (%s)", i, field, verrs[i].Field, verrs[i].Message)
+		}
+	}
+	if !strings.Contains(verrs[1].Message, "required when tls.enabled is set") {
+		t.Errorf("Expected the condition in the message, got %q", verrs[1].Message)
+	}
+
+	// Rule violations combine with struct validation errors
+	chain := NewChainValidator(validator, NewFuncValidator(func(interface{}) error {
+		return ValidationErrors{{Field: "port", Message: "field is required"}}
+	}))
+	if err := chain.Validate(invalid); err == nil || len(err.(ValidationErrors)) != len(want)+1 {
+		t.Errorf("Expected all errors from the chain, got %v", err)
+	}
+}
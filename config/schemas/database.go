@@ -0,0 +1,17 @@
+package schemas
+
+// DatabasePool configures a database connection pool
+type DatabasePool struct {
+	Driver   string `json:"driver" yaml:"driver" env:"DRIVER" envDefault:"postgres" validate:"oneof=postgres mysql" desc:"Database driver"`
+	Host     string `json:"host" yaml:"host" env:"HOST" validate:"required" desc:"Database host"`
+	Port     int    `json:"port" yaml:"port" env:"PORT" envDefault:"5432" validate:"min=1,max=65535" desc:"Database port"`
+	Name     string `json:"name" yaml:"name" env:"NAME" validate:"required" desc:"Database name"`
+	User     string `json:"user" yaml:"user" env:"USER" validate:"required" desc:"Database user"`
+	Password string `json:"password" yaml:"password" env:"PASSWORD" desc:"Database password"`
+	SSLMode  string `json:"ssl_mode" yaml:"ssl_mode" env:"SSL_MODE" envDefault:"disable" validate:"oneof=disable require verify-ca verify-full" desc:"PostgreSQL sslmode"`
+
+	MaxOpenConns       int `json:"max_open_conns" yaml:"max_open_conns" env:"MAX_OPEN_CONNS" envDefault:"25" validate:"min=1" desc:"Maximum open connections"`
+	MaxIdleConns       int `json:"max_idle_conns" yaml:"max_idle_conns" env:"MAX_IDLE_CONNS" envDefault:"5" validate:"min=0" desc:"Maximum idle connections"`
+	ConnMaxLifetimeSec int `json:"conn_max_lifetime_sec" yaml:"conn_max_lifetime_sec" env:"CONN_MAX_LIFETIME_SEC" envDefault:"300" validate:"min=0" desc:"Seconds before a connection is recycled (0 = never)"`
+	QueryTimeoutMs     int `json:"query_timeout_ms" yaml:"query_timeout_ms" env:"QUERY_TIMEOUT_MS" envDefault:"5000" validate:"min=1" desc:"Query timeout in milliseconds"`
+}
@@ -0,0 +1,36 @@
+package schemas
+
+// DiameterPeer configures a Diameter peer connection
+type DiameterPeer struct {
+	Host        string `json:"host" yaml:"host" env:"HOST" validate:"required" desc:"Peer host name or address"`
+	Port        int    `json:"port" yaml:"port" env:"PORT" envDefault:"3868" validate:"min=1,max=65535" desc:"Peer Diameter port"`
+	OriginHost  string `json:"origin_host" yaml:"origin_host" env:"ORIGIN_HOST" validate:"required" desc:"Origin-Host sent to the peer"`
+	OriginRealm string `json:"origin_realm" yaml:"origin_realm" env:"ORIGIN_REALM" validate:"required" desc:"Origin-Realm sent to the peer"`
+	Transport   string `json:"transport" yaml:"transport" env:"TRANSPORT" envDefault:"sctp" validate:"oneof=sctp tcp tls" desc:"Transport protocol"`
+	Priority    int    `json:"priority" yaml:"priority" env:"PRIORITY" validate:"min=0" desc:"Peer selection priority (lower is preferred)"`
+
+	// WatchdogIntervalSec is the Device-Watchdog interval Tw (RFC 3539 requires at least 6s)
+	WatchdogIntervalSec int `json:"watchdog_interval_sec" yaml:"watchdog_interval_sec" env:"WATCHDOG_INTERVAL_SEC" envDefault:"30" validate:"min=6" desc:"Device-Watchdog interval Tw in seconds"`
+
+	// ReconnectIntervalSec is the delay before reconnecting after a disconnect
+	ReconnectIntervalSec int `json:"reconnect_interval_sec" yaml:"reconnect_interval_sec" env:"RECONNECT_INTERVAL_SEC" envDefault:"5" validate:"min=1" desc:"Seconds to wait before reconnecting"`
+
+	TLS TLS `json:"tls" yaml:"tls" env:"TLS" desc:"TLS settings for the peer connection"`
+}
+
+// SCTPTransport configures an SCTP endpoint (RFC 4960 protocol parameters)
+type SCTPTransport struct {
+	// Addresses are the local addresses for multi-homing; the first is the primary path (not bound from env)
+	Addresses []string `json:"addresses" yaml:"addresses" env:"-" desc:"Local addresses for multi-homing, primary path first"`
+	Port      int      `json:"port" yaml:"port" env:"PORT" envDefault:"3868" validate:"min=1,max=65535" desc:"Local SCTP port"`
+
+	OutStreams int `json:"out_streams" yaml:"out_streams" env:"OUT_STREAMS" envDefault:"10" validate:"min=1,max=65535" desc:"Requested outbound streams"`
+	InStreams  int `json:"in_streams" yaml:"in_streams" env:"IN_STREAMS" envDefault:"10" validate:"min=1,max=65535" desc:"Maximum inbound streams"`
+
+	HeartbeatIntervalMs int `json:"heartbeat_interval_ms" yaml:"heartbeat_interval_ms" env:"HEARTBEAT_INTERVAL_MS" envDefault:"30000" validate:"min=0" desc:"Path heartbeat interval in milliseconds (0 disables)"`
+	RTOInitialMs        int `json:"rto_initial_ms" yaml:"rto_initial_ms" env:"RTO_INITIAL_MS" envDefault:"3000" validate:"min=1" desc:"Initial retransmission timeout in milliseconds"`
+	RTOMinMs            int `json:"rto_min_ms" yaml:"rto_min_ms" env:"RTO_MIN_MS" envDefault:"1000" validate:"min=1" desc:"Minimum retransmission timeout in milliseconds"`
+	RTOMaxMs            int `json:"rto_max_ms" yaml:"rto_max_ms" env:"RTO_MAX_MS" envDefault:"60000" validate:"min=1" desc:"Maximum retransmission timeout in milliseconds"`
+	MaxInitRetransmits  int `json:"max_init_retransmits" yaml:"max_init_retransmits" env:"MAX_INIT_RETRANSMITS" envDefault:"8" validate:"min=1" desc:"INIT retransmissions before the association fails"`
+	PathMaxRetransmits  int `json:"path_max_retransmits" yaml:"path_max_retransmits" env:"PATH_MAX_RETRANSMITS" envDefault:"5" validate:"min=1" desc:"Retransmissions before a path is marked inactive"`
+}
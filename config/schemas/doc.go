@@ -0,0 +1,11 @@
+// Package schemas provides reusable configuration structs for common telco service blocks
+//
+// Each struct carries tags for:
+//   - json/yaml: file decoding (snake_case keys)
+//   - env/envDefault: binding with config.BindEnv
+//   - validate: checks with config.StructValidator
+//   - desc: the key description in config.KeyCatalog documentation
+//
+// Durations are expressed as integer fields with an explicit unit suffix (Sec, Ms)
+// so they round-trip through env variables, YAML and JSON without custom parsing.
+package schemas
@@ -0,0 +1,26 @@
+package schemas
+
+// TLS configures transport security for a server or client
+type TLS struct {
+	Enabled            bool   `json:"enabled" yaml:"enabled" env:"ENABLED" desc:"Enable TLS"`
+	CertFile           string `json:"cert_file" yaml:"cert_file" env:"CERT_FILE" desc:"PEM certificate file"`
+	KeyFile            string `json:"key_file" yaml:"key_file" env:"KEY_FILE" desc:"PEM private key file"`
+	CAFile             string `json:"ca_file" yaml:"ca_file" env:"CA_FILE" desc:"PEM CA bundle for verifying peers"`
+	MinVersion         string `json:"min_version" yaml:"min_version" env:"MIN_VERSION" envDefault:"1.2" validate:"oneof=1.2 1.3" desc:"Minimum TLS version"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" yaml:"insecure_skip_verify" env:"INSECURE_SKIP_VERIFY" desc:"Skip peer certificate verification (testing only)"`
+}
+
+// HTTPServer configures an HTTP (SBI) server
+type HTTPServer struct {
+	Host            string `json:"host" yaml:"host" env:"HOST" envDefault:"0.0.0.0" desc:"Listen address"`
+	Port            int    `json:"port" yaml:"port" env:"PORT" envDefault:"8080" validate:"required,min=1,max=65535" desc:"Listen port"`
+	ReadTimeoutSec  int    `json:"read_timeout_sec" yaml:"read_timeout_sec" env:"READ_TIMEOUT_SEC" envDefault:"30" validate:"min=0" desc:"Request read timeout in seconds (0 = none)"`
+	WriteTimeoutSec int    `json:"write_timeout_sec" yaml:"write_timeout_sec" env:"WRITE_TIMEOUT_SEC" envDefault:"30" validate:"min=0" desc:"Response write timeout in seconds (0 = none)"`
+	IdleTimeoutSec  int    `json:"idle_timeout_sec" yaml:"idle_timeout_sec" env:"IDLE_TIMEOUT_SEC" envDefault:"120" validate:"min=0" desc:"Keep-alive idle timeout in seconds (0 = none)"`
+	MaxHeaderBytes  int    `json:"max_header_bytes" yaml:"max_header_bytes" env:"MAX_HEADER_BYTES" envDefault:"1048576" validate:"min=1024" desc:"Maximum request header size in bytes"`
+
+	// HTTP2 enables HTTP/2 (required for 5G SBI interfaces)
+	HTTP2 bool `json:"http2" yaml:"http2" env:"HTTP2" envDefault:"true" desc:"Enable HTTP/2"`
+
+	TLS TLS `json:"tls" yaml:"tls" env:"TLS" desc:"TLS settings for the server"`
+}
@@ -0,0 +1,111 @@
+package schemas
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hsdfat/telco/config"
+)
+
+func TestBindEnv_Defaults(t *testing.T) {
+	t.Setenv("TEST_PEER_HOST", "hss.example.com")
+	t.Setenv("TEST_PEER_ORIGIN_HOST", "eir.example.com")
+	t.Setenv("TEST_PEER_ORIGIN_REALM", "example.com")
+	t.Setenv("TEST_PEER_TLS_ENABLED", "true")
+
+	var peer DiameterPeer
+	if err := config.BindEnv(&peer, "TEST_PEER"); err != nil {
+		t.Fatalf("BindEnv failed: %v", err)
+	}
+
+	if peer.Host != "hss.example.com" {
+		t.Errorf("Expected host from env, got %q", peer.Host)
+	}
+	if peer.Port != 3868 {
+		t.Errorf("Expected default port 3868, got %d", peer.Port)
+	}
+	if peer.Transport != "sctp" {
+		t.Errorf("Expected default transport sctp, got %q", peer.Transport)
+	}
+	if peer.WatchdogIntervalSec != 30 {
+		t.Errorf("Expected default watchdog interval 30, got %d", peer.WatchdogIntervalSec)
+	}
+	if !peer.TLS.Enabled || peer.TLS.MinVersion != "1.2" {
+		t.Errorf("Expected nested TLS binding, got %+v", peer.TLS)
+	}
+
+	if err := config.NewStructValidator(&peer).Validate(map[string]interface{}{}); err != nil {
+		t.Errorf("Expected bound peer to validate, got: %v", err)
+	}
+}
+
+func TestDefaults_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		target interface{}
+	}{
+		{"SCTPTransport", &SCTPTransport{}},
+		{"HTTPServer", &HTTPServer{}},
+		{"DatabasePool", &DatabasePool{Host: "db", Name: "eir", User: "eir"}},
+		{"StatsExport", &StatsExport{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.BindEnv(tt.target, "TEST_SCHEMAS_UNSET"); err != nil {
+				t.Fatalf("BindEnv failed: %v", err)
+			}
+			if err := config.NewStructValidator(tt.target).Validate(map[string]interface{}{}); err != nil {
+				t.Errorf("Expected defaults to validate, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_Invalid(t *testing.T) {
+	peer := DiameterPeer{
+		Host:                "hss",
+		Port:                70000,
+		Transport:           "udp",
+		WatchdogIntervalSec: 2,
+		TLS:                 TLS{MinVersion: "1.0"},
+	}
+
+	err := config.NewStructValidator(&peer).Validate(map[string]interface{}{})
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+
+	for _, field := range []string{"Port", "OriginHost", "OriginRealm", "Transport", "WatchdogIntervalSec", "TLS.MinVersion"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Expected error for %s, got: %v", field, err)
+		}
+	}
+}
+
+func TestKeyCatalog_Described(t *testing.T) {
+	catalog := config.NewKeyCatalog()
+	for prefix, schema := range map[string]interface{}{
+		"hss":      DiameterPeer{},
+		"sctp":     SCTPTransport{},
+		"sbi":      HTTPServer{},
+		"database": DatabasePool{},
+		"stats":    StatsExport{},
+	} {
+		if err := catalog.Add(schema, prefix, strings.ToUpper(prefix)); err != nil {
+			t.Fatalf("Add(%s) error = %v", prefix, err)
+		}
+	}
+
+	for _, doc := range catalog.Keys {
+		if doc.Description == "" {
+			t.Errorf("Key %s has no desc tag", doc.Key)
+		}
+	}
+	if doc, ok := catalog.Lookup("hss.tls.min_version"); !ok || doc.Env != "HSS_TLS_MIN_VERSION" || doc.Default != "1.2" {
+		t.Errorf("hss.tls.min_version = %+v, %v", doc, ok)
+	}
+	if doc, ok := catalog.Lookup("database.password"); !ok || !doc.Secret {
+		t.Errorf("Expected database.password to be secret, got %+v", doc)
+	}
+}
@@ -0,0 +1,22 @@
+package schemas
+
+// StatsExport configures periodic stats export (see stats/export)
+type StatsExport struct {
+	Enabled     bool   `json:"enabled" yaml:"enabled" env:"ENABLED" envDefault:"true" desc:"Enable periodic stats export"`
+	IntervalSec int    `json:"interval_sec" yaml:"interval_sec" env:"INTERVAL_SEC" envDefault:"60" validate:"min=1" desc:"Export interval in seconds"`
+	Hostname    string `json:"hostname" yaml:"hostname" env:"HOSTNAME" desc:"Hostname reported in records (auto-detected if empty)"`
+	SystemName  string `json:"system_name" yaml:"system_name" env:"SYSTEM_NAME" desc:"System name reported in records (service name if empty)"`
+
+	// Exporters lists export destinations (not bound from env)
+	Exporters []StatsExporter `json:"exporters" yaml:"exporters" env:"-" desc:"Export destinations"`
+}
+
+// StatsExporter configures a single stats export destination
+type StatsExporter struct {
+	Type    string `json:"type" yaml:"type" validate:"oneof=http postgres file" desc:"Exporter type"`
+	Name    string `json:"name" yaml:"name" validate:"required" desc:"Exporter name"`
+	Enabled bool   `json:"enabled" yaml:"enabled" desc:"Enable the exporter"`
+
+	// Target is the URL (http), connection string (postgres) or path (file)
+	Target string `json:"target" yaml:"target" validate:"required" desc:"URL (http), connection string (postgres) or path (file)"`
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces secret values in change logs
+const RedactedValue = "[REDACTED]"
+
+// credentialSuffixes mark a key as a secret when its last segment ends with one of them
+var credentialSuffixes = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "credentials"}
+
+// ConfigChange describes a leaf key that changed during a reload
+// Old is nil for added keys and New is nil for removed keys
+type ConfigChange struct {
+	Key    string
+	Old    interface{}
+	New    interface{}
+	Secret bool // Old and New are RedactedValue
+}
+
+// secretHandler is a rotation callback registered with OnSecretRotated
+type secretHandler struct {
+	path string
+	fn   func(ctx context.Context, key string) error
+}
+
+// OnSecretRotated registers fn to run when a secret key matching path changes
+// path is a key ("database.password"), a subtree ("database") or a pattern with
+// "*" segments ("peers.*.password"); fn receives the rotated key and reads the new
+// value from the manager
+//
+// When a reload only changes secret keys and every rotated key is handled without
+// error, the reload callback and reload hooks are skipped, so unrelated subsystems
+// (and Bind bindings) are not reloaded. Any other change, an unhandled secret or a
+// failed handler falls back to the full reload.
+func (m *Manager) OnSecretRotated(path string, fn func(ctx context.Context, key string) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secretHandlers = append(m.secretHandlers, secretHandler{path: path, fn: fn})
+}
+
+// IsSecretKey reports whether key holds a credential: either it matches one of the
+// configured SecretKeys or its last segment looks like one ("password", "api_key", ...)
+func (m *Manager) IsSecretKey(key string) bool {
+	for _, pattern := range m.secretKeys {
+		if matchKeyPattern(pattern, key) {
+			return true
+		}
+	}
+	return isCredentialKey(key)
+}
+
+// isCredentialKey reports whether the last segment of key looks like a credential
+func isCredentialKey(key string) bool {
+	name := strings.ToLower(key[strings.LastIndex(key, KeySeparator)+1:])
+	for _, suffix := range credentialSuffixes {
+		if name == suffix || strings.HasSuffix(name, "_"+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffConfig returns the leaf keys that differ between old and new, sorted by key
+// Values of secret keys (see IsSecretKey) are replaced with RedactedValue
+func (m *Manager) DiffConfig(old, new map[string]interface{}) []ConfigChange {
+	before, after := Flatten(old), Flatten(new)
+
+	var changes []ConfigChange
+	for key, value := range after {
+		if prev, ok := before[key]; !ok || !reflect.DeepEqual(prev, value) {
+			changes = append(changes, ConfigChange{Key: key, Old: prev, New: value})
+		}
+	}
+	for key, prev := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, ConfigChange{Key: key, Old: prev})
+		}
+	}
+
+	for i := range changes {
+		if m.IsSecretKey(changes[i].Key) {
+			changes[i].Secret = true
+			changes[i].Old = redact(changes[i].Old)
+			changes[i].New = redact(changes[i].New)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// redact hides a present value
+func redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return RedactedValue
+}
+
+// applyReload logs the changes between old and new, notifies secret rotation
+// handlers and runs the full reload unless only handled secrets changed: the
+// callback, the components (see AddComponent) and the reload hooks
+// It returns the callback's error, else the components' *ApplyError; the
+// components run either way, the reload hooks only if both accepted new and
+// their failures go to OnReloadHookError
+func (m *Manager) applyReload(ctx context.Context, old, new map[string]interface{}, callback func(map[string]interface{}) error) error {
+	changes := m.DiffConfig(old, new)
+	if m.changeLog != nil && len(changes) > 0 {
+		m.changeLog(changes)
+	}
+
+	if m.notifySecretRotations(ctx, changes) {
+		return nil
+	}
+
+	var err error
+	if callback != nil {
+		err = callback(new)
+	}
+	if applyErr := m.applyComponents(ctx, old, new); err == nil {
+		err = applyErr
+	}
+	if err != nil {
+		return err
+	}
+
+	var hookErr *ReloadHookError
+	if errors.As(m.RunReloadHooks(ctx, new), &hookErr) && m.onHookError != nil {
+		m.onHookError(hookErr)
+	}
+	return nil
+}
+
+// notifySecretRotations runs rotation handlers for changed secret keys
+// It returns true when only secrets changed and every one was handled successfully
+func (m *Manager) notifySecretRotations(ctx context.Context, changes []ConfigChange) bool {
+	m.mu.RLock()
+	handlers := append([]secretHandler(nil), m.secretHandlers...)
+	m.mu.RUnlock()
+
+	handledAll := len(changes) > 0
+	for _, change := range changes {
+		if !change.Secret {
+			handledAll = false
+			continue
+		}
+
+		handled := false
+		for _, h := range handlers {
+			if !matchKeyPattern(h.path, change.Key) {
+				continue
+			}
+			if err := h.fn(ctx, change.Key); err != nil {
+				handledAll = false
+				continue
+			}
+			handled = true
+		}
+		if !handled {
+			handledAll = false
+		}
+	}
+	return handledAll
+}
+
+// matchKeyPattern reports whether key equals pattern or lies under it, with "*"
+// matching any single segment
+func matchKeyPattern(pattern, key string) bool {
+	patternParts := strings.Split(pattern, KeySeparator)
+	keyParts := strings.Split(key, KeySeparator)
+	if len(keyParts) < len(patternParts) {
+		return false
+	}
+
+	for i, part := range patternParts {
+		if part != "*" && part != keyParts[i] {
+			return false
+		}
+	}
+	return true
+}
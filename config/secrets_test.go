@@ -0,0 +1,139 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestManager_IsSecretKey(t *testing.T) {
+	manager := NewManager(ManagerConfig{SecretKeys: []string{"peers.*.shared_key", "vault"}})
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"database.password", true},
+		{"database.PASSWORD", true},
+		{"http.auth_token", true},
+		{"stats.api_key", true},
+		{"peers.0.shared_key", true},
+		{"vault.role_id", true},
+		{"database.host", false},
+		{"http.token_ttl", false},
+		{"peers.0.host", false},
+	}
+
+	for _, tt := range tests {
+		if got := manager.IsSecretKey(tt.key); got != tt.want {
+			t.Errorf("IsSecretKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestManager_DiffConfig_RedactsSecrets(t *testing.T) {
+	manager := NewManager(ManagerConfig{})
+
+	changes := manager.DiffConfig(
+		map[string]interface{}{"database": map[string]interface{}{"host": "db-1", "password": "old-secret"}},
+		map[string]interface{}{"database": map[string]interface{}{"host": "db-2", "password": "new-secret"}, "debug": true},
+	)
+
+	if len(changes) != 3 {
+		t.Fatalf("got %d changes, want 3: %+v", len(changes), changes)
+	}
+	if changes[0].Key != "database.host" || changes[0].Old != "db-1" || changes[0].New != "db-2" {
+		t.Errorf("changes[0] = %+v", changes[0])
+	}
+	if !changes[1].Secret || changes[1].Old != RedactedValue || changes[1].New != RedactedValue {
+		t.Errorf("changes[1] = %+v, want redacted password", changes[1])
+	}
+	if changes[2].Key != "debug" || changes[2].Old != nil {
+		t.Errorf("changes[2] = %+v, want added key", changes[2])
+	}
+}
+
+func TestManager_OnSecretRotated(t *testing.T) {
+	base := func(password, host string) map[string]interface{} {
+		return map[string]interface{}{"database": map[string]interface{}{"host": host, "password": password}}
+	}
+
+	watcher := &mockWatcher{}
+	var logged []ConfigChange
+	manager := NewManager(ManagerConfig{
+		Providers: []Provider{NewMockProvider("test", base("p1", "db-1"))},
+		Watcher:   watcher,
+		ChangeLog: func(changes []ConfigChange) { logged = append(logged, changes...) },
+	})
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var rotated []string
+	var rotationErr error
+	manager.OnSecretRotated("database", func(_ context.Context, key string) error {
+		value, _ := manager.GetString(key)
+		rotated = append(rotated, key+"="+value)
+		return rotationErr
+	})
+
+	reloads := 0
+	manager.AddReloadHook(ReloadHook{
+		Name:  "full",
+		Apply: func(context.Context, map[string]interface{}) error { reloads++; return nil },
+	})
+	if err := manager.Watch(context.Background(), nil); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	// Only the password changed: targeted rotation, no full reload
+	watcher.emit(base("p2", "db-1"))
+	if len(rotated) != 1 || rotated[0] != "database.password=p2" {
+		t.Errorf("rotated = %v, want [database.password=p2]", rotated)
+	}
+	if reloads != 0 {
+		t.Errorf("full reloads = %d, want 0 for a secret-only change", reloads)
+	}
+
+	// A non-secret change alongside runs both
+	watcher.emit(base("p3", "db-2"))
+	if len(rotated) != 2 || reloads != 1 {
+		t.Errorf("rotated = %v, reloads = %d; want 2 rotations and 1 reload", rotated, reloads)
+	}
+
+	// A failed rotation handler falls back to the full reload
+	rotationErr = errors.New("pool refused credentials")
+	watcher.emit(base("p4", "db-2"))
+	if reloads != 2 {
+		t.Errorf("full reloads = %d, want fallback reload after handler failure", reloads)
+	}
+
+	for _, change := range logged {
+		for _, v := range []interface{}{change.Old, change.New} {
+			if s, ok := v.(string); ok && strings.HasPrefix(s, "p") && len(s) == 2 {
+				t.Errorf("change log leaked secret: %+v", change)
+			}
+		}
+	}
+}
+
+func TestManager_OnSecretRotated_Unhandled(t *testing.T) {
+	watcher := &mockWatcher{}
+	manager := NewManager(ManagerConfig{Watcher: watcher})
+
+	reloads := 0
+	manager.OnSecretRotated("database", func(context.Context, string) error { return nil })
+	if err := manager.Watch(context.Background(), func(map[string]interface{}) error {
+		reloads++
+		return nil
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	// No handler covers stats.api_key, so the callback must see the change
+	watcher.emit(map[string]interface{}{"stats": map[string]interface{}{"api_key": "k1"}})
+	if reloads != 1 {
+		t.Errorf("reloads = %d, want 1 for an unhandled secret", reloads)
+	}
+}
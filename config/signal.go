@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Reload reloads the configuration from all providers and, if it loads and
+// validates, runs the reload pipeline: change log, secret rotation handlers,
+// callback and reload hooks, as for a watch event
+// On error the previous configuration is kept, including when the callback or a
+// component rejects the reloaded one
+func (m *Manager) Reload(ctx context.Context, callback func(map[string]interface{}) error) error {
+	m.mu.RLock()
+	old, provenance := m.current, m.provenance
+	m.mu.RUnlock()
+
+	data, err := m.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = m.applyReload(ctx, old, data, callback)
+	if err != nil {
+		m.restoreConfig(old, provenance)
+	}
+	m.observeReload(ctx, err)
+	return err
+}
+
+// restoreConfig makes config current again after a reload was rejected
+func (m *Manager) restoreConfig(config map[string]interface{}, provenance map[string]*Provenance) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = config
+	m.provenance = provenance
+}
+
+// WatchSignals reloads the configuration (see Reload) each time the process
+// receives one of sigs (default: SIGHUP), as operators of classic daemons expect,
+// until ctx is done
+// Signals received during a reload trigger a single reload once it completes
+func (m *Manager) WatchSignals(ctx context.Context, callback func(map[string]interface{}) error, sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+
+	go func() {
+		defer signal.Stop(received)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-received:
+				err := m.Reload(ctx, callback)
+				if m.onSignalReload != nil {
+					m.onSignalReload(sig, err)
+				}
+			}
+		}
+	}()
+}
+
+// reloadSignals returns the signals Watch reloads on
+func reloadSignals(onSIGHUP bool) []os.Signal {
+	if !onSIGHUP {
+		return nil
+	}
+	return []os.Signal{syscall.SIGHUP}
+}
@@ -0,0 +1,93 @@
+//go:build unix
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// lockedProvider is a MockProvider tests can update while a reload may run
+type lockedProvider struct {
+	mu sync.Mutex
+	*MockProvider
+}
+
+func (p *lockedProvider) Load(ctx context.Context) (map[string]interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.MockProvider.Load(ctx)
+}
+
+func (p *lockedProvider) set(data map[string]interface{}, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.data, p.err = data, err
+}
+
+func TestManager_ReloadOnSIGHUP(t *testing.T) {
+	provider := &lockedProvider{MockProvider: NewMockProvider("test", map[string]interface{}{"key": "old"})}
+	results := make(chan error, 1)
+	manager := NewManager(ManagerConfig{
+		Providers:      []Provider{provider},
+		ReloadOnSIGHUP: true,
+		OnSignalReload: func(sig os.Signal, err error) {
+			if sig != syscall.SIGHUP {
+				err = fmt.Errorf("unexpected signal %v", sig)
+			}
+			results <- err
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := manager.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := manager.Watch(ctx, func(data map[string]interface{}) error {
+		got = data
+		return nil
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	reload := func() error {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatalf("Kill() error = %v", err)
+		}
+		select {
+		case err := <-results:
+			return err
+		case <-time.After(time.Second):
+			t.Fatal("No reload after SIGHUP")
+			return nil
+		}
+	}
+
+	provider.set(map[string]interface{}{"key": "new"}, nil)
+	if err := reload(); err != nil {
+		t.Fatalf("Reload error = %v", err)
+	}
+	if got["key"] != "new" {
+		t.Errorf("Expected callback with the reloaded config, got %v", got)
+	}
+	if v, _ := manager.GetString("key"); v != "new" {
+		t.Errorf("Expected key = new, got %q", v)
+	}
+
+	// A failed reload keeps the previous config
+	provider.set(nil, fmt.Errorf("unreachable"))
+	if err := reload(); err == nil {
+		t.Error("Expected the reload error")
+	}
+	if v, _ := manager.GetString("key"); v != "new" {
+		t.Errorf("Expected key = new after a failed reload, got %q", v)
+	}
+}
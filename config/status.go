@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"time"
+)
+
+// ProviderStatus reports the health of one provider, as seen by Manager.Load
+type ProviderStatus struct {
+	Name        string
+	LastLoad    time.Time     // Start of the last load attempt
+	LastSuccess time.Time     // Start of the last successful load
+	LastError   error         // Error of the last load attempt, nil if it succeeded
+	Latency     time.Duration // Duration of the last load attempt
+	Loads       uint64        // Load attempts
+	Failures    uint64        // Failed load attempts
+
+	// Staleness is the time since the last successful load
+	Staleness time.Duration
+
+	// Stale is set when the last successful load is older than StaleAfter, or the
+	// provider has been tried but never loaded successfully
+	Stale bool
+}
+
+// providerState tracks load outcomes for one provider (guarded by Manager.mu)
+type providerState struct {
+	lastLoad    time.Time
+	lastSuccess time.Time
+	lastErr     error
+	latency     time.Duration
+	loads       uint64
+	failures    uint64
+}
+
+// ProviderStatus returns the status of each provider, in priority order
+// Providers Load never reached (after a lower priority provider failed) report no loads
+func (m *Manager) ProviderStatus() []ProviderStatus {
+	now := time.Now()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]ProviderStatus, len(m.providers))
+	for i, p := range m.providers {
+		status := ProviderStatus{Name: p.Name()}
+		if i < len(m.status) {
+			s := m.status[i]
+			status.LastLoad = s.lastLoad
+			status.LastSuccess = s.lastSuccess
+			status.LastError = s.lastErr
+			status.Latency = s.latency
+			status.Loads = s.loads
+			status.Failures = s.failures
+		}
+
+		switch {
+		case !status.LastSuccess.IsZero():
+			status.Staleness = now.Sub(status.LastSuccess)
+			status.Stale = m.staleAfter > 0 && status.Staleness > m.staleAfter
+		case status.Loads > 0:
+			status.Stale = true
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// Up reports whether the provider is healthy: it has loaded, its last load
+// succeeded and it isn't stale
+func (s ProviderStatus) Up() bool {
+	return s.Loads > 0 && s.LastError == nil && !s.Stale
+}
+
+// loadProvider loads provider i, recording the outcome for ProviderStatus
+// A non-empty prefix loads only that subtree from providers implementing SubtreeLoader
+func (m *Manager) loadProvider(ctx context.Context, i int, prefix string) (map[string]interface{}, error) {
+	start := time.Now()
+	var data map[string]interface{}
+	var err error
+	if loader, ok := m.providers[i].(SubtreeLoader); ok && prefix != "" {
+		data, err = loader.LoadSubtree(ctx, prefix)
+	} else {
+		data, err = m.providers[i].Load(ctx)
+	}
+	latency := time.Since(start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.status == nil {
+		m.status = make([]providerState, len(m.providers))
+	}
+	s := &m.status[i]
+	s.lastLoad = start
+	s.latency = latency
+	s.lastErr = err
+	s.loads++
+	if err != nil {
+		s.failures++
+	} else {
+		s.lastSuccess = start
+	}
+	return data, err
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManager_ProviderStatus(t *testing.T) {
+	consul := NewMockProvider("consul", map[string]interface{}{"a": 1})
+	file := NewMockProvider("file", map[string]interface{}{"b": 2})
+
+	manager := NewManager(ManagerConfig{
+		Providers:  []Provider{consul, file},
+		StaleAfter: time.Hour,
+	})
+
+	statuses := manager.ProviderStatus()
+	if len(statuses) != 2 || statuses[0].Name != "consul" || statuses[0].Loads != 0 || statuses[0].Stale {
+		t.Fatalf("ProviderStatus() before Load = %+v", statuses)
+	}
+
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	consul.err = errors.New("connection refused")
+	if _, err := manager.Load(context.Background()); err == nil {
+		t.Fatal("Expected Load() to fail")
+	}
+
+	statuses = manager.ProviderStatus()
+	s := statuses[0]
+	if s.Loads != 2 || s.Failures != 1 || s.LastError == nil {
+		t.Errorf("consul status = %+v, want 2 loads, 1 failure and the last error", s)
+	}
+	if s.LastSuccess.IsZero() || s.LastLoad.Before(s.LastSuccess) {
+		t.Errorf("consul LastSuccess = %v, LastLoad = %v", s.LastSuccess, s.LastLoad)
+	}
+	if s.Stale {
+		t.Error("consul loaded within StaleAfter and should not be stale")
+	}
+	if f := statuses[1]; f.Loads != 2 || f.Failures != 0 || f.LastError != nil {
+		t.Errorf("file status = %+v, want 2 successful loads", f)
+	}
+
+	if s.Up() || !statuses[1].Up() {
+		t.Errorf("Expected consul down after its failed load and file up, got %v and %v", s.Up(), statuses[1].Up())
+	}
+}
+
+func TestManager_ProviderStatus_NeverLoaded(t *testing.T) {
+	consul := NewMockProvider("consul", nil)
+	consul.err = errors.New("timeout")
+
+	manager := NewManager(ManagerConfig{Providers: []Provider{consul}})
+	manager.Load(context.Background())
+
+	if s := manager.ProviderStatus()[0]; !s.Stale || s.Failures != 1 {
+		t.Errorf("status = %+v, want stale after only failed loads", s)
+	}
+}
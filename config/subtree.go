@@ -0,0 +1,140 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SubtreeLoader is implemented by providers that can load a single config subtree
+// without loading everything (e.g. a ConsulProvider in Prefix mode)
+type SubtreeLoader interface {
+	// LoadSubtree retrieves the values under the dot-separated key prefix, nested
+	// from the root like Load; values outside the prefix may be included and are ignored
+	LoadSubtree(ctx context.Context, prefix string) (map[string]interface{}, error)
+}
+
+// ReloadSubtree reloads only the values under prefix (e.g. "stats_export") and merges
+// them into the current config, leaving every other key untouched
+// Providers implementing SubtreeLoader are queried for just the prefix, the others are
+// loaded whole and the subtree is taken from their data. Fallback expressions, immutable
+// keys and the validator apply as in Load; on error the current config is kept
+// Returns the new config; maps outside the prefix are shared with the previous config
+func (m *Manager) ReloadSubtree(ctx context.Context, prefix string) (map[string]interface{}, error) {
+	path := strings.Split(prefix, KeySeparator)
+	for _, part := range path {
+		if part == "" {
+			return nil, fmt.Errorf("invalid config subtree %q", prefix)
+		}
+	}
+
+	subtree := make(map[string]interface{})
+	provenance := make(map[string]*Provenance)
+	var conflicts MergeConflicts
+
+	// Load from providers in reverse order (lower priority first)
+	for i := len(m.providers) - 1; i >= 0; i-- {
+		data, err := m.loadProvider(ctx, i, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := lookupPath(data, prefix)
+		if !ok {
+			continue
+		}
+		part := make(map[string]interface{})
+		setPath(part, path, value)
+		conflicts = append(conflicts, mergeChecked(subtree, part, "", m.providers[i].Name(), provenance)...)
+	}
+
+	if !m.strictMerge {
+		conflicts = nil
+	}
+	if len(conflicts) > 0 && m.failOnConflict {
+		return nil, conflicts
+	}
+
+	if err := m.resolveFallbacks(ctx, subtree); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	old := m.current
+	m.mu.RUnlock()
+
+	value, ok := lookupPath(subtree, prefix)
+	result := withSubtree(old, path, value, ok)
+	m.freezeImmutable(old, result)
+
+	if m.validator != nil {
+		if err := m.validator.Validate(result); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = result
+	m.provenance = mergeSubtreeProvenance(m.provenance, provenance, prefix)
+	m.conflicts = mergeSubtreeConflicts(m.conflicts, conflicts, prefix)
+	return result, nil
+}
+
+// withSubtree returns a copy of config with the value at path replaced, or removed
+// when !ok; only the maps along path are copied
+func withSubtree(config map[string]interface{}, path []string, value interface{}, ok bool) map[string]interface{} {
+	result := make(map[string]interface{}, len(config)+1)
+	for k, v := range config {
+		result[k] = v
+	}
+
+	m := result
+	for _, key := range path[:len(path)-1] {
+		nested, _ := m[key].(map[string]interface{})
+		copied := make(map[string]interface{}, len(nested)+1)
+		for k, v := range nested {
+			copied[k] = v
+		}
+		m[key] = copied
+		m = copied
+	}
+
+	last := path[len(path)-1]
+	if ok {
+		m[last] = value
+	} else {
+		delete(m, last)
+	}
+	return result
+}
+
+// inSubtree reports whether the dot-separated key is prefix or below it
+func inSubtree(key, prefix string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+KeySeparator)
+}
+
+// mergeSubtreeProvenance replaces the provenance of the keys under prefix
+func mergeSubtreeProvenance(current, subtree map[string]*Provenance, prefix string) map[string]*Provenance {
+	result := make(map[string]*Provenance, len(current)+len(subtree))
+	for key, p := range current {
+		if !inSubtree(key, prefix) {
+			result[key] = p
+		}
+	}
+	for key, p := range subtree {
+		result[key] = p
+	}
+	return result
+}
+
+// mergeSubtreeConflicts replaces the merge conflicts of the keys under prefix
+func mergeSubtreeConflicts(current, subtree MergeConflicts, prefix string) MergeConflicts {
+	var result MergeConflicts
+	for _, conflict := range current {
+		if !inSubtree(conflict.Key, prefix) {
+			result = append(result, conflict)
+		}
+	}
+	return append(result, subtree...)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+// subtreeProvider records the prefixes it was asked to load
+type subtreeProvider struct {
+	*MockProvider
+	prefixes []string
+}
+
+func (p *subtreeProvider) LoadSubtree(ctx context.Context, prefix string) (map[string]interface{}, error) {
+	p.prefixes = append(p.prefixes, prefix)
+	return p.Load(ctx)
+}
+
+func TestManager_ReloadSubtree(t *testing.T) {
+	remote := &subtreeProvider{MockProvider: NewMockProvider("consul", map[string]interface{}{
+		"stats_export": map[string]interface{}{"interval": "60s"},
+		"server":       map[string]interface{}{"port": 8080},
+	})}
+	file := NewMockProvider("file", map[string]interface{}{
+		"stats_export": map[string]interface{}{"interval": "30s", "batch_size": 100},
+		"diameter":     map[string]interface{}{"origin_host": "eir.example.com"},
+	})
+	manager := NewManager(ManagerConfig{Providers: []Provider{remote, file}})
+
+	old, err := manager.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Change values inside and outside the subtree; only the subtree is reloaded
+	remote.data = map[string]interface{}{
+		"stats_export": map[string]interface{}{"interval": "15s"},
+		"server":       map[string]interface{}{"port": 9090},
+	}
+	file.data = map[string]interface{}{
+		"stats_export": map[string]interface{}{"batch_size": 500},
+		"diameter":     map[string]interface{}{"origin_host": "changed"},
+	}
+
+	result, err := manager.ReloadSubtree(context.Background(), "stats_export")
+	if err != nil {
+		t.Fatalf("ReloadSubtree() error = %v", err)
+	}
+	if len(remote.prefixes) != 1 || remote.prefixes[0] != "stats_export" {
+		t.Errorf("Expected the subtree loader to be asked for stats_export, got %v", remote.prefixes)
+	}
+
+	if v, _ := manager.GetString("stats_export.interval"); v != "15s" {
+		t.Errorf("Expected stats_export.interval 15s, got %q", v)
+	}
+	if v, _ := manager.GetInt("stats_export.batch_size"); v != 500 {
+		t.Errorf("Expected stats_export.batch_size 500, got %d", v)
+	}
+	if v, _ := manager.GetInt("server.port"); v != 8080 {
+		t.Errorf("Expected server.port untouched, got %d", v)
+	}
+	if v, _ := manager.GetString("diameter.origin_host"); v != "eir.example.com" {
+		t.Errorf("Expected diameter.origin_host untouched, got %q", v)
+	}
+	if p, _ := manager.Provenance("stats_export.batch_size"); p.Provider != "file" {
+		t.Errorf("Expected batch_size provenance file, got %q", p.Provider)
+	}
+
+	// The previous snapshot is left as it was
+	if v, _ := lookupPath(old, "stats_export.interval"); v != "60s" {
+		t.Errorf("Expected the previous config unchanged, got interval %v", v)
+	}
+	if _, ok := lookupPath(result, "server.port"); !ok {
+		t.Error("Expected the new config to keep keys outside the subtree")
+	}
+}
+
+func TestManager_ReloadSubtreeRemoved(t *testing.T) {
+	file := NewMockProvider("file", map[string]interface{}{
+		"stats_export": map[string]interface{}{"interval": "30s"},
+		"server":       map[string]interface{}{"port": 8080},
+	})
+	manager := NewManager(ManagerConfig{Providers: []Provider{file}})
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	file.data = map[string]interface{}{"server": map[string]interface{}{"port": 8080}}
+	if _, err := manager.ReloadSubtree(context.Background(), "stats_export"); err != nil {
+		t.Fatalf("ReloadSubtree() error = %v", err)
+	}
+	if _, ok := manager.Get("stats_export"); ok {
+		t.Error("Expected the removed subtree to be dropped")
+	}
+
+	if _, err := manager.ReloadSubtree(context.Background(), "stats_export..interval"); err == nil {
+		t.Error("Expected an invalid prefix to be rejected")
+	}
+}
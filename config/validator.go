@@ -2,7 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -42,11 +46,23 @@ func NewStructValidator(target interface{}) *StructValidator {
 }
 
 // Validate validates the configuration against struct tags
-// Supported tags:
+// Supported tags (a subset of go-playground/validator syntax):
 //   - validate:"required" - field must be set
-//   - validate:"min=X" - minimum value for numbers, minimum length for strings
-//   - validate:"max=X" - maximum value for numbers, maximum length for strings
+//   - validate:"omitempty" - skip the remaining rules when the field is empty
+//   - validate:"min=X" - minimum value for numbers, minimum length for strings and slices
+//   - validate:"max=X" - maximum value for numbers, maximum length for strings and slices
 //   - validate:"oneof=A B C" - value must be one of the specified options
+//   - validate:"url", "email", "ip", "ipv4", "ipv6", "hostname", "hostname_port" - string formats
+//   - validate:"dive" - apply the following rules to each element of a slice or map
+//   - validate:"required_if=Field value ..." - required when every named sibling field has the value
+//   - validate:"required_with=Field ..." - required when any named sibling field is set
+//   - validate:"excluded_with=Field ..." - must be empty when any named sibling field is set
+//   - validate:"exactly_one_of=A B ...", "at_most_one_of=...", "at_least_one_of=..." - on a
+//     struct field, how many of its named fields may be set (e.g. provider sections'
+//     Enabled flags)
+//
+// Sibling and member fields are named by Go field name, with "." for nested structs
+// (e.g. "TLS.Enabled")
 func (sv *StructValidator) Validate(config interface{}) error {
 	// First unmarshal config into target struct
 	if err := UnmarshalEnv(config.(map[string]interface{}), sv.target); err != nil {
@@ -65,37 +81,30 @@ func (sv *StructValidator) validateStruct(v reflect.Value, prefix string) error
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Field(i)
 		fieldType := t.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
 
 		fieldName := fieldType.Name
 		if prefix != "" {
 			fieldName = prefix + "." + fieldName
 		}
 
-		// Recurse into nested structs
-		if field.Kind() == reflect.Struct {
-			if err := sv.validateStruct(field, fieldName); err != nil {
-				if verrs, ok := err.(ValidationErrors); ok {
-					errors = append(errors, verrs...)
-				}
-			}
-			continue
-		}
-
 		// Get validation tag
-		validateTag := fieldType.Tag.Get("validate")
-		if validateTag == "" {
-			continue
+		var rules []string
+		if validateTag := fieldType.Tag.Get("validate"); validateTag != "" && validateTag != "-" {
+			rules = splitRules(validateTag)
 		}
 
-		// Parse validation rules
-		rules := strings.Split(validateTag, ",")
-		for _, rule := range rules {
-			rule = strings.TrimSpace(rule)
-
-			if err := sv.validateRule(field, fieldName, rule); err.Message != "" {
+		// Conditional rules depend on sibling fields, the others on the field alone
+		rules, conditional := splitConditionalRules(rules)
+		for _, rule := range conditional {
+			if err := sv.validateConditional(v, field, fieldName, rule); err.Message != "" {
 				errors = append(errors, err)
 			}
 		}
+
+		errors = append(errors, sv.validateField(field, fieldName, rules)...)
 	}
 
 	if len(errors) > 0 {
@@ -105,6 +114,80 @@ func (sv *StructValidator) validateStruct(v reflect.Value, prefix string) error
 	return nil
 }
 
+// validateField applies rules to a field, then recurses into structs and, after dive, elements
+func (sv *StructValidator) validateField(field reflect.Value, fieldName string, rules []string) ValidationErrors {
+	var errors ValidationErrors
+
+	for i, rule := range rules {
+		switch rule {
+		case "omitempty":
+			if isZeroValue(field) {
+				return nil
+			}
+			continue
+
+		case "dive":
+			return append(errors, sv.validateElements(field, fieldName, rules[i+1:])...)
+		}
+
+		if err := sv.validateRule(field, fieldName, rule); err.Message != "" {
+			errors = append(errors, err)
+		}
+	}
+
+	// Recurse into nested structs, including through pointers and untagged slices
+	switch field.Kind() {
+	case reflect.Ptr:
+		if !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+			errors = append(errors, sv.validateField(field.Elem(), fieldName, nil)...)
+		}
+	case reflect.Struct:
+		if err := sv.validateStruct(field, fieldName); err != nil {
+			if verrs, ok := err.(ValidationErrors); ok {
+				errors = append(errors, verrs...)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		errors = append(errors, sv.validateElements(field, fieldName, nil)...)
+	}
+
+	return errors
+}
+
+// validateElements validates each element of a slice, array or map against rules
+func (sv *StructValidator) validateElements(field reflect.Value, fieldName string, rules []string) ValidationErrors {
+	var errors ValidationErrors
+
+	switch field.Kind() {
+	case reflect.Ptr:
+		if !field.IsNil() {
+			return sv.validateElements(field.Elem(), fieldName, rules)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < field.Len(); i++ {
+			errors = append(errors, sv.validateField(field.Index(i), fmt.Sprintf("%s[%d]", fieldName, i), rules)...)
+		}
+	case reflect.Map:
+		for _, key := range field.MapKeys() {
+			errors = append(errors, sv.validateField(field.MapIndex(key), fmt.Sprintf("%s[%v]", fieldName, key.Interface()), rules)...)
+		}
+	}
+
+	return errors
+}
+
+// splitRules splits a validate tag into its trimmed, non-empty rules
+func splitRules(tag string) []string {
+	var rules []string
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
 // validateRule validates a single rule
 func (sv *StructValidator) validateRule(field reflect.Value, fieldName, rule string) ValidationError {
 	parts := strings.SplitN(rule, "=", 2)
@@ -137,6 +220,16 @@ func (sv *StructValidator) validateRule(field reflect.Value, fieldName, rule str
 		if err := sv.validateOneOf(field, fieldName, ruleValue); err.Message != "" {
 			return err
 		}
+
+	case "url", "email", "ip", "ipv4", "ipv6", "hostname", "hostname_port":
+		if err := sv.validateFormat(field, fieldName, ruleName); err.Message != "" {
+			return err
+		}
+
+	case "exactly_one_of", "at_most_one_of", "at_least_one_of":
+		if err := sv.validateGroup(field, fieldName, ruleName, ruleValue); err.Message != "" {
+			return err
+		}
 	}
 
 	return ValidationError{}
@@ -155,6 +248,16 @@ func (sv *StructValidator) validateMin(field reflect.Value, fieldName, minStr st
 			}
 		}
 
+	case reflect.Slice, reflect.Array, reflect.Map:
+		var min int
+		fmt.Sscanf(minStr, "%d", &min)
+		if field.Len() < min {
+			return ValidationError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("must have at least %d elements", min),
+			}
+		}
+
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		var min int64
 		fmt.Sscanf(minStr, "%d", &min)
@@ -192,6 +295,16 @@ func (sv *StructValidator) validateMax(field reflect.Value, fieldName, maxStr st
 			}
 		}
 
+	case reflect.Slice, reflect.Array, reflect.Map:
+		var max int
+		fmt.Sscanf(maxStr, "%d", &max)
+		if field.Len() > max {
+			return ValidationError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("must have at most %d elements", max),
+			}
+		}
+
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		var max int64
 		fmt.Sscanf(maxStr, "%d", &max)
@@ -233,6 +346,71 @@ func (sv *StructValidator) validateOneOf(field reflect.Value, fieldName, options
 	}
 }
 
+// validateFormat validates a string against a named format
+func (sv *StructValidator) validateFormat(field reflect.Value, fieldName, format string) ValidationError {
+	if field.Kind() != reflect.String {
+		return ValidationError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("%s requires a string, got %s", format, field.Kind()),
+		}
+	}
+
+	value := field.String()
+	valid := false
+	switch format {
+	case "url":
+		u, err := url.Parse(value)
+		valid = err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "" || u.Path != "")
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		valid = err == nil && addr.Address == value
+	case "ip":
+		valid = net.ParseIP(value) != nil
+	case "ipv4":
+		ip := net.ParseIP(value)
+		valid = ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(value)
+		valid = ip != nil && ip.To4() == nil
+	case "hostname":
+		valid = isHostname(value)
+	case "hostname_port":
+		host, port, err := net.SplitHostPort(value)
+		if err == nil {
+			n, perr := strconv.Atoi(port)
+			valid = perr == nil && n >= 1 && n <= 65535 && (host == "" || isHostname(host) || net.ParseIP(host) != nil)
+		}
+	}
+
+	if valid {
+		return ValidationError{}
+	}
+
+	return ValidationError{
+		Field:   fieldName,
+		Message: fmt.Sprintf("must be a valid %s", strings.ReplaceAll(format, "_", ":")),
+	}
+}
+
+// isHostname reports whether s is an RFC 1123 hostname
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // isZeroValue checks if a field has its zero value
 func isZeroValue(v reflect.Value) bool {
 	switch v.Kind() {
@@ -246,6 +424,8 @@ func isZeroValue(v reflect.Value) bool {
 		return v.Float() == 0
 	case reflect.Bool:
 		return !v.Bool()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
 	case reflect.Ptr, reflect.Interface:
 		return v.IsNil()
 	}
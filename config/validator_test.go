@@ -278,6 +278,114 @@ func TestStructValidator_Nested(t *testing.T) {
 	}
 }
 
+func TestStructValidator_Dive(t *testing.T) {
+	type Peer struct {
+		Host string `validate:"required,hostname"`
+		Port int    `validate:"min=1,max=65535"`
+	}
+
+	type Config struct {
+		Endpoints []string   `validate:"required,min=1,dive,required,url"`
+		Contacts  []string   `validate:"omitempty,dive,email"`
+		Bind      []string   `validate:"dive,ip"`
+		Upstreams []string   `validate:"dive,hostname_port"`
+		Groups    [][]string `validate:"dive,min=1,dive,ipv4"`
+		Peers     []Peer
+	}
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"endpoints": []interface{}{"http://a.example.com:8080/api", "https://b.example.com"},
+			"contacts":  []interface{}{"ops@example.com"},
+			"bind":      []interface{}{"10.0.0.1", "::1"},
+			"upstreams": []interface{}{"dra-1.example.com:3868", "10.0.0.2:3868", "[::1]:3868"},
+			"groups":    []interface{}{[]interface{}{"10.0.0.1", "10.0.0.2"}},
+			"peers": []interface{}{
+				map[string]interface{}{"host": "peer-1.example.com", "port": 3868},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(map[string]interface{})
+		errMsg string
+	}{
+		{name: "valid", modify: func(map[string]interface{}) {}},
+		{name: "no contacts", modify: func(c map[string]interface{}) { delete(c, "contacts") }},
+		{
+			name:   "missing endpoints",
+			modify: func(c map[string]interface{}) { delete(c, "endpoints") },
+			errMsg: "Endpoints",
+		},
+		{
+			name:   "invalid url",
+			modify: func(c map[string]interface{}) { c["endpoints"] = []interface{}{"http://ok.example.com", "not a url"} },
+			errMsg: "Endpoints[1]",
+		},
+		{
+			name:   "invalid email",
+			modify: func(c map[string]interface{}) { c["contacts"] = []interface{}{"ops"} },
+			errMsg: "Contacts[0]",
+		},
+		{
+			name:   "invalid ip",
+			modify: func(c map[string]interface{}) { c["bind"] = []interface{}{"10.0.0.256"} },
+			errMsg: "Bind[0]",
+		},
+		{
+			name:   "missing port",
+			modify: func(c map[string]interface{}) { c["upstreams"] = []interface{}{"dra-1.example.com"} },
+			errMsg: "Upstreams[0]",
+		},
+		{
+			name:   "port out of range",
+			modify: func(c map[string]interface{}) { c["upstreams"] = []interface{}{"dra-1.example.com:70000"} },
+			errMsg: "Upstreams[0]",
+		},
+		{
+			name:   "empty nested slice",
+			modify: func(c map[string]interface{}) { c["groups"] = []interface{}{[]interface{}{}} },
+			errMsg: "Groups[0]",
+		},
+		{
+			name: "ipv6 in ipv4 group",
+			modify: func(c map[string]interface{}) {
+				c["groups"] = []interface{}{[]interface{}{"10.0.0.1"}, []interface{}{"::1"}}
+			},
+			errMsg: "Groups[1][0]",
+		},
+		{
+			name: "invalid struct element",
+			modify: func(c map[string]interface{}) {
+				c["peers"] = []interface{}{map[string]interface{}{"host": "-bad-", "port": 3868}}
+			},
+			errMsg: "Peers[0].Host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid()
+			tt.modify(config)
+
+			err := NewStructValidator(&Config{}).Validate(config)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected error containing %q", tt.errMsg)
+			}
+			if !strings.Contains(err.Error(), "'"+tt.errMsg+"'") {
+				t.Errorf("error message should name %q, got %q", tt.errMsg, err.Error())
+			}
+		})
+	}
+}
+
 func TestFuncValidator(t *testing.T) {
 	validator := NewFuncValidator(func(config interface{}) error {
 		data := config.(map[string]interface{})
@@ -368,6 +476,8 @@ func TestIsZeroValue(t *testing.T) {
 		{"true bool", true, false},
 		{"zero float", 0.0, true},
 		{"non-zero float", 3.14, false},
+		{"empty slice", []string{}, true},
+		{"non-empty slice", []string{"a"}, false},
 	}
 
 	for _, tt := range tests {
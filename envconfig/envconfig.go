@@ -0,0 +1,33 @@
+package envconfig
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EnvConfig is a config struct loaded by ReadConfigFrom
+// Build with the noviper tag to load it without viper (see loader_noviper.go)
+type EnvConfig interface {
+	DefaultValues()
+	Print()
+}
+
+// configPath returns path, or config.env in the working directory if empty
+func configPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return pwd + "/config.env", nil
+}
+
+func getFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("mapstructure"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	return strings.ToLower(field.Name)
+}
@@ -1,30 +1,23 @@
+//go:build !noviper
+
 package envconfig
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 
 	"github.com/spf13/viper"
 )
 
-type EnvConfig interface {
-	DefaultValues()
-	Print()
-}
-
 func ReadConfigFrom(path string, envConfig EnvConfig) error {
 	if reflect.TypeOf(envConfig).Kind() != reflect.Ptr {
 		return fmt.Errorf("envconfig must be a pointer to struct")
 	}
 
-	if path == "" {
-		pwd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-		path = pwd + "/config.env"
+	path, err := configPath(path)
+	if err != nil {
+		return err
 	}
 
 	envConfig.DefaultValues()
@@ -45,7 +38,7 @@ func ReadConfigFrom(path string, envConfig EnvConfig) error {
 		fmt.Println(fmt.Errorf("error reading config file %s, using default", err))
 	}
 
-	err := v.Unmarshal(envConfig)
+	err = v.Unmarshal(envConfig, viper.DecodeHook(decodeHook))
 	if err != nil {
 		return err
 	}
@@ -91,7 +84,10 @@ func registerKeysRecursive(v *viper.Viper, val reflect.Value, prefix string) err
 			key = fieldName
 		}
 
-		if fieldVal.Kind() == reflect.Struct {
+		if isValueType(fieldVal.Type()) {
+			// Registered as a string so env and file values decode the same way
+			v.SetDefault(key, formatValue(fieldVal))
+		} else if fieldVal.Kind() == reflect.Struct {
 			if err := registerKeysRecursive(v, fieldVal, key); err != nil {
 				return err
 			}
@@ -108,9 +104,22 @@ func registerKeysRecursive(v *viper.Viper, val reflect.Value, prefix string) err
 	return nil
 }
 
-func getFieldName(field reflect.StructField) string {
-	if tag := field.Tag.Get("mapstructure"); tag != "" {
-		return strings.Split(tag, ",")[0]
+// decodeHook converts strings to durations, byte sizes, URLs, IP addresses and
+// comma-separated slices. It replaces viper's default hooks, so it covers those too
+func decodeHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+	raw := reflect.ValueOf(data).String()
+
+	if isValueType(to) {
+		return parseValue(to, raw)
+	}
+	if to.Kind() == reflect.Slice {
+		if raw == "" {
+			return []string{}, nil
+		}
+		return strings.Split(raw, ","), nil
 	}
-	return strings.ToLower(field.Name)
+	return data, nil
 }
@@ -0,0 +1,355 @@
+//go:build noviper
+
+package envconfig
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadConfigFrom loads envConfig without external dependencies, for builds with
+// the noviper tag. It behaves like the viper loader: values start from
+// DefaultValues, are overridden by the config file (dotenv, or JSON by extension)
+// and then by non-empty environment variables named after the upper-cased key with
+// "." and "-" replaced by "_" (db.host -> DB_HOST)
+func ReadConfigFrom(path string, envConfig EnvConfig) error {
+	if reflect.TypeOf(envConfig).Kind() != reflect.Ptr {
+		return fmt.Errorf("envconfig must be a pointer to struct")
+	}
+
+	path, err := configPath(path)
+	if err != nil {
+		return err
+	}
+
+	envConfig.DefaultValues()
+	defer envConfig.Print()
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error reading config file %s, using default", err))
+	}
+
+	return applyValues(reflect.ValueOf(envConfig), "", values)
+}
+
+// envKeyReplacer maps config keys to environment variable names, as the viper loader
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// readConfigFile reads a config file into lower-cased, dot-separated keys
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".env":
+		if err := parseDotenv(data, values); err != nil {
+			return nil, err
+		}
+	case ".json":
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		flattenValues(values, "", m)
+	default:
+		return nil, fmt.Errorf("unsupported config type %q (noviper build supports .env and .json)", strings.TrimPrefix(ext, "."))
+	}
+	return values, nil
+}
+
+// parseDotenv parses KEY=value lines: blank lines and # comments are skipped, an
+// "export " prefix is allowed, and values may be single or double quoted
+func parseDotenv(data []byte, values map[string]interface{}) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+
+		value, err := unquoteDotenv(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		values[strings.ToLower(key)] = value
+	}
+	return scanner.Err()
+}
+
+// unquoteDotenv strips quotes, or a trailing " #" comment from an unquoted value
+func unquoteDotenv(value string) (string, error) {
+	if len(value) >= 2 {
+		switch value[0] {
+		case '"':
+			if end := strings.LastIndexByte(value, '"'); end > 0 {
+				return strconv.Unquote(value[:end+1])
+			}
+			return "", fmt.Errorf("unterminated quoted value")
+		case '\'':
+			if end := strings.LastIndexByte(value, '\''); end > 0 {
+				return value[1:end], nil
+			}
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
+
+// flattenValues copies nested JSON objects into dot-separated keys
+func flattenValues(values map[string]interface{}, prefix string, m map[string]interface{}) {
+	for k, v := range m {
+		key := strings.ToLower(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenValues(values, key, nested)
+			continue
+		}
+		values[key] = v
+	}
+}
+
+// applyValues sets the fields of val from the environment and file values
+func applyValues(val reflect.Value, prefix string, values map[string]interface{}) error {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := val.Type()
+
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		fieldVal := val.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+		fieldName := getFieldName(field)
+		if fieldName == "-" {
+			continue
+		}
+
+		var key string
+		if prefix != "" {
+			key = prefix + "." + fieldName
+		} else {
+			key = fieldName
+		}
+
+		if fieldVal.Kind() == reflect.Struct && !isValueType(fieldVal.Type()) && fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+			if err := applyValues(fieldVal, key, values); err != nil {
+				return err
+			}
+			continue
+		}
+		if fieldVal.Kind() == reflect.Ptr && !isValueType(fieldVal.Type()) && !fieldVal.IsNil() && fieldVal.Elem().Kind() == reflect.Struct {
+			if err := applyValues(fieldVal, key, values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var raw interface{}
+		if env := os.Getenv(envKeyReplacer.Replace(strings.ToUpper(key))); env != "" {
+			raw = env
+		} else if v, ok := values[key]; ok {
+			raw = v
+		} else {
+			continue // Keep the default set by DefaultValues
+		}
+
+		if err := setValue(fieldVal, raw); err != nil {
+			return fmt.Errorf("'%s' %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// setValue sets field from a string or JSON value with the weak conversions of
+// the viper loader: numbers and bools from strings, the types of parseValue from
+// their string forms and slices from comma-separated strings
+func setValue(field reflect.Value, raw interface{}) error {
+	if raw == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if isValueType(field.Type()) {
+		if s, ok := raw.(string); ok {
+			value, err := parseValue(field.Type(), s)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(value))
+			return nil
+		}
+		if n, ok := raw.(float64); ok {
+			switch {
+			case field.Type() == durationType:
+				field.SetInt(int64(n))
+				return nil
+			case field.Type() == byteSizeType && n >= 0:
+				field.SetUint(uint64(n))
+				return nil
+			}
+		}
+		return fmt.Errorf("expected type '%s', got unconvertible type '%T'", field.Type(), raw)
+	}
+
+	// Bools convert to 1 or 0 for other kinds
+	if b, ok := raw.(bool); ok && field.Kind() != reflect.Bool && field.Kind() != reflect.Slice {
+		n := 0.0
+		if b {
+			n = 1
+		}
+		raw = n
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch v := raw.(type) {
+		case string:
+			field.SetString(v)
+		case float64:
+			field.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return fmt.Errorf("expected type '%s', got unconvertible type '%T'", field.Type(), raw)
+		}
+
+	case reflect.Bool:
+		switch v := raw.(type) {
+		case bool:
+			field.SetBool(v)
+		case float64:
+			field.SetBool(v != 0)
+		case string:
+			if v == "" {
+				field.SetBool(false)
+				return nil
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("cannot parse %q as bool: %w", v, err)
+			}
+			field.SetBool(b)
+		default:
+			return fmt.Errorf("expected type '%s', got unconvertible type '%T'", field.Type(), raw)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := raw.(type) {
+		case float64:
+			field.SetInt(int64(v))
+		case string:
+			if v == "" {
+				field.SetInt(0)
+				return nil
+			}
+			i, err := strconv.ParseInt(v, 0, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("cannot parse %q as int: %w", v, err)
+			}
+			field.SetInt(i)
+		default:
+			return fmt.Errorf("expected type '%s', got unconvertible type '%T'", field.Type(), raw)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v := raw.(type) {
+		case float64:
+			if v < 0 {
+				return fmt.Errorf("cannot parse %v as uint", v)
+			}
+			field.SetUint(uint64(v))
+		case string:
+			if v == "" {
+				field.SetUint(0)
+				return nil
+			}
+			u, err := strconv.ParseUint(v, 0, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("cannot parse %q as uint: %w", v, err)
+			}
+			field.SetUint(u)
+		default:
+			return fmt.Errorf("expected type '%s', got unconvertible type '%T'", field.Type(), raw)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch v := raw.(type) {
+		case float64:
+			field.SetFloat(v)
+		case string:
+			if v == "" {
+				field.SetFloat(0)
+				return nil
+			}
+			f, err := strconv.ParseFloat(v, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("cannot parse %q as float: %w", v, err)
+			}
+			field.SetFloat(f)
+		default:
+			return fmt.Errorf("expected type '%s', got unconvertible type '%T'", field.Type(), raw)
+		}
+
+	case reflect.Slice:
+		var items []interface{}
+		switch v := raw.(type) {
+		case []interface{}:
+			items = v
+		case string:
+			if v != "" {
+				for _, item := range strings.Split(v, ",") {
+					items = append(items, item)
+				}
+			}
+		default:
+			items = []interface{}{v}
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item); err != nil {
+				return fmt.Errorf("[%d] %w", i, err)
+			}
+		}
+		field.Set(slice)
+
+	default:
+		return fmt.Errorf("unsupported type '%s'", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package envconfig
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The tests run against both loaders: go test ./envconfig and go test -tags noviper ./envconfig
+
+type testDB struct {
+	Name string `mapstructure:"name"`
+	Pool int    `mapstructure:"pool"`
+}
+
+type testConfig struct {
+	Host    string        `mapstructure:"host"`
+	Port    int           `mapstructure:"port"`
+	Debug   bool          `mapstructure:"debug"`
+	Ratio   float64       `mapstructure:"ratio"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Peers   []string      `mapstructure:"peers"`
+	DB      testDB        `mapstructure:"db"`
+	Skipped string        `mapstructure:"-"`
+
+	printed bool
+}
+
+func (c *testConfig) DefaultValues() {
+	c.Host = "localhost"
+	c.Port = 8080
+	c.Ratio = 0.5
+	c.Timeout = time.Second
+	c.Peers = []string{"hss0"}
+	c.DB.Pool = 5
+	c.Skipped = "kept"
+}
+
+func (c *testConfig) Print() {
+	c.printed = true
+}
+
+// clearEnv makes the test independent of the caller's environment (empty values are ignored)
+func clearEnv(t *testing.T) {
+	for _, name := range []string{"HOST", "PORT", "DEBUG", "RATIO", "TIMEOUT", "PEERS", "DB_NAME", "DB_POOL", "SKIPPED"} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadConfigFrom_Dotenv(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.env", `# EIR settings
+HOST=eir.local
+PORT=3868
+DEBUG=true
+PEERS=hss1,hss2
+db.name="eir db"
+SKIPPED=ignored
+`)
+	t.Setenv("PORT", "3869")
+	t.Setenv("TIMEOUT", "5s")
+	t.Setenv("DB_POOL", "10")
+
+	var cfg testConfig
+	if err := ReadConfigFrom(path, &cfg); err != nil {
+		t.Fatalf("ReadConfigFrom() error = %v", err)
+	}
+
+	want := testConfig{
+		Host:    "eir.local",
+		Port:    3869, // Environment overrides the file
+		Debug:   true,
+		Ratio:   0.5,
+		Timeout: 5 * time.Second,
+		Peers:   []string{"hss1", "hss2"},
+		DB:      testDB{Name: "eir db", Pool: 10},
+		Skipped: "kept",
+		printed: true,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ReadConfigFrom() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestReadConfigFrom_JSON(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.json", `{"port": 9000, "ratio": 0.25, "peers": ["a", "b"], "db": {"name": "hlr"}}`)
+	t.Setenv("DB_NAME", "eir")
+
+	var cfg testConfig
+	if err := ReadConfigFrom(path, &cfg); err != nil {
+		t.Fatalf("ReadConfigFrom() error = %v", err)
+	}
+	if cfg.Port != 9000 || cfg.Ratio != 0.25 || !reflect.DeepEqual(cfg.Peers, []string{"a", "b"}) {
+		t.Errorf("ReadConfigFrom() = %+v", cfg)
+	}
+	if cfg.DB.Name != "eir" || cfg.DB.Pool != 5 || cfg.Host != "localhost" {
+		t.Errorf("ReadConfigFrom() db/defaults = %+v", cfg)
+	}
+}
+
+func TestReadConfigFrom_MissingFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DEBUG", "1")
+
+	var cfg testConfig
+	if err := ReadConfigFrom(filepath.Join(t.TempDir(), "missing.env"), &cfg); err != nil {
+		t.Fatalf("ReadConfigFrom() error = %v", err)
+	}
+	if cfg.Host != "localhost" || cfg.Port != 8080 || !cfg.Debug || !cfg.printed {
+		t.Errorf("Expected defaults with env overlay, got %+v", cfg)
+	}
+}
+
+func TestReadConfigFrom_InvalidValue(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "http")
+
+	var cfg testConfig
+	if err := ReadConfigFrom(filepath.Join(t.TempDir(), "missing.env"), &cfg); err == nil {
+		t.Error("Expected error for a non-numeric port")
+	}
+}
+
+type typedConfig struct {
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Cache    ByteSize      `mapstructure:"cache"`
+	Buffer   ByteSize      `mapstructure:"buffer"`
+	NMS      url.URL       `mapstructure:"nms"`
+	Callback *url.URL      `mapstructure:"callback"`
+	Bind     net.IP        `mapstructure:"bind"`
+	Peers    []net.IP      `mapstructure:"peers"`
+}
+
+func (c *typedConfig) DefaultValues() {
+	c.Timeout = 3 * time.Second
+	c.Cache = 64 * MiB
+	c.Buffer = 4 * KiB
+	c.Callback = &url.URL{Scheme: "http", Host: "localhost:8080"}
+	c.Bind = net.IPv4zero
+}
+
+func (c *typedConfig) Print() {}
+
+func TestReadConfigFrom_Types(t *testing.T) {
+	for _, name := range []string{"TIMEOUT", "CACHE", "BUFFER", "NMS", "CALLBACK", "BIND", "PEERS"} {
+		t.Setenv(name, "")
+	}
+	path := writeFile(t, "config.env", `CACHE=256MB
+NMS=https://nms.example.com:8443/ingest
+PEERS=10.0.0.1,10.0.0.2
+`)
+	t.Setenv("TIMEOUT", "1m30s")
+	t.Setenv("BIND", "192.168.1.10")
+
+	var cfg typedConfig
+	if err := ReadConfigFrom(path, &cfg); err != nil {
+		t.Fatalf("ReadConfigFrom() error = %v", err)
+	}
+
+	if cfg.Timeout != 90*time.Second {
+		t.Errorf("Timeout = %v, want 1m30s", cfg.Timeout)
+	}
+	if cfg.Cache != 256*MB || cfg.Buffer != 4*KiB {
+		t.Errorf("Cache = %v, Buffer = %v, want 256MB and default 4KiB", cfg.Cache, cfg.Buffer)
+	}
+	if cfg.NMS.Scheme != "https" || cfg.NMS.Host != "nms.example.com:8443" || cfg.NMS.Path != "/ingest" {
+		t.Errorf("NMS = %v", cfg.NMS.String())
+	}
+	if cfg.Callback == nil || cfg.Callback.String() != "http://localhost:8080" {
+		t.Errorf("Callback = %v, want default", cfg.Callback)
+	}
+	if !cfg.Bind.Equal(net.ParseIP("192.168.1.10")) {
+		t.Errorf("Bind = %v", cfg.Bind)
+	}
+	if len(cfg.Peers) != 2 || !cfg.Peers[1].Equal(net.ParseIP("10.0.0.2")) {
+		t.Errorf("Peers = %v", cfg.Peers)
+	}
+
+	t.Setenv("BIND", "not-an-ip")
+	if err := ReadConfigFrom(path, &typedConfig{}); err == nil {
+		t.Error("Expected error for an invalid IP address")
+	}
+}
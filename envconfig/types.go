@@ -0,0 +1,160 @@
+package envconfig
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a size in bytes, parsed from values like "256MB" or "1.5GiB"
+// KB, MB, GB and TB are powers of 1000; KiB, MiB, GiB and TiB powers of 1024.
+// A plain number is a count of bytes
+type ByteSize uint64
+
+// Byte size units
+const (
+	Byte ByteSize = 1
+	KB   ByteSize = 1000
+	MB   ByteSize = 1000 * KB
+	GB   ByteSize = 1000 * MB
+	TB   ByteSize = 1000 * GB
+	KiB  ByteSize = 1024
+	MiB  ByteSize = 1024 * KiB
+	GiB  ByteSize = 1024 * MiB
+	TiB  ByteSize = 1024 * GiB
+)
+
+// byteSizeUnits are the unit suffixes, longest first so "MiB" isn't read as "B"
+var byteSizeUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	{"TB", TB}, {"GB", GB}, {"MB", MB}, {"KB", KB},
+	{"B", Byte},
+}
+
+// ParseByteSize parses a size such as "512", "64KiB" or "1.5GB" (units are case-insensitive)
+func ParseByteSize(s string) (ByteSize, error) {
+	value := strings.TrimSpace(s)
+	multiplier := Byte
+	for _, unit := range byteSizeUnits {
+		if len(value) > len(unit.suffix) && strings.EqualFold(value[len(value)-len(unit.suffix):], unit.suffix) {
+			value = strings.TrimSpace(value[:len(value)-len(unit.suffix)])
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	bytes := n * float64(multiplier)
+	if bytes >= math.MaxUint64 {
+		return 0, fmt.Errorf("byte size %q overflows", s)
+	}
+	return ByteSize(math.Round(bytes)), nil
+}
+
+// String formats the size in the unit that divides it exactly with the smallest count
+func (b ByteSize) String() string {
+	best := byteSizeUnits[len(byteSizeUnits)-1]
+	for _, unit := range byteSizeUnits {
+		if b >= unit.size && b%unit.size == 0 && unit.size > best.size {
+			best = unit
+		}
+	}
+	return strconv.FormatUint(uint64(b/best.size), 10) + best.suffix
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+	urlType      = reflect.TypeOf(url.URL{})
+	urlPtrType   = reflect.TypeOf(&url.URL{})
+	ipType       = reflect.TypeOf(net.IP{})
+)
+
+// isValueType reports whether t is decoded from a single string rather than as a
+// struct or list of keys
+func isValueType(t reflect.Type) bool {
+	switch t {
+	case durationType, byteSizeType, urlType, urlPtrType, ipType:
+		return true
+	}
+	return false
+}
+
+// parseValue parses s into a value of type t, for the types isValueType reports
+func parseValue(t reflect.Type, s string) (interface{}, error) {
+	switch t {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as duration: %w", s, err)
+		}
+		return d, nil
+
+	case byteSizeType:
+		return ParseByteSize(s)
+
+	case urlType, urlPtrType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as URL: %w", s, err)
+		}
+		if t == urlType {
+			return *u, nil
+		}
+		return u, nil
+
+	case ipType:
+		if s == "" {
+			return net.IP(nil), nil
+		}
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("cannot parse %q as IP address", s)
+		}
+		return ip, nil
+	}
+	return nil, fmt.Errorf("unsupported type '%s'", t)
+}
+
+// formatValue returns the string form of a value of a type isValueType reports,
+// the inverse of parseValue
+func formatValue(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case url.URL:
+		return value.String()
+	case *url.URL:
+		if value == nil {
+			return ""
+		}
+		return value.String()
+	case net.IP:
+		if value == nil {
+			return ""
+		}
+		return value.String()
+	case fmt.Stringer:
+		return value.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,49 @@
+package envconfig
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"64KiB", 64 * KiB, false},
+		{"256MB", 256 * MB, false},
+		{"256mb", 256 * MB, false},
+		{"1.5 GiB", 3 * GiB / 2, false},
+		{"2TB", 2 * TB, false},
+		{"", 0, true},
+		{"-1MB", 0, true},
+		{"12XB", 0, true},
+		{"MB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %v, %v, want %v (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:          "0B",
+		512:        "512B",
+		64 * KiB:   "64KiB",
+		256 * MB:   "256MB",
+		3 * GiB:    "3GiB",
+		1500 * KiB: "1500KiB",
+		1001:       "1001B",
+	}
+	for size, want := range tests {
+		if got := size.String(); got != want {
+			t.Errorf("ByteSize(%d).String() = %q, want %q", uint64(size), got, want)
+		}
+		if parsed, err := ParseByteSize(want); err != nil || parsed != size {
+			t.Errorf("ParseByteSize(%q) = %v, %v, want %d", want, parsed, err, uint64(size))
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package equeue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BrokerMessage is a message consumed from an external broker (Kafka, NATS, Redis streams)
+type BrokerMessage struct {
+	Topic     string // Kafka topic, NATS subject or Redis stream
+	Partition int32
+	Offset    int64  // Kafka offset or stream sequence
+	ID        string // Redis stream entry ID or NATS message ID
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Timestamp time.Time
+
+	// Ack commits the message (Kafka offset commit, NATS JetStream Ack, Redis XACK)
+	// Called after the handler succeeds; nil means nothing to commit
+	Ack func(ctx context.Context) error
+
+	// Nack requests redelivery after the handler fails (optional)
+	Nack func(ctx context.Context) error
+}
+
+// BrokerConsumer fetches messages from an external broker
+// Fetch blocks until a message is available or ctx is done
+type BrokerConsumer interface {
+	Fetch(ctx context.Context) (*BrokerMessage, error)
+}
+
+// BrokerConsumerFunc adapts a function to the BrokerConsumer interface
+type BrokerConsumerFunc func(ctx context.Context) (*BrokerMessage, error)
+
+// Fetch calls f(ctx)
+func (f BrokerConsumerFunc) Fetch(ctx context.Context) (*BrokerMessage, error) {
+	return f(ctx)
+}
+
+// ChanConsumer adapts push-style clients (e.g., NATS subscriptions) to BrokerConsumer
+// The subscription callback hands messages to Deliver
+type ChanConsumer struct {
+	messages chan *BrokerMessage
+}
+
+// NewChanConsumer creates a push consumer with the given buffer size
+func NewChanConsumer(bufferSize int) *ChanConsumer {
+	return &ChanConsumer{messages: make(chan *BrokerMessage, bufferSize)}
+}
+
+// Deliver hands a message to the bridge, blocking until it is accepted or ctx is done
+func (c *ChanConsumer) Deliver(ctx context.Context, msg *BrokerMessage) error {
+	select {
+	case c.messages <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Fetch returns the next delivered message
+func (c *ChanConsumer) Fetch(ctx context.Context) (*BrokerMessage, error) {
+	select {
+	case msg := <-c.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// BrokerEvent is an event carrying a broker message, whose Value is the payload
+// (as json.RawMessage) validated by EventQueueConfig.Schemas
+// Done commits (or nacks) the message before signalling completion, so the offset
+// is committed only once the handler has finished
+type BrokerEvent struct {
+	*Event
+	Message *BrokerMessage
+
+	commitCtx context.Context
+	onCommit  func(msg *BrokerMessage, err error)
+	release   func()
+	once      sync.Once
+}
+
+// Done commits the message on success, nacks it on failure, then completes the event
+func (e *BrokerEvent) Done(result interface{}, err error) {
+	e.once.Do(func() {
+		var commitErr error
+		if err == nil {
+			if e.Message.Ack != nil {
+				commitErr = e.Message.Ack(e.commitCtx)
+			}
+		} else if e.Message.Nack != nil {
+			commitErr = e.Message.Nack(e.commitCtx)
+		}
+		if e.onCommit != nil {
+			e.onCommit(e.Message, commitErr)
+		}
+		if e.release != nil {
+			e.release()
+		}
+
+		e.Event.Done(result, err)
+	})
+}
+
+// BridgeConfig configures a broker bridge
+type BridgeConfig struct {
+	// Consumer supplies broker messages
+	Consumer BrokerConsumer
+
+	// Queue receives the bridged events; handlers are looked up by event type as usual
+	Queue IEventQueue
+
+	// EventType maps a message to the event type (default: message topic)
+	EventType func(msg *BrokerMessage) string
+
+	// EventTimeout sets a processing deadline on each event (0 = none)
+	EventTimeout time.Duration
+
+	// MaxInFlight limits messages enqueued but not yet done (default: 100)
+	MaxInFlight int
+
+	// RetryInterval is the wait before retrying a full queue or a failed fetch (default: 10ms)
+	RetryInterval time.Duration
+
+	// OnCommit is called after each message is acked or nacked (optional)
+	OnCommit func(msg *BrokerMessage, err error)
+}
+
+// Bridge consumes broker messages and enqueues them as events, so the same handler
+// registry processes both in-process and broker-delivered events
+//
+// Wiring examples (adapters live in the service, keeping broker clients out of this package):
+//
+//	// Kafka (segmentio/kafka-go)
+//	BrokerConsumerFunc(func(ctx context.Context) (*BrokerMessage, error) {
+//		m, err := reader.FetchMessage(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &BrokerMessage{Topic: m.Topic, Partition: int32(m.Partition), Offset: m.Offset, Value: m.Value,
+//			Ack: func(ctx context.Context) error { return reader.CommitMessages(ctx, m) }}, nil
+//	})
+//
+//	// NATS JetStream push subscription
+//	consumer := NewChanConsumer(256)
+//	js.Subscribe(subject, func(m *nats.Msg) {
+//		consumer.Deliver(ctx, &BrokerMessage{Topic: m.Subject, Value: m.Data,
+//			Ack:  func(context.Context) error { return m.Ack() },
+//			Nack: func(context.Context) error { return m.Nak() }})
+//	}, nats.ManualAck())
+//
+//	// Redis streams (go-redis XREADGROUP); Ack issues XACK for the entry ID
+type Bridge struct {
+	consumer      BrokerConsumer
+	queue         IEventQueue
+	eventType     func(msg *BrokerMessage) string
+	eventTimeout  time.Duration
+	retryInterval time.Duration
+	onCommit      func(msg *BrokerMessage, err error)
+	inFlight      chan struct{}
+}
+
+// NewBridge creates a broker bridge
+func NewBridge(config BridgeConfig) *Bridge {
+	if config.EventType == nil {
+		config.EventType = func(msg *BrokerMessage) string { return msg.Topic }
+	}
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = 100
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 10 * time.Millisecond
+	}
+
+	return &Bridge{
+		consumer:      config.Consumer,
+		queue:         config.Queue,
+		eventType:     config.EventType,
+		eventTimeout:  config.EventTimeout,
+		retryInterval: config.RetryInterval,
+		onCommit:      config.OnCommit,
+		inFlight:      make(chan struct{}, config.MaxInFlight),
+	}
+}
+
+// Run consumes messages until ctx is done
+// Messages whose value fails the schema of their event type are nacked and skipped
+// Returns nil on context cancellation, or the error that stopped the queue
+func (b *Bridge) Run(ctx context.Context) error {
+	for {
+		// Reserve an in-flight slot before fetching so broker backpressure applies
+		select {
+		case b.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		msg, err := b.consumer.Fetch(ctx)
+		if err != nil {
+			<-b.inFlight
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !b.wait(ctx) {
+				return nil
+			}
+			continue
+		}
+
+		event := b.newEvent(ctx, msg)
+		if err := b.enqueue(ctx, event); err != nil {
+			var payloadErr *PayloadError
+			if errors.As(err, &payloadErr) {
+				// Poison message: nack it and keep consuming
+				event.Done(nil, err)
+				continue
+			}
+			<-b.inFlight
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// newEvent wraps a broker message in an event
+func (b *Bridge) newEvent(ctx context.Context, msg *BrokerMessage) *BrokerEvent {
+	options := []EventOption{WithPayload(json.RawMessage(msg.Value))}
+	if b.eventTimeout > 0 {
+		options = append(options, WithTimeout(b.eventTimeout))
+	}
+
+	return &BrokerEvent{
+		Event:     NewEvent(b.eventType(msg), ctx, options...),
+		Message:   msg,
+		commitCtx: context.WithoutCancel(ctx),
+		onCommit:  b.onCommit,
+		release:   func() { <-b.inFlight },
+	}
+}
+
+// enqueue retries while the queue is full
+func (b *Bridge) enqueue(ctx context.Context, event *BrokerEvent) error {
+	for {
+		err := b.queue.Enqueue(event)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrQueueFull) {
+			return fmt.Errorf("failed to enqueue broker message from %s: %w", event.Message.Topic, err)
+		}
+		if !b.wait(ctx) {
+			return ctx.Err()
+		}
+	}
+}
+
+// wait sleeps for the retry interval, returning false if ctx is done first
+func (b *Bridge) wait(ctx context.Context) bool {
+	timer := time.NewTimer(b.retryInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,254 @@
+package equeue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestBridge tests broker messages are handled as events and acked or nacked after
+func TestBridge(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name       string
+		config     BridgeConfig
+		msg        BrokerMessage
+		handlerErr error
+		wantType   string
+		wantAck    bool
+		wantNack   bool
+		wantErr    error // Result of the event
+	}{
+		{
+			name:     "acked",
+			msg:      BrokerMessage{Topic: "cdr", Offset: 7},
+			wantType: "cdr",
+			wantAck:  true,
+		},
+		{
+			name:       "nacked",
+			msg:        BrokerMessage{Topic: "cdr", Offset: 8},
+			handlerErr: errRejected,
+			wantType:   "cdr",
+			wantNack:   true,
+			wantErr:    errRejected,
+		},
+		{
+			name: "mapped type",
+			config: BridgeConfig{EventType: func(msg *BrokerMessage) string {
+				return msg.Headers["type"]
+			}},
+			msg:      BrokerMessage{Topic: "diameter", Headers: map[string]string{"type": "ulr"}},
+			wantType: "ulr",
+			wantAck:  true,
+		},
+		{
+			name:     "event timeout",
+			config:   BridgeConfig{EventTimeout: 5 * time.Millisecond},
+			msg:      BrokerMessage{Topic: "slow"},
+			wantType: "slow",
+			wantNack: true,
+			wantErr:  ErrEventExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eq := NewEventQueue(EventQueueConfig{})
+			events := make(chan IEvent, 1)
+			handler := EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				events <- event
+				if event.GetType() == "slow" {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return tt.handlerErr
+			})
+			for _, eventType := range []string{"cdr", "ulr", "slow"} {
+				eq.RegisterHandler(eventType, handler)
+			}
+			eq.Start(context.Background())
+			defer eq.Stop()
+
+			var acked, nacked atomic.Bool
+			msg := tt.msg
+			msg.Ack = func(ctx context.Context) error { acked.Store(true); return nil }
+			msg.Nack = func(ctx context.Context) error { nacked.Store(true); return nil }
+			committed := make(chan error, 1)
+
+			consumer := NewChanConsumer(1)
+			config := tt.config
+			config.Consumer, config.Queue = consumer, eq
+			config.OnCommit = func(m *BrokerMessage, err error) {
+				if m != &msg {
+					t.Error("Expected OnCommit to get the delivered message")
+				}
+				committed <- err
+			}
+			bridge := NewBridge(config)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan error)
+			go func() { stopped <- bridge.Run(ctx) }()
+			if err := consumer.Deliver(ctx, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			event := (<-events).(*BrokerEvent)
+			if event.GetType() != tt.wantType || event.Message != &msg {
+				t.Errorf("Handled a %s event for %v, want %s", event.GetType(), event.Message, tt.wantType)
+			}
+			if _, err := event.Wait(); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Event result = %v, want %v", err, tt.wantErr)
+			}
+			// The message is committed before the event completes
+			if acked.Load() != tt.wantAck || nacked.Load() != tt.wantNack {
+				t.Errorf("Acked %v and nacked %v, want %v and %v", acked.Load(), nacked.Load(), tt.wantAck, tt.wantNack)
+			}
+			if err := <-committed; err != nil {
+				t.Errorf("OnCommit error = %v", err)
+			}
+
+			cancel()
+			if err := <-stopped; err != nil {
+				t.Errorf("Run() error = %v, want nil on cancellation", err)
+			}
+		})
+	}
+}
+
+// TestBridge_MaxInFlight tests no more than MaxInFlight messages are fetched before
+// earlier ones are done
+func TestBridge_MaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	eq := NewEventQueue(EventQueueConfig{})
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		<-release
+		return nil
+	}))
+	eq.Start(context.Background())
+	defer eq.Stop()
+
+	var fetched atomic.Int32
+	var acked sync.WaitGroup
+	acked.Add(5)
+	bridge := NewBridge(BridgeConfig{
+		Consumer: BrokerConsumerFunc(func(ctx context.Context) (*BrokerMessage, error) {
+			if fetched.Add(1) > 5 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &BrokerMessage{Topic: "cdr", Ack: func(context.Context) error {
+				acked.Done()
+				return nil
+			}}, nil
+		}),
+		Queue:       eq,
+		MaxInFlight: 2,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bridge.Run(ctx)
+
+	waitFor(t, "messages to be fetched", func() bool { return fetched.Load() == 2 })
+	time.Sleep(10 * time.Millisecond)
+	if got := fetched.Load(); got != 2 {
+		t.Errorf("Fetched %d messages with 2 in flight, want 2", got)
+	}
+
+	close(release)
+	acked.Wait()
+}
+
+// TestBridge_Errors tests fetch failures are retried and a stopped queue stops Run
+func TestBridge_Errors(t *testing.T) {
+	eq := NewEventQueue(EventQueueConfig{})
+	var fetches atomic.Int32
+	bridge := NewBridge(BridgeConfig{
+		Consumer: BrokerConsumerFunc(func(ctx context.Context) (*BrokerMessage, error) {
+			if fetches.Add(1) < 3 {
+				return nil, errors.New("broker unavailable")
+			}
+			return &BrokerMessage{Topic: "cdr"}, nil
+		}),
+		Queue:         eq,
+		RetryInterval: time.Millisecond,
+	})
+
+	// The queue was never started
+	err := bridge.Run(context.Background())
+	if !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("Run() error = %v, want ErrQueueStopped", err)
+	}
+	if got := fetches.Load(); got != 3 {
+		t.Errorf("Fetched %d times, want 3", got)
+	}
+}
+
+// TestBridge_Schemas tests message values are validated against the schema of their
+// event type, and invalid ones are nacked without stopping the bridge
+func TestBridge_Schemas(t *testing.T) {
+	schemas := NewSchemaRegistry()
+	if err := schemas.RegisterJSONSchema("cdr", []byte(`{"type": "object", "required": ["duration"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	eq := NewEventQueue(EventQueueConfig{Schemas: schemas})
+	handled := make(chan interface{}, 2)
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		handled <- event.(PayloadEvent).GetPayload()
+		return nil
+	}))
+	eq.Start(context.Background())
+	defer eq.Stop()
+
+	type commit struct {
+		offset int64
+		acked  bool
+	}
+	commits := make(chan commit, 2)
+	message := func(offset int64, value string) *BrokerMessage {
+		return &BrokerMessage{
+			Topic:  "cdr",
+			Offset: offset,
+			Value:  []byte(value),
+			Ack:    func(context.Context) error { commits <- commit{offset, true}; return nil },
+			Nack:   func(context.Context) error { commits <- commit{offset, false}; return nil },
+		}
+	}
+
+	consumer := NewChanConsumer(2)
+	bridge := NewBridge(BridgeConfig{Consumer: consumer, Queue: eq, MaxInFlight: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- bridge.Run(ctx) }()
+
+	consumer.Deliver(ctx, message(1, `{"volume": 10}`))
+	consumer.Deliver(ctx, message(2, `{"duration": 30}`))
+	for _, want := range []commit{{1, false}, {2, true}} {
+		select {
+		case got := <-commits:
+			if got != want {
+				t.Errorf("Commit = %+v, want %+v", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for the commit of offset %d", want.offset)
+		}
+	}
+	if payload := <-handled; string(payload.(json.RawMessage)) != `{"duration": 30}` {
+		t.Errorf("Handled payload %s, want the valid message value", payload)
+	}
+	if len(handled) != 0 {
+		t.Error("Expected the invalid message not to be handled")
+	}
+
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Errorf("Run() error = %v, want nil on cancellation", err)
+	}
+}
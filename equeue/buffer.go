@@ -0,0 +1,215 @@
+package equeue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// QueueBackend selects the buffer implementation behind an EventQueue
+type QueueBackend int
+
+const (
+	// ChannelBackend buffers events in a Go channel (default)
+	ChannelBackend QueueBackend = iota
+	// RingBackend buffers events in a lock-free MPSC ring buffer, which scales
+	// better under heavy producer contention (capacity is rounded up to a power of two,
+	// at least 2)
+	RingBackend
+)
+
+// String returns the string representation of QueueBackend
+func (qb QueueBackend) String() string {
+	switch qb {
+	case ChannelBackend:
+		return "channel"
+	case RingBackend:
+		return "ring"
+	default:
+		return "unknown"
+	}
+}
+
+// eventBuffer is the storage behind an EventQueue
+// offer may be called by many producers; take and poll by the processor (and Drain)
+type eventBuffer interface {
+	// offer adds an event without blocking, returning false if the buffer is full
+	offer(event IEvent) bool
+	// take blocks until an event is available, returning false once ctx is done
+	take(ctx context.Context) (IEvent, bool)
+	// poll removes an event without blocking
+	poll() (IEvent, bool)
+	// size returns the number of buffered events
+	size() int
+}
+
+// newEventBuffer creates the buffer for the configured backend
+func newEventBuffer(backend QueueBackend, capacity int) eventBuffer {
+	if backend == RingBackend {
+		return newRingBuffer(capacity)
+	}
+	return &chanBuffer{events: make(chan IEvent, capacity)}
+}
+
+// chanBuffer is the channel-based buffer
+type chanBuffer struct {
+	events chan IEvent
+}
+
+func (b *chanBuffer) offer(event IEvent) bool {
+	select {
+	case b.events <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *chanBuffer) take(ctx context.Context) (IEvent, bool) {
+	select {
+	case event := <-b.events:
+		return event, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+func (b *chanBuffer) poll() (IEvent, bool) {
+	select {
+	case event := <-b.events:
+		return event, true
+	default:
+		return nil, false
+	}
+}
+
+func (b *chanBuffer) size() int {
+	return len(b.events)
+}
+
+// cacheLinePad separates hot indices onto their own cache lines to avoid false sharing
+type cacheLinePad [64]byte
+
+// ringSlot holds one event; seq coordinates ownership between producers and the consumer
+type ringSlot struct {
+	seq   atomic.Uint64
+	event IEvent
+	_     [64 - 8 - 16]byte
+}
+
+// ringBuffer is a bounded multi-producer single-consumer ring buffer
+// Producers claim slots with a CAS on tail and never take a lock. The consumer side
+// is serialized by consumerMu, which is uncontended except while Drain abandons or Purge sheds events
+type ringBuffer struct {
+	_    cacheLinePad
+	tail atomic.Uint64 // Next slot to claim (producers)
+	_    cacheLinePad
+	head atomic.Uint64 // Next slot to consume (consumer)
+	_    cacheLinePad
+
+	mask  uint64
+	slots []ringSlot
+
+	consumerMu sync.Mutex
+	sleeping   atomic.Bool // Consumer is waiting on wake
+	wake       chan struct{}
+}
+
+// newRingBuffer creates a ring with capacity rounded up to a power of two
+// A single slot can't tell a filled slot from a consumed one, so the minimum is 2
+func newRingBuffer(capacity int) *ringBuffer {
+	size := uint64(2)
+	for size < uint64(capacity) {
+		size <<= 1
+	}
+
+	r := &ringBuffer{
+		mask:  size - 1,
+		slots: make([]ringSlot, size),
+		wake:  make(chan struct{}, 1),
+	}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+func (r *ringBuffer) offer(event IEvent) bool {
+	pos := r.tail.Load()
+	for {
+		slot := &r.slots[pos&r.mask]
+		seq := slot.seq.Load()
+
+		switch diff := int64(seq) - int64(pos); {
+		case diff == 0:
+			if r.tail.CompareAndSwap(pos, pos+1) {
+				slot.event = event
+				slot.seq.Store(pos + 1)
+				r.signal()
+				return true
+			}
+			pos = r.tail.Load()
+		case diff < 0:
+			// Slot not yet consumed from the previous lap: full
+			return false
+		default:
+			// Another producer claimed this slot; catch up
+			pos = r.tail.Load()
+		}
+	}
+}
+
+// signal wakes the consumer if it is waiting
+func (r *ringBuffer) signal() {
+	if r.sleeping.Load() && r.sleeping.CompareAndSwap(true, false) {
+		select {
+		case r.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *ringBuffer) take(ctx context.Context) (IEvent, bool) {
+	for {
+		if event, ok := r.poll(); ok {
+			return event, true
+		}
+
+		// Announce sleep, then re-check so an offer racing with us is not missed
+		r.sleeping.Store(true)
+		if event, ok := r.poll(); ok {
+			r.sleeping.Store(false)
+			return event, true
+		}
+
+		select {
+		case <-r.wake:
+		case <-ctx.Done():
+			r.sleeping.Store(false)
+			return nil, false
+		}
+	}
+}
+
+func (r *ringBuffer) poll() (IEvent, bool) {
+	r.consumerMu.Lock()
+	defer r.consumerMu.Unlock()
+
+	pos := r.head.Load()
+	slot := &r.slots[pos&r.mask]
+	if slot.seq.Load() != pos+1 {
+		return nil, false
+	}
+
+	event := slot.event
+	slot.event = nil
+	slot.seq.Store(pos + r.mask + 1)
+	r.head.Store(pos + 1)
+	return event, true
+}
+
+func (r *ringBuffer) size() int {
+	// Load head first: tail only grows, so the difference never underflows
+	head := r.head.Load()
+	return int(r.tail.Load() - head)
+}
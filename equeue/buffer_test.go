@@ -0,0 +1,133 @@
+package equeue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestEventBuffer tests FIFO order, capacity and blocking take for each backend
+func TestEventBuffer(t *testing.T) {
+	tests := []struct {
+		backend      QueueBackend
+		capacity     int
+		wantCapacity int
+	}{
+		{ChannelBackend, 5, 5},
+		{RingBackend, 5, 8}, // Rounded up to a power of two
+		{RingBackend, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend.String(), func(t *testing.T) {
+			buf := newEventBuffer(tt.backend, tt.capacity)
+			if _, ok := buf.poll(); ok {
+				t.Fatal("Expected poll on an empty buffer to fail")
+			}
+
+			events := make([]IEvent, tt.wantCapacity)
+			for i := range events {
+				events[i] = NewEvent("cdr", context.Background())
+				if !buf.offer(events[i]) {
+					t.Fatalf("offer() %d failed below capacity", i)
+				}
+			}
+			if buf.offer(NewEvent("cdr", context.Background())) {
+				t.Fatal("Expected offer on a full buffer to fail")
+			}
+			if buf.size() != tt.wantCapacity {
+				t.Errorf("size() = %d, want %d", buf.size(), tt.wantCapacity)
+			}
+
+			// Wrap around: free one slot and fill it again
+			first, _ := buf.poll()
+			last := NewEvent("cdr", context.Background())
+			if !buf.offer(last) {
+				t.Fatal("Expected offer to succeed after poll")
+			}
+			got := []IEvent{first}
+			for buf.size() > 0 {
+				event, ok := buf.take(context.Background())
+				if !ok {
+					t.Fatal("take() failed with events buffered")
+				}
+				got = append(got, event)
+			}
+			want := append(events, last)
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("Event %d out of order", i)
+				}
+			}
+		})
+	}
+}
+
+// TestEventBuffer_Take tests take waits for an offer and gives up when ctx is done
+func TestEventBuffer_Take(t *testing.T) {
+	for _, backend := range []QueueBackend{ChannelBackend, RingBackend} {
+		t.Run(backend.String(), func(t *testing.T) {
+			buf := newEventBuffer(backend, 4)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if _, ok := buf.take(ctx); ok {
+				t.Fatal("Expected take on an empty buffer to fail when ctx is done")
+			}
+
+			event := NewEvent("cdr", context.Background())
+			go func() {
+				time.Sleep(5 * time.Millisecond)
+				buf.offer(event)
+			}()
+			got, ok := buf.take(context.Background())
+			if !ok || got != event {
+				t.Errorf("take() = %v, %v, want the offered event", got, ok)
+			}
+		})
+	}
+}
+
+// TestEventBuffer_Contended tests concurrent producers deliver every event exactly once
+func TestEventBuffer_Contended(t *testing.T) {
+	const producers, perProducer = 8, 500
+
+	for _, backend := range []QueueBackend{ChannelBackend, RingBackend} {
+		t.Run(backend.String(), func(t *testing.T) {
+			buf := newEventBuffer(backend, 64)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			var wg sync.WaitGroup
+			for p := 0; p < producers; p++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < perProducer; i++ {
+						event := NewEvent("cdr", context.Background())
+						for !buf.offer(event) {
+							time.Sleep(time.Microsecond)
+						}
+					}
+				}()
+			}
+
+			seen := make(map[uint64]bool, producers*perProducer)
+			for len(seen) < producers*perProducer {
+				event, ok := buf.take(ctx)
+				if !ok {
+					t.Fatalf("Timed out after %d events", len(seen))
+				}
+				if seen[event.GetID()] {
+					t.Fatalf("Event %d delivered twice", event.GetID())
+				}
+				seen[event.GetID()] = true
+			}
+			wg.Wait()
+			if buf.size() != 0 {
+				t.Errorf("Expected an empty buffer, got %d", buf.size())
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Cancellation causes, set with context.Cause on handler contexts and returned
+// (wrapped) as event results so callers can tell why handling was cut short
+var (
+	// ErrQueueStopped is returned by Enqueue once the queue is stopping or stopped
+	ErrQueueStopped = errors.New("queue is stopped")
+
+	// ErrShuttingDown cancels handlers still running when a drain deadline passes
+	ErrShuttingDown = errors.New("queue is shutting down")
+
+	// ErrEventExpired completes events whose deadline passed, before or during handling
+	ErrEventExpired = errors.New("event expired: deadline exceeded")
+
+	// ErrHandlerTimeout cancels handling that exceeded WithHandlerTimeout
+	ErrHandlerTimeout = errors.New("handler timed out")
+
+	// ErrOverloadPurge completes queued events shed by Purge
+	ErrOverloadPurge = errors.New("event purged under overload")
+)
+
+// Diameter result codes for answering requests that failed in the queue
+const (
+	DiameterSuccess        uint32 = 2001 // DIAMETER_SUCCESS
+	DiameterTooBusy        uint32 = 3004 // DIAMETER_TOO_BUSY: the client should try another peer
+	DiameterUnableToComply uint32 = 5012 // DIAMETER_UNABLE_TO_COMPLY
+)
+
+// IsShutdown reports whether err means the queue is stopping rather than the request failing
+func IsShutdown(err error) bool {
+	return errors.Is(err, ErrShuttingDown) || errors.Is(err, ErrQueueShutdown) || errors.Is(err, ErrQueueStopped) || errors.Is(err, ErrQueueHandover)
+}
+
+// IsTimeout reports whether err means the request ran out of time
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrEventExpired) || errors.Is(err, ErrHandlerTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsOverload reports whether err means the event was rejected or shed under load
+func IsOverload(err error) bool {
+	return errors.Is(err, ErrOverloadPurge) || errors.Is(err, ErrQueueFull)
+}
+
+// DiameterResultCode maps an event result to the Result-Code of its answer
+// Shutdown and overload answer DIAMETER_TOO_BUSY so the client retries on another
+// peer; timeouts and handler failures answer DIAMETER_UNABLE_TO_COMPLY
+func DiameterResultCode(err error) uint32 {
+	switch {
+	case err == nil:
+		return DiameterSuccess
+	case IsShutdown(err), IsOverload(err):
+		return DiameterTooBusy
+	default:
+		return DiameterUnableToComply
+	}
+}
+
+// withCause annotates a handler error caused by ctx being cancelled with the
+// cancellation cause, keeping the original error for errors.Is
+func withCause(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	cause := context.Cause(ctx)
+	if cause == nil || cause == ctx.Err() || errors.Is(err, cause) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", cause, err)
+}
+
+// handlerContext returns the context an event is handled with: the event's context,
+// cancelled with ErrEventExpired at the event deadline and with ErrShuttingDown when
+// a drain deadline passes
+func (eq *EventQueue) handlerContext(event IEvent) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(event.GetContext())
+	stop := func() bool { return false }
+	if abort := eq.abort; abort != nil {
+		stop = context.AfterFunc(abort, func() { cancel(context.Cause(abort)) })
+	}
+
+	cancelDeadline := context.CancelFunc(func() {})
+	if event.HasDeadline() {
+		ctx, cancelDeadline = context.WithDeadlineCause(ctx, event.GetDeadline(), ErrEventExpired)
+	}
+
+	return ctx, func() {
+		stop()
+		cancelDeadline()
+		cancel(nil)
+	}
+}
+
+// Purge sheds queued events matching match (all if nil) under overload, completing
+// them with ErrOverloadPurge so callers can answer with a busy result; events that
+// don't match are requeued behind any enqueued meanwhile. Returns the number purged
+func (eq *EventQueue) Purge(match func(IEvent) bool) int {
+	var kept []IEvent
+	purged := 0
+	for {
+		event, ok := eq.events.poll()
+		if !ok {
+			break
+		}
+		if match != nil && !match(event) {
+			kept = append(kept, event)
+			continue
+		}
+		eq.purge(event)
+		purged++
+	}
+
+	for _, event := range kept {
+		if !eq.events.offer(event) {
+			eq.purge(event)
+			purged++
+		}
+	}
+	eq.observeDepth()
+	return purged
+}
+
+// purge completes a queued event with ErrOverloadPurge
+func (eq *EventQueue) purge(event IEvent) {
+	eq.onRemove(event)
+	eq.onLoss(event, LossPurged)
+	eq.complete(event, nil, ErrOverloadPurge)
+	eq.audit(event, OutcomePurged, time.Since(event.GetTimestamp()), 0, ErrOverloadPurge)
+}
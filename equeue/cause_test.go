@@ -0,0 +1,282 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCauseClassification tests event results map to the right class and Result-Code
+func TestCauseClassification(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantShutdown bool
+		wantTimeout  bool
+		wantOverload bool
+		wantCode     uint32
+	}{
+		{name: "success", err: nil, wantCode: DiameterSuccess},
+		{name: "handler error", err: errors.New("hss unreachable"), wantCode: DiameterUnableToComply},
+		{name: "shutting down", err: fmt.Errorf("%w: %w", ErrShuttingDown, context.Canceled), wantShutdown: true, wantCode: DiameterTooBusy},
+		{name: "queue shutdown", err: ErrQueueShutdown, wantShutdown: true, wantCode: DiameterTooBusy},
+		{name: "queue stopped", err: ErrQueueStopped, wantShutdown: true, wantCode: DiameterTooBusy},
+		{name: "handed over", err: ErrQueueHandover, wantShutdown: true, wantCode: DiameterTooBusy},
+		{name: "expired", err: ErrEventExpired, wantTimeout: true, wantCode: DiameterUnableToComply},
+		{name: "handler timeout", err: fmt.Errorf("%w: %w", ErrHandlerTimeout, context.DeadlineExceeded), wantTimeout: true, wantCode: DiameterUnableToComply},
+		{name: "deadline exceeded", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantTimeout: true, wantCode: DiameterUnableToComply},
+		{name: "purged", err: ErrOverloadPurge, wantOverload: true, wantCode: DiameterTooBusy},
+		{name: "queue full", err: fmt.Errorf("enqueue: %w", ErrQueueFull), wantOverload: true, wantCode: DiameterTooBusy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsShutdown(tt.err); got != tt.wantShutdown {
+				t.Errorf("IsShutdown() = %v, want %v", got, tt.wantShutdown)
+			}
+			if got := IsTimeout(tt.err); got != tt.wantTimeout {
+				t.Errorf("IsTimeout() = %v, want %v", got, tt.wantTimeout)
+			}
+			if got := IsOverload(tt.err); got != tt.wantOverload {
+				t.Errorf("IsOverload() = %v, want %v", got, tt.wantOverload)
+			}
+			if got := DiameterResultCode(tt.err); got != tt.wantCode {
+				t.Errorf("DiameterResultCode() = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestWithCause tests handler errors are annotated with the cause of a cancelled context
+func TestWithCause(t *testing.T) {
+	errRejected := errors.New("rejected")
+	cancelled := func(cause error) context.Context {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(cause)
+		return ctx
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		wantIs   []error
+		wantSame bool // The error is returned unchanged
+	}{
+		{name: "no error", ctx: cancelled(ErrShuttingDown), err: nil, wantSame: true},
+		{name: "context live", ctx: context.Background(), err: context.Canceled, wantSame: true},
+		{name: "unrelated error", ctx: cancelled(ErrShuttingDown), err: errRejected, wantSame: true},
+		{name: "no cause", ctx: cancelled(nil), err: context.Canceled, wantSame: true},
+		{name: "cause already wrapped", ctx: cancelled(ErrShuttingDown), err: fmt.Errorf("%w: %w", ErrShuttingDown, context.Canceled), wantSame: true},
+		{name: "annotated", ctx: cancelled(ErrShuttingDown), err: context.Canceled, wantIs: []error{ErrShuttingDown, context.Canceled}},
+		{name: "annotated wrapped", ctx: cancelled(ErrEventExpired), err: fmt.Errorf("query: %w", context.Canceled), wantIs: []error{ErrEventExpired, context.Canceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withCause(tt.ctx, tt.err)
+			if tt.wantSame && got != tt.err {
+				t.Errorf("withCause() = %v, want %v unchanged", got, tt.err)
+			}
+			for _, want := range tt.wantIs {
+				if !errors.Is(got, want) {
+					t.Errorf("withCause() = %v, want it to match %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestEventQueue_CancellationCause tests handlers see why their context was
+// cancelled, and the event result carries the cause
+func TestEventQueue_CancellationCause(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []HandlerOption
+		event     func() *Event
+		wantCause error
+	}{
+		{
+			name: "event deadline",
+			event: func() *Event {
+				return NewEvent("ulr", context.Background(), WithTimeout(5*time.Millisecond))
+			},
+			wantCause: ErrEventExpired,
+		},
+		{
+			name:    "handler timeout",
+			options: []HandlerOption{WithHandlerTimeout(5 * time.Millisecond)},
+			event: func() *Event {
+				return NewEvent("ulr", context.Background(), WithTimeout(time.Minute))
+			},
+			wantCause: ErrHandlerTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cause error
+			eq := NewTestQueue()
+			eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				<-ctx.Done()
+				cause = context.Cause(ctx)
+				return ctx.Err()
+			}), tt.options...)
+
+			event := tt.event()
+			eq.Enqueue(event)
+			if !errors.Is(cause, tt.wantCause) {
+				t.Errorf("Handler context cause = %v, want %v", cause, tt.wantCause)
+			}
+			_, err := event.Wait()
+			if !errors.Is(err, tt.wantCause) || !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Result = %v, want %v wrapping context.DeadlineExceeded", err, tt.wantCause)
+			}
+			if !IsTimeout(err) || DiameterResultCode(err) != DiameterUnableToComply {
+				t.Errorf("Expected %v to be a timeout answered with DIAMETER_UNABLE_TO_COMPLY", err)
+			}
+		})
+	}
+}
+
+// TestEventQueue_Purge tests Purge sheds matching events and requeues the rest in order
+func TestEventQueue_Purge(t *testing.T) {
+	eq := NewEventQueue(EventQueueConfig{BufferSize: 10})
+	events := make([]*Event, 6)
+	for i := range events {
+		eventType := "cdr"
+		if i%2 == 1 {
+			eventType = "ulr"
+		}
+		events[i] = NewEvent(eventType, context.Background())
+	}
+
+	// Queue without processing so nothing is dequeued meanwhile
+	for _, event := range events {
+		if !eq.events.offer(event) {
+			t.Fatal("offer() failed below capacity")
+		}
+	}
+	if n := eq.Purge(func(event IEvent) bool { return event.GetType() == "cdr" }); n != 3 {
+		t.Errorf("Purge() = %d, want 3", n)
+	}
+	if eq.GetQueueSize() != 3 {
+		t.Errorf("GetQueueSize() = %d, want 3", eq.GetQueueSize())
+	}
+
+	for i, event := range events {
+		if event.GetType() != "cdr" {
+			continue
+		}
+		if _, err := event.Wait(); !errors.Is(err, ErrOverloadPurge) || DiameterResultCode(err) != DiameterTooBusy {
+			t.Errorf("Event %d result = %v, want ErrOverloadPurge", i, err)
+		}
+	}
+	for _, want := range []*Event{events[1], events[3], events[5]} {
+		if event, ok := eq.events.poll(); !ok || event != want {
+			t.Errorf("Expected the kept events requeued in order, got %v", event)
+		}
+	}
+
+	if n := eq.Purge(nil); n != 0 {
+		t.Errorf("Purge() on an empty queue = %d, want 0", n)
+	}
+}
+
+// TestEventQueue_DepthAfterRemoval tests events taken off the queue without being
+// handled leave the depth gauge and count as completed
+func TestEventQueue_DepthAfterRemoval(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(eq *EventQueue)
+		want map[string]uint64 // Completed events by outcome
+	}{
+		{
+			name: "purge",
+			stop: func(eq *EventQueue) { eq.Purge(nil) },
+			want: map[string]uint64{OutcomePurged: 4},
+		},
+		{
+			name: "handover",
+			stop: func(eq *EventQueue) {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				eq.Handover(ctx)
+			},
+			want: map[string]uint64{OutcomeHandedOver: 5},
+		},
+		{
+			name: "handover write fails",
+			stop: func(eq *EventQueue) {
+				eq.handover.Path = filepath.Join(filepath.Dir(eq.handover.Path), "missing", "handover.json")
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				eq.Handover(ctx)
+			},
+			want: map[string]uint64{OutcomeAbandoned: 5},
+		},
+		{
+			name: "drain deadline",
+			stop: func(eq *EventQueue) { eq.StopWithTimeout(10 * time.Millisecond) },
+			want: map[string]uint64{OutcomeFailed: 1, OutcomeAbandoned: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := NewQueueMetrics("q")
+			eq := NewEventQueue(EventQueueConfig{
+				Metrics:  metrics,
+				Handover: &HandoverConfig{Path: filepath.Join(t.TempDir(), "handover.json")},
+			})
+			started := make(chan struct{}, 1)
+			release := make(chan struct{})
+			eq.RegisterHandler("a", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				started <- struct{}{}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-release:
+					return nil
+				}
+			}))
+			eq.Start(context.Background())
+			defer eq.Stop()
+			defer close(release)
+
+			for i := 0; i < 5; i++ {
+				eq.Enqueue(NewEvent("a", context.Background()))
+			}
+			<-started
+			if depth := metrics.Snapshot()[0].Depth; depth != 4 {
+				t.Fatalf("Depth = %d with one event in flight, want 4", depth)
+			}
+
+			tt.stop(eq)
+			var want uint64
+			for _, n := range tt.want {
+				want += n
+			}
+			waitFor(t, "events to complete", func() bool {
+				var completed uint64
+				for _, n := range metrics.Snapshot()[0].Outcomes {
+					completed += n
+				}
+				return completed == want
+			})
+
+			stats := metrics.Snapshot()[0]
+			if stats.Depth != 0 {
+				t.Errorf("Depth = %d, want 0", stats.Depth)
+			}
+			for _, outcome := range outcomes {
+				if stats.Outcomes[outcome] != tt.want[outcome] {
+					t.Errorf("Outcomes[%s] = %d, want %d", outcome, stats.Outcomes[outcome], tt.want[outcome])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package equeue
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotentEvent is implemented by events that carry an idempotency key
+// Events with the same key enqueued while the original is pending, or within the dedup
+// window after it completes, are coalesced
+type IdempotentEvent interface {
+	GetIdempotencyKey() string
+}
+
+// WithIdempotencyKey sets the idempotency key used for duplicate suppression
+// (e.g., Origin-Host + Hop-by-Hop/End-to-End IDs of a retransmitted Diameter request)
+func WithIdempotencyKey(key string) EventOption {
+	return func(e *Event) {
+		e.idempotencyKey = key
+	}
+}
+
+// GetIdempotencyKey returns the idempotency key, or "" if none was set
+func (e *Event) GetIdempotencyKey() string {
+	return e.idempotencyKey
+}
+
+// dedupEntry tracks the original event for an idempotency key
+type dedupEntry struct {
+	expires   time.Time // Set on completion: the window counts from the original's result
+	completed bool
+	result    interface{}
+	err       error
+	waiters   []IEvent // Duplicates waiting for the original's result
+}
+
+// deduplicator coalesces events sharing an idempotency key within a window
+type deduplicator struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*dedupEntry
+	lastSweep time.Time
+}
+
+// newDeduplicator creates a deduplicator with the given window
+func newDeduplicator(window time.Duration) *deduplicator {
+	return &deduplicator{
+		window:  window,
+		entries: make(map[string]*dedupEntry),
+	}
+}
+
+// admit registers an event, returning false if it duplicates a known event
+// Duplicates are completed with the original's result, immediately if it is already known
+func (d *deduplicator) admit(key string, event IEvent) bool {
+	now := time.Now()
+
+	d.mu.Lock()
+	d.sweep(now)
+
+	entry, exists := d.entries[key]
+	if !exists || (entry.completed && now.After(entry.expires)) {
+		d.entries[key] = &dedupEntry{}
+		d.mu.Unlock()
+		return true
+	}
+
+	if !entry.completed {
+		entry.waiters = append(entry.waiters, event)
+		d.mu.Unlock()
+		return false
+	}
+	result, err := entry.result, entry.err
+	d.mu.Unlock()
+
+	event.Done(result, err)
+	return false
+}
+
+// forget removes a key whose original event was rejected, failing waiting duplicates with err
+func (d *deduplicator) forget(key string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, exists := d.entries[key]
+	if !exists {
+		return
+	}
+	delete(d.entries, key)
+
+	for _, waiter := range entry.waiters {
+		waiter.Done(nil, err)
+	}
+}
+
+// complete records the original's result and completes waiting duplicates
+func (d *deduplicator) complete(key string, result interface{}, err error) {
+	d.mu.Lock()
+	entry, exists := d.entries[key]
+	if !exists {
+		d.mu.Unlock()
+		return
+	}
+	entry.completed = true
+	entry.expires = time.Now().Add(d.window)
+	entry.result = result
+	entry.err = err
+	waiters := entry.waiters
+	entry.waiters = nil
+	d.mu.Unlock()
+
+	for _, waiter := range waiters {
+		waiter.Done(result, err)
+	}
+}
+
+// sweep drops completed entries past their window, at most once per window
+// Pending entries are kept so retransmissions during slow processing still coalesce
+func (d *deduplicator) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	d.lastSweep = now
+
+	for key, entry := range d.entries {
+		if entry.completed && now.After(entry.expires) {
+			delete(d.entries, key)
+		}
+	}
+}
+
+// idempotencyKey returns the event's idempotency key, or "" if it has none
+func idempotencyKey(event IEvent) string {
+	if ie, ok := event.(IdempotentEvent); ok {
+		return ie.GetIdempotencyKey()
+	}
+	return ""
+}
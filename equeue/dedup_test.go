@@ -0,0 +1,115 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestEventQueue_Dedup tests duplicates are coalesced while the original is pending
+// and within the window after it completes
+func TestEventQueue_Dedup(t *testing.T) {
+	const window = 50 * time.Millisecond
+
+	tests := []struct {
+		name        string
+		handlerTime time.Duration // Time the original takes to handle
+		delay       time.Duration // Time after the original completes the duplicate is enqueued
+		handlerErr  error
+		wantHandled int32
+		wantDup     uint64
+	}{
+		{name: "within window", delay: 0, wantHandled: 1, wantDup: 1},
+		{name: "result shared", delay: 0, handlerErr: errors.New("hss unreachable"), wantHandled: 1, wantDup: 1},
+		{name: "slow original", handlerTime: 3 * window, delay: window / 5, wantHandled: 1, wantDup: 1},
+		{name: "after window", delay: 2 * window, wantHandled: 2, wantDup: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled atomic.Int32
+			eq := NewEventQueue(EventQueueConfig{DedupWindow: window})
+			eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				handled.Add(1)
+				time.Sleep(tt.handlerTime)
+				return tt.handlerErr
+			}))
+			if err := eq.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			defer eq.Stop()
+
+			original := NewEvent("ulr", context.Background(), WithIdempotencyKey("hss1;42"))
+			if err := eq.Enqueue(original); err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			if _, err := original.Wait(); !errors.Is(err, tt.handlerErr) {
+				t.Fatalf("Original error = %v, want %v", err, tt.handlerErr)
+			}
+
+			time.Sleep(tt.delay)
+			duplicate := NewEvent("ulr", context.Background(), WithIdempotencyKey("hss1;42"))
+			if err := eq.Enqueue(duplicate); err != nil {
+				t.Fatalf("Enqueue() duplicate error = %v", err)
+			}
+			result, err := duplicate.Wait()
+			if !errors.Is(err, tt.handlerErr) || (err == nil && result != "processed") {
+				t.Errorf("Duplicate result = %v, %v, want the original's", result, err)
+			}
+
+			if got := handled.Load(); got != tt.wantHandled {
+				t.Errorf("Handled %d events, want %d", got, tt.wantHandled)
+			}
+			if got := eq.DuplicateCount(); got != tt.wantDup {
+				t.Errorf("DuplicateCount() = %d, want %d", got, tt.wantDup)
+			}
+		})
+	}
+}
+
+// TestEventQueue_DedupPending tests duplicates enqueued while the original is being
+// handled wait for its result
+func TestEventQueue_DedupPending(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var handled atomic.Int32
+
+	eq := NewEventQueue(EventQueueConfig{DedupWindow: time.Millisecond})
+	eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		if handled.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return nil
+	}))
+	if err := eq.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer eq.Stop()
+
+	original := NewEvent("ulr", context.Background(), WithIdempotencyKey("k"))
+	eq.Enqueue(original)
+	<-started
+
+	// Well past the window, but the original has not completed
+	time.Sleep(10 * time.Millisecond)
+	duplicates := make([]*Event, 3)
+	for i := range duplicates {
+		duplicates[i] = NewEvent("ulr", context.Background(), WithIdempotencyKey("k"))
+		if err := eq.Enqueue(duplicates[i]); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	close(release)
+
+	for _, duplicate := range duplicates {
+		if result, err := duplicate.Wait(); err != nil || result != "processed" {
+			t.Errorf("Duplicate result = %v, %v", result, err)
+		}
+	}
+	if handled.Load() != 1 || eq.DuplicateCount() != 3 {
+		t.Errorf("Expected 1 handled and 3 duplicates, got %d and %d", handled.Load(), eq.DuplicateCount())
+	}
+}
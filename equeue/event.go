@@ -80,6 +80,9 @@ type Event struct {
 	eventCtx  *EventContext
 	timestamp time.Time
 	deadline  time.Time
+
+	idempotencyKey string
+	payload        interface{}
 }
 
 // EventOption is a function that configures an Event
@@ -99,6 +102,14 @@ func WithTimeout(timeout time.Duration) EventOption {
 	}
 }
 
+// WithTimestamp sets the creation time of the event, e.g. to restore an event
+// created by another process; WithTimeout is relative to it, so it must come first
+func WithTimestamp(timestamp time.Time) EventOption {
+	return func(e *Event) {
+		e.timestamp = timestamp
+	}
+}
+
 // NewEvent creates a new event instance with auto-incrementing ID
 func NewEvent(eventType string, ctx context.Context, options ...EventOption) *Event {
 	event := &Event{
@@ -0,0 +1,169 @@
+package equeue
+
+import (
+	"context"
+	"sync"
+)
+
+// fairBuffer queues events per type and dequeues them by weighted round robin, so a
+// flood of one event type cannot starve the others
+// Each turn a type may dequeue up to its weight in events before the next type's turn.
+// Types whose handler has no free slot are skipped, so a saturated handler does not
+// hold up the processing loop while other types have work
+type fairBuffer struct {
+	mu            sync.Mutex
+	capacity      int
+	count         int
+	queues        map[string]*typeQueue
+	active        []*typeQueue // Non-empty queues in round robin order
+	next          int          // Index in active of the queue whose turn it is
+	weights       map[string]int
+	defaultWeight int
+
+	ready func(eventType string) bool // Reports whether an event of the type can be dispatched
+	wake  chan struct{}
+}
+
+// typeQueue is the FIFO of one event type
+type typeQueue struct {
+	eventType string
+	weight    int
+	credit    int // Events left in the current turn
+	events    []IEvent
+	head      int
+}
+
+// newFairBuffer creates a fair buffer holding up to capacity events across all types
+func newFairBuffer(capacity int, weights map[string]int, defaultWeight int) *fairBuffer {
+	if defaultWeight <= 0 {
+		defaultWeight = 1
+	}
+	return &fairBuffer{
+		capacity:      capacity,
+		queues:        make(map[string]*typeQueue),
+		weights:       weights,
+		defaultWeight: defaultWeight,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+func (f *fairBuffer) offer(event IEvent) bool {
+	f.mu.Lock()
+	if f.count >= f.capacity {
+		f.mu.Unlock()
+		return false
+	}
+
+	q := f.queues[event.GetType()]
+	if q == nil {
+		q = &typeQueue{eventType: event.GetType(), weight: f.weightOf(event.GetType())}
+		f.queues[q.eventType] = q
+	}
+	if q.len() == 0 {
+		q.credit = q.weight
+		f.active = append(f.active, q)
+	}
+	q.events = append(q.events, event)
+	f.count++
+	f.mu.Unlock()
+
+	f.signal()
+	return true
+}
+
+// weightOf returns the configured weight of an event type
+func (f *fairBuffer) weightOf(eventType string) int {
+	if w := f.weights[eventType]; w > 0 {
+		return w
+	}
+	return f.defaultWeight
+}
+
+// signal wakes take, after an offer or when a handler slot is released
+func (f *fairBuffer) signal() {
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (f *fairBuffer) take(ctx context.Context) (IEvent, bool) {
+	for {
+		if event, ok := f.dequeue(f.ready); ok {
+			return event, true
+		}
+
+		select {
+		case <-f.wake:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// poll dequeues in weighted order regardless of handler readiness (used while draining)
+func (f *fairBuffer) poll() (IEvent, bool) {
+	return f.dequeue(nil)
+}
+
+// dequeue removes the next event whose type is ready, in weighted round robin order
+func (f *fairBuffer) dequeue(ready func(string) bool) (IEvent, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for tried := 0; tried < len(f.active); tried++ {
+		if f.next >= len(f.active) {
+			f.next = 0
+		}
+		q := f.active[f.next]
+
+		if q.credit <= 0 || (ready != nil && !ready(q.eventType)) {
+			// Turn over: the queue gets a fresh quantum on its next turn
+			q.credit = q.weight
+			f.next++
+			continue
+		}
+
+		event := q.pop()
+		f.count--
+		q.credit--
+		if q.len() == 0 {
+			f.active = append(f.active[:f.next], f.active[f.next+1:]...)
+		} else if q.credit <= 0 {
+			q.credit = q.weight
+			f.next++
+		}
+		return event, true
+	}
+	return nil, false
+}
+
+func (f *fairBuffer) size() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// len returns the number of queued events
+func (q *typeQueue) len() int {
+	return len(q.events) - q.head
+}
+
+// pop removes the oldest event, compacting the backing slice once half of it is consumed
+func (q *typeQueue) pop() IEvent {
+	event := q.events[q.head]
+	q.events[q.head] = nil
+	q.head++
+
+	switch {
+	case q.head == len(q.events):
+		q.events = q.events[:0]
+		q.head = 0
+	case q.head > len(q.events)/2:
+		n := copy(q.events, q.events[q.head:])
+		clear(q.events[n:])
+		q.events = q.events[:n]
+		q.head = 0
+	}
+	return event
+}
@@ -0,0 +1,136 @@
+package equeue
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestFairBuffer tests events are dequeued by weighted round robin across types
+func TestFairBuffer(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]int
+		offered string // Event types offered, one letter each
+		ready   func(eventType string) bool
+		want    string
+	}{
+		{
+			name:    "equal weights",
+			offered: "aaabbc",
+			want:    "abcaba",
+		},
+		{
+			name:    "weighted",
+			weights: map[string]int{"a": 3},
+			offered: "aaaaaabbb",
+			want:    "aaabaaabb",
+		},
+		{
+			name:    "single type",
+			weights: map[string]int{"a": 2},
+			offered: "aaaaa",
+			want:    "aaaaa",
+		},
+		{
+			name:    "type not ready",
+			offered: "aabb",
+			ready:   func(eventType string) bool { return eventType != "a" },
+			want:    "bb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFairBuffer(len(tt.offered), tt.weights, 0)
+			f.ready = tt.ready
+			for _, eventType := range tt.offered {
+				if !f.offer(NewEvent(string(eventType), context.Background())) {
+					t.Fatalf("offer(%c) failed below capacity", eventType)
+				}
+			}
+			if f.offer(NewEvent("a", context.Background())) {
+				t.Error("Expected offer on a full buffer to fail")
+			}
+
+			var got strings.Builder
+			for {
+				event, ok := f.dequeue(f.ready)
+				if !ok {
+					break
+				}
+				got.WriteString(event.GetType())
+			}
+			if got.String() != tt.want {
+				t.Errorf("Dequeued %q, want %q", got.String(), tt.want)
+			}
+			if f.size() != len(tt.offered)-len(tt.want) {
+				t.Errorf("size() = %d, want %d", f.size(), len(tt.offered)-len(tt.want))
+			}
+		})
+	}
+}
+
+// TestFairBuffer_Poll tests poll dequeues types that are not ready, as when draining
+func TestFairBuffer_Poll(t *testing.T) {
+	f := newFairBuffer(4, nil, 1)
+	f.ready = func(string) bool { return false }
+	f.offer(NewEvent("a", context.Background()))
+	f.offer(NewEvent("b", context.Background()))
+
+	if _, ok := f.dequeue(f.ready); ok {
+		t.Fatal("Expected no event while no type is ready")
+	}
+	for _, want := range []string{"a", "b"} {
+		event, ok := f.poll()
+		if !ok || event.GetType() != want {
+			t.Fatalf("poll() = %v, %v, want a %s event", event, ok, want)
+		}
+	}
+	if _, ok := f.poll(); ok {
+		t.Error("Expected poll on an empty buffer to fail")
+	}
+}
+
+// TestEventQueue_FairQueuing tests a saturated event type does not hold up the others
+func TestEventQueue_FairQueuing(t *testing.T) {
+	release := make(chan struct{})
+	eq := NewEventQueue(EventQueueConfig{TypeWeights: map[string]int{"ulr": 2, "cdr": 1}})
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		<-release
+		return nil
+	}), WithConcurrency(2))
+	eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		return nil
+	}), WithConcurrency(4))
+	if err := eq.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	cdrs := make([]*Event, 3)
+	for i := range cdrs {
+		cdrs[i] = NewEvent("cdr", context.Background())
+		eq.Enqueue(cdrs[i])
+	}
+	ulrs := make([]*Event, 5)
+	for i := range ulrs {
+		ulrs[i] = NewEvent("ulr", context.Background())
+		eq.Enqueue(ulrs[i])
+	}
+
+	// Every ulr completes while both cdr slots are blocked and a third cdr waits for one
+	for i, event := range ulrs {
+		if _, err := event.Wait(); err != nil {
+			t.Errorf("ulr %d error = %v", i, err)
+		}
+	}
+	close(release)
+	for i, event := range cdrs {
+		if _, err := event.Wait(); err != nil {
+			t.Errorf("cdr %d error = %v", i, err)
+		}
+	}
+	if err := eq.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,147 @@
+package equeue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// EventGroup enqueues a set of related events, such as the backend events one
+// provisioning request fans out into, and waits for all of their results
+// Like errgroup, a group created with NewEventGroupWithContext cancels its context
+// on the first failure so events created with it can stop early
+// The group waits on its events; callers must not call Wait on them
+type EventGroup struct {
+	queue  IEventQueue
+	cancel context.CancelCauseFunc
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	results []GroupResult
+}
+
+// GroupResult is the outcome of one event of a group
+type GroupResult struct {
+	Event  IEvent
+	Result interface{}
+	Err    error // Handler error, or the Enqueue error if the event was rejected
+}
+
+// EventError is the failure of one event of a group
+type EventError struct {
+	EventID   uint64
+	EventType string
+	Err       error
+}
+
+func (e *EventError) Error() string {
+	return fmt.Sprintf("event %d (%s): %v", e.EventID, e.EventType, e.Err)
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// GroupError aggregates the failed events of a group, in enqueue order
+// errors.Is and errors.As match any of the events' errors
+type GroupError struct {
+	Errors []*EventError
+	Total  int // Events in the group
+}
+
+func (e *GroupError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d events failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *GroupError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// NewEventGroup creates a group enqueuing events on queue
+func NewEventGroup(queue IEventQueue) *EventGroup {
+	return &EventGroup{queue: queue}
+}
+
+// NewEventGroupWithContext creates a group and a context derived from ctx, cancelled
+// with the failing event's error as cause when an event of the group fails
+// Create the group's events with the returned context
+func NewEventGroupWithContext(ctx context.Context, queue IEventQueue) (*EventGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &EventGroup{queue: queue, cancel: cancel}, ctx
+}
+
+// Enqueue adds event to the queue as part of the group
+// A rejected event still counts as a failed member of the group; the Enqueue
+// error is returned as well so the caller can stop fanning out
+func (g *EventGroup) Enqueue(event IEvent) error {
+	g.mu.Lock()
+	i := len(g.results)
+	g.results = append(g.results, GroupResult{Event: event})
+	g.mu.Unlock()
+
+	if err := g.queue.Enqueue(event); err != nil {
+		g.finish(i, nil, err)
+		return err
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		result, err := event.Wait()
+		g.finish(i, result, err)
+	}()
+	return nil
+}
+
+// finish records the outcome of the i-th event
+func (g *EventGroup) finish(i int, result interface{}, err error) {
+	g.mu.Lock()
+	r := &g.results[i]
+	r.Result, r.Err = result, err
+	g.mu.Unlock()
+
+	if err != nil && g.cancel != nil {
+		g.cancel(err)
+	}
+}
+
+// Len returns the number of events enqueued in the group
+func (g *EventGroup) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.results)
+}
+
+// Wait waits for every event of the group and returns their results in enqueue
+// order, with a *GroupError if any failed
+// Events must not be added to the group while Wait is running
+func (g *EventGroup) Wait() ([]GroupResult, error) {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(context.Canceled)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	results := append([]GroupResult(nil), g.results...)
+
+	var failed []*EventError
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, &EventError{EventID: r.Event.GetID(), EventType: r.Event.GetType(), Err: r.Err})
+		}
+	}
+	if len(failed) > 0 {
+		return results, &GroupError{Errors: failed, Total: len(results)}
+	}
+	return results, nil
+}
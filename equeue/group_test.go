@@ -0,0 +1,149 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestEventGroup tests group results are returned in enqueue order with the failures
+func TestEventGroup(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name       string
+		eventTypes []string
+		stopped    bool // Enqueue on a stopped queue
+		wantErrs   []error
+		wantError  string
+	}{
+		{
+			name:       "all processed",
+			eventTypes: []string{"hss", "pcrf", "ocs"},
+			wantErrs:   []error{nil, nil, nil},
+		},
+		{
+			name:       "one failed",
+			eventTypes: []string{"hss", "reject", "ocs"},
+			wantErrs:   []error{nil, errRejected, nil},
+			wantError:  "1 of 3 events failed: event 2 (reject): rejected",
+		},
+		{
+			name:       "rejected",
+			eventTypes: []string{"hss", "pcrf"},
+			stopped:    true,
+			wantErrs:   []error{ErrQueueStopped, ErrQueueStopped},
+			wantError:  "2 of 2 events failed: event 1 (hss): queue is stopped; event 2 (pcrf): queue is stopped",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eq := NewTestQueue()
+			for _, eventType := range []string{"hss", "pcrf", "ocs"} {
+				eq.RegisterHandler(eventType, EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+					return nil
+				}))
+			}
+			eq.RegisterHandler("reject", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				return errRejected
+			}))
+			if tt.stopped {
+				eq.Start(context.Background())
+				eq.Stop()
+			}
+
+			group := NewEventGroup(eq)
+			var ids []uint64
+			for _, eventType := range tt.eventTypes {
+				event := NewEvent(eventType, context.Background())
+				ids = append(ids, event.GetID())
+				err := group.Enqueue(event)
+				if tt.stopped != (err != nil) {
+					t.Errorf("Enqueue() error = %v", err)
+				}
+			}
+			if group.Len() != len(tt.eventTypes) {
+				t.Errorf("Len() = %d, want %d", group.Len(), len(tt.eventTypes))
+			}
+
+			results, err := group.Wait()
+			if len(results) != len(tt.eventTypes) {
+				t.Fatalf("Expected %d results, got %d", len(tt.eventTypes), len(results))
+			}
+			for i, r := range results {
+				if r.Event.GetID() != ids[i] {
+					t.Errorf("Result %d is event %d, want %d", i, r.Event.GetID(), ids[i])
+				}
+				if !errors.Is(r.Err, tt.wantErrs[i]) || (tt.wantErrs[i] == nil && r.Err != nil) {
+					t.Errorf("Result %d error = %v, want %v", i, r.Err, tt.wantErrs[i])
+				}
+			}
+
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("Wait() error = %v", err)
+				}
+				return
+			}
+			// Event IDs are global, so match the message with them rebased
+			var groupErr *GroupError
+			if !errors.As(err, &groupErr) {
+				t.Fatalf("Wait() error = %v, want a *GroupError", err)
+			}
+			for _, e := range groupErr.Errors {
+				e.EventID -= ids[0] - 1
+			}
+			if groupErr.Error() != tt.wantError {
+				t.Errorf("Wait() error = %q, want %q", groupErr.Error(), tt.wantError)
+			}
+			for _, want := range tt.wantErrs {
+				if want != nil && !errors.Is(err, want) {
+					t.Errorf("Expected the group error to match %v", want)
+				}
+			}
+			var eventErr *EventError
+			if !errors.As(err, &eventErr) || eventErr != groupErr.Errors[0] {
+				t.Errorf("Expected errors.As to find the first failed event, got %+v", eventErr)
+			}
+		})
+	}
+}
+
+// TestEventGroupWithContext tests the group context is cancelled by the first failure
+func TestEventGroupWithContext(t *testing.T) {
+	errRejected := errors.New("rejected")
+	eq := NewTestQueue()
+	eq.RegisterHandler("reject", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		return errRejected
+	}))
+	var sawCancelled bool
+	eq.RegisterHandler("hss", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		sawCancelled = ctx.Err() != nil
+		return ctx.Err()
+	}))
+
+	group, ctx := NewEventGroupWithContext(context.Background(), eq)
+	group.Enqueue(NewEvent("reject", ctx))
+	waitFor(t, "the group context to be cancelled", func() bool { return ctx.Err() != nil })
+	if !errors.Is(context.Cause(ctx), errRejected) {
+		t.Errorf("Expected the group context cancelled with the failure, got %v", context.Cause(ctx))
+	}
+	group.Enqueue(NewEvent("hss", ctx))
+	if !sawCancelled {
+		t.Error("Expected later events to see the cancelled context")
+	}
+
+	if _, err := group.Wait(); !errors.Is(err, errRejected) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want both failures", err)
+	}
+
+	// A group that succeeds still releases its context
+	group, ctx = NewEventGroupWithContext(context.Background(), eq)
+	if _, err := group.Wait(); err != nil || ctx.Err() == nil {
+		t.Errorf("Wait() = %v with context error %v, want the context released", err, ctx.Err())
+	}
+}
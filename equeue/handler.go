@@ -1,6 +1,9 @@
 package equeue
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // IEventHandler defines the interface for processing events
 type IEventHandler interface {
@@ -15,3 +18,53 @@ type EventHandlerFunc func(ctx context.Context, event IEvent) error
 func (f EventHandlerFunc) Handle(ctx context.Context, event IEvent) error {
 	return f(ctx, event)
 }
+
+// HandlerOption configures how the queue runs a registered handler
+type HandlerOption func(*handlerConfig)
+
+// handlerConfig holds the options declared at registration
+type handlerConfig struct {
+	concurrency int
+	retry       *RetryPolicy
+	timeout     time.Duration
+	class       string
+}
+
+// WithConcurrency lets up to n events of the type be handled at once, off the
+// processing loop; events of the type may then complete out of order (default: 1, inline)
+// In Parallel mode events always run off the loop, bounded by the worker pool and n
+func WithConcurrency(n int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithRetry retries failed handling according to policy
+func WithRetry(policy RetryPolicy) HandlerOption {
+	return func(c *handlerConfig) {
+		c.retry = &policy
+	}
+}
+
+// WithHandlerTimeout bounds each handling attempt with a context timeout
+func WithHandlerTimeout(d time.Duration) HandlerOption {
+	return func(c *handlerConfig) {
+		c.timeout = d
+	}
+}
+
+// WithQueueClass assigns the handler to a class (e.g., "critical"); handlers in a
+// class share the limit set in EventQueueConfig.ClassConcurrency, and the class is
+// reported in audit records
+func WithQueueClass(class string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.class = class
+	}
+}
+
+// registeredHandler is a handler wrapped with its options
+type registeredHandler struct {
+	handler IEventHandler
+	class   string
+	slots   chan struct{} // nil when handled inline
+}
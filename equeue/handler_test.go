@@ -0,0 +1,201 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRegisterHandler_Concurrency tests how many events handlers run at once
+func TestRegisterHandler_Concurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   EventQueueConfig
+		handlers map[string][]HandlerOption
+		want     int32
+	}{
+		{
+			name:     "inline",
+			handlers: map[string][]HandlerOption{"cdr": nil},
+			want:     1,
+		},
+		{
+			name:     "concurrency",
+			handlers: map[string][]HandlerOption{"cdr": {WithConcurrency(3)}},
+			want:     3,
+		},
+		{
+			// Without fair queuing the loop would wait for a cdr slot ahead of the ulrs
+			name:     "per type",
+			config:   EventQueueConfig{TypeWeights: map[string]int{"cdr": 1, "ulr": 1}},
+			handlers: map[string][]HandlerOption{"cdr": {WithConcurrency(2)}, "ulr": {WithConcurrency(3)}},
+			want:     5,
+		},
+		{
+			name:   "class limit",
+			config: EventQueueConfig{ClassConcurrency: map[string]int{"bulk": 3}},
+			handlers: map[string][]HandlerOption{
+				"cdr": {WithConcurrency(4), WithQueueClass("bulk")},
+				"ulr": {WithConcurrency(4), WithQueueClass("bulk")},
+			},
+			want: 3,
+		},
+		{
+			name:   "class only",
+			config: EventQueueConfig{ClassConcurrency: map[string]int{"bulk": 3}},
+			handlers: map[string][]HandlerOption{
+				"cdr": {WithQueueClass("bulk")},
+				"ulr": {WithQueueClass("bulk")},
+			},
+			want: 2, // One at a time per type
+		},
+		{
+			name:     "parallel",
+			config:   EventQueueConfig{ProcessingMode: Parallel, Workers: 4},
+			handlers: map[string][]HandlerOption{"cdr": {WithConcurrency(8)}, "ulr": nil},
+			want:     4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			var running, peak atomic.Int32
+			eq := NewEventQueue(tt.config)
+			for eventType, options := range tt.handlers {
+				eq.RegisterHandler(eventType, EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+					n := running.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					<-release
+					running.Add(-1)
+					return nil
+				}), options...)
+			}
+			eq.Start(context.Background())
+
+			var events []*Event
+			for i := 0; i < 8; i++ {
+				for eventType := range tt.handlers {
+					event := NewEvent(eventType, context.Background())
+					events = append(events, event)
+					eq.Enqueue(event)
+				}
+			}
+
+			waitFor(t, "handlers to start", func() bool { return running.Load() == tt.want })
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+			for _, event := range events {
+				event.Wait()
+			}
+			if got := peak.Load(); got != tt.want {
+				t.Errorf("Handled %d events at once, want %d", got, tt.want)
+			}
+			if err := eq.Stop(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestRegisterHandler_RetryTimeout tests retries and per-attempt timeouts
+func TestRegisterHandler_RetryTimeout(t *testing.T) {
+	errRejected := errors.New("rejected")
+	errPermanent := errors.New("unknown subscriber")
+
+	tests := []struct {
+		name         string
+		options      []HandlerOption
+		attemptErr   func(ctx context.Context, attempt int32) error
+		wantAttempts int32
+		wantErr      error
+	}{
+		{
+			name:         "no retry",
+			attemptErr:   func(ctx context.Context, attempt int32) error { return errRejected },
+			wantAttempts: 1,
+			wantErr:      errRejected,
+		},
+		{
+			name:    "retried until success",
+			options: []HandlerOption{WithRetry(RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond})},
+			attemptErr: func(ctx context.Context, attempt int32) error {
+				if attempt < 3 {
+					return errRejected
+				}
+				return nil
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:         "attempts exhausted",
+			options:      []HandlerOption{WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})},
+			attemptErr:   func(ctx context.Context, attempt int32) error { return errRejected },
+			wantAttempts: 2,
+			wantErr:      errRejected,
+		},
+		{
+			name: "not retryable",
+			options: []HandlerOption{WithRetry(RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				Retryable:      func(err error) bool { return !errors.Is(err, errPermanent) },
+			})},
+			attemptErr:   func(ctx context.Context, attempt int32) error { return errPermanent },
+			wantAttempts: 1,
+			wantErr:      errPermanent,
+		},
+		{
+			name:    "timeout per attempt",
+			options: []HandlerOption{WithHandlerTimeout(5 * time.Millisecond), WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})},
+			attemptErr: func(ctx context.Context, attempt int32) error {
+				if attempt < 3 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				// A fresh timeout for the last attempt
+				if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < time.Millisecond {
+					return errors.New("expected a fresh attempt timeout")
+				}
+				return nil
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:    "timed out",
+			options: []HandlerOption{WithHandlerTimeout(5 * time.Millisecond)},
+			attemptErr: func(ctx context.Context, attempt int32) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantAttempts: 1,
+			wantErr:      ErrHandlerTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			eq := NewTestQueue()
+			eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				return tt.attemptErr(ctx, attempts.Add(1))
+			}), tt.options...)
+
+			event := NewEvent("ulr", context.Background())
+			eq.Enqueue(event)
+			if _, err := event.Wait(); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Result = %v, want %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("Handled %d times, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package equeue
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrQueueHandover completes events written to the handover file by Handover; the
+// process restored from the file handles them
+var ErrQueueHandover = errors.New("event handed over to the next process")
+
+// OutcomeHandedOver is the audit outcome of events written to the handover file
+const OutcomeHandedOver = "handed_over"
+
+// handoverVersion is the handover file format version
+const handoverVersion = 1
+
+// HandoverConfig enables graceful handover of queued events across planned restarts
+type HandoverConfig struct {
+	// Path of the handover file, written by Handover and read by RestoreHandover
+	Path string
+
+	// Codec serializes event payloads (default: plain *Event only, no payload)
+	Codec EventCodec
+}
+
+// EventCodec converts events to and from handover records
+type EventCodec interface {
+	// Encode returns the payload of event, stored in HandoverRecord.Payload
+	Encode(event IEvent) ([]byte, error)
+
+	// Decode recreates an event from its record, typically with
+	// NewEvent(record.Type, ctx, record.Options()...) plus the decoded payload
+	Decode(record HandoverRecord) (IEvent, error)
+}
+
+// HandoverRecord is an event saved in the handover file
+type HandoverRecord struct {
+	ID             uint64    `json:"id"` // ID in the previous process, restored events get a new one
+	Type           string    `json:"type"`
+	Timestamp      time.Time `json:"timestamp"`
+	Deadline       time.Time `json:"deadline"`
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	Metadata       Metadata  `json:"metadata,omitempty"`
+	InFlight       bool      `json:"in_flight,omitempty"` // Was being handled when handed over, so may have been partly applied
+	Payload        []byte    `json:"payload,omitempty"`
+}
+
+// Options returns the options restoring the record's timestamp, deadline,
+// idempotency key and metadata
+func (r HandoverRecord) Options() []EventOption {
+	var options []EventOption
+	if !r.Timestamp.IsZero() {
+		options = append(options, WithTimestamp(r.Timestamp))
+	}
+	if !r.Deadline.IsZero() {
+		options = append(options, WithDeadline(r.Deadline))
+	}
+	if r.IdempotencyKey != "" {
+		options = append(options, WithIdempotencyKey(r.IdempotencyKey))
+	}
+	for key, value := range r.Metadata {
+		options = append(options, WithMetadata(key, value))
+	}
+	return options
+}
+
+// handoverFile is the handover file contents
+type handoverFile struct {
+	Version   int              `json:"version"`
+	WrittenAt time.Time        `json:"written_at"`
+	Events    []HandoverRecord `json:"events"` // In-flight events first, then queued ones in order
+}
+
+// HandoverReport summarizes the events written by Handover
+type HandoverReport struct {
+	Queued   int           // Queued events written to the file
+	InFlight int           // Events still being handled at the deadline, written to the file
+	Failed   int           // Events not handed over because encoding or writing the file failed
+	Duration time.Duration // Time taken by the handover
+}
+
+// eventCodec is the default codec, for plain *Event without payload
+type eventCodec struct{}
+
+func (eventCodec) Encode(event IEvent) ([]byte, error) {
+	if e, ok := event.(*Event); !ok || e.payload != nil {
+		return nil, fmt.Errorf("no handover codec for %T payloads", event)
+	}
+	return nil, nil
+}
+
+func (eventCodec) Decode(record HandoverRecord) (IEvent, error) {
+	return NewEvent(record.Type, context.Background(), record.Options()...), nil
+}
+
+// inflightEvents tracks the events being handled, for handover
+type inflightEvents struct {
+	mu      sync.Mutex
+	events  map[uint64]IEvent
+	claimed map[uint64]bool // Completed by Handover instead of their handler
+}
+
+func (f *inflightEvents) add(event IEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events[event.GetID()] = event
+}
+
+// remove stops tracking event once handled, returning false if Handover claimed
+// it, in which case the result of its handling is discarded
+func (f *inflightEvents) remove(event IEvent) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.events, event.GetID())
+	if f.claimed[event.GetID()] {
+		delete(f.claimed, event.GetID())
+		return false
+	}
+	return true
+}
+
+// claim returns the events being handled, oldest first, leaving their completion
+// to the caller
+func (f *inflightEvents) claim() []IEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	events := make([]IEvent, 0, len(f.events))
+	for id, event := range f.events {
+		events = append(events, event)
+		f.claimed[id] = true
+	}
+	slices.SortFunc(events, func(a, b IEvent) int {
+		if c := a.GetTimestamp().Compare(b.GetTimestamp()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.GetID(), b.GetID())
+	})
+	return events
+}
+
+// Handover stops the queue for a planned restart without draining it: handlers
+// already running get until ctx is done to finish, then queued events and events
+// still being handled are written to the handover file for RestoreHandover in the
+// next process, and completed with ErrQueueHandover
+// If the file can't be written, the events are completed with ErrQueueShutdown
+// instead, as in a drain. Events handed over while in flight may be handled twice;
+// give them an idempotency key if that matters
+func (eq *EventQueue) Handover(ctx context.Context) (HandoverReport, error) {
+	if eq.handover == nil {
+		return HandoverReport{}, fmt.Errorf("handover is not configured")
+	}
+	if !eq.running.CompareAndSwap(true, false) {
+		return HandoverReport{}, fmt.Errorf("queue is already stopped")
+	}
+
+	start := time.Now()
+	eq.handingOver.Store(true)
+	if eq.cancel != nil {
+		eq.cancel(ErrShuttingDown)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		eq.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+	}
+
+	report := HandoverReport{}
+	file := handoverFile{Version: handoverVersion, WrittenAt: time.Now()}
+	var inFlight, queued []IEvent
+	save := func(event IEvent, running bool) bool {
+		record, err := eq.handoverRecord(event, running)
+		if err != nil {
+			report.Failed++
+			return false
+		}
+		file.Events = append(file.Events, record)
+		return true
+	}
+
+	// Handlers keep running until the file is written, but the in-flight events
+	// are completed here, whatever their handling returns
+	for _, event := range eq.inflight.claim() {
+		if save(event, true) {
+			inFlight = append(inFlight, event)
+		} else {
+			eq.abandon(event)
+		}
+	}
+	for {
+		event, ok := eq.events.poll()
+		if !ok {
+			break
+		}
+		eq.onRemove(event)
+		if save(event, false) {
+			queued = append(queued, event)
+		} else {
+			eq.abandon(event)
+		}
+	}
+	eq.observeDepth()
+
+	err := writeHandoverFile(eq.handover.Path, file)
+
+	for _, event := range inFlight {
+		if err != nil {
+			eq.abandon(event)
+		} else {
+			eq.handedOver(event)
+		}
+	}
+	if eq.abortCancel != nil {
+		eq.abortCancel(ErrShuttingDown)
+	}
+	for _, event := range queued {
+		if err != nil {
+			eq.abandon(event)
+		} else {
+			eq.handedOver(event)
+		}
+	}
+
+	report.Duration = time.Since(start)
+	if err != nil {
+		report.Failed += len(inFlight) + len(queued)
+		return report, err
+	}
+	report.InFlight, report.Queued = len(inFlight), len(queued)
+	return report, nil
+}
+
+// handedOver completes an event written to the handover file with ErrQueueHandover
+func (eq *EventQueue) handedOver(event IEvent) {
+	eq.complete(event, nil, ErrQueueHandover)
+	eq.audit(event, OutcomeHandedOver, time.Since(event.GetTimestamp()), 0, ErrQueueHandover)
+}
+
+// handoverRecord encodes event for the handover file
+func (eq *EventQueue) handoverRecord(event IEvent, inFlight bool) (HandoverRecord, error) {
+	payload, err := eq.handover.Codec.Encode(event)
+	if err != nil {
+		return HandoverRecord{}, fmt.Errorf("failed to encode event %d: %w", event.GetID(), err)
+	}
+
+	record := HandoverRecord{
+		ID:        event.GetID(),
+		Type:      event.GetType(),
+		Timestamp: event.GetTimestamp(),
+		Deadline:  event.GetDeadline(),
+		Metadata:  MetadataFromContext(event.GetContext()),
+		InFlight:  inFlight,
+		Payload:   payload,
+	}
+	if idempotent, ok := event.(IdempotentEvent); ok {
+		record.IdempotencyKey = idempotent.GetIdempotencyKey()
+	}
+	return record, nil
+}
+
+// RestoreHandover enqueues the events saved by the previous process's Handover and
+// removes the handover file; call it after Start and before accepting new traffic
+// Restoring waits for room while the queue is full. Records that can't be decoded
+// or enqueued are kept in the file and reported in the error
+// Returns the number of events restored (0 without a handover file)
+func (eq *EventQueue) RestoreHandover(ctx context.Context) (int, error) {
+	if eq.handover == nil {
+		return 0, fmt.Errorf("handover is not configured")
+	}
+
+	data, err := os.ReadFile(eq.handover.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read handover file: %w", err)
+	}
+	var file handoverFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return 0, fmt.Errorf("failed to parse handover file %s: %w", eq.handover.Path, err)
+	}
+	if file.Version != handoverVersion {
+		return 0, fmt.Errorf("unsupported handover file version %d", file.Version)
+	}
+
+	restored := 0
+	var remaining []HandoverRecord
+	var errs []error
+	for _, record := range file.Events {
+		if err := eq.restoreRecord(ctx, record); err != nil {
+			remaining = append(remaining, record)
+			errs = append(errs, fmt.Errorf("event %d (%s): %w", record.ID, record.Type, err))
+			continue
+		}
+		restored++
+	}
+
+	if len(remaining) > 0 {
+		file.Events = remaining
+		if err := writeHandoverFile(eq.handover.Path, file); err != nil {
+			errs = append(errs, err)
+		}
+		return restored, fmt.Errorf("failed to restore %d handed over events: %w", len(remaining), errors.Join(errs...))
+	}
+	if err := os.Remove(eq.handover.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return restored, fmt.Errorf("failed to remove handover file: %w", err)
+	}
+	return restored, nil
+}
+
+// restoreRecord decodes record and enqueues it, waiting while the queue is full
+func (eq *EventQueue) restoreRecord(ctx context.Context, record HandoverRecord) error {
+	event, err := eq.handover.Codec.Decode(record)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := eq.Enqueue(event)
+		if !errors.Is(err, ErrQueueFull) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// writeHandoverFile writes file atomically through a temporary file
+func writeHandoverFile(path string, file handoverFile) error {
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode handover file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create handover file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write handover file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync handover file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write handover file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write handover file: %w", err)
+	}
+	return nil
+}
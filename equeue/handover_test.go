@@ -0,0 +1,239 @@
+package equeue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestEventQueue_Handover tests queued and in-flight events are written to the
+// handover file, or abandoned if they can't be
+func TestEventQueue_Handover(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       func(dir string) string
+		payload    bool // Give the last queued event a payload the default codec rejects
+		want       HandoverReport
+		wantResult error // Result of the handed over events
+		wantError  bool
+	}{
+		{
+			name:       "written",
+			path:       func(dir string) string { return filepath.Join(dir, "handover.json") },
+			want:       HandoverReport{Queued: 2, InFlight: 1},
+			wantResult: ErrQueueHandover,
+		},
+		{
+			name:       "encoding fails",
+			path:       func(dir string) string { return filepath.Join(dir, "handover.json") },
+			payload:    true,
+			want:       HandoverReport{Queued: 1, InFlight: 1, Failed: 1},
+			wantResult: ErrQueueHandover,
+		},
+		{
+			name:       "write fails",
+			path:       func(dir string) string { return filepath.Join(dir, "missing", "handover.json") },
+			want:       HandoverReport{Failed: 3},
+			wantResult: ErrQueueShutdown,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path(t.TempDir())
+			eq := NewEventQueue(EventQueueConfig{Handover: &HandoverConfig{Path: path}})
+			started := make(chan struct{}, 1)
+			eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				started <- struct{}{}
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+			eq.Start(context.Background())
+
+			events := []*Event{
+				NewEvent("cdr", context.Background()),
+				NewEvent("cdr", context.Background(), WithCorrelationID("c-2")),
+				NewEvent("cdr", context.Background()),
+			}
+			if tt.payload {
+				events[2] = NewEvent("cdr", context.Background(), WithPayload(map[string]string{"imsi": "001010000000001"}))
+			}
+			for _, event := range events {
+				if err := eq.Enqueue(event); err != nil {
+					t.Fatalf("Enqueue() error = %v", err)
+				}
+			}
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			report, err := eq.Handover(ctx)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Handover() error = %v, want error %v", err, tt.wantError)
+			}
+			report.Duration = 0
+			if report != tt.want {
+				t.Errorf("Handover() = %+v, want %+v", report, tt.want)
+			}
+
+			// The in-flight event's aborted handling must not override the handover result
+			for i, event := range events {
+				want := tt.wantResult
+				if tt.payload && i == 2 {
+					want = ErrQueueShutdown
+				}
+				if _, err := event.Wait(); !errors.Is(err, want) {
+					t.Errorf("Event %d result = %v, want %v", i, err, want)
+				}
+			}
+
+			if _, err := os.Stat(path); tt.wantError != errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Handover file stat error = %v", err)
+			}
+		})
+	}
+}
+
+// TestEventQueue_RestoreHandover tests the next process handles the handed over events
+func TestEventQueue_RestoreHandover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handover.json")
+	config := EventQueueConfig{Handover: &HandoverConfig{Path: path}}
+
+	// Nothing to restore yet
+	next := NewEventQueue(config)
+	if n, err := next.RestoreHandover(context.Background()); n != 0 || err != nil {
+		t.Fatalf("RestoreHandover() without a file = %d, %v", n, err)
+	}
+
+	prev := NewEventQueue(config)
+	started := make(chan struct{}, 1)
+	prev.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	prev.Start(context.Background())
+	deadline := time.Now().Add(time.Hour).Truncate(time.Second)
+	first := NewEvent("ulr", context.Background(), WithIdempotencyKey("hss1;1"))
+	second := NewEvent("ulr", context.Background(), WithDeadline(deadline), WithTenant("mvno-a"))
+	prev.Enqueue(first)
+	prev.Enqueue(second)
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := prev.Handover(ctx); err != nil {
+		t.Fatalf("Handover() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var handled []IEvent
+	next.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, event)
+		return nil
+	}))
+	next.Start(context.Background())
+	n, err := next.RestoreHandover(context.Background())
+	if n != 2 || err != nil {
+		t.Fatalf("RestoreHandover() = %d, %v", n, err)
+	}
+	if err := next.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 2 {
+		t.Fatalf("Expected 2 restored events handled, got %d", len(handled))
+	}
+	// The in-flight event is restored first
+	if key := idempotencyKey(handled[0]); key != "hss1;1" {
+		t.Errorf("Expected the in-flight event first with its key, got %q", key)
+	}
+	if !handled[1].GetDeadline().Equal(deadline) || GetMetadata(handled[1], MetaTenant) != "mvno-a" {
+		t.Errorf("Expected the deadline and metadata restored, got %v and %q", handled[1].GetDeadline(), GetMetadata(handled[1], MetaTenant))
+	}
+	for i, original := range []*Event{first, second} {
+		if !handled[i].GetTimestamp().Equal(original.GetTimestamp()) {
+			t.Errorf("Event %d timestamp = %v, want the original %v", i, handled[i].GetTimestamp(), original.GetTimestamp())
+		}
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the handover file removed, got %v", err)
+	}
+}
+
+// TestEventQueue_HandoverObservability tests handed over events are counted in the
+// metrics and audited
+func TestEventQueue_HandoverObservability(t *testing.T) {
+	var audit bytes.Buffer
+	metrics := NewQueueMetrics("q")
+	eq := NewEventQueue(EventQueueConfig{
+		Metrics:     metrics,
+		AuditWriter: NewJSONAuditWriter(&audit),
+		Handover:    &HandoverConfig{Path: filepath.Join(t.TempDir(), "handover.json")},
+	})
+	started := make(chan struct{}, 1)
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithQueueClass("bulk"))
+	eq.Start(context.Background())
+
+	events := make([]*Event, 3)
+	for i := range events {
+		events[i] = NewEvent("cdr", context.Background())
+		eq.Enqueue(events[i])
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := eq.Handover(ctx); err != nil {
+		t.Fatalf("Handover() error = %v", err)
+	}
+
+	stats := metrics.Snapshot()[0]
+	if stats.Depth != 0 || stats.Outcomes[OutcomeHandedOver] != 3 || stats.Outcomes[OutcomeFailed] != 0 {
+		t.Errorf("Expected depth 0 and 3 handed over, got depth %d and %v", stats.Depth, stats.Outcomes)
+	}
+
+	var records []AuditRecord
+	dec := json.NewDecoder(&audit)
+	for dec.More() {
+		var record AuditRecord
+		if err := dec.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != len(events) {
+		t.Fatalf("Expected %d audit records, got %d", len(events), len(records))
+	}
+	for i, record := range records {
+		want := AuditRecord{
+			EventID:   events[i].GetID(),
+			Type:      "cdr",
+			Class:     "bulk",
+			Outcome:   OutcomeHandedOver,
+			Error:     ErrQueueHandover.Error(),
+			CreatedAt: record.CreatedAt,
+			QueueTime: record.QueueTime,
+		}
+		if record != want {
+			t.Errorf("Audit record %d = %+v, want %+v", i, record, want)
+		}
+		if !record.CreatedAt.Equal(events[i].GetTimestamp()) {
+			t.Errorf("Audit record %d created at %v, want %v", i, record.CreatedAt, events[i].GetTimestamp())
+		}
+	}
+}
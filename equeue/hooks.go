@@ -0,0 +1,141 @@
+package equeue
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Audit record outcomes
+const (
+	OutcomeProcessed = "processed"
+	OutcomeFailed    = "failed"
+	OutcomeExpired   = "expired"
+	OutcomeNoHandler = "no_handler"
+	OutcomeAbandoned = "abandoned"
+	OutcomePurged    = "purged"
+)
+
+// EventHooks are lifecycle callbacks invoked by the queue
+// Hooks run on the enqueuing or processing goroutine and must not block
+type EventHooks struct {
+	// OnEnqueue is called after an event is accepted into the queue
+	OnEnqueue func(event IEvent)
+
+	// OnDequeue is called when the processor takes an event, with the time since its creation
+	OnDequeue func(event IEvent, queueTime time.Duration)
+
+	// OnComplete is called after an event is completed, whatever the outcome
+	OnComplete func(event IEvent, record AuditRecord)
+
+	// OnExpire is called when an event is dropped because its deadline passed
+	OnExpire func(event IEvent, queueTime time.Duration)
+
+	// OnLoss is called for every event rejected or dropped without being handled,
+	// with the reason (LossQueueFull, LossStopped, LossExpired, LossNoHandler, LossAbandoned, LossPurged, LossInvalid)
+	OnLoss func(event IEvent, reason string)
+}
+
+// AuditRecord captures where an event spent its time
+type AuditRecord struct {
+	EventID        uint64        `json:"event_id"`
+	Type           string        `json:"type"`
+	Class          string        `json:"class,omitempty"` // Queue class of the handler (WithQueueClass)
+	CreatedAt      time.Time     `json:"created_at"`
+	QueueTime      time.Duration `json:"queue_time_ns"`      // From creation until dequeue
+	ProcessingTime time.Duration `json:"processing_time_ns"` // Time spent in the handler
+	Outcome        string        `json:"outcome"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// AuditWriter receives an audit record for every completed event
+type AuditWriter interface {
+	WriteAudit(record AuditRecord)
+}
+
+// JSONAuditWriter writes audit records as JSON lines
+type JSONAuditWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAuditWriter creates an audit writer emitting one JSON object per line
+func NewJSONAuditWriter(w io.Writer) *JSONAuditWriter {
+	return &JSONAuditWriter{enc: json.NewEncoder(w)}
+}
+
+// WriteAudit writes a record; write errors are ignored so auditing never blocks processing
+func (w *JSONAuditWriter) WriteAudit(record AuditRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.enc.Encode(record)
+}
+
+// onEnqueue runs the enqueue hook
+func (eq *EventQueue) onEnqueue(event IEvent) {
+	if eq.metrics != nil {
+		eq.metrics.observeEnqueue(event.GetType())
+	}
+	if eq.hooks.OnEnqueue != nil {
+		eq.hooks.OnEnqueue(event)
+	}
+}
+
+// onDequeue runs the dequeue hook and returns the event's queue time
+func (eq *EventQueue) onDequeue(event IEvent) time.Duration {
+	queueTime := time.Since(event.GetTimestamp())
+	if eq.metrics != nil {
+		eq.metrics.observeDequeue(event.GetType())
+	}
+	if eq.hooks.OnDequeue != nil {
+		eq.hooks.OnDequeue(event, queueTime)
+	}
+	return queueTime
+}
+
+// onRemove counts a queued event taken off the queue without being handled
+func (eq *EventQueue) onRemove(event IEvent) {
+	if eq.metrics != nil {
+		eq.metrics.observeDequeue(event.GetType())
+	}
+}
+
+// onExpire runs the expire hook
+func (eq *EventQueue) onExpire(event IEvent, queueTime time.Duration) {
+	if eq.hooks.OnExpire != nil {
+		eq.hooks.OnExpire(event, queueTime)
+	}
+}
+
+// audit reports a completed event to the metrics, complete hook and audit writer
+func (eq *EventQueue) audit(event IEvent, outcome string, queueTime, processingTime time.Duration, err error) {
+	if eq.metrics != nil {
+		eq.metrics.observeComplete(event.GetType(), outcome, processingTime)
+	}
+	if eq.hooks.OnComplete == nil && eq.auditWriter == nil {
+		return
+	}
+
+	record := AuditRecord{
+		EventID:        event.GetID(),
+		Type:           event.GetType(),
+		CreatedAt:      event.GetTimestamp(),
+		QueueTime:      queueTime,
+		ProcessingTime: processingTime,
+		Outcome:        outcome,
+	}
+	if entry := eq.handlers[event.GetType()]; entry != nil {
+		record.Class = entry.class
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if eq.hooks.OnComplete != nil {
+		eq.hooks.OnComplete(event, record)
+	}
+	if eq.auditWriter != nil {
+		eq.auditWriter.WriteAudit(record)
+	}
+}
@@ -0,0 +1,108 @@
+package equeue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestEventQueue_Hooks tests lifecycle hooks and audit records for each outcome
+func TestEventQueue_Hooks(t *testing.T) {
+	errInvalidIMEI := errors.New("invalid IMEI")
+
+	tests := []struct {
+		name        string
+		eventType   string
+		options     []EventOption
+		handlerErr  error
+		wantHooks   []string
+		wantOutcome string
+		wantError   string
+	}{
+		{
+			name:        "processed",
+			eventType:   "check",
+			wantHooks:   []string{"enqueue", "dequeue", "complete"},
+			wantOutcome: OutcomeProcessed,
+		},
+		{
+			name:        "failed",
+			eventType:   "check",
+			handlerErr:  errInvalidIMEI,
+			wantHooks:   []string{"enqueue", "dequeue", "complete"},
+			wantOutcome: OutcomeFailed,
+			wantError:   "invalid IMEI",
+		},
+		{
+			name:        "expired",
+			eventType:   "check",
+			options:     []EventOption{WithDeadline(time.Now().Add(-time.Second))},
+			wantHooks:   []string{"enqueue", "dequeue", "expire", "loss:expired", "complete"},
+			wantOutcome: OutcomeExpired,
+			wantError:   ErrEventExpired.Error(),
+		},
+		{
+			name:        "no handler",
+			eventType:   "provision",
+			wantHooks:   []string{"enqueue", "dequeue", "loss:no_handler", "complete"},
+			wantOutcome: OutcomeNoHandler,
+			wantError:   "no handler registered for event type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hooks []string
+			var records []AuditRecord
+			var buf bytes.Buffer
+			eq := NewEventQueue(EventQueueConfig{
+				ProcessingMode: Inline,
+				AuditWriter:    NewJSONAuditWriter(&buf),
+				Hooks: EventHooks{
+					OnEnqueue: func(event IEvent) { hooks = append(hooks, "enqueue") },
+					OnDequeue: func(event IEvent, queueTime time.Duration) { hooks = append(hooks, "dequeue") },
+					OnExpire:  func(event IEvent, queueTime time.Duration) { hooks = append(hooks, "expire") },
+					OnLoss:    func(event IEvent, reason string) { hooks = append(hooks, "loss:"+reason) },
+					OnComplete: func(event IEvent, record AuditRecord) {
+						hooks = append(hooks, "complete")
+						records = append(records, record)
+					},
+				},
+			})
+			eq.RegisterHandler("check", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				return tt.handlerErr
+			}), WithQueueClass("critical"))
+
+			event := NewEvent(tt.eventType, context.Background(), tt.options...)
+			if err := eq.Enqueue(event); err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(hooks, tt.wantHooks) {
+				t.Errorf("Hooks = %v, want %v", hooks, tt.wantHooks)
+			}
+			if len(records) != 1 {
+				t.Fatalf("Expected 1 audit record, got %d", len(records))
+			}
+			record := records[0]
+			if record.EventID != event.GetID() || record.Type != tt.eventType || record.Outcome != tt.wantOutcome || record.Error != tt.wantError {
+				t.Errorf("Audit record = %+v, want outcome %q and error %q", record, tt.wantOutcome, tt.wantError)
+			}
+			if tt.eventType == "check" && record.Class != "critical" {
+				t.Errorf("Expected the handler class in the record, got %q", record.Class)
+			}
+
+			var written AuditRecord
+			if err := json.Unmarshal(buf.Bytes(), &written); err != nil {
+				t.Fatalf("Audit writer output %q: %v", buf.String(), err)
+			}
+			if written.EventID != record.EventID || written.Outcome != record.Outcome || written.Error != record.Error {
+				t.Errorf("Written record = %+v, want %+v", written, record)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestNewTestQueue tests Inline mode handles events before Enqueue returns
+func TestNewTestQueue(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name       string
+		options    []HandlerOption
+		handle     func(ctx context.Context, attempt int) error
+		wantResult interface{}
+		wantError  error
+		wantCalls  int
+	}{
+		{
+			name:       "processed",
+			handle:     func(ctx context.Context, attempt int) error { return nil },
+			wantResult: "processed",
+			wantCalls:  1,
+		},
+		{
+			name:      "failed",
+			handle:    func(ctx context.Context, attempt int) error { return errRejected },
+			wantError: errRejected,
+			wantCalls: 1,
+		},
+		{
+			name:    "retried",
+			options: []HandlerOption{WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})},
+			handle: func(ctx context.Context, attempt int) error {
+				if attempt < 3 {
+					return errRejected
+				}
+				return nil
+			},
+			wantResult: "processed",
+			wantCalls:  3,
+		},
+		{
+			name:    "timed out",
+			options: []HandlerOption{WithHandlerTimeout(5 * time.Millisecond)},
+			handle: func(ctx context.Context, attempt int) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantError: context.DeadlineExceeded,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			eq := NewTestQueue()
+			eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				calls++
+				return tt.handle(ctx, calls)
+			}), tt.options...)
+
+			// No Start needed
+			event := NewEvent("cdr", context.Background())
+			if err := eq.Enqueue(event); err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Handler called %d times before Enqueue returned, want %d", calls, tt.wantCalls)
+			}
+
+			result, err := event.Wait()
+			if result != tt.wantResult {
+				t.Errorf("Result = %v, want %v", result, tt.wantResult)
+			}
+			if !errors.Is(err, tt.wantError) || (tt.wantError == nil && err != nil) {
+				t.Errorf("Error = %v, want %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestNewTestQueue_Stopped tests an Inline queue rejects events once stopped
+func TestNewTestQueue_Stopped(t *testing.T) {
+	eq := NewTestQueue()
+	handled := 0
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		handled++
+		return nil
+	}))
+
+	if err := eq.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := eq.Enqueue(NewEvent("cdr", context.Background())); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if err := eq.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := eq.Enqueue(NewEvent("cdr", context.Background())); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("Expected ErrQueueStopped after stopping, got %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("Expected 1 event handled, got %d", handled)
+	}
+}
+
+// TestNewTestQueue_Expired tests an expired event is not handled in Inline mode
+func TestNewTestQueue_Expired(t *testing.T) {
+	eq := NewTestQueue()
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		t.Error("Expected the expired event not to be handled")
+		return nil
+	}))
+
+	event := NewEvent("cdr", context.Background(), WithDeadline(time.Now().Add(-time.Second)))
+	if err := eq.Enqueue(event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if _, err := event.Wait(); !errors.Is(err, ErrEventExpired) {
+		t.Errorf("Expected ErrEventExpired, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package equeue
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Loss reasons, for events that were accepted or offered but never handled
+const (
+	LossQueueFull = "queue_full" // Rejected by Enqueue with ErrQueueFull
+	LossStopped   = "stopped"    // Rejected by Enqueue because the queue was stopped
+	LossExpired   = "expired"    // Deadline passed before a handler ran
+	LossNoHandler = "no_handler" // No handler registered for the event type
+	LossAbandoned = "abandoned"  // Still queued when a drain deadline passed
+	LossPurged    = "purged"     // Shed by Purge under overload
+	LossInvalid   = "invalid"    // Rejected by Enqueue because the payload failed its schema
+)
+
+// lossReasons indexes per-reason counters
+var lossReasons = []string{LossQueueFull, LossStopped, LossExpired, LossNoHandler, LossAbandoned, LossPurged, LossInvalid}
+
+// LossStats counts the lost events of one type
+type LossStats struct {
+	Type     string
+	ByReason map[string]uint64 // Lost events by reason (LossQueueFull etc.)
+	Total    uint64
+}
+
+// lossCounter counts lost events per type and reason
+type lossCounter struct {
+	mu    sync.RWMutex
+	types map[string][]atomic.Uint64 // Indexed like lossReasons
+	total atomic.Uint64
+}
+
+// add counts one lost event
+func (c *lossCounter) add(eventType, reason string) {
+	counts := c.forType(eventType)
+	for i, r := range lossReasons {
+		if r == reason {
+			counts[i].Add(1)
+			break
+		}
+	}
+	c.total.Add(1)
+}
+
+// forType returns the counters for an event type, creating them on first use
+func (c *lossCounter) forType(eventType string) []atomic.Uint64 {
+	c.mu.RLock()
+	counts, ok := c.types[eventType]
+	c.mu.RUnlock()
+	if ok {
+		return counts
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.types == nil {
+		c.types = make(map[string][]atomic.Uint64)
+	}
+	if counts, ok = c.types[eventType]; !ok {
+		counts = make([]atomic.Uint64, len(lossReasons))
+		c.types[eventType] = counts
+	}
+	return counts
+}
+
+// snapshot returns the counts per event type, sorted by type
+func (c *lossCounter) snapshot() []LossStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make([]LossStats, 0, len(c.types))
+	for eventType, counts := range c.types {
+		s := LossStats{Type: eventType, ByReason: make(map[string]uint64, len(lossReasons))}
+		for i, reason := range lossReasons {
+			n := counts[i].Load()
+			s.ByReason[reason] = n
+			s.Total += n
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Type < stats[j].Type })
+	return stats
+}
+
+// Losses returns the events lost per type since the queue was created
+// Duplicates coalesced by DedupWindow are not losses (see DuplicateCount)
+func (eq *EventQueue) Losses() []LossStats {
+	return eq.losses.snapshot()
+}
+
+// LostCount returns the total number of lost events
+func (eq *EventQueue) LostCount() uint64 {
+	return eq.losses.total.Load()
+}
+
+// onLoss counts a lost event and reports it to the metrics and loss hook
+func (eq *EventQueue) onLoss(event IEvent, reason string) {
+	eq.losses.add(event.GetType(), reason)
+	if eq.metrics != nil {
+		eq.metrics.observeLoss(event.GetType(), reason)
+	}
+	if eq.hooks.OnLoss != nil {
+		eq.hooks.OnLoss(event, reason)
+	}
+}
@@ -0,0 +1,141 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestEventQueue_Losses tests lost events are counted per type and reason and
+// reported to the loss hook
+func TestEventQueue_Losses(t *testing.T) {
+	tests := []struct {
+		name   string
+		config EventQueueConfig
+		// lose runs eq into losses; "cdr" events are handled at once and "ulr" events
+		// block until the test ends
+		lose       func(t *testing.T, eq *EventQueue)
+		wantLosses map[string]map[string]uint64 // Event type -> reason -> count
+	}{
+		{
+			name: "stopped",
+			lose: func(t *testing.T, eq *EventQueue) {
+				if err := eq.Enqueue(NewEvent("cdr", context.Background())); !errors.Is(err, ErrQueueStopped) {
+					t.Errorf("Enqueue() error = %v, want ErrQueueStopped", err)
+				}
+			},
+			wantLosses: map[string]map[string]uint64{"cdr": {LossStopped: 1}},
+		},
+		{
+			name:   "expired and no handler",
+			config: EventQueueConfig{ProcessingMode: Inline},
+			lose: func(t *testing.T, eq *EventQueue) {
+				eq.Enqueue(NewEvent("cdr", context.Background(), WithDeadline(time.Now().Add(-time.Second))))
+				eq.Enqueue(NewEvent("imei", context.Background()))
+				eq.Enqueue(NewEvent("imei", context.Background()))
+				eq.Enqueue(NewEvent("cdr", context.Background()))
+			},
+			wantLosses: map[string]map[string]uint64{"cdr": {LossExpired: 1}, "imei": {LossNoHandler: 2}},
+		},
+		{
+			name:   "invalid",
+			config: EventQueueConfig{ProcessingMode: Inline, Schemas: stringSchemas("cdr")},
+			lose: func(t *testing.T, eq *EventQueue) {
+				err := eq.Enqueue(NewEvent("cdr", context.Background(), WithPayload(42)))
+				if !errors.Is(err, ErrInvalidPayload) {
+					t.Errorf("Enqueue() error = %v, want ErrInvalidPayload", err)
+				}
+			},
+			wantLosses: map[string]map[string]uint64{"cdr": {LossInvalid: 1}},
+		},
+		{
+			name:   "queue full and purged",
+			config: EventQueueConfig{BufferSize: 2},
+			lose: func(t *testing.T, eq *EventQueue) {
+				eq.Start(context.Background())
+				eq.Enqueue(NewEvent("ulr", context.Background())) // Blocks the loop
+				waitFor(t, "the handler to start", func() bool { return eq.GetQueueSize() == 0 })
+				eq.Enqueue(NewEvent("cdr", context.Background()))
+				eq.Enqueue(NewEvent("cdr", context.Background()))
+				if err := eq.Enqueue(NewEvent("cdr", context.Background())); !errors.Is(err, ErrQueueFull) {
+					t.Errorf("Enqueue() error = %v, want ErrQueueFull", err)
+				}
+				if n := eq.Purge(nil); n != 2 {
+					t.Errorf("Purge() = %d, want 2", n)
+				}
+			},
+			wantLosses: map[string]map[string]uint64{"cdr": {LossQueueFull: 1, LossPurged: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			hooked := make(map[string]map[string]uint64)
+			tt.config.Hooks.OnLoss = func(event IEvent, reason string) {
+				mu.Lock()
+				defer mu.Unlock()
+				if hooked[event.GetType()] == nil {
+					hooked[event.GetType()] = make(map[string]uint64)
+				}
+				hooked[event.GetType()][reason]++
+			}
+
+			release := make(chan struct{})
+			eq := NewEventQueue(tt.config)
+			eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				return nil
+			}))
+			eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				<-release
+				return nil
+			}))
+			tt.lose(t, eq)
+			close(release)
+			eq.Stop()
+
+			var want uint64
+			got := make(map[string]map[string]uint64)
+			for _, stats := range eq.Losses() {
+				got[stats.Type] = make(map[string]uint64)
+				var total uint64
+				for reason, n := range stats.ByReason {
+					if n > 0 {
+						got[stats.Type][reason] = n
+					}
+					total += n
+				}
+				if stats.Total != total {
+					t.Errorf("%s Total = %d, want %d", stats.Type, stats.Total, total)
+				}
+			}
+			for _, reasons := range tt.wantLosses {
+				for _, n := range reasons {
+					want += n
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.wantLosses) {
+				t.Errorf("Losses() = %v, want %v", got, tt.wantLosses)
+			}
+			if eq.LostCount() != want {
+				t.Errorf("LostCount() = %d, want %d", eq.LostCount(), want)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(hooked, tt.wantLosses) {
+				t.Errorf("OnLoss reported %v, want %v", hooked, tt.wantLosses)
+			}
+		})
+	}
+}
+
+// stringSchemas returns a registry accepting only string payloads for eventType
+func stringSchemas(eventType string) *SchemaRegistry {
+	schemas := NewSchemaRegistry()
+	schemas.Register(eventType, GoTypeSchema[string]())
+	return schemas
+}
@@ -0,0 +1,93 @@
+package equeue
+
+import (
+	"context"
+	"sort"
+)
+
+// MetadataKey identifies a metadata value carried on an event's context
+type MetadataKey string
+
+// Standard metadata keys shared across components for correlation
+const (
+	// MetaCorrelationID ties together all work done for one request (e.g., Diameter Session-Id)
+	MetaCorrelationID MetadataKey = "correlation_id"
+	// MetaOriginInterface is the interface the request arrived on (e.g., "S6a", "N5g-eir")
+	MetaOriginInterface MetadataKey = "origin_interface"
+	// MetaTenant identifies the tenant or network slice the request belongs to
+	MetaTenant MetadataKey = "tenant"
+)
+
+// Metadata is the set of metadata values carried on a context
+type Metadata map[MetadataKey]string
+
+// metadataContextKey is the context key under which Metadata is stored
+type metadataContextKey struct{}
+
+// ContextWithMetadata returns a copy of ctx carrying key=value in addition to existing metadata
+func ContextWithMetadata(ctx context.Context, key MetadataKey, value string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	existing := MetadataFromContext(ctx)
+	metadata := make(Metadata, len(existing)+1)
+	for k, v := range existing {
+		metadata[k] = v
+	}
+	metadata[key] = value
+
+	return context.WithValue(ctx, metadataContextKey{}, metadata)
+}
+
+// MetadataFromContext returns the metadata carried on ctx (nil if none)
+// The returned map must not be modified
+func MetadataFromContext(ctx context.Context) Metadata {
+	if ctx == nil {
+		return nil
+	}
+	metadata, _ := ctx.Value(metadataContextKey{}).(Metadata)
+	return metadata
+}
+
+// GetMetadata returns a metadata value from the event's context
+func GetMetadata(event IEvent, key MetadataKey) string {
+	return MetadataFromContext(event.GetContext())[key]
+}
+
+// WithMetadata attaches a metadata value to the event's context
+func WithMetadata(key MetadataKey, value string) EventOption {
+	return func(e *Event) {
+		e.eventCtx.ctx = ContextWithMetadata(e.eventCtx.ctx, key, value)
+	}
+}
+
+// WithCorrelationID attaches the correlation ID to the event
+func WithCorrelationID(id string) EventOption {
+	return WithMetadata(MetaCorrelationID, id)
+}
+
+// WithOriginInterface attaches the origin interface to the event
+func WithOriginInterface(iface string) EventOption {
+	return WithMetadata(MetaOriginInterface, iface)
+}
+
+// WithTenant attaches the tenant to the event
+func WithTenant(tenant string) EventOption {
+	return WithMetadata(MetaTenant, tenant)
+}
+
+// KeysAndValues flattens metadata into sorted key/value pairs for structured loggers (Infow etc.)
+func (m Metadata) KeysAndValues() []interface{} {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	kv := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		kv = append(kv, k, m[MetadataKey(k)])
+	}
+	return kv
+}
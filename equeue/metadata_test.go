@@ -0,0 +1,73 @@
+package equeue
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestContextWithMetadata tests metadata accumulates without modifying the parent context
+func TestContextWithMetadata(t *testing.T) {
+	parent := ContextWithMetadata(context.Background(), MetaCorrelationID, "session-1")
+	child := ContextWithMetadata(parent, MetaTenant, "slice-a")
+	override := ContextWithMetadata(child, MetaCorrelationID, "session-2")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Metadata
+	}{
+		{name: "none", ctx: context.Background(), want: nil},
+		{name: "nil context", ctx: nil, want: nil},
+		{name: "parent", ctx: parent, want: Metadata{MetaCorrelationID: "session-1"}},
+		{name: "child", ctx: child, want: Metadata{MetaCorrelationID: "session-1", MetaTenant: "slice-a"}},
+		{name: "override", ctx: override, want: Metadata{MetaCorrelationID: "session-2", MetaTenant: "slice-a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetadataFromContext(tt.ctx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MetadataFromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMetadata_Propagation tests event metadata reaches the handler's context
+func TestMetadata_Propagation(t *testing.T) {
+	eq := NewTestQueue()
+	got := make(chan Metadata, 1)
+	eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		if GetMetadata(event, MetaOriginInterface) != "S6a" {
+			t.Errorf("GetMetadata(origin) = %q, want S6a", GetMetadata(event, MetaOriginInterface))
+		}
+		got <- MetadataFromContext(ctx)
+		return nil
+	}))
+
+	ctx := ContextWithMetadata(context.Background(), MetaTenant, "slice-a")
+	event := NewEvent("ulr", ctx, WithCorrelationID("session-1"), WithOriginInterface("S6a"))
+	if err := eq.Enqueue(event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	want := Metadata{MetaCorrelationID: "session-1", MetaOriginInterface: "S6a", MetaTenant: "slice-a"}
+	if metadata := <-got; !reflect.DeepEqual(metadata, want) {
+		t.Errorf("Handler metadata = %v, want %v", metadata, want)
+	}
+	if MetadataFromContext(ctx)[MetaCorrelationID] != "" {
+		t.Error("Expected the caller's context unchanged")
+	}
+}
+
+// TestMetadata_KeysAndValues tests metadata flattens into sorted pairs
+func TestMetadata_KeysAndValues(t *testing.T) {
+	metadata := Metadata{MetaTenant: "slice-a", MetaCorrelationID: "session-1"}
+	want := []interface{}{"correlation_id", "session-1", "tenant", "slice-a"}
+	if got := metadata.KeysAndValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("KeysAndValues() = %v, want %v", got, want)
+	}
+	if got := Metadata(nil).KeysAndValues(); len(got) != 0 {
+		t.Errorf("KeysAndValues() of nil = %v, want empty", got)
+	}
+}
@@ -0,0 +1,310 @@
+package equeue
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets are the processing latency histogram bounds in seconds
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// outcomes indexes per-outcome counters
+var outcomes = []string{OutcomeProcessed, OutcomeFailed, OutcomeExpired, OutcomeNoHandler, OutcomeAbandoned, OutcomePurged, OutcomeHandedOver}
+
+// QueueMetrics collects per-event-type statistics for one queue
+// Set it as EventQueueConfig.Metrics and expose it with WritePrometheus or
+// MetricsHandler; Snapshot feeds adapters such as a prometheus.Collector
+type QueueMetrics struct {
+	name    string
+	buckets []float64
+
+	mu    sync.RWMutex
+	types map[string]*typeMetrics
+
+	rejected atomic.Uint64
+}
+
+// typeMetrics holds the counters for one event type
+type typeMetrics struct {
+	depth    atomic.Int64
+	enqueued atomic.Uint64
+	outcomes []atomic.Uint64 // Indexed like outcomes
+	lost     []atomic.Uint64 // Indexed like lossReasons
+
+	bucketCounts []atomic.Uint64 // Non-cumulative; the last slot is +Inf
+	sumNanos     atomic.Int64
+	count        atomic.Uint64
+}
+
+// TypeStats is a point-in-time view of one event type's metrics
+type TypeStats struct {
+	Type       string
+	Depth      int64
+	Enqueued   uint64
+	Outcomes   map[string]uint64 // Completed events by outcome (OutcomeProcessed etc.)
+	Lost       map[string]uint64 // Lost events by reason (LossQueueFull etc.)
+	Processing HistogramSnapshot // Handler latency of processed and failed events
+}
+
+// HistogramSnapshot is a point-in-time view of a latency histogram
+type HistogramSnapshot struct {
+	Buckets []float64 // Upper bounds in seconds
+	Counts  []uint64  // Cumulative count per bucket
+	Sum     time.Duration
+	Count   uint64
+}
+
+// NewQueueMetrics creates metrics for the queue labelled name
+// buckets are latency upper bounds in seconds (default: DefaultLatencyBuckets)
+func NewQueueMetrics(name string, buckets ...float64) *QueueMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &QueueMetrics{
+		name:    name,
+		buckets: buckets,
+		types:   make(map[string]*typeMetrics),
+	}
+}
+
+// Name returns the queue label
+func (m *QueueMetrics) Name() string {
+	return m.name
+}
+
+// forType returns the metrics for an event type, creating them on first use
+func (m *QueueMetrics) forType(eventType string) *typeMetrics {
+	m.mu.RLock()
+	t, ok := m.types[eventType]
+	m.mu.RUnlock()
+	if ok {
+		return t
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok = m.types[eventType]; !ok {
+		t = &typeMetrics{
+			outcomes:     make([]atomic.Uint64, len(outcomes)),
+			lost:         make([]atomic.Uint64, len(lossReasons)),
+			bucketCounts: make([]atomic.Uint64, len(m.buckets)+1),
+		}
+		m.types[eventType] = t
+	}
+	return t
+}
+
+// observeEnqueue counts an accepted event
+func (m *QueueMetrics) observeEnqueue(eventType string) {
+	t := m.forType(eventType)
+	t.enqueued.Add(1)
+	t.depth.Add(1)
+}
+
+// observeDequeue counts an event taken off the queue, by the processor or without
+// being handled (purged, abandoned or handed over)
+func (m *QueueMetrics) observeDequeue(eventType string) {
+	m.forType(eventType).depth.Add(-1)
+}
+
+// observeComplete counts a completed event and records handler latency
+func (m *QueueMetrics) observeComplete(eventType, outcome string, processingTime time.Duration) {
+	t := m.forType(eventType)
+	for i, o := range outcomes {
+		if o == outcome {
+			t.outcomes[i].Add(1)
+			break
+		}
+	}
+
+	if outcome == OutcomeProcessed || outcome == OutcomeFailed {
+		seconds := processingTime.Seconds()
+		i := sort.SearchFloat64s(m.buckets, seconds)
+		t.bucketCounts[i].Add(1)
+		t.sumNanos.Add(int64(processingTime))
+		t.count.Add(1)
+	}
+}
+
+// observeRejected counts an event rejected because the queue was full
+func (m *QueueMetrics) observeRejected() {
+	m.rejected.Add(1)
+}
+
+// observeLoss counts an event lost for reason
+func (m *QueueMetrics) observeLoss(eventType, reason string) {
+	t := m.forType(eventType)
+	for i, r := range lossReasons {
+		if r == reason {
+			t.lost[i].Add(1)
+			break
+		}
+	}
+}
+
+// Rejected returns the number of events rejected with ErrQueueFull
+func (m *QueueMetrics) Rejected() uint64 {
+	return m.rejected.Load()
+}
+
+// Snapshot returns the current metrics per event type, sorted by type
+func (m *QueueMetrics) Snapshot() []TypeStats {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.types))
+	for name := range m.types {
+		names = append(names, name)
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	stats := make([]TypeStats, 0, len(names))
+	for _, name := range names {
+		t := m.forType(name)
+		s := TypeStats{
+			Type:     name,
+			Depth:    t.depth.Load(),
+			Enqueued: t.enqueued.Load(),
+			Outcomes: make(map[string]uint64, len(outcomes)),
+			Lost:     make(map[string]uint64, len(lossReasons)),
+			Processing: HistogramSnapshot{
+				Buckets: m.buckets,
+				Counts:  make([]uint64, len(m.buckets)),
+				Sum:     time.Duration(t.sumNanos.Load()),
+				Count:   t.count.Load(),
+			},
+		}
+		for i, o := range outcomes {
+			s.Outcomes[o] = t.outcomes[i].Load()
+		}
+		for i, r := range lossReasons {
+			s.Lost[r] = t.lost[i].Load()
+		}
+		var cumulative uint64
+		for i := range m.buckets {
+			cumulative += t.bucketCounts[i].Load()
+			s.Processing.Counts[i] = cumulative
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// WritePrometheus writes the metrics of one or more queues in the Prometheus text
+// exposition format, each metric family once with a queue label per series
+func WritePrometheus(w io.Writer, metrics ...*QueueMetrics) error {
+	type queueStats struct {
+		name  string
+		stats []TypeStats
+	}
+	all := make([]queueStats, len(metrics))
+	for i, m := range metrics {
+		all[i] = queueStats{name: m.name, stats: m.Snapshot()}
+	}
+
+	bw := bufio.NewWriter(w)
+	family := func(name, kind, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	labels := func(queue, eventType string, extra ...string) string {
+		pairs := []string{"queue", queue, "type", eventType}
+		pairs = append(pairs, extra...)
+		var b strings.Builder
+		b.WriteByte('{')
+		for i := 0; i < len(pairs); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(pairs[i])
+			b.WriteString(`="`)
+			b.WriteString(escapeLabel(pairs[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+		return b.String()
+	}
+
+	family("equeue_depth", "gauge", "Events currently queued")
+	for _, q := range all {
+		for _, s := range q.stats {
+			fmt.Fprintf(bw, "equeue_depth%s %d\n", labels(q.name, s.Type), s.Depth)
+		}
+	}
+
+	family("equeue_enqueued_total", "counter", "Events accepted into the queue")
+	for _, q := range all {
+		for _, s := range q.stats {
+			fmt.Fprintf(bw, "equeue_enqueued_total%s %d\n", labels(q.name, s.Type), s.Enqueued)
+		}
+	}
+
+	family("equeue_completed_total", "counter", "Events completed, by outcome")
+	for _, q := range all {
+		for _, s := range q.stats {
+			for _, o := range outcomes {
+				fmt.Fprintf(bw, "equeue_completed_total%s %d\n", labels(q.name, s.Type, "outcome", o), s.Outcomes[o])
+			}
+		}
+	}
+
+	family("equeue_failures_total", "counter", "Events that did not complete successfully")
+	for _, q := range all {
+		for _, s := range q.stats {
+			failures := s.Outcomes[OutcomeFailed] + s.Outcomes[OutcomeExpired] + s.Outcomes[OutcomeNoHandler] + s.Outcomes[OutcomeAbandoned] + s.Outcomes[OutcomePurged]
+			fmt.Fprintf(bw, "equeue_failures_total%s %d\n", labels(q.name, s.Type), failures)
+		}
+	}
+
+	family("equeue_lost_total", "counter", "Events rejected or dropped without being handled, by reason")
+	for _, q := range all {
+		for _, s := range q.stats {
+			for _, r := range lossReasons {
+				fmt.Fprintf(bw, "equeue_lost_total%s %d\n", labels(q.name, s.Type, "reason", r), s.Lost[r])
+			}
+		}
+	}
+
+	family("equeue_processing_seconds", "histogram", "Time spent in the event handler")
+	for _, q := range all {
+		for _, s := range q.stats {
+			h := s.Processing
+			for i, bound := range h.Buckets {
+				fmt.Fprintf(bw, "equeue_processing_seconds_bucket%s %d\n",
+					labels(q.name, s.Type, "le", strconv.FormatFloat(bound, 'g', -1, 64)), h.Counts[i])
+			}
+			fmt.Fprintf(bw, "equeue_processing_seconds_bucket%s %d\n", labels(q.name, s.Type, "le", "+Inf"), h.Count)
+			fmt.Fprintf(bw, "equeue_processing_seconds_sum%s %g\n", labels(q.name, s.Type), h.Sum.Seconds())
+			fmt.Fprintf(bw, "equeue_processing_seconds_count%s %d\n", labels(q.name, s.Type), h.Count)
+		}
+	}
+
+	family("equeue_rejected_total", "counter", "Events rejected because the queue was full")
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "equeue_rejected_total{queue=\"%s\"} %d\n", escapeLabel(m.name), m.Rejected())
+	}
+
+	return bw.Flush()
+}
+
+// MetricsHandler serves WritePrometheus output for a /metrics endpoint
+func MetricsHandler(metrics ...*QueueMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WritePrometheus(w, metrics...)
+	})
+}
+
+// escapeLabel escapes a label value for the text exposition format
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
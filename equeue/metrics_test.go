@@ -0,0 +1,151 @@
+package equeue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestQueueMetrics_Snapshot tests per-type depth, outcome and latency counters
+func TestQueueMetrics_Snapshot(t *testing.T) {
+	metrics := NewQueueMetrics("q", 0.1, 0.01)
+	eq := NewEventQueue(EventQueueConfig{Metrics: metrics})
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	eq.RegisterHandler("ok", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}))
+	eq.RegisterHandler("fail", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		return errors.New("rejected")
+	}))
+
+	eq.Start(context.Background())
+	for i := 0; i < 3; i++ {
+		eq.Enqueue(NewEvent("ok", context.Background()))
+	}
+	<-started
+	eq.Enqueue(NewEvent("fail", context.Background()))
+	eq.Enqueue(NewEvent("fail", context.Background(), WithDeadline(time.Now().Add(-time.Second))))
+	eq.Enqueue(NewEvent("unknown", context.Background()))
+
+	stats := metrics.Snapshot()
+	if len(stats) != 3 {
+		t.Fatalf("Snapshot() has %d types, want 3", len(stats))
+	}
+	for i, want := range []struct {
+		eventType string
+		depth     int64
+		enqueued  uint64
+	}{{"fail", 2, 2}, {"ok", 2, 3}, {"unknown", 1, 1}} {
+		if stats[i].Type != want.eventType || stats[i].Depth != want.depth || stats[i].Enqueued != want.enqueued {
+			t.Errorf("Snapshot()[%d] = %s depth %d enqueued %d, want %s depth %d enqueued %d",
+				i, stats[i].Type, stats[i].Depth, stats[i].Enqueued, want.eventType, want.depth, want.enqueued)
+		}
+	}
+
+	close(release)
+	eq.Stop()
+
+	want := map[string]map[string]uint64{
+		"fail":    {OutcomeFailed: 1, OutcomeExpired: 1},
+		"ok":      {OutcomeProcessed: 3},
+		"unknown": {OutcomeNoHandler: 1},
+	}
+	for _, s := range metrics.Snapshot() {
+		if s.Depth != 0 {
+			t.Errorf("%s depth = %d after Stop, want 0", s.Type, s.Depth)
+		}
+		for _, outcome := range outcomes {
+			if s.Outcomes[outcome] != want[s.Type][outcome] {
+				t.Errorf("%s Outcomes[%s] = %d, want %d", s.Type, outcome, s.Outcomes[outcome], want[s.Type][outcome])
+			}
+		}
+		// Only events that reached a handler are timed
+		handled := want[s.Type][OutcomeProcessed] + want[s.Type][OutcomeFailed]
+		if s.Processing.Count != handled {
+			t.Errorf("%s Processing.Count = %d, want %d", s.Type, s.Processing.Count, handled)
+		}
+		if len(s.Processing.Counts) != 2 || s.Processing.Counts[1] < s.Processing.Counts[0] {
+			t.Errorf("%s Processing.Counts = %v, want 2 cumulative buckets", s.Type, s.Processing.Counts)
+		}
+	}
+}
+
+// TestWritePrometheus tests the text exposition of several queues
+func TestWritePrometheus(t *testing.T) {
+	ingress := NewQueueMetrics("ingress", 0.5, 0.1)
+	ingress.observeEnqueue("ulr")
+	ingress.observeEnqueue("ulr")
+	ingress.observeDequeue("ulr")
+	ingress.observeComplete("ulr", OutcomeProcessed, 50*time.Millisecond)
+	ingress.observeEnqueue("ulr")
+	ingress.observeDequeue("ulr")
+	ingress.observeComplete("ulr", OutcomeFailed, 200*time.Millisecond)
+	ingress.observeLoss("ulr", LossQueueFull)
+	ingress.observeRejected()
+
+	egress := NewQueueMetrics(`out"bound`)
+	egress.observeEnqueue("notify")
+	egress.observeDequeue("notify")
+	egress.observeComplete("notify", OutcomePurged, 0)
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, ingress, egress); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	text := buf.String()
+
+	for _, line := range []string{
+		"# HELP equeue_depth Events currently queued",
+		"# TYPE equeue_depth gauge",
+		`equeue_depth{queue="ingress",type="ulr"} 1`,
+		`equeue_depth{queue="out\"bound",type="notify"} 0`,
+		`equeue_enqueued_total{queue="ingress",type="ulr"} 3`,
+		`equeue_completed_total{queue="ingress",type="ulr",outcome="processed"} 1`,
+		`equeue_completed_total{queue="ingress",type="ulr",outcome="failed"} 1`,
+		`equeue_completed_total{queue="out\"bound",type="notify",outcome="purged"} 1`,
+		`equeue_failures_total{queue="ingress",type="ulr"} 1`,
+		`equeue_failures_total{queue="out\"bound",type="notify"} 1`,
+		`equeue_lost_total{queue="ingress",type="ulr",reason="queue_full"} 1`,
+		"# TYPE equeue_processing_seconds histogram",
+		`equeue_processing_seconds_bucket{queue="ingress",type="ulr",le="0.1"} 1`,
+		`equeue_processing_seconds_bucket{queue="ingress",type="ulr",le="0.5"} 2`,
+		`equeue_processing_seconds_bucket{queue="ingress",type="ulr",le="+Inf"} 2`,
+		`equeue_processing_seconds_sum{queue="ingress",type="ulr"} 0.25`,
+		`equeue_processing_seconds_count{queue="ingress",type="ulr"} 2`,
+		`equeue_processing_seconds_count{queue="out\"bound",type="notify"} 0`,
+		`equeue_rejected_total{queue="ingress"} 1`,
+		`equeue_rejected_total{queue="out\"bound"} 0`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Output missing %q", line)
+		}
+	}
+
+	// Each family is declared once across queues
+	if n := strings.Count(text, "# TYPE equeue_depth "); n != 1 {
+		t.Errorf("equeue_depth declared %d times, want 1", n)
+	}
+}
+
+// TestMetricsHandler tests the /metrics endpoint content type and body
+func TestMetricsHandler(t *testing.T) {
+	metrics := NewQueueMetrics("q")
+	metrics.observeEnqueue("a")
+
+	rec := httptest.NewRecorder()
+	MetricsHandler(metrics).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the text exposition format", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `equeue_depth{queue="q",type="a"} 1`) {
+		t.Errorf("Body missing depth, got:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,131 @@
+package equeue
+
+import (
+	"context"
+	"time"
+)
+
+// Middleware wraps an event handler with cross-cutting behaviour
+type Middleware func(next IEventHandler) IEventHandler
+
+// Chain wraps handler with middlewares; the first middleware is the outermost
+func Chain(handler IEventHandler, middlewares ...Middleware) IEventHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Logger is the structured logger used by LoggingMiddleware
+type Logger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+}
+
+// LoggingMiddleware logs each handled event with its metadata (correlation ID, origin interface, tenant)
+// Successful events are logged at debug level, failures at warn level
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next IEventHandler) IEventHandler {
+		return EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+
+			kv := []interface{}{
+				"event_id", event.GetID(),
+				"type", event.GetType(),
+				"duration", time.Since(start),
+			}
+			kv = append(kv, MetadataFromContext(ctx).KeysAndValues()...)
+
+			if err != nil {
+				logger.Warnw("Event handling failed", append(kv, "error", err)...)
+			} else {
+				logger.Debugw("Event handled", kv...)
+			}
+			return err
+		})
+	}
+}
+
+// MetricsRecorder receives one observation per handled event
+// metadata lets recorders label metrics by origin interface or tenant
+type MetricsRecorder interface {
+	ObserveEvent(eventType string, metadata Metadata, duration time.Duration, err error)
+}
+
+// MetricsMiddleware reports each handled event, with its metadata, to recorder
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next IEventHandler) IEventHandler {
+		return EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+			start := time.Now()
+			err := next.Handle(ctx, event)
+			recorder.ObserveEvent(event.GetType(), MetadataFromContext(ctx), time.Since(start), err)
+			return err
+		})
+	}
+}
+
+// RetryPolicy controls retries of a failed handler
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first (default: 3)
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry (default: 10ms)
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the doubling backoff (default: 1s)
+	MaxBackoff time.Duration
+
+	// Retryable reports whether an error is worth retrying (default: all errors)
+	Retryable func(err error) bool
+}
+
+// RetryMiddleware retries failed handling with exponential backoff
+// Retries stop early when the event's context is done or its deadline has passed
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = 10 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = time.Second
+	}
+
+	return func(next IEventHandler) IEventHandler {
+		return EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+			backoff := policy.InitialBackoff
+			for attempt := 1; ; attempt++ {
+				err := next.Handle(ctx, event)
+				if err == nil || attempt >= policy.MaxAttempts || event.IsExpired() {
+					return err
+				}
+				if policy.Retryable != nil && !policy.Retryable(err) {
+					return err
+				}
+
+				select {
+				case <-ctx.Done():
+					return err
+				case <-time.After(backoff):
+				}
+				if backoff *= 2; backoff > policy.MaxBackoff {
+					backoff = policy.MaxBackoff
+				}
+			}
+		})
+	}
+}
+
+// TimeoutMiddleware handles each event with a context that times out after d,
+// with cause ErrHandlerTimeout
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(next IEventHandler) IEventHandler {
+		return EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+			ctx, cancel := context.WithTimeoutCause(ctx, d, ErrHandlerTimeout)
+			defer cancel()
+			return withCause(ctx, next.Handle(ctx, event))
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingLogger records the messages logged through Logger
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+	kv       []interface{}
+}
+
+func (l *recordingLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.record("debug: "+msg, keysAndValues)
+}
+
+func (l *recordingLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.record("warn: "+msg, keysAndValues)
+}
+
+func (l *recordingLogger) record(msg string, keysAndValues []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+	l.kv = keysAndValues
+}
+
+// recordingMiddleware appends name to calls before and after calling next
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next IEventHandler) IEventHandler {
+		return EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+			*calls = append(*calls, name+" before")
+			err := next.Handle(ctx, event)
+			*calls = append(*calls, name+" after")
+			return err
+		})
+	}
+}
+
+// TestChain tests middleware order and short-circuiting
+func TestChain(t *testing.T) {
+	errDenied := errors.New("denied")
+	deny := func(next IEventHandler) IEventHandler {
+		return EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+			return errDenied
+		})
+	}
+
+	tests := []struct {
+		name      string
+		build     func(calls *[]string, handler IEventHandler) IEventHandler
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name: "no middleware",
+			build: func(calls *[]string, handler IEventHandler) IEventHandler {
+				return Chain(handler)
+			},
+			wantCalls: []string{"handler"},
+		},
+		{
+			name: "first is outermost",
+			build: func(calls *[]string, handler IEventHandler) IEventHandler {
+				return Chain(handler, recordingMiddleware("a", calls), recordingMiddleware("b", calls))
+			},
+			wantCalls: []string{"a before", "b before", "handler", "b after", "a after"},
+		},
+		{
+			name: "short-circuit skips inner middleware and handler",
+			build: func(calls *[]string, handler IEventHandler) IEventHandler {
+				return Chain(handler, recordingMiddleware("a", calls), deny, recordingMiddleware("b", calls))
+			},
+			wantCalls: []string{"a before", "a after"},
+			wantErr:   errDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			handler := EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				calls = append(calls, "handler")
+				return nil
+			})
+
+			err := tt.build(&calls, handler).Handle(context.Background(), NewEvent("a", context.Background()))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("Calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestLoggingMiddleware tests events are logged by result with their metadata
+func TestLoggingMiddleware(t *testing.T) {
+	logger := &recordingLogger{}
+	fail := true
+	handler := Chain(EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		if fail {
+			return errors.New("rejected")
+		}
+		return nil
+	}), LoggingMiddleware(logger))
+
+	event := NewEvent("ulr", context.Background(), WithCorrelationID("session-1"))
+	handler.Handle(event.GetContext(), event)
+	fail = false
+	handler.Handle(event.GetContext(), event)
+
+	want := []string{"warn: Event handling failed", "debug: Event handled"}
+	if !reflect.DeepEqual(logger.messages, want) {
+		t.Errorf("Messages = %v, want %v", logger.messages, want)
+	}
+	var correlationID interface{}
+	for i := 0; i+1 < len(logger.kv); i += 2 {
+		if logger.kv[i] == string(MetaCorrelationID) {
+			correlationID = logger.kv[i+1]
+		}
+	}
+	if correlationID != "session-1" {
+		t.Errorf("Logged correlation_id = %v, want session-1", correlationID)
+	}
+}
+
+// metricsRecorderFunc adapts a function to MetricsRecorder
+type metricsRecorderFunc func(eventType string, metadata Metadata, duration time.Duration, err error)
+
+func (f metricsRecorderFunc) ObserveEvent(eventType string, metadata Metadata, duration time.Duration, err error) {
+	f(eventType, metadata, duration, err)
+}
+
+// TestMetricsMiddleware tests each handled event is observed with its metadata
+func TestMetricsMiddleware(t *testing.T) {
+	errRejected := errors.New("rejected")
+	var observed []string
+	recorder := metricsRecorderFunc(func(eventType string, metadata Metadata, duration time.Duration, err error) {
+		observed = append(observed, eventType+"/"+metadata[MetaOriginInterface])
+		if !errors.Is(err, errRejected) {
+			t.Errorf("Observed error = %v, want %v", err, errRejected)
+		}
+	})
+
+	handler := Chain(EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		return errRejected
+	}), MetricsMiddleware(recorder))
+	event := NewEvent("check", context.Background(), WithOriginInterface("N5g-eir"))
+	handler.Handle(event.GetContext(), event)
+
+	if want := []string{"check/N5g-eir"}; !reflect.DeepEqual(observed, want) {
+		t.Errorf("Observed = %v, want %v", observed, want)
+	}
+}
+
+// TestRetryMiddleware tests retries stop on success, at MaxAttempts and on non-retryable errors
+func TestRetryMiddleware(t *testing.T) {
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		errs         []error // Returned by successive attempts, then nil
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "succeeds after retries",
+			policy:       RetryPolicy{InitialBackoff: time.Millisecond},
+			errs:         []error{errTransient, errTransient},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up at MaxAttempts",
+			policy:       RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			errs:         []error{errTransient, errTransient, errTransient},
+			wantAttempts: 2,
+			wantErr:      errTransient,
+		},
+		{
+			name: "non-retryable",
+			policy: RetryPolicy{
+				InitialBackoff: time.Millisecond,
+				Retryable:      func(err error) bool { return !errors.Is(err, errPermanent) },
+			},
+			errs:         []error{errTransient, errPermanent},
+			wantAttempts: 2,
+			wantErr:      errPermanent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			handler := Chain(EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			}), RetryMiddleware(tt.policy))
+
+			err := handler.Handle(context.Background(), NewEvent("a", context.Background()))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// TestTimeoutMiddleware tests a slow handler fails with ErrHandlerTimeout
+func TestTimeoutMiddleware(t *testing.T) {
+	handler := Chain(EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), TimeoutMiddleware(10*time.Millisecond))
+
+	err := handler.Handle(context.Background(), NewEvent("a", context.Background()))
+	if !errors.Is(err, ErrHandlerTimeout) {
+		t.Errorf("Handle() error = %v, want %v", err, ErrHandlerTimeout)
+	}
+}
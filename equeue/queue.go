@@ -6,16 +6,27 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// ErrQueueFull is returned by Enqueue when the buffer is full
+var ErrQueueFull = errors.New("queue is full")
+
+// ErrQueueShutdown completes events abandoned when a drain deadline passes
+var ErrQueueShutdown = errors.New("queue shut down before event was processed")
+
 // ProcessingMode defines how events should be processed
 type ProcessingMode int
 
 const (
 	// Sequential mode processes events one at a time in order
 	Sequential ProcessingMode = iota
-	// Parallel mode processes events concurrently
+	// Parallel mode processes events concurrently on a worker pool (see
+	// EventQueueConfig.Workers and WorkerAutotune)
 	Parallel
+	// Inline mode handles each event synchronously inside Enqueue, on the caller's
+	// goroutine, for deterministic unit tests; Start and Stop are optional
+	Inline
 )
 
 // String returns the string representation of ProcessingMode
@@ -25,6 +36,8 @@ func (pm ProcessingMode) String() string {
 		return "sequential"
 	case Parallel:
 		return "parallel"
+	case Inline:
+		return "inline"
 	default:
 		return "unknown"
 	}
@@ -39,7 +52,7 @@ type IEventQueue interface {
 	// Stop gracefully stops the queue processing
 	Stop() error
 	// RegisterHandler registers a handler for a specific event type
-	RegisterHandler(eventType string, handler IEventHandler)
+	RegisterHandler(eventType string, handler IEventHandler, options ...HandlerOption)
 	// GetQueueSize returns the current number of events in the queue
 	GetQueueSize() int
 }
@@ -47,20 +60,125 @@ type IEventQueue interface {
 // EventQueue is the default implementation of IEventQueue
 // Uses lock-free design for sequential processing
 type EventQueue struct {
-	events     chan IEvent
-	handlers   map[string]IEventHandler
+	events     eventBuffer
+	fair       *fairBuffer // Same as events when weighted fair queuing is enabled
+	handlers   map[string]*registeredHandler
+	classSlots map[string]chan struct{}
 	mode       atomic.Int32
 	wg         sync.WaitGroup
 	ctx        context.Context
-	cancel     context.CancelFunc
+	cancel     context.CancelCauseFunc
 	bufferSize int
 	running    atomic.Bool
+
+	// abort cancels the contexts of running handlers with ErrShuttingDown
+	abort       context.Context
+	abortCancel context.CancelCauseFunc
+
+	// Drain state, set before cancel and read by the processing loop
+	drainCtx context.Context
+	drainMu  sync.Mutex  // Guards draining, drained and handling
+	draining bool        // Outcomes of events completed now count towards drained
+	drained  DrainReport // Outcome counts of the current drain
+	handling int         // Events dispatched and not yet completed
+
+	dedup      *deduplicator // nil when duplicate suppression is disabled
+	duplicates atomic.Uint64
+
+	losses lossCounter
+
+	watermarks *watermarks // nil when no high watermark is configured
+
+	pool     *workerPool     // nil outside Parallel mode
+	autotune *WorkerAutotune // nil when the pool size is static
+
+	handover    *HandoverConfig // nil when handover is disabled
+	inflight    *inflightEvents // Events being handled, tracked for handover only
+	handingOver atomic.Bool     // Set by Handover: the processing loop stops without draining
+
+	schemas *SchemaRegistry // nil when payloads are not validated
+
+	hooks       EventHooks
+	auditWriter AuditWriter
+	metrics     *QueueMetrics // nil when metrics are disabled
 }
 
+// DrainReport summarizes the events handled while stopping the queue
+// Every event queued or being handled when the drain starts is counted once
+type DrainReport struct {
+	Processed int           // Handled successfully during the drain
+	Failed    int           // Handled with an error (including expired and aborted events)
+	Abandoned int           // Failed with ErrQueueShutdown, or still being handled, after the deadline
+	Duration  time.Duration // Time taken by the drain
+}
+
+// drainAbortGrace is how long Drain waits, after the deadline, for aborted handlers
+// to return so their outcome is reported
+const drainAbortGrace = 100 * time.Millisecond
+
 // EventQueueConfig holds configuration for creating an event queue
 type EventQueueConfig struct {
 	BufferSize     int
 	ProcessingMode ProcessingMode
+
+	// Backend selects the buffer implementation (default: ChannelBackend)
+	Backend QueueBackend
+
+	// DedupWindow enables duplicate suppression: events with the same idempotency key
+	// enqueued while the original is pending, or within the window after it completes,
+	// are coalesced and receive the original's result (0 = disabled)
+	DedupWindow time.Duration
+
+	// HighWatermark marks the queue saturated once depth reaches it (0 = disabled)
+	HighWatermark int
+
+	// LowWatermark clears saturation once depth falls to it (default: HighWatermark/2)
+	LowWatermark int
+
+	// OnHighWatermark is called when the queue becomes saturated
+	OnHighWatermark func(depth int)
+
+	// OnLowWatermark is called when the queue recovers from saturation
+	OnLowWatermark func(depth int)
+
+	// Hooks are lifecycle callbacks for tracing
+	Hooks EventHooks
+
+	// AuditWriter receives a record for every completed event (optional)
+	AuditWriter AuditWriter
+
+	// Metrics collects per-event-type depth, latency and outcome counts (optional)
+	Metrics *QueueMetrics
+
+	// ClassConcurrency limits concurrent handling across all handlers registered
+	// WithQueueClass for each class (e.g., {"bulk": 4})
+	ClassConcurrency map[string]int
+
+	// TypeWeights enables weighted fair queuing across event types: each event type
+	// is queued separately and, in turn, may dispatch up to its weight in events
+	// (e.g., {"peer_state": 8, "cdr": 1}). Types whose handler has no free slot are
+	// skipped, so control-plane events keep flowing during data-plane bursts.
+	// Overrides Backend; BufferSize still bounds the total across types
+	TypeWeights map[string]int
+
+	// DefaultTypeWeight is the weight of event types missing from TypeWeights (default: 1)
+	DefaultTypeWeight int
+
+	// Workers is the number of events handled at once in Parallel mode
+	// (default: GOMAXPROCS); with WorkerAutotune it is the starting pool size
+	Workers int
+
+	// WorkerAutotune resizes the Parallel mode worker pool from observed arrival
+	// rate and handler latency instead of keeping Workers fixed (optional)
+	WorkerAutotune *WorkerAutotune
+
+	// Handover saves queued and in-flight events to a file across planned restarts
+	// (see Handover and RestoreHandover, optional)
+	Handover *HandoverConfig
+
+	// Schemas validates event payloads at enqueue time: events whose payload fails
+	// the schema of their type are rejected with a *PayloadError (optional)
+	Schemas *SchemaRegistry
 }
 
 // NewEventQueue creates a new event queue with the given configuration
@@ -70,12 +188,46 @@ func NewEventQueue(config EventQueueConfig) *EventQueue {
 	}
 
 	eq := &EventQueue{
-		events:     make(chan IEvent, config.BufferSize),
-		handlers:   make(map[string]IEventHandler),
+		handlers:   make(map[string]*registeredHandler),
+		classSlots: make(map[string]chan struct{}),
 		bufferSize: config.BufferSize,
+
+		hooks:       config.Hooks,
+		auditWriter: config.AuditWriter,
+		metrics:     config.Metrics,
+		schemas:     config.Schemas,
+	}
+	if len(config.TypeWeights) > 0 {
+		eq.fair = newFairBuffer(config.BufferSize, config.TypeWeights, config.DefaultTypeWeight)
+		eq.fair.ready = eq.canDispatch
+		eq.events = eq.fair
+	} else {
+		eq.events = newEventBuffer(config.Backend, config.BufferSize)
 	}
 	eq.mode.Store(int32(config.ProcessingMode))
 	eq.running.Store(false)
+	if config.DedupWindow > 0 {
+		eq.dedup = newDeduplicator(config.DedupWindow)
+	}
+	eq.watermarks = newWatermarks(config)
+	eq.pool = newWorkerPool(config)
+	if eq.pool != nil && config.WorkerAutotune != nil {
+		autotune := *config.WorkerAutotune
+		eq.autotune = &autotune
+	}
+	if config.Handover != nil {
+		handover := *config.Handover
+		if handover.Codec == nil {
+			handover.Codec = eventCodec{}
+		}
+		eq.handover = &handover
+		eq.inflight = &inflightEvents{events: make(map[uint64]IEvent), claimed: make(map[uint64]bool)}
+	}
+	for class, limit := range config.ClassConcurrency {
+		if limit > 0 {
+			eq.classSlots[class] = make(chan struct{}, limit)
+		}
+	}
 
 	return eq
 }
@@ -90,19 +242,109 @@ func NewDefaultEventQueue() *EventQueue {
 	})
 }
 
+// NewTestQueue creates an Inline queue: Enqueue runs the registered handler, with
+// its middleware, before returning, so the event's result is ready for Wait
+func NewTestQueue() *EventQueue {
+	return NewEventQueue(EventQueueConfig{ProcessingMode: Inline})
+}
+
 // Enqueue adds an event to the queue
+// In Inline mode the event is handled before Enqueue returns
 func (eq *EventQueue) Enqueue(event IEvent) error {
+	if ProcessingMode(eq.mode.Load()) == Inline {
+		return eq.enqueueInline(event)
+	}
+
 	if !eq.running.Load() {
-		return fmt.Errorf("queue is stopped")
+		eq.onLoss(event, LossStopped)
+		return ErrQueueStopped
 	}
 
-	select {
-	case eq.events <- event:
+	if err := eq.validatePayload(event); err != nil {
+		return err
+	}
+
+	key := eq.dedupKey(event)
+	if key != "" && !eq.dedup.admit(key, event) {
+		eq.duplicates.Add(1)
 		return nil
-	case <-eq.ctx.Done():
-		return fmt.Errorf("queue context cancelled")
-	default:
-		return fmt.Errorf("queue is full")
+	}
+
+	if eq.ctx.Err() != nil {
+		err := fmt.Errorf("queue context cancelled: %w", context.Cause(eq.ctx))
+		eq.forgetKey(key, err)
+		eq.onLoss(event, LossStopped)
+		return err
+	}
+
+	if !eq.events.offer(event) {
+		eq.forgetKey(key, ErrQueueFull)
+		if eq.metrics != nil {
+			eq.metrics.observeRejected()
+		}
+		eq.onLoss(event, LossQueueFull)
+		eq.observeDepth()
+		return ErrQueueFull
+	}
+
+	if eq.pool != nil {
+		eq.pool.arrivals.Add(1)
+	}
+	eq.onEnqueue(event)
+	eq.observeDepth()
+	return nil
+}
+
+// enqueueInline handles event on the caller's goroutine
+// Handler concurrency and class limits do not apply; expiry, middleware, hooks,
+// auditing and duplicate suppression do
+func (eq *EventQueue) enqueueInline(event IEvent) error {
+	if eq.ctx != nil && eq.ctx.Err() != nil {
+		eq.onLoss(event, LossStopped)
+		return ErrQueueStopped
+	}
+
+	if err := eq.validatePayload(event); err != nil {
+		return err
+	}
+
+	key := eq.dedupKey(event)
+	if key != "" && !eq.dedup.admit(key, event) {
+		eq.duplicates.Add(1)
+		return nil
+	}
+
+	eq.onEnqueue(event)
+	eq.handleEvent(event)
+	return nil
+}
+
+// DuplicateCount returns the number of events coalesced by duplicate suppression
+func (eq *EventQueue) DuplicateCount() uint64 {
+	return eq.duplicates.Load()
+}
+
+// dedupKey returns the idempotency key to deduplicate on, or "" if disabled
+func (eq *EventQueue) dedupKey(event IEvent) string {
+	if eq.dedup == nil {
+		return ""
+	}
+	return idempotencyKey(event)
+}
+
+// forgetKey releases a key whose event was rejected
+func (eq *EventQueue) forgetKey(key string, err error) {
+	if key != "" {
+		eq.dedup.forget(key, err)
+	}
+}
+
+// complete signals the event's result, sharing it with coalesced duplicates
+func (eq *EventQueue) complete(event IEvent, result interface{}, err error) {
+	event.Done(result, err)
+
+	if key := eq.dedupKey(event); key != "" {
+		eq.dedup.complete(key, result, err)
 	}
 }
 
@@ -112,39 +354,125 @@ func (eq *EventQueue) Start(ctx context.Context) error {
 		return fmt.Errorf("queue is already running")
 	}
 
-	eq.ctx, eq.cancel = context.WithCancel(ctx)
+	eq.ctx, eq.cancel = context.WithCancelCause(ctx)
+	eq.abort, eq.abortCancel = context.WithCancelCause(context.Background())
+	eq.handingOver.Store(false)
 
 	eq.wg.Add(1)
 	go eq.processEvents()
 
+	if eq.autotune != nil {
+		eq.wg.Add(1)
+		go eq.autotuneWorkers(*eq.autotune)
+	}
+
 	return nil
 }
 
 // Stop gracefully stops the queue processing
+// Waits until every queued event has been processed; use Drain or StopWithTimeout to bound the wait
 func (eq *EventQueue) Stop() error {
+	_, err := eq.Drain(context.Background())
+	return err
+}
+
+// StopWithTimeout stops the queue, processing queued events for at most d
+func (eq *EventQueue) StopWithTimeout(d time.Duration) (DrainReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return eq.Drain(ctx)
+}
+
+// Drain stops the queue and processes queued events until ctx is done
+// Events still queued at the deadline are completed with ErrQueueShutdown, unblocking
+// their Wait(), and handlers still running have their context cancelled with cause
+// ErrShuttingDown. Handlers that don't return shortly after are counted as abandoned
+// and left to finish in the background. Returns ctx.Err() if the deadline passed
+func (eq *EventQueue) Drain(ctx context.Context) (DrainReport, error) {
 	if !eq.running.CompareAndSwap(true, false) {
-		return fmt.Errorf("queue is already stopped")
+		return DrainReport{}, fmt.Errorf("queue is already stopped")
 	}
 
+	start := time.Now()
+	eq.drainMu.Lock()
+	eq.draining = true
+	eq.drained = DrainReport{}
+	eq.drainMu.Unlock()
+	eq.drainCtx = ctx
+
 	if eq.cancel != nil {
-		eq.cancel()
+		eq.cancel(ErrShuttingDown)
 	}
 
-	eq.wg.Wait()
+	stopped := make(chan struct{})
+	go func() {
+		eq.wg.Wait()
+		close(stopped)
+	}()
 
-	return nil
+	finished := true
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		// The processing loop stops handling at the deadline; abandon what is left
+		// and ask running handlers to give up
+		eq.abandonQueue()
+		if eq.abortCancel != nil {
+			eq.abortCancel(ErrShuttingDown)
+		}
+		select {
+		case <-stopped:
+		case <-time.After(drainAbortGrace):
+			finished = false
+		}
+	}
+	if eq.abortCancel != nil {
+		eq.abortCancel(ErrShuttingDown)
+	}
+
+	eq.drainMu.Lock()
+	report := eq.drained
+	if !finished {
+		report.Abandoned += eq.handling
+	}
+	eq.draining = false
+	eq.drainMu.Unlock()
+	report.Duration = time.Since(start)
+
+	if !finished || report.Abandoned > 0 {
+		return report, ctx.Err()
+	}
+	return report, nil
 }
 
 // RegisterHandler registers a handler for a specific event type
 // Only one handler per event type is allowed. Registering a new handler will replace the existing one.
+// Options declare how the handler runs (WithConcurrency, WithRetry, WithHandlerTimeout, WithQueueClass)
 // Note: Should be called before Start() to avoid race conditions
-func (eq *EventQueue) RegisterHandler(eventType string, handler IEventHandler) {
-	eq.handlers[eventType] = handler
+func (eq *EventQueue) RegisterHandler(eventType string, handler IEventHandler, options ...HandlerOption) {
+	var config handlerConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	// Retry wraps the timeout so that each attempt gets the full timeout
+	if config.timeout > 0 {
+		handler = TimeoutMiddleware(config.timeout)(handler)
+	}
+	if config.retry != nil {
+		handler = RetryMiddleware(*config.retry)(handler)
+	}
+
+	entry := &registeredHandler{handler: handler, class: config.class}
+	if config.concurrency > 1 || eq.classSlots[config.class] != nil {
+		entry.slots = make(chan struct{}, max(config.concurrency, 1))
+	}
+	eq.handlers[eventType] = entry
 }
 
 // GetQueueSize returns the current number of events in the queue
 func (eq *EventQueue) GetQueueSize() int {
-	return len(eq.events)
+	return eq.events.size()
 }
 
 // processEvents is the main event processing loop
@@ -152,51 +480,250 @@ func (eq *EventQueue) processEvents() {
 	defer eq.wg.Done()
 
 	for {
-		select {
-		case event := <-eq.events:
-			eq.handleEvent(event)
-		case <-eq.ctx.Done():
-			// Process remaining events before shutting down
-			eq.drainQueue()
+		// Once stopping, remaining events are handled (and counted) by the drain
+		if eq.ctx.Err() != nil {
+			eq.stopProcessing()
 			return
 		}
+
+		// In Parallel mode, wait for a worker before taking the next event
+		if eq.pool != nil && !eq.pool.acquire(eq.ctx) {
+			eq.stopProcessing()
+			return
+		}
+
+		event, ok := eq.events.take(eq.ctx)
+		if !ok {
+			if eq.pool != nil {
+				eq.pool.release()
+			}
+			eq.stopProcessing()
+			return
+		}
+		eq.observeDepth()
+		eq.dispatch(event)
+	}
+}
+
+// stopProcessing drains the queue as the processing loop exits, unless the
+// remaining events are being handed over
+func (eq *EventQueue) stopProcessing() {
+	if !eq.handingOver.Load() {
+		eq.drainQueue()
 	}
 }
 
+// dispatch handles event on the processing loop, or on its own goroutine once a slot
+// is free for handlers registered WithConcurrency or in a limited class
+// In Parallel mode every event gets its own goroutine, on the worker slot the
+// caller acquired
+func (eq *EventQueue) dispatch(event IEvent) {
+	eq.startHandling()
+	entry := eq.handlers[event.GetType()]
+	if eq.pool != nil {
+		eq.dispatchPooled(event, entry)
+		return
+	}
+	if entry == nil || entry.slots == nil {
+		eq.finishHandling(eq.handleEvent(event))
+		return
+	}
+
+	classSlots := eq.classSlots[entry.class]
+	if classSlots != nil {
+		classSlots <- struct{}{}
+	}
+	entry.slots <- struct{}{}
+
+	eq.wg.Add(1)
+	go func() {
+		defer eq.wg.Done()
+		err := eq.handleEvent(event)
+		<-entry.slots
+		if classSlots != nil {
+			<-classSlots
+		}
+		if eq.fair != nil {
+			eq.fair.signal()
+		}
+		eq.finishHandling(err)
+	}()
+}
+
+// dispatchPooled handles event on its own goroutine, releasing its worker slot after
+func (eq *EventQueue) dispatchPooled(event IEvent, entry *registeredHandler) {
+	var classSlots chan struct{}
+	if entry != nil && entry.slots != nil {
+		classSlots = eq.classSlots[entry.class]
+		if classSlots != nil {
+			classSlots <- struct{}{}
+		}
+		entry.slots <- struct{}{}
+	}
+
+	eq.wg.Add(1)
+	go func() {
+		defer eq.wg.Done()
+		start := time.Now()
+		err := eq.handleEvent(event)
+		eq.pool.observe(time.Since(start))
+		if entry != nil && entry.slots != nil {
+			<-entry.slots
+			if classSlots != nil {
+				<-classSlots
+			}
+		}
+		eq.pool.release()
+		if eq.fair != nil {
+			eq.fair.signal()
+		}
+		eq.finishHandling(err)
+	}()
+}
+
+// startHandling records an event being dispatched
+func (eq *EventQueue) startHandling() {
+	eq.drainMu.Lock()
+	defer eq.drainMu.Unlock()
+	eq.handling++
+}
+
+// finishHandling records the outcome of a handled event, counted in the drain report
+// while draining
+func (eq *EventQueue) finishHandling(err error) {
+	eq.drainMu.Lock()
+	defer eq.drainMu.Unlock()
+	eq.handling--
+	if !eq.draining {
+		return
+	}
+	if err != nil {
+		eq.drained.Failed++
+	} else {
+		eq.drained.Processed++
+	}
+}
+
+// canDispatch reports whether dispatching an event of eventType would start it
+// without waiting for a handler or class slot
+func (eq *EventQueue) canDispatch(eventType string) bool {
+	entry := eq.handlers[eventType]
+	if entry == nil || entry.slots == nil {
+		return true
+	}
+	if len(entry.slots) >= cap(entry.slots) {
+		return false
+	}
+	classSlots := eq.classSlots[entry.class]
+	return classSlots == nil || len(classSlots) < cap(classSlots)
+}
+
 // handleEvent processes a single event based on the processing mode
-func (eq *EventQueue) handleEvent(event IEvent) {
+// Returns the error the event was completed with
+func (eq *EventQueue) handleEvent(event IEvent) error {
+	queueTime := eq.onDequeue(event)
+	if eq.inflight != nil {
+		eq.inflight.add(event)
+	}
 
 	// Check if event has expired
 	if event.IsExpired() {
-		event.Done(nil, errors.New("event expired: deadline exceeded"))
-		return
+		err := ErrEventExpired
+		if !eq.handled(event) {
+			return err
+		}
+		eq.onExpire(event, queueTime)
+		eq.onLoss(event, LossExpired)
+		eq.complete(event, nil, err)
+		eq.audit(event, OutcomeExpired, queueTime, 0, err)
+		return err
 	}
 
-	handler, exists := eq.handlers[event.GetType()]
+	entry, exists := eq.handlers[event.GetType()]
 	if !exists {
-		event.Done(nil, errors.New("no handler registered for event type"))
-		return
+		err := errors.New("no handler registered for event type")
+		if !eq.handled(event) {
+			return err
+		}
+		eq.onLoss(event, LossNoHandler)
+		eq.complete(event, nil, err)
+		eq.audit(event, OutcomeNoHandler, queueTime, 0, err)
+		return err
 	}
 
-	// Log event processing start
-
-	// Call the handler and set result
-	err := handler.Handle(event.GetContext(), event)
+	// Call the handler and set result; errors from a cancelled context carry its cause
+	ctx, cancel := eq.handlerContext(event)
+	start := time.Now()
+	err := withCause(ctx, entry.handler.Handle(ctx, event))
+	processingTime := time.Since(start)
+	cancel()
+	if !eq.handled(event) {
+		return err
+	}
 	if err != nil {
-		event.Done(nil, err)
+		eq.complete(event, nil, err)
+		eq.audit(event, OutcomeFailed, queueTime, processingTime, err)
 	} else {
-		event.Done("processed", nil)
+		eq.complete(event, "processed", nil)
+		eq.audit(event, OutcomeProcessed, queueTime, processingTime, nil)
 	}
+	return err
+}
+
+// handled stops tracking event as in flight, returning false if Handover has
+// completed it instead
+func (eq *EventQueue) handled(event IEvent) bool {
+	return eq.inflight == nil || eq.inflight.remove(event)
 }
 
-// drainQueue processes all remaining events in the queue
+// drainQueue processes remaining events in the queue until the drain deadline
 func (eq *EventQueue) drainQueue() {
+	drainCtx := eq.drainCtx
+	if drainCtx == nil {
+		drainCtx = context.Background()
+	}
+
 	for {
-		select {
-		case event := <-eq.events:
-			eq.handleEvent(event)
-		default:
+		if drainCtx.Err() != nil {
+			eq.abandonQueue()
 			return
 		}
+
+		if eq.pool != nil && !eq.pool.acquire(drainCtx) {
+			eq.abandonQueue()
+			return
+		}
+
+		event, ok := eq.events.poll()
+		if !ok {
+			if eq.pool != nil {
+				eq.pool.release()
+			}
+			return
+		}
+		eq.observeDepth()
+		eq.dispatch(event)
+	}
+}
+
+// abandonQueue completes all queued events with ErrQueueShutdown
+func (eq *EventQueue) abandonQueue() {
+	for {
+		event, ok := eq.events.poll()
+		if !ok {
+			return
+		}
+		eq.onRemove(event)
+		eq.abandon(event)
+		eq.drainMu.Lock()
+		eq.drained.Abandoned++
+		eq.drainMu.Unlock()
 	}
 }
+
+// abandon completes an event with ErrQueueShutdown
+func (eq *EventQueue) abandon(event IEvent) {
+	eq.onLoss(event, LossAbandoned)
+	eq.complete(event, nil, ErrQueueShutdown)
+	eq.audit(event, OutcomeAbandoned, time.Since(event.GetTimestamp()), 0, ErrQueueShutdown)
+}
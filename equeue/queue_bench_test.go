@@ -0,0 +1,117 @@
+package equeue
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// benchEvent is a minimal event so benchmarks measure the queue, not event allocation
+type benchEvent struct {
+	Event
+}
+
+func (e *benchEvent) Done(result interface{}, err error) {}
+
+var benchBackends = []QueueBackend{ChannelBackend, RingBackend}
+
+// BenchmarkBuffer_Contended measures raw buffer throughput with many producers and one consumer
+func BenchmarkBuffer_Contended(b *testing.B) {
+	for _, backend := range benchBackends {
+		for _, producers := range []int{1, 16, 64} {
+			b.Run(fmt.Sprintf("%s/producers=%d", backend, producers), func(b *testing.B) {
+				benchmarkBuffer(b, newEventBuffer(backend, 4096), producers)
+			})
+		}
+	}
+}
+
+func benchmarkBuffer(b *testing.B, buf eventBuffer, producers int) {
+	event := &benchEvent{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for i := 0; i < b.N; i++ {
+			if _, ok := buf.take(ctx); !ok {
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		n := b.N / producers
+		if p < b.N%producers {
+			n++
+		}
+
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				for !buf.offer(event) {
+					runtime.Gosched()
+				}
+			}
+		}(n)
+	}
+
+	wg.Wait()
+	<-consumed
+}
+
+// BenchmarkEventQueue_Contended measures end-to-end Enqueue throughput with a no-op handler
+func BenchmarkEventQueue_Contended(b *testing.B) {
+	for _, backend := range benchBackends {
+		for _, producers := range []int{16, 64} {
+			b.Run(fmt.Sprintf("%s/producers=%d", backend, producers), func(b *testing.B) {
+				var handled sync.WaitGroup
+				handled.Add(b.N)
+
+				eq := NewEventQueue(EventQueueConfig{BufferSize: 4096, Backend: backend})
+				eq.RegisterHandler("bench", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+					handled.Done()
+					return nil
+				}))
+				if err := eq.Start(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+				defer eq.Stop()
+
+				event := &benchEvent{Event: Event{eventType: "bench", eventCtx: NewEventContext(nil)}}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				var wg sync.WaitGroup
+				for p := 0; p < producers; p++ {
+					n := b.N / producers
+					if p < b.N%producers {
+						n++
+					}
+
+					wg.Add(1)
+					go func(n int) {
+						defer wg.Done()
+						for i := 0; i < n; i++ {
+							for eq.Enqueue(event) != nil {
+								runtime.Gosched()
+							}
+						}
+					}(n)
+				}
+
+				wg.Wait()
+				handled.Wait()
+			})
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package equeue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestEventQueue_Drain tests every event queued or in flight is reported once
+func TestEventQueue_Drain(t *testing.T) {
+	const events = 5
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name    string
+		config  EventQueueConfig
+		options []HandlerOption
+		// handle handles the n-th event (from 1); release is closed when the test ends
+		handle    func(ctx context.Context, n int32, release <-chan struct{}) error
+		running   int32 // Handlers running when the drain starts
+		timeout   time.Duration
+		want      DrainReport
+		wantError error
+	}{
+		{
+			name: "all processed",
+			handle: func(ctx context.Context, n int32, release <-chan struct{}) error {
+				return nil
+			},
+			timeout: time.Second,
+			want:    DrainReport{Processed: events},
+		},
+		{
+			name: "failures",
+			handle: func(ctx context.Context, n int32, release <-chan struct{}) error {
+				if n%2 == 0 {
+					return errRejected
+				}
+				return nil
+			},
+			timeout: time.Second,
+			want:    DrainReport{Processed: 3, Failed: 2},
+		},
+		{
+			name: "aborted handler",
+			handle: func(ctx context.Context, n int32, release <-chan struct{}) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			running:   1,
+			timeout:   20 * time.Millisecond,
+			want:      DrainReport{Failed: 1, Abandoned: 4},
+			wantError: context.DeadlineExceeded,
+		},
+		{
+			name: "stuck handler",
+			handle: func(ctx context.Context, n int32, release <-chan struct{}) error {
+				<-release
+				return nil
+			},
+			running:   1,
+			timeout:   20 * time.Millisecond,
+			want:      DrainReport{Abandoned: 5},
+			wantError: context.DeadlineExceeded,
+		},
+		{
+			name:    "concurrent handlers",
+			options: []HandlerOption{WithConcurrency(2)},
+			handle: func(ctx context.Context, n int32, release <-chan struct{}) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			running: 2,
+			timeout: 20 * time.Millisecond,
+			// The processing loop holds a third event waiting for a slot, handled
+			// with an aborted context once one is free
+			want:      DrainReport{Failed: 3, Abandoned: 2},
+			wantError: context.DeadlineExceeded,
+		},
+		{
+			name:   "worker pool",
+			config: EventQueueConfig{ProcessingMode: Parallel, Workers: 2},
+			handle: func(ctx context.Context, n int32, release <-chan struct{}) error {
+				if n == 1 {
+					<-release
+					return nil
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			running:   2,
+			timeout:   20 * time.Millisecond,
+			want:      DrainReport{Failed: 1, Abandoned: 4},
+			wantError: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+
+			var started atomic.Int32
+			eq := NewEventQueue(tt.config)
+			eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+				return tt.handle(ctx, started.Add(1), release)
+			}), tt.options...)
+			if err := eq.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			queued := make([]*Event, events)
+			for i := range queued {
+				queued[i] = NewEvent("cdr", context.Background())
+				if err := eq.Enqueue(queued[i]); err != nil {
+					t.Fatalf("Enqueue() error = %v", err)
+				}
+			}
+			if tt.running > 0 {
+				waitFor(t, "handlers to start", func() bool { return started.Load() == tt.running })
+			}
+
+			report, err := eq.StopWithTimeout(tt.timeout)
+			if !errors.Is(err, tt.wantError) || (tt.wantError == nil && err != nil) {
+				t.Errorf("StopWithTimeout() error = %v, want %v", err, tt.wantError)
+			}
+			report.Duration = 0
+			if report != tt.want {
+				t.Errorf("StopWithTimeout() = %+v, want %+v", report, tt.want)
+			}
+			if total := report.Processed + report.Failed + report.Abandoned; total != events {
+				t.Errorf("Expected %d events reported, got %d", events, total)
+			}
+		})
+	}
+}
+
+// TestEventQueue_DrainAbandoned tests events abandoned at the deadline are completed
+// with ErrQueueShutdown and aborted handlers see ErrShuttingDown
+func TestEventQueue_DrainAbandoned(t *testing.T) {
+	eq := NewEventQueue(EventQueueConfig{})
+	started := make(chan struct{}, 1)
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	eq.Start(context.Background())
+
+	first := NewEvent("cdr", context.Background())
+	second := NewEvent("cdr", context.Background())
+	eq.Enqueue(first)
+	eq.Enqueue(second)
+	<-started
+
+	if _, err := eq.StopWithTimeout(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("StopWithTimeout() error = %v", err)
+	}
+	if _, err := first.Wait(); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected the running event to fail with ErrShuttingDown, got %v", err)
+	}
+	if _, err := second.Wait(); !errors.Is(err, ErrQueueShutdown) {
+		t.Errorf("Expected the queued event to fail with ErrQueueShutdown, got %v", err)
+	}
+
+	if err := eq.Enqueue(NewEvent("cdr", context.Background())); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("Expected ErrQueueStopped after stopping, got %v", err)
+	}
+	if _, err := eq.Drain(context.Background()); err == nil {
+		t.Error("Expected an error draining a stopped queue")
+	}
+}
@@ -0,0 +1,479 @@
+package equeue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInvalidPayload is wrapped by the *PayloadError Enqueue returns for events whose
+// payload fails the schema registered for their type
+var ErrInvalidPayload = errors.New("invalid event payload")
+
+// PayloadEvent is implemented by events carrying a payload (see WithPayload)
+type PayloadEvent interface {
+	GetPayload() interface{}
+}
+
+// WithPayload attaches a payload to the event, validated at enqueue time against
+// the schema registered for the event type, if any
+func WithPayload(payload interface{}) EventOption {
+	return func(e *Event) {
+		e.payload = payload
+	}
+}
+
+// GetPayload returns the event payload, or nil if none was set
+func (e *Event) GetPayload() interface{} {
+	return e.payload
+}
+
+// SchemaViolation is one way a payload fails its schema
+type SchemaViolation struct {
+	Path    string // JSON path of the offending value, "$" for the payload itself
+	Message string
+}
+
+// PayloadError reports why an event's payload was rejected
+type PayloadError struct {
+	EventID    uint64
+	EventType  string
+	Violations []SchemaViolation
+}
+
+func (e *PayloadError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Path+": "+v.Message)
+	}
+	return fmt.Sprintf("invalid payload for event type %s: %s", e.EventType, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns ErrInvalidPayload
+func (e *PayloadError) Unwrap() error {
+	return ErrInvalidPayload
+}
+
+// PayloadSchema validates event payloads
+type PayloadSchema interface {
+	// Validate returns the ways payload fails the schema, none if it is valid
+	Validate(payload interface{}) []SchemaViolation
+}
+
+// SchemaRegistry maps event types to payload schemas, see EventQueueConfig.Schemas
+// Events of types without a registered schema are not validated
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]PayloadSchema
+}
+
+// NewSchemaRegistry creates an empty schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{schemas: make(map[string]PayloadSchema)}
+}
+
+// Register sets the payload schema of eventType, replacing any previous one
+func (r *SchemaRegistry) Register(eventType string, schema PayloadSchema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[eventType] = schema
+}
+
+// RegisterJSONSchema parses schema (see JSONSchema) and registers it for eventType
+func (r *SchemaRegistry) RegisterJSONSchema(eventType string, schema []byte) error {
+	parsed, err := JSONSchema(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema for event type %s: %w", eventType, err)
+	}
+	r.Register(eventType, parsed)
+	return nil
+}
+
+// Schema returns the payload schema of eventType
+func (r *SchemaRegistry) Schema(eventType string) (PayloadSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.schemas[eventType]
+	return schema, ok
+}
+
+// Validate checks the payload of event against the schema of its type, returning
+// a *PayloadError if it fails
+func (r *SchemaRegistry) Validate(event IEvent) error {
+	schema, ok := r.Schema(event.GetType())
+	if !ok {
+		return nil
+	}
+
+	var violations []SchemaViolation
+	if payloadEvent, ok := event.(PayloadEvent); ok {
+		violations = schema.Validate(payloadEvent.GetPayload())
+	} else {
+		violations = []SchemaViolation{{Path: "$", Message: fmt.Sprintf("%T carries no payload", event)}}
+	}
+	if len(violations) > 0 {
+		return &PayloadError{EventID: event.GetID(), EventType: event.GetType(), Violations: violations}
+	}
+	return nil
+}
+
+// validatePayload validates event against the queue's schema registry, if any
+func (eq *EventQueue) validatePayload(event IEvent) error {
+	if eq.schemas == nil {
+		return nil
+	}
+	if err := eq.schemas.Validate(event); err != nil {
+		eq.onLoss(event, LossInvalid)
+		return err
+	}
+	return nil
+}
+
+// goTypeSchema accepts payloads of one Go type
+type goTypeSchema[T any] struct{}
+
+// GoTypeSchema accepts payloads of type T or non-nil *T, and JSON documents
+// ([]byte or json.RawMessage) decoding into T without unknown fields
+// Payloads implementing Validate() error (on T or *T) must also pass it
+func GoTypeSchema[T any]() PayloadSchema {
+	return goTypeSchema[T]{}
+}
+
+func (goTypeSchema[T]) Validate(payload interface{}) []SchemaViolation {
+	var value *T
+	switch p := payload.(type) {
+	case T:
+		value = &p
+	case *T:
+		value = p
+	case json.RawMessage:
+		value = new(T)
+		if err := decodeStrict(p, value); err != nil {
+			return []SchemaViolation{{Path: "$", Message: err.Error()}}
+		}
+	case []byte:
+		value = new(T)
+		if err := decodeStrict(p, value); err != nil {
+			return []SchemaViolation{{Path: "$", Message: err.Error()}}
+		}
+	}
+	if value == nil {
+		return []SchemaViolation{{Path: "$", Message: fmt.Sprintf("expected %s, got %T", reflect.TypeFor[T](), payload)}}
+	}
+
+	var validator interface{ Validate() error }
+	if v, ok := any(*value).(interface{ Validate() error }); ok {
+		validator = v
+	} else if v, ok := any(value).(interface{ Validate() error }); ok {
+		validator = v
+	}
+	if validator != nil {
+		if err := validator.Validate(); err != nil {
+			return []SchemaViolation{{Path: "$", Message: err.Error()}}
+		}
+	}
+	return nil
+}
+
+// decodeStrict decodes a JSON document into v, rejecting unknown fields
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// jsonSchema is a compiled JSON schema
+type jsonSchema struct {
+	Type                 []string               // Allowed JSON types (empty = any)
+	Properties           map[string]*jsonSchema // Object property schemas
+	Required             []string
+	AdditionalProperties bool // Unlisted object properties are allowed
+	Items                *jsonSchema
+	Enum                 []interface{}
+	Minimum, Maximum     *float64
+	MinLength, MaxLength *int
+	MinItems, MaxItems   *int
+	Pattern              *regexp.Regexp
+}
+
+// JSONSchema compiles a JSON schema document for payload validation
+// The supported subset covers typical event payloads: type (a name or a list),
+// properties, required, additionalProperties (bool), items, enum, minimum,
+// maximum, minLength, maxLength, minItems, maxItems and pattern
+// Payloads are validated as JSON: []byte and json.RawMessage documents are parsed,
+// other values (structs, maps) are validated as they marshal
+func JSONSchema(schema []byte) (PayloadSchema, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	return compileJSONSchema(doc, "$")
+}
+
+// compileJSONSchema compiles the schema object doc found at path
+func compileJSONSchema(doc map[string]interface{}, path string) (*jsonSchema, error) {
+	s := &jsonSchema{AdditionalProperties: true}
+	for key, value := range doc {
+		var err error
+		switch key {
+		case "type":
+			s.Type, err = schemaTypes(value)
+		case "properties":
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s.properties: expected an object", path)
+			}
+			s.Properties = make(map[string]*jsonSchema, len(props))
+			for name, prop := range props {
+				propDoc, ok := prop.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s.properties.%s: expected an object", path, name)
+				}
+				if s.Properties[name], err = compileJSONSchema(propDoc, path+"."+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.Required, err = schemaStrings(value)
+		case "additionalProperties":
+			allowed, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s.additionalProperties: only true or false is supported", path)
+			}
+			s.AdditionalProperties = allowed
+		case "items":
+			itemsDoc, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s.items: expected an object", path)
+			}
+			s.Items, err = compileJSONSchema(itemsDoc, path+"[]")
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s.enum: expected an array", path)
+			}
+			s.Enum = values
+		case "minimum":
+			s.Minimum, err = schemaNumber(value)
+		case "maximum":
+			s.Maximum, err = schemaNumber(value)
+		case "minLength":
+			s.MinLength, err = schemaCount(value)
+		case "maxLength":
+			s.MaxLength, err = schemaCount(value)
+		case "minItems":
+			s.MinItems, err = schemaCount(value)
+		case "maxItems":
+			s.MaxItems, err = schemaCount(value)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s.pattern: expected a string", path)
+			}
+			s.Pattern, err = regexp.Compile(pattern)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", path, key, err)
+		}
+	}
+	return s, nil
+}
+
+// schemaTypes parses the type keyword
+func schemaTypes(value interface{}) ([]string, error) {
+	types, err := schemaStrings(value)
+	if s, ok := value.(string); ok {
+		types, err = []string{s}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		switch t {
+		case "object", "array", "string", "number", "integer", "boolean", "null":
+		default:
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return types, nil
+}
+
+// schemaStrings parses an array of strings
+func schemaStrings(value interface{}) ([]string, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an array of strings")
+	}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an array of strings")
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// schemaNumber parses a numeric keyword
+func schemaNumber(value interface{}) (*float64, error) {
+	n, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("expected a number")
+	}
+	return &n, nil
+}
+
+// schemaCount parses a non-negative integer keyword
+func schemaCount(value interface{}) (*int, error) {
+	n, ok := value.(float64)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return nil, fmt.Errorf("expected a non-negative integer")
+	}
+	count := int(n)
+	return &count, nil
+}
+
+func (s *jsonSchema) Validate(payload interface{}) []SchemaViolation {
+	var doc interface{}
+	var data []byte
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	default:
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return []SchemaViolation{{Path: "$", Message: fmt.Sprintf("payload is not JSON: %v", err)}}
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []SchemaViolation{{Path: "$", Message: fmt.Sprintf("payload is not JSON: %v", err)}}
+	}
+
+	var violations []SchemaViolation
+	s.validate(doc, "$", &violations)
+	return violations
+}
+
+// validate appends the ways value, found at path, fails s to violations
+func (s *jsonSchema) validate(value interface{}, path string, violations *[]SchemaViolation) {
+	fail := func(format string, args ...interface{}) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Type) > 0 && !matchesType(value, s.Type) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), jsonTypeOf(value))
+		return
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value %v is not one of %v", value, s.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("length %d is below the minimum %d", length, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("length %d exceeds the maximum %d", length, *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(v) {
+			fail("%q does not match %s", v, s.Pattern)
+		}
+
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("%v is below the minimum %v", v, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("%v exceeds the maximum %v", v, *s.Maximum)
+		}
+
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, SchemaViolation{Path: path + "." + name, Message: "required"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := s.Properties[name]; ok {
+				prop.validate(v[name], path+"."+name, violations)
+			} else if !s.AdditionalProperties {
+				*violations = append(*violations, SchemaViolation{Path: path + "." + name, Message: "unknown property"})
+			}
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("%d items, below the minimum %d", len(v), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("%d items, above the maximum %d", len(v), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	}
+}
+
+// matchesType reports whether value is one of the JSON types
+func matchesType(value interface{}, types []string) bool {
+	actual := jsonTypeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeOf returns the JSON type of a decoded value, "integer" for whole numbers
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
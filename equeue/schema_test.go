@@ -0,0 +1,252 @@
+package equeue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// subscriber is a GoTypeSchema payload with its own validation
+type subscriber struct {
+	IMSI string `json:"imsi"`
+	APN  string `json:"apn,omitempty"`
+}
+
+func (s subscriber) Validate() error {
+	if len(s.IMSI) != 15 {
+		return errors.New("imsi must have 15 digits")
+	}
+	return nil
+}
+
+// TestGoTypeSchema tests payloads are accepted as the Go type, a pointer to it or
+// a JSON document decoding into it
+func TestGoTypeSchema(t *testing.T) {
+	schema := GoTypeSchema[subscriber]()
+
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    []SchemaViolation
+	}{
+		{name: "value", payload: subscriber{IMSI: "001010000000001"}},
+		{name: "pointer", payload: &subscriber{IMSI: "001010000000001"}},
+		{name: "json", payload: []byte(`{"imsi":"001010000000001","apn":"internet"}`)},
+		{name: "raw message", payload: json.RawMessage(`{"imsi":"001010000000001"}`)},
+		{
+			name:    "wrong type",
+			payload: "001010000000001",
+			want:    []SchemaViolation{{Path: "$", Message: "expected equeue.subscriber, got string"}},
+		},
+		{
+			name:    "nil pointer",
+			payload: (*subscriber)(nil),
+			want:    []SchemaViolation{{Path: "$", Message: "expected equeue.subscriber, got *equeue.subscriber"}},
+		},
+		{
+			name:    "unknown field",
+			payload: []byte(`{"imsi":"001010000000001","msisdn":"1"}`),
+			want:    []SchemaViolation{{Path: "$", Message: `json: unknown field "msisdn"`}},
+		},
+		{
+			name:    "fails Validate",
+			payload: subscriber{IMSI: "0010"},
+			want:    []SchemaViolation{{Path: "$", Message: "imsi must have 15 digits"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schema.Validate(tt.payload); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestJSONSchema tests payloads against each supported keyword
+func TestJSONSchema(t *testing.T) {
+	schema, err := JSONSchema([]byte(`{
+		"type": "object",
+		"required": ["imsi", "rat"],
+		"additionalProperties": false,
+		"properties": {
+			"imsi": {"type": "string", "pattern": "^[0-9]+$", "minLength": 6, "maxLength": 15},
+			"rat": {"enum": ["EUTRAN", "NR"]},
+			"qci": {"type": "integer", "minimum": 1, "maximum": 9},
+			"apns": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string"}},
+			"roaming": {"type": ["boolean", "null"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    []SchemaViolation
+	}{
+		{
+			name:    "valid",
+			payload: `{"imsi":"001010000000001","rat":"NR","qci":9,"apns":["ims"],"roaming":null}`,
+		},
+		{
+			name:    "valid map",
+			payload: map[string]interface{}{"imsi": "001010", "rat": "EUTRAN", "roaming": true},
+		},
+		{
+			name:    "not an object",
+			payload: `[1]`,
+			want:    []SchemaViolation{{Path: "$", Message: "expected object, got array"}},
+		},
+		{
+			name:    "missing and unknown properties",
+			payload: `{"imsi":"001010","msisdn":"1"}`,
+			want: []SchemaViolation{
+				{Path: "$.rat", Message: "required"},
+				{Path: "$.msisdn", Message: "unknown property"},
+			},
+		},
+		{
+			name:    "string constraints",
+			payload: `{"imsi":"00101a","rat":"GERAN"}`,
+			want: []SchemaViolation{
+				{Path: "$.imsi", Message: `"00101a" does not match ^[0-9]+$`},
+				{Path: "$.rat", Message: "value GERAN is not one of [EUTRAN NR]"},
+			},
+		},
+		{
+			name:    "length",
+			payload: `{"imsi":"0010100000000012","rat":"NR"}`,
+			want:    []SchemaViolation{{Path: "$.imsi", Message: "length 16 exceeds the maximum 15"}},
+		},
+		{
+			name:    "numbers",
+			payload: `{"imsi":"001010","rat":"NR","qci":1.5}`,
+			want:    []SchemaViolation{{Path: "$.qci", Message: "expected integer, got number"}},
+		},
+		{
+			name:    "range",
+			payload: `{"imsi":"001010","rat":"NR","qci":10}`,
+			want:    []SchemaViolation{{Path: "$.qci", Message: "10 exceeds the maximum 9"}},
+		},
+		{
+			name:    "items",
+			payload: `{"imsi":"001010","rat":"NR","apns":["ims",1,"internet"],"roaming":"no"}`,
+			want: []SchemaViolation{
+				{Path: "$.apns", Message: "3 items, above the maximum 2"},
+				{Path: "$.apns[1]", Message: "expected string, got integer"},
+				{Path: "$.roaming", Message: "expected boolean or null, got string"},
+			},
+		},
+		{
+			name:    "invalid json",
+			payload: `{"imsi":`,
+			want:    []SchemaViolation{{Path: "$", Message: "payload is not JSON: unexpected end of JSON input"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := tt.payload
+			if s, ok := payload.(string); ok {
+				payload = []byte(s)
+			}
+			if got := schema.Validate(payload); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestJSONSchema_Invalid tests malformed and unsupported schemas are rejected
+func TestJSONSchema_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "not json", schema: `{`},
+		{name: "unknown type", schema: `{"type": "date"}`},
+		{name: "properties not an object", schema: `{"properties": []}`},
+		{name: "property not an object", schema: `{"properties": {"imsi": "string"}}`},
+		{name: "additionalProperties schema", schema: `{"additionalProperties": {"type": "string"}}`},
+		{name: "negative count", schema: `{"minLength": -1}`},
+		{name: "bad pattern", schema: `{"pattern": "("}`},
+		{name: "nested", schema: `{"items": {"maximum": "9"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := JSONSchema([]byte(tt.schema)); err == nil {
+				t.Error("JSONSchema() succeeded, want an error")
+			}
+		})
+	}
+}
+
+// TestEventQueue_Schemas tests Enqueue rejects events whose payload fails the
+// schema of their type
+func TestEventQueue_Schemas(t *testing.T) {
+	schemas := NewSchemaRegistry()
+	schemas.Register("ulr", GoTypeSchema[subscriber]())
+	if err := schemas.RegisterJSONSchema("cdr", []byte(`{"type": "object", "required": ["duration"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.RegisterJSONSchema("bad", []byte(`{"type": 1}`)); err == nil {
+		t.Error("Expected RegisterJSONSchema to reject an invalid schema")
+	}
+
+	tests := []struct {
+		name      string
+		event     IEvent
+		wantError string
+	}{
+		{name: "valid", event: NewEvent("ulr", context.Background(), WithPayload(subscriber{IMSI: "001010000000001"}))},
+		{name: "no schema", event: NewEvent("imei", context.Background())},
+		{
+			name:      "invalid",
+			event:     NewEvent("cdr", context.Background(), WithPayload(map[string]int{"volume": 1})),
+			wantError: "invalid payload for event type cdr: $.duration: required",
+		},
+		{
+			name:      "no payload",
+			event:     NewEvent("ulr", context.Background()),
+			wantError: "invalid payload for event type ulr: $: expected equeue.subscriber, got <nil>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eq := NewEventQueue(EventQueueConfig{ProcessingMode: Inline, Schemas: schemas})
+			handled := false
+			for _, eventType := range []string{"ulr", "cdr", "imei"} {
+				eq.RegisterHandler(eventType, EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+					handled = true
+					return nil
+				}))
+			}
+
+			err := eq.Enqueue(tt.event)
+			if tt.wantError == "" {
+				if err != nil || !handled {
+					t.Errorf("Enqueue() error = %v, handled %v", err, handled)
+				}
+				return
+			}
+
+			var payloadErr *PayloadError
+			if !errors.As(err, &payloadErr) || !errors.Is(err, ErrInvalidPayload) {
+				t.Fatalf("Enqueue() error = %v, want a *PayloadError", err)
+			}
+			if payloadErr.EventID != tt.event.GetID() || err.Error() != tt.wantError {
+				t.Errorf("Enqueue() error = %q for event %d, want %q", err, payloadErr.EventID, tt.wantError)
+			}
+			if handled {
+				t.Error("Expected the invalid event not to be handled")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package equeue
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultDiameterTxTimer is the answer timer assumed for Diameter peers that don't
+// configure one; RFC 6733 leaves Tx to the implementation and 10s is the common default
+const DefaultDiameterTxTimer = 10 * time.Second
+
+// 3GPP SBI headers (TS 29.500) carrying how long an HTTP client waits for the response
+const (
+	HeaderSbiMaxRspTime      = "3gpp-Sbi-Max-Rsp-Time"     // Milliseconds the client waits for the response
+	HeaderSbiSenderTimestamp = "3gpp-Sbi-Sender-Timestamp" // When the client sent the request
+)
+
+// sbiTimestampLayout is the 3gpp-Sbi-Sender-Timestamp format (RFC 7231 date with milliseconds)
+const sbiTimestampLayout = "Mon, 02 Jan 2006 15:04:05.000 GMT"
+
+// TransportDeadline returns when a peer that sent a request at sent and waits
+// peerTimeout for the answer gives up, less slack for writing the answer
+func TransportDeadline(sent time.Time, peerTimeout, slack time.Duration) time.Time {
+	return sent.Add(peerTimeout - slack)
+}
+
+// WithTransportDeadline sets the event deadline to TransportDeadline(sent, peerTimeout, slack)
+// Like all transport deadlines it only tightens a deadline already set, so the
+// earliest of several transport deadlines applies in any order; WithDeadline and
+// WithTimeout placed after it replace it
+func WithTransportDeadline(sent time.Time, peerTimeout, slack time.Duration) EventOption {
+	return withEarliestDeadline(TransportDeadline(sent, peerTimeout, slack))
+}
+
+// WithDiameterDeadline sets the deadline of an event for a Diameter request received
+// at received, answered within the peer's Tx timer (DefaultDiameterTxTimer if <= 0)
+func WithDiameterDeadline(received time.Time, txTimer, slack time.Duration) EventOption {
+	if txTimer <= 0 {
+		txTimer = DefaultDiameterTxTimer
+	}
+	return WithTransportDeadline(received, txTimer, slack)
+}
+
+// withEarliestDeadline sets deadline unless the event already has an earlier one
+func withEarliestDeadline(deadline time.Time) EventOption {
+	return func(e *Event) {
+		if e.deadline.IsZero() || deadline.Before(e.deadline) {
+			e.deadline = deadline
+		}
+	}
+}
+
+// NewEventFromRequest creates an event for an HTTP request, expiring when the client
+// stops waiting less slack: 3gpp-Sbi-Max-Rsp-Time after 3gpp-Sbi-Sender-Timestamp (or
+// after receipt, without a usable timestamp), or the deadline of ctx, whichever is
+// earlier. ctx defaults to the request's context. Without either the event has no
+// deadline unless options set one
+func NewEventFromRequest(eventType string, ctx context.Context, r *http.Request, slack time.Duration, options ...EventOption) *Event {
+	if ctx == nil {
+		ctx = r.Context()
+	}
+
+	var transport []EventOption
+	if deadline, ok := ctx.Deadline(); ok {
+		transport = append(transport, withEarliestDeadline(deadline.Add(-slack)))
+	}
+	if maxRspTime, ok := sbiMaxRspTime(r.Header); ok {
+		sent := time.Now()
+		if ts, err := time.Parse(sbiTimestampLayout, r.Header.Get(HeaderSbiSenderTimestamp)); err == nil && ts.Before(sent) {
+			sent = ts
+		}
+		transport = append(transport, WithTransportDeadline(sent, maxRspTime, slack))
+	}
+
+	event := NewEvent(eventType, ctx, options...)
+	for _, option := range transport {
+		option(event)
+	}
+	return event
+}
+
+// sbiMaxRspTime parses the 3gpp-Sbi-Max-Rsp-Time header
+func sbiMaxRspTime(header http.Header) (time.Duration, bool) {
+	ms, err := strconv.Atoi(header.Get(HeaderSbiMaxRspTime))
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
@@ -0,0 +1,177 @@
+package equeue
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestWithTransportDeadline tests transport deadlines combine with other deadline options
+func TestWithTransportDeadline(t *testing.T) {
+	sent := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deadline := func(d time.Duration) time.Time { return sent.Add(d) }
+
+	tests := []struct {
+		name    string
+		options []EventOption
+		want    time.Time
+	}{
+		{
+			name:    "slack",
+			options: []EventOption{WithTransportDeadline(sent, 5*time.Second, 500*time.Millisecond)},
+			want:    deadline(4500 * time.Millisecond),
+		},
+		{
+			name:    "zero slack",
+			options: []EventOption{WithTransportDeadline(sent, 5*time.Second, 0)},
+			want:    deadline(5 * time.Second),
+		},
+		{
+			name: "earliest transport deadline first",
+			options: []EventOption{
+				WithTransportDeadline(sent, 2*time.Second, 0),
+				WithTransportDeadline(sent, 5*time.Second, 0),
+			},
+			want: deadline(2 * time.Second),
+		},
+		{
+			name: "earliest transport deadline last",
+			options: []EventOption{
+				WithTransportDeadline(sent, 5*time.Second, 0),
+				WithTransportDeadline(sent, 2*time.Second, 0),
+			},
+			want: deadline(2 * time.Second),
+		},
+		{
+			name:    "tightens an earlier WithDeadline",
+			options: []EventOption{WithDeadline(deadline(time.Minute)), WithTransportDeadline(sent, 5*time.Second, 0)},
+			want:    deadline(5 * time.Second),
+		},
+		{
+			name:    "keeps an earlier, tighter WithDeadline",
+			options: []EventOption{WithDeadline(deadline(time.Second)), WithTransportDeadline(sent, 5*time.Second, 0)},
+			want:    deadline(time.Second),
+		},
+		{
+			name:    "replaced by a later WithDeadline",
+			options: []EventOption{WithTransportDeadline(sent, 5*time.Second, 0), WithDeadline(deadline(time.Minute))},
+			want:    deadline(time.Minute),
+		},
+		{
+			name:    "diameter default Tx timer",
+			options: []EventOption{WithDiameterDeadline(sent, 0, time.Second)},
+			want:    deadline(DefaultDiameterTxTimer - time.Second),
+		},
+		{
+			name:    "diameter Tx timer",
+			options: []EventOption{WithDiameterDeadline(sent, 3*time.Second, 0)},
+			want:    deadline(3 * time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := NewEvent("ulr", context.Background(), tt.options...)
+			if !event.GetDeadline().Equal(tt.want) {
+				t.Errorf("GetDeadline() = %v, want %v", event.GetDeadline(), tt.want)
+			}
+		})
+	}
+}
+
+// TestWithTransportDeadline_SentInPast tests a request the peer has already given up
+// on is expired before it is handled
+func TestWithTransportDeadline_SentInPast(t *testing.T) {
+	eq := NewTestQueue()
+	eq.RegisterHandler("ulr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		t.Error("Expected the expired request not to be handled")
+		return nil
+	}))
+
+	event := NewEvent("ulr", context.Background(), WithDiameterDeadline(time.Now().Add(-time.Minute), 0, 0))
+	if !event.IsExpired() {
+		t.Fatalf("Expected the event expired, deadline %v", event.GetDeadline())
+	}
+	eq.Enqueue(event)
+	if _, err := event.Wait(); !IsTimeout(err) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+}
+
+// TestNewEventFromRequest tests the deadline derived from SBI headers and the request context
+func TestNewEventFromRequest(t *testing.T) {
+	now := time.Now()
+	sbiTimestamp := func(at time.Time) string { return at.UTC().Format(sbiTimestampLayout) }
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		ctxTimeout time.Duration
+		slack      time.Duration
+		want       time.Duration // From now, approximately; 0 for no deadline
+	}{
+		{name: "no deadline"},
+		{
+			name:    "max response time",
+			headers: map[string]string{HeaderSbiMaxRspTime: "3000"},
+			slack:   500 * time.Millisecond,
+			want:    2500 * time.Millisecond,
+		},
+		{
+			name: "sender timestamp",
+			headers: map[string]string{
+				HeaderSbiMaxRspTime:      "3000",
+				HeaderSbiSenderTimestamp: sbiTimestamp(now.Add(-time.Second)),
+			},
+			want: 2 * time.Second,
+		},
+		{
+			name: "sender timestamp in the future",
+			headers: map[string]string{
+				HeaderSbiMaxRspTime:      "3000",
+				HeaderSbiSenderTimestamp: sbiTimestamp(now.Add(time.Minute)),
+			},
+			want: 3 * time.Second,
+		},
+		{
+			name:    "invalid max response time",
+			headers: map[string]string{HeaderSbiMaxRspTime: "soon"},
+		},
+		{
+			name:       "context deadline earlier",
+			headers:    map[string]string{HeaderSbiMaxRspTime: strconv.Itoa(60000)},
+			ctxTimeout: 2 * time.Second,
+			slack:      time.Second,
+			want:       time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/nudm-uecm/v1/imsi-001010000000001/registrations", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+
+			event := NewEventFromRequest("registration", ctx, r, tt.slack)
+			if tt.want == 0 {
+				if event.HasDeadline() {
+					t.Errorf("Expected no deadline, got %v", event.GetDeadline())
+				}
+				return
+			}
+			// Timestamps carry milliseconds only
+			if got := event.GetDeadline().Sub(now); got < tt.want-50*time.Millisecond || got > tt.want+50*time.Millisecond {
+				t.Errorf("Deadline in %v, want about %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package equeue
+
+import "sync/atomic"
+
+// watermarks tracks queue saturation with hysteresis
+// The queue becomes saturated when depth reaches high and recovers only once
+// depth falls to low, so callbacks don't flap around a single threshold
+type watermarks struct {
+	high      int
+	low       int
+	onHigh    func(depth int)
+	onLow     func(depth int)
+	saturated atomic.Bool
+}
+
+// newWatermarks creates watermark tracking, or nil when high is not set
+func newWatermarks(config EventQueueConfig) *watermarks {
+	if config.HighWatermark <= 0 {
+		return nil
+	}
+
+	low := config.LowWatermark
+	if low <= 0 || low >= config.HighWatermark {
+		low = config.HighWatermark / 2
+	}
+
+	return &watermarks{
+		high:   config.HighWatermark,
+		low:    low,
+		onHigh: config.OnHighWatermark,
+		onLow:  config.OnLowWatermark,
+	}
+}
+
+// observe updates saturation for the current depth, firing a callback on transitions
+func (w *watermarks) observe(depth int) {
+	if depth >= w.high {
+		if w.saturated.CompareAndSwap(false, true) && w.onHigh != nil {
+			w.onHigh(depth)
+		}
+		return
+	}
+
+	if depth <= w.low {
+		if w.saturated.CompareAndSwap(true, false) && w.onLow != nil {
+			w.onLow(depth)
+		}
+	}
+}
+
+// IsSaturated reports whether queue depth has crossed the high watermark and not
+// yet fallen back to the low watermark
+// Producers (e.g., a Diameter listener) can reject new work with DIAMETER_TOO_BUSY
+// while saturated instead of waiting for Enqueue to fail with ErrQueueFull
+func (eq *EventQueue) IsSaturated() bool {
+	if eq.watermarks == nil {
+		return false
+	}
+	return eq.watermarks.saturated.Load()
+}
+
+// observeDepth feeds the current queue depth to the watermarks
+func (eq *EventQueue) observeDepth() {
+	if eq.watermarks != nil {
+		eq.watermarks.observe(eq.events.size())
+	}
+}
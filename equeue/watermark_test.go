@@ -0,0 +1,111 @@
+package equeue
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestWatermarks tests saturation transitions with hysteresis
+func TestWatermarks(t *testing.T) {
+	tests := []struct {
+		name          string
+		high, low     int
+		depths        []int
+		want          []string // Callbacks fired, in order
+		wantSaturated bool
+	}{
+		{
+			name:          "rises and recovers",
+			high:          4,
+			low:           1,
+			depths:        []int{1, 3, 4, 5, 3, 2, 1, 0, 4},
+			want:          []string{"high@4", "low@1", "high@4"},
+			wantSaturated: true,
+		},
+		{
+			name:          "no flapping between watermarks",
+			high:          4,
+			low:           2,
+			depths:        []int{4, 3, 4, 3, 3, 4},
+			want:          []string{"high@4"},
+			wantSaturated: true,
+		},
+		{
+			name:   "default low watermark",
+			high:   10,
+			depths: []int{10, 6, 5},
+			want:   []string{"high@10", "low@5"},
+		},
+		{
+			name:   "low watermark above high",
+			high:   10,
+			low:    12,
+			depths: []int{11, 6, 5},
+			want:   []string{"high@11", "low@5"},
+		},
+		{
+			name:   "below high",
+			high:   10,
+			depths: []int{9, 0, 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fired []string
+			w := newWatermarks(EventQueueConfig{
+				HighWatermark:   tt.high,
+				LowWatermark:    tt.low,
+				OnHighWatermark: func(depth int) { fired = append(fired, fmt.Sprintf("high@%d", depth)) },
+				OnLowWatermark:  func(depth int) { fired = append(fired, fmt.Sprintf("low@%d", depth)) },
+			})
+			for _, depth := range tt.depths {
+				w.observe(depth)
+			}
+
+			if !reflect.DeepEqual(fired, tt.want) {
+				t.Errorf("Callbacks = %v, want %v", fired, tt.want)
+			}
+			if got := w.saturated.Load(); got != tt.wantSaturated {
+				t.Errorf("Saturated = %v, want %v", got, tt.wantSaturated)
+			}
+		})
+	}
+
+	if newWatermarks(EventQueueConfig{}) != nil {
+		t.Error("Expected no watermarks without a high watermark")
+	}
+}
+
+// TestEventQueue_IsSaturated tests saturation follows queue depth
+func TestEventQueue_IsSaturated(t *testing.T) {
+	release := make(chan struct{})
+	eq := NewEventQueue(EventQueueConfig{BufferSize: 10, HighWatermark: 3, LowWatermark: 1})
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		<-release
+		return nil
+	}))
+	eq.Start(context.Background())
+	defer eq.Stop()
+
+	// The first event is taken by the blocked handler, the next three stay queued
+	eq.Enqueue(NewEvent("cdr", context.Background()))
+	waitFor(t, "the first event to be taken", func() bool { return eq.GetQueueSize() == 0 })
+	for i := 0; i < 3; i++ {
+		if eq.IsSaturated() {
+			t.Fatalf("Saturated at depth %d", eq.GetQueueSize())
+		}
+		eq.Enqueue(NewEvent("cdr", context.Background()))
+	}
+	if !eq.IsSaturated() {
+		t.Fatal("Expected the queue saturated at the high watermark")
+	}
+
+	close(release)
+	waitFor(t, "recovery", func() bool { return !eq.IsSaturated() })
+	if depth := eq.GetQueueSize(); depth > 1 {
+		t.Errorf("Recovered above the low watermark, at depth %d", depth)
+	}
+}
@@ -0,0 +1,201 @@
+package equeue
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerAutotune sizes the Parallel mode worker pool from observed load
+// Every Interval the pool is resized to the concurrency Little's law requires,
+// arrival rate × mean handling time × Headroom, plus enough workers to clear the
+// current backlog within one interval, bounded by MinWorkers and MaxWorkers
+// Bounds default to multiples of GOMAXPROCS, re-read at each evaluation so a quota
+// change (e.g., a container CPU limit applied at runtime) moves them too
+type WorkerAutotune struct {
+	// MinWorkers is the smallest pool size (default: GOMAXPROCS)
+	MinWorkers int
+
+	// MaxWorkers is the largest pool size (default: 8 × GOMAXPROCS)
+	MaxWorkers int
+
+	// Interval between evaluations (default: 10s)
+	Interval time.Duration
+
+	// Headroom scales the Little's law target to absorb bursts (default: 1.25)
+	Headroom float64
+
+	// OnResize is called when an evaluation changes the pool size
+	OnResize func(from, to int)
+}
+
+// withDefaults returns the autotune config with defaults applied for procs CPUs
+func (a WorkerAutotune) withDefaults(procs int) WorkerAutotune {
+	if a.MinWorkers <= 0 {
+		a.MinWorkers = procs
+	}
+	if a.MaxWorkers <= 0 {
+		a.MaxWorkers = 8 * procs
+	}
+	if a.MaxWorkers < a.MinWorkers {
+		a.MaxWorkers = a.MinWorkers
+	}
+	if a.Interval <= 0 {
+		a.Interval = 10 * time.Second
+	}
+	if a.Headroom <= 0 {
+		a.Headroom = 1.25
+	}
+	return a
+}
+
+// workerPool bounds the number of events handled at once in Parallel mode
+// The processing loop acquires a slot before taking an event, so events wait in the
+// queue (and count towards its depth) rather than on the loop while the pool is busy
+type workerPool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+
+	// Load observed since the last evaluation
+	arrivals  atomic.Uint64
+	completed atomic.Uint64
+	busyNanos atomic.Int64
+}
+
+// newWorkerPool creates the Parallel mode pool, or nil in other modes
+func newWorkerPool(config EventQueueConfig) *workerPool {
+	if config.ProcessingMode != Parallel {
+		return nil
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if config.WorkerAutotune != nil {
+		tune := config.WorkerAutotune.withDefaults(runtime.GOMAXPROCS(0))
+		workers = min(max(workers, tune.MinWorkers), tune.MaxWorkers)
+	}
+
+	p := &workerPool{limit: workers}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// acquire waits for a free worker slot, returning false once ctx is done
+func (p *workerPool) acquire(ctx context.Context) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active < p.limit {
+		p.active++
+		return true
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		p.mu.Lock()
+		p.cond.Broadcast()
+		p.mu.Unlock()
+	})
+	defer stop()
+	for p.active >= p.limit {
+		if ctx.Err() != nil {
+			return false
+		}
+		p.cond.Wait()
+	}
+	p.active++
+	return true
+}
+
+// release frees a worker slot
+func (p *workerPool) release() {
+	p.mu.Lock()
+	p.active--
+	p.cond.Signal()
+	p.mu.Unlock()
+}
+
+// observe records the handling time of one event
+func (p *workerPool) observe(d time.Duration) {
+	p.completed.Add(1)
+	p.busyNanos.Add(int64(d))
+}
+
+// size returns the current pool size
+func (p *workerPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.limit
+}
+
+// resize sets the pool size, waking the loop if slots became free
+// Shrinking lets running handlers finish; no new event starts until active < n
+func (p *workerPool) resize(n int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := p.limit
+	p.limit = n
+	if n > old {
+		p.cond.Broadcast()
+	}
+	return old
+}
+
+// target returns the pool size for the load observed over elapsed with depth events
+// queued, and resets the observations
+func (p *workerPool) target(tune WorkerAutotune, elapsed time.Duration, depth int) int {
+	arrivals := p.arrivals.Swap(0)
+	completed := p.completed.Swap(0)
+	busy := time.Duration(p.busyNanos.Swap(0))
+
+	if arrivals == 0 && depth == 0 {
+		return tune.MinWorkers
+	}
+	if completed == 0 || elapsed <= 0 {
+		return p.size() // No handling time observed yet
+	}
+
+	meanHandling := float64(busy) / float64(completed)
+	arrivalRate := float64(arrivals) / float64(elapsed)
+	workers := arrivalRate * meanHandling * tune.Headroom
+	workers += float64(depth) * meanHandling / float64(elapsed) // Clear the backlog within an interval
+
+	return min(max(int(math.Ceil(workers)), tune.MinWorkers), tune.MaxWorkers)
+}
+
+// autotuneWorkers periodically resizes the worker pool until the queue stops
+func (eq *EventQueue) autotuneWorkers(config WorkerAutotune) {
+	defer eq.wg.Done()
+
+	ticker := time.NewTicker(config.withDefaults(runtime.GOMAXPROCS(0)).Interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-eq.ctx.Done():
+			return
+		case now := <-ticker.C:
+			tune := config.withDefaults(runtime.GOMAXPROCS(0))
+			n := eq.pool.target(tune, now.Sub(last), eq.events.size())
+			last = now
+			if old := eq.pool.resize(n); old != n && tune.OnResize != nil {
+				tune.OnResize(old, n)
+			}
+		}
+	}
+}
+
+// Workers returns the number of events the queue may handle at once in Parallel
+// mode (the current worker pool size), or 0 in other modes
+func (eq *EventQueue) Workers() int {
+	if eq.pool == nil {
+		return 0
+	}
+	return eq.pool.size()
+}
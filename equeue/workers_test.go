@@ -0,0 +1,214 @@
+package equeue
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWorkerAutotune_Defaults tests autotune defaults scale with the CPU count
+func TestWorkerAutotune_Defaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		config WorkerAutotune
+		want   WorkerAutotune
+	}{
+		{
+			name:   "defaults",
+			config: WorkerAutotune{},
+			want:   WorkerAutotune{MinWorkers: 4, MaxWorkers: 32, Interval: 10 * time.Second, Headroom: 1.25},
+		},
+		{
+			name:   "explicit",
+			config: WorkerAutotune{MinWorkers: 2, MaxWorkers: 6, Interval: time.Second, Headroom: 2},
+			want:   WorkerAutotune{MinWorkers: 2, MaxWorkers: 6, Interval: time.Second, Headroom: 2},
+		},
+		{
+			name:   "max below min",
+			config: WorkerAutotune{MinWorkers: 64},
+			want:   WorkerAutotune{MinWorkers: 64, MaxWorkers: 64, Interval: 10 * time.Second, Headroom: 1.25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.withDefaults(4); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withDefaults(4) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWorkerPool_Target tests the pool size follows Little's law plus the backlog
+func TestWorkerPool_Target(t *testing.T) {
+	tune := WorkerAutotune{MinWorkers: 2, MaxWorkers: 32, Headroom: 1.25}
+
+	tests := []struct {
+		name      string
+		arrivals  uint64
+		completed uint64
+		busy      time.Duration
+		depth     int
+		want      int
+	}{
+		{name: "idle", want: 2},
+		{name: "no handling observed", arrivals: 10, want: 5},
+		{name: "steady", arrivals: 1000, completed: 1000, busy: 10 * time.Second, want: 13},              // 1000/s × 10ms × 1.25
+		{name: "backlog", arrivals: 1000, completed: 1000, busy: 10 * time.Second, depth: 500, want: 18}, // + 500 × 10ms / 1s
+		{name: "backlog only", completed: 10, busy: 100 * time.Millisecond, depth: 300, want: 3},
+		{name: "light load", arrivals: 10, completed: 10, busy: 10 * time.Millisecond, want: 2},
+		{name: "overload", arrivals: 1000, completed: 1000, busy: 100 * time.Second, want: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newWorkerPool(EventQueueConfig{ProcessingMode: Parallel, Workers: 5})
+			pool.arrivals.Store(tt.arrivals)
+			pool.completed.Store(tt.completed)
+			pool.busyNanos.Store(int64(tt.busy))
+
+			if got := pool.target(tune, time.Second, tt.depth); got != tt.want {
+				t.Errorf("target() = %d, want %d", got, tt.want)
+			}
+			// Observations are reset for the next interval
+			if got := pool.target(tune, time.Second, 0); got != tune.MinWorkers {
+				t.Errorf("target() after reset = %d, want %d", got, tune.MinWorkers)
+			}
+		})
+	}
+}
+
+// TestEventQueue_Workers tests the initial pool size in each mode
+func TestEventQueue_Workers(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+
+	tests := []struct {
+		name   string
+		config EventQueueConfig
+		want   int
+	}{
+		{name: "sequential", config: EventQueueConfig{ProcessingMode: Sequential, Workers: 4}, want: 0},
+		{name: "parallel", config: EventQueueConfig{ProcessingMode: Parallel, Workers: 3}, want: 3},
+		{name: "parallel default", config: EventQueueConfig{ProcessingMode: Parallel}, want: procs},
+		{
+			name:   "autotune raises to min",
+			config: EventQueueConfig{ProcessingMode: Parallel, Workers: 2, WorkerAutotune: &WorkerAutotune{MinWorkers: 4, MaxWorkers: 6}},
+			want:   4,
+		},
+		{
+			name:   "autotune caps at max",
+			config: EventQueueConfig{ProcessingMode: Parallel, Workers: 10, WorkerAutotune: &WorkerAutotune{MinWorkers: 4, MaxWorkers: 6}},
+			want:   6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewEventQueue(tt.config).Workers(); got != tt.want {
+				t.Errorf("Workers() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWorkerPool_Acquire tests acquire waits for a slot, released or added by resize
+func TestWorkerPool_Acquire(t *testing.T) {
+	pool := newWorkerPool(EventQueueConfig{ProcessingMode: Parallel, Workers: 1})
+	if !pool.acquire(context.Background()) {
+		t.Fatal("Expected a free slot")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if pool.acquire(ctx) {
+		t.Fatal("Expected acquire on a full pool to fail when ctx is done")
+	}
+
+	acquired := make(chan bool)
+	go func() { acquired <- pool.acquire(context.Background()) }()
+	if old := pool.resize(2); old != 1 {
+		t.Errorf("resize() = %d, want 1", old)
+	}
+	if !<-acquired {
+		t.Error("Expected the slot added by resize to be acquired")
+	}
+
+	// Shrinking holds new events until enough running ones finish
+	pool.resize(1)
+	go func() { acquired <- pool.acquire(context.Background()) }()
+	pool.release()
+	select {
+	case <-acquired:
+		t.Fatal("Expected acquire to wait while active >= limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+	pool.release()
+	if !<-acquired {
+		t.Error("Expected acquire to succeed once a slot is free")
+	}
+}
+
+// TestEventQueue_Parallel tests Parallel mode handles at most Workers events at once
+// and autotune shrinks an idle pool
+func TestEventQueue_Parallel(t *testing.T) {
+	resized := make(chan [2]int, 1)
+	eq := NewEventQueue(EventQueueConfig{
+		ProcessingMode: Parallel,
+		Workers:        3,
+		WorkerAutotune: &WorkerAutotune{
+			MinWorkers: 1,
+			MaxWorkers: 3,
+			Interval:   20 * time.Millisecond,
+			OnResize: func(from, to int) {
+				select {
+				case resized <- [2]int{from, to}:
+				default:
+				}
+			},
+		},
+	})
+
+	var running, peak atomic.Int32
+	eq.RegisterHandler("cdr", EventHandlerFunc(func(ctx context.Context, event IEvent) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}))
+	if err := eq.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	events := make([]*Event, 12)
+	for i := range events {
+		events[i] = NewEvent("cdr", context.Background())
+		eq.Enqueue(events[i])
+	}
+	for _, event := range events {
+		event.Wait()
+	}
+	if got := peak.Load(); got > 3 || got < 2 {
+		t.Errorf("Expected at most 3 events handled at once, got %d", got)
+	}
+
+	select {
+	case r := <-resized:
+		if r[1] >= r[0] {
+			t.Errorf("Expected the idle pool to shrink, got %d -> %d", r[0], r[1])
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected autotune to resize the idle pool")
+	}
+	if err := eq.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,302 @@
+// Package anomaly flags sudden deviations in exported counters and KPIs
+//
+// A Detector keeps an exponentially weighted baseline (mean and variance) per series
+// across export cycles, optionally one baseline per seasonal slot, and raises an Alert
+// when a new value's z-score exceeds the threshold. It implements export.Exporter so it
+// can be registered with the export scheduler alongside the real exporters.
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hsdfat/telco/stats/export"
+	"github.com/hsdfat/telco/stats/kpi"
+)
+
+// Direction selects which deviations raise alerts
+type Direction int
+
+const (
+	Both Direction = iota // Drops and spikes
+	Drop                  // Values below the baseline only
+	Rise                  // Values above the baseline only
+)
+
+// String returns the direction name
+func (d Direction) String() string {
+	switch d {
+	case Drop:
+		return "drop"
+	case Rise:
+		return "rise"
+	default:
+		return "both"
+	}
+}
+
+// Series identifies one tracked value
+// KPI is set for KPI series; otherwise CounterID and CauseCode identify the counter
+type Series struct {
+	Hostname   string `json:"hostname"`
+	SystemName string `json:"system_name"`
+	CounterID  int    `json:"counter_id,omitempty"`
+	CauseCode  int    `json:"cause_code,omitempty"`
+	KPI        string `json:"kpi,omitempty"`
+}
+
+// String returns a readable series name
+func (s Series) String() string {
+	if s.KPI != "" {
+		return fmt.Sprintf("%s/%s/%s", s.SystemName, s.Hostname, s.KPI)
+	}
+	return fmt.Sprintf("%s/%s/%s[%d]", s.SystemName, s.Hostname, export.GetCounterName(s.CounterID), s.CauseCode)
+}
+
+// Alert reports a value that deviates from its baseline
+type Alert struct {
+	Series    Series    `json:"series"`
+	Value     float64   `json:"value"`
+	Mean      float64   `json:"mean"`
+	StdDev    float64   `json:"std_dev"`
+	ZScore    float64   `json:"z_score"` // Negative for drops
+	Direction Direction `json:"direction"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Config defines configuration for Detector
+type Config struct {
+	Name string
+
+	// Alpha is the EWMA smoothing factor in (0, 1]; higher adapts faster (default: 0.1)
+	Alpha float64
+
+	// Threshold is the absolute z-score that raises an alert (default: 3)
+	Threshold float64
+
+	// WarmUp is the number of samples a baseline needs before it can alert (default: 10)
+	WarmUp int
+
+	// MinStdDev floors the standard deviation so flat series don't alert on tiny changes (default: 1e-6)
+	MinStdDev float64
+
+	// Direction selects which deviations alert (default: Both)
+	Direction Direction
+
+	// Season and Slots keep a separate baseline per slot of a repeating season,
+	// e.g. Season 24h with 24 Slots learns an hourly profile; zero disables seasonality
+	Season time.Duration
+	Slots  int
+
+	// Counters restricts counter series to these IDs; empty tracks every counter
+	Counters []int
+
+	// KPIs evaluates KPI series from each batch; nil tracks counters only
+	KPIs *kpi.Evaluator
+
+	// OnAlert receives each alert
+	OnAlert func(Alert)
+}
+
+// baseline is an EWMA mean and variance
+type baseline struct {
+	mean     float64
+	variance float64
+	samples  int
+}
+
+// update folds x into the baseline
+func (b *baseline) update(x, alpha float64) {
+	if b.samples == 0 {
+		b.mean = x
+		b.samples = 1
+		return
+	}
+	diff := x - b.mean
+	incr := alpha * diff
+	b.mean += incr
+	b.variance = (1 - alpha) * (b.variance + diff*incr)
+	b.samples++
+}
+
+// baselineKey identifies a baseline by series and seasonal slot
+type baselineKey struct {
+	series Series
+	slot   int
+}
+
+// Detector tracks baselines and raises alerts
+type Detector struct {
+	name   string
+	config Config
+
+	mu        sync.Mutex
+	baselines map[baselineKey]*baseline
+	counters  map[int]bool
+	alerts    uint64
+}
+
+// NewDetector creates a new anomaly detector
+func NewDetector(config Config) (*Detector, error) {
+	if config.Alpha == 0 {
+		config.Alpha = 0.1
+	}
+	if config.Alpha < 0 || config.Alpha > 1 {
+		return nil, fmt.Errorf("anomaly alpha must be in (0, 1], got %v", config.Alpha)
+	}
+	if config.Threshold == 0 {
+		config.Threshold = 3
+	}
+	if config.WarmUp == 0 {
+		config.WarmUp = 10
+	}
+	if config.MinStdDev == 0 {
+		config.MinStdDev = 1e-6
+	}
+	if (config.Season > 0) != (config.Slots > 0) {
+		return nil, fmt.Errorf("anomaly season and slots must be set together")
+	}
+
+	var counters map[int]bool
+	if len(config.Counters) > 0 {
+		counters = make(map[int]bool, len(config.Counters))
+		for _, id := range config.Counters {
+			counters[id] = true
+		}
+	}
+
+	return &Detector{
+		name:      config.Name,
+		config:    config,
+		baselines: make(map[baselineKey]*baseline),
+		counters:  counters,
+	}, nil
+}
+
+// Export observes one export cycle's records
+func (d *Detector) Export(ctx context.Context, records []export.MetricRecord) error {
+	d.Observe(records)
+	return nil
+}
+
+// Observe updates baselines from records and returns the alerts they raise
+func (d *Detector) Observe(records []export.MetricRecord) []Alert {
+	d.mu.Lock()
+	var alerts []Alert
+	for _, record := range records {
+		if d.counters != nil && !d.counters[record.CounterID] {
+			continue
+		}
+		series := Series{
+			Hostname:   record.Hostname,
+			SystemName: record.SystemName,
+			CounterID:  record.CounterID,
+			CauseCode:  record.CauseCode,
+		}
+		if alert, ok := d.observe(series, float64(record.Value), record.Timestamp); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if d.config.KPIs != nil {
+		for _, result := range d.config.KPIs.Evaluate(records) {
+			series := Series{Hostname: result.Hostname, SystemName: result.SystemName, KPI: result.Name}
+			if alert, ok := d.observe(series, result.Value, result.Timestamp); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	d.alerts += uint64(len(alerts))
+	d.mu.Unlock()
+
+	if d.config.OnAlert != nil {
+		for _, alert := range alerts {
+			d.config.OnAlert(alert)
+		}
+	}
+	return alerts
+}
+
+// observe scores value against its baseline, then folds it in
+func (d *Detector) observe(series Series, value float64, timestamp time.Time) (Alert, bool) {
+	key := baselineKey{series: series, slot: d.slot(timestamp)}
+	b, ok := d.baselines[key]
+	if !ok {
+		b = &baseline{}
+		d.baselines[key] = b
+	}
+
+	var alert Alert
+	alerting := false
+	if b.samples >= d.config.WarmUp {
+		stdDev := math.Max(math.Sqrt(b.variance), d.config.MinStdDev)
+		z := (value - b.mean) / stdDev
+		if math.Abs(z) >= d.config.Threshold && d.wanted(z) {
+			direction := Rise
+			if z < 0 {
+				direction = Drop
+			}
+			alert = Alert{
+				Series:    series,
+				Value:     value,
+				Mean:      b.mean,
+				StdDev:    stdDev,
+				ZScore:    z,
+				Direction: direction,
+				Timestamp: timestamp,
+			}
+			alerting = true
+		}
+	}
+
+	b.update(value, d.config.Alpha)
+	return alert, alerting
+}
+
+// wanted reports whether a deviation with z-score z matches the configured direction
+func (d *Detector) wanted(z float64) bool {
+	switch d.config.Direction {
+	case Drop:
+		return z < 0
+	case Rise:
+		return z > 0
+	default:
+		return true
+	}
+}
+
+// slot returns the seasonal slot for timestamp
+func (d *Detector) slot(timestamp time.Time) int {
+	if d.config.Slots == 0 {
+		return 0
+	}
+	offset := timestamp.UnixNano() % int64(d.config.Season)
+	return int(offset * int64(d.config.Slots) / int64(d.config.Season))
+}
+
+// AlertCount returns the total number of alerts raised
+func (d *Detector) AlertCount() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.alerts
+}
+
+// Reset discards every baseline
+func (d *Detector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.baselines = make(map[baselineKey]*baseline)
+}
+
+// Name returns the detector name
+func (d *Detector) Name() string {
+	return d.name
+}
+
+// Close is a no-op
+func (d *Detector) Close() error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hsdfat/telco/stats/export"
+	"github.com/hsdfat/telco/stats/kpi"
+)
+
+func TestDetector_SuccessRateDrop(t *testing.T) {
+	var alerts []Alert
+	d, err := NewDetector(Config{
+		Counters:  []int{export.CounterDiameterTotal},
+		KPIs:      kpi.NewStandardEvaluator(),
+		Direction: Drop,
+		OnAlert:   func(a Alert) { alerts = append(alerts, a) },
+	})
+	if err != nil {
+		t.Fatalf("NewDetector failed: %v", err)
+	}
+
+	start := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)
+	cycle := func(i int, success uint64) {
+		ts := start.Add(time.Duration(i) * 30 * time.Second)
+		d.Observe([]export.MetricRecord{
+			{CounterID: export.CounterDiameterTotal, Value: 1000 + uint64(i%3), Hostname: "eir-1", SystemName: "EIR", Timestamp: ts},
+			{CounterID: export.CounterDiameterSuccess, Value: success + uint64(i%2), Hostname: "eir-1", SystemName: "EIR", Timestamp: ts},
+		})
+	}
+
+	for i := 0; i < 20; i++ {
+		cycle(i, 990)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("Expected no alerts for a steady series, got %+v", alerts)
+	}
+
+	// Success rate falls from ~99% to 60%
+	cycle(20, 600)
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d: %+v", len(alerts), alerts)
+	}
+	alert := alerts[0]
+	if alert.Series.KPI != kpi.DiameterSuccessRate || alert.Direction != Drop || alert.ZScore > -3 {
+		t.Errorf("Unexpected alert: %+v", alert)
+	}
+	if d.AlertCount() != 1 {
+		t.Errorf("Expected alert count 1, got %d", d.AlertCount())
+	}
+}
+
+func TestDetector_WarmUpAndSeasons(t *testing.T) {
+	d, _ := NewDetector(Config{WarmUp: 3, Season: 2 * time.Minute, Slots: 2})
+
+	record := func(ts time.Time, v uint64) []export.MetricRecord {
+		return []export.MetricRecord{{CounterID: export.CounterTotalRequests, Value: v, Hostname: "h", Timestamp: ts}}
+	}
+
+	// Even minutes are busy, odd minutes quiet; each slot learns its own level
+	start := time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		v := uint64(1000)
+		if i%2 == 1 {
+			v = 10
+		}
+		if alerts := d.Observe(record(start.Add(time.Duration(i)*time.Minute), v)); len(alerts) != 0 {
+			t.Fatalf("Cycle %d: expected no alerts, got %+v", i, alerts)
+		}
+	}
+
+	// A busy-level value in a quiet slot is a spike
+	alerts := d.Observe(record(start.Add(11*time.Minute), 1000))
+	if len(alerts) != 1 || alerts[0].Direction != Rise {
+		t.Errorf("Expected one rise alert, got %+v", alerts)
+	}
+}
+
+func TestNewDetector_Invalid(t *testing.T) {
+	if _, err := NewDetector(Config{Alpha: 2}); err == nil {
+		t.Error("Expected error for alpha > 1")
+	}
+	if _, err := NewDetector(Config{Season: time.Hour}); err == nil {
+		t.Error("Expected error for season without slots")
+	}
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultClockSkewThreshold is the wall clock step between samples that counts as a skew event
+const DefaultClockSkewThreshold = time.Second
+
+// ClockSkew is the result of one ClockSkewDetector sample
+type ClockSkew struct {
+	Step    time.Duration // Wall-clock minus monotonic elapsed time since the previous sample
+	Total   time.Duration // Accumulated skew since the first sample (positive = wall clock ahead)
+	Stepped bool          // |Step| exceeded the threshold
+	Steps   uint64        // Samples so far whose step exceeded the threshold
+}
+
+// ClockSkewDetector compares monotonic elapsed time against wall-clock elapsed time
+// between samples. The monotonic clock isn't affected by NTP steps or manual clock
+// changes, so a difference means timestamps taken around the sample (and the
+// period bins derived from them) are shifted
+type ClockSkewDetector struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	origin    time.Time     // First sample, carrying the monotonic reading
+	lastWall  time.Time     // Wall clock of the previous sample
+	lastMono  time.Duration // Monotonic time of the previous sample since origin
+	sampled   bool
+	total     time.Duration
+	steps     uint64
+}
+
+// NewClockSkewDetector creates a detector flagging steps larger than threshold
+// (DefaultClockSkewThreshold if <= 0)
+func NewClockSkewDetector(threshold time.Duration) *ClockSkewDetector {
+	if threshold <= 0 {
+		threshold = DefaultClockSkewThreshold
+	}
+	return &ClockSkewDetector{threshold: threshold}
+}
+
+// Observe samples now, which must carry a monotonic clock reading (time.Now does)
+// Times without one, such as those of a FakeClock started from a fixed date, are
+// ignored and ok is false; so is the first sample, which has nothing to compare against
+func (d *ClockSkewDetector) Observe(now time.Time) (skew ClockSkew, ok bool) {
+	wall := now.Round(0) // Round(0) strips the monotonic reading
+	if wall == now {
+		return ClockSkew{}, false
+	}
+
+	d.mu.Lock()
+	if d.origin.IsZero() {
+		d.origin = now
+	}
+	mono := now.Sub(d.origin)
+	d.mu.Unlock()
+
+	return d.ObserveAt(wall, mono)
+}
+
+// ObserveAt samples a wall-clock time together with the monotonic time elapsed
+// since a fixed origin, for callers with their own monotonic source
+func (d *ClockSkewDetector) ObserveAt(wall time.Time, monotonic time.Duration) (skew ClockSkew, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.sampled {
+		d.sampled = true
+		d.lastWall, d.lastMono = wall, monotonic
+		return ClockSkew{}, false
+	}
+
+	skew.Step = wall.Round(0).Sub(d.lastWall.Round(0)) - (monotonic - d.lastMono)
+	d.lastWall, d.lastMono = wall, monotonic
+
+	d.total += skew.Step
+	if skew.Step > d.threshold || skew.Step < -d.threshold {
+		skew.Stepped = true
+		d.steps++
+	}
+	skew.Total = d.total
+	skew.Steps = d.steps
+	return skew, true
+}
+
+// Skew returns the accumulated skew and the number of steps over the threshold
+func (d *ClockSkewDetector) Skew() (total time.Duration, steps uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.total, d.steps
+}
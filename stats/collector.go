@@ -0,0 +1,1237 @@
+package stats
+
+import (
+	"container/list"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hsdfat/telco/config"
+)
+
+const (
+	// Default number of recent latency samples kept per window
+	defaultLatencySamples = 1024
+
+	// Default number of distinct recent errors kept for triage
+	defaultRecentErrors = 20
+
+	// Default number of distinct TACs tracked
+	defaultMaxTACs = 1000
+
+	// Default number of distinct tenants tracked
+	defaultMaxTenants = 100
+)
+
+// CollectorConfig configures a stats collector
+type CollectorConfig struct {
+	// ServiceName reported in every snapshot (e.g., "EIR")
+	ServiceName string
+
+	// ServiceVersion reported in every snapshot
+	ServiceVersion string
+
+	// LatencySamples is the number of recent samples used for percentiles (default: 1024)
+	LatencySamples int
+
+	// RecentErrors is the number of distinct recent errors kept (default: 20)
+	RecentErrors int
+
+	// RecentStatusChanges is the number of equipment status changes kept for audit (0 = disabled)
+	RecentStatusChanges int
+
+	// MaxTACs caps the number of distinct TACs tracked; the least recently checked TAC is evicted (default: 1000)
+	MaxTACs int
+
+	// Clock supplies timestamps (default: SystemClock)
+	Clock Clock
+
+	// SLOs are per-operation objectives tracked from RecordOperation (see SLOTarget)
+	SLOs []SLOTarget
+
+	// FailureSamples is the number of example requests captured per failure counter
+	// per period with RecordRequestSample (0 = disabled)
+	FailureSamples int
+
+	// MaxTenants caps the number of distinct tenants tracked; requests of further
+	// tenants are only counted in the overall stats (default: 100)
+	MaxTenants int
+}
+
+// Collector accumulates service statistics in memory and produces ServiceStats snapshots
+// It satisfies the export package's ServiceStatsProvider and StatsCollectorInterface
+type Collector struct {
+	mu          sync.RWMutex
+	config      CollectorConfig
+	clock       Clock
+	startTime   time.Time
+	connections ConnectionStats
+	requests    RequestStats
+	errors      ErrorStats
+	eir         *EIRStats
+	peers       map[string]PeerStats
+	sctp        *SCTPStats
+	overload    *OverloadStats
+	capacity    *CapacityStats
+
+	// Latency windows: overall, per source, and per operation
+	latency       *latencyWindow
+	sourceLatency map[string]*latencyWindow
+	opLatency     map[string]*latencyWindow
+
+	// Database latency windows and breakdowns
+	dbLatency   *latencyWindow
+	dbOpLatency map[string]*latencyWindow
+	dbTables    map[string]*dbBreakdown
+	dbQueries   map[string]*dbBreakdown
+
+	// Optional runtime sampler populating ServiceStats.Runtime
+	runtimeSampler *RuntimeSampler
+
+	// Optional source populating ServiceStats.ConfigProviders
+	configSource ConfigStatusSource
+
+	// Optional self-test populating CustomMetrics["selftest"]
+	selfTest *SelfTest
+
+	// Optional expvar and runtime/metrics harvester populating CustomMetrics["runtime"]
+	harvester *MetricsHarvester
+
+	// SLO trackers by operation
+	slos map[string]*sloTracker
+
+	// Failed request examples captured this period, by FailureSampleKey
+	failureSamples map[string][]RequestSample
+
+	// Per-tenant stats and latency, see Tenant
+	tenants map[string]*tenantStats
+
+	// Tracked TACs, most recently checked first, for eviction beyond MaxTACs
+	tacRecency *list.List
+	tacElems   map[string]*list.Element
+}
+
+// ConfigStatusSource reports configuration provider health, e.g. config.Manager
+type ConfigStatusSource interface {
+	ProviderStatus() []config.ProviderStatus
+}
+
+// NewCollector creates a new stats collector
+func NewCollector(cfg CollectorConfig) *Collector {
+	if cfg.LatencySamples <= 0 {
+		cfg.LatencySamples = defaultLatencySamples
+	}
+	if cfg.RecentErrors <= 0 {
+		cfg.RecentErrors = defaultRecentErrors
+	}
+	if cfg.MaxTACs <= 0 {
+		cfg.MaxTACs = defaultMaxTACs
+	}
+	if cfg.MaxTenants <= 0 {
+		cfg.MaxTenants = defaultMaxTenants
+	}
+	clock := clockOrSystem(cfg.Clock)
+	now := clock.Now()
+
+	slos := make(map[string]*sloTracker, len(cfg.SLOs))
+	for _, target := range cfg.SLOs {
+		slos[target.Operation] = newSLOTracker(target, now)
+	}
+
+	return &Collector{
+		config:    cfg,
+		clock:     clock,
+		startTime: now,
+		connections: ConnectionStats{
+			ByListener: make(map[string]ListenerStats),
+		},
+		requests: RequestStats{
+			BySource:    make(map[string]SourceStats),
+			ByOperation: make(map[string]OperationStats),
+		},
+		errors: ErrorStats{
+			ByType:      make(map[string]uint64),
+			ByInterface: make(map[string]uint64),
+		},
+		peers:         make(map[string]PeerStats),
+		latency:       newLatencyWindow(cfg.LatencySamples),
+		sourceLatency: make(map[string]*latencyWindow),
+		opLatency:     make(map[string]*latencyWindow),
+		dbLatency:     newLatencyWindow(cfg.LatencySamples),
+		dbOpLatency:   make(map[string]*latencyWindow),
+		dbTables:      make(map[string]*dbBreakdown),
+		dbQueries:     make(map[string]*dbBreakdown),
+		slos:          slos,
+		tenants:       make(map[string]*tenantStats),
+		tacRecency:    list.New(),
+		tacElems:      make(map[string]*list.Element),
+	}
+}
+
+// RecordRequest records a processed request for the given source (diameter, http)
+func (c *Collector) RecordRequest(source string, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordRequest(source, success)
+}
+
+// recordRequest updates request counters (caller holds the lock)
+func (c *Collector) recordRequest(source string, success bool) {
+	c.requests.Total++
+	src := c.requests.BySource[source]
+	src.Total++
+
+	if success {
+		c.requests.Success++
+		src.Success++
+	} else {
+		c.requests.Failed++
+		src.Failed++
+	}
+
+	c.requests.BySource[source] = src
+}
+
+// RecordEquipmentCheck records an EIR equipment check on the given interface
+// Call it alongside RecordRequest or EndRequest for requests that are equipment checks
+func (c *Collector) RecordEquipmentCheck(iface string, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checks := &c.eirStats().EquipmentChecks
+	checks.Total++
+	ifStats := checks.ByInterface[iface]
+	ifStats.Total++
+
+	if success {
+		checks.Success++
+		ifStats.Success++
+	} else {
+		checks.Failed++
+		ifStats.Failed++
+	}
+
+	checks.ByInterface[iface] = ifStats
+}
+
+// BeginRequest marks a request on the given source as in flight
+// Every BeginRequest must be paired with an EndRequest
+func (c *Collector) BeginRequest(source string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests.Pending++
+	if c.requests.Pending > c.requests.MaxPending {
+		c.requests.MaxPending = c.requests.Pending
+	}
+
+	src := c.requests.BySource[source]
+	src.InFlight++
+	c.requests.BySource[source] = src
+}
+
+// EndRequest completes a request started with BeginRequest and records its outcome
+// It replaces RecordRequest for requests tracked as in flight
+func (c *Collector) EndRequest(source string, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endRequest(source, success)
+}
+
+// endRequest completes an in-flight request (caller holds the lock)
+func (c *Collector) endRequest(source string, success bool) {
+	if c.requests.Pending > 0 {
+		c.requests.Pending--
+	}
+	src := c.requests.BySource[source]
+	if src.InFlight > 0 {
+		src.InFlight--
+	}
+	c.requests.BySource[source] = src
+
+	c.recordRequest(source, success)
+}
+
+// RecordBytes records the bytes sent and received for one message exchange on the given source
+// Zero values are ignored for the corresponding direction's size histogram
+func (c *Collector) RecordBytes(source string, sent, recv uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordBytes(source, sent, recv)
+}
+
+// recordBytes updates byte counters and size histograms (caller holds the lock)
+func (c *Collector) recordBytes(source string, sent, recv uint64) {
+	c.requests.BytesSent += sent
+	c.requests.BytesRecv += recv
+
+	src := c.requests.BySource[source]
+	src.BytesSent += sent
+	src.BytesRecv += recv
+
+	if sent > 0 {
+		if src.SentSizes == nil {
+			src.SentSizes = make(map[int]uint64)
+		}
+		src.SentSizes[SizeBucket(sent)]++
+	}
+	if recv > 0 {
+		if src.RecvSizes == nil {
+			src.RecvSizes = make(map[int]uint64)
+		}
+		src.RecvSizes[SizeBucket(recv)]++
+	}
+
+	c.requests.BySource[source] = src
+}
+
+// RecordResultCode records a Diameter result code or HTTP status code for the given source
+func (c *Collector) RecordResultCode(source string, code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordResultCode(source, code)
+}
+
+// recordResultCode updates the result code distribution (caller holds the lock)
+func (c *Collector) recordResultCode(source string, code int) {
+	checks := &c.eirStats().EquipmentChecks
+	ifStats := checks.ByInterface[source]
+	if ifStats.ByResultCode == nil {
+		ifStats.ByResultCode = make(map[int]uint64)
+	}
+	ifStats.ByResultCode[code]++
+	checks.ByInterface[source] = ifStats
+}
+
+// RecordLatency records the latency of a request for the given source and operation
+// Either source or operation may be empty to skip the corresponding breakdown
+func (c *Collector) RecordLatency(source, operation string, d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordLatency(source, operation, ms)
+}
+
+// recordLatency adds a latency sample in milliseconds to the windows (caller holds the lock)
+func (c *Collector) recordLatency(source, operation string, ms float64) {
+	c.latency.add(ms)
+
+	if source != "" {
+		w, ok := c.sourceLatency[source]
+		if !ok {
+			w = newLatencyWindow(c.config.LatencySamples)
+			c.sourceLatency[source] = w
+		}
+		w.add(ms)
+	}
+
+	if operation != "" {
+		w, ok := c.opLatency[operation]
+		if !ok {
+			w = newLatencyWindow(c.config.LatencySamples)
+			c.opLatency[operation] = w
+		}
+		w.add(ms)
+	}
+}
+
+// RecordOperation records the outcome and latency of one operation (e.g., "check")
+// in Requests.ByOperation and against the operation's SLO, if one is configured
+func (c *Collector) RecordOperation(operation string, success bool, d time.Duration) {
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	op := c.requests.ByOperation[operation]
+	op.Total++
+	if success {
+		op.Success++
+	} else {
+		op.Failed++
+	}
+	ms := float64(d) / float64(time.Millisecond)
+	op.AvgLatencyMs += (ms - op.AvgLatencyMs) / float64(op.Total)
+	c.requests.ByOperation[operation] = op
+
+	if slo, ok := c.slos[operation]; ok {
+		slo.record(now, success, d)
+	}
+}
+
+// RecordError records an error by type (e.g., "timeout", "db_error") and interface (diameter, http)
+// Identical errors are deduplicated in the recent error ring with an occurrence count
+func (c *Collector) RecordError(errType, iface string, err error) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errors.Total++
+	if errType != "" {
+		c.errors.ByType[errType]++
+	}
+	if iface != "" {
+		c.errors.ByInterface[iface]++
+	}
+
+	// Deduplicate against recent errors; a repeated error moves to the newest position
+	info := ErrorInfo{
+		Message:   message,
+		Code:      errType,
+		Interface: iface,
+		FirstSeen: now,
+		Timestamp: now,
+		Count:     1,
+	}
+	for i, recent := range c.errors.Recent {
+		if recent.Message == message && recent.Code == errType && recent.Interface == iface {
+			info.FirstSeen = recent.FirstSeen
+			info.Count = recent.Count + 1
+			c.errors.Recent = append(c.errors.Recent[:i], c.errors.Recent[i+1:]...)
+			break
+		}
+	}
+
+	c.errors.Recent = append(c.errors.Recent, info)
+	if len(c.errors.Recent) > c.config.RecentErrors {
+		c.errors.Recent = c.errors.Recent[len(c.errors.Recent)-c.config.RecentErrors:]
+	}
+
+	last := info
+	c.errors.LastError = &last
+}
+
+// RecentErrors returns the recent distinct errors, oldest first
+func (c *Collector) RecentErrors() []ErrorInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]ErrorInfo(nil), c.errors.Recent...)
+}
+
+// RecordCacheHit records a cache lookup result
+func (c *Collector) RecordCacheHit(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cache := &c.eirStats().CacheStats
+	if hit {
+		cache.Hits++
+	} else {
+		cache.Misses++
+	}
+
+	total := cache.Hits + cache.Misses
+	cache.HitRate = float64(cache.Hits) / float64(total) * 100
+}
+
+// RecordEviction records a cache entry evicted due to capacity
+func (c *Collector) RecordEviction() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eirStats().CacheStats.Evictions++
+}
+
+// RecordExpiration records a cache entry removed because its TTL expired
+func (c *Collector) RecordExpiration() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eirStats().CacheStats.Expirations++
+}
+
+// SetCacheSize sets the current number of cache entries and the configured maximum
+func (c *Collector) SetCacheSize(size, maxSize uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cache := &c.eirStats().CacheStats
+	cache.Size = size
+	cache.MaxSize = maxSize
+}
+
+// SetCacheBytes sets the approximate memory used by cached entries
+func (c *Collector) SetCacheBytes(bytes uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eirStats().CacheStats.Bytes = bytes
+}
+
+// RecordDatabaseOperation records a database operation (query, insert, update, delete)
+func (c *Collector) RecordDatabaseOperation(operation string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.countDBOperation(operation)
+}
+
+// RecordDBOp records a database operation with its table, latency and result
+// An empty table skips the per-table breakdown
+func (c *Collector) RecordDBOp(op, table string, d time.Duration, err error) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	op = c.countDBOperation(op)
+	if err != nil {
+		c.eirStats().DatabaseOps.Errors++
+	}
+
+	c.dbLatency.add(ms)
+	w, ok := c.dbOpLatency[op]
+	if !ok {
+		w = newLatencyWindow(c.config.LatencySamples)
+		c.dbOpLatency[op] = w
+	}
+	w.add(ms)
+
+	if table != "" {
+		recordDBBreakdown(c.dbTables, table, ms, err)
+	}
+}
+
+// RecordDBQuery records the latency and result of a named query (e.g., "lookup_imei")
+func (c *Collector) RecordDBQuery(name string, d time.Duration, err error) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eirStats()
+	recordDBBreakdown(c.dbQueries, name, ms, err)
+}
+
+// countDBOperation increments the counter for op and returns its normalized name (caller holds the lock)
+func (c *Collector) countDBOperation(op string) string {
+	dbOps := &c.eirStats().DatabaseOps
+	switch op {
+	case "query", "select":
+		dbOps.Queries++
+		return "query"
+	case "insert":
+		dbOps.Inserts++
+	case "update":
+		dbOps.Updates++
+	case "delete":
+		dbOps.Deletes++
+	}
+	return op
+}
+
+// RecordEquipmentStatus records the status returned by an equipment check
+func (c *Collector) RecordEquipmentStatus(status string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eirStats().ByEquipmentStatus[status]++
+}
+
+// RecordTACCheck records an equipment check result against the IMEI's Type Allocation Code
+// When MaxTACs distinct TACs are tracked, the least recently checked TAC is evicted, so a
+// newly seen device model is kept while TACs no longer seen in the network age out
+func (c *Collector) RecordTACCheck(imei, status string) {
+	tac := TACFromIMEI(imei)
+	if tac == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	eir := c.eirStats()
+	tacStats, ok := eir.ByTAC[tac]
+	if ok {
+		c.tacRecency.MoveToFront(c.tacElems[tac])
+	} else {
+		if len(eir.ByTAC) >= c.config.MaxTACs {
+			c.evictLeastRecentTAC()
+		}
+		c.tacElems[tac] = c.tacRecency.PushFront(tac)
+	}
+
+	tacStats.Checks++
+	if status != "" {
+		if tacStats.ByStatus == nil {
+			tacStats.ByStatus = make(map[string]uint64)
+		}
+		tacStats.ByStatus[status]++
+	}
+	eir.ByTAC[tac] = tacStats
+}
+
+// evictLeastRecentTAC removes the TAC checked least recently (caller holds the lock)
+func (c *Collector) evictLeastRecentTAC() {
+	tac := c.tacRecency.Remove(c.tacRecency.Back()).(string)
+	delete(c.tacElems, tac)
+	delete(c.eir.ByTAC, tac)
+}
+
+// RecordStatusTransition records an equipment status change (e.g., whitelisted -> blacklisted)
+func (c *Collector) RecordStatusTransition(imei, from, to string) {
+	if from == to {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	eir := c.eirStats()
+	eir.StatusTransitions[TransitionKey(from, to)]++
+
+	if c.config.RecentStatusChanges <= 0 {
+		return
+	}
+
+	eir.RecentChanges = append(eir.RecentChanges, StatusChange{
+		IMEI:      imei,
+		From:      from,
+		To:        to,
+		Timestamp: c.clock.Now(),
+	})
+	if len(eir.RecentChanges) > c.config.RecentStatusChanges {
+		eir.RecentChanges = eir.RecentChanges[len(eir.RecentChanges)-c.config.RecentStatusChanges:]
+	}
+}
+
+// eirStats returns the EIR section, creating it on first use (caller holds the lock)
+func (c *Collector) eirStats() *EIRStats {
+	if c.eir == nil {
+		c.eir = &EIRStats{
+			EquipmentChecks: EquipmentCheckStats{
+				ByInterface: make(map[string]InterfaceCheckStats),
+			},
+			ByEquipmentStatus: make(map[string]uint64),
+			StatusTransitions: make(map[string]uint64),
+			ByTAC:             make(map[string]TACStats),
+		}
+	}
+	return c.eir
+}
+
+// SetActiveConnections sets the current number of active connections
+func (c *Collector) SetActiveConnections(count int64) {
+	if count < 0 {
+		count = 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connections.Active = uint64(count)
+}
+
+// IncrementActiveConnections records a newly established connection
+func (c *Collector) IncrementActiveConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connections.Active++
+	c.connections.Total++
+}
+
+// DecrementActiveConnections records a closed connection
+func (c *Collector) DecrementActiveConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connections.Active > 0 {
+		c.connections.Active--
+	}
+	c.connections.Closed++
+}
+
+// IncrementListenerConnections records a newly established connection on the given listener
+func (c *Collector) IncrementListenerConnections(listener string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connections.Active++
+	c.connections.Total++
+
+	ls := c.connections.ByListener[listener]
+	ls.Active++
+	ls.Total++
+	c.connections.ByListener[listener] = ls
+}
+
+// DecrementListenerConnections records a closed connection on the given listener
+func (c *Collector) DecrementListenerConnections(listener string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connections.Active > 0 {
+		c.connections.Active--
+	}
+	c.connections.Closed++
+
+	ls := c.connections.ByListener[listener]
+	if ls.Active > 0 {
+		ls.Active--
+	}
+	ls.Closed++
+	c.connections.ByListener[listener] = ls
+}
+
+// RecordConnectionFailure records a failed connection attempt
+// An empty listener only updates the overall count
+func (c *Collector) RecordConnectionFailure(listener string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connections.Failed++
+
+	if listener != "" {
+		ls := c.connections.ByListener[listener]
+		ls.Failed++
+		c.connections.ByListener[listener] = ls
+	}
+}
+
+// SetPeerState records the state of a Diameter peer identified by Origin-Host
+// Entering the open state starts the peer's uptime
+func (c *Collector) SetPeerState(originHost, state string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peer := c.peers[originHost]
+	if state == PeerStateOpen && peer.State != PeerStateOpen {
+		peer.ConnectedSince = c.clock.Now()
+	}
+	peer.State = state
+	c.peers[originHost] = peer
+}
+
+// RecordPeerDisconnect records a peer disconnect and its cause (e.g., "REBOOTING", "DWR_TIMEOUT")
+func (c *Collector) RecordPeerDisconnect(originHost, cause string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peer := c.peers[originHost]
+	peer.State = PeerStateClosed
+	peer.ConnectedSince = time.Time{}
+	peer.Disconnects++
+	peer.LastDisconnectCause = cause
+	peer.LastDisconnectAt = c.clock.Now()
+	c.peers[originHost] = peer
+}
+
+// RecordDWRFailure records an unanswered Device-Watchdog request for a peer
+func (c *Collector) RecordDWRFailure(originHost string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peer := c.peers[originHost]
+	peer.DWRFailures++
+	c.peers[originHost] = peer
+}
+
+// RecordPeerMessages records messages sent to and received from a peer
+func (c *Collector) RecordPeerMessages(originHost string, sent, recv uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peer := c.peers[originHost]
+	peer.MessagesSent += sent
+	peer.MessagesRecv += recv
+	c.peers[originHost] = peer
+}
+
+// RecordSCTPEstablished records a newly established SCTP association
+func (c *Collector) RecordSCTPEstablished() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.sctpStats()
+	s.Establishes++
+	s.ActiveAssociations++
+}
+
+// RecordSCTPAborted records an aborted SCTP association
+func (c *Collector) RecordSCTPAborted() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.sctpStats()
+	s.Aborts++
+	if s.ActiveAssociations > 0 {
+		s.ActiveAssociations--
+	}
+}
+
+// RecordSCTPShutdown records a gracefully shut down SCTP association
+func (c *Collector) RecordSCTPShutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.sctpStats()
+	s.Shutdowns++
+	if s.ActiveAssociations > 0 {
+		s.ActiveAssociations--
+	}
+}
+
+// RecordSCTPPathFailover records a primary path change on a multi-homed association
+func (c *Collector) RecordSCTPPathFailover() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sctpStats().PathFailovers++
+}
+
+// RecordSCTPRetransmits records retransmitted DATA chunks
+func (c *Collector) RecordSCTPRetransmits(count uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sctpStats().Retransmits += count
+}
+
+// RecordSCTPGapAcks records SACKs carrying gap ack blocks
+func (c *Collector) RecordSCTPGapAcks(count uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sctpStats().GapAcks += count
+}
+
+// sctpStats returns the SCTP section, creating it on first use (caller holds the lock)
+func (c *Collector) sctpStats() *SCTPStats {
+	if c.sctp == nil {
+		c.sctp = &SCTPStats{}
+	}
+	return c.sctp
+}
+
+// SetLoadLevel sets the current load level in percent (values above 100 are capped)
+func (c *Collector) SetLoadLevel(percent uint64) {
+	if percent > 100 {
+		percent = 100
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overloadStats().LoadLevel = percent
+}
+
+// RecordThrottled records a request throttled by overload control on the given interface
+func (c *Collector) RecordThrottled(iface string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o := c.overloadStats()
+	o.Throttled++
+	ifStats := o.ByInterface[iface]
+	ifStats.Throttled++
+	o.ByInterface[iface] = ifStats
+}
+
+// RecordOverloadRejected records a request rejected due to overload on the given interface
+func (c *Collector) RecordOverloadRejected(iface string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o := c.overloadStats()
+	o.Rejected++
+	ifStats := o.ByInterface[iface]
+	ifStats.Rejected++
+	o.ByInterface[iface] = ifStats
+}
+
+// overloadStats returns the overload section, creating it on first use (caller holds the lock)
+func (c *Collector) overloadStats() *OverloadStats {
+	if c.overload == nil {
+		c.overload = &OverloadStats{ByInterface: make(map[string]InterfaceOverloadStats)}
+	}
+	return c.overload
+}
+
+// SetLicense sets the licensed TPS and subscriber limits
+func (c *Collector) SetLicense(tps, subscribers uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	capacity := c.capacityStats()
+	capacity.LicensedTPS = tps
+	capacity.LicensedSubscribers = subscribers
+}
+
+// SetProvisionedSubscribers sets the number of provisioned subscribers
+func (c *Collector) SetProvisionedSubscribers(count uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacityStats().ProvisionedSubscribers = count
+}
+
+// RecordTPS records an observed TPS sample, keeping the peak for the current period
+func (c *Collector) RecordTPS(tps float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	capacity := c.capacityStats()
+	if tps > capacity.PeakTPS {
+		capacity.PeakTPS = tps
+	}
+}
+
+// capacityStats returns the capacity section, creating it on first use (caller holds the lock)
+func (c *Collector) capacityStats() *CapacityStats {
+	if c.capacity == nil {
+		c.capacity = &CapacityStats{}
+	}
+	return c.capacity
+}
+
+// SetRuntimeSampler attaches a runtime sampler whose latest sample is included in snapshots
+// Passing nil removes the runtime section
+func (c *Collector) SetRuntimeSampler(sampler *RuntimeSampler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runtimeSampler = sampler
+}
+
+// SetConfigStatusSource attaches a source whose provider health is included in snapshots
+// Passing nil removes the config providers section
+func (c *Collector) SetConfigStatusSource(source ConfigStatusSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.configSource = source
+}
+
+// SetSelfTest attaches a self-test whose stats are included in snapshots as
+// CustomMetrics["selftest"]; passing nil removes the section
+func (c *Collector) SetSelfTest(selfTest *SelfTest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.selfTest = selfTest
+}
+
+// SetMetricsHarvester attaches a harvester whose expvar and runtime/metrics values
+// are read into every snapshot as CustomMetrics["runtime"]; passing nil removes the section
+func (c *Collector) SetMetricsHarvester(harvester *MetricsHarvester) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.harvester = harvester
+}
+
+// GetStats returns a snapshot of the collected statistics as *ServiceStats
+func (c *Collector) GetStats() interface{} {
+	return c.GetServiceStats()
+}
+
+// GetServiceStats returns a snapshot of the collected statistics
+// Each call starts a new period: the max in-flight watermark is reset to the current value
+// and the peak TPS is cleared
+func (c *Collector) GetServiceStats() *ServiceStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.snapshot()
+	c.requests.MaxPending = c.requests.Pending
+	c.failureSamples = nil
+	if c.capacity != nil {
+		c.capacity.PeakTPS = 0
+	}
+	return stats
+}
+
+// Snapshot returns a deep copy of the collected statistics without starting a new period
+func (c *Collector) Snapshot() *ServiceStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot()
+}
+
+// snapshot builds a deep copy of the collected statistics (caller holds the lock)
+func (c *Collector) snapshot() *ServiceStats {
+	now := c.clock.Now()
+	uptime := now.Sub(c.startTime)
+	stats := &ServiceStats{
+		ServiceName:    c.config.ServiceName,
+		ServiceVersion: c.config.ServiceVersion,
+		Uptime:         uptime.Truncate(time.Second).String(),
+		UptimeSeconds:  uint64(uptime / time.Second),
+		StartTime:      c.startTime,
+		Timestamp:      now,
+		Connections:    c.copyConnections(),
+		Requests:       c.copyRequests(),
+		Performance:    c.buildPerformance(now),
+		Errors:         c.copyErrors(),
+		CustomMetrics:  make(CustomMetrics),
+		FailureSamples: c.copyFailureSamples(),
+		Tenants:        c.copyTenants(),
+	}
+
+	if c.eir != nil {
+		stats.CustomMetrics["eir"] = c.copyEIR()
+	}
+
+	if c.runtimeSampler != nil {
+		stats.Runtime = c.runtimeSampler.Stats()
+	}
+
+	if c.configSource != nil {
+		stats.ConfigProviders = NewConfigProviderStats(c.configSource.ProviderStatus())
+	}
+
+	if c.selfTest != nil {
+		stats.CustomMetrics["selftest"] = c.selfTest.Stats()
+	}
+
+	if c.harvester != nil {
+		stats.CustomMetrics["runtime"] = c.harvester.Harvest()
+	}
+
+	if len(c.slos) > 0 {
+		stats.SLOs = make(map[string]SLOStats, len(c.slos))
+		for op, slo := range c.slos {
+			stats.SLOs[op] = slo.snapshot(now)
+		}
+	}
+
+	if c.sctp != nil {
+		sctp := *c.sctp
+		stats.SCTP = &sctp
+	}
+
+	if c.capacity != nil {
+		capacity := *c.capacity
+		stats.Capacity = &capacity
+	}
+
+	if c.overload != nil {
+		overload := *c.overload
+		overload.ByInterface = make(map[string]InterfaceOverloadStats, len(c.overload.ByInterface))
+		for k, v := range c.overload.ByInterface {
+			overload.ByInterface[k] = v
+		}
+		stats.Overload = &overload
+	}
+
+	if len(c.peers) > 0 {
+		stats.Peers = make(map[string]PeerStats, len(c.peers))
+		for host, peer := range c.peers {
+			if peer.State == PeerStateOpen && !peer.ConnectedSince.IsZero() {
+				peer.UptimeSeconds = uint64(now.Sub(peer.ConnectedSince) / time.Second)
+			}
+			stats.Peers[host] = peer
+		}
+	}
+
+	return stats
+}
+
+// copyConnections deep copies connection stats
+func (c *Collector) copyConnections() ConnectionStats {
+	conns := c.connections
+	conns.ByListener = make(map[string]ListenerStats, len(c.connections.ByListener))
+	for k, v := range c.connections.ByListener {
+		conns.ByListener[k] = v
+	}
+	return conns
+}
+
+// copyRequests deep copies request stats
+func (c *Collector) copyRequests() RequestStats {
+	req := c.requests
+	req.BySource = make(map[string]SourceStats, len(c.requests.BySource))
+	for k, v := range c.requests.BySource {
+		v.SentSizes = copyIntMap(v.SentSizes)
+		v.RecvSizes = copyIntMap(v.RecvSizes)
+		req.BySource[k] = v
+	}
+	req.ByOperation = make(map[string]OperationStats, len(c.requests.ByOperation))
+	for k, v := range c.requests.ByOperation {
+		req.ByOperation[k] = v
+	}
+	return req
+}
+
+// copyErrors deep copies error stats
+func (c *Collector) copyErrors() ErrorStats {
+	errs := c.errors
+	errs.ByType = copyStringMap(c.errors.ByType)
+	errs.ByInterface = copyStringMap(c.errors.ByInterface)
+	if c.errors.LastError != nil {
+		last := *c.errors.LastError
+		errs.LastError = &last
+	}
+	errs.Recent = append([]ErrorInfo(nil), c.errors.Recent...)
+	return errs
+}
+
+// copyEIR deep copies EIR-specific stats
+func (c *Collector) copyEIR() *EIRStats {
+	eir := *c.eir
+	eir.EquipmentChecks.ByInterface = make(map[string]InterfaceCheckStats, len(c.eir.EquipmentChecks.ByInterface))
+	for k, v := range c.eir.EquipmentChecks.ByInterface {
+		v.ByResultCode = copyIntMap(v.ByResultCode)
+		eir.EquipmentChecks.ByInterface[k] = v
+	}
+	eir.ByEquipmentStatus = copyStringMap(c.eir.ByEquipmentStatus)
+	eir.StatusTransitions = copyStringMap(c.eir.StatusTransitions)
+	eir.RecentChanges = append([]StatusChange(nil), c.eir.RecentChanges...)
+	eir.DatabaseOps.AvgLatencyMs = c.dbLatency.snapshot().AvgLatencyMs
+	eir.DatabaseOps.ByOperation = make(map[string]LatencyStats, len(c.dbOpLatency))
+	for op, w := range c.dbOpLatency {
+		eir.DatabaseOps.ByOperation[op] = w.snapshot()
+	}
+	eir.DatabaseOps.ByTable = snapshotDBBreakdowns(c.dbTables)
+	eir.DatabaseOps.ByQueryName = snapshotDBBreakdowns(c.dbQueries)
+	eir.ByTAC = make(map[string]TACStats, len(c.eir.ByTAC))
+	for tac, v := range c.eir.ByTAC {
+		v.ByStatus = copyStringMap(v.ByStatus)
+		eir.ByTAC[tac] = v
+	}
+	return &eir
+}
+
+// buildPerformance computes performance stats from the latency windows
+func (c *Collector) buildPerformance(now time.Time) PerformanceStats {
+	overall := c.latency.snapshot()
+	perf := PerformanceStats{
+		AvgLatencyMs: overall.AvgLatencyMs,
+		MinLatencyMs: overall.MinLatencyMs,
+		MaxLatencyMs: overall.MaxLatencyMs,
+		P50LatencyMs: overall.P50LatencyMs,
+		P95LatencyMs: overall.P95LatencyMs,
+		P99LatencyMs: overall.P99LatencyMs,
+		BySource:     make(map[string]LatencyStats, len(c.sourceLatency)),
+		ByOperation:  make(map[string]LatencyStats, len(c.opLatency)),
+	}
+
+	if elapsed := now.Sub(c.startTime).Seconds(); elapsed > 0 {
+		perf.RequestsPerSecond = float64(c.requests.Total) / elapsed
+	}
+
+	for source, w := range c.sourceLatency {
+		perf.BySource[source] = w.snapshot()
+	}
+	for op, w := range c.opLatency {
+		perf.ByOperation[op] = w.snapshot()
+	}
+
+	return perf
+}
+
+// copyStringMap copies a map[string]uint64
+func copyStringMap(m map[string]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// copyIntMap copies a map[int]uint64, preserving nil
+func copyIntMap(m map[int]uint64) map[int]uint64 {
+	if m == nil {
+		return nil
+	}
+	result := make(map[int]uint64, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// dbBreakdown accumulates operations and latency for a table or named query
+type dbBreakdown struct {
+	operations uint64
+	errors     uint64
+	sumMs      float64
+	maxMs      float64
+}
+
+// recordDBBreakdown adds a sample to the breakdown for key, creating it if needed
+func recordDBBreakdown(m map[string]*dbBreakdown, key string, ms float64, err error) {
+	b, ok := m[key]
+	if !ok {
+		b = &dbBreakdown{}
+		m[key] = b
+	}
+	b.operations++
+	b.sumMs += ms
+	if ms > b.maxMs {
+		b.maxMs = ms
+	}
+	if err != nil {
+		b.errors++
+	}
+}
+
+// snapshotDBBreakdowns converts breakdown accumulators to stats (nil when empty)
+func snapshotDBBreakdowns(m map[string]*dbBreakdown) map[string]DBBreakdownStats {
+	if len(m) == 0 {
+		return nil
+	}
+	result := make(map[string]DBBreakdownStats, len(m))
+	for key, b := range m {
+		result[key] = DBBreakdownStats{
+			Operations:   b.operations,
+			Errors:       b.errors,
+			AvgLatencyMs: b.sumMs / float64(b.operations),
+			MaxLatencyMs: b.maxMs,
+		}
+	}
+	return result
+}
+
+// latencyWindow keeps lifetime aggregates plus a ring of recent samples for percentiles
+type latencyWindow struct {
+	samples []float64
+	next    int
+	full    bool
+	count   uint64
+	sumMs   float64
+	minMs   float64
+	maxMs   float64
+}
+
+// newLatencyWindow creates a latency window holding up to size recent samples
+func newLatencyWindow(size int) *latencyWindow {
+	return &latencyWindow{samples: make([]float64, size)}
+}
+
+// add records a latency sample in milliseconds
+func (w *latencyWindow) add(ms float64) {
+	if w.count == 0 || ms < w.minMs {
+		w.minMs = ms
+	}
+	if ms > w.maxMs {
+		w.maxMs = ms
+	}
+	w.count++
+	w.sumMs += ms
+
+	w.samples[w.next] = ms
+	w.next++
+	if w.next == len(w.samples) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+// snapshot computes latency stats from the window
+func (w *latencyWindow) snapshot() LatencyStats {
+	if w.count == 0 {
+		return LatencyStats{}
+	}
+
+	n := w.next
+	if w.full {
+		n = len(w.samples)
+	}
+	sorted := make([]float64, n)
+	copy(sorted, w.samples[:n])
+	sort.Float64s(sorted)
+
+	return LatencyStats{
+		Count:        w.count,
+		AvgLatencyMs: w.sumMs / float64(w.count),
+		MinLatencyMs: w.minMs,
+		MaxLatencyMs: w.maxMs,
+		P50LatencyMs: percentile(sorted, 50),
+		P95LatencyMs: percentile(sorted, 95),
+		P99LatencyMs: percentile(sorted, 99),
+	}
+}
+
+// percentile returns the p-th percentile (nearest rank) of sorted samples
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -1,7 +1,6 @@
 package stats
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -31,11 +30,11 @@ func FetchStats(url string, serviceType string) (*ServiceStats, error) {
 	case "prometheus":
 		return ParsePrometheusMetrics(string(body))
 	case "json":
-		var stats ServiceStats
-		if err := json.Unmarshal(body, &stats); err != nil {
+		stats, err := UnmarshalServiceStats(body)
+		if err != nil {
 			return nil, fmt.Errorf("failed to unmarshal JSON stats: %w", err)
 		}
-		return &stats, nil
+		return stats, nil
 	default:
 		return nil, fmt.Errorf("unsupported service type: %s", serviceType)
 	}
@@ -115,7 +114,7 @@ func ParsePrometheusMetrics(metricsText string) (*ServiceStats, error) {
 		hitRate = (cacheHits / cacheTotal) * 100
 	}
 
-	stats.CustomMetrics["cache"] = CacheStats{
+	stats.CustomMetrics["cache"] = &CacheStats{
 		Hits:    uint64(cacheHits),
 		Misses:  uint64(cacheMisses),
 		HitRate: hitRate,
@@ -129,7 +128,7 @@ func ParsePrometheusMetrics(metricsText string) (*ServiceStats, error) {
 			"greylisted":  uint64(metricsMap["eir_equipment_by_status{status=\"greylisted\"}"]),
 		},
 	}
-	stats.CustomMetrics["eir"] = eirStats
+	stats.CustomMetrics["eir"] = &eirStats
 
 	return stats, nil
 }
@@ -146,13 +145,14 @@ func CompareStats(before, after *ServiceStats) *ServiceStats {
 			Closed: after.Connections.Closed - before.Connections.Closed,
 		},
 		Requests: RequestStats{
-			Total:     after.Requests.Total - before.Requests.Total,
-			Success:   after.Requests.Success - before.Requests.Success,
-			Failed:    after.Requests.Failed - before.Requests.Failed,
-			Pending:   after.Requests.Pending,
-			BytesSent: after.Requests.BytesSent - before.Requests.BytesSent,
-			BytesRecv: after.Requests.BytesRecv - before.Requests.BytesRecv,
-			BySource:  make(map[string]SourceStats),
+			Total:      after.Requests.Total - before.Requests.Total,
+			Success:    after.Requests.Success - before.Requests.Success,
+			Failed:     after.Requests.Failed - before.Requests.Failed,
+			Pending:    after.Requests.Pending,
+			MaxPending: after.Requests.MaxPending,
+			BytesSent:  after.Requests.BytesSent - before.Requests.BytesSent,
+			BytesRecv:  after.Requests.BytesRecv - before.Requests.BytesRecv,
+			BySource:   make(map[string]SourceStats),
 		},
 		Performance: after.Performance,
 		Errors: ErrorStats{
@@ -165,9 +165,11 @@ func CompareStats(before, after *ServiceStats) *ServiceStats {
 	for source, afterStats := range after.Requests.BySource {
 		beforeStats := before.Requests.BySource[source]
 		diff.Requests.BySource[source] = SourceStats{
-			Total:   afterStats.Total - beforeStats.Total,
-			Success: afterStats.Success - beforeStats.Success,
-			Failed:  afterStats.Failed - beforeStats.Failed,
+			Total:     afterStats.Total - beforeStats.Total,
+			Success:   afterStats.Success - beforeStats.Success,
+			Failed:    afterStats.Failed - beforeStats.Failed,
+			BytesSent: afterStats.BytesSent - beforeStats.BytesSent,
+			BytesRecv: afterStats.BytesRecv - beforeStats.BytesRecv,
 		}
 	}
 
@@ -191,9 +193,20 @@ func ValidateStats(expected, actual uint64, tolerance uint64) (bool, string) {
 }
 
 // FormatStatsReport generates a human-readable stats report
+// Result codes, error types and HTTP endpoint error rates are ranked, keeping the
+// top DefaultReportTopN rows of each table
 func FormatStatsReport(stats *ServiceStats) string {
+	return FormatStatsReportTopN(stats, DefaultReportTopN)
+}
+
+// FormatStatsReportTopN generates a human-readable stats report keeping the top n
+// rows of each ranked table (all if n <= 0)
+func FormatStatsReportTopN(stats *ServiceStats, n int) string {
 	report := strings.Builder{}
 	report.WriteString(fmt.Sprintf("=== %s Statistics ===\n", stats.ServiceName))
+	if stats.Uptime != "" {
+		report.WriteString(fmt.Sprintf("Uptime: %s\n", stats.Uptime))
+	}
 	report.WriteString(fmt.Sprintf("Timestamp: %s\n\n", stats.Timestamp.Format(time.RFC3339)))
 
 	// Connections
@@ -209,6 +222,7 @@ func FormatStatsReport(stats *ServiceStats) string {
 	report.WriteString(fmt.Sprintf("  Success: %d\n", stats.Requests.Success))
 	report.WriteString(fmt.Sprintf("  Failed: %d\n", stats.Requests.Failed))
 	report.WriteString(fmt.Sprintf("  Pending: %d\n", stats.Requests.Pending))
+	report.WriteString(fmt.Sprintf("  Max Pending: %d\n", stats.Requests.MaxPending))
 	report.WriteString(fmt.Sprintf("  Bytes Sent: %d\n", stats.Requests.BytesSent))
 	report.WriteString(fmt.Sprintf("  Bytes Recv: %d\n\n", stats.Requests.BytesRecv))
 
@@ -235,5 +249,7 @@ func FormatStatsReport(stats *ServiceStats) string {
 	report.WriteString("Errors:\n")
 	report.WriteString(fmt.Sprintf("  Total: %d\n", stats.Errors.Total))
 
+	writeRankedTables(&report, stats, n)
+
 	return report.String()
 }
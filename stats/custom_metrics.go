@@ -0,0 +1,150 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CustomMetrics holds service-specific metric sections by name
+// Sections of a registered type decode from JSON as that type (a pointer, e.g.
+// *EIRStats for "eir"); unregistered sections decode generically
+type CustomMetrics map[string]interface{}
+
+var (
+	customMetricsMu    sync.RWMutex
+	customMetricsTypes = map[string]func() interface{}{
+		"eir":      func() interface{} { return &EIRStats{} },
+		"cache":    func() interface{} { return &CacheStats{} },
+		"selftest": func() interface{} { return &SelfTestStats{} },
+		"runtime":  func() interface{} { return &HarvestedMetrics{} },
+	}
+	interfaceStatsTypes = map[string]func() interface{}{}
+)
+
+// RegisterCustomMetric registers the type of a custom metrics section
+// newFn must return a pointer to a new zero value, which JSON decoding fills in
+func RegisterCustomMetric(name string, newFn func() interface{}) {
+	customMetricsMu.Lock()
+	defer customMetricsMu.Unlock()
+	customMetricsTypes[name] = newFn
+}
+
+// RegisterInterfaceStats registers the type of an InterfaceStats entry, used by
+// UnmarshalServiceStats; newFn must return a pointer to a new zero value
+func RegisterInterfaceStats(name string, newFn func() interface{}) {
+	customMetricsMu.Lock()
+	defer customMetricsMu.Unlock()
+	interfaceStatsTypes[name] = newFn
+}
+
+// RegisteredCustomMetrics returns the names of registered custom metrics sections, sorted
+func RegisteredCustomMetrics() []string {
+	customMetricsMu.RLock()
+	defer customMetricsMu.RUnlock()
+
+	names := make([]string, 0, len(customMetricsTypes))
+	for name := range customMetricsTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnmarshalJSON decodes registered sections into their types
+func (m *CustomMetrics) UnmarshalJSON(data []byte) error {
+	customMetricsMu.RLock()
+	defer customMetricsMu.RUnlock()
+
+	sections, err := decodeSections(data, customMetricsTypes)
+	if err != nil {
+		return fmt.Errorf("custom metrics %w", err)
+	}
+	*m = sections
+	return nil
+}
+
+// decodeSections decodes a JSON object whose values are typed by name in types
+func decodeSections(data []byte, types map[string]func() interface{}) (map[string]interface{}, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	result := make(map[string]interface{}, len(raw))
+	for name, section := range raw {
+		var value interface{}
+		if newFn, ok := types[name]; ok {
+			value = newFn()
+		} else {
+			value = new(interface{})
+		}
+		if err := json.Unmarshal(section, value); err != nil {
+			return nil, fmt.Errorf("%q: %w", name, err)
+		}
+		if generic, ok := value.(*interface{}); ok {
+			value = *generic
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// CustomMetric returns a section as *T, accepting sections stored as T or *T
+func CustomMetric[T any](section interface{}) (*T, bool) {
+	switch v := section.(type) {
+	case *T:
+		return v, v != nil
+	case T:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
+
+// EIR returns the "eir" section
+func (m CustomMetrics) EIR() (*EIRStats, bool) {
+	return CustomMetric[EIRStats](m["eir"])
+}
+
+// Runtime returns the "runtime" section, see MetricsHarvester
+func (m CustomMetrics) Runtime() (*HarvestedMetrics, bool) {
+	return CustomMetric[HarvestedMetrics](m["runtime"])
+}
+
+// SelfTest returns the "selftest" section
+func (m CustomMetrics) SelfTest() (*SelfTestStats, bool) {
+	return CustomMetric[SelfTestStats](m["selftest"])
+}
+
+// UnmarshalServiceStats decodes a /stats JSON document with typed CustomMetrics and
+// InterfaceStats sections, so scraped stats can be fed to CompareStats and the
+// export transformer like locally collected ones
+func UnmarshalServiceStats(data []byte) (*ServiceStats, error) {
+	var stats ServiceStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		InterfaceStats json.RawMessage `json:"interface_stats"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.InterfaceStats) > 0 {
+		customMetricsMu.RLock()
+		sections, err := decodeSections(doc.InterfaceStats, interfaceStatsTypes)
+		customMetricsMu.RUnlock()
+		if err != nil {
+			return nil, fmt.Errorf("interface stats %w", err)
+		}
+		stats.InterfaceStats = sections
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,402 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AggregatorServerConfig defines configuration for AggregatorServer
+type AggregatorServerConfig struct {
+	Name string `json:"name"`
+
+	// Exporters receive every accepted batch
+	Exporters []Exporter `json:"-"`
+
+	// Period is the collection period records are bucketed into for deduplication;
+	// zero deduplicates on the exact timestamp
+	Period time.Duration `json:"period"`
+
+	// DedupRetention is how long (hostname, counter, period) keys are remembered (default: 1h)
+	DedupRetention time.Duration `json:"dedup_retention"`
+
+	// RejectUnknownCounters rejects records whose counter ID has no CounterMetadata
+	RejectUnknownCounters bool `json:"reject_unknown_counters"`
+
+	// ExportTimeout bounds the fan-out to Exporters per batch (default: 30s)
+	ExportTimeout time.Duration `json:"export_timeout"`
+
+	// MaxBodyBytes limits HTTP request bodies (default: 10MB)
+	MaxBodyBytes int64 `json:"max_body_bytes"`
+
+	// OnCatalog receives the counter catalog of a push session's metadata handshake (optional)
+	OnCatalog func(sessionID string, catalog CounterCatalog) `json:"-"`
+}
+
+// AggregatorResult reports how a received batch was handled
+type AggregatorResult struct {
+	Accepted   int `json:"accepted"`
+	Duplicates int `json:"duplicates"`
+	Invalid    int `json:"invalid"`
+	Gaps       int `json:"gaps,omitempty"` // Batch sequences skipped by HTTP senders (cumulative stats only)
+}
+
+// AggregatorServer receives MetricRecord batches pushed by edge services, validates them,
+// drops duplicates by (hostname, counter, cause code, period) and fans them out to its
+// own exporters, enabling a two-tier collection topology
+//
+// Batches arrive as JSON over HTTP (the HTTPExporter format) or over push streams
+// (the PushClient protocol); gRPC front ends feed streams through ServePushStream.
+type AggregatorServer struct {
+	name   string
+	config AggregatorServerConfig
+	logger Logger
+
+	mu       sync.Mutex
+	seen     map[dedupKey]time.Time
+	sessions map[string]uint64 // Last received push sequence per session
+	batches  *SequenceTracker  // HTTP batch sequences per hostname
+	stats    AggregatorResult
+}
+
+// dedupKey identifies a single metric sample within a collection period
+type dedupKey struct {
+	hostname  string
+	system    string
+	counterID int
+	causeCode int
+	tenant    string
+	period    int64
+}
+
+// NewAggregatorServer creates a new aggregator server
+func NewAggregatorServer(config AggregatorServerConfig, logger Logger) (*AggregatorServer, error) {
+	if len(config.Exporters) == 0 {
+		return nil, fmt.Errorf("aggregator server requires at least one exporter")
+	}
+
+	if config.DedupRetention == 0 {
+		config.DedupRetention = time.Hour
+	}
+
+	if config.ExportTimeout == 0 {
+		config.ExportTimeout = 30 * time.Second
+	}
+
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = 10 << 20
+	}
+
+	return &AggregatorServer{
+		name:     config.Name,
+		config:   config,
+		logger:   logger,
+		seen:     make(map[dedupKey]time.Time),
+		sessions: make(map[string]uint64),
+		batches:  NewSequenceTracker(),
+	}, nil
+}
+
+// Receive validates and deduplicates records, then exports the remainder to every exporter
+func (s *AggregatorServer) Receive(ctx context.Context, records []MetricRecord) (AggregatorResult, error) {
+	accepted, result := s.filter(records)
+	if len(accepted) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.ExportTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, exporter := range s.config.Exporters {
+		if err := exporter.Export(ctx, accepted); err != nil {
+			s.logger.Errorw("Aggregator fan-out failed",
+				"aggregator", s.name,
+				"exporter", exporter.Name(),
+				"records", len(accepted),
+				"error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", exporter.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		// Let the sender's retry through; exporters that succeeded may see the records twice
+		s.forget(accepted)
+	}
+
+	s.logger.Debugw("Aggregated metrics",
+		"aggregator", s.name,
+		"accepted", result.Accepted,
+		"duplicates", result.Duplicates,
+		"invalid", result.Invalid)
+	return result, errors.Join(errs...)
+}
+
+// filter drops invalid and already seen records
+func (s *AggregatorServer) filter(records []MetricRecord) ([]MetricRecord, AggregatorResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	var result AggregatorResult
+	accepted := make([]MetricRecord, 0, len(records))
+	for _, record := range records {
+		if err := s.validate(record); err != nil {
+			result.Invalid++
+			s.logger.Debugw("Dropping invalid metric record",
+				"aggregator", s.name,
+				"counter_id", record.CounterID,
+				"hostname", record.Hostname,
+				"error", err)
+			continue
+		}
+
+		key := s.key(record)
+		if _, ok := s.seen[key]; ok {
+			result.Duplicates++
+			continue
+		}
+		s.seen[key] = now
+		accepted = append(accepted, record)
+	}
+	result.Accepted = len(accepted)
+
+	s.stats.Accepted += result.Accepted
+	s.stats.Duplicates += result.Duplicates
+	s.stats.Invalid += result.Invalid
+	return accepted, result
+}
+
+// forget removes the deduplication keys of records
+func (s *AggregatorServer) forget(records []MetricRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range records {
+		delete(s.seen, s.key(record))
+	}
+}
+
+// validate checks a single record
+func (s *AggregatorServer) validate(record MetricRecord) error {
+	if record.CounterID <= 0 {
+		return fmt.Errorf("invalid counter ID %d", record.CounterID)
+	}
+	if record.Hostname == "" {
+		return fmt.Errorf("hostname is required")
+	}
+	if record.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp is required")
+	}
+	if s.config.RejectUnknownCounters && GetCounterName(record.CounterID) == "unknown" {
+		return fmt.Errorf("unknown counter ID %d", record.CounterID)
+	}
+	return nil
+}
+
+// key returns the deduplication key for a record
+func (s *AggregatorServer) key(record MetricRecord) dedupKey {
+	period := record.Timestamp.UnixNano()
+	if s.config.Period > 0 {
+		period = record.Timestamp.Truncate(s.config.Period).UnixNano()
+	}
+	return dedupKey{
+		hostname:  record.Hostname,
+		system:    record.SystemName,
+		counterID: record.CounterID,
+		causeCode: record.CauseCode,
+		tenant:    record.Tenant,
+		period:    period,
+	}
+}
+
+// sweep forgets keys older than DedupRetention
+func (s *AggregatorServer) sweep(now time.Time) {
+	for key, seenAt := range s.seen {
+		if now.Sub(seenAt) > s.config.DedupRetention {
+			delete(s.seen, key)
+		}
+	}
+}
+
+// Stats returns cumulative receive counts
+func (s *AggregatorServer) Stats() AggregatorResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// ServeHTTP accepts a JSON array of MetricRecords via POST, as sent by HTTPExporter
+// Fan-out failures return 502 so the sender retries the batch
+func (s *AggregatorServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var records []MetricRecord
+	body := http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes)
+	if err := json.NewDecoder(body).Decode(&records); err != nil {
+		http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	s.observeSequence(r.Header, records)
+
+	result, err := s.Receive(r.Context(), records)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// observeSequence checks the batch sequence of an HTTP request for gaps
+// Only a cycle's first chunk is observed; resent chunks are deduplicated per record
+func (s *AggregatorServer) observeSequence(header http.Header, records []MetricRecord) {
+	seq, err := strconv.ParseUint(header.Get(HeaderExportSequence), 10, 64)
+	if err != nil || len(records) == 0 {
+		return
+	}
+	if chunk := header.Get(HeaderExportChunk); chunk != "" && !strings.HasPrefix(chunk, "1/") {
+		return
+	}
+
+	gap, _ := s.batches.Observe(records[0].Hostname, header.Get(HeaderExportSession), seq)
+	if gap == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.stats.Gaps += int(gap)
+	s.mu.Unlock()
+	s.logger.Warnw("Batch sequence gap, batches were lost",
+		"aggregator", s.name,
+		"hostname", records[0].Hostname,
+		"sequence", seq,
+		"missing", gap)
+}
+
+// PushServerStream is the aggregator side of a PushStream
+type PushServerStream interface {
+	Recv(ctx context.Context) (PushFrame, error)
+	Send(ctx context.Context, ack PushAck) error
+	Close() error
+}
+
+// ServePushStream handles one PushClient stream until it ends or ctx is cancelled
+// Batches at or below the session's last received sequence are re-acknowledged without re-exporting
+func (s *AggregatorServer) ServePushStream(ctx context.Context, stream PushServerStream) error {
+	defer stream.Close()
+
+	for {
+		frame, err := stream.Recv(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		last := s.sessions[frame.SessionID]
+		s.mu.Unlock()
+
+		ack := PushAck{Sequence: last}
+		if frame.Type == PushFrameMetadata && frame.Catalog != nil {
+			s.logger.Infow("Received push metadata handshake",
+				"aggregator", s.name,
+				"session_id", frame.SessionID,
+				"catalog_version", frame.Catalog.Version,
+				"counters", len(frame.Catalog.Counters))
+			if s.config.OnCatalog != nil {
+				s.config.OnCatalog(frame.SessionID, *frame.Catalog)
+			}
+		}
+		if frame.Type == PushFrameBatch && frame.Sequence > last {
+			ack.Sequence = frame.Sequence
+			if _, err := s.Receive(ctx, frame.Records); err != nil {
+				// Not acknowledged: the client resends after reconnecting
+				return err
+			}
+			s.mu.Lock()
+			s.sessions[frame.SessionID] = frame.Sequence
+			s.mu.Unlock()
+		}
+
+		if err := stream.Send(ctx, ack); err != nil {
+			return err
+		}
+	}
+}
+
+// ServePushListener accepts push connections using the NewTCPPushDialer wire format until ctx is cancelled
+func (s *AggregatorServer) ServePushListener(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		go func() {
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+
+			if err := s.ServePushStream(ctx, NewConnPushServerStream(conn)); err != nil {
+				s.logger.Warnw("Push stream ended",
+					"aggregator", s.name,
+					"remote", conn.RemoteAddr().String(),
+					"error", err)
+			}
+		}()
+	}
+}
+
+// NewConnPushServerStream wraps conn as a PushServerStream using newline-delimited JSON
+func NewConnPushServerStream(conn net.Conn) PushServerStream {
+	return &connPushServerStream{conn: conn, enc: json.NewEncoder(conn), dec: json.NewDecoder(conn)}
+}
+
+// connPushServerStream is a PushServerStream over a net.Conn
+type connPushServerStream struct {
+	conn net.Conn
+	enc  *json.Encoder
+	dec  *json.Decoder
+}
+
+func (s *connPushServerStream) Recv(ctx context.Context) (PushFrame, error) {
+	deadline, _ := ctx.Deadline()
+	s.conn.SetReadDeadline(deadline)
+	var frame PushFrame
+	err := s.dec.Decode(&frame)
+	return frame, err
+}
+
+func (s *connPushServerStream) Send(ctx context.Context, ack PushAck) error {
+	deadline, _ := ctx.Deadline()
+	s.conn.SetWriteDeadline(deadline)
+	return s.enc.Encode(ack)
+}
+
+func (s *connPushServerStream) Close() error {
+	return s.conn.Close()
+}
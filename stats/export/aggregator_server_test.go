@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAggregatorServer_HTTPDedup(t *testing.T) {
+	sink := &channelExporter{batches: make(chan []MetricRecord, 4)}
+	server, err := NewAggregatorServer(AggregatorServerConfig{Name: "agg", Exporters: []Exporter{sink}, Period: time.Minute}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewAggregatorServer failed: %v", err)
+	}
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	period := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)
+	post := func(records []MetricRecord) AggregatorResult {
+		body, _ := json.Marshal(records)
+		resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", resp.StatusCode)
+		}
+		var result AggregatorResult
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result
+	}
+
+	result := post([]MetricRecord{
+		{CounterID: CounterTotalRequests, Value: 10, Hostname: "eir-1", SystemName: "EIR", Timestamp: period},
+		{CounterID: CounterTotalRequests, Value: 7, Hostname: "eir-2", SystemName: "EIR", Timestamp: period},
+		{CounterID: CounterTotalRequests, Value: 1, SystemName: "EIR", Timestamp: period}, // No hostname
+	})
+	if result != (AggregatorResult{Accepted: 2, Invalid: 1}) {
+		t.Errorf("Unexpected first result: %+v", result)
+	}
+	if batch := <-sink.batches; len(batch) != 2 {
+		t.Errorf("Expected 2 records fanned out, got %d", len(batch))
+	}
+
+	// Same host, counter and period (a retried POST) is dropped
+	result = post([]MetricRecord{
+		{CounterID: CounterTotalRequests, Value: 10, Hostname: "eir-1", SystemName: "EIR", Timestamp: period.Add(5 * time.Second)},
+	})
+	if result != (AggregatorResult{Duplicates: 1}) {
+		t.Errorf("Unexpected retry result: %+v", result)
+	}
+	select {
+	case batch := <-sink.batches:
+		t.Errorf("Expected no fan-out for duplicates, got %d records", len(batch))
+	default:
+	}
+}
+
+func TestAggregatorServer_PushStream(t *testing.T) {
+	sink := &channelExporter{batches: make(chan []MetricRecord, 4)}
+	server, _ := NewAggregatorServer(AggregatorServerConfig{Exporters: []Exporter{sink}}, &mockLogger{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Loopback listener unavailable: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.ServePushListener(ctx, listener)
+
+	client, _ := NewPushClient(PushClientConfig{Dialer: NewTCPPushDialer(listener.Addr().String()), AckTimeout: 5 * time.Second}, &mockLogger{})
+	defer client.Close()
+
+	now := time.Now()
+	for i := 1; i <= 2; i++ {
+		records := []MetricRecord{{CounterID: CounterTotalRequests, Value: uint64(i), Hostname: "eir-1", Timestamp: now.Add(time.Duration(i) * time.Second)}}
+		if err := client.Export(ctx, records); err != nil {
+			t.Fatalf("Export %d failed: %v", i, err)
+		}
+		if batch := <-sink.batches; batch[0].Value != uint64(i) {
+			t.Errorf("Expected value %d, got %d", i, batch[0].Value)
+		}
+	}
+	if stats := server.Stats(); stats.Accepted != 2 {
+		t.Errorf("Expected 2 accepted records, got %+v", stats)
+	}
+}
@@ -0,0 +1,197 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SequenceStore persists the last batch sequence number so sequences keep
+// increasing across restarts and receivers never see a number reused
+type SequenceStore interface {
+	// Load returns the last persisted sequence, or 0 if there is none
+	Load() (uint64, error)
+
+	// Save persists seq before the batch carrying it is exported
+	Save(seq uint64) error
+}
+
+// FileSequenceStore keeps the sequence as a decimal number in a file
+type FileSequenceStore struct {
+	path string
+}
+
+// NewFileSequenceStore creates a sequence store at path
+func NewFileSequenceStore(path string) *FileSequenceStore {
+	return &FileSequenceStore{path: path}
+}
+
+// Load reads the sequence, returning 0 if the file does not exist
+func (f *FileSequenceStore) Load() (uint64, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sequence: %w", err)
+	}
+	seq, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sequence file %s: %w", f.path, err)
+	}
+	return seq, nil
+}
+
+// Save writes the sequence atomically
+func (f *FileSequenceStore) Save(seq uint64) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create sequence directory: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(seq, 10)+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write sequence: %w", err)
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// batchSequenceKey is the context key of the batch sequence
+type batchSequenceKey struct{}
+
+// ContextWithBatchSequence returns ctx carrying the sequence number of the batch being exported
+func ContextWithBatchSequence(ctx context.Context, seq uint64) context.Context {
+	return context.WithValue(ctx, batchSequenceKey{}, seq)
+}
+
+// BatchSequence returns the sequence number the scheduler assigned to the batch
+// passed to Export; every exporter sees the same number for the same cycle
+func BatchSequence(ctx context.Context) (uint64, bool) {
+	seq, ok := ctx.Value(batchSequenceKey{}).(uint64)
+	return seq, ok
+}
+
+// AckExporter is implemented by exporters whose receiver acknowledges batches
+// A spool or replay log may drop every batch at or below AckedSequence
+type AckExporter interface {
+	Exporter
+
+	// AckedSequence returns the highest batch sequence acknowledged by the receiver
+	// (0 if none); batches at or below it are never resent by the exporter
+	AckedSequence() uint64
+}
+
+// SetSequenceStore persists batch sequence numbers in store; the first cycle
+// continues from the stored sequence instead of 1
+func (s *ExportScheduler) SetSequenceStore(store SequenceStore) {
+	s.sequenceMu.Lock()
+	defer s.sequenceMu.Unlock()
+	s.sequenceStore = store
+	s.sequenceLoaded = false
+}
+
+// ApplySequence applies the sequence file from config
+func (s *ExportScheduler) ApplySequence(config *ExportConfig) {
+	if config.SequenceFile != "" {
+		s.SetSequenceStore(NewFileSequenceStore(config.SequenceFile))
+	}
+}
+
+// Sequence returns the sequence number of the last exported batch
+func (s *ExportScheduler) Sequence() uint64 {
+	s.sequenceMu.Lock()
+	defer s.sequenceMu.Unlock()
+	return s.sequence
+}
+
+// AckedSequence returns the highest batch sequence acknowledged by every AckExporter,
+// the point up to which a spool can be trimmed; ok is false without AckExporters
+func (s *ExportScheduler) AckedSequence() (seq uint64, ok bool) {
+	s.mu.RLock()
+	exporters := make([]Exporter, len(s.exporters))
+	copy(exporters, s.exporters)
+	s.mu.RUnlock()
+
+	for _, exporter := range exporters {
+		ackExporter, isAck := exporter.(AckExporter)
+		if !isAck {
+			continue
+		}
+		acked := ackExporter.AckedSequence()
+		if !ok || acked < seq {
+			seq = acked
+		}
+		ok = true
+	}
+	return seq, ok
+}
+
+// nextSequence assigns the next batch sequence, persisting it before it is used
+// A failed save is logged; the batch is still exported
+func (s *ExportScheduler) nextSequence() uint64 {
+	s.sequenceMu.Lock()
+	defer s.sequenceMu.Unlock()
+
+	if s.sequenceStore != nil && !s.sequenceLoaded {
+		s.sequenceLoaded = true
+		stored, err := s.sequenceStore.Load()
+		if err != nil {
+			s.logger.Warnw("Failed to load persisted batch sequence", "error", err)
+		}
+		if stored > s.sequence {
+			s.sequence = stored
+			s.logger.Infow("Continuing batch sequence from persisted state", "sequence", stored)
+		}
+	}
+
+	s.sequence++
+	if s.sequenceStore != nil {
+		if err := s.sequenceStore.Save(s.sequence); err != nil {
+			s.logger.Warnw("Failed to persist batch sequence, it may repeat after a restart",
+				"sequence", s.sequence,
+				"error", err)
+		}
+	}
+	return s.sequence
+}
+
+// SequenceTracker detects gaps and duplicates in the batch sequences a receiver
+// gets from each source (e.g. a hostname)
+type SequenceTracker struct {
+	mu      sync.Mutex
+	sources map[string]trackedSequence
+}
+
+// trackedSequence is the last sequence received from a source and its session
+type trackedSequence struct {
+	session string
+	last    uint64
+}
+
+// NewSequenceTracker creates an empty sequence tracker
+func NewSequenceTracker() *SequenceTracker {
+	return &SequenceTracker{sources: make(map[string]trackedSequence)}
+}
+
+// Observe records seq from source and returns how many sequences were skipped
+// since the previous one and whether seq was already received. A new session
+// continuing a persisted sequence is checked for gaps; one restarting below the
+// last sequence (no sequence store) starts over
+func (t *SequenceTracker) Observe(source, session string, seq uint64) (gap uint64, duplicate bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, seen := t.sources[source]
+	switch {
+	case !seen:
+	case seq > prev.last:
+		gap = seq - prev.last - 1
+	case session == prev.session:
+		return 0, true
+	}
+	t.sources[source] = trackedSequence{session: session, last: seq}
+	return gap, false
+}
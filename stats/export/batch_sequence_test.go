@@ -0,0 +1,138 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestExportScheduler_BatchSequence tests cycles carry increasing sequences that survive a restart
+func TestExportScheduler_BatchSequence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "sequence")
+	collector := &mockStatsCollector{stats: &statsmodel.ServiceStats{ServiceName: "eir", UptimeSeconds: 60}}
+
+	newScheduler := func(exporter Exporter) *ExportScheduler {
+		scheduler := NewExportScheduler(time.Minute, collector, NewTransformer("eir-1", "EIR"), &mockLogger{})
+		scheduler.ApplySequence(&ExportConfig{SequenceFile: path})
+		scheduler.AddExporter(exporter)
+		return scheduler
+	}
+
+	first := NewMemoryExporter("memory")
+	scheduler := newScheduler(first)
+	scheduler.exportCycle(context.Background())
+	scheduler.exportCycle(context.Background())
+	if batches := first.Batches(); len(batches) != 2 || batches[0].Sequence != 1 || batches[1].Sequence != 2 {
+		t.Fatalf("Expected sequences 1 and 2, got %+v", batches)
+	}
+
+	// A restarted scheduler continues after the persisted sequence
+	restarted := NewMemoryExporter("memory")
+	scheduler = newScheduler(restarted)
+	scheduler.exportCycle(context.Background())
+	if batch, _ := restarted.LastBatch(); batch.Sequence != 3 || scheduler.Sequence() != 3 {
+		t.Errorf("Expected sequence 3 after restart, got %d", batch.Sequence)
+	}
+	if seq, err := NewFileSequenceStore(path).Load(); err != nil || seq != 3 {
+		t.Errorf("Load() = %d, %v, want 3", seq, err)
+	}
+}
+
+// TestHTTPExporter_Ack tests the scheduler's sequence is sent and acknowledged batches are tracked
+func TestHTTPExporter_Ack(t *testing.T) {
+	sink := NewMemoryExporter("sink")
+	aggregator, _ := NewAggregatorServer(AggregatorServerConfig{Exporters: []Exporter{sink}}, &mockLogger{})
+	server := httptest.NewServer(aggregator)
+	defer server.Close()
+
+	exporter, err := NewHTTPExporter(HTTPExporterConfig{Name: "http", URL: server.URL, RetryAttempts: 1}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewHTTPExporter() error = %v", err)
+	}
+	memory := NewMemoryExporter("memory")
+	scheduler := NewExportScheduler(time.Minute, &mockStatsCollector{}, NewTransformer("eir-1", "EIR"), &mockLogger{})
+	scheduler.AddExporter(exporter)
+	scheduler.AddExporter(memory)
+
+	records := func(value uint64) []MetricRecord {
+		return []MetricRecord{{CounterID: CounterTotalRequests, Value: value, Hostname: "eir-1", Timestamp: time.Now()}}
+	}
+	for _, seq := range []uint64{1, 2, 5} {
+		if err := exporter.Export(ContextWithBatchSequence(context.Background(), seq), records(seq)); err != nil {
+			t.Fatalf("Export(%d) error = %v", seq, err)
+		}
+	}
+	if got := exporter.AckedSequence(); got != 5 {
+		t.Errorf("AckedSequence() = %d, want 5", got)
+	}
+	if got := aggregator.Stats().Gaps; got != 2 {
+		t.Errorf("Expected the aggregator to detect 2 missing batches, got %d", got)
+	}
+
+	// The memory exporter doesn't acknowledge, so only the HTTP exporter bounds trimming
+	if seq, ok := scheduler.AckedSequence(); !ok || seq != 5 {
+		t.Errorf("Scheduler AckedSequence() = %d, %v, want 5", seq, ok)
+	}
+
+	// Without a context sequence the exporter continues its own counter; failures aren't acknowledged
+	server.Close()
+	if err := exporter.Export(context.Background(), records(6)); err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if got := exporter.AckedSequence(); got != 5 {
+		t.Errorf("Expected a failed batch not to be acknowledged, got %d", got)
+	}
+}
+
+// TestSequenceTracker tests gap, duplicate and session restart detection
+func TestSequenceTracker(t *testing.T) {
+	tracker := NewSequenceTracker()
+
+	steps := []struct {
+		session   string
+		seq       uint64
+		gap       uint64
+		duplicate bool
+	}{
+		{"a", 7, 0, false},  // First sequence from a source has nothing to compare against
+		{"a", 8, 0, false},  // Next in order
+		{"a", 8, 0, true},   // Resent batch
+		{"a", 11, 2, false}, // 9 and 10 lost
+		{"b", 12, 0, false}, // Restart continuing a persisted sequence
+		{"c", 1, 0, false},  // Restart without a sequence store starts over
+		{"c", 3, 1, false},
+	}
+	for _, step := range steps {
+		gap, duplicate := tracker.Observe("eir-1", step.session, step.seq)
+		if gap != step.gap || duplicate != step.duplicate {
+			t.Errorf("Observe(%s, %d) = %d, %v, want %d, %v", step.session, step.seq, gap, duplicate, step.gap, step.duplicate)
+		}
+	}
+}
+
+// failingSequenceStore fails every call
+type failingSequenceStore struct{}
+
+func (failingSequenceStore) Load() (uint64, error) { return 0, errors.New("read-only") }
+func (failingSequenceStore) Save(uint64) error     { return errors.New("read-only") }
+
+// TestExportScheduler_SequenceStoreFailure tests batches are still exported when persisting fails
+func TestExportScheduler_SequenceStoreFailure(t *testing.T) {
+	exporter := NewMemoryExporter("memory")
+	scheduler := NewExportScheduler(time.Minute, &mockStatsCollector{stats: &statsmodel.ServiceStats{UptimeSeconds: 1}}, NewTransformer("eir-1", "EIR"), &mockLogger{})
+	scheduler.SetSequenceStore(failingSequenceStore{})
+	scheduler.AddExporter(exporter)
+
+	scheduler.exportCycle(context.Background())
+	if batch, ok := exporter.LastBatch(); !ok || batch.Sequence != 1 {
+		t.Errorf("Expected batch 1 despite the store failure, got %+v", batch)
+	}
+	if _, ok := scheduler.AckedSequence(); ok {
+		t.Error("Expected no acknowledged sequence without AckExporters")
+	}
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hsdfat/telco/version"
+)
+
+var (
+	registeredMu       sync.RWMutex
+	registeredCounters []CounterMetadata
+)
+
+// RegisterCounter adds a service-specific counter to the catalog
+// IDs must be positive and not clash with built-in or previously registered counters
+func RegisterCounter(m CounterMetadata) error {
+	if m.ID <= 0 {
+		return fmt.Errorf("invalid counter ID %d", m.ID)
+	}
+	if m.Name == "" {
+		return fmt.Errorf("counter %d: name is required", m.ID)
+	}
+
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+
+	for _, existing := range append(builtinCounterMetadata(), registeredCounters...) {
+		if existing.ID == m.ID {
+			return fmt.Errorf("counter ID %d already defined as %s", m.ID, existing.Name)
+		}
+	}
+	registeredCounters = append(registeredCounters, m)
+	return nil
+}
+
+// registeredCounterMetadata returns a copy of the registered counters
+func registeredCounterMetadata() []CounterMetadata {
+	registeredMu.RLock()
+	defer registeredMu.RUnlock()
+	return append([]CounterMetadata(nil), registeredCounters...)
+}
+
+// CounterCatalog is the dictionary NMS integrators use to decode exported records
+type CounterCatalog struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Version     string            `json:"version,omitempty"` // version.CounterCatalogVersion of the binary
+	Counters    []CounterMetadata `json:"counters"`
+
+	// CauseCodes maps each CauseCode dimension (source, operation, peer, ...) to its name -> code table
+	CauseCodes map[string]map[string]int `json:"cause_codes"`
+}
+
+// NewCounterCatalog builds the catalog, with units reflecting scaling rules (nil for none)
+func NewCounterCatalog(scaling map[int]ScalingRule) CounterCatalog {
+	counters := ScaledCounterMetadata(scaling)
+	sort.Slice(counters, func(i, j int) bool { return counters[i].ID < counters[j].ID })
+
+	return CounterCatalog{
+		GeneratedAt: time.Now(),
+		Version:     version.CounterCatalogVersion,
+		Counters:    counters,
+		CauseCodes: map[string]map[string]int{
+			"source":            copyCodes(SourceCauseCodes),
+			"operation":         copyCodes(OperationCauseCodes),
+			"peer":              PeerCauseCodes.Codes(),
+			"listener":          ListenerCauseCodes.Codes(),
+			"config_provider":   ConfigProviderCauseCodes.Codes(),
+			"db_operation":      copyCodes(DBOperationCauseCodes),
+			"db_table":          DBTableCauseCodes.Codes(),
+			"status_transition": copyCodes(StatusTransitionCounters),
+			"health_subsystem":  copyCodes(HealthSubsystemCauseCodes),
+		},
+	}
+}
+
+// copyCodes copies a cause code table
+func copyCodes(codes map[string]int) map[string]int {
+	out := make(map[string]int, len(codes))
+	for k, v := range codes {
+		out[k] = v
+	}
+	return out
+}
+
+// WriteJSON writes the catalog as indented JSON
+func (c CounterCatalog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteCSV writes the counters as CSV with a header row; cause code tables are not included
+func (c CounterCatalog) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "name", "unit", "type", "description"})
+	for _, m := range c.Counters {
+		cw.Write([]string{strconv.Itoa(m.ID), m.Name, m.Unit, m.Type, m.Description})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CatalogHandler serves the counter catalog as JSON, or as CSV with ?format=csv
+// or an Accept: text/csv header
+func CatalogHandler(scaling map[int]ScalingRule) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		catalog := NewCounterCatalog(scaling)
+		if r.URL.Query().Get("format") == "csv" || strings.Contains(r.Header.Get("Accept"), "text/csv") {
+			w.Header().Set("Content-Type", "text/csv")
+			catalog.WriteCSV(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		catalog.WriteJSON(w)
+	})
+}
+
+// CatalogExporter is implemented by exporters that can publish the counter catalog
+// The scheduler sends the catalog once at startup when enabled with SetExportCatalog,
+// and on start and reconnect to exporters enabled with SetExporterHandshake
+type CatalogExporter interface {
+	ExportCatalog(ctx context.Context, catalog CounterCatalog) error
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+	"github.com/hsdfat/telco/version"
+)
+
+func TestRegisterCounter(t *testing.T) {
+	t.Cleanup(func() { registeredCounters = nil })
+
+	if err := RegisterCounter(CounterMetadata{9001, "sms_sent", "SMS messages sent", "count", "counter"}); err != nil {
+		t.Fatalf("RegisterCounter failed: %v", err)
+	}
+	if err := RegisterCounter(CounterMetadata{9001, "dup", "", "count", "counter"}); err == nil {
+		t.Error("Expected error for a registered ID")
+	}
+	if err := RegisterCounter(CounterMetadata{CounterTotalRequests, "dup", "", "count", "counter"}); err == nil {
+		t.Error("Expected error for a built-in ID")
+	}
+	if GetCounterName(9001) != "sms_sent" {
+		t.Errorf("Expected registered counter in metadata, got %q", GetCounterName(9001))
+	}
+}
+
+func TestCatalogHandler(t *testing.T) {
+	version.CounterCatalogVersion = "7"
+	t.Cleanup(func() { version.CounterCatalogVersion = "" })
+	handler := CatalogHandler(map[int]ScalingRule{CounterBytesSent: ScaleBytesToKB})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/counters", nil))
+	var catalog CounterCatalog
+	if err := json.Unmarshal(rec.Body.Bytes(), &catalog); err != nil {
+		t.Fatalf("Invalid JSON catalog: %v", err)
+	}
+	if len(catalog.Counters) != len(GetCounterMetadata()) {
+		t.Errorf("Expected %d counters, got %d", len(GetCounterMetadata()), len(catalog.Counters))
+	}
+	if catalog.CauseCodes["source"]["diameter"] != SourceCauseCodes["diameter"] {
+		t.Errorf("Expected source cause codes, got %v", catalog.CauseCodes["source"])
+	}
+	if catalog.Version != "7" {
+		t.Errorf("Expected catalog version 7, got %q", catalog.Version)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/counters?format=csv", nil))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if lines[0] != "id,name,unit,type,description" || len(lines) != len(catalog.Counters)+1 {
+		t.Errorf("Unexpected CSV header or length: %q (%d lines)", lines[0], len(lines))
+	}
+	if !strings.Contains(rec.Body.String(), "1004,bytes_sent,kilobytes,counter,") {
+		t.Error("Expected scaled unit in CSV catalog")
+	}
+}
+
+// TestExportScheduler_Catalog tests the catalog is written once at startup when enabled
+func TestExportScheduler_Catalog(t *testing.T) {
+	dir := t.TempDir()
+	exporter, err := NewFileExporter(FileExporterConfig{Name: "file", Path: filepath.Join(dir, "metrics.jsonl")}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewFileExporter failed: %v", err)
+	}
+
+	clock := statsmodel.NewFakeClock(time.Now())
+	scheduler := NewExportSchedulerWithProvider(time.Minute, StatsFunc(func() *statsmodel.ServiceStats { return nil }), NewTransformer("h", "EIR"), &mockLogger{})
+	scheduler.SetClock(clock)
+	scheduler.SetExportCatalog(true)
+	scheduler.AddExporter(exporter)
+
+	scheduler.Start(context.Background())
+	for clock.TickerCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	scheduler.Stop()
+
+	data, err := os.ReadFile(filepath.Join(dir, "metrics.catalog.json"))
+	if err != nil {
+		t.Fatalf("Expected catalog file: %v", err)
+	}
+	var catalog CounterCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil || len(catalog.Counters) == 0 {
+		t.Errorf("Invalid catalog file: %v", err)
+	}
+}
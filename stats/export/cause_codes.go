@@ -0,0 +1,90 @@
+package export
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CauseCodeRegistry maps the names of a runtime dimension (peers, listeners, config
+// providers, tables) to the CauseCode used on their records
+// Services may register fixed codes; names seen without one are assigned the next free
+// code, so new peers or tables are exported rather than dropped. The mapping is sent
+// in the counter catalog. Safe for concurrent use
+type CauseCodeRegistry struct {
+	mu    sync.RWMutex
+	codes map[string]int
+	used  map[int]string
+	next  int
+}
+
+// NewCauseCodeRegistry creates an empty registry
+func NewCauseCodeRegistry() *CauseCodeRegistry {
+	return &CauseCodeRegistry{codes: make(map[string]int), used: make(map[int]string), next: 1}
+}
+
+// Register assigns a fixed code to name, replacing any previous code
+// Codes must be positive and not used by another name
+func (r *CauseCodeRegistry) Register(name string, code int) error {
+	if code <= 0 {
+		return fmt.Errorf("invalid cause code %d for %s", code, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.used[code]; ok && existing != name {
+		return fmt.Errorf("cause code %d already assigned to %s", code, existing)
+	}
+	if old, ok := r.codes[name]; ok {
+		delete(r.used, old)
+	}
+	r.codes[name] = code
+	r.used[code] = name
+	return nil
+}
+
+// Unregister removes name and frees its code
+func (r *CauseCodeRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if code, ok := r.codes[name]; ok {
+		delete(r.codes, name)
+		delete(r.used, code)
+	}
+}
+
+// Code returns the code of name, assigning the next free one if it has none
+func (r *CauseCodeRegistry) Code(name string) int {
+	r.mu.RLock()
+	code, ok := r.codes[name]
+	r.mu.RUnlock()
+	if ok {
+		return code
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if code, ok := r.codes[name]; ok {
+		return code
+	}
+	for {
+		if _, taken := r.used[r.next]; !taken {
+			break
+		}
+		r.next++
+	}
+	code = r.next
+	r.next++
+	r.codes[name] = code
+	r.used[code] = name
+	return code
+}
+
+// Codes returns a copy of the name -> code table
+func (r *CauseCodeRegistry) Codes() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copyCodes(r.codes)
+}
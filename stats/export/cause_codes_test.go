@@ -0,0 +1,71 @@
+package export
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestCauseCodeRegistry tests fixed and assigned codes
+func TestCauseCodeRegistry(t *testing.T) {
+	r := NewCauseCodeRegistry()
+
+	if err := r.Register("hss1", 2); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := r.Register("hss2", 2); err == nil {
+		t.Error("Expected an error registering a code twice")
+	}
+	if err := r.Register("hss2", 0); err == nil {
+		t.Error("Expected an error registering code 0")
+	}
+
+	// Assigned codes skip fixed ones and stay stable
+	if code := r.Code("dra1"); code != 1 {
+		t.Errorf("Expected code 1, got %d", code)
+	}
+	if code := r.Code("dra2"); code != 3 {
+		t.Errorf("Expected code 3 (2 is registered), got %d", code)
+	}
+	if code := r.Code("dra1"); code != 1 {
+		t.Errorf("Expected dra1 to keep code 1, got %d", code)
+	}
+	if code := r.Code("hss1"); code != 2 {
+		t.Errorf("Expected hss1 to keep its fixed code, got %d", code)
+	}
+
+	r.Unregister("dra2")
+	codes := r.Codes()
+	if len(codes) != 2 || codes["hss1"] != 2 || codes["dra1"] != 1 {
+		t.Errorf("Unexpected codes %v", codes)
+	}
+}
+
+// TestCauseCodeRegistry_Concurrent tests names seen concurrently get distinct codes
+func TestCauseCodeRegistry_Concurrent(t *testing.T) {
+	r := NewCauseCodeRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				r.Code(fmt.Sprintf("peer-%d", j))
+				_ = r.Codes()
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for name, code := range r.Codes() {
+		if seen[code] {
+			t.Errorf("Code %d assigned twice (%s)", code, name)
+		}
+		seen[code] = true
+	}
+	if len(seen) != 50 {
+		t.Errorf("Expected 50 codes, got %d", len(seen))
+	}
+}
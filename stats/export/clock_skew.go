@@ -0,0 +1,72 @@
+package export
+
+import (
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// SetClockSkewThreshold sets the wall clock step between export cycles that flags
+// the cycle's records as ClockSkewed (default: statsmodel.DefaultClockSkewThreshold,
+// negative disables detection)
+func (s *ExportScheduler) SetClockSkewThreshold(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d < 0 {
+		s.clockSkew = nil
+		return
+	}
+	s.clockSkew = statsmodel.NewClockSkewDetector(d)
+}
+
+// ApplyClockSkew applies the clock skew threshold from config
+func (s *ExportScheduler) ApplyClockSkew(config *ExportConfig) {
+	if config.ClockSkewThreshold != 0 {
+		s.SetClockSkewThreshold(config.ClockSkewThreshold)
+	}
+}
+
+// ClockSkew returns the wall clock skew accumulated since the first export cycle
+// and the number of cycles that saw a step over the threshold
+func (s *ExportScheduler) ClockSkew() (total time.Duration, steps uint64) {
+	s.mu.RLock()
+	detector := s.clockSkew
+	s.mu.RUnlock()
+	if detector == nil {
+		return 0, 0
+	}
+	return detector.Skew()
+}
+
+// observeClockSkew samples the skew detector at the start of a cycle
+// Times without a monotonic reading (fake clocks) are never sampled
+func (s *ExportScheduler) observeClockSkew(now time.Time) (statsmodel.ClockSkew, bool) {
+	s.mu.RLock()
+	detector := s.clockSkew
+	s.mu.RUnlock()
+	if detector == nil {
+		return statsmodel.ClockSkew{}, false
+	}
+
+	skew, ok := detector.Observe(now)
+	if ok && skew.Stepped {
+		s.logger.Warnw("Wall clock stepped between export cycles, record periods may be shifted",
+			"step", skew.Step.String(),
+			"total_skew", skew.Total.String(),
+			"steps", skew.Steps)
+	}
+	return skew, ok
+}
+
+// clockSkewRecords returns the skew gauge and, if the wall clock stepped, the step counter
+func (t *Transformer) clockSkewRecords(skew statsmodel.ClockSkew, timestamp time.Time) []MetricRecord {
+	total := skew.Total
+	if total < 0 {
+		total = -total
+	}
+	records := []MetricRecord{t.createRecord(CounterClockSkewMs, uint64(total.Milliseconds()), 0, timestamp)}
+	if skew.Stepped {
+		records = append(records, t.createRecord(CounterClockSteps, 1, 0, timestamp))
+	}
+	return t.scaleRecords(t.filterRecords(records))
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestClockSkewDetector tests wall clock steps are measured against monotonic time
+func TestClockSkewDetector(t *testing.T) {
+	detector := statsmodel.NewClockSkewDetector(time.Second)
+	wall := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	if _, ok := detector.ObserveAt(wall, 0); ok {
+		t.Error("Expected the first sample to have nothing to compare against")
+	}
+
+	// 15 minutes on both clocks: no skew
+	skew, ok := detector.ObserveAt(wall.Add(15*time.Minute), 15*time.Minute)
+	if !ok || skew.Step != 0 || skew.Stepped {
+		t.Errorf("Expected no skew, got %+v", skew)
+	}
+
+	// NTP steps the wall clock 40s forward during the next interval
+	skew, _ = detector.ObserveAt(wall.Add(30*time.Minute+40*time.Second), 30*time.Minute)
+	if skew.Step != 40*time.Second || !skew.Stepped || skew.Steps != 1 {
+		t.Errorf("Expected a 40s step, got %+v", skew)
+	}
+
+	// A sub-threshold correction back accumulates without flagging
+	skew, _ = detector.ObserveAt(wall.Add(45*time.Minute+39500*time.Millisecond), 45*time.Minute)
+	if skew.Stepped || skew.Total != 39500*time.Millisecond || skew.Steps != 1 {
+		t.Errorf("Expected 39.5s total skew without a new step, got %+v", skew)
+	}
+
+	if total, steps := detector.Skew(); total != 39500*time.Millisecond || steps != 1 {
+		t.Errorf("Skew() = %v, %d", total, steps)
+	}
+}
+
+// TestClockSkewDetector_Observe tests times without a monotonic reading are ignored
+func TestClockSkewDetector_Observe(t *testing.T) {
+	detector := statsmodel.NewClockSkewDetector(0)
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	if _, ok := detector.Observe(clock.Now()); ok {
+		t.Error("Expected fake clock times to be ignored")
+	}
+	clock.Advance(time.Hour)
+	if _, ok := detector.Observe(clock.Now()); ok {
+		t.Error("Expected fake clock times to be ignored")
+	}
+
+	detector.Observe(time.Now())
+	skew, ok := detector.Observe(time.Now())
+	if !ok || skew.Stepped {
+		t.Errorf("Expected a real clock sample without a step, got %+v, %v", skew, ok)
+	}
+}
+
+// TestExportScheduler_ClockSkew tests the skew gauge is exported and the threshold can be disabled
+func TestExportScheduler_ClockSkew(t *testing.T) {
+	exporter := NewMemoryExporter("memory")
+	collector := &mockStatsCollector{stats: &statsmodel.ServiceStats{ServiceName: "eir"}}
+	scheduler := NewExportScheduler(time.Minute, collector, NewTransformer("eir-1", "EIR"), &mockLogger{})
+	scheduler.AddExporter(exporter)
+
+	scheduler.exportCycle(context.Background())
+	scheduler.exportCycle(context.Background())
+
+	var found bool
+	for _, r := range exporter.Records() {
+		if r.CounterID == CounterClockSkewMs {
+			found = true
+		}
+		if r.CounterID == CounterClockSteps || r.ClockSkewed {
+			t.Errorf("Unexpected clock step record %+v", r)
+		}
+	}
+	if !found {
+		t.Error("Expected a clock skew gauge once two cycles were sampled")
+	}
+
+	scheduler.ApplyClockSkew(&ExportConfig{ClockSkewThreshold: -1})
+	if total, steps := scheduler.ClockSkew(); total != 0 || steps != 0 {
+		t.Errorf("Expected disabled detection, got %v, %d", total, steps)
+	}
+
+	records := NewTransformer("eir-1", "EIR").clockSkewRecords(statsmodel.ClockSkew{Total: -2500 * time.Millisecond, Stepped: true}, time.Now())
+	if len(records) != 2 || records[0].Value != 2500 || records[1].CounterID != CounterClockSteps {
+		t.Errorf("Expected absolute skew and a step, got %+v", records)
+	}
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"testing"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestCollector_EquipmentChecks tests requests only count as equipment checks on the EIR check path
+func TestCollector_EquipmentChecks(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "DIAM-GW"})
+	collector.RecordRequest("diameter", true)
+	collector.BeginRequest("http")
+	collector.EndRequest("http", false)
+
+	stats := collector.Snapshot()
+	if stats.Requests.Total != 2 {
+		t.Errorf("Requests.Total = %d, want 2", stats.Requests.Total)
+	}
+	if _, ok := stats.CustomMetrics["eir"]; ok {
+		t.Errorf("Expected no eir section without equipment checks, got %+v", stats.CustomMetrics["eir"])
+	}
+
+	collector.RecordRequest("diameter", true)
+	collector.RecordEquipmentCheck("diameter", true)
+	collector.RecordRequest("diameter", false)
+	collector.RecordEquipmentCheck("diameter", false)
+
+	stats = collector.Snapshot()
+	if stats.Requests.Total != 4 {
+		t.Errorf("Requests.Total = %d, want 4", stats.Requests.Total)
+	}
+	eir, ok := stats.CustomMetrics.EIR()
+	if !ok {
+		t.Fatalf("Expected an eir section, got %+v", stats.CustomMetrics)
+	}
+	checks := eir.EquipmentChecks
+	if checks.Total != 2 || checks.Success != 1 || checks.Failed != 1 {
+		t.Errorf("EquipmentChecks = %d/%d/%d, want 2/1/1", checks.Total, checks.Success, checks.Failed)
+	}
+	if diameter := checks.ByInterface["diameter"]; diameter.Total != 2 || diameter.Success != 1 || diameter.Failed != 1 {
+		t.Errorf("EquipmentChecks.ByInterface[diameter] = %+v, want 2 checks, 1 failed", diameter)
+	}
+	if _, ok := checks.ByInterface["http"]; ok {
+		t.Errorf("Expected no http equipment checks, got %+v", checks.ByInterface["http"])
+	}
+}
+
+// TestCollector_TACEviction tests the least recently checked TAC is evicted, so a
+// newly seen TAC survives the next new one
+func TestCollector_TACEviction(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{MaxTACs: 2})
+	const (
+		tacA = "35123456"
+		tacB = "35234567"
+		tacC = "35345678"
+		tacD = "35456789"
+	)
+	for i := 0; i < 3; i++ {
+		collector.RecordTACCheck(tacA+"0000001", "whitelisted")
+	}
+	collector.RecordTACCheck(tacB+"0000001", "whitelisted")
+	collector.RecordTACCheck(tacA+"0000002", "blacklisted")
+
+	collector.RecordTACCheck(tacC+"0000001", "whitelisted") // Evicts B, checked before A
+	collector.RecordTACCheck(tacD+"0000001", "greylisted")  // Evicts A, checked before C
+
+	eir, _ := collector.Snapshot().CustomMetrics.EIR()
+	if len(eir.ByTAC) != 2 {
+		t.Fatalf("ByTAC = %+v, want 2 TACs", eir.ByTAC)
+	}
+	for _, tac := range []string{tacC, tacD} {
+		if eir.ByTAC[tac].Checks != 1 {
+			t.Errorf("ByTAC[%s] = %+v, want 1 check", tac, eir.ByTAC[tac])
+		}
+	}
+	if _, ok := eir.ByTAC[tacA]; ok {
+		t.Errorf("Expected %s evicted despite more checks, got %+v", tacA, eir.ByTAC[tacA])
+	}
+
+	// A re-seen TAC starts over
+	collector.RecordTACCheck(tacA+"0000003", "whitelisted")
+	eir, _ = collector.Snapshot().CustomMetrics.EIR()
+	if a := eir.ByTAC[tacA]; a.Checks != 1 || a.ByStatus["whitelisted"] != 1 {
+		t.Errorf("ByTAC[%s] = %+v, want 1 whitelisted check", tacA, a)
+	}
+	if _, ok := eir.ByTAC[tacC]; ok {
+		t.Errorf("Expected %s evicted, got %+v", tacC, eir.ByTAC[tacC])
+	}
+}
@@ -0,0 +1,422 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// CompactionConfig defines configuration for CompactArchive
+type CompactionConfig struct {
+	// Path is the FileExporter path; rotated backups next to it are compacted
+	Path string `json:"path"`
+
+	// OutputDir receives the aggregate files (default: "<dir of Path>/aggregates")
+	OutputDir string `json:"output_dir"`
+
+	// Resolution is the aggregation window, typically time.Hour or 24*time.Hour (default: 1h)
+	// Use a separate OutputDir per resolution
+	Resolution time.Duration `json:"resolution"`
+
+	// Retention deletes compacted raw files last modified longer ago than this (0 = keep)
+	Retention time.Duration `json:"retention"`
+
+	// Clock drives retention (default: SystemClock)
+	Clock statsmodel.Clock `json:"-"`
+}
+
+// CompactionReport summarizes a CompactArchive run
+type CompactionReport struct {
+	FilesCompacted    int `json:"files_compacted"`
+	RecordsRead       int `json:"records_read"`
+	AggregatesWritten int `json:"aggregates_written"`
+	FilesDeleted      int `json:"files_deleted"`
+}
+
+// compactionState is the file listing raw files already compacted
+const compactionState = ".compacted"
+
+// CompactArchive downsamples rotated JSONL files written by FileExporter into
+// AggregatedMetricRecords, one output file per day, and deletes compacted raw files
+// past the retention window
+//
+// Counters are summed per window; gauges and rates keep the average in Value and the
+// highest sample in Max. Each raw file is compacted once, so the function can run
+// periodically. Set FileExporterConfig.MaxBackups to 0 so rotation doesn't delete
+// files before they are compacted.
+func CompactArchive(config CompactionConfig, logger Logger) (CompactionReport, error) {
+	var report CompactionReport
+	if config.Path == "" {
+		return report, fmt.Errorf("compaction path is required")
+	}
+	if config.OutputDir == "" {
+		config.OutputDir = filepath.Join(filepath.Dir(config.Path), "aggregates")
+	}
+	if config.Resolution == 0 {
+		config.Resolution = time.Hour
+	}
+	if config.Clock == nil {
+		config.Clock = statsmodel.SystemClock
+	}
+
+	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+		return report, fmt.Errorf("failed to create directory %s: %w", config.OutputDir, err)
+	}
+
+	files, err := rotatedFiles(config.Path)
+	if err != nil {
+		return report, err
+	}
+
+	statePath := filepath.Join(config.OutputDir, compactionState)
+	compacted, err := readCompactionState(statePath)
+	if err != nil {
+		return report, err
+	}
+
+	// Aggregate every new raw file
+	buckets := make(map[aggregateKey]*AggregatedMetricRecord)
+	kinds := counterKinds()
+	for _, file := range files {
+		name := filepath.Base(file)
+		if compacted[name] {
+			continue
+		}
+
+		records, err := readRecords(file)
+		if err != nil {
+			return report, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		for _, record := range records {
+			addSample(buckets, kinds, record, config.Resolution)
+		}
+		compacted[name] = true
+		report.FilesCompacted++
+		report.RecordsRead += len(records)
+	}
+
+	written, err := writeAggregates(config, buckets, kinds)
+	if err != nil {
+		return report, err
+	}
+	report.AggregatesWritten = written
+
+	// Record progress before deleting anything
+	if err := writeCompactionState(statePath, compacted); err != nil {
+		return report, err
+	}
+
+	if config.Retention > 0 {
+		cutoff := config.Clock.Now().Add(-config.Retention)
+		for _, file := range files {
+			name := filepath.Base(file)
+			info, err := os.Stat(file)
+			if err != nil || !compacted[name] || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if err := os.Remove(file); err != nil {
+				logger.Warnw("Failed to delete compacted metrics file",
+					"file", file,
+					"error", err)
+				continue
+			}
+			delete(compacted, name)
+			report.FilesDeleted++
+		}
+		if err := writeCompactionState(statePath, compacted); err != nil {
+			return report, err
+		}
+	}
+
+	logger.Infow("Compacted metrics archive",
+		"path", config.Path,
+		"files_compacted", report.FilesCompacted,
+		"records_read", report.RecordsRead,
+		"aggregates_written", report.AggregatesWritten,
+		"files_deleted", report.FilesDeleted)
+	return report, nil
+}
+
+// rotatedFiles returns the rotated backups of path, as named by lumberjack
+func rotatedFiles(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext) + "-"
+
+	var files []string
+	for _, pattern := range []string{prefix + "*" + ext, prefix + "*" + ext + ".gz"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive path %s: %w", path, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readRecords reads a JSONL file, transparently decompressing files with the
+// extension of a registered FileCodec
+func readRecords(path string) ([]MetricRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if codec := fileCodecForPath(path); codec != nil {
+		cr, err := codec.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer cr.Close()
+		r = cr
+	}
+
+	var records []MetricRecord
+	dec := json.NewDecoder(r)
+	for {
+		var record MetricRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// aggregateKey identifies one aggregate window
+type aggregateKey struct {
+	hostname   string
+	systemName string
+	counterID  int
+	causeCode  int
+	tenant     string
+	window     int64
+}
+
+// counterKinds maps counter IDs to their CounterMetadata type
+func counterKinds() map[int]string {
+	kinds := make(map[int]string)
+	for _, m := range GetCounterMetadata() {
+		kinds[m.ID] = m.Type
+	}
+	return kinds
+}
+
+// isGauge reports whether samples of counterID are averaged rather than summed
+func isGauge(kinds map[int]string, counterID int) bool {
+	kind := kinds[counterID]
+	return kind == "gauge" || kind == "rate"
+}
+
+// addSample folds record into its window
+func addSample(buckets map[aggregateKey]*AggregatedMetricRecord, kinds map[int]string, record MetricRecord, resolution time.Duration) {
+	start := record.Timestamp.Truncate(resolution)
+	key := aggregateKey{record.Hostname, record.SystemName, record.CounterID, record.CauseCode, record.Tenant, start.UnixNano()}
+
+	agg, ok := buckets[key]
+	if !ok {
+		agg = &AggregatedMetricRecord{
+			MetricRecord: record,
+			WindowStart:  start,
+			WindowEnd:    start.Add(resolution),
+		}
+		agg.Value = 0
+		agg.Timestamp = start
+		buckets[key] = agg
+	}
+	merge(agg, AggregatedMetricRecord{MetricRecord: record, SampleCount: 1, Max: record.Value}, isGauge(kinds, record.CounterID))
+}
+
+// merge folds src into dst
+func merge(dst *AggregatedMetricRecord, src AggregatedMetricRecord, gauge bool) {
+	if gauge {
+		total := dst.Value*uint64(dst.SampleCount) + src.Value*uint64(src.SampleCount)
+		dst.Value = total / uint64(dst.SampleCount+src.SampleCount)
+		if src.Max > dst.Max {
+			dst.Max = src.Max
+		}
+	} else {
+		dst.Value += src.Value
+	}
+	dst.SampleCount += src.SampleCount
+}
+
+// writeAggregates merges buckets into the per-day aggregate files
+func writeAggregates(config CompactionConfig, buckets map[aggregateKey]*AggregatedMetricRecord, kinds map[int]string) (int, error) {
+	byDay := make(map[string][]*AggregatedMetricRecord)
+	for _, agg := range buckets {
+		day := agg.WindowStart.UTC().Format("2006-01-02")
+		byDay[day] = append(byDay[day], agg)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(config.Path), filepath.Ext(config.Path))
+	written := 0
+	for day, aggs := range byDay {
+		path := filepath.Join(config.OutputDir, fmt.Sprintf("%s-%s-%s.jsonl", base, resolutionName(config.Resolution), day))
+
+		// Merge with windows from earlier runs
+		existing, err := readAggregates(path)
+		if err != nil {
+			return written, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		merged := make(map[aggregateKey]*AggregatedMetricRecord, len(existing)+len(aggs))
+		for i := range existing {
+			agg := &existing[i]
+			merged[aggregateKeyOf(agg)] = agg
+		}
+		for _, agg := range aggs {
+			key := aggregateKeyOf(agg)
+			if prev, ok := merged[key]; ok {
+				merge(prev, *agg, isGauge(kinds, agg.CounterID))
+			} else {
+				merged[key] = agg
+			}
+		}
+
+		out := make([]*AggregatedMetricRecord, 0, len(merged))
+		for _, agg := range merged {
+			out = append(out, agg)
+		}
+		sort.Slice(out, func(i, j int) bool {
+			a, b := out[i], out[j]
+			if !a.WindowStart.Equal(b.WindowStart) {
+				return a.WindowStart.Before(b.WindowStart)
+			}
+			if a.Hostname != b.Hostname {
+				return a.Hostname < b.Hostname
+			}
+			if a.CounterID != b.CounterID {
+				return a.CounterID < b.CounterID
+			}
+			if a.CauseCode != b.CauseCode {
+				return a.CauseCode < b.CauseCode
+			}
+			return a.Tenant < b.Tenant
+		})
+
+		if err := writeJSONLines(path, out); err != nil {
+			return written, err
+		}
+		written += len(aggs)
+	}
+	return written, nil
+}
+
+// resolutionName names a resolution in aggregate file names
+func resolutionName(resolution time.Duration) string {
+	switch resolution {
+	case time.Hour:
+		return "hourly"
+	case 24 * time.Hour:
+		return "daily"
+	default:
+		return resolution.String()
+	}
+}
+
+// aggregateKeyOf returns the window key of an aggregate
+func aggregateKeyOf(agg *AggregatedMetricRecord) aggregateKey {
+	return aggregateKey{agg.Hostname, agg.SystemName, agg.CounterID, agg.CauseCode, agg.Tenant, agg.WindowStart.UnixNano()}
+}
+
+// readAggregates reads an aggregate file, returning nothing if it doesn't exist
+func readAggregates(path string) ([]AggregatedMetricRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var aggs []AggregatedMetricRecord
+	dec := json.NewDecoder(f)
+	for {
+		var agg AggregatedMetricRecord
+		if err := dec.Decode(&agg); err == io.EOF {
+			return aggs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		aggs = append(aggs, agg)
+	}
+}
+
+// writeJSONLines atomically replaces path with one JSON value per line
+func writeJSONLines[T any](path string, values []T) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return fmt.Errorf("failed to write %s: %w", tmp, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// readCompactionState reads the names of compacted raw files
+func readCompactionState(path string) (map[string]bool, error) {
+	compacted := make(map[string]bool)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return compacted, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compaction state: %w", err)
+	}
+	for _, name := range strings.Split(string(data), "\n") {
+		if name != "" {
+			compacted[name] = true
+		}
+	}
+	return compacted, nil
+}
+
+// writeCompactionState writes the names of compacted raw files
+func writeCompactionState(path string, compacted map[string]bool) error {
+	names := make([]string, 0, len(compacted))
+	for name := range compacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write compaction state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestCompactArchive tests rotated files are downsampled once and deleted after retention
+func TestCompactArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.jsonl")
+	hour := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	writeRaw := func(name string, records []MetricRecord) {
+		var b bytes.Buffer
+		for _, r := range records {
+			data, _ := json.Marshal(r)
+			b.Write(append(data, '\n'))
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b.Bytes(), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	record := func(counterID int, value uint64, offset time.Duration) MetricRecord {
+		return MetricRecord{CounterID: counterID, Value: value, Hostname: "eir-1", SystemName: "EIR", Timestamp: hour.Add(offset)}
+	}
+
+	writeRaw("metrics-2026-01-07T10-30-00.000.jsonl", []MetricRecord{
+		record(CounterTotalRequests, 10, 0),
+		record(CounterTotalRequests, 20, 30*time.Minute),
+		record(CounterPendingRequests, 4, 0),
+		record(CounterPendingRequests, 8, 30*time.Minute),
+	})
+	writeRaw("metrics.jsonl", []MetricRecord{record(CounterTotalRequests, 99, 0)}) // Active file is skipped
+
+	clock := statsmodel.NewFakeClock(time.Now())
+	config := CompactionConfig{Path: path, Retention: 24 * time.Hour, Clock: clock}
+	report, err := CompactArchive(config, &mockLogger{})
+	if err != nil {
+		t.Fatalf("CompactArchive failed: %v", err)
+	}
+	if report.FilesCompacted != 1 || report.RecordsRead != 4 || report.AggregatesWritten != 2 || report.FilesDeleted != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	// A second file for the same hour merges into the existing window
+	writeRaw("metrics-2026-01-07T11-00-00.000.jsonl", []MetricRecord{record(CounterTotalRequests, 5, 45*time.Minute)})
+	clock.Advance(48 * time.Hour)
+	report, err = CompactArchive(config, &mockLogger{})
+	if err != nil {
+		t.Fatalf("CompactArchive failed: %v", err)
+	}
+	if report.FilesCompacted != 1 || report.FilesDeleted != 2 {
+		t.Errorf("Unexpected second report: %+v", report)
+	}
+
+	aggs, err := readAggregates(filepath.Join(dir, "aggregates", "metrics-hourly-2026-01-07.jsonl"))
+	if err != nil {
+		t.Fatalf("readAggregates failed: %v", err)
+	}
+	if len(aggs) != 2 {
+		t.Fatalf("Expected 2 aggregates, got %+v", aggs)
+	}
+	for _, agg := range aggs {
+		switch agg.CounterID {
+		case CounterTotalRequests:
+			if agg.Value != 35 || agg.SampleCount != 3 {
+				t.Errorf("Expected summed counter 35 over 3 samples, got %+v", agg)
+			}
+		case CounterPendingRequests:
+			if agg.Value != 6 || agg.Max != 8 || agg.SampleCount != 2 {
+				t.Errorf("Expected gauge avg 6 max 8, got %+v", agg)
+			}
+		}
+		if !agg.WindowStart.Equal(hour) || !agg.WindowEnd.Equal(hour.Add(time.Hour)) {
+			t.Errorf("Unexpected window: %+v", agg)
+		}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected active file to be kept: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Composite exporter strategies
+const (
+	StrategyFailover   = "failover"    // Targets in order; the first success ends the cycle
+	StrategyRoundRobin = "round_robin" // Rotates the first target each cycle, failing over to the next
+	StrategyMirror     = "mirror"      // Every target in parallel; Quorum successes required
+)
+
+// CompositeExporterConfig defines configuration for a composite exporter
+type CompositeExporterConfig struct {
+	Name     string     `json:"name"`
+	Strategy string     `json:"strategy"` // StrategyFailover (default), StrategyRoundRobin or StrategyMirror
+	Quorum   int        `json:"quorum"`   // Mirror: successful targets required (default: all)
+	Targets  []Exporter `json:"-"`
+}
+
+// CompositeExporter presents several exporters as one logical exporter, such as a
+// primary and secondary NMS
+type CompositeExporter struct {
+	name    string
+	config  CompositeExporterConfig
+	logger  Logger
+	targets []Exporter
+
+	mu   sync.Mutex
+	next int // Round-robin: target tried first in the next cycle
+}
+
+// NewCompositeExporter creates a composite exporter over config.Targets
+func NewCompositeExporter(config CompositeExporterConfig, logger Logger) (*CompositeExporter, error) {
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("composite exporter requires at least one target")
+	}
+
+	if config.Strategy == "" {
+		config.Strategy = StrategyFailover
+	}
+	switch config.Strategy {
+	case StrategyFailover, StrategyRoundRobin:
+	case StrategyMirror:
+		if config.Quorum <= 0 {
+			config.Quorum = len(config.Targets)
+		}
+		if config.Quorum > len(config.Targets) {
+			return nil, fmt.Errorf("composite exporter quorum %d exceeds %d targets", config.Quorum, len(config.Targets))
+		}
+	default:
+		return nil, fmt.Errorf("unknown composite exporter strategy: %s", config.Strategy)
+	}
+
+	return &CompositeExporter{
+		name:    config.Name,
+		config:  config,
+		logger:  logger,
+		targets: config.Targets,
+	}, nil
+}
+
+// Export sends records to the targets according to the strategy
+func (e *CompositeExporter) Export(ctx context.Context, records []MetricRecord) error {
+	switch e.config.Strategy {
+	case StrategyMirror:
+		return e.exportMirror(ctx, records)
+	case StrategyRoundRobin:
+		e.mu.Lock()
+		start := e.next
+		e.next = (e.next + 1) % len(e.targets)
+		e.mu.Unlock()
+		return e.exportInOrder(ctx, records, start)
+	default:
+		return e.exportInOrder(ctx, records, 0)
+	}
+}
+
+// exportInOrder tries the targets starting at start until one succeeds
+func (e *CompositeExporter) exportInOrder(ctx context.Context, records []MetricRecord, start int) error {
+	var errs []error
+	for i := range e.targets {
+		target := e.targets[(start+i)%len(e.targets)]
+		err := target.Export(ctx, records)
+		if err == nil {
+			if len(errs) > 0 {
+				e.logger.Infow("Composite exporter failed over",
+					"exporter", e.name,
+					"target", target.Name(),
+					"failed_targets", len(errs))
+			}
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", target.Name(), err))
+		e.logger.Warnw("Composite exporter target failed",
+			"exporter", e.name,
+			"target", target.Name(),
+			"error", err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exportMirror sends to every target in parallel and requires a quorum of successes
+func (e *CompositeExporter) exportMirror(ctx context.Context, records []MetricRecord) error {
+	errs := make([]error, len(e.targets))
+	var wg sync.WaitGroup
+	for i, target := range e.targets {
+		wg.Add(1)
+		go func(i int, target Exporter) {
+			defer wg.Done()
+			if err := target.Export(ctx, records); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", target.Name(), err)
+			}
+		}(i, target)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		e.logger.Warnw("Composite exporter target failed",
+			"exporter", e.name,
+			"target", e.targets[i].Name(),
+			"error", err)
+	}
+
+	if succeeded < e.config.Quorum {
+		return fmt.Errorf("quorum not reached (%d/%d targets succeeded, %d required): %w",
+			succeeded, len(e.targets), e.config.Quorum, errors.Join(errs...))
+	}
+	return nil
+}
+
+// ExportCatalog sends the counter catalog to every target that accepts it
+func (e *CompositeExporter) ExportCatalog(ctx context.Context, catalog CounterCatalog) error {
+	var errs []error
+	for _, target := range e.targets {
+		if catalogExporter, ok := target.(CatalogExporter); ok {
+			if err := catalogExporter.ExportCatalog(ctx, catalog); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Name returns the exporter name
+func (e *CompositeExporter) Name() string {
+	return e.name
+}
+
+// Close closes every target
+func (e *CompositeExporter) Close() error {
+	var errs []error
+	for _, target := range e.targets {
+		if err := target.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
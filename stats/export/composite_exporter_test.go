@@ -0,0 +1,84 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestCompositeExporter_Failover tests the secondary receives records only while the primary fails
+func TestCompositeExporter_Failover(t *testing.T) {
+	primary, secondary := NewMemoryExporter("primary"), NewMemoryExporter("secondary")
+	exporter, err := NewCompositeExporter(CompositeExporterConfig{
+		Name: "nms", Targets: []Exporter{primary, secondary},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewCompositeExporter() error = %v", err)
+	}
+	ctx := context.Background()
+	records := []MetricRecord{{CounterID: 1, Value: 1}}
+
+	exporter.Export(ctx, records)
+	primary.SetError(errors.New("down"))
+	if err := exporter.Export(ctx, records); err != nil {
+		t.Errorf("Expected failover to succeed, got %v", err)
+	}
+	secondary.SetError(errors.New("down"))
+	if err := exporter.Export(ctx, records); err == nil {
+		t.Error("Expected an error when every target fails")
+	}
+
+	if len(primary.Batches()) != 1 || len(secondary.Batches()) != 1 {
+		t.Errorf("Expected 1 batch each, got primary=%d secondary=%d", len(primary.Batches()), len(secondary.Batches()))
+	}
+}
+
+// TestCompositeExporter_RoundRobin tests cycles are spread across targets
+func TestCompositeExporter_RoundRobin(t *testing.T) {
+	a, b := NewMemoryExporter("a"), NewMemoryExporter("b")
+	exporter, _ := NewCompositeExporter(CompositeExporterConfig{
+		Name: "spread", Strategy: StrategyRoundRobin, Targets: []Exporter{a, b},
+	}, &mockLogger{})
+
+	for i := 0; i < 4; i++ {
+		exporter.Export(context.Background(), []MetricRecord{{CounterID: 1}})
+	}
+	if len(a.Batches()) != 2 || len(b.Batches()) != 2 {
+		t.Errorf("Expected 2 batches each, got a=%d b=%d", len(a.Batches()), len(b.Batches()))
+	}
+}
+
+// TestCompositeExporter_MirrorQuorum tests mirror mode from YAML-style config succeeds once the quorum is reached
+func TestCompositeExporter_MirrorQuorum(t *testing.T) {
+	exp, err := CreateExporter(ExporterConfig{
+		Type: "composite",
+		Name: "mirror",
+		Config: map[string]interface{}{
+			"strategy": "mirror",
+			"quorum":   2,
+			"targets": []interface{}{
+				map[string]interface{}{"type": "memory", "name": "a"},
+				map[string]interface{}{"type": "memory", "name": "b"},
+				map[string]interface{}{"type": "memory", "name": "c"},
+			},
+		},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("CreateExporter() error = %v", err)
+	}
+	exporter := exp.(*CompositeExporter)
+	ctx := context.Background()
+
+	exporter.targets[0].(*MemoryExporter).SetError(errors.New("down"))
+	if err := exporter.Export(ctx, []MetricRecord{{CounterID: 1}}); err != nil {
+		t.Errorf("Expected quorum 2/3 to succeed, got %v", err)
+	}
+	exporter.targets[1].(*MemoryExporter).SetError(errors.New("down"))
+	if err := exporter.Export(ctx, []MetricRecord{{CounterID: 1}}); err == nil {
+		t.Error("Expected an error below quorum")
+	}
+
+	if _, err := NewCompositeExporter(CompositeExporterConfig{Strategy: StrategyMirror, Quorum: 4, Targets: exporter.targets}, &mockLogger{}); err == nil {
+		t.Error("Expected an error for a quorum above the number of targets")
+	}
+}
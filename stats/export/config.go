@@ -3,6 +3,7 @@ package export
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -47,6 +48,40 @@ func LoadExportConfig(v *viper.Viper) (*ExportConfig, error) {
 		config.SystemName = "EIR" // default
 	}
 
+	// Load export timeouts
+	if config.ExportTimeout, err = parseOptionalDuration(v.GetString("stats_export.export_timeout")); err != nil {
+		return nil, fmt.Errorf("invalid export_timeout: %w", err)
+	}
+	if config.CycleBudget, err = parseOptionalDuration(v.GetString("stats_export.cycle_budget")); err != nil {
+		return nil, fmt.Errorf("invalid cycle_budget: %w", err)
+	}
+
+	// Load first cycle handling
+	config.FirstCycle = v.GetString("stats_export.first_cycle")
+	config.SnapshotFile = v.GetString("stats_export.snapshot_file")
+	config.SequenceFile = v.GetString("stats_export.sequence_file")
+
+	// Load period labeling
+	config.PeriodTimezone = v.GetString("stats_export.period_timezone")
+	config.PeriodAlign = v.GetBool("stats_export.period_align")
+
+	// Load clock skew detection
+	if config.ClockSkewThreshold, err = parseOptionalDuration(v.GetString("stats_export.clock_skew_threshold")); err != nil {
+		return nil, fmt.Errorf("invalid clock_skew_threshold: %w", err)
+	}
+
+	// Load record deduplication
+	if config.DedupWindow, err = parseOptionalDuration(v.GetString("stats_export.dedup_window")); err != nil {
+		return nil, fmt.Errorf("invalid dedup_window: %w", err)
+	}
+
+	// Load record limit
+	config.MaxRecordsPerCycle = v.GetInt("stats_export.max_records_per_cycle")
+
+	// Load dry run
+	config.DryRun = v.GetBool("stats_export.dry_run")
+	config.DryRunFormat = v.GetString("stats_export.dry_run_format")
+
 	// Load exporters
 	exportersConfig := v.Get("stats_export.exporters")
 	if exportersConfig == nil {
@@ -100,6 +135,25 @@ func parseExporterConfig(m map[string]interface{}) (ExporterConfig, error) {
 		config.Enabled = true
 	}
 
+	// Export timeout (optional)
+	if timeoutVal, ok := m["export_timeout"].(string); ok {
+		timeout, err := parseOptionalDuration(timeoutVal)
+		if err != nil {
+			return config, fmt.Errorf("invalid export_timeout: %w", err)
+		}
+		config.ExportTimeout = timeout
+	}
+
+	// Heartbeat record (optional)
+	if heartbeatVal, ok := m["heartbeat"].(bool); ok {
+		config.Heartbeat = heartbeatVal
+	}
+
+	// Counter catalog handshake (optional)
+	if handshakeVal, ok := m["metadata_handshake"].(bool); ok {
+		config.MetadataHandshake = handshakeVal
+	}
+
 	// Config map
 	if configVal, ok := m["config"].(map[string]interface{}); ok {
 		// Expand environment variables in config values
@@ -151,6 +205,44 @@ func ParseExportConfigFromEnv() (*ExportConfig, error) {
 	}
 	config.Interval = interval
 
+	// Parse export timeouts
+	if config.ExportTimeout, err = parseOptionalDuration(os.Getenv("STATS_EXPORT_EXPORT_TIMEOUT")); err != nil {
+		return nil, fmt.Errorf("invalid STATS_EXPORT_EXPORT_TIMEOUT: %w", err)
+	}
+	if config.CycleBudget, err = parseOptionalDuration(os.Getenv("STATS_EXPORT_CYCLE_BUDGET")); err != nil {
+		return nil, fmt.Errorf("invalid STATS_EXPORT_CYCLE_BUDGET: %w", err)
+	}
+
+	// Parse first cycle handling
+	config.FirstCycle = os.Getenv("STATS_EXPORT_FIRST_CYCLE")
+	config.SnapshotFile = os.Getenv("STATS_EXPORT_SNAPSHOT_FILE")
+	config.SequenceFile = os.Getenv("STATS_EXPORT_SEQUENCE_FILE")
+
+	// Parse period labeling
+	config.PeriodTimezone = os.Getenv("STATS_EXPORT_PERIOD_TIMEZONE")
+	config.PeriodAlign = strings.ToLower(os.Getenv("STATS_EXPORT_PERIOD_ALIGN")) == "true"
+
+	// Parse clock skew detection
+	if config.ClockSkewThreshold, err = parseOptionalDuration(os.Getenv("STATS_EXPORT_CLOCK_SKEW_THRESHOLD")); err != nil {
+		return nil, fmt.Errorf("invalid STATS_EXPORT_CLOCK_SKEW_THRESHOLD: %w", err)
+	}
+
+	// Parse record deduplication
+	if config.DedupWindow, err = parseOptionalDuration(os.Getenv("STATS_EXPORT_DEDUP_WINDOW")); err != nil {
+		return nil, fmt.Errorf("invalid STATS_EXPORT_DEDUP_WINDOW: %w", err)
+	}
+
+	// Parse record limit
+	if maxRecords := os.Getenv("STATS_EXPORT_MAX_RECORDS_PER_CYCLE"); maxRecords != "" {
+		if config.MaxRecordsPerCycle, err = strconv.Atoi(maxRecords); err != nil {
+			return nil, fmt.Errorf("invalid STATS_EXPORT_MAX_RECORDS_PER_CYCLE: %w", err)
+		}
+	}
+
+	// Parse dry run
+	config.DryRun = strings.ToLower(os.Getenv("STATS_EXPORT_DRY_RUN")) == "true"
+	config.DryRunFormat = os.Getenv("STATS_EXPORT_DRY_RUN_FORMAT")
+
 	// Get hostname
 	config.Hostname = os.Getenv("STATS_EXPORT_HOSTNAME")
 	if config.Hostname == "" {
@@ -190,6 +282,28 @@ func ParseExportConfigFromEnv() (*ExportConfig, error) {
 			prefix := fmt.Sprintf("STATS_EXPORT_%s_%s_", strings.ToUpper(exporterType), strings.ToUpper(strings.ReplaceAll(exporterName, "-", "_")))
 			loadExporterConfigFromEnv(&exporterConfig, prefix)
 
+			// e.g., STATS_EXPORT_HTTP_METRICS_HTTP_EXPORT_TIMEOUT
+			if timeoutStr, ok := exporterConfig.Config["export_timeout"].(string); ok {
+				timeout, err := parseOptionalDuration(timeoutStr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid export_timeout for exporter %s: %w", exporterName, err)
+				}
+				exporterConfig.ExportTimeout = timeout
+				delete(exporterConfig.Config, "export_timeout")
+			}
+
+			// e.g., STATS_EXPORT_FILE_NMS_HEARTBEAT=true
+			if heartbeat, ok := exporterConfig.Config["heartbeat"].(string); ok {
+				exporterConfig.Heartbeat = strings.ToLower(heartbeat) == "true"
+				delete(exporterConfig.Config, "heartbeat")
+			}
+
+			// e.g., STATS_EXPORT_PUSH_NMS_METADATA_HANDSHAKE=true
+			if handshake, ok := exporterConfig.Config["metadata_handshake"].(string); ok {
+				exporterConfig.MetadataHandshake = strings.ToLower(handshake) == "true"
+				delete(exporterConfig.Config, "metadata_handshake")
+			}
+
 			config.Exporters = append(config.Exporters, exporterConfig)
 		}
 	}
@@ -216,3 +330,11 @@ func loadExporterConfigFromEnv(config *ExporterConfig, prefix string) {
 		config.Config[key] = value
 	}
 }
+
+// parseOptionalDuration parses a duration, treating an empty string as zero
+func parseOptionalDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
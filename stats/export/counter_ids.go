@@ -1,5 +1,7 @@
 package export
 
+import statsmodel "github.com/hsdfat/telco/stats"
+
 // Counter ID constants for metrics
 const (
 	// General request counters (1000-1099)
@@ -7,18 +9,40 @@ const (
 	CounterSuccessfulRequests = 1001
 	CounterFailedRequests     = 1002
 	CounterPendingRequests    = 1003
+	CounterBytesSent          = 1004
+	CounterBytesRecv          = 1005
+	CounterMaxPendingRequests = 1006
+	CounterUptimeSeconds      = 1007
 
 	// Diameter counters (1100-1199)
 	CounterDiameterTotal      = 1100
 	CounterDiameterSuccess    = 1101
 	CounterDiameterFailed     = 1102
 	CounterDiameterResultCode = 1103 // Use CauseCode for specific result code
+	CounterDiameterBytesSent  = 1104
+	CounterDiameterBytesRecv  = 1105
+	CounterDiameterSentSize   = 1106 // Use CauseCode for size bucket upper bound (bytes)
+	CounterDiameterRecvSize   = 1107 // Use CauseCode for size bucket upper bound (bytes)
+	CounterDiameterInFlight   = 1108
+
+	// Diameter peer counters (1120-1129), CauseCode identifies the peer (Origin-Host)
+	CounterPeerUp            = 1120 // 1 if the peer is open, 0 otherwise
+	CounterPeerUptimeSeconds = 1121
+	CounterPeerDWRFailures   = 1122
+	CounterPeerMessagesSent  = 1123
+	CounterPeerMessagesRecv  = 1124
+	CounterPeerDisconnects   = 1125
 
 	// HTTP counters (1200-1299)
 	CounterHTTPTotal      = 1200
 	CounterHTTPSuccess    = 1201
 	CounterHTTPFailed     = 1202
 	CounterHTTPStatusCode = 1203 // Use CauseCode for specific status code
+	CounterHTTPBytesSent  = 1204
+	CounterHTTPBytesRecv  = 1205
+	CounterHTTPSentSize   = 1206 // Use CauseCode for size bucket upper bound (bytes)
+	CounterHTTPRecvSize   = 1207 // Use CauseCode for size bucket upper bound (bytes)
+	CounterHTTPInFlight   = 1208
 
 	// Performance counters (1300-1399)
 	CounterRequestsPerSecond = 1300
@@ -29,29 +53,227 @@ const (
 	CounterP95LatencyMs      = 1305
 	CounterP99LatencyMs      = 1306
 
+	// Per-source latency counters (1310-1319), CauseCode identifies the source
+	CounterSourceAvgLatencyMs = 1310
+	CounterSourceMaxLatencyMs = 1311
+	CounterSourceP50LatencyMs = 1312
+	CounterSourceP95LatencyMs = 1313
+	CounterSourceP99LatencyMs = 1314
+
+	// Per-operation latency counters (1320-1329), CauseCode identifies the operation
+	CounterOperationAvgLatencyMs = 1320
+	CounterOperationMaxLatencyMs = 1321
+	CounterOperationP50LatencyMs = 1322
+	CounterOperationP95LatencyMs = 1323
+	CounterOperationP99LatencyMs = 1324
+
 	// Cache counters (1400-1499)
-	CounterCacheHits    = 1400
-	CounterCacheMisses  = 1401
-	CounterCacheHitRate = 1402
-	CounterCacheSize    = 1403
+	CounterCacheHits        = 1400
+	CounterCacheMisses      = 1401
+	CounterCacheHitRate     = 1402
+	CounterCacheSize        = 1403
+	CounterCacheEvictions   = 1404
+	CounterCacheExpirations = 1405
+	CounterCacheBytes       = 1406
+	CounterCacheMaxSize     = 1407
 
 	// Database counters (1500-1599)
-	CounterDBQueries = 1500
-	CounterDBInserts = 1501
-	CounterDBUpdates = 1502
-	CounterDBDeletes = 1503
+	CounterDBQueries      = 1500
+	CounterDBInserts      = 1501
+	CounterDBUpdates      = 1502
+	CounterDBDeletes      = 1503
+	CounterDBErrors       = 1504
+	CounterDBAvgLatencyMs = 1505
+
+	// Database per-operation latency counters (1510-1519), CauseCode identifies the operation
+	CounterDBOpAvgLatencyMs = 1510
+	CounterDBOpMaxLatencyMs = 1511
+	CounterDBOpP50LatencyMs = 1512
+	CounterDBOpP95LatencyMs = 1513
+	CounterDBOpP99LatencyMs = 1514
+
+	// Database per-table counters (1520-1529), CauseCode identifies the table
+	CounterDBTableOperations   = 1520
+	CounterDBTableErrors       = 1521
+	CounterDBTableAvgLatencyMs = 1522
+	CounterDBTableMaxLatencyMs = 1523
 
 	// Equipment status counters (1600-1699)
 	CounterWhitelisted = 1600
 	CounterBlacklisted = 1601
 	CounterGreylisted  = 1602
 
+	// Equipment status transition counters (1610-1619)
+	CounterWhiteToBlack = 1610
+	CounterWhiteToGrey  = 1611
+	CounterGreyToWhite  = 1612
+	CounterGreyToBlack  = 1613
+	CounterBlackToWhite = 1614
+	CounterBlackToGrey  = 1615
+
+	// TAC counters (1620-1629), CauseCode is the 8-digit TAC
+	CounterTACChecks      = 1620
+	CounterTACWhitelisted = 1621
+	CounterTACBlacklisted = 1622
+	CounterTACGreylisted  = 1623
+
 	// Connection counters (1700-1799)
 	CounterActiveConnections = 1700
 	CounterTotalConnections  = 1701
 	CounterFailedConnections = 1702
+	CounterClosedConnections = 1703
+
+	// Per-listener connection counters (1710-1719), CauseCode identifies the listener
+	CounterListenerActive = 1710
+	CounterListenerTotal  = 1711
+	CounterListenerFailed = 1712
+	CounterListenerClosed = 1713
+
+	// Go runtime counters (1800-1899)
+	CounterGoroutines     = 1800
+	CounterHeapInUseBytes = 1801
+	CounterHeapObjects    = 1802
+	CounterGCCount        = 1803
+	CounterGCPauseP99Ms   = 1804
+	CounterCPUPercent     = 1805
+
+	// SCTP transport counters (1900-1999)
+	CounterSCTPActiveAssociations = 1900
+	CounterSCTPEstablishes        = 1901
+	CounterSCTPAborts             = 1902
+	CounterSCTPShutdowns          = 1903
+	CounterSCTPPathFailovers      = 1904
+	CounterSCTPRetransmits        = 1905
+	CounterSCTPGapAcks            = 1906
+
+	// Overload control counters (2000-2099)
+	CounterOverloadLevel    = 2000
+	CounterThrottled        = 2001
+	CounterOverloadRejected = 2002
+
+	// Per-interface overload counters (2010-2019), CauseCode identifies the source (see SourceCauseCodes)
+	CounterInterfaceThrottled        = 2010
+	CounterInterfaceOverloadRejected = 2011
+
+	// License/capacity counters (2100-2199)
+	CounterLicensedTPS            = 2100
+	CounterPeakTPS                = 2101
+	CounterLicensedSubscribers    = 2102
+	CounterProvisionedSubscribers = 2103
+
+	// Config provider counters (2200-2299, cause code = provider)
+	CounterConfigProviderUp               = 2200 // 1 if the last load succeeded and the provider isn't stale
+	CounterConfigProviderLoads            = 2201
+	CounterConfigProviderLoadFailures     = 2202
+	CounterConfigProviderLatencyMs        = 2203
+	CounterConfigProviderStalenessSeconds = 2204
+
+	// SLO counters (2300-2399, cause code = operation)
+	CounterSLOCompliant              = 2300 // 1 if the last evaluation interval met the SLO
+	CounterSLOComplianceSeconds      = 2301
+	CounterSLOViolationSeconds       = 2302
+	CounterSLOErrorBudgetRemaining   = 2303 // Percent of the failure budget left in the window (0 once spent)
+	CounterSLOLatencyBudgetRemaining = 2304 // Percent of the slow request budget left in the window (0 once spent)
+
+	// Clock sanity counters (2400-2499)
+	CounterClockSkewMs = 2400 // Absolute wall clock skew against monotonic time since the scheduler started
+	CounterClockSteps  = 2401 // Cycles where the wall clock stepped more than the threshold
+
+	// Per-tenant counters (2500-2599), Tenant identifies the tenant or network slice
+	CounterTenantRequests     = 2500
+	CounterTenantSuccess      = 2501
+	CounterTenantFailed       = 2502
+	CounterTenantBytesSent    = 2503
+	CounterTenantBytesRecv    = 2504
+	CounterTenantResultCode   = 2505 // Use CauseCode for specific result or status code
+	CounterTenantAvgLatencyMs = 2510
+	CounterTenantMaxLatencyMs = 2511
+	CounterTenantP50LatencyMs = 2512
+	CounterTenantP95LatencyMs = 2513
+	CounterTenantP99LatencyMs = 2514
+
+	// Export pipeline counters (2600-2699)
+	CounterDedupSuppressed  = 2600 // Duplicate records suppressed by the scheduler's dedup window
+	CounterHeartbeat        = 2601 // Always 1, sent every cycle to exporters with a heartbeat
+	CounterRecordsTruncated = 2602 // Records dropped by the per-cycle record limit
+	CounterCounterTruncated = 2603 // Records of one counter dropped by the limit (cause code = counter ID)
+
+	// Health score counters (2700-2799), see TransformerConfig.HealthScorer
+	CounterHealthScore          = 2700 // Overall 0-100 health score
+	CounterSubsystemHealthScore = 2701 // Use CauseCode for the subsystem (see HealthSubsystemCauseCodes)
+
+	// Self-test counters (2800-2899), see statsmodel.SelfTest
+	CounterSelfTestRuns                = 2800
+	CounterSelfTestPassed              = 2801
+	CounterSelfTestFailed              = 2802
+	CounterSelfTestTimeouts            = 2803
+	CounterSelfTestConsecutiveFailures = 2804 // Check rounds in a row with a failed check
+	CounterSelfTestLatencyMs           = 2805 // Slowest check of the last round
+	CounterSelfTestSecondsSinceSuccess = 2806 // Since the last round where every check passed
 )
 
+// StatusTransitionCounters maps EIRStats.StatusTransitions keys to counter IDs
+var StatusTransitionCounters = map[string]int{
+	"whitelisted->blacklisted": CounterWhiteToBlack,
+	"whitelisted->greylisted":  CounterWhiteToGrey,
+	"greylisted->whitelisted":  CounterGreyToWhite,
+	"greylisted->blacklisted":  CounterGreyToBlack,
+	"blacklisted->whitelisted": CounterBlackToWhite,
+	"blacklisted->greylisted":  CounterBlackToGrey,
+}
+
+// SourceCauseCodes maps request sources to the CauseCode used on per-source records
+var SourceCauseCodes = map[string]int{
+	"diameter": 1,
+	"http":     2,
+}
+
+// OperationCauseCodes maps operation names to the CauseCode used on per-operation records
+var OperationCauseCodes = map[string]int{
+	"check":     1,
+	"provision": 2,
+	"query":     3,
+}
+
+// PeerCauseCodes maps Diameter peer Origin-Host values to the CauseCode used on per-peer records
+// Services may register fixed codes for their peers; other peers are assigned one on export
+var PeerCauseCodes = NewCauseCodeRegistry()
+
+// ConfigProviderCauseCodes maps config provider names (e.g. "consul(eir/config)") to the
+// CauseCode used on per-provider records
+// Services may register fixed codes for their providers; others are assigned one on export
+var ConfigProviderCauseCodes = NewCauseCodeRegistry()
+
+// HarvestedMetricCounters maps names in the harvested "runtime" section (expvar names
+// or runtime/metrics names, see statsmodel.MetricsHarvester) to counter IDs
+// Services register their counters here and with RegisterCounter; unmapped names are not exported
+var HarvestedMetricCounters = map[string]int{}
+
+// ListenerCauseCodes maps listener bind addresses to the CauseCode used on per-listener records
+// Services may register fixed codes for their listeners; others are assigned one on export
+var ListenerCauseCodes = NewCauseCodeRegistry()
+
+// DBOperationCauseCodes maps database operations to the CauseCode used on per-operation records
+var DBOperationCauseCodes = map[string]int{
+	"query":  1,
+	"insert": 2,
+	"update": 3,
+	"delete": 4,
+}
+
+// DBTableCauseCodes maps table names to the CauseCode used on per-table records
+// Services may register fixed codes for their tables; others are assigned one on export
+var DBTableCauseCodes = NewCauseCodeRegistry()
+
+// HealthSubsystemCauseCodes maps health scorer subsystems to the CauseCode used on
+// per-subsystem health score records
+var HealthSubsystemCauseCodes = map[string]int{
+	statsmodel.HealthConnections: 1,
+	statsmodel.HealthRequests:    2,
+	statsmodel.HealthCache:       3,
+	statsmodel.HealthDatabase:    4,
+}
+
 // CounterMetadata provides metadata about counter IDs
 type CounterMetadata struct {
 	ID          int
@@ -61,26 +283,51 @@ type CounterMetadata struct {
 	Type        string // "counter", "gauge", "rate"
 }
 
-// GetCounterMetadata returns metadata for all defined counters
+// GetCounterMetadata returns metadata for all defined counters, including registered ones
 func GetCounterMetadata() []CounterMetadata {
+	return append(builtinCounterMetadata(), registeredCounterMetadata()...)
+}
+
+// builtinCounterMetadata returns metadata for the counters defined in this package
+func builtinCounterMetadata() []CounterMetadata {
 	return []CounterMetadata{
 		// General request counters
 		{CounterTotalRequests, "total_requests", "Total number of requests processed", "count", "counter"},
 		{CounterSuccessfulRequests, "successful_requests", "Total number of successful requests", "count", "counter"},
 		{CounterFailedRequests, "failed_requests", "Total number of failed requests", "count", "counter"},
 		{CounterPendingRequests, "pending_requests", "Number of requests currently pending", "count", "gauge"},
+		{CounterBytesSent, "bytes_sent", "Total bytes sent", "bytes", "counter"},
+		{CounterBytesRecv, "bytes_recv", "Total bytes received", "bytes", "counter"},
+		{CounterMaxPendingRequests, "max_pending_requests", "Highest number of pending requests during the period", "count", "gauge"},
+		{CounterUptimeSeconds, "uptime_seconds", "Seconds since the service started (drops on restart)", "seconds", "gauge"},
 
 		// Diameter counters
 		{CounterDiameterTotal, "diameter_total", "Total Diameter requests", "count", "counter"},
 		{CounterDiameterSuccess, "diameter_success", "Successful Diameter requests", "count", "counter"},
 		{CounterDiameterFailed, "diameter_failed", "Failed Diameter requests", "count", "counter"},
 		{CounterDiameterResultCode, "diameter_result_code", "Diameter result code distribution", "count", "counter"},
+		{CounterDiameterBytesSent, "diameter_bytes_sent", "Bytes sent over Diameter", "bytes", "counter"},
+		{CounterDiameterBytesRecv, "diameter_bytes_recv", "Bytes received over Diameter", "bytes", "counter"},
+		{CounterDiameterSentSize, "diameter_sent_size", "Sent Diameter message size distribution (cause code = bucket bytes)", "count", "counter"},
+		{CounterDiameterRecvSize, "diameter_recv_size", "Received Diameter message size distribution (cause code = bucket bytes)", "count", "counter"},
+		{CounterDiameterInFlight, "diameter_in_flight", "Diameter requests currently in progress", "count", "gauge"},
+		{CounterPeerUp, "diameter_peer_up", "Whether the Diameter peer is open, 1 or 0 (cause code = peer)", "boolean", "gauge"},
+		{CounterPeerUptimeSeconds, "diameter_peer_uptime_seconds", "Seconds since the Diameter peer opened (cause code = peer)", "seconds", "gauge"},
+		{CounterPeerDWRFailures, "diameter_peer_dwr_failures", "Unanswered Device-Watchdog requests (cause code = peer)", "count", "counter"},
+		{CounterPeerMessagesSent, "diameter_peer_messages_sent", "Messages sent to the Diameter peer (cause code = peer)", "count", "counter"},
+		{CounterPeerMessagesRecv, "diameter_peer_messages_recv", "Messages received from the Diameter peer (cause code = peer)", "count", "counter"},
+		{CounterPeerDisconnects, "diameter_peer_disconnects", "Disconnects from the Diameter peer (cause code = peer)", "count", "counter"},
 
 		// HTTP counters
 		{CounterHTTPTotal, "http_total", "Total HTTP requests", "count", "counter"},
 		{CounterHTTPSuccess, "http_success", "Successful HTTP requests", "count", "counter"},
 		{CounterHTTPFailed, "http_failed", "Failed HTTP requests", "count", "counter"},
 		{CounterHTTPStatusCode, "http_status_code", "HTTP status code distribution", "count", "counter"},
+		{CounterHTTPBytesSent, "http_bytes_sent", "Bytes sent over HTTP", "bytes", "counter"},
+		{CounterHTTPBytesRecv, "http_bytes_recv", "Bytes received over HTTP", "bytes", "counter"},
+		{CounterHTTPSentSize, "http_sent_size", "Sent HTTP message size distribution (cause code = bucket bytes)", "count", "counter"},
+		{CounterHTTPRecvSize, "http_recv_size", "Received HTTP message size distribution (cause code = bucket bytes)", "count", "counter"},
+		{CounterHTTPInFlight, "http_in_flight", "HTTP requests currently in progress", "count", "gauge"},
 
 		// Performance counters
 		{CounterRequestsPerSecond, "requests_per_second", "Request throughput rate", "requests/sec", "gauge"},
@@ -91,27 +338,155 @@ func GetCounterMetadata() []CounterMetadata {
 		{CounterP95LatencyMs, "p95_latency_ms", "95th percentile latency", "milliseconds", "gauge"},
 		{CounterP99LatencyMs, "p99_latency_ms", "99th percentile latency", "milliseconds", "gauge"},
 
+		// Per-source latency counters
+		{CounterSourceAvgLatencyMs, "source_avg_latency_ms", "Average latency per source (cause code = source)", "milliseconds", "gauge"},
+		{CounterSourceMaxLatencyMs, "source_max_latency_ms", "Maximum latency per source (cause code = source)", "milliseconds", "gauge"},
+		{CounterSourceP50LatencyMs, "source_p50_latency_ms", "50th percentile latency per source (cause code = source)", "milliseconds", "gauge"},
+		{CounterSourceP95LatencyMs, "source_p95_latency_ms", "95th percentile latency per source (cause code = source)", "milliseconds", "gauge"},
+		{CounterSourceP99LatencyMs, "source_p99_latency_ms", "99th percentile latency per source (cause code = source)", "milliseconds", "gauge"},
+
+		// Per-operation latency counters
+		{CounterOperationAvgLatencyMs, "operation_avg_latency_ms", "Average latency per operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterOperationMaxLatencyMs, "operation_max_latency_ms", "Maximum latency per operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterOperationP50LatencyMs, "operation_p50_latency_ms", "50th percentile latency per operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterOperationP95LatencyMs, "operation_p95_latency_ms", "95th percentile latency per operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterOperationP99LatencyMs, "operation_p99_latency_ms", "99th percentile latency per operation (cause code = operation)", "milliseconds", "gauge"},
+
 		// Cache counters
 		{CounterCacheHits, "cache_hits", "Number of cache hits", "count", "counter"},
 		{CounterCacheMisses, "cache_misses", "Number of cache misses", "count", "counter"},
 		{CounterCacheHitRate, "cache_hit_rate", "Cache hit rate percentage", "percent", "gauge"},
 		{CounterCacheSize, "cache_size", "Current cache size", "entries", "gauge"},
+		{CounterCacheEvictions, "cache_evictions", "Cache entries evicted due to capacity", "count", "counter"},
+		{CounterCacheExpirations, "cache_expirations", "Cache entries expired by TTL", "count", "counter"},
+		{CounterCacheBytes, "cache_bytes", "Approximate memory used by the cache", "bytes", "gauge"},
+		{CounterCacheMaxSize, "cache_max_size", "Configured maximum cache size", "entries", "gauge"},
 
 		// Database counters
 		{CounterDBQueries, "db_queries", "Total database queries", "count", "counter"},
 		{CounterDBInserts, "db_inserts", "Total database inserts", "count", "counter"},
 		{CounterDBUpdates, "db_updates", "Total database updates", "count", "counter"},
 		{CounterDBDeletes, "db_deletes", "Total database deletes", "count", "counter"},
+		{CounterDBErrors, "db_errors", "Failed database operations", "count", "counter"},
+		{CounterDBAvgLatencyMs, "db_avg_latency_ms", "Average database operation latency", "milliseconds", "gauge"},
+		{CounterDBOpAvgLatencyMs, "db_op_avg_latency_ms", "Average latency per database operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterDBOpMaxLatencyMs, "db_op_max_latency_ms", "Maximum latency per database operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterDBOpP50LatencyMs, "db_op_p50_latency_ms", "50th percentile latency per database operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterDBOpP95LatencyMs, "db_op_p95_latency_ms", "95th percentile latency per database operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterDBOpP99LatencyMs, "db_op_p99_latency_ms", "99th percentile latency per database operation (cause code = operation)", "milliseconds", "gauge"},
+		{CounterDBTableOperations, "db_table_operations", "Database operations per table (cause code = table)", "count", "counter"},
+		{CounterDBTableErrors, "db_table_errors", "Failed database operations per table (cause code = table)", "count", "counter"},
+		{CounterDBTableAvgLatencyMs, "db_table_avg_latency_ms", "Average latency per table (cause code = table)", "milliseconds", "gauge"},
+		{CounterDBTableMaxLatencyMs, "db_table_max_latency_ms", "Maximum latency per table (cause code = table)", "milliseconds", "gauge"},
 
 		// Equipment status counters
 		{CounterWhitelisted, "whitelisted", "Whitelisted equipment checks", "count", "counter"},
 		{CounterBlacklisted, "blacklisted", "Blacklisted equipment checks", "count", "counter"},
 		{CounterGreylisted, "greylisted", "Greylisted equipment checks", "count", "counter"},
 
+		// Equipment status transition counters
+		{CounterWhiteToBlack, "white_to_black", "Equipment moved from whitelist to blacklist", "count", "counter"},
+		{CounterWhiteToGrey, "white_to_grey", "Equipment moved from whitelist to greylist", "count", "counter"},
+		{CounterGreyToWhite, "grey_to_white", "Equipment moved from greylist to whitelist", "count", "counter"},
+		{CounterGreyToBlack, "grey_to_black", "Equipment moved from greylist to blacklist", "count", "counter"},
+		{CounterBlackToWhite, "black_to_white", "Equipment moved from blacklist to whitelist", "count", "counter"},
+		{CounterBlackToGrey, "black_to_grey", "Equipment moved from blacklist to greylist", "count", "counter"},
+
+		// TAC counters
+		{CounterTACChecks, "tac_checks", "Equipment checks per TAC (cause code = TAC)", "count", "counter"},
+		{CounterTACWhitelisted, "tac_whitelisted", "Whitelisted checks per TAC (cause code = TAC)", "count", "counter"},
+		{CounterTACBlacklisted, "tac_blacklisted", "Blacklisted checks per TAC (cause code = TAC)", "count", "counter"},
+		{CounterTACGreylisted, "tac_greylisted", "Greylisted checks per TAC (cause code = TAC)", "count", "counter"},
+
 		// Connection counters
 		{CounterActiveConnections, "active_connections", "Currently active connections", "count", "gauge"},
 		{CounterTotalConnections, "total_connections", "Total connections established", "count", "counter"},
 		{CounterFailedConnections, "failed_connections", "Failed connection attempts", "count", "counter"},
+		{CounterClosedConnections, "closed_connections", "Total number of gracefully closed connections", "count", "counter"},
+		{CounterListenerActive, "listener_active_connections", "Active connections per listener (cause code = listener)", "count", "gauge"},
+		{CounterListenerTotal, "listener_total_connections", "Connections established per listener (cause code = listener)", "count", "counter"},
+		{CounterListenerFailed, "listener_failed_connections", "Failed connection attempts per listener (cause code = listener)", "count", "counter"},
+		{CounterListenerClosed, "listener_closed_connections", "Closed connections per listener (cause code = listener)", "count", "counter"},
+
+		// Go runtime counters
+		{CounterGoroutines, "goroutines", "Number of goroutines", "count", "gauge"},
+		{CounterHeapInUseBytes, "heap_inuse_bytes", "Heap memory in use", "bytes", "gauge"},
+		{CounterHeapObjects, "heap_objects", "Number of allocated heap objects", "count", "gauge"},
+		{CounterGCCount, "gc_count", "Completed GC cycles", "count", "counter"},
+		{CounterGCPauseP99Ms, "gc_pause_p99_ms", "99th percentile of recent GC pauses", "milliseconds", "gauge"},
+		{CounterCPUPercent, "cpu_percent", "Process CPU usage (100 = one core)", "percent", "gauge"},
+
+		// SCTP transport counters
+		{CounterSCTPActiveAssociations, "sctp_active_associations", "Currently established SCTP associations", "count", "gauge"},
+		{CounterSCTPEstablishes, "sctp_establishes", "SCTP associations established", "count", "counter"},
+		{CounterSCTPAborts, "sctp_aborts", "SCTP associations aborted", "count", "counter"},
+		{CounterSCTPShutdowns, "sctp_shutdowns", "SCTP associations gracefully shut down", "count", "counter"},
+		{CounterSCTPPathFailovers, "sctp_path_failovers", "SCTP primary path failovers", "count", "counter"},
+		{CounterSCTPRetransmits, "sctp_retransmits", "Retransmitted SCTP DATA chunks", "count", "counter"},
+		{CounterSCTPGapAcks, "sctp_gap_acks", "SCTP SACKs with gap ack blocks", "count", "counter"},
+
+		// Overload control counters
+		{CounterOverloadLevel, "overload_level", "Current load level", "percent", "gauge"},
+		{CounterThrottled, "throttled_requests", "Requests throttled by overload control", "count", "counter"},
+		{CounterOverloadRejected, "overload_rejected_requests", "Requests rejected due to overload", "count", "counter"},
+		{CounterInterfaceThrottled, "interface_throttled_requests", "Requests throttled per interface (cause code = source)", "count", "counter"},
+		{CounterInterfaceOverloadRejected, "interface_overload_rejected_requests", "Requests rejected due to overload per interface (cause code = source)", "count", "counter"},
+
+		// License/capacity counters
+		{CounterLicensedTPS, "licensed_tps", "Licensed transactions per second", "tps", "gauge"},
+		{CounterPeakTPS, "peak_tps", "Peak transactions per second during the period", "tps", "gauge"},
+		{CounterLicensedSubscribers, "licensed_subscribers", "Licensed subscriber count", "count", "gauge"},
+		{CounterProvisionedSubscribers, "provisioned_subscribers", "Provisioned subscriber count", "count", "gauge"},
+
+		// Config provider counters
+		{CounterConfigProviderUp, "config_provider_up", "Whether the config provider last loaded successfully and isn't stale, 1 or 0 (cause code = provider)", "boolean", "gauge"},
+		{CounterConfigProviderLoads, "config_provider_loads", "Config provider load attempts (cause code = provider)", "count", "counter"},
+		{CounterConfigProviderLoadFailures, "config_provider_load_failures", "Failed config provider loads (cause code = provider)", "count", "counter"},
+		{CounterConfigProviderLatencyMs, "config_provider_latency_ms", "Duration of the last config provider load (cause code = provider)", "milliseconds", "gauge"},
+		{CounterConfigProviderStalenessSeconds, "config_provider_staleness_seconds", "Seconds since the config provider last loaded successfully (cause code = provider)", "seconds", "gauge"},
+
+		// SLO counters
+		{CounterSLOCompliant, "slo_compliant", "Whether the last evaluation interval met the operation's SLO, 1 or 0 (cause code = operation)", "boolean", "gauge"},
+		{CounterSLOComplianceSeconds, "slo_compliance_seconds", "Time in the SLO window meeting the objective (cause code = operation)", "seconds", "gauge"},
+		{CounterSLOViolationSeconds, "slo_violation_seconds", "Time in the SLO window violating the objective (cause code = operation)", "seconds", "gauge"},
+		{CounterSLOErrorBudgetRemaining, "slo_error_budget_remaining", "Failure budget left in the SLO window (cause code = operation)", "percent", "gauge"},
+		{CounterSLOLatencyBudgetRemaining, "slo_latency_budget_remaining", "Slow request budget left in the SLO window (cause code = operation)", "percent", "gauge"},
+
+		// Clock sanity counters
+		{CounterClockSkewMs, "clock_skew_ms", "Absolute wall clock skew against monotonic time since the export scheduler started", "milliseconds", "gauge"},
+		{CounterClockSteps, "clock_steps", "Export cycles where the wall clock stepped more than the skew threshold", "count", "counter"},
+
+		// Per-tenant counters
+		{CounterTenantRequests, "tenant_requests", "Requests per tenant (tenant label)", "count", "counter"},
+		{CounterTenantSuccess, "tenant_success", "Successful requests per tenant (tenant label)", "count", "counter"},
+		{CounterTenantFailed, "tenant_failed", "Failed requests per tenant (tenant label)", "count", "counter"},
+		{CounterTenantBytesSent, "tenant_bytes_sent", "Bytes sent per tenant (tenant label)", "bytes", "counter"},
+		{CounterTenantBytesRecv, "tenant_bytes_recv", "Bytes received per tenant (tenant label)", "bytes", "counter"},
+		{CounterTenantResultCode, "tenant_result_code", "Result codes per tenant (tenant label, cause code = result or status code)", "count", "counter"},
+		{CounterTenantAvgLatencyMs, "tenant_avg_latency_ms", "Average latency per tenant (tenant label)", "milliseconds", "gauge"},
+		{CounterTenantMaxLatencyMs, "tenant_max_latency_ms", "Maximum latency per tenant (tenant label)", "milliseconds", "gauge"},
+		{CounterTenantP50LatencyMs, "tenant_p50_latency_ms", "50th percentile latency per tenant (tenant label)", "milliseconds", "gauge"},
+		{CounterTenantP95LatencyMs, "tenant_p95_latency_ms", "95th percentile latency per tenant (tenant label)", "milliseconds", "gauge"},
+		{CounterTenantP99LatencyMs, "tenant_p99_latency_ms", "99th percentile latency per tenant (tenant label)", "milliseconds", "gauge"},
+
+		// Export pipeline counters
+		{CounterDedupSuppressed, "dedup_suppressed", "Duplicate records suppressed by the export scheduler", "count", "counter"},
+		{CounterHeartbeat, "heartbeat", "Export cycle heartbeat, always 1 (node alive)", "count", "gauge"},
+		{CounterRecordsTruncated, "records_truncated", "Records dropped by the per-cycle record limit", "count", "counter"},
+		{CounterCounterTruncated, "counter_truncated", "Records of a counter dropped by the record limit, by counter ID", "count", "counter"},
+
+		// Health score counters
+		{CounterHealthScore, "health_score", "Overall service health score (0-100)", "score", "gauge"},
+		{CounterSubsystemHealthScore, "subsystem_health_score", "Health score per subsystem (cause code = subsystem, 0-100)", "score", "gauge"},
+
+		// Self-test counters
+		{CounterSelfTestRuns, "selftest_runs", "Synthetic self-test checks executed", "count", "counter"},
+		{CounterSelfTestPassed, "selftest_passed", "Synthetic self-test checks that completed end to end", "count", "counter"},
+		{CounterSelfTestFailed, "selftest_failed", "Synthetic self-test checks that failed or timed out", "count", "counter"},
+		{CounterSelfTestTimeouts, "selftest_timeouts", "Synthetic self-test checks that timed out", "count", "counter"},
+		{CounterSelfTestConsecutiveFailures, "selftest_consecutive_failures", "Self-test rounds in a row with a failed check", "count", "gauge"},
+		{CounterSelfTestLatencyMs, "selftest_latency_ms", "Duration of the slowest check of the last self-test round", "milliseconds", "gauge"},
+		{CounterSelfTestSecondsSinceSuccess, "selftest_seconds_since_success", "Seconds since every self-test check last passed", "seconds", "gauge"},
 	}
 }
 
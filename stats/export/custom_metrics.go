@@ -0,0 +1,158 @@
+package export
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// CustomMetricHandler tells the scheduler and transformer how to handle one custom
+// metrics section; register the section's type with statsmodel.RegisterCustomMetric
+// so it also survives JSON round-tripping
+type CustomMetricHandler struct {
+	// Delta returns the section's change since prev (nil on the first cycle)
+	// Without Delta the current section is exported as-is
+	Delta func(current, prev interface{}) interface{}
+
+	// Transform converts the section to metric records
+	// Without Transform the section is not exported
+	Transform func(t *Transformer, section interface{}, timestamp time.Time) []MetricRecord
+}
+
+var (
+	customHandlersMu sync.RWMutex
+	customHandlers   = map[string]CustomMetricHandler{
+		"eir": {
+			Delta: func(current, prev interface{}) interface{} {
+				curr, ok := statsmodel.CustomMetric[statsmodel.EIRStats](current)
+				if !ok {
+					return current
+				}
+				p, ok := statsmodel.CustomMetric[statsmodel.EIRStats](prev)
+				if !ok {
+					return curr
+				}
+				delta := statsmodel.Delta(*curr, *p)
+				return &delta
+			},
+			Transform: func(t *Transformer, section interface{}, timestamp time.Time) []MetricRecord {
+				eir, ok := statsmodel.CustomMetric[statsmodel.EIRStats](section)
+				if !ok {
+					return nil
+				}
+				return t.transformEIRStats(eir, timestamp)
+			},
+		},
+		"runtime": {
+			Delta: func(current, prev interface{}) interface{} {
+				curr, ok := statsmodel.CustomMetric[statsmodel.HarvestedMetrics](current)
+				if !ok {
+					return current
+				}
+				p, ok := statsmodel.CustomMetric[statsmodel.HarvestedMetrics](prev)
+				if !ok {
+					return curr
+				}
+				delta := statsmodel.Delta(*curr, *p)
+				return &delta
+			},
+			Transform: func(t *Transformer, section interface{}, timestamp time.Time) []MetricRecord {
+				harvested, ok := statsmodel.CustomMetric[statsmodel.HarvestedMetrics](section)
+				if !ok {
+					return nil
+				}
+				return t.transformHarvestedMetrics(harvested, timestamp)
+			},
+		},
+		"selftest": {
+			Delta: func(current, prev interface{}) interface{} {
+				curr, ok := statsmodel.CustomMetric[statsmodel.SelfTestStats](current)
+				if !ok {
+					return current
+				}
+				p, ok := statsmodel.CustomMetric[statsmodel.SelfTestStats](prev)
+				if !ok {
+					return curr
+				}
+				delta := statsmodel.Delta(*curr, *p)
+				return &delta
+			},
+			Transform: func(t *Transformer, section interface{}, timestamp time.Time) []MetricRecord {
+				selfTest, ok := statsmodel.CustomMetric[statsmodel.SelfTestStats](section)
+				if !ok {
+					return nil
+				}
+				return appendSection(t, nil, selfTestCounters, selfTest, 0, timestamp)
+			},
+		},
+	}
+)
+
+// RegisterCustomMetricHandler registers delta and transform hooks for a custom metrics section
+func RegisterCustomMetricHandler(name string, handler CustomMetricHandler) {
+	customHandlersMu.Lock()
+	defer customHandlersMu.Unlock()
+	customHandlers[name] = handler
+}
+
+// customMetricHandler returns the hooks registered for a section
+func customMetricHandler(name string) (CustomMetricHandler, bool) {
+	customHandlersMu.RLock()
+	defer customHandlersMu.RUnlock()
+	h, ok := customHandlers[name]
+	return h, ok
+}
+
+// transformHarvestedMetrics transforms the harvested values mapped in
+// HarvestedMetricCounters, rounded; counters without activity are skipped
+func (t *Transformer) transformHarvestedMetrics(harvested *statsmodel.HarvestedMetrics, timestamp time.Time) []MetricRecord {
+	var records []MetricRecord
+	add := func(values map[string]float64, counter bool) {
+		for name, value := range values {
+			counterID, ok := HarvestedMetricCounters[name]
+			if !ok || value < 0 || counter && value == 0 {
+				continue
+			}
+			records = append(records, t.createRecord(counterID, uint64(math.Round(value)), 0, timestamp))
+		}
+	}
+	add(harvested.Counters, true)
+	add(harvested.Gauges, false)
+
+	sort.Slice(records, func(i, j int) bool { return records[i].CounterID < records[j].CounterID })
+	return records
+}
+
+// calculateCustomMetricsDelta calculates the delta of every custom metrics section
+func calculateCustomMetricsDelta(current, prev statsmodel.CustomMetrics) statsmodel.CustomMetrics {
+	delta := make(statsmodel.CustomMetrics, len(current))
+	for name, section := range current {
+		h, ok := customMetricHandler(name)
+		if !ok || h.Delta == nil {
+			delta[name] = section
+			continue
+		}
+		delta[name] = h.Delta(section, prev[name])
+	}
+	return delta
+}
+
+// transformCustomMetrics transforms every custom metrics section with a Transform hook
+func (t *Transformer) transformCustomMetrics(custom statsmodel.CustomMetrics, timestamp time.Time) []MetricRecord {
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var records []MetricRecord
+	for _, name := range names {
+		if h, ok := customMetricHandler(name); ok && h.Transform != nil {
+			records = append(records, h.Transform(t, custom[name], timestamp)...)
+		}
+	}
+	return records
+}
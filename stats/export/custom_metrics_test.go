@@ -0,0 +1,126 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// testQueueStats is a custom metrics section registered by the tests
+type testQueueStats struct {
+	Drops uint64 `json:"drops"`
+}
+
+const testCounterQueueDrops = 99001
+
+func init() {
+	statsmodel.RegisterCustomMetric("test_queue", func() interface{} { return &testQueueStats{} })
+	RegisterCustomMetricHandler("test_queue", CustomMetricHandler{
+		Delta: func(current, prev interface{}) interface{} {
+			curr := current.(*testQueueStats)
+			if p, ok := prev.(*testQueueStats); ok {
+				return &testQueueStats{Drops: curr.Drops - p.Drops}
+			}
+			return curr
+		},
+		Transform: func(t *Transformer, section interface{}, timestamp time.Time) []MetricRecord {
+			return []MetricRecord{t.createRecord(testCounterQueueDrops, section.(*testQueueStats).Drops, 0, timestamp)}
+		},
+	})
+}
+
+// roundTrip encodes stats as JSON and decodes them back, like FetchStats does
+func roundTrip(t *testing.T, stats *statsmodel.ServiceStats) *statsmodel.ServiceStats {
+	t.Helper()
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out statsmodel.ServiceStats
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return &out
+}
+
+// TestCustomMetrics_JSONRoundTrip tests registered sections decode to their types
+func TestCustomMetrics_JSONRoundTrip(t *testing.T) {
+	out := roundTrip(t, &statsmodel.ServiceStats{
+		CustomMetrics: map[string]interface{}{
+			"eir":     &statsmodel.EIRStats{EquipmentChecks: statsmodel.EquipmentCheckStats{Total: 7}},
+			"unknown": map[string]interface{}{"x": 1},
+		},
+	})
+
+	eir, ok := out.CustomMetrics.EIR()
+	if !ok || eir.EquipmentChecks.Total != 7 {
+		t.Errorf("Expected *EIRStats with 7 checks, got %#v", out.CustomMetrics["eir"])
+	}
+	if _, ok := out.CustomMetrics["unknown"].(map[string]interface{}); !ok {
+		t.Errorf("Expected unregistered section as a map, got %T", out.CustomMetrics["unknown"])
+	}
+}
+
+// TestCustomMetrics_DeltaAndTransform tests registered sections flow through delta calculation and transformation
+func TestCustomMetrics_DeltaAndTransform(t *testing.T) {
+	scheduler := NewExportScheduler(time.Minute, &mockStatsCollector{}, NewTransformer("h", "EIR"), &mockLogger{})
+
+	first := roundTrip(t, &statsmodel.ServiceStats{
+		CustomMetrics: map[string]interface{}{"test_queue": &testQueueStats{Drops: 10}},
+	})
+	scheduler.updatePreviousSnapshot(first)
+
+	second := roundTrip(t, &statsmodel.ServiceStats{
+		CustomMetrics: map[string]interface{}{"test_queue": &testQueueStats{Drops: 25}},
+	})
+	delta := scheduler.calculateDeltaStats(second)
+
+	records := scheduler.transformer.Transform(delta)
+	var found bool
+	for _, r := range records {
+		if r.CounterID == testCounterQueueDrops {
+			found = true
+			if r.Value != 15 {
+				t.Errorf("Expected 15 drops in the delta, got %d", r.Value)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a record for the registered custom section")
+	}
+}
+
+// TestUnmarshalServiceStats tests a scraped /stats document transforms like collected stats
+func TestUnmarshalServiceStats(t *testing.T) {
+	statsmodel.RegisterInterfaceStats("s6a", func() interface{} { return &statsmodel.InterfaceCheckStats{} })
+
+	doc := []byte(`{
+		"service_name": "EIR",
+		"interface_stats": {"s6a": {"total": 3}, "other": {"x": 1}},
+		"custom_metrics": {"eir": {"equipment_checks": {"total": 5, "by_interface": {"diameter": {"total": 5, "success": 5}}}}}
+	}`)
+
+	stats, err := statsmodel.UnmarshalServiceStats(doc)
+	if err != nil {
+		t.Fatalf("UnmarshalServiceStats() error = %v", err)
+	}
+	if s6a, ok := stats.InterfaceStats["s6a"].(*statsmodel.InterfaceCheckStats); !ok || s6a.Total != 3 {
+		t.Errorf("Expected typed s6a interface stats, got %#v", stats.InterfaceStats["s6a"])
+	}
+
+	var found bool
+	for _, r := range NewTransformer("h", "EIR").Transform(stats) {
+		if r.CounterID == CounterDiameterTotal && r.Value == 5 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected EIR records from the decoded document")
+	}
+
+	if _, err := statsmodel.UnmarshalServiceStats([]byte(`{"custom_metrics": {"eir": []}}`)); err == nil {
+		t.Error("Expected an error for a malformed eir section")
+	}
+}
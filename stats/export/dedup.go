@@ -0,0 +1,139 @@
+package export
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// recordDeduper suppresses identical records exported more than once for the same
+// (counter, cause code, tenant, hostname, period), e.g. when an ExportNow overlaps a
+// tick or two transformation paths produce the same record
+type recordDeduper struct {
+	window time.Duration
+
+	mu         sync.Mutex
+	seen       map[recordKey]seenRecord
+	suppressed uint64
+}
+
+// recordKey identifies a record within a dedup window
+type recordKey struct {
+	hostname  string
+	system    string
+	counterID int
+	causeCode int
+	tenant    string
+	period    int64
+}
+
+// seenRecord is the value exported for a key and when
+type seenRecord struct {
+	value  uint64
+	seenAt time.Time
+}
+
+// newRecordDeduper creates a deduper bucketing record timestamps into window-sized periods
+func newRecordDeduper(window time.Duration) *recordDeduper {
+	return &recordDeduper{window: window, seen: make(map[recordKey]seenRecord)}
+}
+
+// filter drops records already exported with the same value in their period, in place,
+// and returns the remaining records and how many were dropped
+func (d *recordDeduper) filter(records []MetricRecord, now time.Time) ([]MetricRecord, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sweep(now)
+
+	kept := records[:0]
+	for _, record := range records {
+		key := recordKey{
+			hostname:  record.Hostname,
+			system:    record.SystemName,
+			counterID: record.CounterID,
+			causeCode: record.CauseCode,
+			tenant:    record.Tenant,
+			period:    record.Timestamp.Truncate(d.window).UnixNano(),
+		}
+		if prev, ok := d.seen[key]; ok && prev.value == record.Value {
+			continue
+		}
+		d.seen[key] = seenRecord{value: record.Value, seenAt: now}
+		kept = append(kept, record)
+	}
+
+	dropped := len(records) - len(kept)
+	d.suppressed += uint64(dropped)
+	return kept, dropped
+}
+
+// sweep forgets keys not seen for two windows (caller holds the lock)
+func (d *recordDeduper) sweep(now time.Time) {
+	for key, prev := range d.seen {
+		if now.Sub(prev.seenAt) > 2*d.window {
+			delete(d.seen, key)
+		}
+	}
+}
+
+// SetDedupWindow enables suppressing records identical to one already exported for
+// the same counter, cause code, tenant and hostname within a window-sized period
+// (0 disables deduplication, the default)
+func (s *ExportScheduler) SetDedupWindow(window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if window <= 0 {
+		s.dedup = nil
+		return
+	}
+	s.dedup = newRecordDeduper(window)
+}
+
+// ApplyDedup applies the dedup window from config
+func (s *ExportScheduler) ApplyDedup(config *ExportConfig) {
+	if config.DedupWindow != 0 {
+		s.SetDedupWindow(config.DedupWindow)
+	}
+}
+
+// DedupSuppressed returns the number of duplicate records suppressed since the dedup
+// window was set
+func (s *ExportScheduler) DedupSuppressed() uint64 {
+	s.mu.RLock()
+	dedup := s.dedup
+	s.mu.RUnlock()
+	if dedup == nil {
+		return 0
+	}
+
+	dedup.mu.Lock()
+	defer dedup.mu.Unlock()
+	return dedup.suppressed
+}
+
+// ExportNow runs an export cycle immediately, independently of the ticker
+// A cycle overlapping a tick re-exports gauges unchanged; set a dedup window to suppress them
+func (s *ExportScheduler) ExportNow(ctx context.Context) {
+	s.exportCycle(ctx)
+}
+
+// dedupRecords drops duplicate records and, if any were dropped, appends a record
+// counting them (the count itself is never deduplicated)
+func (s *ExportScheduler) dedupRecords(records []MetricRecord, now time.Time) []MetricRecord {
+	s.mu.RLock()
+	dedup := s.dedup
+	s.mu.RUnlock()
+	if dedup == nil {
+		return records
+	}
+
+	records, dropped := dedup.filter(records, now)
+	if dropped > 0 {
+		s.logger.Debugw("Suppressed duplicate metric records",
+			"records", dropped)
+		count := []MetricRecord{s.transformer.createRecord(CounterDedupSuppressed, uint64(dropped), 0, now)}
+		records = append(records, s.transformer.scaleRecords(s.transformer.filterRecords(count))...)
+	}
+	return records
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestExportScheduler_Dedup tests an ExportNow overlapping a tick doesn't re-export unchanged records
+func TestExportScheduler_Dedup(t *testing.T) {
+	ctx := context.Background()
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	h := NewSchedulerHarness(collector, HarnessConfig{Clock: clock})
+	h.Scheduler.ApplyDedup(&ExportConfig{DedupWindow: time.Minute})
+
+	collector.SetActiveConnections(3)
+	collector.RecordRequest("diameter", true)
+	if records := h.Tick(ctx); len(records) == 0 {
+		t.Fatal("Expected records from the first cycle")
+	}
+
+	// Same instant: every gauge is unchanged and counters have no delta
+	h.Scheduler.ExportNow(ctx)
+	batches := h.Exporter.Batches()
+	overlap := batches[len(batches)-1].Records
+	if len(overlap) != 1 || overlap[0].CounterID != CounterDedupSuppressed || overlap[0].Value == 0 {
+		t.Errorf("Expected only the suppressed count, got %+v", overlap)
+	}
+	if got := h.Scheduler.DedupSuppressed(); got != overlap[0].Value {
+		t.Errorf("Expected DedupSuppressed %d, got %d", overlap[0].Value, got)
+	}
+
+	// A changed gauge in the same period is exported again
+	collector.SetActiveConnections(4)
+	h.Scheduler.ExportNow(ctx)
+	batches = h.Exporter.Batches()
+	found := false
+	for _, record := range batches[len(batches)-1].Records {
+		if record.CounterID == CounterActiveConnections && record.Value == 4 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the changed active connections gauge to be exported")
+	}
+
+	// The next period starts fresh
+	records := h.Tick(ctx)
+	for _, record := range records {
+		if record.CounterID == CounterDedupSuppressed {
+			t.Errorf("Expected no suppression in a new period, got %+v", record)
+		}
+	}
+}
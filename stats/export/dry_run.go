@@ -0,0 +1,65 @@
+package export
+
+import (
+	"context"
+)
+
+// SetDryRun makes export cycles log their records through the scheduler's logger
+// instead of sending them to the configured exporters (see SetDryRunExporter)
+func (s *ExportScheduler) SetDryRun(enabled bool) {
+	if !enabled {
+		s.SetDryRunExporter(nil)
+		return
+	}
+	exporter, _ := NewLogExporter(LogExporterConfig{Name: "dry-run"}, s.logger)
+	s.SetDryRunExporter(exporter)
+}
+
+// SetDryRunExporter makes export cycles hand their records to exporter, typically a
+// LogExporter, instead of the configured exporters (nil restores normal exports)
+// Dry-run cycles compute deltas as usual but persist neither the delta snapshot nor
+// the batch sequence, and the counter catalog isn't sent
+func (s *ExportScheduler) SetDryRunExporter(exporter Exporter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dryRun = exporter
+}
+
+// ApplyDryRun applies the dry-run flag and format from config
+func (s *ExportScheduler) ApplyDryRun(config *ExportConfig) error {
+	if !config.DryRun {
+		return nil
+	}
+	exporter, err := NewLogExporter(LogExporterConfig{Name: "dry-run", Format: config.DryRunFormat}, s.logger)
+	if err != nil {
+		return err
+	}
+	s.SetDryRunExporter(exporter)
+	return nil
+}
+
+// DryRun reports whether export cycles are dry runs
+func (s *ExportScheduler) DryRun() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dryRun != nil
+}
+
+// dryRunExporter returns the dry-run exporter, nil outside dry runs
+func (s *ExportScheduler) dryRunExporter() Exporter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dryRun
+}
+
+// exportDryRun hands a cycle's records to the dry-run exporter
+func (s *ExportScheduler) exportDryRun(ctx context.Context, exporter Exporter, records []MetricRecord) {
+	if err := exporter.Export(ctx, records); err != nil {
+		s.logger.Errorw("Dry-run export failed",
+			"exporter", exporter.Name(),
+			"error", err)
+		return
+	}
+	s.logger.Infow("Dry-run export cycle, records not sent",
+		"records", len(records))
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestCollector_RecordError tests errors are counted by type and interface and
+// deduplicated in the recent error ring
+func TestCollector_RecordError(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := statsmodel.NewFakeClock(start)
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{Clock: clock, RecentErrors: 2})
+
+	errTimeout := errors.New("peer hss01 timed out")
+	collector.RecordError("timeout", "diameter", errTimeout)
+	clock.Advance(time.Second)
+	collector.RecordError("db_error", "http", errors.New("connection refused"))
+	clock.Advance(time.Second)
+	collector.RecordError("timeout", "diameter", errTimeout) // Repeat moves to newest
+	clock.Advance(time.Second)
+	collector.RecordError("", "", nil) // Uncategorised
+
+	stats := collector.Snapshot()
+	if stats.Errors.Total != 4 {
+		t.Errorf("Errors.Total = %d, want 4", stats.Errors.Total)
+	}
+	wantByType := map[string]uint64{"timeout": 2, "db_error": 1}
+	if len(stats.Errors.ByType) != len(wantByType) {
+		t.Errorf("Errors.ByType = %v, want %v", stats.Errors.ByType, wantByType)
+	}
+	for errType, want := range wantByType {
+		if stats.Errors.ByType[errType] != want {
+			t.Errorf("Errors.ByType[%s] = %d, want %d", errType, stats.Errors.ByType[errType], want)
+		}
+	}
+	wantByInterface := map[string]uint64{"diameter": 2, "http": 1}
+	if len(stats.Errors.ByInterface) != len(wantByInterface) {
+		t.Errorf("Errors.ByInterface = %v, want %v", stats.Errors.ByInterface, wantByInterface)
+	}
+	for iface, want := range wantByInterface {
+		if stats.Errors.ByInterface[iface] != want {
+			t.Errorf("Errors.ByInterface[%s] = %d, want %d", iface, stats.Errors.ByInterface[iface], want)
+		}
+	}
+
+	// The ring keeps the two newest distinct errors, oldest first
+	recent := collector.RecentErrors()
+	if len(recent) != 2 {
+		t.Fatalf("RecentErrors() = %+v, want 2 entries", recent)
+	}
+	timeout := recent[0]
+	if timeout.Code != "timeout" || timeout.Interface != "diameter" || timeout.Message != errTimeout.Error() {
+		t.Errorf("RecentErrors()[0] = %+v, want the timeout", timeout)
+	}
+	if timeout.Count != 2 || !timeout.FirstSeen.Equal(start) || !timeout.Timestamp.Equal(start.Add(2*time.Second)) {
+		t.Errorf("Timeout count %d first seen %v last %v, want 2 from %v to %v",
+			timeout.Count, timeout.FirstSeen, timeout.Timestamp, start, start.Add(2*time.Second))
+	}
+	if recent[1].Code != "" || recent[1].Message != "" || recent[1].Count != 1 {
+		t.Errorf("RecentErrors()[1] = %+v, want the uncategorised error", recent[1])
+	}
+	if stats.Errors.LastError == nil || stats.Errors.LastError.Message != "" || !stats.Errors.LastError.Timestamp.Equal(start.Add(3*time.Second)) {
+		t.Errorf("Errors.LastError = %+v, want the uncategorised error", stats.Errors.LastError)
+	}
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// benchStats builds stats producing a few thousand records, like a busy EIR
+func benchStats() *statsmodel.ServiceStats {
+	eir := &statsmodel.EIRStats{
+		EquipmentChecks: statsmodel.EquipmentCheckStats{
+			Total: 1000,
+			ByInterface: map[string]statsmodel.InterfaceCheckStats{
+				"diameter": {Total: 500, Success: 450, Failed: 50, ByResultCode: map[int]uint64{2001: 450, 5012: 50}},
+				"http":     {Total: 500, Success: 450, Failed: 50, ByResultCode: map[int]uint64{200: 450, 500: 50}},
+			},
+		},
+		ByTAC: make(map[string]statsmodel.TACStats),
+	}
+	for i := 0; i < 1000; i++ {
+		eir.ByTAC[strconv.Itoa(35000000+i)] = statsmodel.TACStats{
+			Checks:   10,
+			ByStatus: map[string]uint64{"whitelisted": 8, "blacklisted": 1, "greylisted": 1},
+		}
+	}
+
+	return &statsmodel.ServiceStats{
+		Timestamp:     time.Now(),
+		Requests:      statsmodel.RequestStats{Total: 1000, Success: 900, Failed: 100},
+		CustomMetrics: statsmodel.CustomMetrics{"eir": eir},
+	}
+}
+
+// BenchmarkTransformer_Transform measures a cycle's transformation with and without slice reuse
+func BenchmarkTransformer_Transform(b *testing.B) {
+	stats := benchStats()
+
+	b.Run("fresh", func(b *testing.B) {
+		transformer := NewTransformer("bench", "EIR")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			transformer.Transform(stats)
+		}
+	})
+
+	b.Run("reuse", func(b *testing.B) {
+		transformer := NewTransformer("bench", "EIR")
+		var records []MetricRecord
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			records = transformer.TransformInto(records, stats)
+		}
+	})
+}
+
+// BenchmarkHTTPExporter_Export measures encoding and posting a cycle with pooled buffers
+func BenchmarkHTTPExporter_Export(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	exporter, err := NewHTTPExporter(HTTPExporterConfig{Name: "bench", URL: server.URL, ChunkSize: 1000}, &mockLogger{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	records := NewTransformer("bench", "EIR").Transform(benchStats())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := exporter.Export(context.Background(), records); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFileExporter_Export measures writing a cycle as JSONL through the reused encoder
+func BenchmarkFileExporter_Export(b *testing.B) {
+	exporter, err := NewFileExporter(FileExporterConfig{
+		Name: "bench", Path: filepath.Join(b.TempDir(), "metrics.jsonl"), MaxSizeMB: 1000,
+	}, &mockLogger{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer exporter.Close()
+	records := NewTransformer("bench", "EIR").Transform(benchStats())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := exporter.Export(context.Background(), records); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPostgresExporter_InsertQuery measures building a full batch's INSERT statement
+func BenchmarkPostgresExporter_InsertQuery(b *testing.B) {
+	exporter := &PostgresExporter{config: PostgresExporterConfig{TableName: "metrics", BatchSize: 1000}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		exporter.insertQuery(1000)
+	}
+}
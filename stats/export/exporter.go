@@ -1,6 +1,11 @@
 package export
 
-import "context"
+import (
+	"context"
+	"fmt"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
 
 // Exporter defines the interface for exporting metric records
 type Exporter interface {
@@ -16,10 +21,66 @@ type Exporter interface {
 
 // StatsCollectorInterface defines the interface for getting stats
 // This allows us to decouple from the specific implementation
+//
+// Deprecated: implement ServiceStatsProvider instead; GetStats must return *ServiceStats
 type StatsCollectorInterface interface {
 	GetStats() interface{}
 }
 
+// ServiceStatsProvider defines the typed interface for getting stats
+type ServiceStatsProvider interface {
+	GetServiceStats() *statsmodel.ServiceStats
+}
+
+// StatsFunc adapts a function to ServiceStatsProvider
+type StatsFunc func() *statsmodel.ServiceStats
+
+// GetServiceStats calls f
+func (f StatsFunc) GetServiceStats() *statsmodel.ServiceStats {
+	return f()
+}
+
+// ConvertStats adapts a source of service-specific stats to ServiceStatsProvider
+func ConvertStats[T any](get func() T, convert func(T) *statsmodel.ServiceStats) ServiceStatsProvider {
+	return StatsFunc(func() *statsmodel.ServiceStats {
+		return convert(get())
+	})
+}
+
+// AdaptCollector wraps a legacy collector as a ServiceStatsProvider
+// Collectors that already implement ServiceStatsProvider are returned as is
+func AdaptCollector(collector StatsCollectorInterface) ServiceStatsProvider {
+	if provider, ok := collector.(ServiceStatsProvider); ok {
+		return provider
+	}
+	return &legacyCollector{collector: collector}
+}
+
+// legacyCollector adapts StatsCollectorInterface to ServiceStatsProvider
+type legacyCollector struct {
+	collector StatsCollectorInterface
+}
+
+// GetServiceStats returns the collector's stats, or nil if they are not ServiceStats
+func (l *legacyCollector) GetServiceStats() *statsmodel.ServiceStats {
+	switch stats := l.collector.GetStats().(type) {
+	case *statsmodel.ServiceStats:
+		return stats
+	case statsmodel.ServiceStats:
+		return &stats
+	default:
+		return nil
+	}
+}
+
+// providerName returns the type name of the underlying stats source for logging
+func providerName(provider ServiceStatsProvider) string {
+	if legacy, ok := provider.(*legacyCollector); ok {
+		return fmt.Sprintf("%T", legacy.collector)
+	}
+	return fmt.Sprintf("%T", provider)
+}
+
 // Logger defines the logging interface used by exporters
 type Logger interface {
 	Infow(msg string, keysAndValues ...interface{})
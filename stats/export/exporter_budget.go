@@ -0,0 +1,165 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultExportTimeout bounds a single exporter's Export call when no timeout is configured
+const DefaultExportTimeout = 30 * time.Second
+
+// SlowExporterThreshold is the number of consecutive cycles an exporter must exceed
+// its timeout (or be cut off by the cycle budget) before it is reported as slow
+const SlowExporterThreshold = 3
+
+// ExporterStats are self-metrics about one exporter's time budget
+type ExporterStats struct {
+	Name                string        `json:"name"`
+	Timeout             time.Duration `json:"timeout"`
+	Exports             uint64        `json:"exports"`
+	Failures            uint64        `json:"failures"`
+	Overruns            uint64        `json:"overruns"`             // Exports that hit the timeout or cycle budget
+	ConsecutiveOverruns uint64        `json:"consecutive_overruns"` // Reset by an export within budget
+	LastDuration        time.Duration `json:"last_duration"`
+	MaxDuration         time.Duration `json:"max_duration"`
+	Slow                bool          `json:"slow"` // ConsecutiveOverruns reached SlowExporterThreshold
+}
+
+// exporterBudgets holds timeouts and per-exporter self-metrics for a scheduler
+type exporterBudgets struct {
+	mu          sync.Mutex
+	timeout     time.Duration            // Default per-exporter timeout
+	timeouts    map[string]time.Duration // Per-exporter overrides by name
+	cycleBudget time.Duration            // Total time allowed for a cycle (0 = unbounded)
+	stats       map[string]*ExporterStats
+}
+
+func newExporterBudgets() *exporterBudgets {
+	return &exporterBudgets{
+		timeout:  DefaultExportTimeout,
+		timeouts: make(map[string]time.Duration),
+		stats:    make(map[string]*ExporterStats),
+	}
+}
+
+// timeoutFor returns the timeout for the named exporter
+func (b *exporterBudgets) timeoutFor(name string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if d, ok := b.timeouts[name]; ok {
+		return d
+	}
+	return b.timeout
+}
+
+// cycleContext bounds ctx by the cycle budget
+func (b *exporterBudgets) cycleContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	b.mu.Lock()
+	budget := b.cycleBudget
+	b.mu.Unlock()
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, budget)
+}
+
+// observe records one export and reports whether the exporter just became slow
+func (b *exporterBudgets) observe(name string, timeout, duration time.Duration, err error) (stats ExporterStats, becameSlow bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.stats[name]
+	if !ok {
+		s = &ExporterStats{Name: name}
+		b.stats[name] = s
+	}
+
+	s.Timeout = timeout
+	s.Exports++
+	if err != nil {
+		s.Failures++
+	}
+	s.LastDuration = duration
+	if duration > s.MaxDuration {
+		s.MaxDuration = duration
+	}
+
+	if duration >= timeout || errors.Is(err, context.DeadlineExceeded) {
+		s.Overruns++
+		s.ConsecutiveOverruns++
+	} else {
+		s.ConsecutiveOverruns = 0
+	}
+
+	wasSlow := s.Slow
+	s.Slow = s.ConsecutiveOverruns >= SlowExporterThreshold
+	return *s, s.Slow && !wasSlow
+}
+
+// SetExportTimeout sets the default timeout for each exporter's Export call (default: 30s)
+func (s *ExportScheduler) SetExportTimeout(d time.Duration) {
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+	if d <= 0 {
+		d = DefaultExportTimeout
+	}
+	s.budgets.timeout = d
+}
+
+// SetExporterTimeout overrides the export timeout for the named exporter (0 removes the override)
+func (s *ExportScheduler) SetExporterTimeout(name string, d time.Duration) {
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+	if d <= 0 {
+		delete(s.budgets.timeouts, name)
+		return
+	}
+	s.budgets.timeouts[name] = d
+}
+
+// SetCycleBudget bounds the whole export cycle; exporters still running when it
+// expires have their contexts cancelled (0 = unbounded)
+func (s *ExportScheduler) SetCycleBudget(d time.Duration) {
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+	s.budgets.cycleBudget = d
+}
+
+// ApplyTimeouts applies the export timeout, cycle budget and per-exporter timeouts
+// from config; exporters are matched by name
+func (s *ExportScheduler) ApplyTimeouts(config *ExportConfig) {
+	if config.ExportTimeout > 0 {
+		s.SetExportTimeout(config.ExportTimeout)
+	}
+	s.SetCycleBudget(config.CycleBudget)
+	for _, exporter := range config.Exporters {
+		s.SetExporterTimeout(exporter.Name, exporter.ExportTimeout)
+	}
+}
+
+// ExporterStats returns time budget self-metrics for each exporter, sorted by name
+func (s *ExportScheduler) ExporterStats() []ExporterStats {
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+
+	result := make([]ExporterStats, 0, len(s.budgets.stats))
+	for _, stats := range s.budgets.stats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// SlowExporters returns the names of exporters that consistently exceed their timeout
+func (s *ExportScheduler) SlowExporters() []string {
+	var names []string
+	for _, stats := range s.ExporterStats() {
+		if stats.Slow {
+			names = append(names, stats.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestCollector_FailureSamples tests failed requests are sampled per counter and period
+func TestCollector_FailureSamples(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", FailureSamples: 2})
+
+	for i := 0; i < 5; i++ {
+		collector.RecordRequestSample(statsmodel.RequestSample{
+			Interface:  "diameter",
+			Operation:  "check",
+			Peer:       "mme1.example.com",
+			ResultCode: 5012,
+			LatencyMs:  float64(i),
+		})
+	}
+	collector.RecordRequestSample(statsmodel.RequestSample{Interface: "diameter", ResultCode: 2001})
+	collector.RecordRequestSample(statsmodel.RequestSample{Interface: "http", ResultCode: 503})
+
+	samples := collector.Snapshot().FailureSamples
+	if got := samples[statsmodel.FailureSampleKey("diameter", 5012)]; len(got) != 2 || got[1].LatencyMs != 1 || got[0].Timestamp.IsZero() {
+		t.Errorf("Expected the first 2 samples of diameter/5012, got %+v", got)
+	}
+	if len(samples["http/503"]) != 1 || len(samples) != 2 {
+		t.Errorf("Expected samples for diameter/5012 and http/503 only, got %+v", samples)
+	}
+
+	// Samples are kept until the period ends
+	if stats := collector.GetServiceStats(); len(stats.FailureSamples) != 2 {
+		t.Errorf("Expected the period's samples in GetServiceStats, got %+v", stats.FailureSamples)
+	}
+	if stats := collector.Snapshot(); stats.FailureSamples != nil {
+		t.Errorf("Expected no samples in a new period, got %+v", stats.FailureSamples)
+	}
+	collector.RecordRequestSample(statsmodel.RequestSample{Interface: "diameter", ResultCode: 5012, Timestamp: time.Unix(1, 0)})
+	if got := collector.Snapshot().FailureSamples["diameter/5012"]; len(got) != 1 || !got[0].Timestamp.Equal(time.Unix(1, 0)) {
+		t.Errorf("Expected a fresh sample in the new period, got %+v", got)
+	}
+
+	// Disabled by default
+	disabled := statsmodel.NewCollector(statsmodel.CollectorConfig{})
+	disabled.RecordRequestSample(statsmodel.RequestSample{Interface: "diameter", ResultCode: 5012})
+	if samples := disabled.Snapshot().FailureSamples; samples != nil {
+		t.Errorf("Expected no samples when disabled, got %+v", samples)
+	}
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileCodec compresses FileExporter output. Each batch is written as one
+// self-contained member (a gzip member, a zstd frame), so the active file is
+// readable up to the last completed batch and rotation never splits a stream;
+// concatenated members decompress as one stream with gzip and zstd readers
+type FileCodec interface {
+	// Extension is the file suffix identifying the codec, e.g. ".gz"
+	Extension() string
+
+	// NewWriter returns a writer compressing into w at level (0 = codec default)
+	NewWriter(w io.Writer, level int) (CodecWriter, error)
+
+	// NewReader returns a reader decompressing every member read from r
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// CodecWriter compresses one member per Close; Reset starts the next member on w
+type CodecWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+var (
+	fileCodecsMu sync.RWMutex
+	fileCodecs   = map[string]FileCodec{
+		"gzip": gzipCodec{},
+	}
+)
+
+// RegisterFileCodec registers a codec for FileExporterConfig.Codec, e.g. a zstd
+// codec built on a third-party encoder; "gzip" is built in
+func RegisterFileCodec(name string, codec FileCodec) {
+	fileCodecsMu.Lock()
+	defer fileCodecsMu.Unlock()
+	fileCodecs[name] = codec
+}
+
+// fileCodec returns the codec registered under name
+func fileCodec(name string) (FileCodec, error) {
+	fileCodecsMu.RLock()
+	defer fileCodecsMu.RUnlock()
+	codec, ok := fileCodecs[name]
+	if !ok {
+		names := make([]string, 0, len(fileCodecs))
+		for n := range fileCodecs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown file codec %q (registered: %s)", name, strings.Join(names, ", "))
+	}
+	return codec, nil
+}
+
+// fileCodecForPath returns the codec whose extension path ends with, if any
+func fileCodecForPath(path string) FileCodec {
+	fileCodecsMu.RLock()
+	defer fileCodecsMu.RUnlock()
+	for _, codec := range fileCodecs {
+		if strings.HasSuffix(path, codec.Extension()) {
+			return codec
+		}
+	}
+	return nil
+}
+
+// gzipCodec is the built-in gzip FileCodec
+type gzipCodec struct{}
+
+func (gzipCodec) Extension() string {
+	return ".gz"
+}
+
+func (gzipCodec) NewWriter(w io.Writer, level int) (CodecWriter, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
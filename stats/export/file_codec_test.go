@@ -0,0 +1,135 @@
+package export
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFileExporter_Gzip tests the active file is written as one gzip member per batch
+func TestFileExporter_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	exporter, err := CreateExporter(ExporterConfig{
+		Type:    "file",
+		Name:    "file",
+		Enabled: true,
+		Config:  map[string]interface{}{"path": path, "codec": "gzip", "level": 9},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("CreateExporter() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		records := []MetricRecord{{CounterID: CounterTotalRequests, Value: uint64(i)}, {CounterID: CounterFailedRequests, Value: 1}}
+		if err := exporter.Export(context.Background(), records); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+
+	// Every completed batch is readable before Close
+	records, err := readRecords(path + ".gz")
+	if err != nil {
+		t.Fatalf("readRecords() error = %v", err)
+	}
+	if len(records) != 6 || records[4].Value != 2 {
+		t.Errorf("Expected 6 records from 3 members, got %+v", records)
+	}
+	exporter.Close()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected the codec extension to be appended to the path")
+	}
+}
+
+// TestFileExporter_GzipSigned tests signed batches verify after decompression
+func TestFileExporter_GzipSigned(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	path := filepath.Join(t.TempDir(), "metrics.jsonl.gz")
+	exporter, err := NewFileExporter(FileExporterConfig{
+		Name:    "file",
+		Path:    path,
+		Codec:   "gzip",
+		Signing: &SigningConfig{Algorithm: SignatureHMACSHA256, Key: key},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewFileExporter() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := exporter.Export(context.Background(), []MetricRecord{{CounterID: 1, Value: uint64(i)}}); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+	exporter.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewSignatureVerifier(SigningConfig{Algorithm: SignatureHMACSHA256, Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches, err := verifier.VerifyJSONL(gz); err != nil || batches != 2 {
+		t.Errorf("VerifyJSONL() = %d, %v, want 2 batches", batches, err)
+	}
+}
+
+// hexCodec is a test codec hex-encoding its output, standing in for zstd
+type hexCodec struct{}
+
+func (hexCodec) Extension() string { return ".hex" }
+
+func (hexCodec) NewWriter(w io.Writer, level int) (CodecWriter, error) {
+	return &hexWriter{Writer: hex.NewEncoder(w)}, nil
+}
+
+func (hexCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(hex.NewDecoder(r)), nil
+}
+
+type hexWriter struct {
+	io.Writer
+}
+
+func (h *hexWriter) Close() error { return nil }
+
+func (h *hexWriter) Reset(w io.Writer) { h.Writer = hex.NewEncoder(w) }
+
+// TestFileExporter_RegisteredCodec tests codecs registered by services and unknown codec errors
+func TestFileExporter_RegisteredCodec(t *testing.T) {
+	if _, err := NewFileExporter(FileExporterConfig{Path: filepath.Join(t.TempDir(), "m.jsonl"), Codec: "zstd"}, &mockLogger{}); err == nil {
+		t.Error("Expected an error for an unregistered codec")
+	}
+
+	RegisterFileCodec("hex", hexCodec{})
+	path := filepath.Join(t.TempDir(), "m.jsonl")
+	exporter, err := NewFileExporter(FileExporterConfig{Path: path, Codec: "hex"}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewFileExporter() error = %v", err)
+	}
+	if err := exporter.Export(context.Background(), []MetricRecord{{CounterID: 1, Value: 5, Hostname: "eir-1"}}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	exporter.Close()
+
+	data, _ := os.ReadFile(path + ".hex")
+	if len(data) == 0 || strings.Contains(string(data), "eir-1") {
+		t.Errorf("Expected codec output, got %s", data)
+	}
+	records, err := readRecords(path + ".hex")
+	if err != nil || len(records) != 1 || records[0].Hostname != "eir-1" {
+		t.Errorf("readRecords() = %+v, %v", records, err)
+	}
+}
@@ -1,11 +1,14 @@
 package export
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,7 +22,12 @@ type FileExporter struct {
 	logger   Logger
 	writer   *lumberjack.Logger
 	mu       sync.Mutex
-	encoder  *json.Encoder
+	encoder  *json.Encoder // Encodes into buf, guarded by mu
+	buf      bytes.Buffer
+	signer   *BatchSigner // nil when signing is disabled
+
+	codec      CodecWriter // nil when the active file is uncompressed, guarded by mu
+	compressed bytes.Buffer
 }
 
 // NewFileExporter creates a new file exporter
@@ -30,21 +38,44 @@ func NewFileExporter(config FileExporterConfig, logger Logger) (*FileExporter, e
 		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
+	exporter := &FileExporter{
+		name:   config.Name,
+		logger: logger,
+	}
+
+	// Compress the active file; rotated files are then already compressed
+	if config.Codec != "" {
+		codec, err := fileCodec(config.Codec)
+		if err != nil {
+			return nil, err
+		}
+		if exporter.codec, err = codec.NewWriter(io.Discard, config.Level); err != nil {
+			return nil, fmt.Errorf("invalid %s level %d: %w", config.Codec, config.Level, err)
+		}
+		if !strings.HasSuffix(config.Path, codec.Extension()) {
+			config.Path += codec.Extension()
+		}
+		config.Compress = false
+	}
+
 	// Create lumberjack logger for rotation
-	writer := &lumberjack.Logger{
+	exporter.writer = &lumberjack.Logger{
 		Filename:   config.Path,
 		MaxSize:    config.MaxSizeMB, // megabytes
 		MaxBackups: config.MaxBackups,
 		MaxAge:     0,               // days (0 = don't delete old backups)
 		Compress:   config.Compress, // compress rotated files
 	}
+	exporter.config = config
 
-	exporter := &FileExporter{
-		name:   config.Name,
-		config: config,
-		logger: logger,
-		writer: writer,
+	if config.Signing != nil {
+		signer, err := NewBatchSigner(*config.Signing)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing config: %w", err)
+		}
+		exporter.signer = signer
 	}
+	exporter.encoder = json.NewEncoder(&exporter.buf)
 
 	return exporter, nil
 }
@@ -67,10 +98,32 @@ func (e *FileExporter) Export(ctx context.Context, records []MetricRecord) error
 
 	startTime := time.Now()
 
+	if e.signer != nil {
+		if err := e.writeSigned(records); err != nil {
+			return err
+		}
+		e.logger.Debugw("Exported signed metrics to file",
+			"exporter", e.name,
+			"records", len(records),
+			"duration_ms", time.Since(startTime).Milliseconds())
+		return nil
+	}
+
+	if e.codec != nil {
+		if err := e.writeCompressed(records); err != nil {
+			return err
+		}
+		e.logger.Debugw("Exported compressed metrics to file",
+			"exporter", e.name,
+			"records", len(records),
+			"duration_ms", time.Since(startTime).Milliseconds())
+		return nil
+	}
+
 	// Write each record as a single line
 	for _, record := range records {
-		data, err := json.Marshal(record)
-		if err != nil {
+		e.buf.Reset()
+		if err := e.encoder.Encode(record); err != nil {
 			e.logger.Errorw("Failed to marshal metric record",
 				"exporter", e.name,
 				"counter_id", record.CounterID,
@@ -79,7 +132,7 @@ func (e *FileExporter) Export(ctx context.Context, records []MetricRecord) error
 		}
 
 		// Write line to file
-		if _, err := e.writer.Write(append(data, '\n')); err != nil {
+		if _, err := e.writer.Write(e.buf.Bytes()); err != nil {
 			return fmt.Errorf("failed to write to file: %w", err)
 		}
 	}
@@ -93,6 +146,90 @@ func (e *FileExporter) Export(ctx context.Context, records []MetricRecord) error
 	return nil
 }
 
+// writeSigned writes the batch followed by a signature line over its record lines,
+// in a single write so rotation never separates them (caller holds mu)
+func (e *FileExporter) writeSigned(records []MetricRecord) error {
+	e.buf.Reset()
+	written := 0
+	for _, record := range records {
+		n := e.buf.Len()
+		if err := e.encoder.Encode(record); err != nil {
+			e.buf.Truncate(n)
+			e.logger.Errorw("Failed to marshal metric record",
+				"exporter", e.name,
+				"counter_id", record.CounterID,
+				"error", err)
+			continue
+		}
+		written++
+	}
+
+	sig := e.signer.Sign(e.buf.Bytes())
+	if err := e.encoder.Encode(fileSignatureLine{Signature: &sig, Records: written}); err != nil {
+		return fmt.Errorf("failed to marshal signature: %w", err)
+	}
+
+	return e.write(e.buf.Bytes())
+}
+
+// writeCompressed writes the batch as one compressed member (caller holds mu)
+func (e *FileExporter) writeCompressed(records []MetricRecord) error {
+	e.buf.Reset()
+	for _, record := range records {
+		n := e.buf.Len()
+		if err := e.encoder.Encode(record); err != nil {
+			e.buf.Truncate(n)
+			e.logger.Errorw("Failed to marshal metric record",
+				"exporter", e.name,
+				"counter_id", record.CounterID,
+				"error", err)
+		}
+	}
+	return e.write(e.buf.Bytes())
+}
+
+// write writes p in a single write, as one compressed member if a codec is set,
+// so rotation never separates it (caller holds mu)
+func (e *FileExporter) write(p []byte) error {
+	if e.codec != nil {
+		e.compressed.Reset()
+		e.codec.Reset(&e.compressed)
+		if _, err := e.codec.Write(p); err != nil {
+			return fmt.Errorf("failed to compress batch: %w", err)
+		}
+		if err := e.codec.Close(); err != nil {
+			return fmt.Errorf("failed to compress batch: %w", err)
+		}
+		p = e.compressed.Bytes()
+	}
+
+	if _, err := e.writer.Write(p); err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+	return nil
+}
+
+// ExportCatalog writes the counter catalog next to the archive as "<name>.catalog.json"
+func (e *FileExporter) ExportCatalog(ctx context.Context, catalog CounterCatalog) error {
+	path := strings.TrimSuffix(e.config.Path, filepath.Ext(e.config.Path)) + ".catalog.json"
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create catalog file: %w", err)
+	}
+	if err := catalog.WriteJSON(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write catalog file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write catalog file: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
 // Name returns the exporter name
 func (e *FileExporter) Name() string {
 	return e.name
@@ -0,0 +1,73 @@
+package export
+
+import "context"
+
+// SetExporterHandshake makes the scheduler send the named exporter the counter
+// catalog (catalog version, counters and cause code tables) before its first batch,
+// and again before the first batch after a failed export, when the receiver may
+// have restarted or the connection been re-established, so receivers can configure
+// decoding from it. The exporter must implement CatalogExporter
+func (s *ExportScheduler) SetExporterHandshake(name string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !enabled {
+		delete(s.handshakes, name)
+		return
+	}
+	if s.handshakes == nil {
+		s.handshakes = make(map[string]bool)
+	}
+	s.handshakes[name] = true
+}
+
+// ApplyHandshakes applies the per-exporter metadata handshake flags from config;
+// exporters are matched by name
+func (s *ExportScheduler) ApplyHandshakes(config *ExportConfig) {
+	for _, exporter := range config.Exporters {
+		s.SetExporterHandshake(exporter.Name, exporter.MetadataHandshake)
+	}
+}
+
+// handshakePending reports whether exporter is due a metadata handshake
+func (s *ExportScheduler) handshakePending(exporter Exporter) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.handshakes[exporter.Name()]
+}
+
+// setHandshakePending marks exporter as due a handshake, or not, if it has them enabled
+func (s *ExportScheduler) setHandshakePending(exporter Exporter, pending bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.handshakes[exporter.Name()]; ok {
+		s.handshakes[exporter.Name()] = pending
+	}
+}
+
+// handshake sends the counter catalog to exporter if it is due one; a failed
+// handshake is retried before the next batch
+func (s *ExportScheduler) handshake(ctx context.Context, exporter Exporter) {
+	if !s.handshakePending(exporter) {
+		return
+	}
+	catalogExporter, ok := exporter.(CatalogExporter)
+	if !ok {
+		s.logger.Warnw("Exporter does not support the metadata handshake",
+			"exporter", exporter.Name())
+		s.SetExporterHandshake(exporter.Name(), false)
+		return
+	}
+
+	catalog := NewCounterCatalog(s.transformer.config.Scaling)
+	if err := catalogExporter.ExportCatalog(ctx, catalog); err != nil {
+		s.logger.Errorw("Metadata handshake failed",
+			"exporter", exporter.Name(),
+			"error", err)
+		return
+	}
+	s.setHandshakePending(exporter, false)
+	s.logger.Infow("Sent metadata handshake",
+		"exporter", exporter.Name(),
+		"catalog_version", catalog.Version,
+		"counters", len(catalog.Counters))
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// catalogMemoryExporter is a MemoryExporter that records the catalogs it is sent
+type catalogMemoryExporter struct {
+	*MemoryExporter
+	mu       sync.Mutex
+	catalogs []CounterCatalog
+}
+
+func (e *catalogMemoryExporter) ExportCatalog(ctx context.Context, catalog CounterCatalog) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.catalogs = append(e.catalogs, catalog)
+	return nil
+}
+
+func (e *catalogMemoryExporter) catalogCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.catalogs)
+}
+
+// TestExportScheduler_MetadataHandshake tests the catalog is sent before the first batch and again after a failed export
+func TestExportScheduler_MetadataHandshake(t *testing.T) {
+	ctx := context.Background()
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	h := NewSchedulerHarness(collector, HarnessConfig{Clock: clock})
+
+	nms := &catalogMemoryExporter{MemoryExporter: NewMemoryExporter("nms")}
+	h.Scheduler.AddExporter(nms)
+	h.Scheduler.ApplyHandshakes(&ExportConfig{Exporters: []ExporterConfig{{Name: "nms", MetadataHandshake: true}}})
+
+	collector.RecordRequest("diameter", true)
+	h.Tick(ctx)
+	if n := nms.catalogCount(); n != 1 {
+		t.Fatalf("Expected a handshake before the first batch, got %d", n)
+	}
+	if c := nms.catalogs[0]; len(c.Counters) == 0 || c.CauseCodes["source"] == nil {
+		t.Errorf("Expected the counter mapping in the handshake, got %+v", c)
+	}
+
+	collector.RecordRequest("diameter", true)
+	h.Tick(ctx)
+	if n := nms.catalogCount(); n != 1 {
+		t.Errorf("Expected no handshake while connected, got %d", n)
+	}
+
+	// A failed export means the receiver may have restarted: handshake again on recovery
+	nms.SetError(errors.New("connection reset"))
+	h.Tick(ctx)
+	nms.SetError(nil)
+	h.Tick(ctx)
+	if n := nms.catalogCount(); n != 2 {
+		t.Errorf("Expected a handshake after recovering, got %d", n)
+	}
+
+	// The harness exporter has no handshake enabled
+	if len(h.Exporter.Batches()) != 4 {
+		t.Errorf("Expected the other exporter to get every batch, got %d", len(h.Exporter.Batches()))
+	}
+}
+
+// TestPushClient_MetadataHandshake tests the catalog is sent after the hello of every connect
+func TestPushClient_MetadataHandshake(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var received []CounterCatalog
+	agg, err := NewAggregatorServer(AggregatorServerConfig{
+		Exporters: []Exporter{NewMemoryExporter("central")},
+		OnCatalog: func(sessionID string, catalog CounterCatalog) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, catalog)
+		},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewPushClient(PushClientConfig{
+		Name:       "push",
+		AckTimeout: time.Second,
+		Dialer: func(ctx context.Context) (PushStream, error) {
+			conn, server := net.Pipe()
+			go agg.ServePushStream(ctx, NewConnPushServerStream(server))
+			return NewConnPushStream(conn), nil
+		},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := NewCounterCatalog(nil)
+	catalog.Version = "7"
+	if err := client.ExportCatalog(ctx, catalog); err != nil {
+		t.Fatalf("ExportCatalog() error = %v", err)
+	}
+	records := []MetricRecord{{Hostname: "h", CounterID: CounterTotalRequests, Value: 1, Timestamp: time.Now()}}
+	if err := client.Export(ctx, records); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// Reconnect
+	client.Close()
+	records[0].Timestamp = records[0].Timestamp.Add(time.Minute)
+	if err := client.Export(ctx, records); err != nil {
+		t.Fatalf("Export() after reconnect error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("Expected a handshake on each connect, got %d", len(received))
+	}
+	if received[1].Version != "7" || len(received[1].Counters) != len(catalog.Counters) {
+		t.Errorf("Expected the catalog in the handshake, got version %q", received[1].Version)
+	}
+	if client.Pending() != 0 {
+		t.Errorf("Expected both batches acknowledged, %d pending", client.Pending())
+	}
+}
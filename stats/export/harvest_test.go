@@ -0,0 +1,68 @@
+package export
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+const (
+	testCounterLegacyRequests = 99101
+	testCounterLegacyQueue    = 99102
+	testCounterGoroutines     = 99103
+)
+
+// TestMetricsHarvester_Export tests expvar and runtime/metrics values flow through delta calculation and transformation
+func TestMetricsHarvester_Export(t *testing.T) {
+	requests := expvar.NewMap("test_harvest_legacy")
+	requests.Add("requests", 10)
+	requests.Add("queue_depth", 3)
+
+	HarvestedMetricCounters["test_harvest_legacy.requests"] = testCounterLegacyRequests
+	HarvestedMetricCounters["test_harvest_legacy.queue_depth"] = testCounterLegacyQueue
+	HarvestedMetricCounters["/sched/goroutines:goroutines"] = testCounterGoroutines
+	defer func() {
+		delete(HarvestedMetricCounters, "test_harvest_legacy.requests")
+		delete(HarvestedMetricCounters, "test_harvest_legacy.queue_depth")
+		delete(HarvestedMetricCounters, "/sched/goroutines:goroutines")
+	}()
+
+	ctx := context.Background()
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	collector.SetMetricsHarvester(statsmodel.NewMetricsHarvester(statsmodel.MetricsHarvesterConfig{
+		Expvars:        []string{"test_harvest_legacy"},
+		ExpvarCounters: []string{"test_harvest_legacy.req*"},
+		RuntimeMetrics: []string{"/sched/goroutines:goroutines", "/gc/heap/allocs-by-size:bytes"}, // Histograms are skipped
+	}))
+	h := NewSchedulerHarness(collector, HarnessConfig{Clock: clock})
+
+	section, ok := collector.Snapshot().CustomMetrics.Runtime()
+	if !ok {
+		t.Fatal("Expected a runtime section")
+	}
+	if section.Counters["test_harvest_legacy.requests"] != 10 || section.Gauges["test_harvest_legacy.queue_depth"] != 3 {
+		t.Errorf("Expected the expvar values by semantics, got %+v", section)
+	}
+	if section.Gauges["/sched/goroutines:goroutines"] == 0 || len(section.Gauges)+len(section.Counters) != 3 {
+		t.Errorf("Expected the goroutine count and no histogram, got %+v", section)
+	}
+
+	h.Tick(ctx)
+	requests.Add("requests", 5)
+	requests.Add("queue_depth", -1)
+	h.Tick(ctx)
+
+	if v, ok := h.Exporter.Last(testCounterLegacyRequests); !ok || v != 5 {
+		t.Errorf("Expected a delta of 5 requests, got %d (%v)", v, ok)
+	}
+	if v, ok := h.Exporter.Last(testCounterLegacyQueue); !ok || v != 2 {
+		t.Errorf("Expected queue depth gauge 2, got %d (%v)", v, ok)
+	}
+	if _, ok := h.Exporter.Last(testCounterGoroutines); !ok {
+		t.Error("Expected the goroutine count record")
+	}
+}
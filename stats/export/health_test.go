@@ -0,0 +1,95 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestHealthScorer_Score tests subsystem scores, reasons and the derived HealthStatus
+func TestHealthScorer_Score(t *testing.T) {
+	scorer := statsmodel.NewHealthScorer(statsmodel.HealthScorerConfig{
+		Weights: map[string]float64{statsmodel.HealthRequests: 3},
+	})
+	stats := &statsmodel.ServiceStats{
+		Timestamp:   time.Now(),
+		Connections: statsmodel.ConnectionStats{Total: 10, Active: 10},
+		Requests:    statsmodel.RequestStats{Total: 1000, Success: 945, Failed: 55},
+		Performance: statsmodel.PerformanceStats{P95LatencyMs: 20},
+		CustomMetrics: map[string]interface{}{"eir": &statsmodel.EIRStats{
+			CacheStats:  statsmodel.CacheStats{Hits: 90, Misses: 10},
+			DatabaseOps: statsmodel.DatabaseOperationStats{Queries: 100, Errors: 20, AvgLatencyMs: 5},
+		}},
+	}
+
+	score := scorer.Score(stats)
+	want := map[string]int{
+		statsmodel.HealthConnections: 100,
+		statsmodel.HealthRequests:    50, // 5.5% errors, halfway between 1% and 10%
+		statsmodel.HealthCache:       100,
+		statsmodel.HealthDatabase:    0, // 20% errors
+	}
+	for subsystem, s := range want {
+		if got := score.Subsystems[subsystem].Score; got != s {
+			t.Errorf("Expected %s score %d, got %d", subsystem, s, got)
+		}
+	}
+	if reasons := score.Subsystems[statsmodel.HealthDatabase].Reasons; len(reasons) != 1 {
+		t.Errorf("Expected the database error ratio as reason, got %v", reasons)
+	}
+	if score.Score != 58 { // (100 + 3*50 + 100 + 0) / 6
+		t.Errorf("Expected weighted overall score 58, got %d", score.Score)
+	}
+
+	health := scorer.Health(stats)
+	if health.Status != statsmodel.HealthStatusDegraded || health.Score != 58 {
+		t.Errorf("Expected degraded with score 58, got %s %d", health.Status, health.Score)
+	}
+	if check := health.Checks[statsmodel.HealthDatabase]; check.Status != statsmodel.CheckFail {
+		t.Errorf("Expected the database check to fail, got %+v", check)
+	}
+
+	if idle := scorer.Score(&statsmodel.ServiceStats{}); idle.Score != 100 || len(idle.Subsystems) != 0 {
+		t.Errorf("Expected an idle service to score 100 with no subsystems, got %+v", idle)
+	}
+}
+
+// TestTransformer_HealthScore tests health scores are exported as gauges
+func TestTransformer_HealthScore(t *testing.T) {
+	transformer := NewTransformerWithConfig("h", "s", TransformerConfig{
+		SampleRate:   1.0,
+		HealthScorer: statsmodel.NewHealthScorer(statsmodel.HealthScorerConfig{}),
+	})
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Requests: statsmodel.RequestStats{Total: 100, Success: 100},
+		Peers: map[string]statsmodel.PeerStats{
+			"hss1": {State: statsmodel.PeerStateOpen},
+			"hss2": {State: statsmodel.PeerStateClosed},
+		},
+	})
+
+	got := map[int]uint64{}
+	for _, r := range records {
+		switch r.CounterID {
+		case CounterHealthScore:
+			got[0] = r.Value
+		case CounterSubsystemHealthScore:
+			got[r.CauseCode] = r.Value
+		}
+	}
+	want := map[int]uint64{
+		0: 50,
+		HealthSubsystemCauseCodes[statsmodel.HealthConnections]: 0, // Half the peers down
+		HealthSubsystemCauseCodes[statsmodel.HealthRequests]:    100,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for code, v := range want {
+		if got[code] != v {
+			t.Errorf("Expected score %d for cause code %d, got %d", v, code, got[code])
+		}
+	}
+}
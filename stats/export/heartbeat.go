@@ -0,0 +1,43 @@
+package export
+
+// SetExporterHeartbeat makes every export cycle send the named exporter a
+// CounterHeartbeat record with value 1, even when there is nothing else to export,
+// so a fault management system watching for missing files or records can tell an
+// idle node from a down one
+func (s *ExportScheduler) SetExporterHeartbeat(name string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !enabled {
+		delete(s.heartbeats, name)
+		return
+	}
+	if s.heartbeats == nil {
+		s.heartbeats = make(map[string]bool)
+	}
+	s.heartbeats[name] = true
+}
+
+// ApplyHeartbeats applies the per-exporter heartbeat flags from config; exporters
+// are matched by name
+func (s *ExportScheduler) ApplyHeartbeats(config *ExportConfig) {
+	for _, exporter := range config.Exporters {
+		s.SetExporterHeartbeat(exporter.Name, exporter.Heartbeat)
+	}
+}
+
+// heartbeatExporters returns the names of the exporters sent a heartbeat record
+func (s *ExportScheduler) heartbeatExporters() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	heartbeats := make(map[string]bool, len(s.heartbeats))
+	for name := range s.heartbeats {
+		heartbeats[name] = true
+	}
+	return heartbeats
+}
+
+// withHeartbeat returns records followed by the heartbeat record, leaving records
+// untouched as it is shared by the cycle's exporters
+func withHeartbeat(records []MetricRecord, heartbeat MetricRecord) []MetricRecord {
+	return append(records[:len(records):len(records)], heartbeat)
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestExportScheduler_Heartbeat tests heartbeat exporters get a record every cycle, idle or not
+func TestExportScheduler_Heartbeat(t *testing.T) {
+	ctx := context.Background()
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	h := NewSchedulerHarness(collector, HarnessConfig{
+		Clock:       clock,
+		Transformer: TransformerConfig{SampleRate: 1.0, IncludeCounters: []int{CounterTotalRequests}},
+	})
+
+	nms := NewMemoryExporter("nms")
+	h.Scheduler.AddExporter(nms)
+	h.Scheduler.ApplyHeartbeats(&ExportConfig{Exporters: []ExporterConfig{{Name: "nms", Heartbeat: true}}})
+
+	collector.RecordRequest("diameter", true)
+	h.Tick(ctx)
+	h.Ticks(ctx, 2) // Idle
+
+	batches := nms.Batches()
+	if len(batches) != 3 {
+		t.Fatalf("Expected a batch every cycle, got %d", len(batches))
+	}
+	for i, batch := range batches {
+		last := batch.Records[len(batch.Records)-1]
+		if last.CounterID != CounterHeartbeat || last.Value != 1 || last.PeriodEnd.IsZero() {
+			t.Errorf("Cycle %d: expected a heartbeat record last, got %+v", i, last)
+		}
+	}
+	if len(batches[1].Records) != 1 {
+		t.Errorf("Expected only the heartbeat in an idle cycle, got %+v", batches[1].Records)
+	}
+
+	// The exporter without a heartbeat sees neither the heartbeat nor the idle cycles
+	for _, batch := range h.Exporter.Batches() {
+		for _, r := range batch.Records {
+			if r.CounterID == CounterHeartbeat {
+				t.Errorf("Unexpected heartbeat sent to %s", h.Exporter.Name())
+			}
+		}
+	}
+	if got := len(h.Exporter.Batches()); got != 1 {
+		t.Errorf("Expected 1 batch without heartbeat, got %d", got)
+	}
+}
@@ -4,18 +4,34 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Headers describing each export request's place in its cycle
+const (
+	HeaderExportSession  = "X-Export-Session"  // Identifies the exporter process; unpersisted sequences restart with a new session
+	HeaderExportSequence = "X-Export-Sequence" // Batch sequence from the scheduler (see BatchSequence), else incremented per Export call
+	HeaderExportChunk    = "X-Export-Chunk"    // Chunk position within the cycle as "i/N" (1-based)
+	HeaderExportRecords  = "X-Export-Records"  // Total records in the cycle across all chunks
+)
+
 // HTTPExporter exports metrics to an HTTP endpoint
 type HTTPExporter struct {
 	name       string
 	config     HTTPExporterConfig
 	logger     Logger
 	httpClient *http.Client
+	signer     *BatchSigner // nil when signing is disabled
+	session    string
+	sequence   atomic.Uint64
+	acked      atomic.Uint64 // Highest sequence whose chunks were all accepted
 }
 
 // NewHTTPExporter creates a new HTTP exporter
@@ -36,27 +52,135 @@ func NewHTTPExporter(config HTTPExporterConfig, logger Logger) (*HTTPExporter, e
 		config.RetryDelay = 1 * time.Second
 	}
 
+	if config.Parallelism <= 0 {
+		config.Parallelism = 4
+	}
+
+	httpClient := &http.Client{
+		Timeout: config.Timeout,
+	}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS config: %w", err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		httpClient.Transport = transport
+	}
+
+	var signer *BatchSigner
+	if config.Signing != nil {
+		var err error
+		if signer, err = NewBatchSigner(*config.Signing); err != nil {
+			return nil, fmt.Errorf("invalid signing config: %w", err)
+		}
+	}
+
 	return &HTTPExporter{
-		name:   config.Name,
-		config: config,
-		logger: logger,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		name:       config.Name,
+		config:     config,
+		logger:     logger,
+		httpClient: httpClient,
+		signer:     signer,
+		session:    newSessionID(),
 	}, nil
 }
 
 // Export sends metric records to HTTP endpoint as JSON
+// With ChunkSize set, large record sets are split into chunks sent by up to Parallelism
+// concurrent requests; every request carries the cycle's sequence number and its chunk
+// position so the receiver can reassemble the cycle or detect lost chunks
 func (e *HTTPExporter) Export(ctx context.Context, records []MetricRecord) error {
 	if len(records) == 0 {
 		return nil
 	}
 
-	// Marshal records to JSON
-	data, err := json.Marshal(records)
-	if err != nil {
+	sequence, ok := BatchSequence(ctx)
+	if ok {
+		e.sequence.Store(sequence)
+	} else {
+		sequence = e.sequence.Add(1)
+	}
+	chunks := chunkRecords(records, e.config.ChunkSize)
+
+	sem := make(chan struct{}, e.config.Parallelism)
+	errs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		header := http.Header{}
+		header.Set(HeaderExportSession, e.session)
+		header.Set(HeaderExportSequence, strconv.FormatUint(sequence, 10))
+		header.Set(HeaderExportChunk, strconv.Itoa(i+1)+"/"+strconv.Itoa(len(chunks)))
+		header.Set(HeaderExportRecords, strconv.Itoa(len(records)))
+
+		select {
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, chunk []MetricRecord) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := e.exportChunk(ctx, chunk, header); err != nil {
+				if len(chunks) > 1 {
+					err = fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+				}
+				errs[i] = err
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	e.ack(sequence)
+	return nil
+}
+
+// ack records sequence as accepted by the endpoint
+func (e *HTTPExporter) ack(sequence uint64) {
+	for {
+		acked := e.acked.Load()
+		if sequence <= acked || e.acked.CompareAndSwap(acked, sequence) {
+			return
+		}
+	}
+}
+
+// AckedSequence returns the highest batch sequence the endpoint accepted with a 2xx
+// response for every chunk; failed batches are reported by Export and not resent
+func (e *HTTPExporter) AckedSequence() uint64 {
+	return e.acked.Load()
+}
+
+// chunkRecords splits records into chunks of at most size records (size <= 0 = one chunk)
+func chunkRecords(records []MetricRecord, size int) [][]MetricRecord {
+	if size <= 0 || len(records) <= size {
+		return [][]MetricRecord{records}
+	}
+
+	chunks := make([][]MetricRecord, 0, (len(records)+size-1)/size)
+	for start := 0; start < len(records); start += size {
+		chunks = append(chunks, records[start:min(start+size, len(records))])
+	}
+	return chunks
+}
+
+// exportChunk sends one chunk of records with retries
+func (e *HTTPExporter) exportChunk(ctx context.Context, records []MetricRecord, header http.Header) error {
+	// Marshal records to JSON into a pooled buffer
+	buf := getBuffer()
+	if err := json.NewEncoder(buf).Encode(records); err != nil {
+		putBuffer(buf)
 		return fmt.Errorf("failed to marshal records: %w", err)
 	}
+	data := newPayload(buf)
+	defer data.release()
 
 	// Retry logic
 	var lastErr error
@@ -69,13 +193,15 @@ func (e *HTTPExporter) Export(ctx context.Context, records []MetricRecord) error
 		}
 
 		startTime := time.Now()
-		err := e.sendRequest(ctx, data)
+		err := e.sendRequest(ctx, data, header)
 		duration := time.Since(startTime)
 
 		if err == nil {
 			e.logger.Debugw("Exported metrics via HTTP",
 				"exporter", e.name,
 				"records", len(records),
+				"sequence", header.Get(HeaderExportSequence),
+				"chunk", header.Get(HeaderExportChunk),
 				"attempt", attempt,
 				"duration_ms", duration.Milliseconds())
 			return nil
@@ -84,6 +210,8 @@ func (e *HTTPExporter) Export(ctx context.Context, records []MetricRecord) error
 		lastErr = err
 		e.logger.Warnw("HTTP export attempt failed",
 			"exporter", e.name,
+			"sequence", header.Get(HeaderExportSequence),
+			"chunk", header.Get(HeaderExportChunk),
 			"attempt", attempt,
 			"max_attempts", e.config.RetryAttempts,
 			"error", err)
@@ -101,20 +229,46 @@ func (e *HTTPExporter) Export(ctx context.Context, records []MetricRecord) error
 	return fmt.Errorf("failed after %d attempts: %w", e.config.RetryAttempts, lastErr)
 }
 
+// ExportCatalog posts the counter catalog as JSON to CatalogURL, if configured
+func (e *HTTPExporter) ExportCatalog(ctx context.Context, catalog CounterCatalog) error {
+	if e.config.CatalogURL == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	return e.post(ctx, e.config.CatalogURL, newPayload(bytes.NewBuffer(data)), nil)
+}
+
 // sendRequest sends a single HTTP request
-func (e *HTTPExporter) sendRequest(ctx context.Context, data []byte) error {
-	req, err := http.NewRequestWithContext(ctx, "POST", e.config.URL, bytes.NewReader(data))
+func (e *HTTPExporter) sendRequest(ctx context.Context, data *payload, header http.Header) error {
+	return e.post(ctx, e.config.URL, data, header)
+}
+
+// post sends data as JSON to url with the given extra headers
+func (e *HTTPExporter) post(ctx context.Context, url string, data *payload, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, data.body())
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	req.ContentLength = int64(data.buf.Len())
+	req.GetBody = func() (io.ReadCloser, error) { return data.body(), nil }
 
 	// Set default Content-Type
 	req.Header.Set("Content-Type", "application/json")
 
-	// Add custom headers
+	// Add chunk metadata and custom headers
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	for key, value := range e.config.Headers {
 		req.Header.Set(key, value)
 	}
+	if e.signer != nil {
+		e.signer.setHeaders(req.Header, data.buf.Bytes())
+	}
 
 	resp, err := e.httpClient.Do(req)
 	if err != nil {
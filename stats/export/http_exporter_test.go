@@ -0,0 +1,105 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHTTPExporter_Chunking tests large cycles are split into concurrent chunks carrying sequence metadata
+func TestHTTPExporter_Chunking(t *testing.T) {
+	var mu sync.Mutex
+	chunks := make(map[string][]string) // Sequence -> chunk positions
+	received := 0
+	var inFlight, maxInFlight atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var records []MetricRecord
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get(HeaderExportSession) == "" || r.Header.Get(HeaderExportRecords) != "10" {
+			http.Error(w, "missing metadata", http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		seq := r.Header.Get(HeaderExportSequence)
+		chunks[seq] = append(chunks[seq], r.Header.Get(HeaderExportChunk))
+		received += len(records)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	exporter, err := NewHTTPExporter(HTTPExporterConfig{Name: "http", URL: server.URL, ChunkSize: 3, Parallelism: 2}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewHTTPExporter() error = %v", err)
+	}
+
+	records := make([]MetricRecord, 10)
+	for i := range records {
+		records[i] = MetricRecord{CounterID: i + 1, Value: 1, Hostname: "h", Timestamp: time.Now()}
+	}
+
+	for cycle := 0; cycle < 2; cycle++ {
+		if err := exporter.Export(context.Background(), records); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+
+	if received != 20 {
+		t.Errorf("Expected 20 records received, got %d", received)
+	}
+	for _, seq := range []string{"1", "2"} {
+		got := chunks[seq]
+		sort.Strings(got)
+		if len(got) != 4 || got[0] != "1/4" || got[3] != "4/4" {
+			t.Errorf("Expected chunks 1/4..4/4 for sequence %s, got %v", seq, got)
+		}
+	}
+	if m := maxInFlight.Load(); m > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", m)
+	}
+}
+
+// TestHTTPExporter_ChunkFailure tests a failed chunk fails the export while the others are delivered
+func TestHTTPExporter_ChunkFailure(t *testing.T) {
+	var delivered atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(HeaderExportChunk) == "2/2" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		delivered.Add(1)
+	}))
+	defer server.Close()
+
+	exporter, _ := NewHTTPExporter(HTTPExporterConfig{
+		Name: "http", URL: server.URL, ChunkSize: 1, RetryAttempts: 1,
+	}, &mockLogger{})
+
+	records := []MetricRecord{{CounterID: 1, Value: 1}, {CounterID: 2, Value: 1}}
+	if err := exporter.Export(context.Background(), records); err == nil {
+		t.Fatal("Expected an error for the failed chunk")
+	}
+	if delivered.Load() != 1 {
+		t.Errorf("Expected the first chunk to be delivered, got %d", delivered.Load())
+	}
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestExportScheduler_DryRun tests dry-run cycles print records instead of exporting them
+func TestExportScheduler_DryRun(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR"})
+	h := NewSchedulerHarness(collector, HarnessConfig{})
+
+	var out bytes.Buffer
+	dryRun, err := NewLogExporter(LogExporterConfig{Name: "dry-run", Format: LogFormatTable, Output: &out}, nopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Scheduler.SetDryRunExporter(dryRun)
+
+	collector.RecordRequest("diameter", true)
+	collector.RecordResultCode("diameter", 5012)
+	h.Tick(context.Background())
+
+	if batches := h.Exporter.Batches(); len(batches) != 0 {
+		t.Errorf("Expected no exports in a dry run, got %d batches", len(batches))
+	}
+	if h.Scheduler.Sequence() != 0 {
+		t.Errorf("Expected the batch sequence untouched, got %d", h.Scheduler.Sequence())
+	}
+	table := out.String()
+	for _, want := range []string{"COUNTER", "total_requests", "diameter_result_code", "5012"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("Expected %q in the dry-run table:\n%s", want, table)
+		}
+	}
+
+	// Leaving dry-run exports the next cycle's deltas
+	h.Scheduler.SetDryRun(false)
+	collector.RecordRequest("diameter", true)
+	h.Tick(context.Background())
+	if got := h.Exporter.Sum(CounterTotalRequests); got != 1 {
+		t.Errorf("Expected a delta of 1 request after the dry run, got %d", got)
+	}
+}
+
+// TestCreateExporter_Log tests the log exporter type and its format validation
+func TestCreateExporter_Log(t *testing.T) {
+	exporter, err := CreateExporter(ExporterConfig{Type: "log", Name: "commissioning", Config: map[string]interface{}{"format": "table"}}, nopLogger{})
+	if err != nil {
+		t.Fatalf("CreateExporter failed: %v", err)
+	}
+	if _, ok := exporter.(*LogExporter); !ok || exporter.Name() != "commissioning" {
+		t.Errorf("Expected a LogExporter named commissioning, got %T %s", exporter, exporter.Name())
+	}
+
+	if _, err := CreateExporter(ExporterConfig{Type: "log", Config: map[string]interface{}{"format": "xml"}}, nopLogger{}); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
@@ -0,0 +1,173 @@
+package export
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBatch is one Export call captured by a MemoryExporter
+type MemoryBatch struct {
+	Records    []MetricRecord
+	Sequence   uint64 // Batch sequence from the context (0 if none)
+	ExportedAt time.Time
+}
+
+// MemoryExporter keeps exported records in memory, for integration tests of a
+// stats pipeline without a database or HTTP server
+type MemoryExporter struct {
+	name string
+
+	mu      sync.Mutex
+	batches []MemoryBatch
+	err     error         // Returned by Export when set
+	notify  chan struct{} // Closed and replaced on every export
+	closed  bool
+}
+
+// NewMemoryExporter creates an in-memory exporter
+func NewMemoryExporter(name string) *MemoryExporter {
+	return &MemoryExporter{
+		name:   name,
+		notify: make(chan struct{}),
+	}
+}
+
+// Export captures a copy of records as one batch
+func (e *MemoryExporter) Export(ctx context.Context, records []MetricRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.err != nil {
+		return e.err
+	}
+
+	sequence, _ := BatchSequence(ctx)
+	e.batches = append(e.batches, MemoryBatch{
+		Records:    append([]MetricRecord(nil), records...),
+		Sequence:   sequence,
+		ExportedAt: time.Now(),
+	})
+	close(e.notify)
+	e.notify = make(chan struct{})
+	return nil
+}
+
+// SetError makes subsequent exports fail with err (nil restores success)
+func (e *MemoryExporter) SetError(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = err
+}
+
+// Batches returns the captured batches in export order
+func (e *MemoryExporter) Batches() []MemoryBatch {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]MemoryBatch(nil), e.batches...)
+}
+
+// LastBatch returns the most recent batch
+func (e *MemoryExporter) LastBatch() (MemoryBatch, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.batches) == 0 {
+		return MemoryBatch{}, false
+	}
+	return e.batches[len(e.batches)-1], true
+}
+
+// Records returns the records of every batch in export order
+func (e *MemoryExporter) Records() []MetricRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var records []MetricRecord
+	for _, batch := range e.batches {
+		records = append(records, batch.Records...)
+	}
+	return records
+}
+
+// RecordsFor returns every captured record of a counter, optionally limited to one cause code
+func (e *MemoryExporter) RecordsFor(counterID int, causeCode ...int) []MetricRecord {
+	var matched []MetricRecord
+	for _, record := range e.Records() {
+		if record.CounterID != counterID {
+			continue
+		}
+		if len(causeCode) > 0 && record.CauseCode != causeCode[0] {
+			continue
+		}
+		matched = append(matched, record)
+	}
+	return matched
+}
+
+// Last returns a counter's value in the most recent batch that contains it
+func (e *MemoryExporter) Last(counterID int, causeCode ...int) (uint64, bool) {
+	records := e.RecordsFor(counterID, causeCode...)
+	if len(records) == 0 {
+		return 0, false
+	}
+	return records[len(records)-1].Value, true
+}
+
+// Sum returns the total of a counter's values across all batches, which for delta
+// counters is the count since the first export
+func (e *MemoryExporter) Sum(counterID int, causeCode ...int) uint64 {
+	var sum uint64
+	for _, record := range e.RecordsFor(counterID, causeCode...) {
+		sum += record.Value
+	}
+	return sum
+}
+
+// WaitForBatches blocks until at least n batches were captured or ctx is done
+func (e *MemoryExporter) WaitForBatches(ctx context.Context, n int) error {
+	for {
+		e.mu.Lock()
+		count, notify := len(e.batches), e.notify
+		e.mu.Unlock()
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// Reset discards the captured batches
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = nil
+}
+
+// Closed reports whether Close was called
+func (e *MemoryExporter) Closed() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closed
+}
+
+// Name returns the exporter name
+func (e *MemoryExporter) Name() string {
+	return e.name
+}
+
+// Close marks the exporter closed; captured batches stay readable
+func (e *MemoryExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	return nil
+}
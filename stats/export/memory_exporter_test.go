@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestSchedulerHarness tests the harness exports per-cycle deltas captured by the memory exporter
+func TestSchedulerHarness(t *testing.T) {
+	clock := statsmodel.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	h := NewSchedulerHarness(collector, HarnessConfig{Clock: clock})
+	ctx := context.Background()
+
+	collector.RecordRequest("diameter", true)
+	collector.RecordRequest("diameter", true)
+	first := h.Tick(ctx)
+	if len(first) == 0 {
+		t.Fatal("Expected records from the first cycle")
+	}
+	if want := clock.Now(); !first[0].Timestamp.Equal(want) {
+		t.Errorf("Expected timestamp %v from the fake clock, got %v", want, first[0].Timestamp)
+	}
+
+	collector.RecordRequest("diameter", false)
+	h.Tick(ctx)
+
+	if got, _ := h.Exporter.Last(CounterTotalRequests); got != 1 {
+		t.Errorf("Expected a delta of 1 request in the second cycle, got %d", got)
+	}
+	if got := h.Exporter.Sum(CounterTotalRequests); got != 3 {
+		t.Errorf("Expected 3 requests across cycles, got %d", got)
+	}
+	if n := len(h.Exporter.Batches()); n != 2 {
+		t.Errorf("Expected 2 batches, got %d", n)
+	}
+}
+
+// TestMemoryExporter tests captured batches are copies and errors can be injected
+func TestMemoryExporter(t *testing.T) {
+	exporter := NewMemoryExporter("memory")
+	ctx := context.Background()
+
+	records := []MetricRecord{{CounterID: 1, Value: 5, CauseCode: 7}}
+	if err := exporter.Export(ctx, records); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	records[0].Value = 99
+	if got, ok := exporter.Last(1, 7); !ok || got != 5 {
+		t.Errorf("Expected the captured value 5, got %d", got)
+	}
+	if _, ok := exporter.Last(1, 8); ok {
+		t.Error("Expected no record for another cause code")
+	}
+
+	exporter.SetError(errors.New("down"))
+	if err := exporter.Export(ctx, records); err == nil {
+		t.Error("Expected the injected error")
+	}
+	exporter.SetError(nil)
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	go exporter.Export(ctx, records)
+	if err := exporter.WaitForBatches(waitCtx, 2); err != nil {
+		t.Errorf("WaitForBatches() error = %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"fmt"
+	"time"
+)
+
+// periodConfig labels each cycle's records with the period they cover
+type periodConfig struct {
+	location *time.Location // Zone periods are expressed and aligned in
+	align    bool           // Snap periods to interval boundaries of the local day
+	last     time.Time      // End of the previous cycle's period
+}
+
+// SetPeriodLocation sets the time zone of PeriodStart/PeriodEnd (default: time.Local)
+func (s *ExportScheduler) SetPeriodLocation(loc *time.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if loc == nil {
+		loc = time.Local
+	}
+	s.period.location = loc
+}
+
+// SetPeriodAlignment snaps periods to interval boundaries counted from local midnight
+// (e.g. :00, :15, :30, :45 for a 15m interval), labeling each cycle with the period
+// that ended at or before it; otherwise a period runs from the previous cycle to this one
+func (s *ExportScheduler) SetPeriodAlignment(align bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.period.align = align
+}
+
+// ApplyPeriods applies the period time zone and alignment from config
+func (s *ExportScheduler) ApplyPeriods(config *ExportConfig) error {
+	if config.PeriodTimezone != "" {
+		loc, err := time.LoadLocation(config.PeriodTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid period_timezone: %w", err)
+		}
+		s.SetPeriodLocation(loc)
+	}
+	s.SetPeriodAlignment(config.PeriodAlign)
+	return nil
+}
+
+// nextPeriod returns the period for a cycle running at now and records its end
+func (s *ExportScheduler) nextPeriod(now time.Time) (start, end time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loc := s.period.location
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+
+	if s.period.align {
+		end = alignPeriod(now, s.interval)
+		start = periodBefore(end, s.interval)
+	} else {
+		end = now
+		start = s.period.last
+		if start.IsZero() {
+			start = now.Add(-s.interval)
+		}
+	}
+
+	s.period.last = end
+	return start.In(loc), end
+}
+
+// alignPeriod returns the latest interval boundary at or before t, counting from
+// t's local midnight so boundaries follow the wall clock across DST changes; in the
+// repeated hour after clocks fall back, boundaries keep t's offset
+// Intervals of a day or more align to local midnight
+func alignPeriod(t time.Time, interval time.Duration) time.Time {
+	if interval <= 0 || interval >= 24*time.Hour {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+
+	// Step back along the wall clock rather than rebuilding the boundary with
+	// time.Date, which is ambiguous for wall times that occur twice
+	elapsed := wallElapsed(t)
+	return t.Add(-(elapsed - elapsed.Truncate(interval)) - time.Duration(t.Nanosecond()))
+}
+
+// periodBefore returns the start of the aligned period ending at end
+func periodBefore(end time.Time, interval time.Duration) time.Time {
+	if interval >= 24*time.Hour {
+		days := int(interval / (24 * time.Hour))
+		return end.AddDate(0, 0, -days)
+	}
+	return end.Add(-interval)
+}
+
+// wallElapsed returns the wall-clock time of day of t as a duration
+func wallElapsed(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestAlignPeriod tests 15-minute boundaries follow the local wall clock across DST changes
+func TestAlignPeriod(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fallBack := time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC) // 02:00 EDT becomes 01:00 EST
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval time.Duration
+		want     string
+	}{
+		{"plain", time.Date(2024, 6, 1, 10, 7, 30, 5, ny), 15 * time.Minute, "2024-06-01T10:00:00-04:00"},
+		{"after spring forward", time.Date(2024, 3, 10, 3, 20, 0, 0, ny), 15 * time.Minute, "2024-03-10T03:15:00-04:00"},
+		{"first 01:20 after fall back", fallBack.Add(-40 * time.Minute), 15 * time.Minute, "2024-11-03T01:15:00-04:00"},
+		{"second 01:20 after fall back", fallBack.Add(20 * time.Minute), 15 * time.Minute, "2024-11-03T01:15:00-05:00"},
+		{"daily", time.Date(2024, 11, 3, 15, 0, 0, 0, ny), 24 * time.Hour, "2024-11-03T00:00:00-04:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alignPeriod(tt.t.In(ny), tt.interval).Format(time.RFC3339); got != tt.want {
+				t.Errorf("alignPeriod() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// The 25-hour day still yields one daily period
+	end := time.Date(2024, 11, 4, 0, 0, 0, 0, ny)
+	if got := periodBefore(end, 24*time.Hour); !got.Equal(time.Date(2024, 11, 3, 0, 0, 0, 0, ny)) || end.Sub(got) != 25*time.Hour {
+		t.Errorf("Expected the period before %v to start at local midnight 25h earlier, got %v", end, got)
+	}
+}
+
+// TestExportScheduler_Periods tests records carry aligned local-time periods
+func TestExportScheduler_Periods(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	clock := statsmodel.NewFakeClock(time.Date(2024, 3, 31, 0, 52, 0, 0, time.UTC)) // 01:52 CET, before 02:00 -> 03:00 CEST
+	h := NewSchedulerHarness(StatsFunc(func() *statsmodel.ServiceStats {
+		return &statsmodel.ServiceStats{}
+	}), HarnessConfig{Clock: clock, Interval: 15 * time.Minute})
+	if err := h.Scheduler.ApplyPeriods(&ExportConfig{PeriodTimezone: "Europe/Paris", PeriodAlign: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	records := h.Tick(context.Background()) // 02:07 CET = 03:07 CEST
+	if len(records) == 0 {
+		t.Fatal("Expected records")
+	}
+	r := records[0]
+	if r.PeriodStart.Format(time.RFC3339) != "2024-03-31T01:45:00+01:00" || r.PeriodEnd.Format(time.RFC3339) != "2024-03-31T03:00:00+02:00" {
+		t.Errorf("Expected period 01:45 CET - 03:00 CEST, got %s - %s", r.PeriodStart.Format(time.RFC3339), r.PeriodEnd.Format(time.RFC3339))
+	}
+	if r.PeriodEnd.Location().String() != paris.String() {
+		t.Errorf("Expected the period in Europe/Paris, got %v", r.PeriodEnd.Location())
+	}
+
+	if err := h.Scheduler.ApplyPeriods(&ExportConfig{PeriodTimezone: "Mars/Olympus"}); err == nil {
+		t.Error("Expected an error for an unknown zone")
+	}
+}
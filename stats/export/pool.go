@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBuffer caps the buffers kept for reuse, so one oversized cycle doesn't pin its memory
+const maxPooledBuffer = 4 << 20
+
+// bufferPool reuses encoding buffers across export cycles
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// payload is a request body backed by a pooled buffer
+// The HTTP transport may keep reading a request body after Client.Do returns, so the
+// buffer only goes back to the pool once every body handed out has been closed
+type payload struct {
+	buf  *bytes.Buffer
+	open atomic.Int32
+}
+
+// newPayload wraps buf as a payload
+func newPayload(buf *bytes.Buffer) *payload {
+	return &payload{buf: buf}
+}
+
+// body returns a new reader over the payload
+func (p *payload) body() io.ReadCloser {
+	p.open.Add(1)
+	return &payloadBody{Reader: bytes.NewReader(p.buf.Bytes()), p: p}
+}
+
+// release recycles the buffer unless a body is still in use
+func (p *payload) release() {
+	if p.open.Load() == 0 {
+		putBuffer(p.buf)
+	}
+}
+
+// payloadBody is one reader over a payload
+type payloadBody struct {
+	*bytes.Reader
+	p      *payload
+	closed atomic.Bool
+}
+
+// Close marks the body as no longer in use
+func (b *payloadBody) Close() error {
+	if b.closed.CompareAndSwap(false, true) {
+		b.p.open.Add(-1)
+	}
+	return nil
+}
+
+// maxCachedCauseCodes bounds the cause code cache; it is cleared when full
+const maxCachedCauseCodes = 10000
+
+// causeCodeCache interns cause codes parsed from strings (such as TACs), which repeat
+// every cycle; invalid codes are cached as -1
+type causeCodeCache struct {
+	mu    sync.RWMutex
+	codes map[string]int
+}
+
+// code returns the integer cause code for s
+func (c *causeCodeCache) code(s string) (int, bool) {
+	c.mu.RLock()
+	code, ok := c.codes[s]
+	c.mu.RUnlock()
+	if ok {
+		return code, code >= 0
+	}
+
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 0 {
+		code = -1
+	}
+
+	c.mu.Lock()
+	if c.codes == nil || len(c.codes) >= maxCachedCauseCodes {
+		c.codes = make(map[string]int)
+	}
+	c.codes[s] = code
+	c.mu.Unlock()
+	return code, code >= 0
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
@@ -16,6 +18,9 @@ type PostgresExporter struct {
 	config PostgresExporterConfig
 	logger Logger
 	db     *sql.DB
+
+	fullBatchQuery sync.Once // Caches the INSERT for full batches, which repeat every cycle
+	fullQuery      string
 }
 
 // NewPostgresExporter creates a new PostgreSQL exporter
@@ -36,8 +41,16 @@ func NewPostgresExporter(config PostgresExporterConfig, logger Logger) (*Postgre
 		config.MaxRetry = 3
 	}
 
+	connStr := config.ConnectionString
+	if config.TLS != nil {
+		var err error
+		if connStr, err = config.TLS.PostgresConnectionString(connStr); err != nil {
+			return nil, err
+		}
+	}
+
 	// Open database connection
-	db, err := sql.Open("postgres", config.ConnectionString)
+	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -81,6 +94,7 @@ func (e *PostgresExporter) ensureTable(ctx context.Context) error {
 			counter_id INTEGER NOT NULL,
 			value DOUBLE PRECISION NOT NULL,
 			cause_code VARCHAR(100),
+			tenant VARCHAR(100),
 			hostname VARCHAR(255) NOT NULL,
 			system_name VARCHAR(100) NOT NULL,
 			timestamp TIMESTAMPTZ NOT NULL,
@@ -92,6 +106,12 @@ func (e *PostgresExporter) ensureTable(ctx context.Context) error {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// Tables created before per-tenant records lack the tenant column
+	alter := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS tenant VARCHAR(100)", e.config.TableName)
+	if _, err := e.db.ExecContext(ctx, alter); err != nil {
+		return fmt.Errorf("failed to add tenant column: %w", err)
+	}
+
 	// Create indexes
 	indexes := []string{
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_counter_time ON %s(counter_id, timestamp DESC)", e.config.TableName, e.config.TableName),
@@ -140,6 +160,32 @@ func (e *PostgresExporter) Export(ctx context.Context, records []MetricRecord) e
 	return nil
 }
 
+// insertQuery builds the multi-row INSERT statement for n records
+func (e *PostgresExporter) insertQuery(n int) string {
+	var b strings.Builder
+	b.Grow(len(e.config.TableName) + 104 + n*42)
+	b.WriteString("INSERT INTO ")
+	b.WriteString(e.config.TableName)
+	b.WriteString(" (counter_id, value, cause_code, tenant, hostname, system_name, timestamp) VALUES ")
+
+	var num [20]byte
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := 1; j <= 7; j++ {
+			if j > 1 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('$')
+			b.Write(strconv.AppendInt(num[:0], int64(i*7+j), 10))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
 // insertBatch inserts a batch of records using a single multi-row INSERT
 func (e *PostgresExporter) insertBatch(ctx context.Context, records []MetricRecord) error {
 	if len(records) == 0 {
@@ -147,28 +193,26 @@ func (e *PostgresExporter) insertBatch(ctx context.Context, records []MetricReco
 	}
 
 	// Build multi-row INSERT statement
-	placeholders := make([]string, len(records))
-	values := make([]interface{}, 0, len(records)*6)
-
-	for i, record := range records {
-		offset := i * 6
-		placeholders[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)",
-			offset+1, offset+2, offset+3, offset+4, offset+5, offset+6)
-
+	values := make([]interface{}, 0, len(records)*7)
+	for _, record := range records {
 		values = append(values,
 			record.CounterID,
 			record.Value,
 			nullInt(record.CauseCode),
+			nullString(record.Tenant),
 			record.Hostname,
 			record.SystemName,
 			record.Timestamp,
 		)
 	}
 
-	query := fmt.Sprintf(`
-		INSERT INTO %s (counter_id, value, cause_code, hostname, system_name, timestamp)
-		VALUES %s
-	`, e.config.TableName, strings.Join(placeholders, ", "))
+	var query string
+	if len(records) == e.config.BatchSize {
+		e.fullBatchQuery.Do(func() { e.fullQuery = e.insertQuery(len(records)) })
+		query = e.fullQuery
+	} else {
+		query = e.insertQuery(len(records))
+	}
 
 	// Execute with retry
 	var lastErr error
@@ -0,0 +1,407 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Push frame types
+const (
+	PushFrameHello = "hello" // Opens a stream; Sequence is the last batch the client saw acknowledged
+	PushFrameBatch = "batch" // Carries one export cycle's records
+
+	// PushFrameMetadata carries the counter catalog, sent after each hello once
+	// ExportCatalog was called, so the aggregator can decode the session's records
+	PushFrameMetadata = "metadata"
+)
+
+// PushFrame is a message sent from a PushClient to the aggregator
+type PushFrame struct {
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id"` // Identifies the client process; sequences restart with a new session
+	Sequence  uint64          `json:"sequence"`
+	Records   []MetricRecord  `json:"records,omitempty"`
+	Catalog   *CounterCatalog `json:"catalog,omitempty"` // Metadata frames only
+
+	// Signature of the JSON-encoded Records, when the client signs batches
+	Signature *BatchSignature `json:"signature,omitempty"`
+}
+
+// PushAck acknowledges every batch up to and including Sequence
+// In reply to a hello frame, Sequence is the last batch the aggregator has received for the session,
+// and the client resends everything after it. A non-empty Error rejects the batch permanently
+type PushAck struct {
+	Sequence uint64 `json:"sequence"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PushStream is a persistent, ordered connection to an aggregator
+// Implementations wrap a transport such as a gRPC bidirectional stream or a WebSocket
+type PushStream interface {
+	Send(ctx context.Context, frame PushFrame) error
+	Recv(ctx context.Context) (PushAck, error)
+	Close() error
+}
+
+// PushDialer opens a new PushStream
+type PushDialer func(ctx context.Context) (PushStream, error)
+
+// ErrPushRejected is returned when the aggregator rejects a batch
+var ErrPushRejected = errors.New("push batch rejected by aggregator")
+
+// PushClientConfig defines configuration for PushClient
+type PushClientConfig struct {
+	Name       string         `json:"name"`
+	Dialer     PushDialer     `json:"-"`
+	AckTimeout time.Duration  `json:"ack_timeout"` // Per-batch wait for acknowledgement (default: 10s)
+	MaxPending int            `json:"max_pending"` // Unacknowledged batches kept for resend (default: 100)
+	Signing    *SigningConfig `json:"signing"`     // Signs each batch frame's records (optional)
+}
+
+// PushClient streams each export cycle's records to a central aggregator over a
+// persistent connection, as an alternative to per-cycle HTTP POSTs
+//
+// Every batch carries a sequence number and stays pending until acknowledged. After a
+// reconnect the client sends a hello frame, the aggregator answers with the last
+// sequence it received, and the client resends the remaining pending batches in order.
+// When more than MaxPending batches are unacknowledged the oldest are dropped.
+type PushClient struct {
+	name   string
+	config PushClientConfig
+	logger Logger
+	signer *BatchSigner // nil when signing is disabled
+
+	mu        sync.Mutex
+	sessionID string
+	stream    PushStream
+	nextSeq   uint64
+	pending   []PushFrame
+	dropped   uint64
+	catalog   *CounterCatalog // Sent on every connect, see ExportCatalog
+}
+
+// NewPushClient creates a new push client
+func NewPushClient(config PushClientConfig, logger Logger) (*PushClient, error) {
+	if config.Dialer == nil {
+		return nil, fmt.Errorf("push client dialer is required")
+	}
+
+	if config.AckTimeout == 0 {
+		config.AckTimeout = 10 * time.Second
+	}
+
+	if config.MaxPending == 0 {
+		config.MaxPending = 100
+	}
+
+	var signer *BatchSigner
+	if config.Signing != nil {
+		var err error
+		if signer, err = NewBatchSigner(*config.Signing); err != nil {
+			return nil, fmt.Errorf("invalid signing config: %w", err)
+		}
+	}
+
+	return &PushClient{
+		name:      config.Name,
+		config:    config,
+		logger:    logger,
+		signer:    signer,
+		sessionID: newSessionID(),
+		nextSeq:   1,
+	}, nil
+}
+
+// newSessionID returns a random session identifier
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Export queues records as the next batch and streams it, waiting for acknowledgement
+// On failure the batch stays pending and is resent on the next Export after reconnecting
+func (c *PushClient) Export(ctx context.Context, records []MetricRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Use the scheduler's batch sequence when it keeps the stream increasing
+	seq := c.nextSeq
+	if batchSeq, ok := BatchSequence(ctx); ok && batchSeq > seq {
+		seq = batchSeq
+	}
+	frame := PushFrame{Type: PushFrameBatch, SessionID: c.sessionID, Sequence: seq, Records: records}
+	if c.signer != nil {
+		data, err := json.Marshal(records)
+		if err != nil {
+			return fmt.Errorf("failed to marshal records: %w", err)
+		}
+		sig := c.signer.Sign(data)
+		frame.Signature = &sig
+	}
+	c.nextSeq = seq + 1
+	c.pending = append(c.pending, frame)
+	if over := len(c.pending) - c.config.MaxPending; over > 0 {
+		c.dropped += uint64(over)
+		c.logger.Warnw("Dropping unacknowledged push batches",
+			"exporter", c.name,
+			"dropped", over,
+			"oldest_sequence", c.pending[0].Sequence)
+		c.pending = c.pending[over:]
+	}
+
+	ackCtx, cancel := context.WithTimeout(ctx, c.config.AckTimeout)
+	defer cancel()
+
+	if c.stream == nil {
+		if err := c.connect(ackCtx); err != nil {
+			return fmt.Errorf("push connect failed: %w", err)
+		}
+	} else if err := c.stream.Send(ackCtx, frame); err != nil {
+		c.disconnect()
+		return fmt.Errorf("push send failed: %w", err)
+	}
+
+	if err := c.awaitAck(ackCtx, frame.Sequence); err != nil {
+		if !errors.Is(err, ErrPushRejected) {
+			c.disconnect()
+		}
+		return err
+	}
+
+	c.logger.Debugw("Pushed metrics",
+		"exporter", c.name,
+		"records", len(records),
+		"sequence", frame.Sequence)
+	return nil
+}
+
+// connect opens a stream, resumes from the aggregator's last received sequence and
+// resends every batch after it
+func (c *PushClient) connect(ctx context.Context) error {
+	stream, err := c.config.Dialer(ctx)
+	if err != nil {
+		return err
+	}
+
+	hello := PushFrame{Type: PushFrameHello, SessionID: c.sessionID, Sequence: c.ackedSequence()}
+	if err := stream.Send(ctx, hello); err != nil {
+		stream.Close()
+		return err
+	}
+	resume, err := stream.Recv(ctx)
+	if err != nil {
+		stream.Close()
+		return err
+	}
+	c.stream = stream
+	c.trim(resume.Sequence)
+
+	if c.catalog != nil {
+		if err := c.sendCatalog(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, frame := range c.pending {
+		if err := stream.Send(ctx, frame); err != nil {
+			c.disconnect()
+			return err
+		}
+	}
+
+	c.logger.Infow("Push stream connected",
+		"exporter", c.name,
+		"session_id", c.sessionID,
+		"resume_from", resume.Sequence,
+		"resent", len(c.pending))
+	return nil
+}
+
+// ExportCatalog sends the counter catalog as a metadata frame, now if the stream is
+// connected, and after the hello of every later connect
+func (c *PushClient) ExportCatalog(ctx context.Context, catalog CounterCatalog) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.catalog = &catalog
+	if c.stream == nil {
+		return nil
+	}
+	ackCtx, cancel := context.WithTimeout(ctx, c.config.AckTimeout)
+	defer cancel()
+	return c.sendCatalog(ackCtx)
+}
+
+// sendCatalog sends the catalog on the connected stream and waits for its
+// acknowledgement, disconnecting on failure
+func (c *PushClient) sendCatalog(ctx context.Context) error {
+	frame := PushFrame{Type: PushFrameMetadata, SessionID: c.sessionID, Sequence: c.ackedSequence(), Catalog: c.catalog}
+	if err := c.stream.Send(ctx, frame); err != nil {
+		c.disconnect()
+		return fmt.Errorf("push metadata send failed: %w", err)
+	}
+	ack, err := c.stream.Recv(ctx)
+	if err != nil {
+		c.disconnect()
+		return fmt.Errorf("push metadata ack failed: %w", err)
+	}
+	c.trim(ack.Sequence)
+	return nil
+}
+
+// awaitAck reads acknowledgements until seq is acknowledged
+func (c *PushClient) awaitAck(ctx context.Context, seq uint64) error {
+	for {
+		ack, err := c.stream.Recv(ctx)
+		if err != nil {
+			return fmt.Errorf("push ack failed: %w", err)
+		}
+		c.trim(ack.Sequence)
+
+		if ack.Error != "" {
+			c.logger.Warnw("Push batch rejected",
+				"exporter", c.name,
+				"sequence", ack.Sequence,
+				"error", ack.Error)
+			if ack.Sequence == seq {
+				return fmt.Errorf("%w: %s", ErrPushRejected, ack.Error)
+			}
+		}
+		if ack.Sequence >= seq {
+			return nil
+		}
+	}
+}
+
+// trim drops pending batches acknowledged by seq
+func (c *PushClient) trim(seq uint64) {
+	i := 0
+	for i < len(c.pending) && c.pending[i].Sequence <= seq {
+		i++
+	}
+	c.pending = c.pending[i:]
+}
+
+// ackedSequence returns the highest sequence known to be acknowledged
+func (c *PushClient) ackedSequence() uint64 {
+	if len(c.pending) > 0 {
+		return c.pending[0].Sequence - 1
+	}
+	return c.nextSeq - 1
+}
+
+// AckedSequence returns the highest batch sequence the aggregator acknowledged
+func (c *PushClient) AckedSequence() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ackedSequence()
+}
+
+// disconnect closes the current stream
+func (c *PushClient) disconnect() {
+	if c.stream != nil {
+		c.stream.Close()
+		c.stream = nil
+	}
+}
+
+// Pending returns the number of unacknowledged batches
+func (c *PushClient) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// Dropped returns the number of batches dropped because MaxPending was exceeded
+func (c *PushClient) Dropped() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+// Name returns the exporter name
+func (c *PushClient) Name() string {
+	return c.name
+}
+
+// Close closes the stream; pending batches are discarded
+func (c *PushClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disconnect()
+	return nil
+}
+
+// NewTCPPushDialer returns a dialer for a plain TCP stream carrying newline-delimited
+// JSON frames and acks, for aggregators that don't front the stream with gRPC or WebSocket
+func NewTCPPushDialer(address string) PushDialer {
+	return func(ctx context.Context) (PushStream, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		return NewConnPushStream(conn), nil
+	}
+}
+
+// NewTLSPushDialer returns a NewTCPPushDialer equivalent that wraps the stream in TLS
+func NewTLSPushDialer(address string, config *tls.Config) PushDialer {
+	return func(ctx context.Context) (PushStream, error) {
+		d := tls.Dialer{Config: config}
+		conn, err := d.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		return NewConnPushStream(conn), nil
+	}
+}
+
+// NewConnPushStream wraps conn as a PushStream using newline-delimited JSON
+func NewConnPushStream(conn net.Conn) PushStream {
+	return &connPushStream{conn: conn, enc: json.NewEncoder(conn), dec: json.NewDecoder(conn)}
+}
+
+// connPushStream is a PushStream over a net.Conn
+type connPushStream struct {
+	conn net.Conn
+	enc  *json.Encoder
+	dec  *json.Decoder
+}
+
+func (s *connPushStream) Send(ctx context.Context, frame PushFrame) error {
+	s.setDeadline(ctx)
+	return s.enc.Encode(frame)
+}
+
+func (s *connPushStream) Recv(ctx context.Context) (PushAck, error) {
+	s.setDeadline(ctx)
+	var ack PushAck
+	err := s.dec.Decode(&ack)
+	return ack, err
+}
+
+func (s *connPushStream) Close() error {
+	return s.conn.Close()
+}
+
+// setDeadline applies the context deadline to the connection
+func (s *connPushStream) setDeadline(ctx context.Context) {
+	deadline, _ := ctx.Deadline()
+	s.conn.SetDeadline(deadline)
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeAggregator records batches per session over net.Pipe connections
+type fakeAggregator struct {
+	received map[string]uint64 // Last received sequence per session
+	batches  []PushFrame
+	conns    chan net.Conn
+}
+
+func newFakeAggregator() *fakeAggregator {
+	return &fakeAggregator{received: make(map[string]uint64), conns: make(chan net.Conn, 4)}
+}
+
+func (a *fakeAggregator) dialer() PushDialer {
+	return func(ctx context.Context) (PushStream, error) {
+		client, server := net.Pipe()
+		a.conns <- server
+		return NewConnPushStream(client), nil
+	}
+}
+
+// serve handles one connection, acknowledging at most limit batches before hanging up (0 = unlimited)
+func (a *fakeAggregator) serve(conn net.Conn, limit int, done chan<- struct{}) {
+	defer close(done)
+	defer conn.Close()
+
+	stream := NewConnPushStream(conn).(*connPushStream)
+
+	// Acks are written asynchronously since net.Pipe has no buffering
+	acks := make(chan PushAck, 16)
+	defer close(acks)
+	go func() {
+		for ack := range acks {
+			stream.enc.Encode(ack)
+		}
+	}()
+
+	acked := 0
+	for {
+		var frame PushFrame
+		if err := stream.dec.Decode(&frame); err != nil {
+			return
+		}
+		if frame.Type == PushFrameHello {
+			acks <- PushAck{Sequence: a.received[frame.SessionID]}
+			continue
+		}
+		if frame.Sequence <= a.received[frame.SessionID] {
+			continue
+		}
+		if limit > 0 && acked == limit {
+			return
+		}
+		a.received[frame.SessionID] = frame.Sequence
+		a.batches = append(a.batches, frame)
+		acks <- PushAck{Sequence: frame.Sequence}
+		acked++
+	}
+}
+
+func TestPushClient_ResumeAfterDisconnect(t *testing.T) {
+	agg := newFakeAggregator()
+	client, err := NewPushClient(PushClientConfig{Name: "push", Dialer: agg.dialer(), AckTimeout: time.Second}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewPushClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	batch := func(v uint64) []MetricRecord { return []MetricRecord{{CounterID: 1001, Value: v}} }
+
+	// First connection acknowledges one batch, then drops
+	done := make(chan struct{})
+	go func() { agg.serve(<-agg.conns, 1, done) }()
+
+	if err := client.Export(ctx, batch(1)); err != nil {
+		t.Fatalf("Export 1 failed: %v", err)
+	}
+	if err := client.Export(ctx, batch(2)); err == nil {
+		t.Fatal("Expected Export 2 to fail when the aggregator hangs up")
+	}
+	<-done
+	if client.Pending() != 1 {
+		t.Fatalf("Expected batch 2 pending, got %d pending", client.Pending())
+	}
+
+	// Reconnect resends batch 2 before batch 3
+	done = make(chan struct{})
+	go func() { agg.serve(<-agg.conns, 0, done) }()
+
+	if err := client.Export(ctx, batch(3)); err != nil {
+		t.Fatalf("Export 3 failed: %v", err)
+	}
+	if client.Pending() != 0 {
+		t.Errorf("Expected no pending batches, got %d", client.Pending())
+	}
+
+	client.Close()
+	<-done
+
+	if len(agg.batches) != 3 {
+		t.Fatalf("Expected 3 batches, got %d", len(agg.batches))
+	}
+	for i, frame := range agg.batches {
+		if frame.Sequence != uint64(i+1) || frame.Records[0].Value != uint64(i+1) {
+			t.Errorf("Batch %d: expected sequence and value %d, got %d/%d", i, i+1, frame.Sequence, frame.Records[0].Value)
+		}
+	}
+}
+
+func TestPushClient_MaxPending(t *testing.T) {
+	dialer := func(ctx context.Context) (PushStream, error) {
+		return nil, errors.New("aggregator unavailable")
+	}
+	client, _ := NewPushClient(PushClientConfig{Dialer: dialer, MaxPending: 2}, &mockLogger{})
+
+	for i := 0; i < 3; i++ {
+		if err := client.Export(context.Background(), []MetricRecord{{CounterID: 1001}}); err == nil {
+			t.Fatal("Expected Export to fail without an aggregator")
+		}
+	}
+	if client.Pending() != 2 || client.Dropped() != 1 {
+		t.Errorf("Expected 2 pending and 1 dropped, got %d and %d", client.Pending(), client.Dropped())
+	}
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RecordLimitStats reports the work of the per-cycle record limit
+type RecordLimitStats struct {
+	Limit           int    // Max records per cycle (0 = unlimited)
+	TruncatedCycles uint64 // Cycles that exceeded the limit
+	DroppedRecords  uint64 // Records dropped since the limit was set
+	LastDropped     int    // Records dropped by the most recent truncated cycle
+}
+
+// recordLimiter truncates cycles producing more than limit records, keeping the most
+// important ones:
+//  1. Records without cause code or tenant (the core KPIs), in transformer order
+//  2. Per-tenant records without cause code
+//  3. Records with a cause code
+//
+// Within tiers 2 and 3 the counters with the fewest records in the cycle come first,
+// so a counter whose cause codes or tenants suddenly explode is the first to be cut;
+// a counter's records are then ranked by value, highest first
+type recordLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	stats RecordLimitStats
+}
+
+// newRecordLimiter creates a limiter keeping at most limit records per cycle
+func newRecordLimiter(limit int) *recordLimiter {
+	return &recordLimiter{limit: limit, stats: RecordLimitStats{Limit: limit}}
+}
+
+// recordTier returns the priority tier of record, lower is kept first
+func recordTier(record MetricRecord) int {
+	switch {
+	case record.CauseCode == 0 && record.Tenant == "":
+		return 0
+	case record.CauseCode == 0:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// truncate returns the records to keep, in their original order, and how many
+// records of each counter ID were dropped
+func (l *recordLimiter) truncate(records []MetricRecord) ([]MetricRecord, map[int]int) {
+	if len(records) <= l.limit {
+		return records, nil
+	}
+
+	cardinality := make(map[int]int)
+	for _, record := range records {
+		if recordTier(record) > 0 {
+			cardinality[record.CounterID]++
+		}
+	}
+
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		a, b := records[i], records[j]
+		if c := cmp.Compare(recordTier(a), recordTier(b)); c != 0 || recordTier(a) == 0 {
+			return c
+		}
+		if c := cmp.Compare(cardinality[a.CounterID], cardinality[b.CounterID]); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.CounterID, b.CounterID); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Value, a.Value); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.CauseCode, b.CauseCode); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tenant, b.Tenant)
+	})
+
+	keep := make([]bool, len(records))
+	for _, i := range order[:l.limit] {
+		keep[i] = true
+	}
+	dropped := make(map[int]int)
+	kept := make([]MetricRecord, 0, l.limit)
+	for i, record := range records {
+		if keep[i] {
+			kept = append(kept, record)
+		} else {
+			dropped[record.CounterID]++
+		}
+	}
+
+	l.mu.Lock()
+	l.stats.TruncatedCycles++
+	l.stats.DroppedRecords += uint64(len(records) - len(kept))
+	l.stats.LastDropped = len(records) - len(kept)
+	l.mu.Unlock()
+	return kept, dropped
+}
+
+// SetMaxRecordsPerCycle limits the records exported per cycle to protect downstream
+// systems from a sudden explosion of cause codes or tenants (0 = unlimited, the default)
+// Truncated cycles end with truncation markers, not counted in the limit: a
+// CounterRecordsTruncated record with the number of records dropped, and a
+// CounterCounterTruncated record per affected counter, its ID as cause code
+func (s *ExportScheduler) SetMaxRecordsPerCycle(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit <= 0 {
+		s.recordLimit = nil
+		return
+	}
+	s.recordLimit = newRecordLimiter(limit)
+}
+
+// ApplyMaxRecords applies the per-cycle record limit from config
+func (s *ExportScheduler) ApplyMaxRecords(config *ExportConfig) {
+	if config.MaxRecordsPerCycle != 0 {
+		s.SetMaxRecordsPerCycle(config.MaxRecordsPerCycle)
+	}
+}
+
+// RecordLimitStats returns the record limit's self-metrics since it was set
+func (s *ExportScheduler) RecordLimitStats() RecordLimitStats {
+	s.mu.RLock()
+	limiter := s.recordLimit
+	s.mu.RUnlock()
+	if limiter == nil {
+		return RecordLimitStats{}
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return limiter.stats
+}
+
+// limitRecords truncates records to the record limit and, if any were dropped,
+// appends the truncation markers
+func (s *ExportScheduler) limitRecords(records []MetricRecord, now time.Time) []MetricRecord {
+	s.mu.RLock()
+	limiter := s.recordLimit
+	s.mu.RUnlock()
+	if limiter == nil {
+		return records
+	}
+
+	records, dropped := limiter.truncate(records)
+	if len(dropped) == 0 {
+		return records
+	}
+
+	counters := make([]int, 0, len(dropped))
+	total := 0
+	for counterID, n := range dropped {
+		counters = append(counters, counterID)
+		total += n
+	}
+	slices.Sort(counters)
+	s.logger.Warnw("Export cycle exceeded the record limit, records dropped",
+		"limit", limiter.limit,
+		"dropped", total,
+		"counters", counters)
+
+	markers := []MetricRecord{s.transformer.createRecord(CounterRecordsTruncated, uint64(total), 0, now)}
+	for _, counterID := range counters {
+		markers = append(markers, s.transformer.createRecord(CounterCounterTruncated, uint64(dropped[counterID]), counterID, now))
+	}
+	return append(records, s.transformer.scaleRecords(s.transformer.filterRecords(markers))...)
+}
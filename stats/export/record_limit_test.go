@@ -0,0 +1,62 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestExportScheduler_RecordLimit tests truncation keeps core KPIs and cuts the highest-cardinality counter first
+func TestExportScheduler_RecordLimit(t *testing.T) {
+	h := NewSchedulerHarness(statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR"}), HarnessConfig{})
+	h.Scheduler.ApplyMaxRecords(&ExportConfig{MaxRecordsPerCycle: 6})
+
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	records := []MetricRecord{
+		{CounterID: CounterDiameterResultCode, CauseCode: 5001, Value: 1},
+		{CounterID: CounterTotalRequests, Value: 100},
+		{CounterID: CounterDiameterResultCode, CauseCode: 5002, Value: 9},
+		{CounterID: CounterTenantRequests, Tenant: "slice-a", Value: 40},
+		{CounterID: CounterHTTPStatusCode, CauseCode: 503, Value: 2},
+		{CounterID: CounterDiameterResultCode, CauseCode: 2001, Value: 90},
+		{CounterID: CounterActiveConnections, Value: 3},
+		{CounterID: CounterDiameterResultCode, CauseCode: 5003, Value: 5},
+	}
+
+	got := h.Scheduler.limitRecords(records, now)
+	want := []MetricRecord{
+		{CounterID: CounterTotalRequests, Value: 100},
+		{CounterID: CounterDiameterResultCode, CauseCode: 5002, Value: 9},
+		{CounterID: CounterTenantRequests, Tenant: "slice-a", Value: 40},
+		{CounterID: CounterHTTPStatusCode, CauseCode: 503, Value: 2},
+		{CounterID: CounterDiameterResultCode, CauseCode: 2001, Value: 90},
+		{CounterID: CounterActiveConnections, Value: 3},
+	}
+	if len(got) != len(want)+2 {
+		t.Fatalf("Expected %d records and 2 markers, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].CounterID != w.CounterID || got[i].CauseCode != w.CauseCode || got[i].Value != w.Value {
+			t.Errorf("Record %d: expected %+v, got %+v", i, w, got[i])
+		}
+	}
+
+	total, perCounter := got[len(want)], got[len(want)+1]
+	if total.CounterID != CounterRecordsTruncated || total.Value != 2 {
+		t.Errorf("Expected a truncation marker for 2 records, got %+v", total)
+	}
+	if perCounter.CounterID != CounterCounterTruncated || perCounter.CauseCode != CounterDiameterResultCode || perCounter.Value != 2 {
+		t.Errorf("Expected 2 result code records marked as dropped, got %+v", perCounter)
+	}
+
+	stats := h.Scheduler.RecordLimitStats()
+	if stats.Limit != 6 || stats.TruncatedCycles != 1 || stats.DroppedRecords != 2 || stats.LastDropped != 2 {
+		t.Errorf("Unexpected record limit stats %+v", stats)
+	}
+
+	// Cycles within the limit are left alone
+	if got := h.Scheduler.limitRecords(want, now); len(got) != len(want) {
+		t.Errorf("Expected %d records untouched, got %d", len(want), len(got))
+	}
+}
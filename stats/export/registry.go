@@ -2,11 +2,28 @@ package export
 
 import (
 	"fmt"
+	"os"
 	"time"
 )
 
 // CreateExporter creates an exporter based on configuration
+// Network exporters (http, postgres, push) accept a common "tls" block, see TLSSettings
+// Any exporter accepts a "scrubbing" block, see ScrubbingProfile
 func CreateExporter(config ExporterConfig, logger Logger) (Exporter, error) {
+	profile, err := parseScrubbingProfile(config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := createExporter(config, logger)
+	if err != nil || profile == nil {
+		return exporter, err
+	}
+	return NewScrubbingExporter(exporter, *profile)
+}
+
+// createExporter creates the exporter of config's type
+func createExporter(config ExporterConfig, logger Logger) (Exporter, error) {
 	switch config.Type {
 	case "http":
 		return createHTTPExporter(config, logger)
@@ -14,11 +31,39 @@ func CreateExporter(config ExporterConfig, logger Logger) (Exporter, error) {
 		return createPostgresExporter(config, logger)
 	case "file":
 		return createFileExporter(config, logger)
+	case "push":
+		return createPushClient(config, logger)
+	case "memory":
+		return NewMemoryExporter(config.Name), nil
+	case "log":
+		return createLogExporter(config, logger)
+	case "composite":
+		return createCompositeExporter(config, logger)
 	default:
 		return nil, fmt.Errorf("unknown exporter type: %s", config.Type)
 	}
 }
 
+// createLogExporter creates a log exporter from generic config
+func createLogExporter(config ExporterConfig, logger Logger) (*LogExporter, error) {
+	logConfig := LogExporterConfig{Name: config.Name}
+
+	if format, ok := config.Config["format"].(string); ok {
+		logConfig.Format = format
+	}
+
+	// Extract table output stream
+	switch output, _ := config.Config["output"].(string); output {
+	case "", "stdout":
+	case "stderr":
+		logConfig.Output = os.Stderr
+	default:
+		return nil, fmt.Errorf("log exporter output must be stdout or stderr, got %q", output)
+	}
+
+	return NewLogExporter(logConfig, logger)
+}
+
 // createHTTPExporter creates an HTTP exporter from generic config
 func createHTTPExporter(config ExporterConfig, logger Logger) (*HTTPExporter, error) {
 	httpConfig := HTTPExporterConfig{
@@ -63,6 +108,39 @@ func createHTTPExporter(config ExporterConfig, logger Logger) (*HTTPExporter, er
 		}
 	}
 
+	// Extract catalog URL
+	if catalogURL, ok := config.Config["catalog_url"].(string); ok {
+		httpConfig.CatalogURL = catalogURL
+	}
+
+	// Extract chunk size
+	if chunkSize, ok := config.Config["chunk_size"].(int); ok {
+		httpConfig.ChunkSize = chunkSize
+	} else if chunkSizeFloat, ok := config.Config["chunk_size"].(float64); ok {
+		httpConfig.ChunkSize = int(chunkSizeFloat)
+	}
+
+	// Extract parallelism
+	if parallelism, ok := config.Config["parallelism"].(int); ok {
+		httpConfig.Parallelism = parallelism
+	} else if parallelismFloat, ok := config.Config["parallelism"].(float64); ok {
+		httpConfig.Parallelism = int(parallelismFloat)
+	}
+
+	// Extract TLS settings
+	tlsSettings, err := parseTLSSettings(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	httpConfig.TLS = tlsSettings
+
+	// Extract signing config
+	signing, err := parseSigningConfig(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	httpConfig.Signing = signing
+
 	return NewHTTPExporter(httpConfig, logger)
 }
 
@@ -98,6 +176,13 @@ func createPostgresExporter(config ExporterConfig, logger Logger) (*PostgresExpo
 		pgConfig.MaxRetry = int(maxRetryFloat)
 	}
 
+	// Extract TLS settings
+	tlsSettings, err := parseTLSSettings(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	pgConfig.TLS = tlsSettings
+
 	return NewPostgresExporter(pgConfig, logger)
 }
 
@@ -137,5 +222,130 @@ func createFileExporter(config ExporterConfig, logger Logger) (*FileExporter, er
 		fileConfig.Compress = compress
 	}
 
+	// Extract active file codec and level
+	if codec, ok := config.Config["codec"].(string); ok {
+		fileConfig.Codec = codec
+	}
+	if level, ok := config.Config["level"].(int); ok {
+		fileConfig.Level = level
+	} else if levelFloat, ok := config.Config["level"].(float64); ok {
+		fileConfig.Level = int(levelFloat)
+	}
+
+	// Extract signing config
+	signing, err := parseSigningConfig(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	fileConfig.Signing = signing
+
 	return NewFileExporter(fileConfig, logger)
 }
+
+// createPushClient creates a push client over TCP from generic config
+func createPushClient(config ExporterConfig, logger Logger) (*PushClient, error) {
+	pushConfig := PushClientConfig{
+		Name: config.Name,
+	}
+
+	// Extract address (required)
+	address, ok := config.Config["address"].(string)
+	if !ok || address == "" {
+		return nil, fmt.Errorf("Push exporter requires 'address' in config")
+	}
+	pushConfig.Dialer = NewTCPPushDialer(address)
+
+	// Extract TLS settings
+	tlsSettings, err := parseTLSSettings(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsSettings != nil {
+		tlsConfig, err := tlsSettings.Config()
+		if err != nil {
+			return nil, err
+		}
+		pushConfig.Dialer = NewTLSPushDialer(address, tlsConfig)
+	}
+
+	// Extract ack timeout
+	if ackTimeoutStr, ok := config.Config["ack_timeout"].(string); ok {
+		if duration, err := time.ParseDuration(ackTimeoutStr); err == nil {
+			pushConfig.AckTimeout = duration
+		}
+	}
+
+	// Extract max pending
+	if maxPending, ok := config.Config["max_pending"].(int); ok {
+		pushConfig.MaxPending = maxPending
+	} else if maxPendingFloat, ok := config.Config["max_pending"].(float64); ok {
+		pushConfig.MaxPending = int(maxPendingFloat)
+	}
+
+	// Extract signing config
+	signing, err := parseSigningConfig(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	pushConfig.Signing = signing
+
+	return NewPushClient(pushConfig, logger)
+}
+
+// createCompositeExporter creates a composite exporter and its targets from generic config
+func createCompositeExporter(config ExporterConfig, logger Logger) (*CompositeExporter, error) {
+	compositeConfig := CompositeExporterConfig{
+		Name: config.Name,
+	}
+
+	// Extract strategy
+	if strategy, ok := config.Config["strategy"].(string); ok {
+		compositeConfig.Strategy = strategy
+	}
+
+	// Extract quorum
+	if quorum, ok := config.Config["quorum"].(int); ok {
+		compositeConfig.Quorum = quorum
+	} else if quorumFloat, ok := config.Config["quorum"].(float64); ok {
+		compositeConfig.Quorum = int(quorumFloat)
+	}
+
+	// Extract targets (required), each configured like a top-level exporter
+	targets, ok := config.Config["targets"].([]interface{})
+	if !ok || len(targets) == 0 {
+		return nil, fmt.Errorf("Composite exporter requires 'targets' in config")
+	}
+	closeTargets := func() {
+		for _, target := range compositeConfig.Targets {
+			target.Close()
+		}
+	}
+	for i, targetInterface := range targets {
+		targetMap, ok := targetInterface.(map[string]interface{})
+		if !ok {
+			closeTargets()
+			return nil, fmt.Errorf("composite target %d is not a map", i)
+		}
+		targetConfig, err := parseExporterConfig(targetMap)
+		if err != nil {
+			closeTargets()
+			return nil, fmt.Errorf("failed to parse composite target %d: %w", i, err)
+		}
+		if !targetConfig.Enabled {
+			continue
+		}
+		target, err := CreateExporter(targetConfig, logger)
+		if err != nil {
+			closeTargets()
+			return nil, fmt.Errorf("failed to create composite target %s: %w", targetConfig.Name, err)
+		}
+		compositeConfig.Targets = append(compositeConfig.Targets, target)
+	}
+
+	exporter, err := NewCompositeExporter(compositeConfig, logger)
+	if err != nil {
+		closeTargets()
+		return nil, err
+	}
+	return exporter, nil
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestFormatStatsReport_Empty tests an empty collector reports zero counters and no ranked tables
+func TestFormatStatsReport_Empty(t *testing.T) {
+	clock := statsmodel.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+
+	report := statsmodel.FormatStatsReport(collector.Snapshot())
+
+	for _, want := range []string{
+		"=== EIR Statistics ===\n",
+		"Timestamp: 2026-03-01T12:00:00Z\n",
+		"Requests:\n  Total: 0\n",
+		"Errors:\n  Total: 0\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report missing %q:\n%s", want, report)
+		}
+	}
+	for _, table := range []string{"By Source:", "Top Result Codes:", "Top Errors:", "Top Endpoints by Error Rate:"} {
+		if strings.Contains(report, table) {
+			t.Errorf("Expected no %q table in an empty report:\n%s", table, report)
+		}
+	}
+}
+
+// TestFormatStatsReport_Populated tests the ranked tables of a populated collector
+func TestFormatStatsReport_Populated(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR"})
+	for i := 0; i < 6; i++ {
+		collector.RecordRequest("diameter", i < 4)
+		collector.RecordResultCode("diameter", 2001)
+	}
+	collector.RecordResultCode("diameter", 5012)
+	collector.RecordResultCode("diameter", 5012)
+	collector.RecordResultCode("diameter", 3002)
+	for i := 0; i < 3; i++ {
+		collector.RecordError("timeout", "diameter", errors.New("peer timed out"))
+	}
+	collector.RecordError("db_error", "http", errors.New("connection refused"))
+
+	stats := collector.Snapshot()
+	stats.InterfaceStats = map[string]interface{}{
+		"http": &statsmodel.HTTPStats{
+			ByEndpoint: map[string]statsmodel.EndpointStats{
+				"check":  {Path: "/n5g-eir-eic/v1/equipment-status", Requests: 10, Errors: 1},
+				"health": {Path: "/health", Requests: 4, Errors: 2},
+				"ok":     {Path: "/metrics", Requests: 5},
+			},
+		},
+	}
+
+	report := statsmodel.FormatStatsReportTopN(stats, 2)
+	lines := strings.Split(report, "\n")
+	indexOf := func(prefix string) int {
+		for i, line := range lines {
+			if strings.HasPrefix(line, prefix) {
+				return i
+			}
+		}
+		t.Fatalf("Report missing line %q:\n%s", prefix, report)
+		return -1
+	}
+
+	if !strings.Contains(report, "Requests:\n  Total: 6\n  Success: 4\n  Failed: 2\n") {
+		t.Errorf("Unexpected request counters:\n%s", report)
+	}
+
+	// Result codes ranked by count and cut at n rows
+	codes := indexOf("Top Result Codes:")
+	wantCodes := []string{"  diameter:", "    CODE  COUNT  SHARE", "    2001  6      66.7%", "    5012  2      22.2%", "    ... 1 more"}
+	for i, want := range wantCodes {
+		if got := lines[codes+1+i]; got != want {
+			t.Errorf("Result codes line %d = %q, want %q", i, got, want)
+		}
+	}
+
+	errs := indexOf("Top Errors:")
+	wantErrors := []string{"  TYPE      COUNT  SHARE", "  timeout   3      75.0%", "  db_error  1      25.0%"}
+	for i, want := range wantErrors {
+		if got := lines[errs+1+i]; got != want {
+			t.Errorf("Errors line %d = %q, want %q", i, got, want)
+		}
+	}
+
+	// Endpoints without errors are not ranked
+	endpoints := indexOf("Top Endpoints by Error Rate:")
+	if !strings.HasPrefix(lines[endpoints+2], "  /health ") || !strings.HasSuffix(lines[endpoints+2], "50.0%") {
+		t.Errorf("Expected /health ranked first, got %q", lines[endpoints+2])
+	}
+	if !strings.HasPrefix(lines[endpoints+3], "  /n5g-eir-eic/v1/equipment-status ") {
+		t.Errorf("Expected the check endpoint ranked second, got %q", lines[endpoints+3])
+	}
+	if strings.Contains(report, "/metrics") {
+		t.Errorf("Expected the endpoint without errors left out:\n%s", report)
+	}
+}
@@ -0,0 +1,59 @@
+package export
+
+import (
+	"math"
+)
+
+// Standard scaling rules
+// Factors apply to record values as exported, so fixed-point counters keep their
+// precision: latencies in hundredths of a ms become hundredths of a second
+var (
+	ScaleMsToSeconds    = ScalingRule{Factor: 0.001, Unit: "seconds"}
+	ScaleMsToMicros     = ScalingRule{Factor: 1000, Unit: "microseconds"}
+	ScaleBytesToKB      = ScalingRule{Factor: 1.0 / 1024, Unit: "kilobytes"}
+	ScaleBytesToMB      = ScalingRule{Factor: 1.0 / (1024 * 1024), Unit: "megabytes"}
+	ScaleRatioToPercent = ScalingRule{Factor: 100, Unit: "percent"}
+)
+
+// apply scales value, saturating at the uint64 range
+func (r ScalingRule) apply(value uint64) uint64 {
+	scaled := math.Round(float64(value) * r.Factor)
+	switch {
+	case scaled <= 0:
+		return 0
+	case scaled >= math.MaxUint64:
+		return math.MaxUint64
+	default:
+		return uint64(scaled)
+	}
+}
+
+// scaleRecords applies the configured scaling rules in place
+func (t *Transformer) scaleRecords(records []MetricRecord) []MetricRecord {
+	if len(t.config.Scaling) == 0 {
+		return records
+	}
+
+	for i := range records {
+		if rule, ok := t.config.Scaling[records[i].CounterID]; ok {
+			records[i].Value = rule.apply(records[i].Value)
+		}
+	}
+	return records
+}
+
+// CounterMetadata returns counter metadata with units reflecting the transformer's scaling rules
+func (t *Transformer) CounterMetadata() []CounterMetadata {
+	return ScaledCounterMetadata(t.config.Scaling)
+}
+
+// ScaledCounterMetadata returns GetCounterMetadata with the units of scaled counters replaced
+func ScaledCounterMetadata(scaling map[int]ScalingRule) []CounterMetadata {
+	metadata := GetCounterMetadata()
+	for i := range metadata {
+		if rule, ok := scaling[metadata[i].ID]; ok && rule.Unit != "" {
+			metadata[i].Unit = rule.Unit
+		}
+	}
+	return metadata
+}
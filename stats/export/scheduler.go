@@ -2,7 +2,6 @@ package export
 
 import (
 	"context"
-	"fmt"
 	"sync"
 	"time"
 
@@ -14,36 +13,87 @@ type ExportScheduler struct {
 	interval       time.Duration
 	exporters      []Exporter
 	transformer    *Transformer
-	statsCollector StatsCollectorInterface
+	statsProvider  ServiceStatsProvider
 	logger         Logger
+	clock          statsmodel.Clock
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
 	mu             sync.RWMutex
 	running        bool
+	exportCatalog  bool
+	budgets        *exporterBudgets
+	firstCycle     string        // FirstCycleExport, FirstCycleSuppress or FirstCycleFlag
+	snapshotStore  SnapshotStore // Persists prevSnapshot across restarts (optional)
+	period         periodConfig  // PeriodStart/PeriodEnd labeling
+	clockSkew      *statsmodel.ClockSkewDetector // Wall clock vs monotonic time between cycles (nil = disabled)
+	dedup          *recordDeduper                // Suppresses duplicate records (nil = disabled)
+	dryRun         Exporter                      // Receives records instead of exporters (nil = not a dry run)
+	heartbeats     map[string]bool               // Exporters sent a heartbeat record every cycle, by name
+	handshakes     map[string]bool               // Exporters sent the counter catalog on connect, by name; true while one is due
+	recordLimit    *recordLimiter                // Truncates cycles over a record count (nil = unlimited)
+
+	// Batch sequencing: one number per exported cycle, optionally persisted
+	sequence       uint64
+	sequenceStore  SequenceStore
+	sequenceLoaded bool
+	sequenceMu     sync.Mutex
 
 	// Delta tracking: stores previous snapshot for calculating differences
 	prevSnapshot   *statsmodel.ServiceStats
 	snapshotMutex  sync.RWMutex
 }
 
-// NewExportScheduler creates a new export scheduler
+// NewExportScheduler creates a new export scheduler from a legacy stats collector
 func NewExportScheduler(
 	interval time.Duration,
 	statsCollector StatsCollectorInterface,
 	transformer *Transformer,
 	logger Logger,
+) *ExportScheduler {
+	return NewExportSchedulerWithProvider(interval, AdaptCollector(statsCollector), transformer, logger)
+}
+
+// NewExportSchedulerWithProvider creates a new export scheduler from a typed stats provider
+func NewExportSchedulerWithProvider(
+	interval time.Duration,
+	statsProvider ServiceStatsProvider,
+	transformer *Transformer,
+	logger Logger,
 ) *ExportScheduler {
 	return &ExportScheduler{
 		interval:       interval,
 		exporters:      make([]Exporter, 0),
 		transformer:    transformer,
-		statsCollector: statsCollector,
+		statsProvider:  statsProvider,
 		logger:         logger,
+		clock:          statsmodel.SystemClock,
 		stopChan:       make(chan struct{}),
 		running:        false,
+		budgets:        newExporterBudgets(),
+		firstCycle:     FirstCycleExport,
+		clockSkew:      statsmodel.NewClockSkewDetector(statsmodel.DefaultClockSkewThreshold),
 	}
 }
 
+// SetClock replaces the clock driving export cycles (call before Start)
+// Use statsmodel.FakeClock to trigger cycles deterministically in tests
+func (s *ExportScheduler) SetClock(clock statsmodel.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if clock == nil {
+		clock = statsmodel.SystemClock
+	}
+	s.clock = clock
+}
+
+// SetExportCatalog enables sending the counter catalog once at startup to exporters
+// implementing CatalogExporter
+func (s *ExportScheduler) SetExportCatalog(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exportCatalog = enabled
+}
+
 // AddExporter adds an exporter to the scheduler
 func (s *ExportScheduler) AddExporter(exporter Exporter) {
 	s.mu.Lock()
@@ -61,6 +111,8 @@ func (s *ExportScheduler) Start(ctx context.Context) {
 	s.running = true
 	s.mu.Unlock()
 
+	s.loadSnapshot()
+
 	s.wg.Add(1)
 	go s.run(ctx)
 }
@@ -96,7 +148,16 @@ func (s *ExportScheduler) Stop() {
 func (s *ExportScheduler) run(ctx context.Context) {
 	defer s.wg.Done()
 
-	ticker := time.NewTicker(s.interval)
+	s.mu.RLock()
+	clock := s.clock
+	exportCatalog := s.exportCatalog && s.dryRun == nil
+	s.mu.RUnlock()
+
+	if exportCatalog {
+		s.sendCatalog(ctx)
+	}
+
+	ticker := clock.NewTicker(s.interval)
 	defer ticker.Stop()
 
 	s.logger.Infow("Export scheduler started",
@@ -111,7 +172,7 @@ func (s *ExportScheduler) run(ctx context.Context) {
 		case <-s.stopChan:
 			s.logger.Infow("Export scheduler stopped")
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			s.exportCycle(ctx)
 		}
 	}
@@ -119,29 +180,68 @@ func (s *ExportScheduler) run(ctx context.Context) {
 
 // exportCycle performs a single export cycle
 func (s *ExportScheduler) exportCycle(ctx context.Context) {
-	startTime := time.Now()
+	s.mu.RLock()
+	clock := s.clock
+	firstCycle := s.firstCycle
+	s.mu.RUnlock()
+	startTime := clock.Now()
+	skew, skewSampled := s.observeClockSkew(startTime)
 
 	// Get current stats
-	statsInterface := s.statsCollector.GetStats()
-	currentStats, ok := statsInterface.(*statsmodel.ServiceStats)
-	if !ok {
-		s.logger.Errorw("Failed to cast stats to ServiceStats",
-			"type", fmt.Sprintf("%T", statsInterface))
+	currentStats := s.statsProvider.GetServiceStats()
+	if currentStats == nil {
+		s.logger.Errorw("Stats provider returned no ServiceStats",
+			"provider", providerName(s.statsProvider))
 		return
 	}
 
 	// Calculate delta stats (difference since last export)
+	first := !s.hasSnapshot()
 	deltaStats := s.calculateDeltaStats(currentStats)
 
+	// The first cycle holds absolute totals; optionally only seed the snapshot
+	if first && firstCycle == FirstCycleSuppress {
+		s.logger.Infow("Suppressed first export cycle, snapshot seeded")
+		s.updatePreviousSnapshot(currentStats)
+		s.saveSnapshot(currentStats)
+		return
+	}
+
 	// Transform delta stats to metric records
 	records := s.transformer.Transform(deltaStats)
-	if len(records) == 0 {
+	if skewSampled {
+		records = append(records, s.transformer.clockSkewRecords(skew, startTime)...)
+	}
+	records = s.dedupRecords(records, startTime)
+	records = s.limitRecords(records, startTime)
+	heartbeats := s.heartbeatExporters()
+	if len(records) == 0 && len(heartbeats) == 0 {
 		s.logger.Debugw("No metrics to export")
 		return
 	}
+	periodStart, periodEnd := s.nextPeriod(startTime)
+	heartbeat := s.transformer.createRecord(CounterHeartbeat, 1, 0, startTime)
+	heartbeat.PeriodStart, heartbeat.PeriodEnd = periodStart, periodEnd
+	for i := range records {
+		records[i].PeriodStart = periodStart
+		records[i].PeriodEnd = periodEnd
+		records[i].FirstInterval = first && firstCycle == FirstCycleFlag
+		records[i].ClockSkewed = skew.Stepped
+	}
+
+	// Dry runs only log the records; the persisted snapshot and sequence are left alone
+	if dryRun := s.dryRunExporter(); dryRun != nil {
+		s.updatePreviousSnapshot(currentStats)
+		if len(heartbeats) > 0 {
+			records = withHeartbeat(records, heartbeat)
+		}
+		s.exportDryRun(ctx, dryRun, records)
+		return
+	}
 
 	// Store current stats as previous snapshot for next cycle
 	s.updatePreviousSnapshot(currentStats)
+	s.saveSnapshot(currentStats)
 
 	// Get exporters safely
 	s.mu.RLock()
@@ -149,34 +249,92 @@ func (s *ExportScheduler) exportCycle(ctx context.Context) {
 	copy(exporters, s.exporters)
 	s.mu.RUnlock()
 
-	// Export to all exporters in parallel
+	// Export to all exporters in parallel, within the cycle budget
+	sequence := s.nextSequence()
+	cycleCtx, cancel := s.budgets.cycleContext(ContextWithBatchSequence(ctx, sequence))
+	defer cancel()
+
 	var wg sync.WaitGroup
 	for _, exporter := range exporters {
+		batch := records
+		if heartbeats[exporter.Name()] {
+			batch = withHeartbeat(records, heartbeat)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
 		wg.Add(1)
 		go func(exp Exporter) {
 			defer wg.Done()
-			s.exportToExporter(ctx, exp, records)
+			s.exportToExporter(cycleCtx, exp, batch)
 		}(exporter)
 	}
 
 	wg.Wait()
 
-	duration := time.Since(startTime)
+	duration := clock.Now().Sub(startTime)
 	s.logger.Debugw("Export cycle completed",
+		"sequence", sequence,
 		"records", len(records),
 		"exporters", len(exporters),
 		"duration_ms", duration.Milliseconds())
 }
 
-// exportToExporter exports records to a single exporter
+// sendCatalog sends the counter catalog to every CatalogExporter
+func (s *ExportScheduler) sendCatalog(ctx context.Context) {
+	catalog := NewCounterCatalog(s.transformer.config.Scaling)
+
+	s.mu.RLock()
+	exporters := make([]Exporter, len(s.exporters))
+	copy(exporters, s.exporters)
+	s.mu.RUnlock()
+
+	for _, exporter := range exporters {
+		catalogExporter, ok := exporter.(CatalogExporter)
+		if !ok {
+			continue
+		}
+
+		exportCtx, cancel := context.WithTimeout(ctx, s.budgets.timeoutFor(exporter.Name()))
+		err := catalogExporter.ExportCatalog(exportCtx, catalog)
+		cancel()
+		if err != nil {
+			s.logger.Errorw("Failed to export counter catalog",
+				"exporter", exporter.Name(),
+				"error", err)
+			continue
+		}
+
+		s.logger.Infow("Exported counter catalog",
+			"exporter", exporter.Name(),
+			"counters", len(catalog.Counters))
+	}
+}
+
+// exportToExporter exports records to a single exporter within its timeout
 func (s *ExportScheduler) exportToExporter(ctx context.Context, exporter Exporter, records []MetricRecord) {
-	exportCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	timeout := s.budgets.timeoutFor(exporter.Name())
+	exportCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	s.handshake(exportCtx, exporter)
+
+	start := time.Now()
+	err := exporter.Export(exportCtx, records)
+	stats, becameSlow := s.budgets.observe(exporter.Name(), timeout, time.Since(start), err)
+	if becameSlow {
+		s.logger.Warnw("Exporter consistently exceeds its export timeout",
+			"exporter", exporter.Name(),
+			"timeout", timeout.String(),
+			"consecutive_overruns", stats.ConsecutiveOverruns,
+			"max_duration_ms", stats.MaxDuration.Milliseconds())
+	}
 
-	if err := exporter.Export(exportCtx, records); err != nil {
+	if err != nil {
 		s.logger.Errorw("Failed to export metrics",
 			"exporter", exporter.Name(),
 			"error", err)
+		s.setHandshakePending(exporter, true) // The receiver may not have the catalog anymore
 		return
 	}
 
@@ -197,117 +355,13 @@ func (s *ExportScheduler) calculateDeltaStats(current *statsmodel.ServiceStats)
 		return current
 	}
 
-	// Create delta stats by subtracting previous from current
-	delta := &statsmodel.ServiceStats{
-		ServiceName:    current.ServiceName,
-		ServiceVersion: current.ServiceVersion,
-		Uptime:         current.Uptime,
-		Timestamp:      current.Timestamp,
-		Connections: statsmodel.ConnectionStats{
-			Active: current.Connections.Active, // Use current value for gauges
-			Total:  safeSub64(current.Connections.Total, prev.Connections.Total),
-			Failed: safeSub64(current.Connections.Failed, prev.Connections.Failed),
-		},
-		Requests: statsmodel.RequestStats{
-			Total:   safeSub64(current.Requests.Total, prev.Requests.Total),
-			Success: safeSub64(current.Requests.Success, prev.Requests.Success),
-			Failed:  safeSub64(current.Requests.Failed, prev.Requests.Failed),
-			Pending: current.Requests.Pending, // Use current value for gauges
-			BySource: make(map[string]statsmodel.SourceStats),
-			ByOperation: make(map[string]statsmodel.OperationStats),
-		},
-		Performance: statsmodel.PerformanceStats{
-			RequestsPerSecond: current.Performance.RequestsPerSecond, // Use current value
-			AvgLatencyMs:      current.Performance.AvgLatencyMs,      // Use current value
-			MinLatencyMs:      current.Performance.MinLatencyMs,      // Use current value
-			MaxLatencyMs:      current.Performance.MaxLatencyMs,      // Use current value
-			P50LatencyMs:      current.Performance.P50LatencyMs,      // Use current value
-			P95LatencyMs:      current.Performance.P95LatencyMs,      // Use current value
-			P99LatencyMs:      current.Performance.P99LatencyMs,      // Use current value
-		},
-		Errors: statsmodel.ErrorStats{
-			Total:       safeSub64(current.Errors.Total, prev.Errors.Total),
-			ByType:      calculateMapDelta64(current.Errors.ByType, prev.Errors.ByType),
-			ByInterface: calculateMapDelta64(current.Errors.ByInterface, prev.Errors.ByInterface),
-		},
-		CustomMetrics: make(map[string]interface{}),
-	}
-
-	// Calculate delta for BySource
-	for source, currStat := range current.Requests.BySource {
-		prevStat := prev.Requests.BySource[source]
-		delta.Requests.BySource[source] = statsmodel.SourceStats{
-			Total:   safeSub64(currStat.Total, prevStat.Total),
-			Success: safeSub64(currStat.Success, prevStat.Success),
-			Failed:  safeSub64(currStat.Failed, prevStat.Failed),
-		}
-	}
-
-	// Calculate delta for ByOperation
-	for op, currStat := range current.Requests.ByOperation {
-		prevStat := prev.Requests.ByOperation[op]
-		delta.Requests.ByOperation[op] = statsmodel.OperationStats{
-			Total:   safeSub64(currStat.Total, prevStat.Total),
-			Success: safeSub64(currStat.Success, prevStat.Success),
-			Failed:  safeSub64(currStat.Failed, prevStat.Failed),
-		}
-	}
-
-	// Calculate delta for EIR-specific metrics
-	if currEIR, ok := current.CustomMetrics["eir"].(*statsmodel.EIRStats); ok {
-		var prevEIR *statsmodel.EIRStats
-		if prev.CustomMetrics != nil {
-			if p, ok := prev.CustomMetrics["eir"].(*statsmodel.EIRStats); ok {
-				prevEIR = p
-			}
-		}
-
-		delta.CustomMetrics["eir"] = s.calculateEIRDelta(currEIR, prevEIR)
-	}
-
-	return delta
-}
-
-// calculateEIRDelta calculates delta for EIR-specific stats
-func (s *ExportScheduler) calculateEIRDelta(current *statsmodel.EIRStats, prev *statsmodel.EIRStats) *statsmodel.EIRStats {
-	if prev == nil {
-		return current
-	}
-
-	deltaEIR := &statsmodel.EIRStats{
-		EquipmentChecks: statsmodel.EquipmentCheckStats{
-			Total:       safeSub64(current.EquipmentChecks.Total, prev.EquipmentChecks.Total),
-			Success:     safeSub64(current.EquipmentChecks.Success, prev.EquipmentChecks.Success),
-			Failed:      safeSub64(current.EquipmentChecks.Failed, prev.EquipmentChecks.Failed),
-			ByInterface: make(map[string]statsmodel.InterfaceCheckStats),
-		},
-		CacheStats: statsmodel.CacheStats{
-			Hits:    safeSub64(current.CacheStats.Hits, prev.CacheStats.Hits),
-			Misses:  safeSub64(current.CacheStats.Misses, prev.CacheStats.Misses),
-			HitRate: current.CacheStats.HitRate, // Use current value
-			Size:    current.CacheStats.Size,    // Use current value (gauge)
-		},
-		DatabaseOps: statsmodel.DatabaseOperationStats{
-			Queries: safeSub64(current.DatabaseOps.Queries, prev.DatabaseOps.Queries),
-			Inserts: safeSub64(current.DatabaseOps.Inserts, prev.DatabaseOps.Inserts),
-			Updates: safeSub64(current.DatabaseOps.Updates, prev.DatabaseOps.Updates),
-			Deletes: safeSub64(current.DatabaseOps.Deletes, prev.DatabaseOps.Deletes),
-		},
-		ByEquipmentStatus: calculateMapDelta64(current.ByEquipmentStatus, prev.ByEquipmentStatus),
-	}
+	// Counters are subtracted and gauges copied as declared by the model's stats tags
+	delta := statsmodel.Delta(*current, *prev)
 
-	// Calculate delta for interface-specific stats
-	for ifName, currIf := range current.EquipmentChecks.ByInterface {
-		prevIf := prev.EquipmentChecks.ByInterface[ifName]
-		deltaEIR.EquipmentChecks.ByInterface[ifName] = statsmodel.InterfaceCheckStats{
-			Total:        safeSub64(currIf.Total, prevIf.Total),
-			Success:      safeSub64(currIf.Success, prevIf.Success),
-			Failed:       safeSub64(currIf.Failed, prevIf.Failed),
-			ByResultCode: calculateMapDeltaInt64(currIf.ByResultCode, prevIf.ByResultCode),
-		}
-	}
+	// Calculate delta for custom metrics sections (EIR and registered handlers)
+	delta.CustomMetrics = calculateCustomMetricsDelta(current.CustomMetrics, prev.CustomMetrics)
 
-	return deltaEIR
+	return &delta
 }
 
 // updatePreviousSnapshot stores current stats as previous snapshot
@@ -316,35 +370,3 @@ func (s *ExportScheduler) updatePreviousSnapshot(current *statsmodel.ServiceStat
 	defer s.snapshotMutex.Unlock()
 	s.prevSnapshot = current
 }
-
-// safeSub64 safely subtracts two uint64 values (returns 0 if result would be negative)
-func safeSub64(a, b uint64) uint64 {
-	if a >= b {
-		return a - b
-	}
-	return 0
-}
-
-// calculateMapDelta64 calculates delta for map[string]uint64
-func calculateMapDelta64(current, prev map[string]uint64) map[string]uint64 {
-	delta := make(map[string]uint64)
-	for key, currVal := range current {
-		prevVal := prev[key]
-		if diff := safeSub64(currVal, prevVal); diff > 0 {
-			delta[key] = diff
-		}
-	}
-	return delta
-}
-
-// calculateMapDeltaInt64 calculates delta for map[int]uint64
-func calculateMapDeltaInt64(current, prev map[int]uint64) map[int]uint64 {
-	delta := make(map[int]uint64)
-	for key, currVal := range current {
-		prevVal := prev[key]
-		if diff := safeSub64(currVal, prevVal); diff > 0 {
-			delta[key] = diff
-		}
-	}
-	return delta
-}
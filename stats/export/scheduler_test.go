@@ -1,6 +1,7 @@
 package export
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -277,36 +278,37 @@ func TestDeltaCalculation(t *testing.T) {
 	})
 }
 
-// TestSafeSubtraction tests the safeSub64 helper function
-func TestSafeSubtraction(t *testing.T) {
+// TestDelta_Counters tests counter subtraction, including counters reset since the previous snapshot
+func TestDelta_Counters(t *testing.T) {
 	tests := []struct {
 		name     string
-		a, b     uint64
+		curr     uint64
+		prev     uint64
 		expected uint64
 	}{
 		{"Normal subtraction", 100, 50, 50},
 		{"Equal values", 100, 100, 0},
-		{"Would be negative", 50, 100, 0},
-		{"Zero subtraction", 100, 0, 100},
+		{"Reset reports current", 50, 100, 50},
+		{"Zero previous", 100, 0, 100},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := safeSub64(tt.a, tt.b)
-			if result != tt.expected {
-				t.Errorf("safeSub64(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			delta := statsmodel.Delta(statsmodel.ErrorStats{Total: tt.curr}, statsmodel.ErrorStats{Total: tt.prev})
+			if delta.Total != tt.expected {
+				t.Errorf("Delta(%d, %d) = %d, want %d", tt.curr, tt.prev, delta.Total, tt.expected)
 			}
 		})
 	}
 }
 
-// TestMapDeltaCalculation tests map delta calculation helpers
-func TestMapDeltaCalculation(t *testing.T) {
+// TestDelta_Maps tests per-key counter maps are subtracted by key
+func TestDelta_Maps(t *testing.T) {
 	t.Run("StringMap", func(t *testing.T) {
-		current := map[string]uint64{"a": 100, "b": 50, "c": 30}
-		prev := map[string]uint64{"a": 70, "b": 50, "c": 20}
+		current := statsmodel.ErrorStats{ByType: map[string]uint64{"a": 100, "b": 50, "c": 30}}
+		prev := statsmodel.ErrorStats{ByType: map[string]uint64{"a": 70, "b": 50, "c": 20}}
 
-		delta := calculateMapDelta64(current, prev)
+		delta := statsmodel.Delta(current, prev).ByType
 
 		if delta["a"] != 30 {
 			t.Errorf("Expected a=30, got %d", delta["a"])
@@ -320,10 +322,10 @@ func TestMapDeltaCalculation(t *testing.T) {
 	})
 
 	t.Run("IntMap", func(t *testing.T) {
-		current := map[int]uint64{200: 100, 404: 50, 500: 30}
-		prev := map[int]uint64{200: 70, 404: 50, 500: 20}
+		current := statsmodel.InterfaceCheckStats{ByResultCode: map[int]uint64{200: 100, 404: 50, 500: 30}}
+		prev := statsmodel.InterfaceCheckStats{ByResultCode: map[int]uint64{200: 70, 404: 50, 500: 20}}
 
-		delta := calculateMapDeltaInt64(current, prev)
+		delta := statsmodel.Delta(current, prev).ByResultCode
 
 		if delta[200] != 30 {
 			t.Errorf("Expected 200=30, got %d", delta[200])
@@ -337,10 +339,10 @@ func TestMapDeltaCalculation(t *testing.T) {
 	})
 
 	t.Run("NewKeyInCurrent", func(t *testing.T) {
-		current := map[string]uint64{"a": 100, "b": 50}
-		prev := map[string]uint64{"a": 70}
+		current := statsmodel.ErrorStats{ByType: map[string]uint64{"a": 100, "b": 50}}
+		prev := statsmodel.ErrorStats{ByType: map[string]uint64{"a": 70, "c": 5}}
 
-		delta := calculateMapDelta64(current, prev)
+		delta := statsmodel.Delta(current, prev).ByType
 
 		if delta["a"] != 30 {
 			t.Errorf("Expected a=30, got %d", delta["a"])
@@ -348,5 +350,257 @@ func TestMapDeltaCalculation(t *testing.T) {
 		if delta["b"] != 50 { // New key
 			t.Errorf("Expected b=50 (new key), got %d", delta["b"])
 		}
+		if _, ok := delta["c"]; ok { // Only in prev
+			t.Errorf("Expected c dropped, got %d", delta["c"])
+		}
+	})
+}
+
+// TestDeltaCalculation_Bytes tests that byte counters and size histograms are delta-calculated
+func TestDeltaCalculation_Bytes(t *testing.T) {
+	scheduler := NewExportScheduler(time.Minute, &mockStatsCollector{}, NewTransformer("h", "s"), &mockLogger{})
+
+	scheduler.updatePreviousSnapshot(&statsmodel.ServiceStats{
+		Requests: statsmodel.RequestStats{
+			BytesSent: 1000,
+			BytesRecv: 400,
+			BySource: map[string]statsmodel.SourceStats{
+				"diameter": {BytesSent: 1000, BytesRecv: 400, SentSizes: map[int]uint64{256: 3}},
+			},
+		},
+	})
+
+	delta := scheduler.calculateDeltaStats(&statsmodel.ServiceStats{
+		Requests: statsmodel.RequestStats{
+			BytesSent: 1600,
+			BytesRecv: 500,
+			BySource: map[string]statsmodel.SourceStats{
+				"diameter": {BytesSent: 1600, BytesRecv: 500, SentSizes: map[int]uint64{256: 5, 1024: 1}},
+			},
+		},
+	})
+
+	if delta.Requests.BytesSent != 600 || delta.Requests.BytesRecv != 100 {
+		t.Errorf("Expected bytes delta 600/100, got %d/%d", delta.Requests.BytesSent, delta.Requests.BytesRecv)
+	}
+
+	diam := delta.Requests.BySource["diameter"]
+	if diam.BytesSent != 600 {
+		t.Errorf("Expected diameter BytesSent delta 600, got %d", diam.BytesSent)
+	}
+	if diam.SentSizes[256] != 2 || diam.SentSizes[1024] != 1 {
+		t.Errorf("Unexpected size histogram delta: %v", diam.SentSizes)
+	}
+}
+
+// TestDeltaCalculation_CacheGauges tests that cache counters are delta-calculated while gauges pass through
+func TestDeltaCalculation_CacheGauges(t *testing.T) {
+	prevEIR := statsmodel.EIRStats{
+		CacheStats: statsmodel.CacheStats{Evictions: 10, Expirations: 4, Bytes: 4096, Size: 100, MaxSize: 1000},
+	}
+	currEIR := statsmodel.EIRStats{
+		CacheStats: statsmodel.CacheStats{Evictions: 15, Expirations: 4, Bytes: 2048, Size: 80, MaxSize: 1000},
+	}
+
+	deltaEIR := statsmodel.Delta(currEIR, prevEIR)
+
+	if deltaEIR.CacheStats.Evictions != 5 {
+		t.Errorf("Expected Evictions delta 5, got %d", deltaEIR.CacheStats.Evictions)
+	}
+	if deltaEIR.CacheStats.Expirations != 0 {
+		t.Errorf("Expected Expirations delta 0, got %d", deltaEIR.CacheStats.Expirations)
+	}
+	if deltaEIR.CacheStats.Bytes != 2048 {
+		t.Errorf("Expected Bytes gauge 2048, got %d", deltaEIR.CacheStats.Bytes)
+	}
+	if deltaEIR.CacheStats.MaxSize != 1000 {
+		t.Errorf("Expected MaxSize gauge 1000, got %d", deltaEIR.CacheStats.MaxSize)
+	}
+}
+
+// typedStatsProvider implements ServiceStatsProvider for testing
+type typedStatsProvider struct {
+	stats *statsmodel.ServiceStats
+}
+
+func (p *typedStatsProvider) GetServiceStats() *statsmodel.ServiceStats {
+	return p.stats
+}
+
+// wrongTypeCollector returns stats that are not ServiceStats
+type wrongTypeCollector struct{}
+
+func (w *wrongTypeCollector) GetStats() interface{} {
+	return map[string]int{"requests": 1}
+}
+
+// TestAdaptCollector tests adapting legacy collectors to ServiceStatsProvider
+func TestAdaptCollector(t *testing.T) {
+	stats := &statsmodel.ServiceStats{ServiceName: "EIR"}
+
+	if got := AdaptCollector(&mockStatsCollector{stats: stats}).GetServiceStats(); got != stats {
+		t.Errorf("Expected pointer stats to pass through, got %v", got)
+	}
+
+	if got := AdaptCollector(&wrongTypeCollector{}).GetServiceStats(); got != nil {
+		t.Errorf("Expected nil for non-ServiceStats collector, got %v", got)
+	}
+
+	provider := &typedStatsProvider{stats: stats}
+	scheduler := NewExportSchedulerWithProvider(time.Minute, provider, NewTransformer("h", "s"), &mockLogger{})
+	if scheduler.statsProvider != provider {
+		t.Error("Typed provider should be used directly")
+	}
+
+	converted := ConvertStats(func() int { return 42 }, func(n int) *statsmodel.ServiceStats {
+		return &statsmodel.ServiceStats{Requests: statsmodel.RequestStats{Total: uint64(n)}}
+	})
+	if got := converted.GetServiceStats().Requests.Total; got != 42 {
+		t.Errorf("Expected converted total 42, got %d", got)
+	}
+}
+
+// TestDeltaCalculation_SCTP tests SCTP counters are deltas while active associations stay a gauge
+func TestDeltaCalculation_SCTP(t *testing.T) {
+	scheduler := NewExportScheduler(time.Minute, &mockStatsCollector{}, NewTransformer("h", "s"), &mockLogger{})
+
+	scheduler.updatePreviousSnapshot(&statsmodel.ServiceStats{
+		SCTP: &statsmodel.SCTPStats{ActiveAssociations: 4, Establishes: 6, Aborts: 2, Retransmits: 100},
+	})
+
+	delta := scheduler.calculateDeltaStats(&statsmodel.ServiceStats{
+		SCTP: &statsmodel.SCTPStats{ActiveAssociations: 3, Establishes: 6, Aborts: 3, Retransmits: 150},
+	})
+
+	if delta.SCTP == nil {
+		t.Fatal("Expected SCTP section in delta")
+	}
+	if delta.SCTP.ActiveAssociations != 3 {
+		t.Errorf("Expected ActiveAssociations gauge 3, got %d", delta.SCTP.ActiveAssociations)
+	}
+	if delta.SCTP.Aborts != 1 {
+		t.Errorf("Expected Aborts delta 1, got %d", delta.SCTP.Aborts)
+	}
+	if delta.SCTP.Retransmits != 50 {
+		t.Errorf("Expected Retransmits delta 50, got %d", delta.SCTP.Retransmits)
+	}
+	if delta.SCTP.Establishes != 0 {
+		t.Errorf("Expected Establishes delta 0, got %d", delta.SCTP.Establishes)
+	}
+}
+
+// channelExporter delivers each exported batch on a channel
+type channelExporter struct {
+	batches chan []MetricRecord
+}
+
+func (e *channelExporter) Export(ctx context.Context, records []MetricRecord) error {
+	e.batches <- records
+	return nil
+}
+
+func (e *channelExporter) Name() string { return "channel" }
+func (e *channelExporter) Close() error { return nil }
+
+// TestExportScheduler_FakeClock tests export cycles and timestamps are driven by the injected clock
+func TestExportScheduler_FakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := statsmodel.NewFakeClock(start)
+
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	collector.RecordRequest("diameter", true)
+
+	exporter := &channelExporter{batches: make(chan []MetricRecord, 1)}
+	scheduler := NewExportSchedulerWithProvider(time.Minute, collector, NewTransformer("h", "s"), &mockLogger{})
+	scheduler.SetClock(clock)
+	scheduler.AddExporter(exporter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.Start(ctx)
+	defer scheduler.Stop()
+
+	for clock.TickerCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Minute)
+
+	select {
+	case records := <-exporter.batches:
+		want := start.Add(time.Minute)
+		for _, record := range records {
+			if !record.Timestamp.Equal(want) {
+				t.Fatalf("Expected timestamp %v from fake clock, got %v", want, record.Timestamp)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an export cycle after advancing the clock")
+	}
+
+	stats := collector.Snapshot()
+	if stats.UptimeSeconds != 60 {
+		t.Errorf("Expected uptime 60s from fake clock, got %d", stats.UptimeSeconds)
+	}
+}
+
+// blockingExporter waits for its context to end on every export
+type blockingExporter struct {
+	name string
+}
+
+func (e *blockingExporter) Export(ctx context.Context, records []MetricRecord) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (e *blockingExporter) Name() string { return e.name }
+func (e *blockingExporter) Close() error { return nil }
+
+// TestExportScheduler_ExporterTimeouts tests per-exporter timeouts, the cycle budget and slow exporter reporting
+func TestExportScheduler_ExporterTimeouts(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR"})
+	scheduler := NewExportSchedulerWithProvider(time.Minute, collector, NewTransformer("h", "s"), &mockLogger{})
+
+	fast := &channelExporter{batches: make(chan []MetricRecord, SlowExporterThreshold)}
+	scheduler.AddExporter(fast)
+	scheduler.AddExporter(&blockingExporter{name: "slow"})
+	scheduler.AddExporter(&blockingExporter{name: "budgeted"})
+
+	scheduler.ApplyTimeouts(&ExportConfig{
+		ExportTimeout: 10 * time.Millisecond,
+		CycleBudget:   50 * time.Millisecond,
+		Exporters:     []ExporterConfig{{Name: "budgeted", ExportTimeout: time.Hour}},
 	})
+
+	for i := 0; i < SlowExporterThreshold; i++ {
+		collector.RecordRequest("diameter", true)
+		start := time.Now()
+		scheduler.exportCycle(context.Background())
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Cycle took %v, expected the cycle budget to cancel the budgeted exporter", elapsed)
+		}
+	}
+
+	stats := scheduler.ExporterStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected stats for 3 exporters, got %d", len(stats))
+	}
+	byName := make(map[string]ExporterStats)
+	for _, s := range stats {
+		byName[s.Name] = s
+	}
+
+	if s := byName["slow"]; s.Timeout != 10*time.Millisecond || s.Overruns != SlowExporterThreshold || !s.Slow {
+		t.Errorf("Expected slow exporter to overrun every cycle, got %+v", s)
+	}
+	if s := byName["budgeted"]; s.Timeout != time.Hour || s.Failures != SlowExporterThreshold || !s.Slow {
+		t.Errorf("Expected budgeted exporter to overrun the cycle budget, got %+v", s)
+	}
+	if s := byName["channel"]; s.Overruns != 0 || s.Slow {
+		t.Errorf("Expected channel exporter within budget, got %+v", s)
+	}
+
+	if slow := scheduler.SlowExporters(); len(slow) != 2 || slow[0] != "budgeted" || slow[1] != "slow" {
+		t.Errorf("Expected SlowExporters [budgeted slow], got %v", slow)
+	}
 }
@@ -0,0 +1,279 @@
+package export
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Scrub actions
+const (
+	ScrubHash = "hash" // Replace the value with a salted hash, stable across cycles
+	ScrubDrop = "drop" // Cause code rules: drop the record; labels: clear the label
+)
+
+// ScrubRule selects sensitive cause codes, e.g. the TACs of CounterTACChecks
+type ScrubRule struct {
+	CounterIDs []int  `json:"counter_ids" yaml:"counter_ids"` // Counters the rule applies to (empty = all)
+	CauseCodes []int  `json:"cause_codes" yaml:"cause_codes"` // Cause codes the rule applies to (empty = any non-zero)
+	Action     string `json:"action" yaml:"action"`           // ScrubHash or ScrubDrop
+}
+
+// ScrubbingProfile defines what an exporter must not send as is
+type ScrubbingProfile struct {
+	// Rules scrub cause codes; the first matching rule applies
+	Rules []ScrubRule `json:"rules" yaml:"rules"`
+
+	// Hostname and SystemName scrub the record labels ("" = keep)
+	Hostname   string `json:"hostname" yaml:"hostname"`
+	SystemName string `json:"system_name" yaml:"system_name"`
+
+	// Salt keys the hashes so small value spaces such as TACs can't be reversed by
+	// hashing every candidate (required by ScrubHash)
+	Salt string `json:"salt" yaml:"salt"`
+
+	// Scrub is a custom hook run on each record after the rules; returning false
+	// drops the record
+	Scrub func(record *MetricRecord) bool `json:"-" yaml:"-"`
+}
+
+// Validate checks the profile's actions
+func (p ScrubbingProfile) Validate() error {
+	hashes := false
+	check := func(what, action string, allowEmpty bool) error {
+		switch action {
+		case ScrubHash:
+			hashes = true
+		case ScrubDrop:
+		case "":
+			if !allowEmpty {
+				return fmt.Errorf("%s requires an action", what)
+			}
+		default:
+			return fmt.Errorf("%s: unknown scrub action %q", what, action)
+		}
+		return nil
+	}
+
+	for i, rule := range p.Rules {
+		if err := check(fmt.Sprintf("scrub rule %d", i), rule.Action, false); err != nil {
+			return err
+		}
+	}
+	if err := check("hostname", p.Hostname, true); err != nil {
+		return err
+	}
+	if err := check("system_name", p.SystemName, true); err != nil {
+		return err
+	}
+	if hashes && p.Salt == "" {
+		return fmt.Errorf("scrubbing profile with hash actions requires a salt")
+	}
+	return nil
+}
+
+// ScrubbingExporter scrubs records before passing them to the wrapped exporter
+// The scheduler shares one batch between exporters, so records are copied, never
+// modified in place
+type ScrubbingExporter struct {
+	exporter Exporter
+	profile  ScrubbingProfile
+}
+
+// NewScrubbingExporter wraps exporter with a scrubbing profile
+func NewScrubbingExporter(exporter Exporter, profile ScrubbingProfile) (*ScrubbingExporter, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return &ScrubbingExporter{exporter: exporter, profile: profile}, nil
+}
+
+// Export scrubs records and exports what remains
+func (e *ScrubbingExporter) Export(ctx context.Context, records []MetricRecord) error {
+	scrubbed := e.profile.Apply(records)
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return e.exporter.Export(ctx, scrubbed)
+}
+
+// ExportCatalog passes the counter catalog to the wrapped exporter if it accepts it
+func (e *ScrubbingExporter) ExportCatalog(ctx context.Context, catalog CounterCatalog) error {
+	if catalogExporter, ok := e.exporter.(CatalogExporter); ok {
+		return catalogExporter.ExportCatalog(ctx, catalog)
+	}
+	return nil
+}
+
+// Name returns the wrapped exporter's name
+func (e *ScrubbingExporter) Name() string {
+	return e.exporter.Name()
+}
+
+// Close closes the wrapped exporter
+func (e *ScrubbingExporter) Close() error {
+	return e.exporter.Close()
+}
+
+// Apply returns scrubbed copies of records, without the dropped ones
+func (p ScrubbingProfile) Apply(records []MetricRecord) []MetricRecord {
+	out := make([]MetricRecord, 0, len(records))
+	for _, record := range records {
+		if rule, ok := p.match(record); ok {
+			if rule.Action == ScrubDrop {
+				continue
+			}
+			record.CauseCode = p.hashCode(record.CauseCode)
+		}
+		record.Hostname = p.scrubLabel(p.Hostname, record.Hostname)
+		record.SystemName = p.scrubLabel(p.SystemName, record.SystemName)
+
+		if p.Scrub != nil && !p.Scrub(&record) {
+			continue
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
+// match returns the first rule matching record
+func (p ScrubbingProfile) match(record MetricRecord) (ScrubRule, bool) {
+	for _, rule := range p.Rules {
+		if len(rule.CounterIDs) > 0 && !containsInt(rule.CounterIDs, record.CounterID) {
+			continue
+		}
+		if len(rule.CauseCodes) > 0 {
+			if !containsInt(rule.CauseCodes, record.CauseCode) {
+				continue
+			}
+		} else if record.CauseCode == 0 {
+			continue
+		}
+		return rule, true
+	}
+	return ScrubRule{}, false
+}
+
+// hashCode maps a cause code to a positive, non-zero code keyed by the salt
+func (p ScrubbingProfile) hashCode(code int) int {
+	sum := p.hash("cause:" + strconv.Itoa(code))
+	if hashed := int(binary.BigEndian.Uint32(sum) & 0x7fffffff); hashed != 0 {
+		return hashed
+	}
+	return 1
+}
+
+// scrubLabel applies action to a label value
+func (p ScrubbingProfile) scrubLabel(action, value string) string {
+	switch {
+	case value == "":
+		return value
+	case action == ScrubHash:
+		return hex.EncodeToString(p.hash("label:" + value)[:8])
+	case action == ScrubDrop:
+		return ""
+	default:
+		return value
+	}
+}
+
+// hash returns the HMAC-SHA256 of s keyed by the salt
+func (p ScrubbingProfile) hash(s string) []byte {
+	mac := hmac.New(sha256.New, []byte(p.Salt))
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+// containsInt reports whether values contains v
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseScrubbingProfile reads the "scrubbing" block of an exporter's generic config
+func parseScrubbingProfile(config map[string]interface{}) (*ScrubbingProfile, error) {
+	raw, ok := config["scrubbing"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("scrubbing must be a map")
+	}
+
+	profile := &ScrubbingProfile{}
+	fields := map[string]*string{
+		"hostname":    &profile.Hostname,
+		"system_name": &profile.SystemName,
+		"salt":        &profile.Salt,
+	}
+	for key, target := range fields {
+		switch v := m[key].(type) {
+		case nil:
+		case string:
+			*target = v
+		default:
+			return nil, fmt.Errorf("scrubbing %s must be a string", key)
+		}
+	}
+
+	if rawRules, ok := m["rules"]; ok && rawRules != nil {
+		rules, ok := rawRules.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("scrubbing rules must be a list")
+		}
+		for i, rawRule := range rules {
+			ruleMap, ok := rawRule.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("scrub rule %d is not a map", i)
+			}
+			rule := ScrubRule{}
+			rule.Action, _ = ruleMap["action"].(string)
+			var err error
+			if rule.CounterIDs, err = parseIntList(ruleMap["counter_ids"]); err != nil {
+				return nil, fmt.Errorf("scrub rule %d counter_ids: %w", i, err)
+			}
+			if rule.CauseCodes, err = parseIntList(ruleMap["cause_codes"]); err != nil {
+				return nil, fmt.Errorf("scrub rule %d cause_codes: %w", i, err)
+			}
+			profile.Rules = append(profile.Rules, rule)
+		}
+	}
+
+	// Fail at startup rather than on the first export
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// parseIntList reads a list of integers from generic config
+func parseIntList(raw interface{}) ([]int, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be a list")
+	}
+	values := make([]int, 0, len(list))
+	for _, v := range list {
+		switch n := v.(type) {
+		case int:
+			values = append(values, n)
+		case float64:
+			values = append(values, int(n))
+		default:
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,95 @@
+package export
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScrubbingExporter(t *testing.T) {
+	exporter, err := CreateExporter(ExporterConfig{
+		Type: "memory",
+		Name: "nms",
+		Config: map[string]interface{}{
+			"scrubbing": map[string]interface{}{
+				"salt":     "s3cret",
+				"hostname": "hash",
+				"rules": []interface{}{
+					map[string]interface{}{"counter_ids": []interface{}{float64(CounterTACChecks)}, "action": "hash"},
+					map[string]interface{}{"counter_ids": []interface{}{CounterTACBlacklisted}, "action": "drop"},
+				},
+			},
+		},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("CreateExporter() error = %v", err)
+	}
+	scrubber, ok := exporter.(*ScrubbingExporter)
+	if !ok {
+		t.Fatalf("Expected *ScrubbingExporter, got %T", exporter)
+	}
+	memory := scrubber.exporter.(*MemoryExporter)
+
+	records := []MetricRecord{
+		{CounterID: CounterTACChecks, CauseCode: 35209900, Value: 5, Hostname: "eir-1", SystemName: "EIR"},
+		{CounterID: CounterTACBlacklisted, CauseCode: 35209900, Value: 1, Hostname: "eir-1", SystemName: "EIR"},
+		{CounterID: CounterTotalRequests, Value: 9, Hostname: "eir-1", SystemName: "EIR"},
+	}
+	for i := 0; i < 2; i++ {
+		if err := exporter.Export(context.Background(), records); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+	}
+
+	if records[0].CauseCode != 35209900 || records[0].Hostname != "eir-1" {
+		t.Error("Export modified the caller's records")
+	}
+
+	batches := memory.Batches()
+	got := batches[0].Records
+	if len(got) != 2 {
+		t.Fatalf("Expected the blacklisted record dropped, got %d records", len(got))
+	}
+	if got[0].CauseCode == 35209900 || got[0].CauseCode <= 0 {
+		t.Errorf("Expected a hashed positive TAC, got %d", got[0].CauseCode)
+	}
+	if got[0].CauseCode != batches[1].Records[0].CauseCode {
+		t.Error("Expected hashed TACs to be stable across cycles")
+	}
+	if got[1].Hostname == "eir-1" || got[1].Hostname != got[0].Hostname {
+		t.Errorf("Expected a stable hashed hostname, got %q and %q", got[0].Hostname, got[1].Hostname)
+	}
+	if got[1].SystemName != "EIR" || got[1].CauseCode != 0 {
+		t.Errorf("Expected unmatched fields kept, got %+v", got[1])
+	}
+}
+
+func TestScrubbingProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile ScrubbingProfile
+		wantErr bool
+	}{
+		{"drop without salt", ScrubbingProfile{Rules: []ScrubRule{{Action: ScrubDrop}}}, false},
+		{"hash without salt", ScrubbingProfile{Hostname: ScrubHash}, true},
+		{"rule without action", ScrubbingProfile{Rules: []ScrubRule{{CounterIDs: []int{1}}}}, true},
+		{"unknown action", ScrubbingProfile{SystemName: "mask"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.profile.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScrubbingProfile_Hook(t *testing.T) {
+	profile := ScrubbingProfile{Scrub: func(record *MetricRecord) bool {
+		record.SystemName = "redacted"
+		return record.Value > 0
+	}}
+	got := profile.Apply([]MetricRecord{{CounterID: 1, Value: 0}, {CounterID: 2, Value: 3, SystemName: "EIR"}})
+	if len(got) != 1 || got[0].CounterID != 2 || got[0].SystemName != "redacted" {
+		t.Errorf("Apply() = %+v", got)
+	}
+}
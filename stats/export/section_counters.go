@@ -0,0 +1,215 @@
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// sectionCounters maps the numeric fields of a stats section to counter IDs
+// Whether a zero value is exported follows the field's stats tag (see
+// statsmodel.Semantics): counters and omitzero gauges are skipped, gauges are not
+type sectionCounters[T any] []sectionCounter
+
+// sectionCounter is one mapped field of a stats section
+type sectionCounter struct {
+	index     int
+	counterID int
+	float     bool // Exported multiplied by 100 (2 decimal precision)
+	skipZero  bool
+}
+
+// newSectionCounters maps the named fields of T to counter IDs, in field order
+// It panics on an unknown or non-numeric field, so a typo fails at init
+func newSectionCounters[T any](counters map[string]int) sectionCounters[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	section := make(sectionCounters[T], 0, len(counters))
+	for name, counterID := range counters {
+		field, ok := typ.FieldByName(name)
+		if !ok || len(field.Index) != 1 {
+			panic(fmt.Sprintf("export: %s has no field %s", typ, name))
+		}
+
+		c := sectionCounter{index: field.Index[0], counterID: counterID, skipZero: statsmodel.SemanticsOf(field).SkipZero()}
+		switch field.Type.Kind() {
+		case reflect.Float32, reflect.Float64:
+			c.float = true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			panic(fmt.Sprintf("export: %s.%s is not numeric", typ, name))
+		}
+		section = append(section, c)
+	}
+	sort.Slice(section, func(i, j int) bool { return section[i].index < section[j].index })
+	return section
+}
+
+// appendSection appends a record for every mapped field of section
+func appendSection[T any](t *Transformer, records []MetricRecord, counters sectionCounters[T], section *T, causeCode int, timestamp time.Time) []MetricRecord {
+	v := reflect.ValueOf(section).Elem()
+	for _, c := range counters {
+		field := v.Field(c.index)
+		if c.skipZero && field.IsZero() {
+			continue
+		}
+
+		var value uint64
+		switch {
+		case c.float:
+			value = uint64(field.Float() * 100)
+		case field.CanUint():
+			value = field.Uint()
+		default:
+			value = uint64(field.Int())
+		}
+		records = append(records, t.createRecord(c.counterID, value, causeCode, timestamp))
+	}
+	return records
+}
+
+var (
+	serviceCounters = newSectionCounters[statsmodel.ServiceStats](map[string]int{
+		"UptimeSeconds": CounterUptimeSeconds,
+	})
+
+	requestCounters = newSectionCounters[statsmodel.RequestStats](map[string]int{
+		"Total":      CounterTotalRequests,
+		"Success":    CounterSuccessfulRequests,
+		"Failed":     CounterFailedRequests,
+		"Pending":    CounterPendingRequests,
+		"MaxPending": CounterMaxPendingRequests,
+		"BytesSent":  CounterBytesSent,
+		"BytesRecv":  CounterBytesRecv,
+	})
+
+	diameterSourceCounters = newSectionCounters[statsmodel.SourceStats](map[string]int{
+		"InFlight":  CounterDiameterInFlight,
+		"BytesSent": CounterDiameterBytesSent,
+		"BytesRecv": CounterDiameterBytesRecv,
+	})
+
+	httpSourceCounters = newSectionCounters[statsmodel.SourceStats](map[string]int{
+		"InFlight":  CounterHTTPInFlight,
+		"BytesSent": CounterHTTPBytesSent,
+		"BytesRecv": CounterHTTPBytesRecv,
+	})
+
+	connectionCounters = newSectionCounters[statsmodel.ConnectionStats](map[string]int{
+		"Total":  CounterTotalConnections,
+		"Active": CounterActiveConnections,
+		"Failed": CounterFailedConnections,
+		"Closed": CounterClosedConnections,
+	})
+
+	listenerCounters = newSectionCounters[statsmodel.ListenerStats](map[string]int{
+		"Active": CounterListenerActive,
+		"Total":  CounterListenerTotal,
+		"Failed": CounterListenerFailed,
+		"Closed": CounterListenerClosed,
+	})
+
+	tenantCounters = newSectionCounters[statsmodel.TenantStats](map[string]int{
+		"Total":     CounterTenantRequests,
+		"Success":   CounterTenantSuccess,
+		"Failed":    CounterTenantFailed,
+		"BytesSent": CounterTenantBytesSent,
+		"BytesRecv": CounterTenantBytesRecv,
+	})
+
+	capacityCounters = newSectionCounters[statsmodel.CapacityStats](map[string]int{
+		"LicensedTPS":            CounterLicensedTPS,
+		"PeakTPS":                CounterPeakTPS,
+		"LicensedSubscribers":    CounterLicensedSubscribers,
+		"ProvisionedSubscribers": CounterProvisionedSubscribers,
+	})
+
+	sctpCounters = newSectionCounters[statsmodel.SCTPStats](map[string]int{
+		"ActiveAssociations": CounterSCTPActiveAssociations,
+		"Establishes":        CounterSCTPEstablishes,
+		"Aborts":             CounterSCTPAborts,
+		"Shutdowns":          CounterSCTPShutdowns,
+		"PathFailovers":      CounterSCTPPathFailovers,
+		"Retransmits":        CounterSCTPRetransmits,
+		"GapAcks":            CounterSCTPGapAcks,
+	})
+
+	overloadCounters = newSectionCounters[statsmodel.OverloadStats](map[string]int{
+		"LoadLevel": CounterOverloadLevel,
+		"Throttled": CounterThrottled,
+		"Rejected":  CounterOverloadRejected,
+	})
+
+	interfaceOverloadCounters = newSectionCounters[statsmodel.InterfaceOverloadStats](map[string]int{
+		"Throttled": CounterInterfaceThrottled,
+		"Rejected":  CounterInterfaceOverloadRejected,
+	})
+
+	peerCounters = newSectionCounters[statsmodel.PeerStats](map[string]int{
+		"UptimeSeconds": CounterPeerUptimeSeconds,
+		"DWRFailures":   CounterPeerDWRFailures,
+		"MessagesSent":  CounterPeerMessagesSent,
+		"MessagesRecv":  CounterPeerMessagesRecv,
+		"Disconnects":   CounterPeerDisconnects,
+	})
+
+	configProviderCounters = newSectionCounters[statsmodel.ConfigProviderStats](map[string]int{
+		"Loads":            CounterConfigProviderLoads,
+		"LoadFailures":     CounterConfigProviderLoadFailures,
+		"StalenessSeconds": CounterConfigProviderStalenessSeconds,
+	})
+
+	runtimeCounters = newSectionCounters[statsmodel.GoRuntimeStats](map[string]int{
+		"Goroutines":     CounterGoroutines,
+		"HeapInUseBytes": CounterHeapInUseBytes,
+		"HeapObjects":    CounterHeapObjects,
+		"NumGC":          CounterGCCount,
+		"GCPauseP99Ms":   CounterGCPauseP99Ms,
+		"CPUPercent":     CounterCPUPercent,
+	})
+
+	diameterCheckCounters = newSectionCounters[statsmodel.InterfaceCheckStats](map[string]int{
+		"Total":   CounterDiameterTotal,
+		"Success": CounterDiameterSuccess,
+		"Failed":  CounterDiameterFailed,
+	})
+
+	httpCheckCounters = newSectionCounters[statsmodel.InterfaceCheckStats](map[string]int{
+		"Total":   CounterHTTPTotal,
+		"Success": CounterHTTPSuccess,
+		"Failed":  CounterHTTPFailed,
+	})
+
+	cacheCounters = newSectionCounters[statsmodel.CacheStats](map[string]int{
+		"Hits":        CounterCacheHits,
+		"Misses":      CounterCacheMisses,
+		"HitRate":     CounterCacheHitRate,
+		"Size":        CounterCacheSize,
+		"MaxSize":     CounterCacheMaxSize,
+		"Evictions":   CounterCacheEvictions,
+		"Expirations": CounterCacheExpirations,
+		"Bytes":       CounterCacheBytes,
+	})
+
+	selfTestCounters = newSectionCounters[statsmodel.SelfTestStats](map[string]int{
+		"Runs":                CounterSelfTestRuns,
+		"Passed":              CounterSelfTestPassed,
+		"Failed":              CounterSelfTestFailed,
+		"Timeouts":            CounterSelfTestTimeouts,
+		"ConsecutiveFailures": CounterSelfTestConsecutiveFailures,
+		"LastLatencyMs":       CounterSelfTestLatencyMs,
+		"SecondsSinceSuccess": CounterSelfTestSecondsSinceSuccess,
+	})
+
+	dbCounters = newSectionCounters[statsmodel.DatabaseOperationStats](map[string]int{
+		"Queries":      CounterDBQueries,
+		"Inserts":      CounterDBInserts,
+		"Updates":      CounterDBUpdates,
+		"Deletes":      CounterDBDeletes,
+		"Errors":       CounterDBErrors,
+		"AvgLatencyMs": CounterDBAvgLatencyMs,
+	})
+)
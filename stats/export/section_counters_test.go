@@ -0,0 +1,174 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestSemantics_ModelTagged tests every numeric stats field declares gauge or counter semantics
+func TestSemantics_ModelTagged(t *testing.T) {
+	for _, v := range []interface{}{statsmodel.ServiceStats{}, statsmodel.EIRStats{}, statsmodel.CacheStats{}, statsmodel.SelfTestStats{}, statsmodel.HarvestedMetrics{}} {
+		if err := statsmodel.CheckSemantics(v); err != nil {
+			t.Errorf("CheckSemantics(%T) = %v", v, err)
+		}
+	}
+
+	type untagged struct {
+		Drops uint64
+		Queue struct {
+			Depth int `stats:"gauge"`
+			Lost  int `stats:"count"`
+		}
+		Name string `stats:"counter"`
+	}
+	err := statsmodel.CheckSemantics(&untagged{})
+	if err == nil {
+		t.Fatal("Expected errors for untagged and mis-tagged fields")
+	}
+	for _, want := range []string{"untagged.Drops", "untagged.Queue.Lost", "untagged.Name"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s in %v", want, err)
+		}
+	}
+}
+
+// TestSectionCounters_MatchCatalog tests mapped fields have the semantics of their counter's catalog type
+func TestSectionCounters_MatchCatalog(t *testing.T) {
+	kinds := counterKinds()
+	sections := []struct {
+		typ      reflect.Type
+		counters []sectionCounter
+	}{
+		{reflect.TypeOf(statsmodel.ServiceStats{}), serviceCounters},
+		{reflect.TypeOf(statsmodel.RequestStats{}), requestCounters},
+		{reflect.TypeOf(statsmodel.SourceStats{}), diameterSourceCounters},
+		{reflect.TypeOf(statsmodel.SourceStats{}), httpSourceCounters},
+		{reflect.TypeOf(statsmodel.ConnectionStats{}), connectionCounters},
+		{reflect.TypeOf(statsmodel.ListenerStats{}), listenerCounters},
+		{reflect.TypeOf(statsmodel.CapacityStats{}), capacityCounters},
+		{reflect.TypeOf(statsmodel.SCTPStats{}), sctpCounters},
+		{reflect.TypeOf(statsmodel.OverloadStats{}), overloadCounters},
+		{reflect.TypeOf(statsmodel.InterfaceOverloadStats{}), interfaceOverloadCounters},
+		{reflect.TypeOf(statsmodel.PeerStats{}), peerCounters},
+		{reflect.TypeOf(statsmodel.ConfigProviderStats{}), configProviderCounters},
+		{reflect.TypeOf(statsmodel.GoRuntimeStats{}), runtimeCounters},
+		{reflect.TypeOf(statsmodel.InterfaceCheckStats{}), diameterCheckCounters},
+		{reflect.TypeOf(statsmodel.InterfaceCheckStats{}), httpCheckCounters},
+		{reflect.TypeOf(statsmodel.CacheStats{}), cacheCounters},
+		{reflect.TypeOf(statsmodel.DatabaseOperationStats{}), dbCounters},
+	}
+
+	for _, section := range sections {
+		for _, c := range section.counters {
+			field := section.typ.Field(c.index)
+			sem := statsmodel.SemanticsOf(field).Semantics
+			kind := kinds[c.counterID]
+			if kind == "rate" {
+				kind = "gauge"
+			}
+			if sem.String() != kind {
+				t.Errorf("%s.%s is a %s but counter %d is a %q", section.typ.Name(), field.Name, sem, c.counterID, kinds[c.counterID])
+			}
+		}
+	}
+}
+
+type testQueueSection struct {
+	Name     string
+	Depth    uint64                    `stats:"gauge"`
+	Enqueued uint64                    `stats:"counter"`
+	Drops    map[string]uint64         `stats:"counter"`
+	ByQueue  map[string]testQueueEntry `stats:"omitidle"`
+	Stages   map[string]testQueueEntry
+	Worker   *testQueueEntry
+	Debug    map[string]string `stats:"-"`
+}
+
+type testQueueEntry struct {
+	Items   uint64  `stats:"counter"`
+	Latency float64 `stats:"gauge"`
+}
+
+// TestDelta_Semantics tests Delta subtracts counters and copies gauges as declared
+func TestDelta_Semantics(t *testing.T) {
+	prev := testQueueSection{
+		Depth:    50,
+		Enqueued: 100,
+		Drops:    map[string]uint64{"full": 3, "expired": 2},
+		ByQueue:  map[string]testQueueEntry{"a": {Items: 10}, "b": {Items: 5}},
+		Stages:   map[string]testQueueEntry{"decode": {Items: 7}},
+	}
+	current := testQueueSection{
+		Name:     "ingress",
+		Depth:    20,
+		Enqueued: 130,
+		Drops:    map[string]uint64{"full": 3, "expired": 4, "shutdown": 1},
+		ByQueue:  map[string]testQueueEntry{"a": {Items: 10, Latency: 2}, "b": {Items: 8}},
+		Stages:   map[string]testQueueEntry{"decode": {Items: 7, Latency: 1.5}},
+		Worker:   &testQueueEntry{Items: 4},
+		Debug:    map[string]string{"x": "y"},
+	}
+
+	delta := statsmodel.Delta(current, prev)
+	want := testQueueSection{
+		Name:     "ingress",
+		Depth:    20,
+		Enqueued: 30,
+		Drops:    map[string]uint64{"expired": 2, "shutdown": 1},
+		ByQueue:  map[string]testQueueEntry{"b": {Items: 3}},
+		Stages:   map[string]testQueueEntry{"decode": {Latency: 1.5}},
+		Worker:   &testQueueEntry{Items: 4},
+	}
+	if !reflect.DeepEqual(delta, want) {
+		t.Errorf("Delta() = %+v, want %+v", delta, want)
+	}
+
+}
+
+// TestDelta_CounterReset tests a counter below its previous value (service restart)
+// reports its new count
+func TestDelta_CounterReset(t *testing.T) {
+	prev := testQueueSection{
+		Depth:    50,
+		Enqueued: 1000,
+		Drops:    map[string]uint64{"full": 3, "expired": 40},
+		ByQueue:  map[string]testQueueEntry{"a": {Items: 100}},
+	}
+	current := testQueueSection{
+		Depth:    5,
+		Enqueued: 12,
+		Drops:    map[string]uint64{"full": 3, "expired": 2},
+		ByQueue:  map[string]testQueueEntry{"a": {Items: 7}},
+	}
+
+	delta := statsmodel.Delta(current, prev)
+	want := testQueueSection{
+		Depth:    5,
+		Enqueued: 12,
+		Drops:    map[string]uint64{"expired": 2},
+		ByQueue:  map[string]testQueueEntry{"a": {Items: 7}},
+	}
+	if !reflect.DeepEqual(delta, want) {
+		t.Errorf("Delta() after reset = %+v, want %+v", delta, want)
+	}
+}
+
+// TestAppendSection_ZeroFiltering tests zero counters and omitzero gauges are skipped, plain gauges kept
+func TestAppendSection_ZeroFiltering(t *testing.T) {
+	transformer := NewTransformer("eir-1", "EIR")
+	ts := time.Now()
+
+	records := appendSection(transformer, nil, connectionCounters, &statsmodel.ConnectionStats{Failed: 2}, 0, ts)
+	if len(records) != 2 || records[0].CounterID != CounterActiveConnections || records[1].CounterID != CounterFailedConnections {
+		t.Errorf("Expected active gauge and failed counter, got %+v", records)
+	}
+
+	records = appendSection(transformer, nil, cacheCounters, &statsmodel.CacheStats{HitRate: 87.5}, 0, ts)
+	if len(records) != 1 || records[0].CounterID != CounterCacheHitRate || records[0].Value != 8750 {
+		t.Errorf("Expected only the scaled hit rate, got %+v", records)
+	}
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestSelfTest_Export tests self-test results are exported per cycle from the collector's selftest section
+func TestSelfTest_Export(t *testing.T) {
+	ctx := context.Background()
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR", Clock: clock})
+	h := NewSchedulerHarness(collector, HarnessConfig{
+		Clock:       clock,
+		Transformer: TransformerConfig{SampleRate: 1.0},
+	})
+
+	selfTest := statsmodel.NewSelfTest(statsmodel.SelfTestConfig{Timeout: 50 * time.Millisecond})
+	selfTest.Register("s13", func(ctx context.Context) error {
+		if !statsmodel.IsSelfTest(ctx) {
+			return errors.New("context not marked as self-test")
+		}
+		collector.RecordRequest(statsmodel.SelfTestSource, true)
+		return nil
+	})
+	healthy := true
+	selfTest.Register("db", func(ctx context.Context) error {
+		if healthy {
+			return nil
+		}
+		<-ctx.Done() // Hangs
+		return ctx.Err()
+	})
+	collector.SetSelfTest(selfTest)
+
+	if err := selfTest.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	h.Tick(ctx)
+
+	healthy = false
+	if err := selfTest.RunOnce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the db check to time out, got %v", err)
+	}
+	if err := selfTest.RunOnce(ctx); err == nil {
+		t.Fatal("Expected the db check to fail again")
+	}
+	h.Tick(ctx)
+
+	values := func(batch int) map[int]uint64 {
+		values := make(map[int]uint64)
+		for _, r := range h.Exporter.Batches()[batch].Records {
+			values[r.CounterID] = r.Value
+		}
+		return values
+	}
+
+	first := values(0)
+	if first[CounterSelfTestRuns] != 2 || first[CounterSelfTestPassed] != 2 || first[CounterSelfTestConsecutiveFailures] != 0 {
+		t.Errorf("Expected 2 passed checks in the first cycle, got %v", first)
+	}
+	if _, ok := first[CounterSelfTestFailed]; ok {
+		t.Error("Expected no failed record without failures")
+	}
+
+	second := values(1)
+	want := map[int]uint64{
+		CounterSelfTestRuns:                4,
+		CounterSelfTestPassed:              2,
+		CounterSelfTestFailed:              2,
+		CounterSelfTestTimeouts:            2,
+		CounterSelfTestConsecutiveFailures: 2,
+	}
+	for id, value := range want {
+		if second[id] != value {
+			t.Errorf("Expected %s = %d in the second cycle, got %d", GetCounterName(id), value, second[id])
+		}
+	}
+
+	section, ok := collector.Snapshot().CustomMetrics.SelfTest()
+	if !ok || section.Checks["db"].ConsecutiveFailures != 2 || section.Checks["s13"].Passed != 3 || section.Checks["db"].LastError == "" {
+		t.Errorf("Expected per-check stats, got %+v", section)
+	}
+	if out := roundTrip(t, collector.Snapshot()); out.CustomMetrics["selftest"] == nil {
+		t.Error("Expected the selftest section to survive JSON")
+	} else if _, ok := out.CustomMetrics.SelfTest(); !ok {
+		t.Errorf("Expected *SelfTestStats after JSON, got %T", out.CustomMetrics["selftest"])
+	}
+}
+
+// TestSelfTest_Clock tests latency and time since success follow the injected clock
+func TestSelfTest_Clock(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	clock := statsmodel.NewFakeClock(start)
+	selfTest := statsmodel.NewSelfTest(statsmodel.SelfTestConfig{Clock: clock})
+
+	fail := false
+	selfTest.Register("s13", func(ctx context.Context) error {
+		clock.Advance(250 * time.Millisecond) // Simulated round trip
+		if fail {
+			return errors.New("no answer")
+		}
+		return nil
+	})
+
+	if err := selfTest.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	fail = true
+	selfTest.RunOnce(ctx)
+	clock.Advance(time.Minute)
+
+	stats := selfTest.Stats()
+	if stats.LastLatencyMs != 250 {
+		t.Errorf("Expected 250ms latency, got %v", stats.LastLatencyMs)
+	}
+	if !stats.LastSuccess.Equal(start.Add(250 * time.Millisecond)) {
+		t.Errorf("Expected the first round as last success, got %v", stats.LastSuccess)
+	}
+	if stats.SecondsSinceSuccess != 60.25 {
+		t.Errorf("Expected 60.25s since success, got %v", stats.SecondsSinceSuccess)
+	}
+}
+
+// TestSelfTest_Restart tests a stopped self-test runs rounds again when restarted
+func TestSelfTest_Restart(t *testing.T) {
+	ctx := context.Background()
+	clock := statsmodel.NewFakeClock(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))
+	selfTest := statsmodel.NewSelfTest(statsmodel.SelfTestConfig{Interval: time.Minute, Clock: clock})
+	selfTest.Register("s13", func(ctx context.Context) error { return nil })
+
+	waitRuns := func(want uint64) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for selfTest.Stats().Runs != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d runs, got %d", want, selfTest.Stats().Runs)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitTicker := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for clock.TickerCount() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d tickers, got %d", want, clock.TickerCount())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	for round := uint64(0); round < 2; round++ {
+		selfTest.Start(ctx)
+		waitTicker(1) // The first round ran before the ticker was created
+		waitRuns(2*round + 1)
+		clock.Advance(time.Minute)
+		waitRuns(2*round + 2)
+		selfTest.Stop()
+		waitTicker(0)
+	}
+
+	// Stopped by its context, it can be started again as well
+	cancelled, cancel := context.WithCancel(ctx)
+	selfTest.Start(cancelled)
+	waitTicker(1)
+	cancel()
+	waitTicker(0)
+	selfTest.Start(ctx)
+	defer selfTest.Stop()
+	waitRuns(6)
+}
@@ -0,0 +1,367 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Signature algorithms
+const (
+	SignatureHMACSHA256 = "hmac-sha256"
+	SignatureEd25519    = "ed25519"
+)
+
+// Headers carrying the detached signature of an HTTP export request body
+const (
+	HeaderExportSignature          = "X-Export-Signature"           // Base64 signature of the request body
+	HeaderExportSignatureAlgorithm = "X-Export-Signature-Algorithm" // SignatureHMACSHA256 or SignatureEd25519
+	HeaderExportKeyID              = "X-Export-Key-ID"              // Identifies the signing key (optional)
+)
+
+// ErrInvalidSignature is returned when a batch is unsigned or its signature doesn't verify
+var ErrInvalidSignature = errors.New("invalid batch signature")
+
+// SigningConfig configures batch signing for an exporter, or a verification key
+type SigningConfig struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm"` // SignatureHMACSHA256 or SignatureEd25519
+	KeyID     string `json:"key_id" yaml:"key_id"`       // Sent with each signature so receivers can rotate keys
+
+	// Key is the HMAC secret, or for Ed25519 the base64 or PEM private key when
+	// signing and public key when verifying
+	Key string `json:"key" yaml:"key"`
+
+	// KeyFile holds the key instead of Key
+	KeyFile string `json:"key_file" yaml:"key_file"`
+}
+
+// BatchSignature is the signature of one exported batch
+type BatchSignature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id,omitempty"`
+	Value     string `json:"value"` // Base64 signature
+}
+
+// BatchSigner signs exported batches for tamper evidence
+type BatchSigner struct {
+	algorithm string
+	keyID     string
+	secret    []byte
+	private   ed25519.PrivateKey
+}
+
+// NewBatchSigner creates a signer from config
+func NewBatchSigner(config SigningConfig) (*BatchSigner, error) {
+	key, err := config.keyMaterial()
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &BatchSigner{algorithm: config.Algorithm, keyID: config.KeyID}
+	switch config.Algorithm {
+	case SignatureHMACSHA256:
+		signer.secret = key
+	case SignatureEd25519:
+		if signer.private, err = parseEd25519PrivateKey(key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown signature algorithm: %q", config.Algorithm)
+	}
+	return signer, nil
+}
+
+// Sign signs data, the exact bytes of a batch as exported
+func (s *BatchSigner) Sign(data []byte) BatchSignature {
+	var sig []byte
+	if s.algorithm == SignatureEd25519 {
+		sig = ed25519.Sign(s.private, data)
+	} else {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(data)
+		sig = mac.Sum(nil)
+	}
+	return BatchSignature{
+		Algorithm: s.algorithm,
+		KeyID:     s.keyID,
+		Value:     base64.StdEncoding.EncodeToString(sig),
+	}
+}
+
+// setHeaders sets the detached signature headers for body
+func (s *BatchSigner) setHeaders(header http.Header, body []byte) {
+	sig := s.Sign(body)
+	header.Set(HeaderExportSignature, sig.Value)
+	header.Set(HeaderExportSignatureAlgorithm, sig.Algorithm)
+	if sig.KeyID != "" {
+		header.Set(HeaderExportKeyID, sig.KeyID)
+	}
+}
+
+// SignatureVerifier verifies signed batches on the receiving side
+type SignatureVerifier struct {
+	keys []verificationKey
+}
+
+// verificationKey is one accepted key
+type verificationKey struct {
+	algorithm string
+	keyID     string
+	secret    []byte
+	public    ed25519.PublicKey
+}
+
+// NewSignatureVerifier creates a verifier accepting signatures by any of the keys
+// A key with a KeyID only verifies signatures carrying that key ID
+func NewSignatureVerifier(keys ...SigningConfig) (*SignatureVerifier, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("signature verifier requires at least one key")
+	}
+
+	v := &SignatureVerifier{}
+	for _, config := range keys {
+		material, err := config.keyMaterial()
+		if err != nil {
+			return nil, err
+		}
+
+		key := verificationKey{algorithm: config.Algorithm, keyID: config.KeyID}
+		switch config.Algorithm {
+		case SignatureHMACSHA256:
+			key.secret = material
+		case SignatureEd25519:
+			if key.public, err = parseEd25519PublicKey(material); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown signature algorithm: %q", config.Algorithm)
+		}
+		v.keys = append(v.keys, key)
+	}
+	return v, nil
+}
+
+// Verify checks sig against data
+func (v *SignatureVerifier) Verify(data []byte, sig BatchSignature) error {
+	raw, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil || len(raw) == 0 {
+		return ErrInvalidSignature
+	}
+
+	for _, key := range v.keys {
+		if key.algorithm != sig.Algorithm || (key.keyID != "" && key.keyID != sig.KeyID) {
+			continue
+		}
+		if key.algorithm == SignatureEd25519 {
+			if ed25519.Verify(key.public, data, raw) {
+				return nil
+			}
+			continue
+		}
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(data)
+		if hmac.Equal(mac.Sum(nil), raw) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// VerifyRequest verifies the detached signature of an HTTP export request and
+// returns its body; the request body is replaced so it can be read again
+func (v *SignatureVerifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	sig := BatchSignature{
+		Algorithm: r.Header.Get(HeaderExportSignatureAlgorithm),
+		KeyID:     r.Header.Get(HeaderExportKeyID),
+		Value:     r.Header.Get(HeaderExportSignature),
+	}
+	if err := v.Verify(body, sig); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// VerifyPushFrame verifies the signature field of a push batch frame
+func (v *SignatureVerifier) VerifyPushFrame(frame PushFrame) error {
+	if frame.Signature == nil {
+		return ErrInvalidSignature
+	}
+	data, err := json.Marshal(frame.Records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal records: %w", err)
+	}
+	return v.Verify(data, *frame.Signature)
+}
+
+// fileSignatureLine is the line a signing FileExporter writes after each batch
+type fileSignatureLine struct {
+	Signature *BatchSignature `json:"signature"`
+	Records   int             `json:"records"`
+}
+
+// fileSignaturePrefix starts every signature line
+const fileSignaturePrefix = `{"signature":`
+
+// VerifyJSONL verifies a file written by a signing FileExporter: every batch of
+// record lines must be followed by a signature line covering exactly those lines
+// Returns the number of verified batches
+func (v *SignatureVerifier) VerifyJSONL(r io.Reader) (int, error) {
+	reader := bufio.NewReader(r)
+	var batch bytes.Buffer
+	records, batches := 0, 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if !bytes.HasPrefix(line, []byte(fileSignaturePrefix)) {
+				batch.Write(line)
+				records++
+			} else {
+				var sig fileSignatureLine
+				if err := json.Unmarshal(line, &sig); err != nil || sig.Signature == nil {
+					return batches, fmt.Errorf("batch %d: malformed signature line", batches+1)
+				}
+				if sig.Records != records {
+					return batches, fmt.Errorf("batch %d: signature covers %d records, found %d: %w",
+						batches+1, sig.Records, records, ErrInvalidSignature)
+				}
+				if err := v.Verify(batch.Bytes(), *sig.Signature); err != nil {
+					return batches, fmt.Errorf("batch %d: %w", batches+1, err)
+				}
+				batch.Reset()
+				records = 0
+				batches++
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return batches, err
+		}
+	}
+
+	if records > 0 {
+		return batches, fmt.Errorf("%d trailing records without a signature: %w", records, ErrInvalidSignature)
+	}
+	return batches, nil
+}
+
+// keyMaterial returns the configured key bytes
+func (c SigningConfig) keyMaterial() ([]byte, error) {
+	if c.Key != "" {
+		return []byte(c.Key), nil
+	}
+	if c.KeyFile == "" {
+		return nil, fmt.Errorf("signing key or key_file is required")
+	}
+	data, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	return bytes.TrimRight(data, "\r\n"), nil
+}
+
+// parseEd25519PrivateKey parses a PEM PKCS#8 key, or a base64 seed or private key
+func parseEd25519PrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Ed25519 private key: %w", err)
+		}
+		private, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM key is not an Ed25519 private key")
+		}
+		return private, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Ed25519 private key: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid Ed25519 private key: %d bytes", len(raw))
+	}
+}
+
+// parseEd25519PublicKey parses a PEM PKIX key or a base64 public key
+func parseEd25519PublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Ed25519 public key: %w", err)
+		}
+		public, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM key is not an Ed25519 public key")
+		}
+		return public, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// parseSigningConfig reads the "signing" block of an exporter's generic config
+func parseSigningConfig(config map[string]interface{}) (*SigningConfig, error) {
+	raw, ok := config["signing"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("signing must be a map")
+	}
+
+	signing := &SigningConfig{}
+	fields := map[string]*string{
+		"algorithm": &signing.Algorithm,
+		"key_id":    &signing.KeyID,
+		"key":       &signing.Key,
+		"key_file":  &signing.KeyFile,
+	}
+	for key, target := range fields {
+		switch v := m[key].(type) {
+		case nil:
+		case string:
+			*target = v
+		default:
+			return nil, fmt.Errorf("signing %s must be a string", key)
+		}
+	}
+
+	// Fail at startup rather than on the first export
+	if _, err := NewBatchSigner(*signing); err != nil {
+		return nil, err
+	}
+	return signing, nil
+}
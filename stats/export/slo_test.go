@@ -0,0 +1,85 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestCollector_SLO tests compliance time and error budgets are tracked per evaluation interval
+func TestCollector_SLO(t *testing.T) {
+	clock := statsmodel.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{
+		ServiceName: "EIR",
+		Clock:       clock,
+		SLOs: []statsmodel.SLOTarget{{
+			Operation:        "check",
+			SuccessRatio:     0.9,
+			LatencyThreshold: 50 * time.Millisecond,
+			Window:           time.Hour,
+		}},
+	})
+
+	// First minute: 1 failure in 20 (within the 10% failure budget), all fast
+	for i := 0; i < 20; i++ {
+		collector.RecordOperation("check", i != 0, 10*time.Millisecond)
+	}
+	clock.Advance(time.Minute)
+
+	// Second minute: 4 slow requests in 10 (p95 objective violated)
+	for i := 0; i < 10; i++ {
+		collector.RecordOperation("check", true, time.Duration(i*10)*time.Millisecond)
+	}
+	clock.Advance(time.Minute)
+
+	slo := collector.Snapshot().SLOs["check"]
+	if slo.Total != 30 || slo.Failed != 1 || slo.Slow != 4 {
+		t.Errorf("Expected 30 requests, 1 failed and 4 slow, got %+v", slo)
+	}
+	if slo.Compliant {
+		t.Error("Expected the last interval to violate the latency objective")
+	}
+	if slo.ComplianceSeconds != 60 || slo.ViolationSeconds != 60 {
+		t.Errorf("Expected 60s compliant and 60s violating, got %d and %d", slo.ComplianceSeconds, slo.ViolationSeconds)
+	}
+	if want := 1 - 1/3.0; slo.ErrorBudgetRemaining < want-1e-9 || slo.ErrorBudgetRemaining > want+1e-9 {
+		t.Errorf("Expected %.3f of the error budget left, got %.3f", want, slo.ErrorBudgetRemaining)
+	}
+	if op := collector.Snapshot().Requests.ByOperation["check"]; op.Total != 30 || op.Failed != 1 {
+		t.Errorf("Expected the operation breakdown to count 30 requests, got %+v", op)
+	}
+
+	// A new window restarts the budget
+	clock.Advance(time.Hour)
+	if slo := collector.Snapshot().SLOs["check"]; slo.Total != 0 || slo.ErrorBudgetRemaining != 1 || !slo.Compliant {
+		t.Errorf("Expected a fresh window, got %+v", slo)
+	}
+}
+
+// TestTransformer_SLOStats tests SLO counters are exported per operation
+func TestTransformer_SLOStats(t *testing.T) {
+	h := NewSchedulerHarness(StatsFunc(func() *statsmodel.ServiceStats {
+		return &statsmodel.ServiceStats{
+			SLOs: map[string]statsmodel.SLOStats{
+				"check":   {Compliant: true, ComplianceSeconds: 300, ErrorBudgetRemaining: 0.755, LatencyBudgetRemaining: -0.5},
+				"unknown": {Compliant: true},
+			},
+		}
+	}), HarnessConfig{})
+	h.Ticks(context.Background(), 2)
+
+	code := OperationCauseCodes["check"]
+	expected := map[int]uint64{
+		CounterSLOCompliant:              1,
+		CounterSLOComplianceSeconds:      300,
+		CounterSLOErrorBudgetRemaining:   76,
+		CounterSLOLatencyBudgetRemaining: 0,
+	}
+	for counter, want := range expected {
+		if got, ok := h.Exporter.Last(counter, code); !ok || got != want {
+			t.Errorf("Counter %d: expected %d, got %d (found=%v)", counter, want, got, ok)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestCollector_Tenants tests tenant recorders update both the tenant's and the overall stats
+func TestCollector_Tenants(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "DIAM-GW", MaxTenants: 2})
+
+	mvnoA := collector.Tenant("1-000001")
+	mvnoA.RecordRequest("diameter", true)
+	mvnoA.RecordRequest("diameter", false)
+	mvnoA.RecordResultCode("diameter", 5012)
+	mvnoA.RecordBytes("diameter", 100, 200)
+	mvnoA.RecordLatency("diameter", "check", 4*time.Millisecond)
+
+	collector.Tenant("mvno-b").RecordRequest("http", true)
+	collector.Tenant("mvno-c").RecordRequest("http", true) // Over MaxTenants
+	collector.Tenant("").RecordRequest("http", true)
+
+	stats := collector.Snapshot()
+	if stats.Requests.Total != 5 {
+		t.Errorf("Expected 5 requests overall, got %d", stats.Requests.Total)
+	}
+	if len(stats.Tenants) != 2 {
+		t.Fatalf("Expected 2 tenants, got %+v", stats.Tenants)
+	}
+	a := stats.Tenants["1-000001"]
+	if a.Total != 2 || a.Success != 1 || a.Failed != 1 || a.BytesRecv != 200 || a.ByResultCode[5012] != 1 {
+		t.Errorf("Unexpected tenant stats %+v", a)
+	}
+	if a.Latency.Count != 1 || a.Latency.MaxLatencyMs != 4 {
+		t.Errorf("Expected one 4ms latency sample, got %+v", a.Latency)
+	}
+}
+
+// TestTransformer_TenantRecords tests tenant deltas are exported with the tenant label
+func TestTransformer_TenantRecords(t *testing.T) {
+	scheduler := NewExportScheduler(time.Minute, &mockStatsCollector{}, NewTransformer("h", "s"), &mockLogger{})
+
+	scheduler.updatePreviousSnapshot(&statsmodel.ServiceStats{
+		Tenants: map[string]statsmodel.TenantStats{
+			"mvno-a": {Total: 10, Success: 9, Failed: 1, ByResultCode: map[int]uint64{5012: 1}},
+			"mvno-b": {Total: 5, Success: 5},
+		},
+	})
+	delta := scheduler.calculateDeltaStats(&statsmodel.ServiceStats{
+		Tenants: map[string]statsmodel.TenantStats{
+			"mvno-a": {Total: 13, Success: 11, Failed: 2, ByResultCode: map[int]uint64{5012: 2}},
+			"mvno-b": {Total: 5, Success: 5},
+		},
+	})
+	if _, ok := delta.Tenants["mvno-b"]; ok {
+		t.Errorf("Expected idle tenant to be dropped from the delta, got %+v", delta.Tenants)
+	}
+
+	records := NewTransformer("h", "s").Transform(delta)
+	want := map[int]uint64{CounterTenantRequests: 3, CounterTenantSuccess: 2, CounterTenantFailed: 1, CounterTenantResultCode: 1}
+	found := 0
+	for _, record := range records {
+		if record.Tenant == "" {
+			continue
+		}
+		if record.Tenant != "mvno-a" {
+			t.Errorf("Unexpected tenant record %+v", record)
+			continue
+		}
+		if value, ok := want[record.CounterID]; !ok || value != record.Value {
+			t.Errorf("Unexpected tenant record %+v", record)
+		}
+		if record.CounterID == CounterTenantResultCode && record.CauseCode != 5012 {
+			t.Errorf("Expected result code as cause code, got %+v", record)
+		}
+		found++
+	}
+	if found != len(want) {
+		t.Errorf("Expected %d tenant records, got %d", len(want), found)
+	}
+}
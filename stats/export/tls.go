@@ -0,0 +1,150 @@
+package export
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TLSSettings configures transport security for network exporters
+// The same "tls" block is accepted by every network exporter type in the registry
+type TLSSettings struct {
+	CAFile             string `json:"ca_file" yaml:"ca_file"`     // PEM bundle verifying the server (default: system roots)
+	CertFile           string `json:"cert_file" yaml:"cert_file"` // Client certificate for mutual TLS
+	KeyFile            string `json:"key_file" yaml:"key_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+	MinVersion         string `json:"min_version" yaml:"min_version"` // "1.2" (default) or "1.3"
+	ServerName         string `json:"server_name" yaml:"server_name"` // Overrides the name verified against the certificate
+}
+
+// Config builds a client tls.Config
+func (t *TLSSettings) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+		ServerName:         t.ServerName,
+	}
+
+	switch t.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min_version %q (use 1.2 or 1.3)", t.MinVersion)
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// PostgresConnectionString applies the settings to a lib/pq connection string (URL or
+// key=value form) as sslmode, sslrootcert, sslcert and sslkey parameters, overriding any
+// already present. lib/pq has no equivalent of MinVersion or ServerName; they are ignored
+func (t *TLSSettings) PostgresConnectionString(connStr string) (string, error) {
+	params := [][2]string{{"sslmode", "verify-full"}}
+	if t.InsecureSkipVerify {
+		params[0][1] = "require"
+	}
+	if t.CAFile != "" {
+		params = append(params, [2]string{"sslrootcert", t.CAFile})
+	}
+	if t.CertFile != "" {
+		params = append(params, [2]string{"sslcert", t.CertFile})
+	}
+	if t.KeyFile != "" {
+		params = append(params, [2]string{"sslkey", t.KeyFile})
+	}
+
+	if strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://") {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection string: %w", err)
+		}
+		query := u.Query()
+		for _, p := range params {
+			query.Set(p[0], p[1])
+		}
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	// lib/pq keeps the last occurrence of a repeated key
+	var b strings.Builder
+	b.WriteString(connStr)
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	for _, p := range params {
+		fmt.Fprintf(&b, " %s='%s'", p[0], quote.Replace(p[1]))
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// parseTLSSettings reads the optional "tls" block of an exporter's config
+func parseTLSSettings(config map[string]interface{}) (*TLSSettings, error) {
+	raw, ok := config["tls"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("tls must be a map")
+	}
+
+	settings := &TLSSettings{}
+	fields := map[string]*string{
+		"ca_file":     &settings.CAFile,
+		"cert_file":   &settings.CertFile,
+		"key_file":    &settings.KeyFile,
+		"min_version": &settings.MinVersion,
+		"server_name": &settings.ServerName,
+	}
+	for key, target := range fields {
+		switch v := m[key].(type) {
+		case nil:
+		case string:
+			*target = v
+		case float64:
+			// YAML/JSON may decode min_version: 1.2 as a number
+			*target = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("tls %s must be a string", key)
+		}
+	}
+
+	switch v := m["insecure_skip_verify"].(type) {
+	case nil:
+	case bool:
+		settings.InsecureSkipVerify = v
+	case string:
+		settings.InsecureSkipVerify = v == "true"
+	default:
+		return nil, fmt.Errorf("tls insecure_skip_verify must be a bool")
+	}
+
+	// Fail at startup rather than on the first export
+	if _, err := settings.Config(); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
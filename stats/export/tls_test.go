@@ -0,0 +1,101 @@
+package export
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTLSSettings_HTTPExporter tests an HTTP exporter trusts a server through ca_file
+func TestTLSSettings_HTTPExporter(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []MetricRecord{{CounterID: 1, Value: 1}}
+
+	untrusted, _ := NewHTTPExporter(HTTPExporterConfig{Name: "http", URL: server.URL, RetryAttempts: 1}, &mockLogger{})
+	if err := untrusted.Export(context.Background(), records); err == nil {
+		t.Error("Expected an unknown authority error without ca_file")
+	}
+
+	exporter, err := CreateExporter(ExporterConfig{
+		Type: "http",
+		Name: "http",
+		Config: map[string]interface{}{
+			"url":            server.URL,
+			"retry_attempts": 1,
+			"tls": map[string]interface{}{
+				"ca_file":     caFile,
+				"min_version": 1.2,
+				"server_name": "example.com", // httptest certificates are issued for example.com
+			},
+		},
+	}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("CreateExporter() error = %v", err)
+	}
+	if err := exporter.Export(context.Background(), records); err != nil {
+		t.Errorf("Export() error = %v", err)
+	}
+}
+
+// TestParseTLSSettings tests invalid tls blocks are rejected when the exporter is created
+func TestParseTLSSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		tls  interface{}
+	}{
+		{"not a map", "on"},
+		{"bad min_version", map[string]interface{}{"min_version": "1.0"}},
+		{"missing ca_file", map[string]interface{}{"ca_file": "/nonexistent/ca.pem"}},
+		{"cert without key", map[string]interface{}{"cert_file": "/nonexistent/cert.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTLSSettings(map[string]interface{}{"tls": tt.tls}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	settings, err := parseTLSSettings(map[string]interface{}{})
+	if err != nil || settings != nil {
+		t.Errorf("Expected no settings without a tls block, got %v, %v", settings, err)
+	}
+}
+
+// TestTLSSettings_PostgresConnectionString tests TLS settings map to lib/pq parameters
+func TestTLSSettings_PostgresConnectionString(t *testing.T) {
+	settings := &TLSSettings{CAFile: "/etc/ca.pem", CertFile: "/etc/client.pem", KeyFile: "/etc/client.key"}
+
+	got, err := settings.PostgresConnectionString("postgres://u:p@db:5432/metrics?sslmode=disable")
+	if err != nil {
+		t.Fatalf("PostgresConnectionString() error = %v", err)
+	}
+	for _, want := range []string{"sslmode=verify-full", "sslrootcert=%2Fetc%2Fca.pem", "sslcert=%2Fetc%2Fclient.pem", "sslkey=%2Fetc%2Fclient.key"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "disable") {
+		t.Errorf("Expected sslmode=disable to be replaced, got %q", got)
+	}
+
+	insecure := &TLSSettings{InsecureSkipVerify: true}
+	got, _ = insecure.PostgresConnectionString("host=db dbname=metrics sslmode=disable")
+	if !strings.HasSuffix(got, "sslmode='require'") {
+		t.Errorf("Expected sslmode='require' appended, got %q", got)
+	}
+}
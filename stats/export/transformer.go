@@ -1,6 +1,7 @@
 package export
 
 import (
+	"sync/atomic"
 	"time"
 
 	statsmodel "github.com/hsdfat/telco/stats"
@@ -11,6 +12,9 @@ type Transformer struct {
 	hostname   string
 	systemName string
 	config     TransformerConfig
+
+	causeCodes causeCodeCache // TAC cause codes parsed in previous cycles
+	sizeHint   atomic.Int64   // Records produced by the previous cycle, to presize the next
 }
 
 // NewTransformer creates a transformer with hostname and system name
@@ -35,32 +39,37 @@ func NewTransformerWithConfig(hostname, systemName string, config TransformerCon
 
 // Transform converts ServiceStats to MetricRecords
 func (t *Transformer) Transform(stats *statsmodel.ServiceStats) []MetricRecord {
-	records := make([]MetricRecord, 0, 100)
-	timestamp := stats.Timestamp
+	return t.TransformInto(nil, stats)
+}
 
-	// General request metrics (skip zero values for counters)
-	if stats.Requests.Total > 0 {
-		records = append(records, t.createRecord(CounterTotalRequests, stats.Requests.Total, 0, timestamp))
-	}
-	if stats.Requests.Success > 0 {
-		records = append(records, t.createRecord(CounterSuccessfulRequests, stats.Requests.Success, 0, timestamp))
-	}
-	if stats.Requests.Failed > 0 {
-		records = append(records, t.createRecord(CounterFailedRequests, stats.Requests.Failed, 0, timestamp))
+// TransformInto is Transform appending to dst[:0], for callers that reuse the record
+// slice between cycles once its exporters are done with it
+func (t *Transformer) TransformInto(dst []MetricRecord, stats *statsmodel.ServiceStats) []MetricRecord {
+	records := dst[:0]
+	if cap(records) == 0 {
+		records = make([]MetricRecord, 0, max(100, int(t.sizeHint.Load())))
 	}
-	if stats.Requests.Pending > 0 {
-		records = append(records, t.createRecord(CounterPendingRequests, stats.Requests.Pending, 0, timestamp))
+	timestamp := stats.Timestamp
+	if timestamp.IsZero() {
+		timestamp = t.now()
 	}
 
-	// Connection metrics (Active is gauge, others are counters)
-	// Always export Active connections (gauge - can be 0)
-	records = append(records, t.createRecord(CounterActiveConnections, stats.Connections.Active, 0, timestamp))
+	// General request metrics (zero values are skipped per the model's stats tags)
+	records = appendSection(t, records, requestCounters, &stats.Requests, 0, timestamp)
 
-	if stats.Connections.Total > 0 {
-		records = append(records, t.createRecord(CounterTotalConnections, stats.Connections.Total, 0, timestamp))
-	}
-	if stats.Connections.Failed > 0 {
-		records = append(records, t.createRecord(CounterFailedConnections, stats.Connections.Failed, 0, timestamp))
+	// Uptime gauge (only set by collectors that track their start time)
+	records = appendSection(t, records, serviceCounters, stats, 0, timestamp)
+
+	// Per-source in-flight, byte and message size metrics
+	records = append(records, t.transformSourceStats(stats.Requests.BySource, timestamp)...)
+
+	// Connection metrics (Active is a gauge exported even when 0, others are counters)
+	records = appendSection(t, records, connectionCounters, &stats.Connections, 0, timestamp)
+
+	// Per-listener connection metrics (cause code identifies the listener)
+	for listener, ls := range stats.Connections.ByListener {
+		code := ListenerCauseCodes.Code(listener)
+		records = appendSection(t, records, listenerCounters, &ls, code, timestamp)
 	}
 
 	// Performance metrics (all gauges - always export for visibility)
@@ -78,53 +87,273 @@ func (t *Transformer) Transform(stats *statsmodel.ServiceStats) []MetricRecord {
 		records = append(records, t.createRecord(CounterP99LatencyMs, uint64(stats.Performance.P99LatencyMs*100), 0, timestamp))
 	}
 
-	// EIR-specific metrics
-	if eirStats, ok := stats.CustomMetrics["eir"].(*statsmodel.EIRStats); ok {
-		records = append(records, t.transformEIRStats(eirStats, timestamp)...)
+	// Per-source and per-operation latency (cause code identifies the source/operation)
+	for source, latency := range stats.Performance.BySource {
+		if code, ok := SourceCauseCodes[source]; ok {
+			records = append(records, t.transformLatency(latency, code, CounterSourceAvgLatencyMs, timestamp)...)
+		}
+	}
+	for op, latency := range stats.Performance.ByOperation {
+		if code, ok := OperationCauseCodes[op]; ok {
+			records = append(records, t.transformLatency(latency, code, CounterOperationAvgLatencyMs, timestamp)...)
+		}
+	}
+
+	// Diameter peer metrics (cause code identifies the peer)
+	records = append(records, t.transformPeerStats(stats.Peers, timestamp)...)
+	records = append(records, t.transformConfigProviderStats(stats.ConfigProviders, timestamp)...)
+	records = append(records, t.transformSLOStats(stats.SLOs, timestamp)...)
+
+	// Per-tenant metrics (Tenant label identifies the tenant or network slice)
+	records = append(records, t.transformTenantStats(stats.Tenants, timestamp)...)
+
+	// SCTP transport metrics (optional section)
+	if stats.SCTP != nil {
+		records = append(records, t.transformSCTPStats(stats.SCTP, timestamp)...)
+	}
+
+	// Overload control metrics (optional section)
+	if stats.Overload != nil {
+		records = append(records, t.transformOverloadStats(stats.Overload, timestamp)...)
+	}
+
+	// License/capacity metrics (optional section, all gauges)
+	if stats.Capacity != nil {
+		records = appendSection(t, records, capacityCounters, stats.Capacity, 0, timestamp)
+	}
+
+	// Go runtime metrics (optional section, gauges always exported when present)
+	if stats.Runtime != nil {
+		records = append(records, t.transformRuntimeStats(stats.Runtime, timestamp)...)
+	}
+
+	// Custom metrics sections (EIR and registered handlers)
+	records = append(records, t.transformCustomMetrics(stats.CustomMetrics, timestamp)...)
+
+	// Health scores (optional, scored from this cycle's stats)
+	if t.config.HealthScorer != nil {
+		records = append(records, t.transformHealthScore(t.config.HealthScorer.Score(stats), timestamp)...)
+	}
+
+	t.sizeHint.Store(int64(len(records)))
+
+	// Filter and scale records based on configuration
+	return t.scaleRecords(t.filterRecords(records))
+}
+
+// transformHealthScore transforms the overall and per-subsystem health scores
+func (t *Transformer) transformHealthScore(score statsmodel.HealthScore, timestamp time.Time) []MetricRecord {
+	records := []MetricRecord{t.createRecord(CounterHealthScore, uint64(score.Score), 0, timestamp)}
+	for subsystem, health := range score.Subsystems {
+		if code, ok := HealthSubsystemCauseCodes[subsystem]; ok {
+			records = append(records, t.createRecord(CounterSubsystemHealthScore, uint64(health.Score), code, timestamp))
+		}
+	}
+	return records
+}
+
+// transformSCTPStats transforms SCTP association and transport stats
+func (t *Transformer) transformSCTPStats(sctp *statsmodel.SCTPStats, timestamp time.Time) []MetricRecord {
+	return appendSection(t, make([]MetricRecord, 0, 7), sctpCounters, sctp, 0, timestamp)
+}
+
+// transformOverloadStats transforms overload control stats
+func (t *Transformer) transformOverloadStats(overload *statsmodel.OverloadStats, timestamp time.Time) []MetricRecord {
+	records := make([]MetricRecord, 0, 3+len(overload.ByInterface)*2)
+
+	// Load level is a gauge - always export
+	records = appendSection(t, records, overloadCounters, overload, 0, timestamp)
+
+	for iface, ifStats := range overload.ByInterface {
+		code, ok := SourceCauseCodes[iface]
+		if !ok {
+			continue
+		}
+		records = appendSection(t, records, interfaceOverloadCounters, &ifStats, code, timestamp)
+	}
+
+	return records
+}
+
+// transformPeerStats transforms per-peer Diameter stats
+func (t *Transformer) transformPeerStats(peers map[string]statsmodel.PeerStats, timestamp time.Time) []MetricRecord {
+	records := make([]MetricRecord, 0, len(peers)*6)
+
+	for host, peer := range peers {
+		code := PeerCauseCodes.Code(host)
+
+		// Peer state and uptime are gauges - always export
+		var up uint64
+		if peer.State == statsmodel.PeerStateOpen {
+			up = 1
+		}
+		records = append(records, t.createRecord(CounterPeerUp, up, code, timestamp))
+		records = appendSection(t, records, peerCounters, &peer, code, timestamp)
+	}
+
+	return records
+}
+
+// transformConfigProviderStats transforms config provider health
+func (t *Transformer) transformConfigProviderStats(providers map[string]statsmodel.ConfigProviderStats, timestamp time.Time) []MetricRecord {
+	records := make([]MetricRecord, 0, len(providers)*5)
+
+	for name, provider := range providers {
+		code := ConfigProviderCauseCodes.Code(name)
+
+		// Health, latency and staleness are gauges - always export
+		var up uint64
+		if provider.Up {
+			up = 1
+		}
+		records = append(records, t.createRecord(CounterConfigProviderUp, up, code, timestamp))
+		records = append(records, t.createRecord(CounterConfigProviderLatencyMs, uint64(provider.LatencyMs), code, timestamp))
+		records = appendSection(t, records, configProviderCounters, &provider, code, timestamp)
+	}
+
+	return records
+}
+
+// transformSLOStats transforms per-operation SLO compliance for operations with a cause code
+func (t *Transformer) transformSLOStats(slos map[string]statsmodel.SLOStats, timestamp time.Time) []MetricRecord {
+	records := make([]MetricRecord, 0, len(slos)*5)
+
+	for op, slo := range slos {
+		code, ok := OperationCauseCodes[op]
+		if !ok {
+			continue
+		}
+
+		var compliant uint64
+		if slo.Compliant {
+			compliant = 1
+		}
+		records = append(records, t.createRecord(CounterSLOCompliant, compliant, code, timestamp))
+		records = append(records, t.createRecord(CounterSLOComplianceSeconds, slo.ComplianceSeconds, code, timestamp))
+		records = append(records, t.createRecord(CounterSLOViolationSeconds, slo.ViolationSeconds, code, timestamp))
+		records = append(records, t.createRecord(CounterSLOErrorBudgetRemaining, budgetPercent(slo.ErrorBudgetRemaining), code, timestamp))
+		records = append(records, t.createRecord(CounterSLOLatencyBudgetRemaining, budgetPercent(slo.LatencyBudgetRemaining), code, timestamp))
+	}
+
+	return records
+}
+
+// transformTenantStats transforms per-tenant KPIs, labelling each record with its tenant
+func (t *Transformer) transformTenantStats(tenants map[string]statsmodel.TenantStats, timestamp time.Time) []MetricRecord {
+	records := make([]MetricRecord, 0, len(tenants)*12)
+
+	for tenant, tenantStats := range tenants {
+		start := len(records)
+		records = appendSection(t, records, tenantCounters, &tenantStats, 0, timestamp)
+		for code, count := range tenantStats.ByResultCode {
+			if count > 0 {
+				records = append(records, t.createRecord(CounterTenantResultCode, count, code, timestamp))
+			}
+		}
+		records = append(records, t.transformLatency(tenantStats.Latency, 0, CounterTenantAvgLatencyMs, timestamp)...)
+
+		for i := start; i < len(records); i++ {
+			records[i].Tenant = tenant
+		}
+	}
+
+	return records
+}
+
+// budgetPercent converts a remaining budget share to a percentage, 0 once overspent
+func budgetPercent(remaining float64) uint64 {
+	if remaining <= 0 {
+		return 0
+	}
+	return uint64(remaining*100 + 0.5)
+}
+
+// transformSourceStats transforms per-source in-flight gauges, byte counts and message size histograms
+func (t *Transformer) transformSourceStats(bySource map[string]statsmodel.SourceStats, timestamp time.Time) []MetricRecord {
+	records := make([]MetricRecord, 0, 16)
+
+	for source, srcStats := range bySource {
+		var counters sectionCounters[statsmodel.SourceStats]
+		var sentSizeCounter, recvSizeCounter int
+
+		// Determine counter IDs based on source
+		switch source {
+		case "diameter":
+			counters = diameterSourceCounters
+			sentSizeCounter = CounterDiameterSentSize
+			recvSizeCounter = CounterDiameterRecvSize
+		case "http":
+			counters = httpSourceCounters
+			sentSizeCounter = CounterHTTPSentSize
+			recvSizeCounter = CounterHTTPRecvSize
+		default:
+			continue
+		}
+
+		// In-flight is a gauge - always export for known sources
+		records = appendSection(t, records, counters, &srcStats, 0, timestamp)
+
+		// Size histograms (use bucket upper bound directly as cause code)
+		for bucket, count := range srcStats.SentSizes {
+			if count > 0 {
+				records = append(records, t.createRecord(sentSizeCounter, count, bucket, timestamp))
+			}
+		}
+		for bucket, count := range srcStats.RecvSizes {
+			if count > 0 {
+				records = append(records, t.createRecord(recvSizeCounter, count, bucket, timestamp))
+			}
+		}
 	}
 
-	// Filter records based on configuration
-	return t.filterRecords(records)
+	return records
+}
+
+// transformRuntimeStats transforms Go runtime statistics
+func (t *Transformer) transformRuntimeStats(rt *statsmodel.GoRuntimeStats, timestamp time.Time) []MetricRecord {
+	return appendSection(t, make([]MetricRecord, 0, 6), runtimeCounters, rt, 0, timestamp)
+}
+
+// transformLatency creates avg/max/p50/p95/p99 records for a latency breakdown
+// Counter IDs are laid out consecutively starting at baseCounter (see counter_ids.go)
+func (t *Transformer) transformLatency(latency statsmodel.LatencyStats, causeCode, baseCounter int, timestamp time.Time) []MetricRecord {
+	if latency.Count == 0 {
+		return nil
+	}
+
+	// Convert float64 to uint64 by multiplying by 100 (2 decimal precision)
+	return []MetricRecord{
+		t.createRecord(baseCounter, uint64(latency.AvgLatencyMs*100), causeCode, timestamp),
+		t.createRecord(baseCounter+1, uint64(latency.MaxLatencyMs*100), causeCode, timestamp),
+		t.createRecord(baseCounter+2, uint64(latency.P50LatencyMs*100), causeCode, timestamp),
+		t.createRecord(baseCounter+3, uint64(latency.P95LatencyMs*100), causeCode, timestamp),
+		t.createRecord(baseCounter+4, uint64(latency.P99LatencyMs*100), causeCode, timestamp),
+	}
 }
 
 // transformEIRStats transforms EIR-specific statistics
 func (t *Transformer) transformEIRStats(eirStats *statsmodel.EIRStats, timestamp time.Time) []MetricRecord {
-	records := make([]MetricRecord, 0, 50)
+	records := make([]MetricRecord, 0, 50+len(eirStats.ByTAC)*4)
 
 	// Interface-specific metrics
 	for ifName, ifStats := range eirStats.EquipmentChecks.ByInterface {
-		var totalCounter, successCounter, failedCounter, resultCodeCounter int
+		var counters sectionCounters[statsmodel.InterfaceCheckStats]
+		var resultCodeCounter int
 
 		// Determine counter IDs based on interface
 		switch ifName {
 		case "diameter":
-			totalCounter = CounterDiameterTotal
-			successCounter = CounterDiameterSuccess
-			failedCounter = CounterDiameterFailed
+			counters = diameterCheckCounters
 			resultCodeCounter = CounterDiameterResultCode
 		case "http":
-			totalCounter = CounterHTTPTotal
-			successCounter = CounterHTTPSuccess
-			failedCounter = CounterHTTPFailed
+			counters = httpCheckCounters
 			resultCodeCounter = CounterHTTPStatusCode
 		default:
 			continue
 		}
 
-		// Total per interface
-		if ifStats.Total > 0 {
-			records = append(records, t.createRecord(totalCounter, ifStats.Total, 0, timestamp))
-		}
-
-		// Success per interface
-		if ifStats.Success > 0 {
-			records = append(records, t.createRecord(successCounter, ifStats.Success, 0, timestamp))
-		}
-
-		// Failed per interface
-		if ifStats.Failed > 0 {
-			records = append(records, t.createRecord(failedCounter, ifStats.Failed, 0, timestamp))
-		}
+		// Total, success and failed per interface
+		records = appendSection(t, records, counters, &ifStats, 0, timestamp)
 
 		// Result codes per interface (use code directly as integer)
 		for code, count := range ifStats.ByResultCode {
@@ -135,32 +364,26 @@ func (t *Transformer) transformEIRStats(eirStats *statsmodel.EIRStats, timestamp
 	}
 
 	// Cache statistics
-	if eirStats.CacheStats.Hits > 0 {
-		records = append(records, t.createRecord(CounterCacheHits, eirStats.CacheStats.Hits, 0, timestamp))
-	}
-	if eirStats.CacheStats.Misses > 0 {
-		records = append(records, t.createRecord(CounterCacheMisses, eirStats.CacheStats.Misses, 0, timestamp))
-	}
-	if eirStats.CacheStats.HitRate > 0 {
-		// Convert float64 to uint64 by multiplying by 100 (2 decimal precision)
-		records = append(records, t.createRecord(CounterCacheHitRate, uint64(eirStats.CacheStats.HitRate*100), 0, timestamp))
-	}
-	if eirStats.CacheStats.Size > 0 {
-		records = append(records, t.createRecord(CounterCacheSize, eirStats.CacheStats.Size, 0, timestamp))
-	}
+	records = appendSection(t, records, cacheCounters, &eirStats.CacheStats, 0, timestamp)
 
 	// Database operations
-	if eirStats.DatabaseOps.Queries > 0 {
-		records = append(records, t.createRecord(CounterDBQueries, eirStats.DatabaseOps.Queries, 0, timestamp))
-	}
-	if eirStats.DatabaseOps.Inserts > 0 {
-		records = append(records, t.createRecord(CounterDBInserts, eirStats.DatabaseOps.Inserts, 0, timestamp))
-	}
-	if eirStats.DatabaseOps.Updates > 0 {
-		records = append(records, t.createRecord(CounterDBUpdates, eirStats.DatabaseOps.Updates, 0, timestamp))
+	records = appendSection(t, records, dbCounters, &eirStats.DatabaseOps, 0, timestamp)
+	for op, latency := range eirStats.DatabaseOps.ByOperation {
+		if code, ok := DBOperationCauseCodes[op]; ok {
+			records = append(records, t.transformLatency(latency, code, CounterDBOpAvgLatencyMs, timestamp)...)
+		}
 	}
-	if eirStats.DatabaseOps.Deletes > 0 {
-		records = append(records, t.createRecord(CounterDBDeletes, eirStats.DatabaseOps.Deletes, 0, timestamp))
+	for table, tableStats := range eirStats.DatabaseOps.ByTable {
+		if tableStats.Operations == 0 {
+			continue
+		}
+		code := DBTableCauseCodes.Code(table)
+		records = append(records, t.createRecord(CounterDBTableOperations, tableStats.Operations, code, timestamp))
+		if tableStats.Errors > 0 {
+			records = append(records, t.createRecord(CounterDBTableErrors, tableStats.Errors, code, timestamp))
+		}
+		records = append(records, t.createRecord(CounterDBTableAvgLatencyMs, uint64(tableStats.AvgLatencyMs*100), code, timestamp))
+		records = append(records, t.createRecord(CounterDBTableMaxLatencyMs, uint64(tableStats.MaxLatencyMs*100), code, timestamp))
 	}
 
 	// Equipment status distribution
@@ -174,9 +397,46 @@ func (t *Transformer) transformEIRStats(eirStats *statsmodel.EIRStats, timestamp
 		records = append(records, t.createRecord(CounterGreylisted, count, 0, timestamp))
 	}
 
+	// Equipment status transitions
+	for transition, count := range eirStats.StatusTransitions {
+		if counterID, ok := StatusTransitionCounters[transition]; ok && count > 0 {
+			records = append(records, t.createRecord(counterID, count, 0, timestamp))
+		}
+	}
+
+	// TAC analytics (use TAC directly as integer cause code)
+	for tac, tacStats := range eirStats.ByTAC {
+		if tacStats.Checks == 0 {
+			continue
+		}
+		code, ok := t.causeCodes.code(tac)
+		if !ok {
+			continue
+		}
+
+		records = append(records, t.createRecord(CounterTACChecks, tacStats.Checks, code, timestamp))
+		if count := tacStats.ByStatus["whitelisted"]; count > 0 {
+			records = append(records, t.createRecord(CounterTACWhitelisted, count, code, timestamp))
+		}
+		if count := tacStats.ByStatus["blacklisted"]; count > 0 {
+			records = append(records, t.createRecord(CounterTACBlacklisted, count, code, timestamp))
+		}
+		if count := tacStats.ByStatus["greylisted"]; count > 0 {
+			records = append(records, t.createRecord(CounterTACGreylisted, count, code, timestamp))
+		}
+	}
+
 	return records
 }
 
+// now returns the current time from the configured clock
+func (t *Transformer) now() time.Time {
+	if t.config.Clock != nil {
+		return t.config.Clock.Now()
+	}
+	return statsmodel.SystemClock.Now()
+}
+
 // createRecord creates a MetricRecord with proper timestamp handling
 func (t *Transformer) createRecord(counterID int, value uint64, causeCode int, timestamp time.Time) MetricRecord {
 	return MetricRecord{
@@ -195,7 +455,8 @@ func (t *Transformer) filterRecords(records []MetricRecord) []MetricRecord {
 		return records
 	}
 
-	filtered := make([]MetricRecord, 0, len(records))
+	// Filter in place; records is owned by this cycle
+	filtered := records[:0]
 
 	for _, record := range records {
 		// Check if counter is in exclude list
@@ -1,9 +1,11 @@
 package export
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/hsdfat/telco/config"
 	statsmodel "github.com/hsdfat/telco/stats"
 )
 
@@ -405,3 +407,604 @@ func TestTransformer_ZeroValueFiltering(t *testing.T) {
 
 	t.Logf("Total records exported: %d (filtered zero-value counters)", len(records))
 }
+
+// TestTransformer_PerSourceLatency tests that per-source and per-operation latency
+// is exported with the source/operation in the cause code
+func TestTransformer_PerSourceLatency(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	stats := &statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Performance: statsmodel.PerformanceStats{
+			BySource: map[string]statsmodel.LatencyStats{
+				"diameter": {Count: 10, AvgLatencyMs: 40, MaxLatencyMs: 120, P50LatencyMs: 35, P95LatencyMs: 90, P99LatencyMs: 110},
+				"http":     {Count: 10, AvgLatencyMs: 2.5, MaxLatencyMs: 5, P50LatencyMs: 2, P95LatencyMs: 4, P99LatencyMs: 5},
+				"unknown":  {Count: 10, AvgLatencyMs: 1},
+			},
+			ByOperation: map[string]statsmodel.LatencyStats{
+				"check":     {Count: 5, AvgLatencyMs: 20, P99LatencyMs: 100},
+				"provision": {Count: 0},
+			},
+		},
+	}
+
+	records := transformer.Transform(stats)
+
+	find := func(counterID, causeCode int) (MetricRecord, bool) {
+		for _, r := range records {
+			if r.CounterID == counterID && r.CauseCode == causeCode {
+				return r, true
+			}
+		}
+		return MetricRecord{}, false
+	}
+
+	if r, ok := find(CounterSourceAvgLatencyMs, SourceCauseCodes["diameter"]); !ok || r.Value != 4000 {
+		t.Errorf("Expected diameter avg latency 4000, got %+v (found=%v)", r, ok)
+	}
+	if r, ok := find(CounterSourceP95LatencyMs, SourceCauseCodes["diameter"]); !ok || r.Value != 9000 {
+		t.Errorf("Expected diameter p95 latency 9000, got %+v (found=%v)", r, ok)
+	}
+	if r, ok := find(CounterSourceAvgLatencyMs, SourceCauseCodes["http"]); !ok || r.Value != 250 {
+		t.Errorf("Expected http avg latency 250, got %+v (found=%v)", r, ok)
+	}
+	if r, ok := find(CounterOperationP99LatencyMs, OperationCauseCodes["check"]); !ok || r.Value != 10000 {
+		t.Errorf("Expected check p99 latency 10000, got %+v (found=%v)", r, ok)
+	}
+	if _, ok := find(CounterOperationAvgLatencyMs, OperationCauseCodes["provision"]); ok {
+		t.Error("Operation without samples should not be exported")
+	}
+
+	sourceRecords := 0
+	for _, r := range records {
+		if r.CounterID >= CounterSourceAvgLatencyMs && r.CounterID <= CounterSourceP99LatencyMs {
+			sourceRecords++
+		}
+	}
+	if sourceRecords != 10 {
+		t.Errorf("Expected 10 per-source latency records (unknown source skipped), got %d", sourceRecords)
+	}
+}
+
+// TestTransformer_SourceBytes tests byte counters and message size histograms per source
+func TestTransformer_SourceBytes(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	stats := &statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Requests: statsmodel.RequestStats{
+			BytesSent: 3000,
+			BytesRecv: 1500,
+			BySource: map[string]statsmodel.SourceStats{
+				"diameter": {
+					BytesSent: 2000,
+					BytesRecv: 1000,
+					SentSizes: map[int]uint64{256: 4, statsmodel.OverflowSizeBucket: 1},
+					RecvSizes: map[int]uint64{128: 5},
+				},
+				"http": {BytesSent: 1000, BytesRecv: 500},
+			},
+		},
+	}
+
+	records := transformer.Transform(stats)
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	expected := map[[2]int]uint64{
+		{CounterBytesSent, 0}:                                    3000,
+		{CounterBytesRecv, 0}:                                    1500,
+		{CounterDiameterBytesSent, 0}:                            2000,
+		{CounterDiameterBytesRecv, 0}:                            1000,
+		{CounterDiameterSentSize, 256}:                           4,
+		{CounterDiameterSentSize, statsmodel.OverflowSizeBucket}: 1,
+		{CounterDiameterRecvSize, 128}:                           5,
+		{CounterHTTPBytesSent, 0}:                                1000,
+		{CounterHTTPBytesRecv, 0}:                                500,
+	}
+
+	for key, want := range expected {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("Counter %d cause %d: expected %d, got %d (found=%v)", key[0], key[1], want, got, ok)
+		}
+	}
+}
+
+// TestTransformer_RuntimeStats tests that the optional runtime section is exported as gauges
+func TestTransformer_RuntimeStats(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{Timestamp: time.Now()})
+	for _, r := range records {
+		if r.CounterID >= CounterGoroutines && r.CounterID <= CounterCPUPercent {
+			t.Fatalf("Runtime counter %d exported without runtime section", r.CounterID)
+		}
+	}
+
+	records = transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Runtime: &statsmodel.GoRuntimeStats{
+			Goroutines:     42,
+			HeapInUseBytes: 1 << 20,
+			GCPauseP99Ms:   1.25,
+			CPUPercent:     0,
+		},
+	})
+
+	values := make(map[int]uint64)
+	for _, r := range records {
+		values[r.CounterID] = r.Value
+	}
+
+	if values[CounterGoroutines] != 42 {
+		t.Errorf("Expected goroutines 42, got %d", values[CounterGoroutines])
+	}
+	if values[CounterGCPauseP99Ms] != 125 {
+		t.Errorf("Expected GC pause p99 125, got %d", values[CounterGCPauseP99Ms])
+	}
+	if _, ok := values[CounterCPUPercent]; !ok {
+		t.Error("CPU percent gauge should be exported even when zero")
+	}
+	if _, ok := values[CounterGCCount]; ok {
+		t.Error("GC count should not be exported when zero")
+	}
+}
+
+// TestTransformer_StatusTransitions tests that status transitions map to dedicated counters
+func TestTransformer_StatusTransitions(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		CustomMetrics: map[string]interface{}{
+			"eir": &statsmodel.EIRStats{
+				StatusTransitions: map[string]uint64{
+					statsmodel.TransitionKey("whitelisted", "blacklisted"): 3,
+					statsmodel.TransitionKey("greylisted", "whitelisted"):  1,
+					statsmodel.TransitionKey("blacklisted", "greylisted"):  0,
+					"unknown->whitelisted":                                 7,
+				},
+			},
+		},
+	})
+
+	values := make(map[int]uint64)
+	for _, r := range records {
+		values[r.CounterID] = r.Value
+	}
+
+	if values[CounterWhiteToBlack] != 3 {
+		t.Errorf("Expected white->black = 3, got %d", values[CounterWhiteToBlack])
+	}
+	if values[CounterGreyToWhite] != 1 {
+		t.Errorf("Expected grey->white = 1, got %d", values[CounterGreyToWhite])
+	}
+	if _, ok := values[CounterBlackToGrey]; ok {
+		t.Error("Zero transition count should not be exported")
+	}
+}
+
+// TestTransformer_TACStats tests that TAC analytics are exported with the TAC as cause code
+func TestTransformer_TACStats(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		CustomMetrics: map[string]interface{}{
+			"eir": &statsmodel.EIRStats{
+				ByTAC: map[string]statsmodel.TACStats{
+					"35693803": {Checks: 10, ByStatus: map[string]uint64{"whitelisted": 8, "blacklisted": 2}},
+					"ABCDEFGH": {Checks: 5},
+				},
+			},
+		},
+	})
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	if values[[2]int{CounterTACChecks, 35693803}] != 10 {
+		t.Errorf("Expected 10 checks for TAC 35693803, got %d", values[[2]int{CounterTACChecks, 35693803}])
+	}
+	if values[[2]int{CounterTACBlacklisted, 35693803}] != 2 {
+		t.Errorf("Expected 2 blacklisted for TAC 35693803, got %d", values[[2]int{CounterTACBlacklisted, 35693803}])
+	}
+	if _, ok := values[[2]int{CounterTACGreylisted, 35693803}]; ok {
+		t.Error("Zero greylisted count should not be exported")
+	}
+
+	tacRecords := 0
+	for _, r := range records {
+		if r.CounterID == CounterTACChecks {
+			tacRecords++
+		}
+	}
+	if tacRecords != 1 {
+		t.Errorf("Expected non-numeric TAC to be skipped, got %d TAC check records", tacRecords)
+	}
+}
+
+// TestTransformer_DatabaseBreakdown tests database latency and per-table export
+func TestTransformer_DatabaseBreakdown(t *testing.T) {
+	if err := DBTableCauseCodes.Register("equipment", 7); err != nil {
+		t.Fatal(err)
+	}
+	defer DBTableCauseCodes.Unregister("equipment")
+	defer DBTableCauseCodes.Unregister("audit_log")
+
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		CustomMetrics: map[string]interface{}{
+			"eir": &statsmodel.EIRStats{
+				DatabaseOps: statsmodel.DatabaseOperationStats{
+					Queries:      20,
+					Errors:       2,
+					AvgLatencyMs: 3.5,
+					ByOperation: map[string]statsmodel.LatencyStats{
+						"query": {Count: 20, AvgLatencyMs: 3.5, P95LatencyMs: 12},
+					},
+					ByTable: map[string]statsmodel.DBBreakdownStats{
+						"equipment": {Operations: 20, Errors: 2, AvgLatencyMs: 3.5, MaxLatencyMs: 40},
+						"audit_log": {Operations: 5},
+					},
+				},
+			},
+		},
+	})
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	expected := map[[2]int]uint64{
+		{CounterDBErrors, 0}:                                      2,
+		{CounterDBAvgLatencyMs, 0}:                                350,
+		{CounterDBOpP95LatencyMs, DBOperationCauseCodes["query"]}: 1200,
+		{CounterDBTableOperations, 7}:                             20,
+		{CounterDBTableErrors, 7}:                                 2,
+		{CounterDBTableMaxLatencyMs, 7}:                           4000,
+	}
+	for key, want := range expected {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("Counter %d cause %d: expected %d, got %d (found=%v)", key[0], key[1], want, got, ok)
+		}
+	}
+
+	code := DBTableCauseCodes.Codes()["audit_log"]
+	if got := values[[2]int{CounterDBTableOperations, code}]; code == 0 || got != 5 {
+		t.Errorf("Expected the unregistered table under a new code, got %d operations on code %d", got, code)
+	}
+}
+
+// TestTransformer_InFlight tests pending watermark and per-source in-flight gauges
+func TestTransformer_InFlight(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Requests: statsmodel.RequestStats{
+			Pending:    3,
+			MaxPending: 12,
+			BySource: map[string]statsmodel.SourceStats{
+				"diameter": {InFlight: 3},
+				"http":     {InFlight: 0},
+			},
+		},
+	})
+
+	values := make(map[int]uint64)
+	found := make(map[int]bool)
+	for _, r := range records {
+		values[r.CounterID] = r.Value
+		found[r.CounterID] = true
+	}
+
+	if values[CounterMaxPendingRequests] != 12 {
+		t.Errorf("Expected max pending 12, got %d", values[CounterMaxPendingRequests])
+	}
+	if values[CounterDiameterInFlight] != 3 {
+		t.Errorf("Expected diameter in-flight 3, got %d", values[CounterDiameterInFlight])
+	}
+	if !found[CounterHTTPInFlight] {
+		t.Error("HTTP in-flight gauge should be exported even when zero")
+	}
+}
+
+// TestTransformer_Uptime tests the uptime gauge is exported only when set
+func TestTransformer_Uptime(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{Timestamp: time.Now(), UptimeSeconds: 3600})
+	found := false
+	for _, r := range records {
+		if r.CounterID == CounterUptimeSeconds {
+			found = true
+			if r.Value != 3600 {
+				t.Errorf("Expected uptime 3600, got %d", r.Value)
+			}
+		}
+	}
+	if !found {
+		t.Error("Uptime gauge should be exported when set")
+	}
+
+	for _, r := range transformer.Transform(&statsmodel.ServiceStats{Timestamp: time.Now()}) {
+		if r.CounterID == CounterUptimeSeconds {
+			t.Error("Uptime gauge should not be exported when unknown")
+		}
+	}
+}
+
+// TestTransformer_ListenerStats tests per-listener connection export
+func TestTransformer_ListenerStats(t *testing.T) {
+	if err := ListenerCauseCodes.Register("sctp://0.0.0.0:3868", 1); err != nil {
+		t.Fatal(err)
+	}
+	defer ListenerCauseCodes.Unregister("sctp://0.0.0.0:3868")
+	defer ListenerCauseCodes.Unregister("tcp://0.0.0.0:3868")
+
+	transformer := NewTransformer("test-host", "DIAM-GW")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Connections: statsmodel.ConnectionStats{
+			Active: 4,
+			Closed: 2,
+			ByListener: map[string]statsmodel.ListenerStats{
+				"sctp://0.0.0.0:3868": {Active: 3, Total: 5, Closed: 2},
+				"tcp://0.0.0.0:3868":  {Active: 1, Total: 1},
+			},
+		},
+	})
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	if values[[2]int{CounterClosedConnections, 0}] != 2 {
+		t.Errorf("Expected 2 closed connections, got %d", values[[2]int{CounterClosedConnections, 0}])
+	}
+	if values[[2]int{CounterListenerActive, 1}] != 3 {
+		t.Errorf("Expected 3 active on SCTP listener, got %d", values[[2]int{CounterListenerActive, 1}])
+	}
+	if values[[2]int{CounterListenerTotal, 1}] != 5 {
+		t.Errorf("Expected 5 total on SCTP listener, got %d", values[[2]int{CounterListenerTotal, 1}])
+	}
+	if _, ok := values[[2]int{CounterListenerFailed, 1}]; ok {
+		t.Error("Zero listener failures should not be exported")
+	}
+	// Unregistered listeners are assigned a code rather than dropped
+	code := ListenerCauseCodes.Codes()["tcp://0.0.0.0:3868"]
+	if code == 0 || code == 1 {
+		t.Fatalf("Expected a new code for the unregistered listener, got %d", code)
+	}
+	if values[[2]int{CounterListenerActive, code}] != 1 {
+		t.Errorf("Expected 1 active on TCP listener, got %d", values[[2]int{CounterListenerActive, code}])
+	}
+}
+
+// TestTransformer_PeerStats tests per-peer Diameter export
+func TestTransformer_PeerStats(t *testing.T) {
+	if err := PeerCauseCodes.Register("hss.roaming.example", 5); err != nil {
+		t.Fatal(err)
+	}
+	defer PeerCauseCodes.Unregister("hss.roaming.example")
+	defer PeerCauseCodes.Unregister("unregistered.peer")
+
+	transformer := NewTransformer("test-host", "DIAM-GW")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Peers: map[string]statsmodel.PeerStats{
+			"hss.roaming.example": {State: statsmodel.PeerStateOpen, UptimeSeconds: 120, MessagesSent: 10, Disconnects: 1},
+			"unregistered.peer":   {State: statsmodel.PeerStateClosed, DWRFailures: 3},
+		},
+	})
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	expected := map[[2]int]uint64{
+		{CounterPeerUp, 5}:            1,
+		{CounterPeerUptimeSeconds, 5}: 120,
+		{CounterPeerMessagesSent, 5}:  10,
+		{CounterPeerDisconnects, 5}:   1,
+	}
+	for key, want := range expected {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("Counter %d: expected %d, got %d (found=%v)", key[0], want, got, ok)
+		}
+	}
+	code := PeerCauseCodes.Codes()["unregistered.peer"]
+	if got := values[[2]int{CounterPeerDWRFailures, code}]; code == 0 || got != 3 {
+		t.Errorf("Expected the unregistered peer under a new code, got %d DWR failures on code %d", got, code)
+	}
+}
+
+// TestTransformer_ConfigProviderStats tests per-provider config health export
+func TestTransformer_ConfigProviderStats(t *testing.T) {
+	if err := ConfigProviderCauseCodes.Register("consul(eir/config)", 3); err != nil {
+		t.Fatal(err)
+	}
+	defer ConfigProviderCauseCodes.Unregister("consul(eir/config)")
+	defer ConfigProviderCauseCodes.Unregister("env(EIR_*)")
+
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		ConfigProviders: map[string]statsmodel.ConfigProviderStats{
+			"consul(eir/config)": {Up: false, LoadFailures: 2, LatencyMs: 5000, StalenessSeconds: 900},
+			"env(EIR_*)":         {Up: true, Loads: 1},
+		},
+	})
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	expected := map[[2]int]uint64{
+		{CounterConfigProviderUp, 3}:               0,
+		{CounterConfigProviderLoadFailures, 3}:     2,
+		{CounterConfigProviderLatencyMs, 3}:        5000,
+		{CounterConfigProviderStalenessSeconds, 3}: 900,
+	}
+	for key, want := range expected {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("Counter %d: expected %d, got %d (found=%v)", key[0], want, got, ok)
+		}
+	}
+	code := ConfigProviderCauseCodes.Codes()["env(EIR_*)"]
+	if got := values[[2]int{CounterConfigProviderUp, code}]; code == 0 || got != 1 {
+		t.Errorf("Expected the unregistered provider under a new code, got up=%d on code %d", got, code)
+	}
+}
+
+// staticConfigSource is a ConfigStatusSource reporting fixed provider statuses
+type staticConfigSource []config.ProviderStatus
+
+func (s staticConfigSource) ProviderStatus() []config.ProviderStatus { return s }
+
+// TestCollector_ConfigStatusSource tests provider statuses are converted in snapshots
+func TestCollector_ConfigStatusSource(t *testing.T) {
+	collector := statsmodel.NewCollector(statsmodel.CollectorConfig{ServiceName: "EIR"})
+	collector.SetConfigStatusSource(staticConfigSource{
+		{Name: "consul", Loads: 2, Failures: 1, LastError: errors.New("connection refused"), Latency: 1500 * time.Millisecond},
+		{Name: "file", Loads: 2, Staleness: 90 * time.Second},
+	})
+
+	providers := collector.Snapshot().ConfigProviders
+	if ps := providers["consul"]; ps.Up || ps.LoadFailures != 1 || ps.LastError != "connection refused" || ps.LatencyMs != 1500 {
+		t.Errorf("consul stats = %+v, want down with 1 failure", ps)
+	}
+	if ps := providers["file"]; !ps.Up || ps.Loads != 2 || ps.StalenessSeconds != 90 {
+		t.Errorf("file stats = %+v, want up with 2 loads", ps)
+	}
+}
+
+// TestTransformer_OverloadStats tests overload control export
+func TestTransformer_OverloadStats(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Overload: &statsmodel.OverloadStats{
+			LoadLevel: 0,
+			Rejected:  7,
+			ByInterface: map[string]statsmodel.InterfaceOverloadStats{
+				"http":    {Rejected: 7},
+				"unknown": {Throttled: 2},
+			},
+		},
+	})
+
+	values := make(map[[2]int]uint64)
+	for _, r := range records {
+		values[[2]int{r.CounterID, r.CauseCode}] = r.Value
+	}
+
+	if _, ok := values[[2]int{CounterOverloadLevel, 0}]; !ok {
+		t.Error("Load level gauge should be exported even when zero")
+	}
+	if values[[2]int{CounterOverloadRejected, 0}] != 7 {
+		t.Errorf("Expected 7 rejected, got %d", values[[2]int{CounterOverloadRejected, 0}])
+	}
+	if values[[2]int{CounterInterfaceOverloadRejected, SourceCauseCodes["http"]}] != 7 {
+		t.Error("Expected 7 rejected on the http interface")
+	}
+	if _, ok := values[[2]int{CounterThrottled, 0}]; ok {
+		t.Error("Zero throttled counter should not be exported")
+	}
+	for key := range values {
+		if key[0] == CounterInterfaceThrottled {
+			t.Error("Unknown interface should not be exported")
+		}
+	}
+}
+
+// TestTransformer_CapacityStats tests license/capacity gauges are always exported when present
+func TestTransformer_CapacityStats(t *testing.T) {
+	transformer := NewTransformer("test-host", "EIR")
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Capacity: &statsmodel.CapacityStats{
+			LicensedTPS:         5000,
+			PeakTPS:             1234.5,
+			LicensedSubscribers: 1000000,
+		},
+	})
+
+	values := make(map[int]uint64)
+	found := make(map[int]bool)
+	for _, r := range records {
+		values[r.CounterID] = r.Value
+		found[r.CounterID] = true
+	}
+
+	if values[CounterLicensedTPS] != 5000 {
+		t.Errorf("Expected licensed TPS 5000, got %d", values[CounterLicensedTPS])
+	}
+	if values[CounterPeakTPS] != 123450 {
+		t.Errorf("Expected peak TPS 123450 (x100), got %d", values[CounterPeakTPS])
+	}
+	if !found[CounterProvisionedSubscribers] {
+		t.Error("Provisioned subscribers gauge should be exported even when zero")
+	}
+}
+
+// TestTransformer_Scaling tests per-counter scaling rules and the scaled metadata units
+func TestTransformer_Scaling(t *testing.T) {
+	transformer := NewTransformerWithConfig("test-host", "EIR", TransformerConfig{
+		SampleRate: 1.0,
+		Scaling: map[int]ScalingRule{
+			CounterBytesSent:    ScaleBytesToKB,
+			CounterAvgLatencyMs: ScaleMsToSeconds,
+		},
+	})
+
+	records := transformer.Transform(&statsmodel.ServiceStats{
+		Timestamp: time.Now(),
+		Requests: statsmodel.RequestStats{
+			Total:     10,
+			BytesSent: 5120,
+		},
+		Performance: statsmodel.PerformanceStats{
+			AvgLatencyMs: 2500, // 250000 hundredths of a ms
+		},
+	})
+
+	values := make(map[int]uint64)
+	for _, r := range records {
+		values[r.CounterID] = r.Value
+	}
+	if values[CounterBytesSent] != 5 {
+		t.Errorf("Expected 5 KB sent, got %d", values[CounterBytesSent])
+	}
+	if values[CounterAvgLatencyMs] != 250 {
+		t.Errorf("Expected 250 hundredths of a second, got %d", values[CounterAvgLatencyMs])
+	}
+	if values[CounterTotalRequests] != 10 {
+		t.Errorf("Expected unscaled total 10, got %d", values[CounterTotalRequests])
+	}
+
+	units := make(map[int]string)
+	for _, m := range transformer.CounterMetadata() {
+		units[m.ID] = m.Unit
+	}
+	if units[CounterBytesSent] != "kilobytes" || units[CounterAvgLatencyMs] != "seconds" || units[CounterTotalRequests] != "count" {
+		t.Errorf("Unexpected scaled units: %v %v %v", units[CounterBytesSent], units[CounterAvgLatencyMs], units[CounterTotalRequests])
+	}
+}
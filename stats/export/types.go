@@ -1,15 +1,25 @@
 package export
 
-import "time"
+import (
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
 
 // MetricRecord represents a single metric data point to be exported
 type MetricRecord struct {
 	CounterID  int       `json:"counter_id"`  // Unique identifier for the metric type
 	Value      uint64   `json:"value"`       // The numeric value of the metric
 	CauseCode  int       `json:"cause_code,omitempty"` // Result/status/error code (0 = no code)
+	Tenant     string    `json:"tenant,omitempty"`     // Tenant or network slice of per-tenant records (empty = all tenants)
 	Hostname   string    `json:"hostname"`    // The host generating the metric
 	SystemName string    `json:"system_name"` // Service/system name (e.g., "EIR", "DIAM-GW")
 	Timestamp  time.Time `json:"timestamp"`   // When the metric was recorded
+
+	PeriodStart   time.Time `json:"period_start"`             // Start of the interval the value covers, in the scheduler's period zone
+	PeriodEnd     time.Time `json:"period_end"`
+	FirstInterval bool      `json:"first_interval,omitempty"` // Value covers everything since start, not one interval
+	ClockSkewed   bool      `json:"clock_skewed,omitempty"`   // The wall clock stepped since the previous cycle, so the period may be shifted
 }
 
 // ExportConfig defines configuration for the metrics export system
@@ -19,14 +29,38 @@ type ExportConfig struct {
 	Hostname   string            `json:"hostname" yaml:"hostname"`         // Auto-detect if empty
 	SystemName string            `json:"system_name" yaml:"system_name"`   // Default: service name
 	Exporters  []ExporterConfig  `json:"exporters" yaml:"exporters"`
+
+	ExportTimeout time.Duration `json:"export_timeout" yaml:"export_timeout"` // Per-exporter default (default: 30s)
+	CycleBudget   time.Duration `json:"cycle_budget" yaml:"cycle_budget"`     // Whole cycle limit (0 = unbounded)
+
+	FirstCycle   string `json:"first_cycle" yaml:"first_cycle"`     // "export" (default), "suppress" or "flag"
+	SnapshotFile string `json:"snapshot_file" yaml:"snapshot_file"` // Persists the delta snapshot across restarts
+	SequenceFile string `json:"sequence_file" yaml:"sequence_file"` // Persists the batch sequence across restarts
+
+	PeriodTimezone string `json:"period_timezone" yaml:"period_timezone"` // IANA zone for PeriodStart/PeriodEnd (default: local)
+	PeriodAlign    bool   `json:"period_align" yaml:"period_align"`       // Snap periods to interval boundaries of the local day
+
+	ClockSkewThreshold time.Duration `json:"clock_skew_threshold" yaml:"clock_skew_threshold"` // Wall clock step that flags a cycle (default: 1s, negative disables)
+
+	DedupWindow time.Duration `json:"dedup_window" yaml:"dedup_window"` // Suppresses identical records within a period of this length (0 = disabled)
+
+	MaxRecordsPerCycle int `json:"max_records_per_cycle" yaml:"max_records_per_cycle"` // Truncates larger cycles, see SetMaxRecordsPerCycle (0 = unlimited)
+
+	DryRun       bool   `json:"dry_run" yaml:"dry_run"`               // Log each cycle's records instead of exporting them
+	DryRunFormat string `json:"dry_run_format" yaml:"dry_run_format"` // "log" (default) or "table", see LogExporter
 }
 
 // ExporterConfig defines configuration for a single exporter
 type ExporterConfig struct {
-	Type    string                 `json:"type" yaml:"type"`       // "http", "postgres", "file"
+	Type    string                 `json:"type" yaml:"type"`       // "http", "postgres", "file", "push"
 	Name    string                 `json:"name" yaml:"name"`
 	Enabled bool                   `json:"enabled" yaml:"enabled"`
 	Config  map[string]interface{} `json:"config" yaml:"config"`
+
+	ExportTimeout time.Duration `json:"export_timeout" yaml:"export_timeout"` // Overrides ExportConfig.ExportTimeout
+	Heartbeat     bool          `json:"heartbeat" yaml:"heartbeat"`           // Adds a CounterHeartbeat record every cycle, see SetExporterHeartbeat
+
+	MetadataHandshake bool `json:"metadata_handshake" yaml:"metadata_handshake"` // Sends the counter catalog on start and reconnect, see SetExporterHandshake
 }
 
 // HTTPExporterConfig defines configuration for HTTP exporter
@@ -37,6 +71,11 @@ type HTTPExporterConfig struct {
 	Timeout      time.Duration     `json:"timeout"`
 	RetryDelay   time.Duration     `json:"retry_delay"`
 	RetryAttempts int              `json:"retry_attempts"`
+	CatalogURL   string            `json:"catalog_url"` // Receives the counter catalog at startup (optional)
+	ChunkSize    int               `json:"chunk_size"`  // Max records per request (0 = one request per cycle)
+	Parallelism  int               `json:"parallelism"` // Concurrent chunk requests (default: 4)
+	TLS          *TLSSettings      `json:"tls"`         // Client TLS for https URLs (optional)
+	Signing      *SigningConfig    `json:"signing"`     // Signs each request body into X-Export-Signature headers (optional)
 }
 
 // PostgresExporterConfig defines configuration for PostgreSQL exporter
@@ -46,6 +85,7 @@ type PostgresExporterConfig struct {
 	TableName        string `json:"table_name"`
 	BatchSize        int    `json:"batch_size"`
 	MaxRetry         int    `json:"max_retry"`
+	TLS              *TLSSettings `json:"tls"` // Applied as sslmode/sslrootcert/sslcert/sslkey (optional)
 }
 
 // FileExporterConfig defines configuration for file exporter
@@ -55,6 +95,13 @@ type FileExporterConfig struct {
 	MaxSizeMB   int    `json:"max_size_mb"`
 	MaxBackups  int    `json:"max_backups"`
 	Compress    bool   `json:"compress"`
+	Signing     *SigningConfig `json:"signing"` // Appends a signature line after each batch (optional)
+
+	// Codec compresses the active file too, one member per batch: "gzip" or a name
+	// registered with RegisterFileCodec ("" = uncompressed). Its extension is
+	// appended to Path if missing, and Compress is ignored
+	Codec string `json:"codec"`
+	Level int    `json:"level"` // Codec compression level (0 = codec default)
 }
 
 // TransformerConfig defines configuration for metric transformation
@@ -62,6 +109,23 @@ type TransformerConfig struct {
 	IncludeCounters []int   // Only export these counter IDs (empty = all)
 	ExcludeCounters []int   // Don't export these counter IDs
 	SampleRate      float64 // For high-volume metrics (0.0-1.0)
+
+	// Clock timestamps records from stats that carry no Timestamp (default: SystemClock)
+	Clock statsmodel.Clock
+
+	// Scaling converts the values of the given counter IDs, e.g. ms to seconds
+	Scaling map[int]ScalingRule
+
+	// HealthScorer, if set, scores each cycle's stats and exports the overall and
+	// per-subsystem scores as gauges (CounterHealthScore, CounterSubsystemHealthScore)
+	HealthScorer *statsmodel.HealthScorer
+}
+
+// ScalingRule multiplies a counter's values by Factor (rounded to the nearest integer)
+// and reports them in Unit
+type ScalingRule struct {
+	Factor float64 `json:"factor" yaml:"factor"`
+	Unit   string  `json:"unit" yaml:"unit"` // Unit after scaling, reported in CounterMetadata
 }
 
 // AggregatedMetricRecord for windowed metrics
@@ -70,4 +134,5 @@ type AggregatedMetricRecord struct {
 	WindowStart time.Time `json:"window_start"`
 	WindowEnd   time.Time `json:"window_end"`
 	SampleCount int       `json:"sample_count"` // Number of samples aggregated
+	Max         uint64    `json:"max,omitempty"` // Highest sample, for gauges (Value is the average)
 }
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// First cycle modes, for the export cycle that has no previous snapshot to diff against
+const (
+	FirstCycleExport   = "export"   // Export absolute totals as the first delta (default)
+	FirstCycleSuppress = "suppress" // Export nothing; the cycle only seeds the snapshot
+	FirstCycleFlag     = "flag"     // Export with FirstInterval set on every record
+)
+
+// SnapshotStore persists the scheduler's delta snapshot so a restarted scheduler
+// diffs against the last exported totals instead of treating them as one delta
+type SnapshotStore interface {
+	// Load returns the persisted snapshot, or nil if there is none
+	Load() (*statsmodel.ServiceStats, error)
+
+	// Save persists the snapshot after each export cycle
+	Save(stats *statsmodel.ServiceStats) error
+}
+
+// FileSnapshotStore keeps the snapshot as a JSON file
+type FileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore creates a snapshot store at path
+func NewFileSnapshotStore(path string) *FileSnapshotStore {
+	return &FileSnapshotStore{path: path}
+}
+
+// Load reads the snapshot, returning nil if the file does not exist
+func (f *FileSnapshotStore) Load() (*statsmodel.ServiceStats, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return statsmodel.UnmarshalServiceStats(data)
+}
+
+// Save writes the snapshot atomically
+func (f *FileSnapshotStore) Save(stats *statsmodel.ServiceStats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// SetFirstCycleMode sets how the first export cycle after start is handled
+func (s *ExportScheduler) SetFirstCycleMode(mode string) error {
+	switch mode {
+	case "":
+		mode = FirstCycleExport
+	case FirstCycleExport, FirstCycleSuppress, FirstCycleFlag:
+	default:
+		return fmt.Errorf("unknown first cycle mode: %s", mode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.firstCycle = mode
+	return nil
+}
+
+// SetSnapshotStore persists the delta snapshot in store; Start seeds the snapshot
+// from it, so only a scheduler without a persisted snapshot has a first cycle
+func (s *ExportScheduler) SetSnapshotStore(store SnapshotStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshotStore = store
+}
+
+// SeedSnapshot sets the snapshot the next cycle's delta is calculated against
+func (s *ExportScheduler) SeedSnapshot(stats *statsmodel.ServiceStats) {
+	s.updatePreviousSnapshot(stats)
+}
+
+// ApplyFirstCycle applies the first cycle mode and snapshot file from config
+func (s *ExportScheduler) ApplyFirstCycle(config *ExportConfig) error {
+	if err := s.SetFirstCycleMode(config.FirstCycle); err != nil {
+		return err
+	}
+	if config.SnapshotFile != "" {
+		s.SetSnapshotStore(NewFileSnapshotStore(config.SnapshotFile))
+	}
+	return nil
+}
+
+// loadSnapshot seeds the snapshot from the store, if any
+func (s *ExportScheduler) loadSnapshot() {
+	s.mu.RLock()
+	store := s.snapshotStore
+	s.mu.RUnlock()
+	if store == nil {
+		return
+	}
+
+	stats, err := store.Load()
+	if err != nil {
+		s.logger.Warnw("Failed to load persisted snapshot", "error", err)
+		return
+	}
+	if stats != nil {
+		s.SeedSnapshot(stats)
+		s.logger.Infow("Seeded delta snapshot from persisted state", "timestamp", stats.Timestamp)
+	}
+}
+
+// saveSnapshot persists the snapshot to the store, if any
+func (s *ExportScheduler) saveSnapshot(stats *statsmodel.ServiceStats) {
+	s.mu.RLock()
+	store := s.snapshotStore
+	s.mu.RUnlock()
+	if store == nil {
+		return
+	}
+
+	if err := store.Save(stats); err != nil {
+		s.logger.Warnw("Failed to persist snapshot", "error", err)
+	}
+}
+
+// hasSnapshot reports whether a previous snapshot exists to diff against
+func (s *ExportScheduler) hasSnapshot() bool {
+	s.snapshotMutex.RLock()
+	defer s.snapshotMutex.RUnlock()
+	return s.prevSnapshot != nil
+}
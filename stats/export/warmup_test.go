@@ -0,0 +1,85 @@
+package export
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// TestFirstCycleMode tests the first cycle's absolute totals can be suppressed or flagged
+func TestFirstCycleMode(t *testing.T) {
+	var total uint64
+	provider := StatsFunc(func() *statsmodel.ServiceStats {
+		return &statsmodel.ServiceStats{Requests: statsmodel.RequestStats{Total: total}}
+	})
+	ctx := context.Background()
+
+	t.Run("suppress", func(t *testing.T) {
+		total = 100
+		h := NewSchedulerHarness(provider, HarnessConfig{})
+		if err := h.Scheduler.SetFirstCycleMode(FirstCycleSuppress); err != nil {
+			t.Fatal(err)
+		}
+		if records := h.Tick(ctx); records != nil {
+			t.Errorf("Expected the first cycle to be suppressed, got %d records", len(records))
+		}
+		total = 103
+		h.Tick(ctx)
+		if got, _ := h.Exporter.Last(CounterTotalRequests); got != 3 {
+			t.Errorf("Expected a delta of 3, got %d", got)
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		total = 100
+		h := NewSchedulerHarness(provider, HarnessConfig{})
+		h.Scheduler.SetFirstCycleMode(FirstCycleFlag)
+		for _, r := range h.Tick(ctx) {
+			if !r.FirstInterval {
+				t.Fatalf("Expected FirstInterval on first cycle record %d", r.CounterID)
+			}
+		}
+		total = 101
+		for _, r := range h.Tick(ctx) {
+			if r.FirstInterval {
+				t.Fatalf("Expected no FirstInterval on second cycle record %d", r.CounterID)
+			}
+		}
+	})
+
+	if err := NewSchedulerHarness(provider, HarnessConfig{}).Scheduler.SetFirstCycleMode("drop"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+// TestFileSnapshotStore tests a restarted scheduler diffs against the persisted snapshot
+func TestFileSnapshotStore(t *testing.T) {
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "state", "snapshot.json"))
+	total := uint64(500)
+	provider := StatsFunc(func() *statsmodel.ServiceStats {
+		return &statsmodel.ServiceStats{
+			Requests:      statsmodel.RequestStats{Total: total},
+			CustomMetrics: statsmodel.CustomMetrics{"eir": &statsmodel.EIRStats{EquipmentChecks: statsmodel.EquipmentCheckStats{Total: total}}},
+		}
+	})
+	ctx := context.Background()
+
+	before := NewSchedulerHarness(provider, HarnessConfig{})
+	before.Scheduler.SetSnapshotStore(store)
+	before.Tick(ctx)
+
+	total = 520
+	after := NewSchedulerHarness(provider, HarnessConfig{})
+	after.Scheduler.SetSnapshotStore(store)
+	after.Scheduler.loadSnapshot()
+	after.Tick(ctx)
+
+	if got, _ := after.Exporter.Last(CounterTotalRequests); got != 20 {
+		t.Errorf("Expected a delta of 20 against the persisted snapshot, got %d", got)
+	}
+	if prev, _ := store.Load(); prev == nil || prev.Requests.Total != 520 {
+		t.Errorf("Expected the latest snapshot to be persisted, got %+v", prev)
+	}
+}
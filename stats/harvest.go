@@ -0,0 +1,163 @@
+package stats
+
+import (
+	"encoding/json"
+	"expvar"
+	"runtime/metrics"
+	"strings"
+)
+
+// Default expvar variables left out by a MetricsHarvester: memstats is covered by
+// RuntimeSampler and cmdline isn't numeric
+var defaultSkippedExpvars = map[string]bool{"memstats": true, "cmdline": true}
+
+// HarvestedMetrics holds expvar and runtime/metrics values collected by a
+// MetricsHarvester, in CustomMetrics["runtime"]
+// Names are the expvar name (nested map keys joined with ".") or the runtime/metrics
+// name, e.g. "requests.diameter" or "/sched/goroutines:goroutines"
+type HarvestedMetrics struct {
+	Counters map[string]float64 `json:"counters,omitempty" stats:"counter"` // Cumulative values
+	Gauges   map[string]float64 `json:"gauges,omitempty" stats:"gauge"`
+}
+
+// MetricsHarvesterConfig selects the values a MetricsHarvester collects
+type MetricsHarvesterConfig struct {
+	// Expvars are the expvar variables to harvest ("*" = all but memstats and cmdline)
+	// Numeric values are harvested, maps and JSON objects are walked
+	Expvars []string
+
+	// ExpvarCounters are the harvested names with counter semantics, exact or as a
+	// prefix ending in "*" (e.g. "requests.*"); other expvar values are gauges
+	ExpvarCounters []string
+
+	// RuntimeMetrics are the runtime/metrics names to read ("*" = every scalar metric)
+	// Cumulative metrics are counters, the others gauges; histograms are skipped
+	RuntimeMetrics []string
+}
+
+// MetricsHarvester converts existing expvar and runtime/metrics instrumentation into
+// a CustomMetrics section on demand, so legacy services export it without rewriting
+// it; attach it with Collector.SetMetricsHarvester
+type MetricsHarvester struct {
+	config  MetricsHarvesterConfig
+	samples []metrics.Sample
+	counter map[string]bool // runtime/metrics names with cumulative values
+}
+
+// NewMetricsHarvester creates a harvester; unknown and histogram runtime/metrics
+// names are ignored
+func NewMetricsHarvester(cfg MetricsHarvesterConfig) *MetricsHarvester {
+	h := &MetricsHarvester{config: cfg, counter: make(map[string]bool)}
+
+	wanted := make(map[string]bool, len(cfg.RuntimeMetrics))
+	all := false
+	for _, name := range cfg.RuntimeMetrics {
+		wanted[name] = true
+		all = all || name == "*"
+	}
+	for _, desc := range metrics.All() {
+		if !all && !wanted[desc.Name] {
+			continue
+		}
+		if desc.Kind != metrics.KindUint64 && desc.Kind != metrics.KindFloat64 {
+			continue
+		}
+		h.samples = append(h.samples, metrics.Sample{Name: desc.Name})
+		h.counter[desc.Name] = desc.Cumulative
+	}
+	return h
+}
+
+// Harvest reads the configured values now
+func (h *MetricsHarvester) Harvest() *HarvestedMetrics {
+	result := &HarvestedMetrics{Counters: make(map[string]float64), Gauges: make(map[string]float64)}
+	h.harvestExpvars(result)
+	h.harvestRuntimeMetrics(result)
+	return result
+}
+
+// harvestExpvars adds the configured expvar values to result
+func (h *MetricsHarvester) harvestExpvars(result *HarvestedMetrics) {
+	if len(h.config.Expvars) == 0 {
+		return
+	}
+
+	all := false
+	wanted := make(map[string]bool, len(h.config.Expvars))
+	for _, name := range h.config.Expvars {
+		wanted[name] = true
+		all = all || name == "*"
+	}
+
+	expvar.Do(func(kv expvar.KeyValue) {
+		if all && defaultSkippedExpvars[kv.Key] || !all && !wanted[kv.Key] {
+			return
+		}
+		// Every expvar.Var renders as JSON, whatever its type
+		var value interface{}
+		if err := json.Unmarshal([]byte(kv.Value.String()), &value); err != nil {
+			return
+		}
+		h.addExpvar(result, kv.Key, value)
+	})
+}
+
+// addExpvar adds the numeric leaves of an expvar JSON value to result
+func (h *MetricsHarvester) addExpvar(result *HarvestedMetrics, name string, value interface{}) {
+	switch v := value.(type) {
+	case float64:
+		if h.isExpvarCounter(name) {
+			result.Counters[name] = v
+		} else {
+			result.Gauges[name] = v
+		}
+	case bool:
+		gauge := 0.0
+		if v {
+			gauge = 1
+		}
+		result.Gauges[name] = gauge
+	case map[string]interface{}:
+		for key, nested := range v {
+			h.addExpvar(result, name+"."+key, nested)
+		}
+	}
+}
+
+// isExpvarCounter reports whether an expvar name has counter semantics
+func (h *MetricsHarvester) isExpvarCounter(name string) bool {
+	for _, pattern := range h.config.ExpvarCounters {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(name, prefix) || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// harvestRuntimeMetrics adds the configured runtime/metrics values to result
+func (h *MetricsHarvester) harvestRuntimeMetrics(result *HarvestedMetrics) {
+	if len(h.samples) == 0 {
+		return
+	}
+
+	samples := make([]metrics.Sample, len(h.samples))
+	copy(samples, h.samples)
+	metrics.Read(samples)
+
+	for _, sample := range samples {
+		var value float64
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			value = float64(sample.Value.Uint64())
+		case metrics.KindFloat64:
+			value = sample.Value.Float64()
+		default:
+			continue
+		}
+		if h.counter[sample.Name] {
+			result.Counters[sample.Name] = value
+		} else {
+			result.Gauges[sample.Name] = value
+		}
+	}
+}
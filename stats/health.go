@@ -0,0 +1,282 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Health subsystems scored by HealthScorer
+const (
+	HealthConnections = "connections"
+	HealthRequests    = "requests"
+	HealthCache       = "cache"
+	HealthDatabase    = "database"
+)
+
+// Health statuses of HealthStatus and its checks
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// HealthThreshold scores a metric where higher is worse: 100 up to Warn, 0 from
+// Fail and linear in between
+// The zero value uses the metric's default; a negative Fail disables the metric
+type HealthThreshold struct {
+	Warn float64
+	Fail float64
+}
+
+// HealthScorerConfig configures how a ServiceStats snapshot is scored
+// Ratios are fractions (0.01 = 1%), latencies milliseconds
+type HealthScorerConfig struct {
+	// ConnectionFailureRatio is Connections.Failed / Connections.Total (default: 5%, 50%)
+	ConnectionFailureRatio HealthThreshold
+
+	// PeerDownRatio is the fraction of Diameter peers not open (default: 0%, 50%)
+	PeerDownRatio HealthThreshold
+
+	// RequestErrorRatio is Requests.Failed / Requests.Total (default: 1%, 10%)
+	RequestErrorRatio HealthThreshold
+
+	// LatencyP95Ms is Performance.P95LatencyMs (default: 100ms, 1000ms)
+	LatencyP95Ms HealthThreshold
+
+	// CacheMissRatio is the EIR cache misses / lookups (default: 20%, 80%)
+	CacheMissRatio HealthThreshold
+
+	// DBErrorRatio is the EIR database errors / operations (default: 1%, 10%)
+	DBErrorRatio HealthThreshold
+
+	// DBLatencyMs is the EIR database average latency (default: 50ms, 500ms)
+	DBLatencyMs HealthThreshold
+
+	// Weights of the subsystems in the overall score (default: 1 each)
+	Weights map[string]float64
+
+	// DegradedBelow is the score under which a subsystem warns and the service is
+	// degraded (default: 80)
+	DegradedBelow int
+
+	// UnhealthyBelow is the score under which a subsystem fails and the service is
+	// unhealthy (default: 50)
+	UnhealthyBelow int
+}
+
+// HealthScore is the machine-readable health of a service
+type HealthScore struct {
+	Score      int                        `json:"score"`                // Weighted average of the subsystem scores, 0-100
+	Subsystems map[string]SubsystemHealth `json:"subsystems,omitempty"` // Scored subsystems; idle ones are left out
+}
+
+// SubsystemHealth is the score of one subsystem with the reasons it isn't 100
+type SubsystemHealth struct {
+	Score   int      `json:"score"` // Score of the worst metric, 0-100
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// HealthScorer converts ServiceStats into a 0-100 score per subsystem
+// Ratios are computed from the counters it is given: score a period's stats (e.g.
+// Delta of two snapshots) rather than totals since start to judge current health
+type HealthScorer struct {
+	config HealthScorerConfig
+}
+
+// NewHealthScorer creates a scorer, applying defaults to unset thresholds
+func NewHealthScorer(config HealthScorerConfig) *HealthScorer {
+	config.ConnectionFailureRatio = config.ConnectionFailureRatio.withDefault(0.05, 0.5)
+	config.PeerDownRatio = config.PeerDownRatio.withDefault(0, 0.5)
+	config.RequestErrorRatio = config.RequestErrorRatio.withDefault(0.01, 0.1)
+	config.LatencyP95Ms = config.LatencyP95Ms.withDefault(100, 1000)
+	config.CacheMissRatio = config.CacheMissRatio.withDefault(0.2, 0.8)
+	config.DBErrorRatio = config.DBErrorRatio.withDefault(0.01, 0.1)
+	config.DBLatencyMs = config.DBLatencyMs.withDefault(50, 500)
+	if config.DegradedBelow <= 0 {
+		config.DegradedBelow = 80
+	}
+	if config.UnhealthyBelow <= 0 {
+		config.UnhealthyBelow = 50
+	}
+	return &HealthScorer{config: config}
+}
+
+// withDefault returns t, or the default thresholds when t is the zero value
+func (t HealthThreshold) withDefault(warn, fail float64) HealthThreshold {
+	if t == (HealthThreshold{}) {
+		return HealthThreshold{Warn: warn, Fail: fail}
+	}
+	return t
+}
+
+// score returns the 0-100 score of value
+func (t HealthThreshold) score(value float64) int {
+	switch {
+	case t.Fail < 0 || value <= t.Warn:
+		return 100
+	case value >= t.Fail:
+		return 0
+	default:
+		return int(math.Round(100 * (t.Fail - value) / (t.Fail - t.Warn)))
+	}
+}
+
+// subsystemScorer accumulates the metric scores of one subsystem
+type subsystemScorer struct {
+	health SubsystemHealth
+}
+
+// ratio scores a fraction, reported as a percentage
+func (s *subsystemScorer) ratio(name string, value float64, t HealthThreshold) {
+	s.metric(t.score(value), fmt.Sprintf("%s %.2f%% (warn %.2f%%, fail %.2f%%)", name, value*100, t.Warn*100, t.Fail*100))
+}
+
+// latency scores a latency in milliseconds
+func (s *subsystemScorer) latency(name string, ms float64, t HealthThreshold) {
+	s.metric(t.score(ms), fmt.Sprintf("%s %.1fms (warn %.1fms, fail %.1fms)", name, ms, t.Warn, t.Fail))
+}
+
+// metric lowers the subsystem score to score, recording reason when below 100
+func (s *subsystemScorer) metric(score int, reason string) {
+	if score < 100 {
+		s.health.Reasons = append(s.health.Reasons, reason)
+	}
+	s.health.Score = min(s.health.Score, score)
+}
+
+// Score scores stats; subsystems without activity are left out, and a service
+// with none scores 100
+func (h *HealthScorer) Score(stats *ServiceStats) HealthScore {
+	subsystems := make(map[string]SubsystemHealth)
+	score := func(name string, fn func(s *subsystemScorer)) {
+		s := &subsystemScorer{health: SubsystemHealth{Score: 100}}
+		fn(s)
+		subsystems[name] = s.health
+	}
+
+	conns := stats.Connections
+	if conns.Total > 0 || conns.Failed > 0 || len(stats.Peers) > 0 {
+		score(HealthConnections, func(s *subsystemScorer) {
+			if conns.Total > 0 || conns.Failed > 0 {
+				s.ratio("connection failure ratio", ratio(conns.Failed, max(conns.Total, conns.Failed)), h.config.ConnectionFailureRatio)
+			}
+			if len(stats.Peers) > 0 {
+				var down uint64
+				for _, peer := range stats.Peers {
+					if peer.State != PeerStateOpen {
+						down++
+					}
+				}
+				s.ratio("peers down", ratio(down, uint64(len(stats.Peers))), h.config.PeerDownRatio)
+			}
+		})
+	}
+
+	if reqs := stats.Requests; reqs.Total > 0 {
+		score(HealthRequests, func(s *subsystemScorer) {
+			s.ratio("request error ratio", ratio(reqs.Failed, reqs.Total), h.config.RequestErrorRatio)
+			if p95 := stats.Performance.P95LatencyMs; p95 > 0 {
+				s.latency("p95 latency", p95, h.config.LatencyP95Ms)
+			}
+		})
+	}
+
+	if eir, ok := stats.CustomMetrics.EIR(); ok {
+		cache := eir.CacheStats
+		if lookups := cache.Hits + cache.Misses; lookups > 0 {
+			score(HealthCache, func(s *subsystemScorer) {
+				s.ratio("cache miss ratio", ratio(cache.Misses, lookups), h.config.CacheMissRatio)
+			})
+		}
+
+		db := eir.DatabaseOps
+		if ops := db.Queries + db.Inserts + db.Updates + db.Deletes; ops > 0 {
+			score(HealthDatabase, func(s *subsystemScorer) {
+				s.ratio("database error ratio", ratio(db.Errors, ops), h.config.DBErrorRatio)
+				if db.AvgLatencyMs > 0 {
+					s.latency("database latency", db.AvgLatencyMs, h.config.DBLatencyMs)
+				}
+			})
+		}
+	}
+
+	result := HealthScore{Score: 100}
+	if len(subsystems) == 0 {
+		return result
+	}
+	result.Subsystems = subsystems
+
+	var sum, weights float64
+	for name, sub := range subsystems {
+		weight := 1.0
+		if w, ok := h.config.Weights[name]; ok {
+			weight = w
+		}
+		sum += weight * float64(sub.Score)
+		weights += weight
+	}
+	if weights > 0 {
+		result.Score = int(math.Round(sum / weights))
+	}
+	return result
+}
+
+// Health scores stats as a HealthStatus with one check per scored subsystem
+func (h *HealthScorer) Health(stats *ServiceStats) HealthStatus {
+	score := h.Score(stats)
+	status := HealthStatus{
+		Status:    h.serviceStatus(score.Score),
+		Score:     score.Score,
+		Timestamp: stats.Timestamp,
+		Checks:    make(map[string]Check, len(score.Subsystems)),
+	}
+	if status.Timestamp.IsZero() {
+		status.Timestamp = time.Now()
+	}
+
+	for name, sub := range score.Subsystems {
+		status.Checks[name] = Check{
+			Status:  h.checkStatus(sub.Score),
+			Score:   sub.Score,
+			Reasons: sub.Reasons,
+		}
+	}
+	return status
+}
+
+// serviceStatus maps an overall score to a HealthStatus status
+func (h *HealthScorer) serviceStatus(score int) string {
+	switch {
+	case score < h.config.UnhealthyBelow:
+		return HealthStatusUnhealthy
+	case score < h.config.DegradedBelow:
+		return HealthStatusDegraded
+	default:
+		return HealthStatusHealthy
+	}
+}
+
+// checkStatus maps a subsystem score to a Check status
+func (h *HealthScorer) checkStatus(score int) string {
+	switch {
+	case score < h.config.UnhealthyBelow:
+		return CheckFail
+	case score < h.config.DegradedBelow:
+		return CheckWarn
+	default:
+		return CheckPass
+	}
+}
+
+// ratio returns n / total, 0 when total is 0
+func ratio(n, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
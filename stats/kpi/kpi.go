@@ -0,0 +1,186 @@
+// Package kpi computes 3GPP-style key performance indicators, such as success ratios,
+// from exported metric records
+//
+// A Formula declares which counters are summed into the numerator and denominator;
+// an Evaluator applies a set of formulas to the records of each export period.
+package kpi
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hsdfat/telco/stats/export"
+)
+
+// Units for KPI values
+const (
+	UnitPercent = "percent"
+	UnitRatio   = "ratio"
+)
+
+// CounterRef selects records of one counter, optionally restricted to some cause codes
+type CounterRef struct {
+	CounterID  int   `json:"counter_id" yaml:"counter_id"`
+	CauseCodes []int `json:"cause_codes,omitempty" yaml:"cause_codes,omitempty"` // Empty matches every cause code
+}
+
+// Counter references every record of counterID
+func Counter(counterID int) CounterRef {
+	return CounterRef{CounterID: counterID}
+}
+
+// CounterWithCauses references the records of counterID carrying one of causeCodes
+func CounterWithCauses(counterID int, causeCodes ...int) CounterRef {
+	return CounterRef{CounterID: counterID, CauseCodes: causeCodes}
+}
+
+// matches reports whether record is selected by the reference
+func (r CounterRef) matches(record export.MetricRecord) bool {
+	if record.CounterID != r.CounterID {
+		return false
+	}
+	if len(r.CauseCodes) == 0 {
+		return true
+	}
+	for _, code := range r.CauseCodes {
+		if record.CauseCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Formula defines a KPI as sum(Numerator) / sum(Denominator)
+type Formula struct {
+	Name        string       `json:"name" yaml:"name"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Numerator   []CounterRef `json:"numerator" yaml:"numerator"`
+	Denominator []CounterRef `json:"denominator" yaml:"denominator"`
+	Unit        string       `json:"unit" yaml:"unit"` // UnitPercent scales the ratio by 100
+}
+
+// Validate checks the formula is well formed
+func (f Formula) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("kpi name is required")
+	}
+	if len(f.Numerator) == 0 {
+		return fmt.Errorf("kpi %s: numerator is required", f.Name)
+	}
+	if len(f.Denominator) == 0 {
+		return fmt.Errorf("kpi %s: denominator is required", f.Name)
+	}
+	return nil
+}
+
+// Result is a KPI value for one host and export period
+type Result struct {
+	Name        string    `json:"name"`
+	Unit        string    `json:"unit"`
+	Value       float64   `json:"value"`
+	Numerator   uint64    `json:"numerator"`
+	Denominator uint64    `json:"denominator"`
+	Hostname    string    `json:"hostname"`
+	SystemName  string    `json:"system_name"`
+	Timestamp   time.Time `json:"timestamp"` // Export period the records belong to
+}
+
+// Evaluator computes a set of KPIs from metric records
+type Evaluator struct {
+	formulas []Formula
+}
+
+// NewEvaluator creates an evaluator for formulas
+func NewEvaluator(formulas ...Formula) (*Evaluator, error) {
+	seen := make(map[string]bool, len(formulas))
+	for _, f := range formulas {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("duplicate kpi %s", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	return &Evaluator{formulas: formulas}, nil
+}
+
+// Formulas returns the evaluator's formulas
+func (e *Evaluator) Formulas() []Formula {
+	return e.formulas
+}
+
+// periodKey groups records exported together by one host
+type periodKey struct {
+	hostname   string
+	systemName string
+	timestamp  int64
+}
+
+// Evaluate computes every KPI for each (hostname, system, period) found in records
+// Records of one export cycle share a timestamp, which identifies the period
+// KPIs with a zero denominator are omitted, since the ratio is undefined
+func (e *Evaluator) Evaluate(records []export.MetricRecord) []Result {
+	groups := make(map[periodKey][]export.MetricRecord)
+	var keys []periodKey
+	for _, record := range records {
+		key := periodKey{record.Hostname, record.SystemName, record.Timestamp.UnixNano()}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], record)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].timestamp != keys[j].timestamp {
+			return keys[i].timestamp < keys[j].timestamp
+		}
+		if keys[i].hostname != keys[j].hostname {
+			return keys[i].hostname < keys[j].hostname
+		}
+		return keys[i].systemName < keys[j].systemName
+	})
+
+	var results []Result
+	for _, key := range keys {
+		group := groups[key]
+		for _, f := range e.formulas {
+			numerator := sum(group, f.Numerator)
+			denominator := sum(group, f.Denominator)
+			if denominator == 0 {
+				continue
+			}
+
+			value := float64(numerator) / float64(denominator)
+			if f.Unit == UnitPercent {
+				value *= 100
+			}
+			results = append(results, Result{
+				Name:        f.Name,
+				Unit:        f.Unit,
+				Value:       value,
+				Numerator:   numerator,
+				Denominator: denominator,
+				Hostname:    key.hostname,
+				SystemName:  key.systemName,
+				Timestamp:   group[0].Timestamp,
+			})
+		}
+	}
+	return results
+}
+
+// sum adds the values of records matching any of refs
+func sum(records []export.MetricRecord, refs []CounterRef) uint64 {
+	var total uint64
+	for _, record := range records {
+		for _, ref := range refs {
+			if ref.matches(record) {
+				total += record.Value
+				break
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,83 @@
+package kpi
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hsdfat/telco/stats/export"
+)
+
+func TestEvaluator_StandardKPIs(t *testing.T) {
+	period := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)
+	record := func(host string, counterID int, value uint64) export.MetricRecord {
+		return export.MetricRecord{CounterID: counterID, Value: value, Hostname: host, SystemName: "EIR", Timestamp: period}
+	}
+
+	results := NewStandardEvaluator().Evaluate([]export.MetricRecord{
+		record("eir-1", export.CounterDiameterTotal, 200),
+		record("eir-1", export.CounterDiameterSuccess, 190),
+		record("eir-1", export.CounterCacheHits, 30),
+		record("eir-1", export.CounterCacheMisses, 10),
+		record("eir-2", export.CounterDiameterTotal, 50),
+	})
+
+	got := make(map[string]Result)
+	for _, r := range results {
+		got[r.Hostname+"/"+r.Name] = r
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 results (zero denominators omitted), got %d: %+v", len(got), results)
+	}
+
+	expected := map[string]float64{
+		"eir-1/" + DiameterSuccessRate: 95,
+		"eir-1/" + CacheHitRate:        75,
+		"eir-2/" + DiameterSuccessRate: 0,
+	}
+	for key, want := range expected {
+		r, ok := got[key]
+		if !ok {
+			t.Errorf("Missing result %s", key)
+			continue
+		}
+		if math.Abs(r.Value-want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", key, want, r.Value)
+		}
+		if !r.Timestamp.Equal(period) || r.Unit != UnitPercent {
+			t.Errorf("%s: unexpected timestamp or unit: %+v", key, r)
+		}
+	}
+}
+
+func TestEvaluator_CauseCodes(t *testing.T) {
+	formula := Formula{
+		Name:        "diameter_unable_to_comply_rate",
+		Numerator:   []CounterRef{CounterWithCauses(export.CounterDiameterResultCode, 5012)},
+		Denominator: []CounterRef{Counter(export.CounterDiameterResultCode)},
+		Unit:        UnitRatio,
+	}
+	e, err := NewEvaluator(formula)
+	if err != nil {
+		t.Fatalf("NewEvaluator failed: %v", err)
+	}
+
+	now := time.Now()
+	results := e.Evaluate([]export.MetricRecord{
+		{CounterID: export.CounterDiameterResultCode, CauseCode: 2001, Value: 3, Hostname: "eir-1", Timestamp: now},
+		{CounterID: export.CounterDiameterResultCode, CauseCode: 5012, Value: 1, Hostname: "eir-1", Timestamp: now},
+	})
+	if len(results) != 1 || results[0].Value != 0.25 {
+		t.Errorf("Expected ratio 0.25, got %+v", results)
+	}
+}
+
+func TestNewEvaluator_Invalid(t *testing.T) {
+	if _, err := NewEvaluator(Formula{Name: "x", Numerator: []CounterRef{Counter(1)}}); err == nil {
+		t.Error("Expected error for missing denominator")
+	}
+	f := Standard()[0]
+	if _, err := NewEvaluator(f, f); err == nil {
+		t.Error("Expected error for duplicate names")
+	}
+}
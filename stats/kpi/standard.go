@@ -0,0 +1,94 @@
+package kpi
+
+import (
+	"github.com/hsdfat/telco/stats/export"
+)
+
+// Standard KPI names
+const (
+	RequestSuccessRate       = "request_success_rate"
+	DiameterSuccessRate      = "diameter_success_rate"
+	HTTPSuccessRate          = "http_success_rate"
+	CacheHitRate             = "cache_hit_rate"
+	DBErrorRate              = "db_error_rate"
+	ConnectionFailureRate    = "connection_failure_rate"
+	EquipmentBlacklistedRate = "equipment_blacklisted_rate"
+	OverloadRejectRate       = "overload_reject_rate"
+)
+
+// Standard returns the built-in EIR and Diameter KPI formulas
+func Standard() []Formula {
+	return []Formula{
+		{
+			Name:        RequestSuccessRate,
+			Description: "Successful requests over all requests",
+			Numerator:   []CounterRef{Counter(export.CounterSuccessfulRequests)},
+			Denominator: []CounterRef{Counter(export.CounterTotalRequests)},
+			Unit:        UnitPercent,
+		},
+		{
+			Name:        DiameterSuccessRate,
+			Description: "Successful Diameter requests (e.g. S13 ME-Identity-Check) over all Diameter requests",
+			Numerator:   []CounterRef{Counter(export.CounterDiameterSuccess)},
+			Denominator: []CounterRef{Counter(export.CounterDiameterTotal)},
+			Unit:        UnitPercent,
+		},
+		{
+			Name:        HTTPSuccessRate,
+			Description: "Successful HTTP requests (e.g. N5g-eir) over all HTTP requests",
+			Numerator:   []CounterRef{Counter(export.CounterHTTPSuccess)},
+			Denominator: []CounterRef{Counter(export.CounterHTTPTotal)},
+			Unit:        UnitPercent,
+		},
+		{
+			Name:        CacheHitRate,
+			Description: "Cache hits over cache lookups",
+			Numerator:   []CounterRef{Counter(export.CounterCacheHits)},
+			Denominator: []CounterRef{Counter(export.CounterCacheHits), Counter(export.CounterCacheMisses)},
+			Unit:        UnitPercent,
+		},
+		{
+			Name:        DBErrorRate,
+			Description: "Failed database operations over all database operations",
+			Numerator:   []CounterRef{Counter(export.CounterDBErrors)},
+			Denominator: []CounterRef{
+				Counter(export.CounterDBQueries),
+				Counter(export.CounterDBInserts),
+				Counter(export.CounterDBUpdates),
+				Counter(export.CounterDBDeletes),
+			},
+			Unit: UnitPercent,
+		},
+		{
+			Name:        ConnectionFailureRate,
+			Description: "Failed connection attempts over all connection attempts",
+			Numerator:   []CounterRef{Counter(export.CounterFailedConnections)},
+			Denominator: []CounterRef{Counter(export.CounterTotalConnections)},
+			Unit:        UnitPercent,
+		},
+		{
+			Name:        EquipmentBlacklistedRate,
+			Description: "Equipment checks answered blacklisted over all answered checks",
+			Numerator:   []CounterRef{Counter(export.CounterBlacklisted)},
+			Denominator: []CounterRef{
+				Counter(export.CounterWhitelisted),
+				Counter(export.CounterBlacklisted),
+				Counter(export.CounterGreylisted),
+			},
+			Unit: UnitPercent,
+		},
+		{
+			Name:        OverloadRejectRate,
+			Description: "Requests rejected by overload control over all requests",
+			Numerator:   []CounterRef{Counter(export.CounterOverloadRejected)},
+			Denominator: []CounterRef{Counter(export.CounterTotalRequests)},
+			Unit:        UnitPercent,
+		},
+	}
+}
+
+// NewStandardEvaluator creates an evaluator for the Standard KPIs
+func NewStandardEvaluator() *Evaluator {
+	e, _ := NewEvaluator(Standard()...)
+	return e
+}
@@ -1,81 +1,258 @@
 package stats
 
-import "time"
+import (
+	"time"
+
+	"github.com/hsdfat/telco/config"
+)
 
 // ServiceStats represents unified statistics for any service (EIR, Diam-GW, HTTP-GW)
 type ServiceStats struct {
-	ServiceName     string                 `json:"service_name"`
-	ServiceVersion  string                 `json:"service_version,omitempty"`
-	Uptime          string                 `json:"uptime"`
-	Timestamp       time.Time              `json:"timestamp"`
-	Connections     ConnectionStats        `json:"connections"`
-	Requests        RequestStats           `json:"requests"`
-	Performance     PerformanceStats       `json:"performance"`
-	Errors          ErrorStats             `json:"errors"`
-	InterfaceStats  map[string]interface{} `json:"interface_stats,omitempty"`  // Interface-specific stats
-	CustomMetrics   map[string]interface{} `json:"custom_metrics,omitempty"`   // Service-specific metrics
+	ServiceName     string                         `json:"service_name"`
+	ServiceVersion  string                         `json:"service_version,omitempty"`
+	Uptime          string                         `json:"uptime"`
+	UptimeSeconds   uint64                         `json:"uptime_seconds,omitempty" stats:"gauge,omitzero"` // Seconds since the collector started
+	StartTime       time.Time                      `json:"start_time"`                                      // When the collector started (zero if unknown)
+	Timestamp       time.Time                      `json:"timestamp"`
+	Connections     ConnectionStats                `json:"connections"`
+	Requests        RequestStats                   `json:"requests"`
+	Performance     PerformanceStats               `json:"performance"`
+	Errors          ErrorStats                     `json:"errors"`
+	Runtime         *GoRuntimeStats                `json:"runtime,omitempty"`                   // Optional Go runtime stats
+	Peers           map[string]PeerStats           `json:"peers,omitempty"`                     // Diameter peers by Origin-Host
+	SCTP            *SCTPStats                     `json:"sctp,omitempty"`                      // Optional SCTP transport stats
+	Overload        *OverloadStats                 `json:"overload,omitempty"`                  // Optional overload control stats
+	Capacity        *CapacityStats                 `json:"capacity,omitempty"`                  // Optional license/capacity usage
+	ConfigProviders map[string]ConfigProviderStats `json:"config_providers,omitempty"`          // Config provider health by provider name
+	SLOs            map[string]SLOStats            `json:"slos,omitempty" stats:"gauge"`        // SLO compliance by operation, see CollectorConfig.SLOs
+	Tenants         map[string]TenantStats         `json:"tenants,omitempty" stats:"omitidle"`  // KPIs by tenant (S-NSSAI or customer ID), see Collector.Tenant
+	InterfaceStats  map[string]interface{}         `json:"interface_stats,omitempty" stats:"-"` // Interface-specific stats
+	CustomMetrics   CustomMetrics                  `json:"custom_metrics,omitempty" stats:"-"`  // Service-specific metrics, see RegisterCustomMetric
+	FailureSamples  map[string][]RequestSample     `json:"failure_samples,omitempty" stats:"-"` // Example failed requests by FailureSampleKey, see RecordRequestSample
 }
 
 // ConnectionStats tracks connection-related statistics
 type ConnectionStats struct {
-	Total   uint64 `json:"total"`    // Total connections ever established
-	Active  uint64 `json:"active"`   // Currently active connections
-	Failed  uint64 `json:"failed"`   // Failed connection attempts
-	Closed  uint64 `json:"closed"`   // Gracefully closed connections
+	Total      uint64                   `json:"total" stats:"counter"`  // Total connections ever established
+	Active     uint64                   `json:"active" stats:"gauge"`   // Currently active connections
+	Failed     uint64                   `json:"failed" stats:"counter"` // Failed connection attempts
+	Closed     uint64                   `json:"closed" stats:"counter"` // Gracefully closed connections
+	ByListener map[string]ListenerStats `json:"by_listener,omitempty"`  // Stats by listener bind address (e.g., "sctp://0.0.0.0:3868")
+}
+
+// ListenerStats tracks connection statistics for a single listener
+type ListenerStats struct {
+	Active uint64 `json:"active" stats:"gauge"`
+	Total  uint64 `json:"total" stats:"counter"`
+	Failed uint64 `json:"failed" stats:"counter"`
+	Closed uint64 `json:"closed" stats:"counter"`
+}
+
+// Diameter peer states (RFC 6733 peer state machine, simplified)
+const (
+	PeerStateClosed      = "closed"
+	PeerStateWaitConnAck = "wait_conn_ack"
+	PeerStateWaitCEA     = "wait_cea"
+	PeerStateOpen        = "open"
+	PeerStateClosing     = "closing"
+)
+
+// PeerStats tracks the connection health of a single Diameter peer
+type PeerStats struct {
+	State               string    `json:"state"`
+	ConnectedSince      time.Time `json:"connected_since,omitempty"`    // When the peer last entered the open state
+	UptimeSeconds       uint64    `json:"uptime_seconds" stats:"gauge"` // Seconds in the open state (0 if not open)
+	DWRFailures         uint64    `json:"dwr_failures" stats:"counter"` // Device-Watchdog requests without answer
+	MessagesSent        uint64    `json:"messages_sent" stats:"counter"`
+	MessagesRecv        uint64    `json:"messages_recv" stats:"counter"`
+	Disconnects         uint64    `json:"disconnects" stats:"counter"`
+	LastDisconnectCause string    `json:"last_disconnect_cause,omitempty"`
+	LastDisconnectAt    time.Time `json:"last_disconnect_at,omitempty"`
+}
+
+// SCTPStats tracks SCTP association and transport health
+type SCTPStats struct {
+	ActiveAssociations uint64 `json:"active_associations" stats:"gauge"` // Currently established associations (gauge)
+	Establishes        uint64 `json:"establishes" stats:"counter"`       // Associations established (COMM_UP)
+	Aborts             uint64 `json:"aborts" stats:"counter"`            // Associations aborted (COMM_LOST / ABORT)
+	Shutdowns          uint64 `json:"shutdowns" stats:"counter"`         // Associations gracefully shut down
+	PathFailovers      uint64 `json:"path_failovers" stats:"counter"`    // Primary path changes on multi-homed associations
+	Retransmits        uint64 `json:"retransmits" stats:"counter"`       // Retransmitted DATA chunks
+	GapAcks            uint64 `json:"gap_acks" stats:"counter"`          // SACKs reporting gap ack blocks
+}
+
+// OverloadStats tracks overload/congestion control behavior (Diameter DOIC, HTTP 503)
+type OverloadStats struct {
+	LoadLevel   uint64                            `json:"load_level" stats:"gauge"`  // Current load level in percent (gauge)
+	Throttled   uint64                            `json:"throttled" stats:"counter"` // Requests delayed or reduced by overload control
+	Rejected    uint64                            `json:"rejected" stats:"counter"`  // Requests rejected due to overload
+	ByInterface map[string]InterfaceOverloadStats `json:"by_interface,omitempty"`
+}
+
+// InterfaceOverloadStats tracks overload control actions on a single interface
+type InterfaceOverloadStats struct {
+	Throttled uint64 `json:"throttled" stats:"counter"`
+	Rejected  uint64 `json:"rejected" stats:"counter"`
+}
+
+// CapacityStats tracks license and capacity usage for compliance reporting (all gauges)
+type CapacityStats struct {
+	LicensedTPS            uint64  `json:"licensed_tps" stats:"gauge"`
+	PeakTPS                float64 `json:"peak_tps" stats:"gauge"` // Highest TPS observed during the period
+	LicensedSubscribers    uint64  `json:"licensed_subscribers" stats:"gauge"`
+	ProvisionedSubscribers uint64  `json:"provisioned_subscribers" stats:"gauge"`
+}
+
+// ConfigProviderStats tracks the health of one configuration provider (e.g. Consul)
+type ConfigProviderStats struct {
+	Up               bool      `json:"up"`                              // Last load succeeded and the provider isn't stale
+	Loads            uint64    `json:"loads" stats:"counter"`           // Load attempts
+	LoadFailures     uint64    `json:"load_failures" stats:"counter"`   // Failed load attempts
+	LastLoad         time.Time `json:"last_load,omitempty"`             // Start of the last load attempt
+	LastError        string    `json:"last_error,omitempty"`            // Error of the last load attempt
+	LatencyMs        float64   `json:"latency_ms" stats:"gauge"`        // Duration of the last load attempt
+	StalenessSeconds uint64    `json:"staleness_seconds" stats:"gauge"` // Seconds since the last successful load
+}
+
+// NewConfigProviderStats converts config provider statuses to ConfigProviderStats by
+// provider name
+func NewConfigProviderStats(statuses []config.ProviderStatus) map[string]ConfigProviderStats {
+	result := make(map[string]ConfigProviderStats, len(statuses))
+	for _, status := range statuses {
+		s := ConfigProviderStats{
+			Up:               status.Up(),
+			Loads:            status.Loads,
+			LoadFailures:     status.Failures,
+			LastLoad:         status.LastLoad,
+			LatencyMs:        float64(status.Latency) / float64(time.Millisecond),
+			StalenessSeconds: uint64(status.Staleness / time.Second),
+		}
+		if status.LastError != nil {
+			s.LastError = status.LastError.Error()
+		}
+		result[status.Name] = s
+	}
+	return result
+}
+
+// SLOStats tracks compliance with one operation's SLO over the current budget window
+type SLOStats struct {
+	SuccessTarget          float64   `json:"success_target,omitempty"`     // Minimum success ratio
+	LatencyTargetMs        float64   `json:"latency_target_ms,omitempty"`  // Latency objective threshold
+	LatencyPercentile      float64   `json:"latency_percentile,omitempty"` // Share of requests (percent) within the threshold
+	WindowStart            time.Time `json:"window_start"`
+	Total                  uint64    `json:"total"` // Requests in the window
+	Failed                 uint64    `json:"failed"`
+	Slow                   uint64    `json:"slow"`                     // Requests slower than the latency target
+	Compliant              bool      `json:"compliant"`                // Whether the last evaluation interval met the SLO
+	ComplianceSeconds      uint64    `json:"compliance_seconds"`       // Evaluated time in the window meeting the SLO
+	ViolationSeconds       uint64    `json:"violation_seconds"`        // Evaluated time in the window violating the SLO
+	ErrorBudgetRemaining   float64   `json:"error_budget_remaining"`   // Share of the failure budget left (1 = untouched, negative = overspent)
+	LatencyBudgetRemaining float64   `json:"latency_budget_remaining"` // Share of the slow request budget left
 }
 
 // RequestStats tracks request/response statistics
 type RequestStats struct {
-	Total       uint64 `json:"total"`        // Total requests processed
-	Success     uint64 `json:"success"`      // Successful requests
-	Failed      uint64 `json:"failed"`       // Failed requests
-	Pending     uint64 `json:"pending"`      // Requests in progress
-	BytesSent   uint64 `json:"bytes_sent"`   // Total bytes sent
-	BytesRecv   uint64 `json:"bytes_recv"`   // Total bytes received
-	BySource    map[string]SourceStats `json:"by_source,omitempty"`  // Stats by source (diameter, http, etc)
-	ByOperation map[string]OperationStats `json:"by_operation,omitempty"` // Stats by operation type
+	Total       uint64                    `json:"total" stats:"counter"`              // Total requests processed
+	Success     uint64                    `json:"success" stats:"counter"`            // Successful requests
+	Failed      uint64                    `json:"failed" stats:"counter"`             // Failed requests
+	Pending     uint64                    `json:"pending" stats:"gauge,omitzero"`     // Requests in progress
+	MaxPending  uint64                    `json:"max_pending" stats:"gauge,omitzero"` // Highest Pending value observed during the period
+	BytesSent   uint64                    `json:"bytes_sent" stats:"counter"`         // Total bytes sent
+	BytesRecv   uint64                    `json:"bytes_recv" stats:"counter"`         // Total bytes received
+	BySource    map[string]SourceStats    `json:"by_source,omitempty"`                // Stats by source (diameter, http, etc)
+	ByOperation map[string]OperationStats `json:"by_operation,omitempty"`             // Stats by operation type
+}
+
+// TenantStats tracks the KPIs of one tenant or network slice on a gateway shared by several MVNOs
+type TenantStats struct {
+	Total        uint64         `json:"total" stats:"counter"`
+	Success      uint64         `json:"success" stats:"counter"`
+	Failed       uint64         `json:"failed" stats:"counter"`
+	BytesSent    uint64         `json:"bytes_sent,omitempty" stats:"counter"`
+	BytesRecv    uint64         `json:"bytes_recv,omitempty" stats:"counter"`
+	ByResultCode map[int]uint64 `json:"by_result_code,omitempty" stats:"counter"` // Diameter result code or HTTP status code distribution
+	Latency      LatencyStats   `json:"latency" stats:"gauge"`
 }
 
 // SourceStats tracks statistics by source interface
 type SourceStats struct {
-	Total   uint64 `json:"total"`
-	Success uint64 `json:"success"`
-	Failed  uint64 `json:"failed"`
+	Total     uint64         `json:"total" stats:"counter"`
+	Success   uint64         `json:"success" stats:"counter"`
+	Failed    uint64         `json:"failed" stats:"counter"`
+	InFlight  uint64         `json:"in_flight,omitempty" stats:"gauge"` // Requests currently in progress for this source
+	BytesSent uint64         `json:"bytes_sent,omitempty" stats:"counter"`
+	BytesRecv uint64         `json:"bytes_recv,omitempty" stats:"counter"`
+	SentSizes map[int]uint64 `json:"sent_sizes,omitempty" stats:"counter"` // Sent message size histogram (bucket upper bound -> count)
+	RecvSizes map[int]uint64 `json:"recv_sizes,omitempty" stats:"counter"` // Received message size histogram (bucket upper bound -> count)
+}
+
+// MessageSizeBuckets are the upper bounds (bytes) of the message size histogram buckets
+// Sizes above the last bound are counted under OverflowSizeBucket
+var MessageSizeBuckets = []int{128, 256, 512, 1024, 2048, 4096, 8192, 16384, 65536}
+
+// OverflowSizeBucket is the histogram key for messages larger than every bucket bound
+const OverflowSizeBucket = -1
+
+// SizeBucket returns the histogram bucket key for a message size in bytes
+func SizeBucket(size uint64) int {
+	for _, bound := range MessageSizeBuckets {
+		if size <= uint64(bound) {
+			return bound
+		}
+	}
+	return OverflowSizeBucket
 }
 
 // OperationStats tracks statistics by operation type
 type OperationStats struct {
-	Total   uint64  `json:"total"`
-	Success uint64  `json:"success"`
-	Failed  uint64  `json:"failed"`
-	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	Total        uint64  `json:"total" stats:"counter"`
+	Success      uint64  `json:"success" stats:"counter"`
+	Failed       uint64  `json:"failed" stats:"counter"`
+	AvgLatencyMs float64 `json:"avg_latency_ms" stats:"gauge"`
 }
 
 // PerformanceStats tracks performance-related statistics
 type PerformanceStats struct {
-	RequestsPerSecond float64       `json:"requests_per_second"`
-	AvgLatencyMs      float64       `json:"avg_latency_ms"`
-	MinLatencyMs      float64       `json:"min_latency_ms"`
-	MaxLatencyMs      float64       `json:"max_latency_ms"`
-	P50LatencyMs      float64       `json:"p50_latency_ms,omitempty"`
-	P95LatencyMs      float64       `json:"p95_latency_ms,omitempty"`
-	P99LatencyMs      float64       `json:"p99_latency_ms,omitempty"`
+	RequestsPerSecond float64                 `json:"requests_per_second" stats:"gauge"`
+	AvgLatencyMs      float64                 `json:"avg_latency_ms" stats:"gauge"`
+	MinLatencyMs      float64                 `json:"min_latency_ms" stats:"gauge"`
+	MaxLatencyMs      float64                 `json:"max_latency_ms" stats:"gauge"`
+	P50LatencyMs      float64                 `json:"p50_latency_ms,omitempty" stats:"gauge"`
+	P95LatencyMs      float64                 `json:"p95_latency_ms,omitempty" stats:"gauge"`
+	P99LatencyMs      float64                 `json:"p99_latency_ms,omitempty" stats:"gauge"`
+	BySource          map[string]LatencyStats `json:"by_source,omitempty" stats:"gauge"`    // Latency by source (diameter, http, etc)
+	ByOperation       map[string]LatencyStats `json:"by_operation,omitempty" stats:"gauge"` // Latency by operation type
+}
+
+// LatencyStats tracks latency percentiles for a single source or operation
+type LatencyStats struct {
+	Count        uint64  `json:"count" stats:"gauge"`
+	AvgLatencyMs float64 `json:"avg_latency_ms" stats:"gauge"`
+	MinLatencyMs float64 `json:"min_latency_ms" stats:"gauge"`
+	MaxLatencyMs float64 `json:"max_latency_ms" stats:"gauge"`
+	P50LatencyMs float64 `json:"p50_latency_ms,omitempty" stats:"gauge"`
+	P95LatencyMs float64 `json:"p95_latency_ms,omitempty" stats:"gauge"`
+	P99LatencyMs float64 `json:"p99_latency_ms,omitempty" stats:"gauge"`
 }
 
 // ErrorStats tracks error-related statistics
 type ErrorStats struct {
-	Total       uint64            `json:"total"`
-	ByType      map[string]uint64 `json:"by_type,omitempty"`      // Errors by type/code
-	ByInterface map[string]uint64 `json:"by_interface,omitempty"` // Errors by interface
-	LastError   *ErrorInfo        `json:"last_error,omitempty"`
+	Total       uint64            `json:"total" stats:"counter"`
+	ByType      map[string]uint64 `json:"by_type,omitempty" stats:"counter"`      // Errors by type/code
+	ByInterface map[string]uint64 `json:"by_interface,omitempty" stats:"counter"` // Errors by interface
+	LastError   *ErrorInfo        `json:"last_error,omitempty" stats:"gauge"`
+	Recent      []ErrorInfo       `json:"recent,omitempty" stats:"gauge"` // Recent distinct errors, oldest first
 }
 
 // ErrorInfo contains information about an error
 type ErrorInfo struct {
 	Message   string    `json:"message"`
 	Code      string    `json:"code,omitempty"`
-	Timestamp time.Time `json:"timestamp"`
-	Count     uint64    `json:"count"` // How many times this error occurred
+	Interface string    `json:"interface,omitempty"`
+	FirstSeen time.Time `json:"first_seen,omitempty"`
+	Timestamp time.Time `json:"timestamp"` // When the error last occurred
+	Count     uint64    `json:"count"`     // How many times this error occurred
 }
 
 // DiameterStats contains Diameter-specific statistics
@@ -85,14 +262,14 @@ type DiameterStats struct {
 
 // ApplicationStats tracks statistics for a Diameter application
 type ApplicationStats struct {
-	ApplicationID int                    `json:"application_id"`
-	Name          string                 `json:"name,omitempty"`
-	MessagesSent  uint64                 `json:"messages_sent"`
-	MessagesRecv  uint64                 `json:"messages_recv"`
-	BytesSent     uint64                 `json:"bytes_sent"`
-	BytesRecv     uint64                 `json:"bytes_recv"`
-	Errors        uint64                 `json:"errors"`
-	Commands      map[int]CommandStats   `json:"commands,omitempty"` // Stats by Command-Code
+	ApplicationID int                  `json:"application_id"`
+	Name          string               `json:"name,omitempty"`
+	MessagesSent  uint64               `json:"messages_sent"`
+	MessagesRecv  uint64               `json:"messages_recv"`
+	BytesSent     uint64               `json:"bytes_sent"`
+	BytesRecv     uint64               `json:"bytes_recv"`
+	Errors        uint64               `json:"errors"`
+	Commands      map[int]CommandStats `json:"commands,omitempty"` // Stats by Command-Code
 }
 
 // CommandStats tracks statistics for a Diameter command
@@ -132,66 +309,112 @@ type MethodStats struct {
 
 // EIRStats contains EIR-specific statistics
 type EIRStats struct {
-	EquipmentChecks   EquipmentCheckStats      `json:"equipment_checks"`
-	DatabaseOps       DatabaseOperationStats   `json:"database_operations"`
-	CacheStats        CacheStats               `json:"cache_stats"`
-	ByEquipmentStatus map[string]uint64        `json:"by_equipment_status,omitempty"` // whitelisted, blacklisted, greylisted
+	EquipmentChecks   EquipmentCheckStats    `json:"equipment_checks"`
+	DatabaseOps       DatabaseOperationStats `json:"database_operations"`
+	CacheStats        CacheStats             `json:"cache_stats"`
+	ByEquipmentStatus map[string]uint64      `json:"by_equipment_status,omitempty" stats:"counter"` // whitelisted, blacklisted, greylisted
+	StatusTransitions map[string]uint64      `json:"status_transitions,omitempty" stats:"counter"`  // "whitelisted->blacklisted" -> count
+	RecentChanges     []StatusChange         `json:"recent_status_changes,omitempty"`               // Optional audit ring, oldest first
+	ByTAC             map[string]TACStats    `json:"by_tac,omitempty" stats:"omitidle"`             // Type Allocation Code (first 8 IMEI digits)
+}
+
+// TACStats tracks equipment checks for a single Type Allocation Code (device model)
+type TACStats struct {
+	Checks   uint64            `json:"checks" stats:"counter"`
+	ByStatus map[string]uint64 `json:"by_status,omitempty" stats:"counter"`
+}
+
+// TACFromIMEI returns the Type Allocation Code (first 8 digits) of an IMEI
+func TACFromIMEI(imei string) string {
+	if len(imei) < 8 {
+		return ""
+	}
+	return imei[:8]
+}
+
+// StatusChange records a single equipment status transition for auditing
+type StatusChange struct {
+	IMEI      string    `json:"imei"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TransitionKey returns the StatusTransitions map key for a status change
+func TransitionKey(from, to string) string {
+	return from + "->" + to
 }
 
 // InterfaceCheckStats tracks equipment check statistics for a specific interface
 type InterfaceCheckStats struct {
-	Total        uint64         `json:"total"`
-	Success      uint64         `json:"success"`
-	Failed       uint64         `json:"failed"`
-	ByResultCode map[int]uint64 `json:"by_result_code,omitempty"` // Result code distribution
+	Total        uint64         `json:"total" stats:"counter"`
+	Success      uint64         `json:"success" stats:"counter"`
+	Failed       uint64         `json:"failed" stats:"counter"`
+	ByResultCode map[int]uint64 `json:"by_result_code,omitempty" stats:"counter"` // Result code distribution
 }
 
 // EquipmentCheckStats tracks equipment check statistics
 type EquipmentCheckStats struct {
-	Total       uint64                          `json:"total"`
-	Success     uint64                          `json:"success"`
-	Failed      uint64                          `json:"failed"`
+	Total       uint64                         `json:"total" stats:"counter"`
+	Success     uint64                         `json:"success" stats:"counter"`
+	Failed      uint64                         `json:"failed" stats:"counter"`
 	ByInterface map[string]InterfaceCheckStats `json:"by_interface,omitempty"` // diameter, http
 }
 
 // DatabaseOperationStats tracks database operation statistics
 type DatabaseOperationStats struct {
-	Queries        uint64  `json:"queries"`
-	Inserts        uint64  `json:"inserts"`
-	Updates        uint64  `json:"updates"`
-	Deletes        uint64  `json:"deletes"`
-	Errors         uint64  `json:"errors"`
-	AvgLatencyMs   float64 `json:"avg_latency_ms"`
-	ActiveQueries  uint64  `json:"active_queries"`
+	Queries       uint64                      `json:"queries" stats:"counter"`
+	Inserts       uint64                      `json:"inserts" stats:"counter"`
+	Updates       uint64                      `json:"updates" stats:"counter"`
+	Deletes       uint64                      `json:"deletes" stats:"counter"`
+	Errors        uint64                      `json:"errors" stats:"counter"`
+	AvgLatencyMs  float64                     `json:"avg_latency_ms" stats:"gauge,omitzero"`
+	ActiveQueries uint64                      `json:"active_queries" stats:"gauge"`
+	ByOperation   map[string]LatencyStats     `json:"by_operation,omitempty" stats:"gauge"`     // Latency by operation (query, insert, update, delete)
+	ByTable       map[string]DBBreakdownStats `json:"by_table,omitempty" stats:"omitidle"`      // Optional breakdown by table
+	ByQueryName   map[string]DBBreakdownStats `json:"by_query_name,omitempty" stats:"omitidle"` // Optional breakdown by named query
+}
+
+// DBBreakdownStats tracks database operations for a single table or named query
+type DBBreakdownStats struct {
+	Operations   uint64  `json:"operations" stats:"counter"`
+	Errors       uint64  `json:"errors" stats:"counter"`
+	AvgLatencyMs float64 `json:"avg_latency_ms" stats:"gauge"`
+	MaxLatencyMs float64 `json:"max_latency_ms" stats:"gauge"`
 }
 
 // CacheStats tracks cache statistics
 type CacheStats struct {
-	Hits        uint64  `json:"hits"`
-	Misses      uint64  `json:"misses"`
-	HitRate     float64 `json:"hit_rate"` // Percentage
-	Size        uint64  `json:"size"`     // Number of entries
-	MaxSize     uint64  `json:"max_size"`
-	Evictions   uint64  `json:"evictions"`
+	Hits        uint64  `json:"hits" stats:"counter"`
+	Misses      uint64  `json:"misses" stats:"counter"`
+	HitRate     float64 `json:"hit_rate" stats:"gauge,omitzero"` // Percentage
+	Size        uint64  `json:"size" stats:"gauge,omitzero"`     // Number of entries
+	MaxSize     uint64  `json:"max_size" stats:"gauge,omitzero"`
+	Evictions   uint64  `json:"evictions" stats:"counter"`
+	Expirations uint64  `json:"expirations" stats:"counter"`
+	Bytes       uint64  `json:"bytes" stats:"gauge,omitzero"` // Approximate memory used by cached entries
 }
 
 // StatsResponse is the standard HTTP response format for stats endpoints
 type StatsResponse struct {
-	Status  string       `json:"status"`  // "success" or "error"
+	Status  string       `json:"status"` // "success" or "error"
 	Message string       `json:"message,omitempty"`
 	Data    ServiceStats `json:"data"`
 }
 
 // HealthStatus represents the health status of a service
 type HealthStatus struct {
-	Status    string            `json:"status"` // "healthy", "degraded", "unhealthy"
-	Timestamp time.Time         `json:"timestamp"`
-	Checks    map[string]Check  `json:"checks,omitempty"`
+	Status    string           `json:"status"` // "healthy", "degraded", "unhealthy"
+	Score     int              `json:"score"`  // 0-100 overall score, see HealthScorer
+	Timestamp time.Time        `json:"timestamp"`
+	Checks    map[string]Check `json:"checks,omitempty"`
 }
 
 // Check represents a health check result
 type Check struct {
-	Status   string `json:"status"` // "pass", "warn", "fail"
-	Message  string `json:"message,omitempty"`
-	Duration string `json:"duration,omitempty"`
+	Status   string   `json:"status"` // "pass", "warn", "fail"
+	Score    int      `json:"score"`  // 0-100 subsystem score (HealthScorer checks)
+	Message  string   `json:"message,omitempty"`
+	Reasons  []string `json:"reasons,omitempty"` // Metrics lowering Score
+	Duration string   `json:"duration,omitempty"`
 }
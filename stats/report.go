@@ -0,0 +1,165 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// DefaultReportTopN is the number of rows of each ranked table in FormatStatsReport
+const DefaultReportTopN = 10
+
+// RankedCount is one row of a ranked table
+type RankedCount[K cmp.Ordered] struct {
+	Key   K
+	Count uint64
+	Share float64 // Fraction of the map's total
+}
+
+// TopCounts ranks the entries of m by count, highest first (ties by key), keeping
+// at most n (all if n <= 0)
+func TopCounts[K cmp.Ordered](m map[K]uint64, n int) []RankedCount[K] {
+	var total uint64
+	rows := make([]RankedCount[K], 0, len(m))
+	for k, v := range m {
+		total += v
+		rows = append(rows, RankedCount[K]{Key: k, Count: v})
+	}
+	slices.SortFunc(rows, func(a, b RankedCount[K]) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	if n > 0 && len(rows) > n {
+		rows = rows[:n]
+	}
+	for i := range rows {
+		if total > 0 {
+			rows[i].Share = float64(rows[i].Count) / float64(total)
+		}
+	}
+	return rows
+}
+
+// EndpointErrorRate is one row of the endpoint error rate ranking
+type EndpointErrorRate struct {
+	Endpoint  string
+	Requests  uint64
+	Errors    uint64
+	ErrorRate float64
+}
+
+// TopEndpointsByErrorRate ranks HTTP endpoints with errors by error rate, highest
+// first (ties by error count, then endpoint), keeping at most n (all if n <= 0)
+func TopEndpointsByErrorRate(endpoints map[string]EndpointStats, n int) []EndpointErrorRate {
+	var rows []EndpointErrorRate
+	for key, ep := range endpoints {
+		if ep.Errors == 0 || ep.Requests == 0 {
+			continue
+		}
+		name := ep.Path
+		if name == "" {
+			name = key
+		}
+		rows = append(rows, EndpointErrorRate{
+			Endpoint:  name,
+			Requests:  ep.Requests,
+			Errors:    ep.Errors,
+			ErrorRate: float64(ep.Errors) / float64(ep.Requests),
+		})
+	}
+	slices.SortFunc(rows, func(a, b EndpointErrorRate) int {
+		if c := cmp.Compare(b.ErrorRate, a.ErrorRate); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Errors, a.Errors); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Endpoint, b.Endpoint)
+	})
+	if n > 0 && len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+// resultCodesByInterface collects the result code distributions of stats by interface:
+// EIR equipment checks per interface, and HTTP status codes as "http"
+func resultCodesByInterface(stats *ServiceStats) map[string]map[int]uint64 {
+	codes := make(map[string]map[int]uint64)
+	if eir, ok := stats.CustomMetrics.EIR(); ok {
+		for iface, s := range eir.EquipmentChecks.ByInterface {
+			if len(s.ByResultCode) > 0 {
+				codes[iface] = s.ByResultCode
+			}
+		}
+	}
+	if http, ok := CustomMetric[HTTPStats](stats.InterfaceStats["http"]); ok && len(http.ByStatus) > 0 {
+		if _, exists := codes["http"]; !exists {
+			codes["http"] = http.ByStatus
+		}
+	}
+	return codes
+}
+
+// writeRankedTables appends the top-n result code, error type and endpoint error
+// rate tables to report, skipping empty ones
+func writeRankedTables(report *strings.Builder, stats *ServiceStats, n int) {
+	codes := resultCodesByInterface(stats)
+	if len(codes) > 0 {
+		report.WriteString("\nTop Result Codes:\n")
+		ifaces := make([]string, 0, len(codes))
+		for iface := range codes {
+			ifaces = append(ifaces, iface)
+		}
+		slices.Sort(ifaces)
+		for _, iface := range ifaces {
+			report.WriteString(fmt.Sprintf("  %s:\n", iface))
+			w := tabwriter.NewWriter(report, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "    CODE\tCOUNT\tSHARE")
+			for _, row := range TopCounts(codes[iface], n) {
+				fmt.Fprintf(w, "    %d\t%d\t%.1f%%\n", row.Key, row.Count, row.Share*100)
+			}
+			w.Flush()
+			writeMore(report, "    ", len(codes[iface]), n)
+		}
+	}
+
+	if len(stats.Errors.ByType) > 0 {
+		report.WriteString("\nTop Errors:\n")
+		w := tabwriter.NewWriter(report, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  TYPE\tCOUNT\tSHARE")
+		for _, row := range TopCounts(stats.Errors.ByType, n) {
+			fmt.Fprintf(w, "  %s\t%d\t%.1f%%\n", row.Key, row.Count, row.Share*100)
+		}
+		w.Flush()
+		writeMore(report, "  ", len(stats.Errors.ByType), n)
+	}
+
+	if http, ok := CustomMetric[HTTPStats](stats.InterfaceStats["http"]); ok {
+		all := TopEndpointsByErrorRate(http.ByEndpoint, 0)
+		if len(all) > 0 {
+			report.WriteString("\nTop Endpoints by Error Rate:\n")
+			w := tabwriter.NewWriter(report, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "  ENDPOINT\tREQUESTS\tERRORS\tERROR RATE")
+			for i, row := range all {
+				if n > 0 && i == n {
+					break
+				}
+				fmt.Fprintf(w, "  %s\t%d\t%d\t%.1f%%\n", row.Endpoint, row.Requests, row.Errors, row.ErrorRate*100)
+			}
+			w.Flush()
+			writeMore(report, "  ", len(all), n)
+		}
+	}
+}
+
+// writeMore notes the rows of a table cut by the top-n limit
+func writeMore(report *strings.Builder, indent string, total, n int) {
+	if n > 0 && total > n {
+		report.WriteString(fmt.Sprintf("%s... %d more\n", indent, total-n))
+	}
+}
@@ -0,0 +1,182 @@
+package stats
+
+import (
+	"context"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Go runtime/metrics key for total CPU time consumed by the Go runtime and user code
+const cpuTotalMetric = "/cpu/classes/total:cpu-seconds"
+
+// Go runtime/metrics key for idle CPU time, subtracted from the total
+const cpuIdleMetric = "/cpu/classes/idle:cpu-seconds"
+
+// GoRuntimeStats tracks Go runtime health for correlating KPI drops with GC pressure
+type GoRuntimeStats struct {
+	Goroutines     uint64  `json:"goroutines" stats:"gauge"`
+	HeapInUseBytes uint64  `json:"heap_inuse_bytes" stats:"gauge"`
+	HeapObjects    uint64  `json:"heap_objects" stats:"gauge"`
+	NumGC          uint64  `json:"num_gc" stats:"counter"`        // Completed GC cycles (counter)
+	GCPauseP99Ms   float64 `json:"gc_pause_p99_ms" stats:"gauge"` // p99 over the most recent 256 GC pauses
+	CPUPercent     float64 `json:"cpu_percent" stats:"gauge"`     // Process CPU usage since last sample (100 = one core)
+	GOMAXPROCS     int     `json:"gomaxprocs" stats:"gauge"`
+}
+
+// RuntimeSampler periodically samples Go runtime statistics
+type RuntimeSampler struct {
+	interval time.Duration
+	mu       sync.RWMutex
+	latest   *GoRuntimeStats
+	lastCPU  float64
+	lastWall time.Time
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+}
+
+// NewRuntimeSampler creates a runtime sampler (default interval: 10s)
+func NewRuntimeSampler(interval time.Duration) *RuntimeSampler {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	return &RuntimeSampler{
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins periodic sampling until Stop is called or ctx is cancelled
+func (s *RuntimeSampler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	s.Sample()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.Sample()
+			}
+		}
+	}()
+}
+
+// Stop halts periodic sampling
+func (s *RuntimeSampler) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	s.mu.Unlock()
+
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Stats returns a copy of the latest sample, sampling now if none has been taken yet
+func (s *RuntimeSampler) Stats() *GoRuntimeStats {
+	s.mu.RLock()
+	latest := s.latest
+	s.mu.RUnlock()
+
+	if latest == nil {
+		latest = s.Sample()
+	}
+
+	result := *latest
+	return &result
+}
+
+// Sample takes a runtime sample immediately and stores it as the latest
+func (s *RuntimeSampler) Sample() *GoRuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	sample := &GoRuntimeStats{
+		Goroutines:     uint64(runtime.NumGoroutine()),
+		HeapInUseBytes: mem.HeapInuse,
+		HeapObjects:    mem.HeapObjects,
+		NumGC:          uint64(mem.NumGC),
+		GCPauseP99Ms:   gcPauseP99Ms(&mem),
+		GOMAXPROCS:     runtime.GOMAXPROCS(0),
+	}
+
+	cpuSeconds := readCPUSeconds()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lastWall.IsZero() {
+		if wall := now.Sub(s.lastWall).Seconds(); wall > 0 && cpuSeconds >= s.lastCPU {
+			sample.CPUPercent = (cpuSeconds - s.lastCPU) / wall * 100
+		}
+	} else if s.latest != nil {
+		sample.CPUPercent = s.latest.CPUPercent
+	}
+	s.lastCPU = cpuSeconds
+	s.lastWall = now
+	s.latest = sample
+
+	return sample
+}
+
+// gcPauseP99Ms computes the p99 of the recent GC pauses recorded in MemStats
+func gcPauseP99Ms(mem *runtime.MemStats) float64 {
+	n := int(mem.NumGC)
+	if n == 0 {
+		return 0
+	}
+	if n > len(mem.PauseNs) {
+		n = len(mem.PauseNs)
+	}
+
+	pauses := make([]float64, n)
+	for i := 0; i < n; i++ {
+		pauses[i] = float64(mem.PauseNs[i]) / float64(time.Millisecond)
+	}
+	sort.Float64s(pauses)
+
+	return percentile(pauses, 99)
+}
+
+// readCPUSeconds returns the CPU time used by the process as estimated by the Go runtime
+func readCPUSeconds() float64 {
+	samples := []metrics.Sample{
+		{Name: cpuTotalMetric},
+		{Name: cpuIdleMetric},
+	}
+	metrics.Read(samples)
+
+	var total, idle float64
+	if samples[0].Value.Kind() == metrics.KindFloat64 {
+		total = samples[0].Value.Float64()
+	}
+	if samples[1].Value.Kind() == metrics.KindFloat64 {
+		idle = samples[1].Value.Float64()
+	}
+	return total - idle
+}
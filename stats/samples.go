@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"strconv"
+	"time"
+)
+
+// RequestSample describes one failed request, kept as an example of what a failure
+// counter counted so a spike (e.g. in 5012 answers) comes with concrete requests to chase
+type RequestSample struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Interface  string    `json:"interface"`           // Source as passed to RecordResultCode (diameter, http, s13)
+	Operation  string    `json:"operation,omitempty"` // e.g. "check"
+	Peer       string    `json:"peer,omitempty"`      // Origin-Host or client address
+	ResultCode int       `json:"result_code"`         // Diameter result code or HTTP status code
+	LatencyMs  float64   `json:"latency_ms"`
+	Detail     string    `json:"detail,omitempty"` // Error message, IMEI prefix etc.; avoid subscriber identities
+}
+
+// IsFailureCode reports whether a Diameter result code (3xxx-5xxx) or HTTP status
+// code (4xx-5xx) is a failure
+func IsFailureCode(code int) bool {
+	return (code >= 400 && code < 600) || code >= 3000
+}
+
+// FailureSampleKey returns the key of the failure counter a sample belongs to in
+// ServiceStats.FailureSamples, e.g. "diameter/5012"
+func FailureSampleKey(iface string, code int) string {
+	return iface + "/" + strconv.Itoa(code)
+}
+
+// RecordRequestSample captures sample as an example of its failure counter if fewer
+// than CollectorConfig.FailureSamples were captured for it this period
+// Successful result codes are ignored; call it alongside RecordResultCode
+func (c *Collector) RecordRequestSample(sample RequestSample) {
+	if c.config.FailureSamples <= 0 || !IsFailureCode(sample.ResultCode) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := FailureSampleKey(sample.Interface, sample.ResultCode)
+	if len(c.failureSamples[key]) >= c.config.FailureSamples {
+		return
+	}
+	if sample.Timestamp.IsZero() {
+		sample.Timestamp = c.clock.Now()
+	}
+	if c.failureSamples == nil {
+		c.failureSamples = make(map[string][]RequestSample)
+	}
+	c.failureSamples[key] = append(c.failureSamples[key], sample)
+}
+
+// copyFailureSamples deep copies the samples captured this period (caller holds the lock)
+func (c *Collector) copyFailureSamples() map[string][]RequestSample {
+	if len(c.failureSamples) == 0 {
+		return nil
+	}
+	samples := make(map[string][]RequestSample, len(c.failureSamples))
+	for key, list := range c.failureSamples {
+		samples[key] = append([]RequestSample(nil), list...)
+	}
+	return samples
+}
@@ -0,0 +1,293 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SelfTestSource is the source of synthetic self-test traffic; checks record it
+// under this source (e.g. RecordRequest(SelfTestSource, ok)) so it is kept apart
+// from real traffic
+const SelfTestSource = "selftest"
+
+// Default self-test settings
+const (
+	defaultSelfTestInterval = 30 * time.Second
+	defaultSelfTestTimeout  = 5 * time.Second
+)
+
+// selfTestKey marks contexts of self-test checks
+type selfTestKey struct{}
+
+// WithSelfTest marks ctx as carrying synthetic self-test traffic
+func WithSelfTest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, selfTestKey{}, true)
+}
+
+// IsSelfTest reports whether ctx carries synthetic self-test traffic, so handlers
+// can skip side effects such as billing or subscriber updates
+func IsSelfTest(ctx context.Context) bool {
+	marked, _ := ctx.Value(selfTestKey{}).(bool)
+	return marked
+}
+
+// SelfTestCheck injects one synthetic request through the pipeline under test and
+// returns once it has completed end to end, or with the reason it didn't
+// Its ctx is marked with WithSelfTest and expires with the check timeout
+type SelfTestCheck func(ctx context.Context) error
+
+// SelfTestConfig configures a SelfTest
+type SelfTestConfig struct {
+	Interval time.Duration // Time between check rounds (default: 30s)
+	Timeout  time.Duration // Time a check has to complete, in real time (default: 5s)
+
+	// Clock schedules rounds and times checks (default: SystemClock)
+	Clock Clock
+}
+
+// SelfTestStats tracks the self-test checks of a node, in CustomMetrics["selftest"]
+type SelfTestStats struct {
+	Runs                uint64                        `json:"runs" stats:"counter"`   // Check executions
+	Passed              uint64                        `json:"passed" stats:"counter"` // Checks that completed
+	Failed              uint64                        `json:"failed" stats:"counter"` // Checks that returned an error or timed out
+	Timeouts            uint64                        `json:"timeouts" stats:"counter"`
+	ConsecutiveFailures uint64                        `json:"consecutive_failures" stats:"gauge"`           // Rounds in a row with a failed check
+	LastLatencyMs       float64                       `json:"last_latency_ms" stats:"gauge,omitzero"`       // Slowest check of the last round
+	SecondsSinceSuccess float64                       `json:"seconds_since_success" stats:"gauge,omitzero"` // Since the last round where every check passed
+	LastRun             time.Time                     `json:"last_run,omitempty"`
+	LastSuccess         time.Time                     `json:"last_success,omitempty"`
+	Checks              map[string]SelfTestCheckStats `json:"checks,omitempty"`
+}
+
+// SelfTestCheckStats tracks a single self-test check
+type SelfTestCheckStats struct {
+	Runs                uint64    `json:"runs" stats:"counter"`
+	Passed              uint64    `json:"passed" stats:"counter"`
+	Failed              uint64    `json:"failed" stats:"counter"`
+	ConsecutiveFailures uint64    `json:"consecutive_failures" stats:"gauge"`
+	LatencyMs           float64   `json:"latency_ms" stats:"gauge"` // Duration of the last execution
+	LastError           string    `json:"last_error,omitempty"`
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+}
+
+// SelfTest periodically runs registered synthetic checks to measure end-to-end
+// pipeline liveness; attach it with Collector.SetSelfTest to export its stats
+type SelfTest struct {
+	interval time.Duration
+	timeout  time.Duration
+	clock    Clock
+	mu       sync.RWMutex
+	checks   map[string]SelfTestCheck
+	stats    SelfTestStats
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+}
+
+// NewSelfTest creates a self-test without checks, see Register
+func NewSelfTest(cfg SelfTestConfig) *SelfTest {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultSelfTestInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSelfTestTimeout
+	}
+
+	return &SelfTest{
+		interval: cfg.Interval,
+		timeout:  cfg.Timeout,
+		clock:    clockOrSystem(cfg.Clock),
+		checks:   make(map[string]SelfTestCheck),
+	}
+}
+
+// Register adds a check run every round, replacing any check with the same name
+func (s *SelfTest) Register(name string, check SelfTestCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// Unregister removes a check; its stats are kept
+func (s *SelfTest) Unregister(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checks, name)
+}
+
+// Start runs a round of checks now and then every interval until Stop is called
+// or ctx is cancelled; it can be started again afterwards
+func (s *SelfTest) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = true
+	stopCh := make(chan struct{})
+	s.stopCh = stopCh
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		s.RunOnce(ctx)
+
+		ticker := s.clock.NewTicker(s.interval)
+		defer func() {
+			// Stopped by ctx: allow a new Start
+			s.mu.Lock()
+			if s.stopCh == stopCh {
+				s.running = false
+			}
+			s.mu.Unlock()
+			ticker.Stop()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopCh:
+				return
+			case <-ticker.C():
+				s.RunOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Stop halts the periodic checks, waiting for a running round
+func (s *SelfTest) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	stopCh := s.stopCh
+	s.mu.Unlock()
+
+	close(stopCh)
+	s.wg.Wait()
+}
+
+// RunOnce runs every registered check concurrently and records the results
+// Returns the failed checks' errors joined, nil if all passed
+func (s *SelfTest) RunOnce(ctx context.Context) error {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.checks))
+	for name := range s.checks {
+		names = append(names, name)
+	}
+	checks := make([]SelfTestCheck, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		checks[i] = s.checks[name]
+	}
+	s.mu.RUnlock()
+	if len(checks) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(checks))
+	durations := make([]time.Duration, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check SelfTestCheck) {
+			defer wg.Done()
+			start := s.clock.Now()
+			errs[i] = s.runCheck(ctx, check)
+			durations[i] = s.clock.Now().Sub(start)
+		}(i, check)
+	}
+	wg.Wait()
+
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stats.Checks == nil {
+		s.stats.Checks = make(map[string]SelfTestCheckStats, len(names))
+	}
+	var failed []error
+	var slowest time.Duration
+	for i, name := range names {
+		check := s.stats.Checks[name]
+		check.Runs++
+		check.LatencyMs = float64(durations[i]) / float64(time.Millisecond)
+		s.stats.Runs++
+		slowest = max(slowest, durations[i])
+
+		if err := errs[i]; err != nil {
+			check.Failed++
+			check.ConsecutiveFailures++
+			check.LastError = err.Error()
+			s.stats.Failed++
+			if errors.Is(err, context.DeadlineExceeded) {
+				s.stats.Timeouts++
+			}
+			failed = append(failed, fmt.Errorf("%s: %w", name, err))
+		} else {
+			check.Passed++
+			check.ConsecutiveFailures = 0
+			check.LastError = ""
+			check.LastSuccess = now
+			s.stats.Passed++
+		}
+		s.stats.Checks[name] = check
+	}
+
+	s.stats.LastRun = now
+	s.stats.LastLatencyMs = float64(slowest) / float64(time.Millisecond)
+	if len(failed) > 0 {
+		s.stats.ConsecutiveFailures++
+		return errors.Join(failed...)
+	}
+	s.stats.ConsecutiveFailures = 0
+	s.stats.LastSuccess = now
+	return nil
+}
+
+// runCheck runs check with the self-test marker, failing it if it doesn't
+// complete within the timeout
+func (s *SelfTest) runCheck(ctx context.Context, check SelfTestCheck) error {
+	ctx, cancel := context.WithTimeout(WithSelfTest(ctx), s.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("no completion within %s: %w", s.timeout, ctx.Err())
+	}
+}
+
+// Stats returns a copy of the self-test stats
+func (s *SelfTest) Stats() *SelfTestStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := s.stats
+	if !result.LastSuccess.IsZero() {
+		result.SecondsSinceSuccess = s.clock.Now().Sub(result.LastSuccess).Seconds()
+	}
+	if s.stats.Checks != nil {
+		result.Checks = make(map[string]SelfTestCheckStats, len(s.stats.Checks))
+		for name, check := range s.stats.Checks {
+			result.Checks[name] = check
+		}
+	}
+	return &result
+}
@@ -0,0 +1,326 @@
+package stats
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Semantics declares how a stats field behaves between export cycles. Fields
+// declare it with the stats struct tag:
+//
+//	stats:"counter"        only increases; deltas carry the change since the previous cycle
+//	stats:"gauge"          point-in-time value; deltas carry the current value
+//	stats:"gauge,omitzero" gauge only exported once set (e.g. not tracked by every collector)
+//	stats:"omitidle"       map whose entries without counter activity are dropped from deltas
+//	stats:"-"              not carried into deltas
+//
+// Counter and gauge apply to numeric fields and to maps of numbers (per-key
+// counters such as histograms). Gauge on any other field copies it as-is.
+// Untagged structs, pointers to structs and maps of structs are walked field by
+// field; other untagged fields (strings, timestamps, slices) are copied
+type Semantics int
+
+const (
+	Untracked Semantics = iota // No declared semantics
+	Gauge
+	Counter
+)
+
+// String returns the counter catalog type name of the semantics
+func (s Semantics) String() string {
+	switch s {
+	case Gauge:
+		return "gauge"
+	case Counter:
+		return "counter"
+	}
+	return "untracked"
+}
+
+// FieldSemantics is the parsed stats tag of a field
+type FieldSemantics struct {
+	Semantics Semantics
+	OmitZero  bool // Zero values are not exported
+	OmitIdle  bool // Map entries without counter activity are dropped from deltas
+	Ignore    bool // Not carried into deltas
+}
+
+// SkipZero reports whether a zero value of the field is left out of exports:
+// counters without activity and gauges declared omitzero
+func (f FieldSemantics) SkipZero() bool {
+	return f.Semantics == Counter || f.OmitZero
+}
+
+// SemanticsOf returns the declared semantics of a struct field
+func SemanticsOf(field reflect.StructField) FieldSemantics {
+	sem, _ := parseSemantics(field.Tag.Get("stats"))
+	return sem
+}
+
+// parseSemantics parses a stats struct tag
+func parseSemantics(tag string) (FieldSemantics, error) {
+	var sem FieldSemantics
+	if tag == "" {
+		return sem, nil
+	}
+	if tag == "-" {
+		sem.Ignore = true
+		return sem, nil
+	}
+
+	for i, part := range strings.Split(tag, ",") {
+		switch {
+		case i == 0 && part == "":
+		case i == 0 && part == "gauge":
+			sem.Semantics = Gauge
+		case i == 0 && part == "counter":
+			sem.Semantics = Counter
+		case part == "omitzero":
+			sem.OmitZero = true
+		case part == "omitidle":
+			sem.OmitIdle = true
+		case i == 0:
+			return sem, fmt.Errorf("unknown semantics %q", part)
+		default:
+			return sem, fmt.Errorf("unknown option %q", part)
+		}
+	}
+	if sem.OmitZero && sem.Semantics != Gauge {
+		return sem, fmt.Errorf("omitzero requires gauge semantics")
+	}
+	return sem, nil
+}
+
+// CheckSemantics reports stats tags that don't parse and numeric fields of v's
+// type without declared semantics, so a new field can't silently be delta'd or
+// filtered the wrong way. v is a struct or a pointer to one
+func CheckSemantics(v interface{}) error {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("stats: CheckSemantics requires a struct, got %T", v)
+	}
+
+	var problems []string
+	checkSemantics(t, t.Name(), make(map[reflect.Type]bool), &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("stats: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkSemantics collects the problems of the fields of struct type t
+func checkSemantics(t reflect.Type, path string, seen map[reflect.Type]bool, problems *[]string) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := path + "." + field.Name
+
+		sem, err := parseSemantics(field.Tag.Get("stats"))
+		if err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if sem.Ignore || sem.Semantics == Gauge {
+			continue
+		}
+
+		ft := field.Type
+		if sem.Semantics == Counter {
+			if !isNumeric(ft) && !(ft.Kind() == reflect.Map && isNumeric(ft.Elem())) {
+				*problems = append(*problems, fmt.Sprintf("%s: counter semantics on non-numeric %s", name, ft))
+			}
+			continue
+		}
+
+		switch {
+		case isNumeric(ft), ft.Kind() == reflect.Map && isNumeric(ft.Elem()):
+			*problems = append(*problems, fmt.Sprintf("%s: numeric field without stats tag", name))
+		case ft.Kind() == reflect.Struct && ft != timeType:
+			checkSemantics(ft, name, seen, problems)
+		case ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct:
+			checkSemantics(ft.Elem(), name, seen, problems)
+		case ft.Kind() == reflect.Map && ft.Elem().Kind() == reflect.Struct:
+			checkSemantics(ft.Elem(), name, seen, problems)
+		}
+	}
+}
+
+// Delta returns the change from prev to current: counters are subtracted, and
+// gauges and untracked values are taken from current. A counter below its
+// previous value was reset (e.g. the service restarted), so its delta is the
+// current value rather than zero. Map entries are matched by key; entries only
+// in prev are dropped
+func Delta[T any](current, prev T) T {
+	var delta T
+	deltaValue(reflect.ValueOf(&delta).Elem(), reflect.ValueOf(&current).Elem(), reflect.ValueOf(&prev).Elem(), FieldSemantics{})
+	return delta
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+
+	fieldSemanticsMu    sync.RWMutex
+	fieldSemanticsCache = map[reflect.Type][]FieldSemantics{}
+)
+
+// structSemantics returns the semantics of every field of struct type t
+func structSemantics(t reflect.Type) []FieldSemantics {
+	fieldSemanticsMu.RLock()
+	sems, ok := fieldSemanticsCache[t]
+	fieldSemanticsMu.RUnlock()
+	if ok {
+		return sems
+	}
+
+	sems = make([]FieldSemantics, t.NumField())
+	for i := range sems {
+		sems[i] = SemanticsOf(t.Field(i))
+	}
+	fieldSemanticsMu.Lock()
+	fieldSemanticsCache[t] = sems
+	fieldSemanticsMu.Unlock()
+	return sems
+}
+
+// deltaValue sets dst to the delta of curr and prev, which have dst's type
+func deltaValue(dst, curr, prev reflect.Value, sem FieldSemantics) {
+	t := curr.Type()
+
+	switch {
+	case sem.Ignore:
+		return
+
+	case sem.Semantics == Counter && isNumeric(t):
+		dst.Set(subtract(curr, prev))
+
+	case sem.Semantics == Counter && t.Kind() == reflect.Map:
+		if curr.IsNil() {
+			return
+		}
+		delta := reflect.MakeMapWithSize(t, curr.Len())
+		for iter := curr.MapRange(); iter.Next(); {
+			diff := subtract(iter.Value(), mapIndex(prev, iter.Key()))
+			if !diff.IsZero() {
+				delta.SetMapIndex(iter.Key(), diff)
+			}
+		}
+		dst.Set(delta)
+
+	case sem.Semantics == Gauge:
+		dst.Set(curr)
+
+	case t.Kind() == reflect.Struct && t != timeType:
+		sems := structSemantics(t)
+		for i := range sems {
+			if !dst.Field(i).CanSet() {
+				continue
+			}
+			deltaValue(dst.Field(i), curr.Field(i), prev.Field(i), sems[i])
+		}
+
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		if curr.IsNil() {
+			return
+		}
+		prevElem := reflect.Zero(t.Elem())
+		if !prev.IsNil() {
+			prevElem = prev.Elem()
+		}
+		delta := reflect.New(t.Elem())
+		deltaValue(delta.Elem(), curr.Elem(), prevElem, FieldSemantics{})
+		dst.Set(delta)
+
+	case t.Kind() == reflect.Map && t.Elem().Kind() == reflect.Struct:
+		if curr.IsNil() {
+			return
+		}
+		delta := reflect.MakeMapWithSize(t, curr.Len())
+		for iter := curr.MapRange(); iter.Next(); {
+			entry := reflect.New(t.Elem()).Elem()
+			deltaValue(entry, iter.Value(), mapIndex(prev, iter.Key()), FieldSemantics{})
+			if sem.OmitIdle && idle(entry) {
+				continue
+			}
+			delta.SetMapIndex(iter.Key(), entry)
+		}
+		dst.Set(delta)
+
+	default:
+		dst.Set(curr)
+	}
+}
+
+// mapIndex returns m[key], or the zero value when m is nil or lacks key
+func mapIndex(m, key reflect.Value) reflect.Value {
+	if !m.IsNil() {
+		if v := m.MapIndex(key); v.IsValid() {
+			return v
+		}
+	}
+	return reflect.Zero(m.Type().Elem())
+}
+
+// subtract returns curr - prev for numeric values, or curr if the counter was reset
+// (prev is larger)
+func subtract(curr, prev reflect.Value) reflect.Value {
+	diff := reflect.New(curr.Type()).Elem()
+	diff.Set(curr)
+	switch curr.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if curr.Uint() >= prev.Uint() {
+			diff.SetUint(curr.Uint() - prev.Uint())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if curr.Int() >= prev.Int() {
+			diff.SetInt(curr.Int() - prev.Int())
+		}
+	case reflect.Float32, reflect.Float64:
+		if curr.Float() >= prev.Float() {
+			diff.SetFloat(curr.Float() - prev.Float())
+		}
+	}
+	return diff
+}
+
+// idle reports whether a delta struct has no counter activity
+func idle(v reflect.Value) bool {
+	sems := structSemantics(v.Type())
+	for i, sem := range sems {
+		if sem.Semantics != Counter {
+			continue
+		}
+		f := v.Field(i)
+		if f.Kind() == reflect.Map {
+			if f.Len() > 0 {
+				return false
+			}
+		} else if !f.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether t is an integer or float type (time.Duration included)
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
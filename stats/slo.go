@@ -0,0 +1,172 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Default SLO error budget window
+	defaultSLOWindow = 24 * time.Hour
+
+	// Default SLO evaluation interval
+	defaultSLOEvaluationInterval = time.Minute
+
+	// Default latency percentile of an SLO latency objective
+	defaultSLOLatencyPercentile = 95
+)
+
+// SLOTarget is a service level objective for one operation, e.g. S13 ECR success
+// >= 99.9% and p95 latency <= 50ms
+type SLOTarget struct {
+	// Operation the objective applies to, as passed to RecordOperation
+	Operation string
+
+	// SuccessRatio is the minimum fraction of successful requests (e.g., 0.999; 0 = none)
+	SuccessRatio float64
+
+	// LatencyThreshold is the latency LatencyPercentile percent of requests must
+	// complete within (0 = no latency objective)
+	LatencyThreshold time.Duration
+
+	// LatencyPercentile of the latency objective (default: 95)
+	LatencyPercentile float64
+
+	// Window is the error budget window; counts and budgets restart when it ends (default: 24h)
+	Window time.Duration
+
+	// EvaluationInterval is the unit of compliance time: each interval is judged
+	// compliant or violating on its own requests (default: 1m)
+	EvaluationInterval time.Duration
+}
+
+// sloTracker tracks one SLO; it has its own lock so snapshots can advance it
+type sloTracker struct {
+	mu     sync.Mutex
+	target SLOTarget
+
+	windowStart   time.Time
+	intervalStart time.Time
+
+	// Requests in the window and in the current evaluation interval
+	total, failed, slow          uint64
+	intTotal, intFailed, intSlow uint64
+	compliant                    bool
+	compliance, violation        time.Duration
+}
+
+// newSLOTracker creates a tracker whose first window starts at now
+func newSLOTracker(target SLOTarget, now time.Time) *sloTracker {
+	if target.Window <= 0 {
+		target.Window = defaultSLOWindow
+	}
+	if target.EvaluationInterval <= 0 {
+		target.EvaluationInterval = defaultSLOEvaluationInterval
+	}
+	if target.LatencyPercentile <= 0 || target.LatencyPercentile >= 100 {
+		target.LatencyPercentile = defaultSLOLatencyPercentile
+	}
+
+	return &sloTracker{
+		target:        target,
+		windowStart:   now,
+		intervalStart: now,
+		compliant:     true,
+	}
+}
+
+// record counts one request
+func (t *sloTracker) record(now time.Time, success bool, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance(now)
+
+	t.total++
+	t.intTotal++
+	if !success {
+		t.failed++
+		t.intFailed++
+	}
+	if t.target.LatencyThreshold > 0 && d > t.target.LatencyThreshold {
+		t.slow++
+		t.intSlow++
+	}
+}
+
+// advance judges the evaluation intervals that ended by now and starts a new
+// budget window when the current one ends (caller holds t.mu)
+func (t *sloTracker) advance(now time.Time) {
+	interval := t.target.EvaluationInterval
+	for end := t.intervalStart.Add(interval); !now.Before(end); end = t.intervalStart.Add(interval) {
+		t.compliant = t.meets(t.intTotal, t.intFailed, t.intSlow)
+		if t.compliant {
+			t.compliance += interval
+		} else {
+			t.violation += interval
+		}
+		t.intTotal, t.intFailed, t.intSlow = 0, 0, 0
+		t.intervalStart = end
+
+		if !end.Before(t.windowStart.Add(t.target.Window)) {
+			t.windowStart = end
+			t.total, t.failed, t.slow = 0, 0, 0
+			t.compliance, t.violation = 0, 0
+		}
+	}
+}
+
+// meets reports whether requests with the given failures and slow requests meet the SLO
+func (t *sloTracker) meets(total, failed, slow uint64) bool {
+	if total == 0 {
+		return true
+	}
+	if t.target.SuccessRatio > 0 && float64(total-failed)/float64(total) < t.target.SuccessRatio {
+		return false
+	}
+	if t.target.LatencyThreshold > 0 && float64(slow)/float64(total) > 1-t.target.LatencyPercentile/100 {
+		return false
+	}
+	return true
+}
+
+// snapshot returns the SLO's state at now
+func (t *sloTracker) snapshot(now time.Time) SLOStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance(now)
+
+	s := SLOStats{
+		SuccessTarget:          t.target.SuccessRatio,
+		WindowStart:            t.windowStart,
+		Total:                  t.total,
+		Failed:                 t.failed,
+		Slow:                   t.slow,
+		Compliant:              t.compliant,
+		ComplianceSeconds:      uint64(t.compliance / time.Second),
+		ViolationSeconds:       uint64(t.violation / time.Second),
+		ErrorBudgetRemaining:   1,
+		LatencyBudgetRemaining: 1,
+	}
+	if t.target.LatencyThreshold > 0 {
+		s.LatencyTargetMs = float64(t.target.LatencyThreshold) / float64(time.Millisecond)
+		s.LatencyPercentile = t.target.LatencyPercentile
+		s.LatencyBudgetRemaining = budgetRemaining(t.slow, t.total, 1-t.target.LatencyPercentile/100)
+	}
+	if t.target.SuccessRatio > 0 {
+		s.ErrorBudgetRemaining = budgetRemaining(t.failed, t.total, 1-t.target.SuccessRatio)
+	}
+	return s
+}
+
+// budgetRemaining returns the fraction of the budget (allowed share of total) not
+// spent by used; negative once overspent
+func budgetRemaining(used, total uint64, allowedShare float64) float64 {
+	allowed := allowedShare * float64(total)
+	if allowed <= 0 {
+		if used > 0 {
+			return 0
+		}
+		return 1
+	}
+	return 1 - float64(used)/allowed
+}
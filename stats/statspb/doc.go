@@ -0,0 +1,7 @@
+// Package statspb implements the protobuf wire format for ServiceStats snapshots and
+// exported MetricRecords, as defined in stats.proto
+//
+// The codec is hand-written against the stats domain types so the module does not
+// depend on the protobuf runtime; output is wire-compatible with protoc-generated
+// bindings for stats.proto in any language.
+package statspb
@@ -0,0 +1,249 @@
+package statspb
+
+import (
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+func encodeEIR(e *encoder, s *statsmodel.EIRStats) {
+	e.message(1, func(m *encoder) { encodeEquipmentChecks(m, s.EquipmentChecks) })
+	e.message(2, func(m *encoder) { encodeDatabaseOps(m, s.DatabaseOps) })
+	e.message(3, func(m *encoder) { encodeCache(m, s.CacheStats) })
+	stringMap(e, 4, s.ByEquipmentStatus, uint64Value)
+	stringMap(e, 5, s.StatusTransitions, uint64Value)
+	for _, change := range s.RecentChanges {
+		e.message(6, func(m *encoder) {
+			m.string(1, change.IMEI)
+			m.string(2, change.From)
+			m.string(3, change.To)
+			m.time(4, change.Timestamp)
+		})
+	}
+	stringMap(e, 7, s.ByTAC, func(m *encoder, t statsmodel.TACStats) {
+		m.message(2, func(m *encoder) {
+			m.uint64(1, t.Checks)
+			stringMap(m, 2, t.ByStatus, uint64Value)
+		})
+	})
+}
+
+func decodeEIR(b []byte, s *statsmodel.EIRStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			return decodeEquipmentChecks(f.b, &s.EquipmentChecks)
+		case 2:
+			return decodeDatabaseOps(f.b, &s.DatabaseOps)
+		case 3:
+			return decodeCache(f.b, &s.CacheStats)
+		case 4:
+			return decodeUint64Entry(f.b, &s.ByEquipmentStatus)
+		case 5:
+			return decodeUint64Entry(f.b, &s.StatusTransitions)
+		case 6:
+			var change statsmodel.StatusChange
+			err := walk(f.b, func(f field) error {
+				switch f.num {
+				case 1:
+					change.IMEI = f.string()
+				case 2:
+					change.From = f.string()
+				case 3:
+					change.To = f.string()
+				case 4:
+					change.Timestamp = f.time()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			s.RecentChanges = append(s.RecentChanges, change)
+		case 7:
+			if s.ByTAC == nil {
+				s.ByTAC = make(map[string]statsmodel.TACStats)
+			}
+			var t statsmodel.TACStats
+			key, err := decodeStringEntry(f.b, func(v field) error {
+				return walk(v.b, func(f field) error {
+					switch f.num {
+					case 1:
+						t.Checks = f.uint64()
+					case 2:
+						return decodeUint64Entry(f.b, &t.ByStatus)
+					}
+					return nil
+				})
+			})
+			s.ByTAC[key] = t
+			return err
+		}
+		return nil
+	})
+}
+
+func encodeEquipmentChecks(e *encoder, c statsmodel.EquipmentCheckStats) {
+	e.uint64(1, c.Total)
+	e.uint64(2, c.Success)
+	e.uint64(3, c.Failed)
+	stringMap(e, 4, c.ByInterface, func(m *encoder, i statsmodel.InterfaceCheckStats) {
+		m.message(2, func(m *encoder) {
+			m.uint64(1, i.Total)
+			m.uint64(2, i.Success)
+			m.uint64(3, i.Failed)
+			m.intMap(4, i.ByResultCode)
+		})
+	})
+}
+
+func decodeEquipmentChecks(b []byte, c *statsmodel.EquipmentCheckStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			c.Total = f.uint64()
+		case 2:
+			c.Success = f.uint64()
+		case 3:
+			c.Failed = f.uint64()
+		case 4:
+			if c.ByInterface == nil {
+				c.ByInterface = make(map[string]statsmodel.InterfaceCheckStats)
+			}
+			var i statsmodel.InterfaceCheckStats
+			key, err := decodeStringEntry(f.b, func(v field) error {
+				return walk(v.b, func(f field) error {
+					switch f.num {
+					case 1:
+						i.Total = f.uint64()
+					case 2:
+						i.Success = f.uint64()
+					case 3:
+						i.Failed = f.uint64()
+					case 4:
+						code, count, err := decodeIntEntry(f.b)
+						if err != nil {
+							return err
+						}
+						if i.ByResultCode == nil {
+							i.ByResultCode = make(map[int]uint64)
+						}
+						i.ByResultCode[code] = count
+					}
+					return nil
+				})
+			})
+			c.ByInterface[key] = i
+			return err
+		}
+		return nil
+	})
+}
+
+func encodeDatabaseOps(e *encoder, d statsmodel.DatabaseOperationStats) {
+	e.uint64(1, d.Queries)
+	e.uint64(2, d.Inserts)
+	e.uint64(3, d.Updates)
+	e.uint64(4, d.Deletes)
+	e.uint64(5, d.Errors)
+	e.double(6, d.AvgLatencyMs)
+	e.uint64(7, d.ActiveQueries)
+	stringMap(e, 8, d.ByOperation, encodeLatencyValue)
+	stringMap(e, 9, d.ByTable, encodeBreakdownValue)
+	stringMap(e, 10, d.ByQueryName, encodeBreakdownValue)
+}
+
+func decodeDatabaseOps(b []byte, d *statsmodel.DatabaseOperationStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			d.Queries = f.uint64()
+		case 2:
+			d.Inserts = f.uint64()
+		case 3:
+			d.Updates = f.uint64()
+		case 4:
+			d.Deletes = f.uint64()
+		case 5:
+			d.Errors = f.uint64()
+		case 6:
+			d.AvgLatencyMs = f.double()
+		case 7:
+			d.ActiveQueries = f.uint64()
+		case 8:
+			return decodeLatencyEntry(f.b, &d.ByOperation)
+		case 9:
+			return decodeBreakdownEntry(f.b, &d.ByTable)
+		case 10:
+			return decodeBreakdownEntry(f.b, &d.ByQueryName)
+		}
+		return nil
+	})
+}
+
+func encodeBreakdownValue(e *encoder, d statsmodel.DBBreakdownStats) {
+	e.message(2, func(m *encoder) {
+		m.uint64(1, d.Operations)
+		m.uint64(2, d.Errors)
+		m.double(3, d.AvgLatencyMs)
+		m.double(4, d.MaxLatencyMs)
+	})
+}
+
+func decodeBreakdownEntry(b []byte, m *map[string]statsmodel.DBBreakdownStats) error {
+	if *m == nil {
+		*m = make(map[string]statsmodel.DBBreakdownStats)
+	}
+
+	var d statsmodel.DBBreakdownStats
+	key, err := decodeStringEntry(b, func(v field) error {
+		return walk(v.b, func(f field) error {
+			switch f.num {
+			case 1:
+				d.Operations = f.uint64()
+			case 2:
+				d.Errors = f.uint64()
+			case 3:
+				d.AvgLatencyMs = f.double()
+			case 4:
+				d.MaxLatencyMs = f.double()
+			}
+			return nil
+		})
+	})
+	(*m)[key] = d
+	return err
+}
+
+func encodeCache(e *encoder, c statsmodel.CacheStats) {
+	e.uint64(1, c.Hits)
+	e.uint64(2, c.Misses)
+	e.double(3, c.HitRate)
+	e.uint64(4, c.Size)
+	e.uint64(5, c.MaxSize)
+	e.uint64(6, c.Evictions)
+	e.uint64(7, c.Expirations)
+	e.uint64(8, c.Bytes)
+}
+
+func decodeCache(b []byte, c *statsmodel.CacheStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			c.Hits = f.uint64()
+		case 2:
+			c.Misses = f.uint64()
+		case 3:
+			c.HitRate = f.double()
+		case 4:
+			c.Size = f.uint64()
+		case 5:
+			c.MaxSize = f.uint64()
+		case 6:
+			c.Evictions = f.uint64()
+		case 7:
+			c.Expirations = f.uint64()
+		case 8:
+			c.Bytes = f.uint64()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package statspb
+
+import (
+	"github.com/hsdfat/telco/stats/export"
+)
+
+// MarshalMetricRecord encodes record as a telco.stats.v1.MetricRecord message
+func MarshalMetricRecord(record export.MetricRecord) []byte {
+	var e encoder
+	encodeMetricRecord(&e, record)
+	return e.buf
+}
+
+// UnmarshalMetricRecord decodes a telco.stats.v1.MetricRecord message
+func UnmarshalMetricRecord(b []byte) (export.MetricRecord, error) {
+	var record export.MetricRecord
+	err := decodeMetricRecord(b, &record)
+	return record, err
+}
+
+// MarshalMetricBatch encodes records as a telco.stats.v1.MetricBatch message
+func MarshalMetricBatch(records []export.MetricRecord) []byte {
+	var e encoder
+	for _, record := range records {
+		e.message(1, func(m *encoder) { encodeMetricRecord(m, record) })
+	}
+	return e.buf
+}
+
+// UnmarshalMetricBatch decodes a telco.stats.v1.MetricBatch message
+func UnmarshalMetricBatch(b []byte) ([]export.MetricRecord, error) {
+	var records []export.MetricRecord
+	err := walk(b, func(f field) error {
+		if f.num != 1 {
+			return nil
+		}
+		var record export.MetricRecord
+		if err := decodeMetricRecord(f.b, &record); err != nil {
+			return err
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func encodeMetricRecord(e *encoder, r export.MetricRecord) {
+	e.int64(1, int64(r.CounterID))
+	e.uint64(2, r.Value)
+	e.int64(3, int64(r.CauseCode))
+	e.string(4, r.Hostname)
+	e.string(5, r.SystemName)
+	e.time(6, r.Timestamp)
+	e.string(7, r.Tenant)
+}
+
+func decodeMetricRecord(b []byte, r *export.MetricRecord) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			r.CounterID = int(int32(f.v))
+		case 2:
+			r.Value = f.uint64()
+		case 3:
+			r.CauseCode = int(int32(f.v))
+		case 4:
+			r.Hostname = f.string()
+		case 5:
+			r.SystemName = f.string()
+		case 6:
+			r.Timestamp = f.time()
+		case 7:
+			r.Tenant = f.string()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,607 @@
+package statspb
+
+import (
+	statsmodel "github.com/hsdfat/telco/stats"
+)
+
+// MarshalServiceStats encodes stats as a telco.stats.v1.ServiceStats message
+// Typed sections are encoded field by field; CustomMetrics["eir"] is carried as the
+// eir field, while other untyped InterfaceStats/CustomMetrics entries are not encoded
+func MarshalServiceStats(stats *statsmodel.ServiceStats) []byte {
+	var e encoder
+	if stats != nil {
+		encodeServiceStats(&e, stats)
+	}
+	return e.buf
+}
+
+// UnmarshalServiceStats decodes a telco.stats.v1.ServiceStats message
+func UnmarshalServiceStats(b []byte) (*statsmodel.ServiceStats, error) {
+	stats := &statsmodel.ServiceStats{}
+	if err := decodeServiceStats(b, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func encodeServiceStats(e *encoder, s *statsmodel.ServiceStats) {
+	e.string(1, s.ServiceName)
+	e.string(2, s.ServiceVersion)
+	e.string(3, s.Uptime)
+	e.uint64(4, s.UptimeSeconds)
+	e.time(5, s.StartTime)
+	e.time(6, s.Timestamp)
+	e.message(7, func(m *encoder) { encodeConnections(m, s.Connections) })
+	e.message(8, func(m *encoder) { encodeRequests(m, s.Requests) })
+	e.message(9, func(m *encoder) { encodePerformance(m, s.Performance) })
+	e.message(10, func(m *encoder) { encodeErrors(m, s.Errors) })
+	if s.Runtime != nil {
+		e.message(11, func(m *encoder) { encodeRuntime(m, s.Runtime) })
+	}
+	stringMap(e, 12, s.Peers, func(m *encoder, p statsmodel.PeerStats) {
+		m.message(2, func(m *encoder) { encodePeer(m, p) })
+	})
+	if s.SCTP != nil {
+		e.message(13, func(m *encoder) { encodeSCTP(m, s.SCTP) })
+	}
+	if s.Overload != nil {
+		e.message(14, func(m *encoder) { encodeOverload(m, s.Overload) })
+	}
+	if s.Capacity != nil {
+		e.message(15, func(m *encoder) { encodeCapacity(m, s.Capacity) })
+	}
+	if eir, ok := s.CustomMetrics["eir"].(*statsmodel.EIRStats); ok && eir != nil {
+		e.message(16, func(m *encoder) { encodeEIR(m, eir) })
+	}
+}
+
+func decodeServiceStats(b []byte, s *statsmodel.ServiceStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			s.ServiceName = f.string()
+		case 2:
+			s.ServiceVersion = f.string()
+		case 3:
+			s.Uptime = f.string()
+		case 4:
+			s.UptimeSeconds = f.uint64()
+		case 5:
+			s.StartTime = f.time()
+		case 6:
+			s.Timestamp = f.time()
+		case 7:
+			return decodeConnections(f.b, &s.Connections)
+		case 8:
+			return decodeRequests(f.b, &s.Requests)
+		case 9:
+			return decodePerformance(f.b, &s.Performance)
+		case 10:
+			return decodeErrors(f.b, &s.Errors)
+		case 11:
+			s.Runtime = &statsmodel.GoRuntimeStats{}
+			return decodeRuntime(f.b, s.Runtime)
+		case 12:
+			if s.Peers == nil {
+				s.Peers = make(map[string]statsmodel.PeerStats)
+			}
+			var p statsmodel.PeerStats
+			key, err := decodeStringEntry(f.b, func(v field) error { return decodePeer(v.b, &p) })
+			s.Peers[key] = p
+			return err
+		case 13:
+			s.SCTP = &statsmodel.SCTPStats{}
+			return decodeSCTP(f.b, s.SCTP)
+		case 14:
+			s.Overload = &statsmodel.OverloadStats{}
+			return decodeOverload(f.b, s.Overload)
+		case 15:
+			s.Capacity = &statsmodel.CapacityStats{}
+			return decodeCapacity(f.b, s.Capacity)
+		case 16:
+			eir := &statsmodel.EIRStats{}
+			if s.CustomMetrics == nil {
+				s.CustomMetrics = make(map[string]interface{})
+			}
+			s.CustomMetrics["eir"] = eir
+			return decodeEIR(f.b, eir)
+		}
+		return nil
+	})
+}
+
+func encodeConnections(e *encoder, c statsmodel.ConnectionStats) {
+	e.uint64(1, c.Total)
+	e.uint64(2, c.Active)
+	e.uint64(3, c.Failed)
+	e.uint64(4, c.Closed)
+	stringMap(e, 5, c.ByListener, func(m *encoder, l statsmodel.ListenerStats) {
+		m.message(2, func(m *encoder) {
+			m.uint64(1, l.Active)
+			m.uint64(2, l.Total)
+			m.uint64(3, l.Failed)
+			m.uint64(4, l.Closed)
+		})
+	})
+}
+
+func decodeConnections(b []byte, c *statsmodel.ConnectionStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			c.Total = f.uint64()
+		case 2:
+			c.Active = f.uint64()
+		case 3:
+			c.Failed = f.uint64()
+		case 4:
+			c.Closed = f.uint64()
+		case 5:
+			if c.ByListener == nil {
+				c.ByListener = make(map[string]statsmodel.ListenerStats)
+			}
+			var l statsmodel.ListenerStats
+			key, err := decodeStringEntry(f.b, func(v field) error {
+				return walk(v.b, func(f field) error {
+					switch f.num {
+					case 1:
+						l.Active = f.uint64()
+					case 2:
+						l.Total = f.uint64()
+					case 3:
+						l.Failed = f.uint64()
+					case 4:
+						l.Closed = f.uint64()
+					}
+					return nil
+				})
+			})
+			c.ByListener[key] = l
+			return err
+		}
+		return nil
+	})
+}
+
+func encodePeer(e *encoder, p statsmodel.PeerStats) {
+	e.string(1, p.State)
+	e.time(2, p.ConnectedSince)
+	e.uint64(3, p.UptimeSeconds)
+	e.uint64(4, p.DWRFailures)
+	e.uint64(5, p.MessagesSent)
+	e.uint64(6, p.MessagesRecv)
+	e.uint64(7, p.Disconnects)
+	e.string(8, p.LastDisconnectCause)
+	e.time(9, p.LastDisconnectAt)
+}
+
+func decodePeer(b []byte, p *statsmodel.PeerStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			p.State = f.string()
+		case 2:
+			p.ConnectedSince = f.time()
+		case 3:
+			p.UptimeSeconds = f.uint64()
+		case 4:
+			p.DWRFailures = f.uint64()
+		case 5:
+			p.MessagesSent = f.uint64()
+		case 6:
+			p.MessagesRecv = f.uint64()
+		case 7:
+			p.Disconnects = f.uint64()
+		case 8:
+			p.LastDisconnectCause = f.string()
+		case 9:
+			p.LastDisconnectAt = f.time()
+		}
+		return nil
+	})
+}
+
+func encodeSCTP(e *encoder, s *statsmodel.SCTPStats) {
+	e.uint64(1, s.ActiveAssociations)
+	e.uint64(2, s.Establishes)
+	e.uint64(3, s.Aborts)
+	e.uint64(4, s.Shutdowns)
+	e.uint64(5, s.PathFailovers)
+	e.uint64(6, s.Retransmits)
+	e.uint64(7, s.GapAcks)
+}
+
+func decodeSCTP(b []byte, s *statsmodel.SCTPStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			s.ActiveAssociations = f.uint64()
+		case 2:
+			s.Establishes = f.uint64()
+		case 3:
+			s.Aborts = f.uint64()
+		case 4:
+			s.Shutdowns = f.uint64()
+		case 5:
+			s.PathFailovers = f.uint64()
+		case 6:
+			s.Retransmits = f.uint64()
+		case 7:
+			s.GapAcks = f.uint64()
+		}
+		return nil
+	})
+}
+
+func encodeOverload(e *encoder, o *statsmodel.OverloadStats) {
+	e.uint64(1, o.LoadLevel)
+	e.uint64(2, o.Throttled)
+	e.uint64(3, o.Rejected)
+	stringMap(e, 4, o.ByInterface, func(m *encoder, i statsmodel.InterfaceOverloadStats) {
+		m.message(2, func(m *encoder) {
+			m.uint64(1, i.Throttled)
+			m.uint64(2, i.Rejected)
+		})
+	})
+}
+
+func decodeOverload(b []byte, o *statsmodel.OverloadStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			o.LoadLevel = f.uint64()
+		case 2:
+			o.Throttled = f.uint64()
+		case 3:
+			o.Rejected = f.uint64()
+		case 4:
+			if o.ByInterface == nil {
+				o.ByInterface = make(map[string]statsmodel.InterfaceOverloadStats)
+			}
+			var i statsmodel.InterfaceOverloadStats
+			key, err := decodeStringEntry(f.b, func(v field) error {
+				return walk(v.b, func(f field) error {
+					switch f.num {
+					case 1:
+						i.Throttled = f.uint64()
+					case 2:
+						i.Rejected = f.uint64()
+					}
+					return nil
+				})
+			})
+			o.ByInterface[key] = i
+			return err
+		}
+		return nil
+	})
+}
+
+func encodeCapacity(e *encoder, c *statsmodel.CapacityStats) {
+	e.uint64(1, c.LicensedTPS)
+	e.double(2, c.PeakTPS)
+	e.uint64(3, c.LicensedSubscribers)
+	e.uint64(4, c.ProvisionedSubscribers)
+}
+
+func decodeCapacity(b []byte, c *statsmodel.CapacityStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			c.LicensedTPS = f.uint64()
+		case 2:
+			c.PeakTPS = f.double()
+		case 3:
+			c.LicensedSubscribers = f.uint64()
+		case 4:
+			c.ProvisionedSubscribers = f.uint64()
+		}
+		return nil
+	})
+}
+
+func encodeRequests(e *encoder, r statsmodel.RequestStats) {
+	e.uint64(1, r.Total)
+	e.uint64(2, r.Success)
+	e.uint64(3, r.Failed)
+	e.uint64(4, r.Pending)
+	e.uint64(5, r.MaxPending)
+	e.uint64(6, r.BytesSent)
+	e.uint64(7, r.BytesRecv)
+	stringMap(e, 8, r.BySource, func(m *encoder, s statsmodel.SourceStats) {
+		m.message(2, func(m *encoder) {
+			m.uint64(1, s.Total)
+			m.uint64(2, s.Success)
+			m.uint64(3, s.Failed)
+			m.uint64(4, s.InFlight)
+			m.uint64(5, s.BytesSent)
+			m.uint64(6, s.BytesRecv)
+			m.intMap(7, s.SentSizes)
+			m.intMap(8, s.RecvSizes)
+		})
+	})
+	stringMap(e, 9, r.ByOperation, func(m *encoder, o statsmodel.OperationStats) {
+		m.message(2, func(m *encoder) {
+			m.uint64(1, o.Total)
+			m.uint64(2, o.Success)
+			m.uint64(3, o.Failed)
+			m.double(4, o.AvgLatencyMs)
+		})
+	})
+}
+
+func decodeRequests(b []byte, r *statsmodel.RequestStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			r.Total = f.uint64()
+		case 2:
+			r.Success = f.uint64()
+		case 3:
+			r.Failed = f.uint64()
+		case 4:
+			r.Pending = f.uint64()
+		case 5:
+			r.MaxPending = f.uint64()
+		case 6:
+			r.BytesSent = f.uint64()
+		case 7:
+			r.BytesRecv = f.uint64()
+		case 8:
+			if r.BySource == nil {
+				r.BySource = make(map[string]statsmodel.SourceStats)
+			}
+			var s statsmodel.SourceStats
+			key, err := decodeStringEntry(f.b, func(v field) error { return decodeSource(v.b, &s) })
+			r.BySource[key] = s
+			return err
+		case 9:
+			if r.ByOperation == nil {
+				r.ByOperation = make(map[string]statsmodel.OperationStats)
+			}
+			var o statsmodel.OperationStats
+			key, err := decodeStringEntry(f.b, func(v field) error {
+				return walk(v.b, func(f field) error {
+					switch f.num {
+					case 1:
+						o.Total = f.uint64()
+					case 2:
+						o.Success = f.uint64()
+					case 3:
+						o.Failed = f.uint64()
+					case 4:
+						o.AvgLatencyMs = f.double()
+					}
+					return nil
+				})
+			})
+			r.ByOperation[key] = o
+			return err
+		}
+		return nil
+	})
+}
+
+func decodeSource(b []byte, s *statsmodel.SourceStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			s.Total = f.uint64()
+		case 2:
+			s.Success = f.uint64()
+		case 3:
+			s.Failed = f.uint64()
+		case 4:
+			s.InFlight = f.uint64()
+		case 5:
+			s.BytesSent = f.uint64()
+		case 6:
+			s.BytesRecv = f.uint64()
+		case 7, 8:
+			key, count, err := decodeIntEntry(f.b)
+			if err != nil {
+				return err
+			}
+			sizes := &s.SentSizes
+			if f.num == 8 {
+				sizes = &s.RecvSizes
+			}
+			if *sizes == nil {
+				*sizes = make(map[int]uint64)
+			}
+			(*sizes)[key] = count
+		}
+		return nil
+	})
+}
+
+func encodePerformance(e *encoder, p statsmodel.PerformanceStats) {
+	e.double(1, p.RequestsPerSecond)
+	e.double(2, p.AvgLatencyMs)
+	e.double(3, p.MinLatencyMs)
+	e.double(4, p.MaxLatencyMs)
+	e.double(5, p.P50LatencyMs)
+	e.double(6, p.P95LatencyMs)
+	e.double(7, p.P99LatencyMs)
+	stringMap(e, 8, p.BySource, encodeLatencyValue)
+	stringMap(e, 9, p.ByOperation, encodeLatencyValue)
+}
+
+func decodePerformance(b []byte, p *statsmodel.PerformanceStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			p.RequestsPerSecond = f.double()
+		case 2:
+			p.AvgLatencyMs = f.double()
+		case 3:
+			p.MinLatencyMs = f.double()
+		case 4:
+			p.MaxLatencyMs = f.double()
+		case 5:
+			p.P50LatencyMs = f.double()
+		case 6:
+			p.P95LatencyMs = f.double()
+		case 7:
+			p.P99LatencyMs = f.double()
+		case 8:
+			return decodeLatencyEntry(f.b, &p.BySource)
+		case 9:
+			return decodeLatencyEntry(f.b, &p.ByOperation)
+		}
+		return nil
+	})
+}
+
+func encodeLatencyValue(e *encoder, l statsmodel.LatencyStats) {
+	e.message(2, func(m *encoder) {
+		m.uint64(1, l.Count)
+		m.double(2, l.AvgLatencyMs)
+		m.double(3, l.MinLatencyMs)
+		m.double(4, l.MaxLatencyMs)
+		m.double(5, l.P50LatencyMs)
+		m.double(6, l.P95LatencyMs)
+		m.double(7, l.P99LatencyMs)
+	})
+}
+
+func decodeLatencyEntry(b []byte, m *map[string]statsmodel.LatencyStats) error {
+	if *m == nil {
+		*m = make(map[string]statsmodel.LatencyStats)
+	}
+
+	var l statsmodel.LatencyStats
+	key, err := decodeStringEntry(b, func(v field) error {
+		return walk(v.b, func(f field) error {
+			switch f.num {
+			case 1:
+				l.Count = f.uint64()
+			case 2:
+				l.AvgLatencyMs = f.double()
+			case 3:
+				l.MinLatencyMs = f.double()
+			case 4:
+				l.MaxLatencyMs = f.double()
+			case 5:
+				l.P50LatencyMs = f.double()
+			case 6:
+				l.P95LatencyMs = f.double()
+			case 7:
+				l.P99LatencyMs = f.double()
+			}
+			return nil
+		})
+	})
+	(*m)[key] = l
+	return err
+}
+
+func encodeErrors(e *encoder, s statsmodel.ErrorStats) {
+	e.uint64(1, s.Total)
+	stringMap(e, 2, s.ByType, uint64Value)
+	stringMap(e, 3, s.ByInterface, uint64Value)
+	if s.LastError != nil {
+		e.message(4, func(m *encoder) { encodeErrorInfo(m, *s.LastError) })
+	}
+	for _, info := range s.Recent {
+		e.message(5, func(m *encoder) { encodeErrorInfo(m, info) })
+	}
+}
+
+func decodeErrors(b []byte, s *statsmodel.ErrorStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			s.Total = f.uint64()
+		case 2:
+			return decodeUint64Entry(f.b, &s.ByType)
+		case 3:
+			return decodeUint64Entry(f.b, &s.ByInterface)
+		case 4:
+			s.LastError = &statsmodel.ErrorInfo{}
+			return decodeErrorInfo(f.b, s.LastError)
+		case 5:
+			var info statsmodel.ErrorInfo
+			if err := decodeErrorInfo(f.b, &info); err != nil {
+				return err
+			}
+			s.Recent = append(s.Recent, info)
+		}
+		return nil
+	})
+}
+
+func encodeErrorInfo(e *encoder, info statsmodel.ErrorInfo) {
+	e.string(1, info.Message)
+	e.string(2, info.Code)
+	e.string(3, info.Interface)
+	e.time(4, info.FirstSeen)
+	e.time(5, info.Timestamp)
+	e.uint64(6, info.Count)
+}
+
+func decodeErrorInfo(b []byte, info *statsmodel.ErrorInfo) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			info.Message = f.string()
+		case 2:
+			info.Code = f.string()
+		case 3:
+			info.Interface = f.string()
+		case 4:
+			info.FirstSeen = f.time()
+		case 5:
+			info.Timestamp = f.time()
+		case 6:
+			info.Count = f.uint64()
+		}
+		return nil
+	})
+}
+
+func decodeUint64Entry(b []byte, m *map[string]uint64) error {
+	if *m == nil {
+		*m = make(map[string]uint64)
+	}
+
+	var count uint64
+	key, err := decodeStringEntry(b, func(v field) error {
+		count = v.uint64()
+		return nil
+	})
+	(*m)[key] = count
+	return err
+}
+
+func encodeRuntime(e *encoder, r *statsmodel.GoRuntimeStats) {
+	e.uint64(1, r.Goroutines)
+	e.uint64(2, r.HeapInUseBytes)
+	e.uint64(3, r.HeapObjects)
+	e.uint64(4, r.NumGC)
+	e.double(5, r.GCPauseP99Ms)
+	e.double(6, r.CPUPercent)
+	e.int64(7, int64(r.GOMAXPROCS))
+}
+
+func decodeRuntime(b []byte, r *statsmodel.GoRuntimeStats) error {
+	return walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			r.Goroutines = f.uint64()
+		case 2:
+			r.HeapInUseBytes = f.uint64()
+		case 3:
+			r.HeapObjects = f.uint64()
+		case 4:
+			r.NumGC = f.uint64()
+		case 5:
+			r.GCPauseP99Ms = f.double()
+		case 6:
+			r.CPUPercent = f.double()
+		case 7:
+			r.GOMAXPROCS = int(f.int64())
+		}
+		return nil
+	})
+}
@@ -0,0 +1,129 @@
+package statspb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	statsmodel "github.com/hsdfat/telco/stats"
+	"github.com/hsdfat/telco/stats/export"
+)
+
+func TestServiceStats_RoundTrip(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	lastErr := statsmodel.ErrorInfo{Message: "timeout", Code: "5012", Interface: "s13", FirstSeen: now.Add(-time.Minute), Timestamp: now, Count: 3}
+
+	in := &statsmodel.ServiceStats{
+		ServiceName:    "eir",
+		ServiceVersion: "1.2.3",
+		Uptime:         "1h0m0s",
+		UptimeSeconds:  3600,
+		StartTime:      now.Add(-time.Hour),
+		Timestamp:      now,
+		Connections: statsmodel.ConnectionStats{
+			Total: 10, Active: 4, Failed: 1, Closed: 5,
+			ByListener: map[string]statsmodel.ListenerStats{"diameter": {Active: 2, Total: 6}},
+		},
+		Requests: statsmodel.RequestStats{
+			Total: 100, Success: 95, Failed: 5, Pending: 2, MaxPending: 9, BytesSent: 4096, BytesRecv: 2048,
+			BySource: map[string]statsmodel.SourceStats{
+				"mme-1": {Total: 60, Success: 58, Failed: 2, InFlight: 1, SentSizes: map[int]uint64{512: 40, -1: 2}, RecvSizes: map[int]uint64{256: 60}},
+			},
+			ByOperation: map[string]statsmodel.OperationStats{"ME-Identity-Check": {Total: 100, Success: 95, Failed: 5, AvgLatencyMs: 1.5}},
+		},
+		Performance: statsmodel.PerformanceStats{
+			RequestsPerSecond: 12.5, AvgLatencyMs: 1.5, MinLatencyMs: 0.2, MaxLatencyMs: 9.8, P50LatencyMs: 1.1, P95LatencyMs: 4.2, P99LatencyMs: 8.7,
+			BySource:    map[string]statsmodel.LatencyStats{"mme-1": {Count: 60, AvgLatencyMs: 1.4, P99LatencyMs: 7}},
+			ByOperation: map[string]statsmodel.LatencyStats{"ME-Identity-Check": {Count: 100, MaxLatencyMs: 9.8}},
+		},
+		Errors: statsmodel.ErrorStats{
+			Total:       5,
+			ByType:      map[string]uint64{"timeout": 3, "invalid": 2},
+			ByInterface: map[string]uint64{"s13": 5},
+			LastError:   &lastErr,
+			Recent:      []statsmodel.ErrorInfo{lastErr, {Message: "invalid", Count: 2}},
+		},
+		Runtime:  &statsmodel.GoRuntimeStats{Goroutines: 42, HeapInUseBytes: 1 << 20, NumGC: 7, GCPauseP99Ms: 0.3, CPUPercent: 12.5, GOMAXPROCS: 8},
+		Peers:    map[string]statsmodel.PeerStats{"mme-1.epc": {State: "OPEN", ConnectedSince: now.Add(-time.Hour), DWRFailures: 1, MessagesSent: 50, LastDisconnectCause: "REBOOTING"}},
+		SCTP:     &statsmodel.SCTPStats{ActiveAssociations: 2, Retransmits: 11},
+		Overload: &statsmodel.OverloadStats{LoadLevel: 40, Throttled: 3, ByInterface: map[string]statsmodel.InterfaceOverloadStats{"s13": {Throttled: 3}}},
+		Capacity: &statsmodel.CapacityStats{LicensedTPS: 1000, PeakTPS: 250.5},
+		CustomMetrics: map[string]interface{}{
+			"eir": &statsmodel.EIRStats{
+				EquipmentChecks: statsmodel.EquipmentCheckStats{
+					Total: 100, Success: 95, Failed: 5,
+					ByInterface: map[string]statsmodel.InterfaceCheckStats{"diameter": {Total: 100, Success: 95, ByResultCode: map[int]uint64{2001: 95, 5012: 5}}},
+				},
+				DatabaseOps: statsmodel.DatabaseOperationStats{
+					Queries: 80, Errors: 1, AvgLatencyMs: 0.7, ActiveQueries: 1,
+					ByOperation: map[string]statsmodel.LatencyStats{"query": {Count: 80}},
+					ByTable:     map[string]statsmodel.DBBreakdownStats{"equipment": {Operations: 80, MaxLatencyMs: 3}},
+					ByQueryName: map[string]statsmodel.DBBreakdownStats{"lookup_imei": {Operations: 80, Errors: 1}},
+				},
+				CacheStats:        statsmodel.CacheStats{Hits: 70, Misses: 30, HitRate: 70, Size: 500, Bytes: 65536},
+				ByEquipmentStatus: map[string]uint64{"whitelisted": 90, "blacklisted": 10},
+				StatusTransitions: map[string]uint64{"whitelisted->blacklisted": 1},
+				RecentChanges:     []statsmodel.StatusChange{{IMEI: "35209900176148", From: "whitelisted", To: "blacklisted", Timestamp: now}},
+				ByTAC:             map[string]statsmodel.TACStats{"35209900": {Checks: 12, ByStatus: map[string]uint64{"whitelisted": 12}}},
+			},
+		},
+	}
+
+	out, err := UnmarshalServiceStats(MarshalServiceStats(in))
+	if err != nil {
+		t.Fatalf("UnmarshalServiceStats failed: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Round trip mismatch\n in: %+v\nout: %+v", in, out)
+	}
+}
+
+func TestServiceStats_OptionalSectionsStayNil(t *testing.T) {
+	out, err := UnmarshalServiceStats(MarshalServiceStats(&statsmodel.ServiceStats{ServiceName: "gw"}))
+	if err != nil {
+		t.Fatalf("UnmarshalServiceStats failed: %v", err)
+	}
+	if out.ServiceName != "gw" {
+		t.Errorf("Expected service name gw, got %q", out.ServiceName)
+	}
+	if out.Runtime != nil || out.SCTP != nil || out.Overload != nil || out.Capacity != nil || out.Errors.LastError != nil || out.CustomMetrics != nil {
+		t.Errorf("Expected unset optional sections to stay nil, got %+v", out)
+	}
+}
+
+func TestMetricBatch_RoundTrip(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	in := []export.MetricRecord{
+		{CounterID: 1001, Value: 42, Hostname: "eir-1", SystemName: "EIR", Timestamp: now},
+		{CounterID: 1013, Value: 5, CauseCode: 5012, Hostname: "eir-1", SystemName: "EIR", Timestamp: now},
+		{CounterID: 2500, Value: 3, Tenant: "1-000001", Hostname: "eir-1", SystemName: "EIR", Timestamp: now},
+		{CounterID: 1020, Value: 0, CauseCode: -1, Hostname: "eir-1", SystemName: "EIR", Timestamp: now},
+	}
+
+	out, err := UnmarshalMetricBatch(MarshalMetricBatch(in))
+	if err != nil {
+		t.Fatalf("UnmarshalMetricBatch failed: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Round trip mismatch\n in: %+v\nout: %+v", in, out)
+	}
+}
+
+func TestMetricRecord_KnownEncoding(t *testing.T) {
+	// counter_id=150 (field 1 varint), hostname="a" (field 4 bytes)
+	got := MarshalMetricRecord(export.MetricRecord{CounterID: 150, Hostname: "a"})
+	want := []byte{0x08, 0x96, 0x01, 0x22, 0x01, 'a'}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected % x, got % x", want, got)
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	b := MarshalMetricBatch([]export.MetricRecord{{CounterID: 1, Hostname: "host"}})
+	if _, err := UnmarshalMetricBatch(b[:len(b)-2]); err == nil {
+		t.Error("Expected error for truncated batch")
+	}
+	if _, err := UnmarshalServiceStats([]byte{0x0a, 0x05, 'e'}); err == nil {
+		t.Error("Expected error for truncated service stats")
+	}
+}
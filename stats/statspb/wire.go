@@ -0,0 +1,219 @@
+package statspb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("statspb: truncated message")
+
+// encoder appends protobuf fields, omitting zero values as proto3 does
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(num, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(num)<<3|uint64(wireType))
+}
+
+func (e *encoder) uint64(num int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(num, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *encoder) int64(num int, v int64) {
+	e.uint64(num, uint64(v))
+}
+
+func (e *encoder) double(num int, v float64) {
+	if v == 0 {
+		return
+	}
+	e.tag(num, wireFixed64)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+func (e *encoder) string(num int, s string) {
+	if s == "" {
+		return
+	}
+	e.tag(num, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) time(num int, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	e.int64(num, t.UnixNano())
+}
+
+// message writes a nested message; it is always emitted so optional sections keep their presence
+func (e *encoder) message(num int, fn func(*encoder)) {
+	var sub encoder
+	fn(&sub)
+	e.tag(num, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(sub.buf)))
+	e.buf = append(e.buf, sub.buf...)
+}
+
+// stringMap writes a map<string, V> as repeated entries in key order
+func stringMap[V any](e *encoder, num int, m map[string]V, value func(*encoder, V)) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		e.message(num, func(entry *encoder) {
+			entry.string(1, k)
+			value(entry, m[k])
+		})
+	}
+}
+
+// intMap writes a map<int32, uint64> as repeated entries in key order
+func (e *encoder) intMap(num int, m map[int]uint64) {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	for _, k := range keys {
+		e.message(num, func(entry *encoder) {
+			entry.int64(1, int64(k))
+			entry.uint64(2, m[k])
+		})
+	}
+}
+
+// uint64Value writes a map value of type uint64
+func uint64Value(e *encoder, v uint64) {
+	e.uint64(2, v)
+}
+
+// field is a single decoded protobuf field
+type field struct {
+	num      int
+	wireType int
+	v        uint64 // Varint and fixed values
+	b        []byte // Length-delimited values
+}
+
+func (f field) uint64() uint64 {
+	return f.v
+}
+
+func (f field) int64() int64 {
+	return int64(f.v)
+}
+
+func (f field) double() float64 {
+	return math.Float64frombits(f.v)
+}
+
+func (f field) string() string {
+	return string(f.b)
+}
+
+func (f field) time() time.Time {
+	if f.v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(f.v))
+}
+
+// walk decodes each field of a message and passes it to fn
+// Unknown fields are passed through too; callers ignore numbers they don't know
+func walk(buf []byte, fn func(f field) error) error {
+	for len(buf) > 0 {
+		key, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return errTruncated
+		}
+		buf = buf[n:]
+
+		f := field{num: int(key >> 3), wireType: int(key & 7)}
+		switch f.wireType {
+		case wireVarint:
+			v, n := binary.Uvarint(buf)
+			if n <= 0 {
+				return errTruncated
+			}
+			f.v, buf = v, buf[n:]
+		case wireFixed64:
+			if len(buf) < 8 {
+				return errTruncated
+			}
+			f.v, buf = binary.LittleEndian.Uint64(buf), buf[8:]
+		case wireBytes:
+			l, n := binary.Uvarint(buf)
+			if n <= 0 || uint64(len(buf)-n) < l {
+				return errTruncated
+			}
+			f.b, buf = buf[n:n+int(l)], buf[n+int(l):]
+		case wireFixed32:
+			if len(buf) < 4 {
+				return errTruncated
+			}
+			f.v, buf = uint64(binary.LittleEndian.Uint32(buf)), buf[4:]
+		default:
+			return fmt.Errorf("statspb: unsupported wire type %d for field %d", f.wireType, f.num)
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeStringEntry decodes a map<string, V> entry, returning the key and the raw entry fields
+func decodeStringEntry(b []byte, value func(f field) error) (string, error) {
+	var key string
+	err := walk(b, func(f field) error {
+		if f.num == 1 {
+			key = f.string()
+			return nil
+		}
+		if f.num == 2 {
+			return value(f)
+		}
+		return nil
+	})
+	return key, err
+}
+
+// decodeIntEntry decodes a map<int32, uint64> entry
+func decodeIntEntry(b []byte) (int, uint64, error) {
+	var key int
+	var value uint64
+	err := walk(b, func(f field) error {
+		switch f.num {
+		case 1:
+			key = int(int32(f.v))
+		case 2:
+			value = f.v
+		}
+		return nil
+	})
+	return key, value, err
+}
@@ -0,0 +1,133 @@
+package stats
+
+import "time"
+
+// TenantRecorder records requests of one tenant or network slice (an S-NSSAI such as
+// "1-000001", or a customer ID) so a gateway serving several MVNOs can report
+// segregated KPIs. Every call also updates the overall stats exactly like the
+// Collector method of the same name, so it replaces rather than accompanies it
+type TenantRecorder struct {
+	c      *Collector
+	tenant string
+}
+
+// tenantStats accumulates the stats of one tenant
+type tenantStats struct {
+	stats   TenantStats
+	latency *latencyWindow
+}
+
+// Tenant returns a recorder for the given tenant
+// An empty tenant records the overall stats only
+func (c *Collector) Tenant(tenant string) TenantRecorder {
+	return TenantRecorder{c: c, tenant: tenant}
+}
+
+// RecordRequest records a processed request for the given source
+func (r TenantRecorder) RecordRequest(source string, success bool) {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	r.c.recordRequest(source, success)
+	if t := r.c.tenantStats(r.tenant); t != nil {
+		t.stats.Total++
+		if success {
+			t.stats.Success++
+		} else {
+			t.stats.Failed++
+		}
+	}
+}
+
+// BeginRequest marks a request on the given source as in flight
+// In-flight gauges aren't broken down by tenant
+func (r TenantRecorder) BeginRequest(source string) {
+	r.c.BeginRequest(source)
+}
+
+// EndRequest completes a request started with BeginRequest and records its outcome
+func (r TenantRecorder) EndRequest(source string, success bool) {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	r.c.endRequest(source, success)
+	if t := r.c.tenantStats(r.tenant); t != nil {
+		t.stats.Total++
+		if success {
+			t.stats.Success++
+		} else {
+			t.stats.Failed++
+		}
+	}
+}
+
+// RecordBytes records the bytes sent and received for one message exchange on the given source
+func (r TenantRecorder) RecordBytes(source string, sent, recv uint64) {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	r.c.recordBytes(source, sent, recv)
+	if t := r.c.tenantStats(r.tenant); t != nil {
+		t.stats.BytesSent += sent
+		t.stats.BytesRecv += recv
+	}
+}
+
+// RecordResultCode records a Diameter result code or HTTP status code for the given source
+func (r TenantRecorder) RecordResultCode(source string, code int) {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	r.c.recordResultCode(source, code)
+	if t := r.c.tenantStats(r.tenant); t != nil {
+		if t.stats.ByResultCode == nil {
+			t.stats.ByResultCode = make(map[int]uint64)
+		}
+		t.stats.ByResultCode[code]++
+	}
+}
+
+// RecordLatency records the latency of a request for the given source and operation
+func (r TenantRecorder) RecordLatency(source, operation string, d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	r.c.recordLatency(source, operation, ms)
+	if t := r.c.tenantStats(r.tenant); t != nil {
+		t.latency.add(ms)
+	}
+}
+
+// tenantStats returns the stats of tenant, creating them on first use, or nil when
+// tenant is empty or MaxTenants are already tracked (caller holds the lock)
+func (c *Collector) tenantStats(tenant string) *tenantStats {
+	if tenant == "" {
+		return nil
+	}
+	t, ok := c.tenants[tenant]
+	if !ok {
+		if len(c.tenants) >= c.config.MaxTenants {
+			return nil
+		}
+		t = &tenantStats{latency: newLatencyWindow(c.config.LatencySamples)}
+		c.tenants[tenant] = t
+	}
+	return t
+}
+
+// copyTenants deep copies the per-tenant stats (caller holds the lock)
+func (c *Collector) copyTenants() map[string]TenantStats {
+	if len(c.tenants) == 0 {
+		return nil
+	}
+	tenants := make(map[string]TenantStats, len(c.tenants))
+	for tenant, t := range c.tenants {
+		stats := t.stats
+		stats.ByResultCode = copyIntMap(t.stats.ByResultCode)
+		stats.Latency = t.latency.snapshot()
+		tenants[tenant] = stats
+	}
+	return tenants
+}
@@ -0,0 +1,166 @@
+package version
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Release metadata, injected at build time, e.g.
+// -ldflags "-X github.com/hsdfat/telco/version.Version=1.8.0 -X github.com/hsdfat/telco/version.MinUpgradeFrom=1.6.0"
+// Changelog holds a JSON array of ChangelogEntry; as it rarely fits in ldflags,
+// services usually embed it instead:
+//
+//	//go:embed changelog.json
+//	var changelog string
+//
+//	func init() { version.Changelog = changelog }
+var (
+	Version        string // Release version of the binary, e.g. "1.8.0"
+	MinUpgradeFrom string // Oldest release that can be upgraded to this one directly
+	Changelog      string // JSON array of ChangelogEntry, newest first
+)
+
+// ChangelogEntry describes the changes of one release
+type ChangelogEntry struct {
+	Version  string   `json:"version"`
+	Date     string   `json:"date,omitempty"`
+	Changes  []string `json:"changes,omitempty"`
+	Breaking []string `json:"breaking,omitempty"` // Changes needing operator action on upgrade
+}
+
+// ParseChangelog decodes the embedded Changelog, nil if none was embedded
+func ParseChangelog() ([]ChangelogEntry, error) {
+	if strings.TrimSpace(Changelog) == "" {
+		return nil, nil
+	}
+	var entries []ChangelogEntry
+	if err := json.Unmarshal([]byte(Changelog), &entries); err != nil {
+		return nil, fmt.Errorf("invalid changelog: %w", err)
+	}
+	return entries, nil
+}
+
+// UpgradePathError reports an unsupported upgrade to this binary
+type UpgradePathError struct {
+	From   string // Version upgraded from
+	To     string // Version of this binary
+	Reason string
+}
+
+func (e *UpgradePathError) Error() string {
+	return fmt.Sprintf("unsupported upgrade from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+// CheckUpgradePath reports whether the running instances of fromVersion can be
+// replaced by this binary, so orchestration can block unsupported version jumps
+// Downgrades and upgrades from releases older than MinUpgradeFrom return
+// *UpgradePathError; without an injected Version the path can't be checked
+func CheckUpgradePath(fromVersion string) error {
+	if Version == "" {
+		return fmt.Errorf("binary version was not set at build time")
+	}
+	to, err := parseSemver(Version)
+	if err != nil {
+		return fmt.Errorf("invalid binary version: %w", err)
+	}
+	from, err := parseSemver(fromVersion)
+	if err != nil {
+		return fmt.Errorf("invalid version to upgrade from: %w", err)
+	}
+
+	if from.compare(to) > 0 {
+		return &UpgradePathError{From: fromVersion, To: Version, Reason: "downgrades are not supported"}
+	}
+	if MinUpgradeFrom == "" {
+		return nil
+	}
+	oldest, err := parseSemver(MinUpgradeFrom)
+	if err != nil {
+		return fmt.Errorf("invalid minimum upgrade version: %w", err)
+	}
+	if from.compare(oldest) < 0 {
+		return &UpgradePathError{
+			From:   fromVersion,
+			To:     Version,
+			Reason: fmt.Sprintf("upgrade to %s or later first", MinUpgradeFrom),
+		}
+	}
+	return nil
+}
+
+// semver is a parsed semantic version; build metadata is ignored
+type semver struct {
+	core       [3]uint64
+	prerelease []string
+}
+
+// parseSemver parses "1.2.3", "v1.2" or "1.2.3-rc.1+build"; missing minor and
+// patch numbers are 0
+func parseSemver(v string) (semver, error) {
+	var s semver
+	rest := strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		s.prerelease = strings.Split(rest[i+1:], ".")
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if rest == "" || len(parts) > 3 {
+		return s, fmt.Errorf("%q is not a semantic version", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return s, fmt.Errorf("%q is not a semantic version", v)
+		}
+		s.core[i] = n
+	}
+	return s, nil
+}
+
+// compare returns -1, 0 or 1 as s is older than, the same as or newer than o
+// A pre-release is older than its release
+func (s semver) compare(o semver) int {
+	for i := range s.core {
+		if c := cmp.Compare(s.core[i], o.core[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(s.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(s.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(s.prerelease) && i < len(o.prerelease); i++ {
+		if c := comparePrerelease(s.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(s.prerelease), len(o.prerelease))
+}
+
+// comparePrerelease compares pre-release identifiers: numeric ones numerically
+// and before alphanumeric ones, which compare lexically
+func comparePrerelease(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,118 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+// setRelease sets the injected release metadata for one test
+func setRelease(t *testing.T, version, minUpgradeFrom string) {
+	t.Helper()
+	oldVersion, oldMin := Version, MinUpgradeFrom
+	Version, MinUpgradeFrom = version, minUpgradeFrom
+	t.Cleanup(func() { Version, MinUpgradeFrom = oldVersion, oldMin })
+}
+
+// TestCheckUpgradePath tests which version jumps are allowed
+func TestCheckUpgradePath(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		minUpgradeFrom string
+		from           string
+		wantErr        bool
+		wantPathErr    bool // Error is *UpgradePathError
+	}{
+		{name: "patch upgrade", version: "1.8.1", from: "1.8.0"},
+		{name: "same version", version: "1.8.0", from: "1.8.0"},
+		{name: "v prefix and build metadata", version: "v1.8.0+abc123", from: "v1.7"},
+		{name: "release after its pre-release", version: "1.8.0", from: "1.8.0-rc.2"},
+		{name: "pre-release order", version: "1.8.0-rc.10", from: "1.8.0-rc.9"},
+		{name: "downgrade", version: "1.8.0", from: "1.9.0", wantErr: true, wantPathErr: true},
+		{name: "pre-release downgrade", version: "1.8.0-rc.1", from: "1.8.0", wantErr: true, wantPathErr: true},
+		{name: "at minimum", version: "1.8.0", minUpgradeFrom: "1.6.0", from: "1.6.0"},
+		{name: "below minimum", version: "1.8.0", minUpgradeFrom: "1.6.0", from: "1.5.9", wantErr: true, wantPathErr: true},
+		{name: "pre-release of minimum", version: "1.8.0", minUpgradeFrom: "1.6.0", from: "1.6.0-beta", wantErr: true, wantPathErr: true},
+		{name: "version not set", from: "1.0.0", wantErr: true},
+		{name: "malformed binary version", version: "1.x", from: "1.0.0", wantErr: true},
+		{name: "malformed from version", version: "1.8.0", from: "latest", wantErr: true},
+		{name: "empty from version", version: "1.8.0", from: "", wantErr: true},
+		{name: "too many parts", version: "1.8.0", from: "1.2.3.4", wantErr: true},
+		{name: "malformed minimum", version: "1.8.0", minUpgradeFrom: "six", from: "1.7.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRelease(t, tt.version, tt.minUpgradeFrom)
+
+			err := CheckUpgradePath(tt.from)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckUpgradePath(%q) error = %v, wantErr %v", tt.from, err, tt.wantErr)
+			}
+			var pathErr *UpgradePathError
+			if errors.As(err, &pathErr) != tt.wantPathErr {
+				t.Errorf("CheckUpgradePath(%q) error = %v, want *UpgradePathError %v", tt.from, err, tt.wantPathErr)
+			}
+		})
+	}
+}
+
+// TestSemverCompare tests version ordering, including pre-release precedence
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"v1", "1.0.0", 0},
+		{"1.2.3+build.1", "1.2.3+build.2", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta", 1},
+	}
+
+	for _, tt := range tests {
+		a, err := parseSemver(tt.a)
+		if err != nil {
+			t.Fatalf("parseSemver(%q) error = %v", tt.a, err)
+		}
+		b, err := parseSemver(tt.b)
+		if err != nil {
+			t.Fatalf("parseSemver(%q) error = %v", tt.b, err)
+		}
+		if got := a.compare(b); got != tt.want {
+			t.Errorf("compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.compare(a); got != -tt.want {
+			t.Errorf("compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+// TestParseChangelog tests decoding the embedded changelog
+func TestParseChangelog(t *testing.T) {
+	old := Changelog
+	t.Cleanup(func() { Changelog = old })
+
+	Changelog = "  "
+	if entries, err := ParseChangelog(); err != nil || entries != nil {
+		t.Errorf("ParseChangelog() of empty = %v, %v, want nil, nil", entries, err)
+	}
+
+	Changelog = `[{"version":"1.8.0","breaking":["Config key eir.cache renamed"]},{"version":"1.7.0"}]`
+	entries, err := ParseChangelog()
+	if err != nil || len(entries) != 2 || entries[0].Version != "1.8.0" || len(entries[0].Breaking) != 1 {
+		t.Errorf("ParseChangelog() = %+v, %v", entries, err)
+	}
+
+	Changelog = `{"version":"1.8.0"}`
+	if _, err := ParseChangelog(); err == nil {
+		t.Error("Expected an error for a changelog that is not an array")
+	}
+}
@@ -1,10 +1,21 @@
 package version
 
 import (
+	"encoding/json"
+	"net/http"
 	"runtime/debug"
 	"strings"
 )
 
+// Component schema versions, injected at build time so operators can check that
+// NMS mapping files and config templates match the binary, e.g.
+// -ldflags "-X github.com/hsdfat/telco/version.CounterCatalogVersion=7"
+var (
+	CounterCatalogVersion string // Version of the stats export counter catalog
+	ConfigSchemaVersion   string // Version of the service's config schema
+	StatsModelVersion     string // Version of the stats model
+)
+
 type BuildInfo struct {
 	Project      string            `json:"project"`
 	Hash         string            `json:"hash"`
@@ -12,6 +23,24 @@ type BuildInfo struct {
 	BuildHost    string            `json:"host"`
 	GoVersion    string            `json:"go_version"`
 	Dependencies map[string]string `json:"dependencies"`
+	Schemas      SchemaVersions    `json:"schemas"`
+	Upgrade      UpgradeInfo       `json:"upgrade"`
+}
+
+// UpgradeInfo is the release metadata built into the binary, see Version
+type UpgradeInfo struct {
+	Version        string           `json:"version,omitempty"`
+	MinUpgradeFrom string           `json:"min_upgrade_from,omitempty"` // Oldest release CheckUpgradePath accepts
+	Changelog      []ChangelogEntry `json:"changelog,omitempty"`
+	ChangelogError string           `json:"changelog_error,omitempty"` // Why the embedded changelog didn't parse
+}
+
+// SchemaVersions are the component schema versions built into the binary
+// Empty when not injected at build time
+type SchemaVersions struct {
+	CounterCatalog string `json:"counter_catalog,omitempty"`
+	ConfigSchema   string `json:"config_schema,omitempty"`
+	StatsModel     string `json:"stats_model,omitempty"`
 }
 
 type Dependency struct {
@@ -59,9 +88,28 @@ func GetBuildInfo() BuildInfo {
 	if host, ok := LDFlags["main.host"]; ok {
 		buildInfo.BuildHost = host
 	}
+	buildInfo.Schemas = SchemaVersions{
+		CounterCatalog: CounterCatalogVersion,
+		ConfigSchema:   ConfigSchemaVersion,
+		StatsModel:     StatsModelVersion,
+	}
+	buildInfo.Upgrade = UpgradeInfo{Version: Version, MinUpgradeFrom: MinUpgradeFrom}
+	if changelog, err := ParseChangelog(); err != nil {
+		buildInfo.Upgrade.ChangelogError = err.Error()
+	} else {
+		buildInfo.Upgrade.Changelog = changelog
+	}
 	return buildInfo
 }
 
+// Handler serves the build info as JSON, for the service's version endpoint
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GetBuildInfo())
+	})
+}
+
 func parseLDFlags(ldflags string) map[string]string {
 	result := make(map[string]string)
 	var isXFlag bool